@@ -0,0 +1,175 @@
+// Package app owns the terminal event loop. A Dispatcher is the single place
+// that waits on input events, timers, and background jobs, and it routes
+// input to whichever view currently has focus.
+package app
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// View is a screen the dispatcher can draw and send input to
+type View interface {
+	// Draw renders the view; it is called before waiting for the next event
+	Draw()
+	// HandleEvent processes an input event while the view has focus
+	HandleEvent(ev tcell.Event)
+}
+
+// Dispatcher runs the event loop over a stack of views. The view on top of
+// the stack has focus; pushing a dialog focuses it, popping returns focus.
+type Dispatcher struct {
+	events <-chan tcell.Event
+	jobs   chan func()
+
+	mu      sync.Mutex
+	views   []View
+	stopped bool
+	// lastInput is when the last input event finished being handled, or
+	// when the dispatcher was created
+	lastInput time.Time
+}
+
+// NewDispatcher creates a dispatcher reading input from events
+func NewDispatcher(events <-chan tcell.Event) *Dispatcher {
+	return &Dispatcher{
+		events:    events,
+		jobs:      make(chan func(), 16),
+		lastInput: time.Now(),
+	}
+}
+
+// Events returns the input channel for modal helpers that still poll it
+// directly. They run inside HandleEvent, while the dispatcher is waiting.
+func (d *Dispatcher) Events() <-chan tcell.Event {
+	return d.events
+}
+
+// Push adds a view on top of the stack and gives it focus
+func (d *Dispatcher) Push(v View) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.views = append(d.views, v)
+}
+
+// Pop removes the focused view, returning focus to the one below it
+func (d *Dispatcher) Pop() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if len(d.views) > 0 {
+		d.views = d.views[:len(d.views)-1]
+	}
+}
+
+// PopUntil removes views from the top of the stack until keep returns true
+// for the focused one. If no view on the stack is kept, nothing is removed
+// and it returns false.
+func (d *Dispatcher) PopUntil(keep func(View) bool) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for i := len(d.views) - 1; i >= 0; i-- {
+		if keep(d.views[i]) {
+			d.views = d.views[:i+1]
+			return true
+		}
+	}
+	return false
+}
+
+// Idle returns how long it has been since an input event was last handled.
+// A command run from an event handler counts as input until it finishes.
+func (d *Dispatcher) Idle() time.Duration {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return time.Since(d.lastInput)
+}
+
+// Focused returns the view on top of the stack, or nil if there is none
+func (d *Dispatcher) Focused() View {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if len(d.views) == 0 {
+		return nil
+	}
+	return d.views[len(d.views)-1]
+}
+
+// contains reports whether v is anywhere on the stack
+func (d *Dispatcher) contains(v View) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for _, existing := range d.views {
+		if existing == v {
+			return true
+		}
+	}
+	return false
+}
+
+// Post queues fn to run on the loop goroutine. Safe to call from any goroutine,
+// so background jobs can hand their results back to the UI.
+func (d *Dispatcher) Post(fn func()) {
+	d.jobs <- fn
+}
+
+// AfterFunc runs fn on the loop goroutine once d has elapsed
+func (d *Dispatcher) AfterFunc(dur time.Duration, fn func()) *time.Timer {
+	return time.AfterFunc(dur, func() { d.Post(fn) })
+}
+
+// Stop ends Run after the current event has been handled
+func (d *Dispatcher) Stop() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.stopped = true
+}
+
+// Stopped reports whether Stop has been called
+func (d *Dispatcher) Stopped() bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.stopped
+}
+
+// Run processes events until Stop is called, the view stack empties, the
+// event channel closes, or ctx is cancelled (which returns ctx.Err()).
+func (d *Dispatcher) Run(ctx context.Context) error {
+	return d.loop(ctx, func() bool { return d.Focused() != nil })
+}
+
+// RunView pushes v and runs the loop until v is removed from the stack.
+// It lets straight-line code show a dialog and continue once it closes.
+func (d *Dispatcher) RunView(ctx context.Context, v View) error {
+	d.Push(v)
+	return d.loop(ctx, func() bool { return d.contains(v) })
+}
+
+// loop draws the focused view and dispatches one event, job, or cancellation
+// at a time while active returns true
+func (d *Dispatcher) loop(ctx context.Context, active func() bool) error {
+	for !d.Stopped() && active() {
+		view := d.Focused()
+		view.Draw()
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case fn := <-d.jobs:
+			fn()
+		case ev, ok := <-d.events:
+			if !ok {
+				return nil
+			}
+			if ev != nil {
+				view.HandleEvent(ev)
+				d.mu.Lock()
+				d.lastInput = time.Now()
+				d.mu.Unlock()
+			}
+		}
+	}
+	return nil
+}
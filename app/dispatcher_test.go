@@ -0,0 +1,145 @@
+package app
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// recordView counts draws and records the runes it receives
+type recordView struct {
+	draws  int
+	runes  []rune
+	handle func(ev tcell.Event)
+}
+
+func (v *recordView) Draw() { v.draws++ }
+
+func (v *recordView) HandleEvent(ev tcell.Event) {
+	if key, ok := ev.(*tcell.EventKey); ok {
+		v.runes = append(v.runes, key.Rune())
+	}
+	if v.handle != nil {
+		v.handle(ev)
+	}
+}
+
+func keyEvent(r rune) tcell.Event {
+	return tcell.NewEventKey(tcell.KeyRune, r, tcell.ModNone)
+}
+
+func TestDispatcherRoutesToFocusedView(t *testing.T) {
+	events := make(chan tcell.Event, 4)
+	d := NewDispatcher(events)
+
+	base := &recordView{}
+	dialog := &recordView{}
+	dialog.handle = func(tcell.Event) { d.Pop() }
+	base.handle = func(ev tcell.Event) {
+		if ev.(*tcell.EventKey).Rune() == 'q' {
+			d.Stop()
+		}
+	}
+	d.Push(base)
+	d.Push(dialog)
+
+	events <- keyEvent('a') // closes the dialog
+	events <- keyEvent('b')
+	events <- keyEvent('q')
+
+	if err := d.Run(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(dialog.runes) != "a" {
+		t.Errorf("expected dialog to receive only 'a', got %q", string(dialog.runes))
+	}
+	if string(base.runes) != "bq" {
+		t.Errorf("expected base to receive 'bq', got %q", string(base.runes))
+	}
+	if base.draws == 0 || dialog.draws == 0 {
+		t.Errorf("expected both views to be drawn while focused")
+	}
+}
+
+func TestDispatcherRunViewReturnsWhenClosed(t *testing.T) {
+	events := make(chan tcell.Event, 2)
+	d := NewDispatcher(events)
+	base := &recordView{}
+	d.Push(base)
+
+	dialog := &recordView{}
+	dialog.handle = func(tcell.Event) { d.Pop() }
+	events <- keyEvent('x')
+
+	if err := d.RunView(context.Background(), dialog); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if d.Focused() != base {
+		t.Errorf("expected focus to return to the base view")
+	}
+	if len(base.runes) != 0 {
+		t.Errorf("expected base view to receive no input, got %q", string(base.runes))
+	}
+}
+
+func TestDispatcherJobsAndTimers(t *testing.T) {
+	d := NewDispatcher(make(chan tcell.Event))
+	d.Push(&recordView{})
+
+	ran := false
+	go d.Post(func() { ran = true })
+	d.AfterFunc(10*time.Millisecond, d.Stop)
+
+	if err := d.Run(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !ran {
+		t.Errorf("expected posted job to run on the loop")
+	}
+}
+
+func TestDispatcherContextCancel(t *testing.T) {
+	d := NewDispatcher(make(chan tcell.Event))
+	d.Push(&recordView{})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := d.Run(ctx); err != context.Canceled {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
+
+func TestDispatcherPopUntil(t *testing.T) {
+	d := NewDispatcher(make(chan tcell.Event))
+	base, dialog, prompt := &recordView{}, &recordView{}, &recordView{}
+	d.Push(base)
+	d.Push(dialog)
+	d.Push(prompt)
+
+	if d.PopUntil(func(v View) bool { return false }) || d.Focused() != prompt {
+		t.Fatal("expected nothing to be removed when no view is kept")
+	}
+	if !d.PopUntil(func(v View) bool { return v == base }) || d.Focused() != base {
+		t.Errorf("expected the views over base to be removed, got %v", d.Focused())
+	}
+}
+
+func TestDispatcherIdle(t *testing.T) {
+	events := make(chan tcell.Event, 1)
+	d := NewDispatcher(events)
+	d.Push(&recordView{handle: func(tcell.Event) { d.Stop() }})
+
+	time.Sleep(20 * time.Millisecond)
+	if d.Idle() < 20*time.Millisecond {
+		t.Fatalf("expected idle time to grow without input, got %v", d.Idle())
+	}
+	events <- keyEvent('a')
+	if err := d.Run(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if d.Idle() >= 20*time.Millisecond {
+		t.Errorf("expected input to reset the idle time, got %v", d.Idle())
+	}
+}
@@ -0,0 +1,75 @@
+// Package auth checks the password of an OS account, for menus locked with
+// `lock: {auth: os}`. Linux builds made with -tags pam (cgo) and macOS builds
+// made the same way go through PAM's "menuworks" service. Other Linux builds
+// ask PAM's own unix_chkpwd helper, which can only check local accounts, and
+// only the user running MenuWorks unless it runs as root. Windows uses
+// LogonUser.
+package auth
+
+import (
+	"errors"
+	"os/user"
+	"runtime"
+	"strings"
+)
+
+// Service is the PAM service MenuWorks authenticates with; without an
+// /etc/pam.d/menuworks file PAM uses its "other" service
+const Service = "menuworks"
+
+// ErrDenied is returned by Verify for a wrong password or an account that
+// may not sign in
+var ErrDenied = errors.New("wrong user name or password")
+
+// Verify checks that password is the password of the OS account name.
+// It returns ErrDenied if it isn't, and other errors if it couldn't be checked.
+func Verify(name, password string) error {
+	if name == "" || password == "" {
+		return ErrDenied
+	}
+	return verify(name, password)
+}
+
+// CurrentUser returns the name of the account running MenuWorks, or "" if it
+// can't be found. On Windows it includes the domain ("PC\alice").
+func CurrentUser() string {
+	u, err := user.Current()
+	if err != nil {
+		return ""
+	}
+	return u.Username
+}
+
+// Allowed reports whether name is one of users, or the current user when
+// users is empty. On Windows names are compared case-insensitively, and a
+// name without a domain matches the same name in any domain.
+func Allowed(name string, users []string) bool {
+	if len(users) == 0 {
+		users = []string{CurrentUser()}
+	}
+	for _, u := range users {
+		if sameUser(runtime.GOOS, name, u) {
+			return true
+		}
+	}
+	return false
+}
+
+// sameUser compares account names as goos does
+func sameUser(goos, a, b string) bool {
+	if goos != "windows" {
+		return a != "" && a == b
+	}
+	if !strings.Contains(a, `\`) || !strings.Contains(b, `\`) {
+		a, b = withoutDomain(a), withoutDomain(b)
+	}
+	return a != "" && strings.EqualFold(a, b)
+}
+
+// withoutDomain strips "DOMAIN\" from a Windows account name
+func withoutDomain(name string) string {
+	if i := strings.LastIndex(name, `\`); i >= 0 {
+		return name[i+1:]
+	}
+	return name
+}
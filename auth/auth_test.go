@@ -0,0 +1,30 @@
+package auth
+
+import "testing"
+
+func TestSameUser(t *testing.T) {
+	tests := []struct {
+		goos, a, b string
+		want       bool
+	}{
+		{"linux", "alice", "alice", true},
+		{"linux", "alice", "Alice", false},
+		{"linux", "", "", false},
+		{"windows", "Alice", "alice", true},
+		{"windows", `PC\alice`, "alice", true},
+		{"windows", `PC\alice`, `pc\ALICE`, true},
+		{"windows", `PC\alice`, `CORP\alice`, false},
+		{"windows", "alice", "bob", false},
+	}
+	for _, tt := range tests {
+		if got := sameUser(tt.goos, tt.a, tt.b); got != tt.want {
+			t.Errorf("sameUser(%s, %q, %q) = %v, want %v", tt.goos, tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestVerifyRejectsEmptyPassword(t *testing.T) {
+	if err := Verify(CurrentUser(), ""); err != ErrDenied {
+		t.Errorf("expected an empty password to be denied without asking the OS, got %v", err)
+	}
+}
@@ -0,0 +1,43 @@
+//go:build linux && !(cgo && pam)
+
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// chkpwdPaths are where distributions install PAM's unix_chkpwd helper
+var chkpwdPaths = []string{"/usr/sbin/unix_chkpwd", "/sbin/unix_chkpwd"}
+
+// verify asks unix_chkpwd, the setuid helper pam_unix uses to read
+// /etc/shadow, whether password is name's. The helper refuses to check any
+// account but the caller's unless the caller is root.
+func verify(name, password string) error {
+	if os.Geteuid() != 0 && name != CurrentUser() {
+		return fmt.Errorf("only %s's password can be checked; run as root or use a build with PAM (-tags pam)", CurrentUser())
+	}
+	helper := ""
+	for _, p := range chkpwdPaths {
+		if _, err := os.Stat(p); err == nil {
+			helper = p
+			break
+		}
+	}
+	if helper == "" {
+		return errors.New("unix_chkpwd not found; use a build with PAM (-tags pam)")
+	}
+
+	// The helper reads the password up to a NUL byte
+	cmd := exec.Command(helper, name, "nonull")
+	cmd.Stdin = strings.NewReader(password + "\x00")
+	err := cmd.Run()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return ErrDenied
+	}
+	return err
+}
@@ -0,0 +1,10 @@
+//go:build !linux && !windows && !(darwin && cgo && pam)
+
+package auth
+
+import "errors"
+
+// verify has nothing to check passwords with on this build
+func verify(name, password string) error {
+	return errors.New("OS authentication needs a build with PAM (-tags pam)")
+}
@@ -0,0 +1,80 @@
+//go:build (linux || darwin) && cgo && pam
+
+package auth
+
+/*
+#cgo LDFLAGS: -lpam
+#include <security/pam_appl.h>
+#include <stdlib.h>
+#include <string.h>
+
+// answer replies to PAM's prompts: the password for hidden ones, nothing for
+// the rest. data is the password.
+static int answer(int n, const struct pam_message **msg, struct pam_response **resp, void *data) {
+	struct pam_response *r;
+	int i;
+
+	if (n <= 0 || n > PAM_MAX_NUM_MSG)
+		return PAM_CONV_ERR;
+	r = calloc(n, sizeof(struct pam_response));
+	if (r == NULL)
+		return PAM_BUF_ERR;
+	for (i = 0; i < n; i++) {
+		if (msg[i]->msg_style == PAM_PROMPT_ECHO_OFF) {
+			r[i].resp = strdup((const char *)data);
+			if (r[i].resp == NULL) {
+				for (i--; i >= 0; i--)
+					free(r[i].resp);
+				free(r);
+				return PAM_BUF_ERR;
+			}
+		}
+	}
+	*resp = r;
+	return PAM_SUCCESS;
+}
+
+// authenticate runs service's auth and account stacks for user
+static int authenticate(const char *service, const char *user, const char *password) {
+	struct pam_conv conv = { answer, (void *)password };
+	pam_handle_t *pamh = NULL;
+	int rc = pam_start(service, user, &conv, &pamh);
+	if (rc != PAM_SUCCESS)
+		return rc;
+	rc = pam_authenticate(pamh, PAM_SILENT | PAM_DISALLOW_NULL_AUTHTOK);
+	if (rc == PAM_SUCCESS)
+		rc = pam_acct_mgmt(pamh, PAM_SILENT);
+	pam_end(pamh, rc);
+	return rc;
+}
+*/
+import "C"
+
+import (
+	"fmt"
+	"unsafe"
+)
+
+// verify runs the PAM service's auth and account stacks for name, answering
+// its password prompt with password
+func verify(name, password string) error {
+	cService := C.CString(Service)
+	defer C.free(unsafe.Pointer(cService))
+	cName := C.CString(name)
+	defer C.free(unsafe.Pointer(cName))
+	cPassword := C.CString(password)
+	defer func() {
+		C.memset(unsafe.Pointer(cPassword), 0, C.size_t(len(password)))
+		C.free(unsafe.Pointer(cPassword))
+	}()
+
+	switch rc := C.authenticate(cService, cName, cPassword); rc {
+	case C.PAM_SUCCESS:
+		return nil
+	case C.PAM_AUTH_ERR, C.PAM_USER_UNKNOWN, C.PAM_MAXTRIES, C.PAM_PERM_DENIED,
+		C.PAM_ACCT_EXPIRED, C.PAM_NEW_AUTHTOK_REQD, C.PAM_CRED_INSUFFICIENT:
+		return ErrDenied
+	default:
+		return fmt.Errorf("PAM error %d", int(rc))
+	}
+}
@@ -0,0 +1,70 @@
+//go:build windows
+
+package auth
+
+import (
+	"strings"
+	"syscall"
+	"unsafe"
+)
+
+var (
+	advapi32      = syscall.NewLazyDLL("advapi32.dll")
+	procLogonUser = advapi32.NewProc("LogonUserW")
+)
+
+const (
+	logon32LogonNetwork    = 3
+	logon32ProviderDefault = 0
+
+	errorLogonFailure      = syscall.Errno(1326)
+	errorAccountRestricted = syscall.Errno(1327)
+	errorAccountDisabled   = syscall.Errno(1331)
+	errorAccountExpired    = syscall.Errno(1793)
+	errorPasswordExpired   = syscall.Errno(1330)
+)
+
+// verify signs name in with LogonUser and closes the token straight away. A
+// network logon checks the password without loading the user's profile.
+// name may be "user", "DOMAIN\user", or "user@domain"; a plain name is a
+// local account.
+func verify(name, password string) error {
+	domain := "."
+	if i := strings.Index(name, `\`); i >= 0 {
+		domain, name = name[:i], name[i+1:]
+	} else if strings.Contains(name, "@") {
+		domain = ""
+	}
+	pName, err := syscall.UTF16PtrFromString(name)
+	if err != nil {
+		return ErrDenied
+	}
+	pPassword, err := syscall.UTF16PtrFromString(password)
+	if err != nil {
+		return ErrDenied
+	}
+	var pDomain *uint16
+	if domain != "" {
+		if pDomain, err = syscall.UTF16PtrFromString(domain); err != nil {
+			return ErrDenied
+		}
+	}
+
+	var token syscall.Handle
+	ok, _, err := procLogonUser.Call(
+		uintptr(unsafe.Pointer(pName)),
+		uintptr(unsafe.Pointer(pDomain)),
+		uintptr(unsafe.Pointer(pPassword)),
+		logon32LogonNetwork,
+		logon32ProviderDefault,
+		uintptr(unsafe.Pointer(&token)))
+	if ok == 0 {
+		switch err {
+		case errorLogonFailure, errorAccountRestricted, errorAccountDisabled, errorAccountExpired, errorPasswordExpired:
+			return ErrDenied
+		}
+		return err
+	}
+	syscall.CloseHandle(token)
+	return nil
+}
@@ -0,0 +1,124 @@
+package menuworks
+
+import (
+	"crypto/sha256"
+	"os"
+	"time"
+
+	"github.com/benworks/menuworks/i18n"
+	"github.com/benworks/menuworks/logging"
+)
+
+// configWatchInterval is how often the config file is checked for changes
+// while auto_reload is on
+const configWatchInterval = 2 * time.Second
+
+// configStamp identifies a version of the config file on disk
+type configStamp struct {
+	modTime time.Time
+	size    int64
+}
+
+// statConfig returns the stamp of the config file, or false if it can't be read
+func statConfig(path string) (configStamp, bool) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return configStamp{}, false
+	}
+	return configStamp{info.ModTime(), info.Size()}, true
+}
+
+// configVersion identifies the config file a config was loaded from. The
+// hash tells a file that was only touched or rewritten as it was from one
+// whose contents changed.
+type configVersion struct {
+	path  string
+	stamp configStamp
+	sum   [sha256.Size]byte
+}
+
+// readConfigVersion returns the version of the config file at path, or false
+// if it can't be read
+func readConfigVersion(path string) (configVersion, bool) {
+	stamp, ok := statConfig(path)
+	if !ok {
+		return configVersion{}, false
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return configVersion{}, false
+	}
+	return configVersion{path, stamp, sha256.Sum256(data)}, true
+}
+
+// configChanged reports whether the config file differs from the version the
+// current config was loaded from. An unchanged stamp means an unchanged file;
+// otherwise the contents are hashed and compared.
+func (a *App) configChanged() bool {
+	if a.loaded.path == "" || a.loaded.path != a.ConfigPath {
+		return true
+	}
+	stamp, ok := statConfig(a.ConfigPath)
+	if !ok {
+		return true // let loading report why
+	}
+	if stamp == a.loaded.stamp {
+		return false
+	}
+	v, ok := readConfigVersion(a.ConfigPath)
+	if !ok || v.sum != a.loaded.sum {
+		return true
+	}
+	a.loaded.stamp = stamp
+	return false
+}
+
+// startConfigWatcher checks the config file at path every configWatchInterval
+// and reloads it once it changes, the same as pressing R but without the
+// confirmation. A change seen while a dialog or command is open waits until
+// the menu is back in front. Switching profiles (F3) moves the watch to the
+// new file without reloading it again. Turning auto_reload off in the file
+// stops the reloads; turning it on takes a restart, since the watcher only
+// starts when the config enables it.
+func (a *App) startConfigWatcher(path string, last configStamp) {
+	a.configTimer = a.d.AfterFunc(configWatchInterval, func() {
+		if a.d.Stopped() {
+			return
+		}
+		defer func() { a.startConfigWatcher(path, last) }()
+
+		if a.ConfigPath != path {
+			path = a.ConfigPath
+			last, _ = statConfig(path)
+			return
+		}
+		stamp, ok := statConfig(path)
+		if !ok || stamp == last {
+			return
+		}
+		if !a.cfg.IsAutoReloadEnabled() {
+			last = stamp
+			return
+		}
+		if _, inMenu := a.d.Focused().(*menuView); !inMenu {
+			return
+		}
+		last = stamp
+		logging.Info("config changed on disk", "path", path)
+		reloaded, err := a.reloadConfig()
+		if err != nil {
+			a.showError(i18n.T("Reload Error"), i18n.Tf("Failed to reload config: %v", err))
+			return
+		}
+		if reloaded {
+			a.warnHotkeyConflicts()
+		}
+	})
+}
+
+// stopConfigWatcher cancels the periodic config check
+func (a *App) stopConfigWatcher() {
+	if a.configTimer != nil {
+		a.configTimer.Stop()
+	}
+}
@@ -0,0 +1,138 @@
+// Package autostart registers MenuWorks to start when the user logs in, the
+// way each platform expects: a Run registry value or Startup folder shortcut
+// on Windows, a systemd user unit or XDG autostart entry on Linux, and a
+// LaunchAgent on macOS. Entries outside Windows open MenuWorks in a new
+// terminal window, since nothing that starts programs at login gives them one.
+package autostart
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"strings"
+
+	"github.com/benworks/menuworks/export"
+)
+
+const (
+	// unitName is the systemd user unit
+	unitName = "menuworks.service"
+	// desktopName is the XDG autostart entry
+	desktopName = "menuworks.desktop"
+	// agentLabel is the LaunchAgent's label and file name
+	agentLabel = "com.benworks.menuworks"
+	// runValue is the value under the Run key, and the Startup shortcut's name
+	runValue = "MenuWorks"
+)
+
+// ErrNotInstalled is returned by Uninstall when there is no entry to remove
+var ErrNotInstalled = errors.New("no autostart entry")
+
+// Methods returns the ways MenuWorks can be started at login on goos, the
+// default first
+func Methods(goos string) []string {
+	switch goos {
+	case "windows":
+		return []string{"registry", "startup"}
+	case "linux":
+		return []string{"systemd", "xdg"}
+	case "darwin":
+		return []string{"launchagent"}
+	}
+	return nil
+}
+
+// Install registers argv, the menuworks binary and its flags, to start at
+// login with method, replacing an entry written before. It returns where the
+// entry was written.
+func Install(method string, argv []string) (string, error) {
+	if err := checkMethod(method); err != nil {
+		return "", err
+	}
+	return install(method, argv)
+}
+
+// Uninstall removes the entry Install wrote with method and returns where it
+// was. It returns ErrNotInstalled if there is none.
+func Uninstall(method string) (string, error) {
+	if err := checkMethod(method); err != nil {
+		return "", err
+	}
+	return uninstall(method)
+}
+
+// checkMethod returns an error unless method is one of Methods for this OS
+func checkMethod(method string) error {
+	methods := Methods(runtime.GOOS)
+	if len(methods) == 0 {
+		return fmt.Errorf("autostart is not supported on %s", runtime.GOOS)
+	}
+	for _, m := range methods {
+		if m == method {
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown autostart method '%s' on %s (expected %s)", method, runtime.GOOS, strings.Join(methods, ", "))
+}
+
+// systemdUnit returns a user unit that runs argv with the graphical session
+func systemdUnit(argv []string) string {
+	quoted := make([]string, len(argv))
+	for i, arg := range argv {
+		quoted[i] = systemdQuote(arg)
+	}
+	var b strings.Builder
+	b.WriteString("[Unit]\n")
+	b.WriteString("Description=MenuWorks\n")
+	b.WriteString("PartOf=graphical-session.target\n")
+	b.WriteString("After=graphical-session.target\n\n")
+	b.WriteString("[Service]\n")
+	fmt.Fprintf(&b, "ExecStart=%s\n\n", strings.Join(quoted, " "))
+	b.WriteString("[Install]\n")
+	b.WriteString("WantedBy=graphical-session.target\n")
+	return b.String()
+}
+
+// systemdQuote quotes one ExecStart argument. '%' and '$' are doubled so
+// systemd doesn't expand them as specifiers or variables.
+func systemdQuote(arg string) string {
+	arg = strings.NewReplacer("%", "%%", "$", "$$").Replace(arg)
+	if arg != "" && !strings.ContainsAny(arg, " \t\n\"'\\;") {
+		return arg
+	}
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(arg) + `"`
+}
+
+// desktopEntry returns an XDG autostart entry that runs argv in a terminal
+func desktopEntry(argv []string) string {
+	var b strings.Builder
+	b.WriteString("[Desktop Entry]\n")
+	b.WriteString("Type=Application\n")
+	b.WriteString("Name=MenuWorks\n")
+	fmt.Fprintf(&b, "Exec=%s\n", export.DesktopExec(argv))
+	b.WriteString("Terminal=true\n")
+	b.WriteString("X-GNOME-Autostart-enabled=true\n")
+	return b.String()
+}
+
+// launchAgent returns a LaunchAgent property list that runs argv at login
+func launchAgent(argv []string) string {
+	var b strings.Builder
+	b.WriteString(`<?xml version="1.0" encoding="UTF-8"?>` + "\n")
+	b.WriteString(`<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">` + "\n")
+	b.WriteString(`<plist version="1.0">` + "\n<dict>\n")
+	fmt.Fprintf(&b, "\t<key>Label</key>\n\t<string>%s</string>\n", agentLabel)
+	b.WriteString("\t<key>ProgramArguments</key>\n\t<array>\n")
+	for _, arg := range argv {
+		fmt.Fprintf(&b, "\t\t<string>%s</string>\n", xmlEscape(arg))
+	}
+	b.WriteString("\t</array>\n")
+	b.WriteString("\t<key>RunAtLoad</key>\n\t<true/>\n")
+	b.WriteString("</dict>\n</plist>\n")
+	return b.String()
+}
+
+// xmlEscape escapes s for XML character data
+func xmlEscape(s string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(s)
+}
@@ -0,0 +1,57 @@
+package autostart
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSystemdUnit(t *testing.T) {
+	unit := systemdUnit([]string{"xterm", "-e", "/opt/menu works/menuworks", "-menu", "100%"})
+	want := `ExecStart=xterm -e "/opt/menu works/menuworks" -menu 100%%` + "\n"
+	if !strings.Contains(unit, want) {
+		t.Errorf("expected %q in:\n%s", want, unit)
+	}
+	if !strings.Contains(unit, "WantedBy=graphical-session.target\n") {
+		t.Errorf("expected the unit to start with the graphical session:\n%s", unit)
+	}
+}
+
+func TestSystemdQuote(t *testing.T) {
+	tests := []struct{ arg, want string }{
+		{"plain", "plain"},
+		{"", `""`},
+		{"$HOME", "$$HOME"},
+		{`say "hi"`, `"say \"hi\""`},
+		{`C:\menu`, `"C:\\menu"`},
+	}
+	for _, tt := range tests {
+		if got := systemdQuote(tt.arg); got != tt.want {
+			t.Errorf("systemdQuote(%q) = %q, want %q", tt.arg, got, tt.want)
+		}
+	}
+}
+
+func TestDesktopEntry(t *testing.T) {
+	entry := desktopEntry([]string{"/usr/bin/menuworks", "-config", "/home/me/My Menus/config.yaml"})
+	for _, want := range []string{
+		`Exec=/usr/bin/menuworks -config "/home/me/My Menus/config.yaml"` + "\n",
+		"Terminal=true\n",
+	} {
+		if !strings.Contains(entry, want) {
+			t.Errorf("expected %q in:\n%s", want, entry)
+		}
+	}
+}
+
+func TestLaunchAgent(t *testing.T) {
+	plist := launchAgent([]string{"osascript", "-e", `tell application "Terminal" to do script "exec menuworks -menu a&b"`})
+	for _, want := range []string{
+		"<string>com.benworks.menuworks</string>",
+		`<string>tell application "Terminal" to do script "exec menuworks -menu a&amp;b"</string>`,
+		"<key>RunAtLoad</key>\n\t<true/>",
+	} {
+		if !strings.Contains(plist, want) {
+			t.Errorf("expected %q in:\n%s", want, plist)
+		}
+	}
+}
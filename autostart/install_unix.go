@@ -0,0 +1,107 @@
+//go:build !windows
+
+package autostart
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	osexec "os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/benworks/menuworks/exec"
+)
+
+// entryPath returns the file method writes
+func entryPath(method string) (string, error) {
+	if method == "launchagent" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		return filepath.Join(home, "Library", "LaunchAgents", agentLabel+".plist"), nil
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	if method == "systemd" {
+		return filepath.Join(dir, "systemd", "user", unitName), nil
+	}
+	return filepath.Join(dir, "autostart", desktopName), nil
+}
+
+func install(method string, argv []string) (string, error) {
+	path, err := entryPath(method)
+	if err != nil {
+		return "", err
+	}
+	var content string
+	switch method {
+	case "systemd", "launchagent":
+		args, err := exec.TerminalArgs(runtime.GOOS, argv)
+		if err != nil {
+			return "", err
+		}
+		if method == "systemd" {
+			content = systemdUnit(args)
+		} else {
+			content = launchAgent(args)
+		}
+	case "xdg":
+		// The desktop opens its own terminal for Terminal=true
+		content = desktopEntry(argv)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		return "", err
+	}
+	if method == "systemd" {
+		if err := systemctl("daemon-reload"); err != nil {
+			return path, err
+		}
+		if err := systemctl("enable", unitName); err != nil {
+			return path, err
+		}
+	}
+	return path, nil
+}
+
+func uninstall(method string) (string, error) {
+	path, err := entryPath(method)
+	if err != nil {
+		return "", err
+	}
+	if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+		return path, ErrNotInstalled
+	}
+	if method != "systemd" {
+		return path, os.Remove(path)
+	}
+	// Without the unit, systemd can't start it even if disabling it failed
+	err = systemctl("disable", unitName)
+	if rmErr := os.Remove(path); rmErr != nil {
+		return path, rmErr
+	}
+	if err != nil {
+		return path, err
+	}
+	return path, systemctl("daemon-reload")
+}
+
+// systemctl runs systemctl --user with args
+func systemctl(args ...string) error {
+	out, err := osexec.Command("systemctl", append([]string{"--user"}, args...)...).CombinedOutput()
+	if err != nil {
+		if errors.Is(err, osexec.ErrNotFound) {
+			return fmt.Errorf("systemctl not found; use the xdg method instead")
+		}
+		return fmt.Errorf("systemctl --user %s: %w: %s", strings.Join(args, " "), err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+//go:build windows
+
+package autostart
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"syscall"
+	"unsafe"
+
+	"github.com/benworks/menuworks/export"
+	"github.com/benworks/menuworks/shell"
+)
+
+// runKey is the per-user key whose values Windows runs at sign-in
+const runKey = `Software\Microsoft\Windows\CurrentVersion\Run`
+
+var (
+	advapi32           = syscall.NewLazyDLL("advapi32.dll")
+	procRegSetValueEx  = advapi32.NewProc("RegSetValueExW")
+	procRegDeleteValue = advapi32.NewProc("RegDeleteValueW")
+)
+
+// startupShortcut returns the shortcut in the user's Startup folder
+func startupShortcut() (string, error) {
+	appData := os.Getenv("APPDATA")
+	if appData == "" {
+		return "", errors.New("APPDATA is not set")
+	}
+	return filepath.Join(appData, "Microsoft", "Windows", "Start Menu", "Programs", "Startup", runValue+".lnk"), nil
+}
+
+func install(method string, argv []string) (string, error) {
+	if method == "startup" {
+		file, err := startupShortcut()
+		if err != nil {
+			return "", err
+		}
+		return file, export.WriteWindowsShortcut(file, argv[0], argv[1:], "MenuWorks")
+	}
+
+	where := `HKCU\` + runKey + `\` + runValue
+	key, err := openRunKey()
+	if err != nil {
+		return where, err
+	}
+	defer syscall.RegCloseKey(key)
+	name, err := syscall.UTF16FromString(runValue)
+	if err != nil {
+		return where, err
+	}
+	data, err := syscall.UTF16FromString(shell.Quote("windows", argv))
+	if err != nil {
+		return where, err
+	}
+	r, _, _ := procRegSetValueEx.Call(uintptr(key), uintptr(unsafe.Pointer(&name[0])), 0,
+		syscall.REG_SZ, uintptr(unsafe.Pointer(&data[0])), uintptr(len(data)*2))
+	if r != 0 {
+		return where, syscall.Errno(r)
+	}
+	return where, nil
+}
+
+func uninstall(method string) (string, error) {
+	if method == "startup" {
+		file, err := startupShortcut()
+		if err != nil {
+			return "", err
+		}
+		if err := os.Remove(file); errors.Is(err, os.ErrNotExist) {
+			return file, ErrNotInstalled
+		} else if err != nil {
+			return file, err
+		}
+		return file, nil
+	}
+
+	where := `HKCU\` + runKey + `\` + runValue
+	key, err := openRunKey()
+	if err != nil {
+		return where, err
+	}
+	defer syscall.RegCloseKey(key)
+	name, err := syscall.UTF16PtrFromString(runValue)
+	if err != nil {
+		return where, err
+	}
+	r, _, _ := procRegDeleteValue.Call(uintptr(key), uintptr(unsafe.Pointer(name)))
+	if syscall.Errno(r) == syscall.ERROR_FILE_NOT_FOUND {
+		return where, ErrNotInstalled
+	}
+	if r != 0 {
+		return where, syscall.Errno(r)
+	}
+	return where, nil
+}
+
+// openRunKey opens runKey under HKEY_CURRENT_USER for writing
+func openRunKey() (syscall.Handle, error) {
+	path, err := syscall.UTF16PtrFromString(runKey)
+	if err != nil {
+		return 0, err
+	}
+	var key syscall.Handle
+	err = syscall.RegOpenKeyEx(syscall.HKEY_CURRENT_USER, path, 0, syscall.KEY_SET_VALUE, &key)
+	return key, err
+}
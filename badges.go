@@ -0,0 +1,71 @@
+package menuworks
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/benworks/menuworks/config"
+	"github.com/benworks/menuworks/exec"
+	"github.com/benworks/menuworks/logging"
+)
+
+// badgeTimeout is how long a badge_exec command may take; a slower one is
+// stopped and its item keeps the badge from its config
+const badgeTimeout = 2 * time.Second
+
+// refreshBadges runs the badge_exec commands of the current menu's items
+// when a menu is entered or the config reloaded, and hands their output to
+// the screen as each one finishes
+func (v *menuView) refreshBadges() {
+	a := v.a
+	name := a.navigator.GetCurrentMenuName()
+	if name == v.badgeMenu && a.cfg == v.badgeCfg {
+		return
+	}
+	v.badgeMenu, v.badgeCfg = name, a.cfg
+	if v.badgeCancel != nil {
+		v.badgeCancel() // the menu they were for is gone
+	}
+	badges := make(map[string]string)
+	a.screen.SetItemBadges(badges)
+
+	ctx, cancel := context.WithCancel(a.ctx)
+	v.badgeCancel = cancel
+	for _, item := range a.navigator.GetCurrentMenu() {
+		if !item.HasBadgeCommand() || item.BadgeExec.CommandForOS(exec.GetOS()) == "" {
+			continue
+		}
+		go func(item config.MenuItem) {
+			text, ok := badgeOutput(ctx, item)
+			if !ok {
+				return
+			}
+			a.d.Post(func() {
+				if ctx.Err() == nil {
+					badges[item.Key()] = text
+				}
+			})
+		}(item)
+	}
+}
+
+// badgeOutput runs item's badge_exec command and returns the first line it
+// prints. It reports false if the command fails, prints nothing, or takes
+// longer than badgeTimeout.
+func badgeOutput(ctx context.Context, item config.MenuItem) (string, bool) {
+	ctx, cancel := context.WithTimeout(ctx, badgeTimeout)
+	defer cancel()
+	osType := exec.GetOS()
+	command := item.BadgeExec.CommandForOS(osType)
+	output, exitCode := exec.ExecuteAndCaptureContext(ctx, command, item.BadgeExec.ArgvForOS(osType), item.BadgeExec.WorkDir, nil)
+	if exitCode != 0 {
+		if ctx.Err() != context.Canceled {
+			logging.Warn("badge command failed", "label", item.Label, "command", command, "exit", exitCode)
+		}
+		return "", false
+	}
+	line, _, _ := strings.Cut(strings.TrimSpace(output), "\n")
+	line = strings.TrimSpace(line)
+	return line, line != ""
+}
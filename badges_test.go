@@ -0,0 +1,77 @@
+package menuworks
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+
+	"github.com/benworks/menuworks/app"
+	"github.com/benworks/menuworks/config"
+)
+
+// untilView keeps the dispatcher running until done reports true
+type untilView struct {
+	d    *app.Dispatcher
+	done func() bool
+}
+
+func (u *untilView) Draw() {
+	if u.done() {
+		u.d.Post(u.d.Pop) // the loop waits for the next job after drawing
+	}
+}
+
+func (u *untilView) HandleEvent(tcell.Event) {}
+
+func TestBadgeOutput(t *testing.T) {
+	both := func(command string) config.ExecConfig {
+		return config.ExecConfig{Windows: command, Linux: command, Mac: command}
+	}
+	for _, tc := range []struct {
+		command string
+		want    string
+		ok      bool
+	}{
+		{"echo main", "main", true},
+		{"echo 12G && echo free", "12G", true},
+		{"exit 3", "", false},
+		{"echo", "", false},
+	} {
+		got, ok := badgeOutput(context.Background(), config.MenuItem{Label: "Repo", BadgeExec: both(tc.command)})
+		if got != tc.want || ok != tc.ok {
+			t.Errorf("%q: got %q, %v; want %q, %v", tc.command, got, ok, tc.want, tc.ok)
+		}
+	}
+}
+
+func TestBadgesRefreshPerMenu(t *testing.T) {
+	branch := config.ExecConfig{Windows: "echo main", Linux: "echo main", Mac: "echo main"}
+	cfg := &config.Config{Title: "Root", Items: []config.MenuItem{
+		{Type: "command", Label: "Open repo", BadgeExec: branch, Badge: "?", Exec: branch},
+		{Type: "command", Label: "Cleanup", Badge: "static", Exec: branch},
+	}}
+	a, sim, _ := newTestApp(t, cfg)
+	v := &menuView{a: a}
+
+	v.refreshBadges()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	shown := func() bool {
+		a.screen.DrawMenu(a.navigator)
+		text := screenText(sim)
+		return strings.Contains(text, "main") && strings.Contains(text, "static") && !strings.Contains(text, " ? ")
+	}
+	// Each result posted to the loop wakes it; stop once the badge is drawn
+	if err := a.d.RunView(ctx, &untilView{d: a.d, done: shown}); err != nil {
+		t.Fatalf("expected the command's output as the badge: %v\n%s", err, screenText(sim))
+	}
+
+	// Drawing the same menu again keeps the output rather than rerunning
+	v.refreshBadges()
+	if !shown() {
+		t.Errorf("expected the badge kept for the same menu, got:\n%s", screenText(sim))
+	}
+}
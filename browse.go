@@ -0,0 +1,31 @@
+package menuworks
+
+import (
+	"errors"
+	"os"
+
+	"github.com/benworks/menuworks/config"
+	"github.com/benworks/menuworks/i18n"
+	"github.com/benworks/menuworks/logging"
+	"github.com/benworks/menuworks/ui"
+)
+
+// browseFiles opens the file picker for a browse item and runs the item's
+// command on the picked file, as if it were a command item
+func (a *App) browseFiles(item config.MenuItem) {
+	root := item.BrowseRoot()
+	info, err := os.Stat(root)
+	if err == nil && !info.IsDir() {
+		err = errors.New("not a folder")
+	}
+	if err != nil {
+		logging.Error("browse path unavailable", "label", item.Label, "path", root, "err", err)
+		a.showError(i18n.T("Launch Error"), i18n.Tf("Can't browse %s: %v", root, err))
+		return
+	}
+	a.d.Push(ui.NewFileView(a.screen, item.Label, root, item.ListsFile, func(file string) {
+		a.d.Pop()
+		logging.Info("file picked", "label", item.Label, "file", file)
+		a.runCommand(item.WithFile(file))
+	}, a.d.Pop))
+}
@@ -26,6 +26,10 @@ Write-Host "Go: $localGo" -ForegroundColor Gray
 Write-Host "Version: $Version" -ForegroundColor Gray
 Write-Host ""
 
+# Build metadata shown by `menuworks version`
+$commit = (git rev-parse HEAD 2>$null)
+$buildDate = (Get-Date).ToUniversalTime().ToString("yyyy-MM-ddTHH:mm:ssZ")
+
 # Ensure dist directory exists
 if (-not (Test-Path "dist")) {
     New-Item -ItemType Directory -Path "dist" | Out-Null
@@ -67,7 +71,7 @@ $targets.GetEnumerator() | ForEach-Object {
     $env:GOOS = $os
     $env:GOARCH = $arch
     
-    $ldFlags = "-s -w -X main.version=$Version"
+    $ldFlags = "-s -w -X main.version=$Version -X main.commit=$commit -X main.buildDate=$buildDate"
     $outputPath = "dist/$outputFile"
     
     & $localGo build -trimpath -ldflags $ldFlags -o $outputPath ./cmd/menuworks
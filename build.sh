@@ -25,6 +25,10 @@ export PATH="$(pwd)/bin/go/bin:$PATH"
 echo "MenuWorks 3.X Build System"
 echo "Go: $LOCAL_GO"
 echo "Version: $VERSION"
+
+# Build metadata shown by `menuworks version`
+COMMIT=$(git rev-parse HEAD 2>/dev/null || echo "")
+BUILD_DATE=$(date -u +%Y-%m-%dT%H:%M:%SZ)
 echo ""
 
 # Ensure dist directory exists
@@ -70,7 +74,7 @@ for output in "${!TARGETS[@]}"; do
     export GOOS="$os"
     export GOARCH="$arch"
     
-    LD_FLAGS="-s -w -X main.version=$VERSION"
+    LD_FLAGS="-s -w -X main.version=$VERSION -X main.commit=$COMMIT -X main.buildDate=$BUILD_DATE"
     OUTPUT_PATH="dist/$output"
     
     "$LOCAL_GO" build -trimpath -ldflags "$LD_FLAGS" -o "$OUTPUT_PATH" ./cmd/menuworks
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"runtime"
+	"strings"
+
+	"github.com/benworks/menuworks/autostart"
+)
+
+// runInstallAutostart handles the "menuworks install-autostart" subcommand.
+// It registers this binary, with the config and flags given, to start when
+// the current user logs in.
+func runInstallAutostart(args []string) {
+	methods := autostart.Methods(runtime.GOOS)
+	defaultMethod := ""
+	if len(methods) > 0 {
+		defaultMethod = methods[0]
+	}
+
+	fs := flag.NewFlagSet("install-autostart", flag.ExitOnError)
+	configFlag := fs.String("config", "", "Path to config.yaml file the menu starts with (default: resolved at login as usual)")
+	profileFlag := fs.String("profile", "", "Profile the menu starts with (name of a .yaml file in the config directory)")
+	portableFlag := fs.Bool("portable", false, "Use config.yaml next to the binary instead of the user config directory")
+	menuFlag := fs.String("menu", "", "Initial menu to display, or a path such as games/steam")
+	noSplashFlag := fs.Bool("no-splash", false, "Skip the splash screen on startup")
+	roleFlag := fs.String("role", "", "Role whose items to show")
+	methodFlag := fs.String("method", defaultMethod, "How to start at login: "+strings.Join(methods, " or "))
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: menuworks install-autostart [flags]\n\n")
+		fmt.Fprintf(os.Stderr, "Start the menu when you log in: a Run registry value or Startup folder\n")
+		fmt.Fprintf(os.Stderr, "shortcut on Windows, a systemd user unit or XDG autostart entry on Linux,\n")
+		fmt.Fprintf(os.Stderr, "or a LaunchAgent on macOS. Running it again replaces the entry.\n")
+		fmt.Fprintf(os.Stderr, "Remove it with 'menuworks uninstall-autostart'.\n\n")
+		fmt.Fprintf(os.Stderr, "Flags:\n")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if fs.NArg() > 0 {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	exe, err := os.Executable()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: failed to determine executable path: %v\n", err)
+		os.Exit(1)
+	}
+	argv := []string{exe}
+	if *configFlag != "" || *profileFlag != "" {
+		// Pin the config the menu starts with, checked now rather than at login
+		configPath, _, err := resolveConfigPath(*configFlag, *profileFlag, *portableFlag)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if _, err := os.Stat(configPath); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: config file not found: %s\n", configPath)
+			os.Exit(1)
+		}
+		argv = append(argv, "-config", configPath)
+	} else if *portableFlag {
+		argv = append(argv, "-portable")
+	}
+	if *menuFlag != "" {
+		argv = append(argv, "-menu", *menuFlag)
+	}
+	if *noSplashFlag {
+		argv = append(argv, "-no-splash")
+	}
+	if *roleFlag != "" {
+		argv = append(argv, "-role", *roleFlag)
+	}
+
+	where, err := autostart.Install(*methodFlag, argv)
+	if err != nil {
+		if where != "" {
+			fmt.Fprintf(os.Stderr, "Error: %s: %v\n", where, err)
+		} else {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		}
+		os.Exit(1)
+	}
+	fmt.Printf("Wrote %s\n", where)
+}
+
+// runUninstallAutostart handles the "menuworks uninstall-autostart" subcommand.
+// It removes the entries install-autostart wrote.
+func runUninstallAutostart(args []string) {
+	methods := autostart.Methods(runtime.GOOS)
+
+	fs := flag.NewFlagSet("uninstall-autostart", flag.ExitOnError)
+	methodFlag := fs.String("method", "", "Only remove the entry for this method: "+strings.Join(methods, " or ")+" (default: all)")
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: menuworks uninstall-autostart [flags]\n\n")
+		fmt.Fprintf(os.Stderr, "Stop starting the menu at login by removing the entries that\n")
+		fmt.Fprintf(os.Stderr, "'menuworks install-autostart' wrote.\n\n")
+		fmt.Fprintf(os.Stderr, "Flags:\n")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if fs.NArg() > 0 {
+		fs.Usage()
+		os.Exit(2)
+	}
+	if *methodFlag != "" {
+		methods = []string{*methodFlag}
+	}
+
+	removed, failed := 0, false
+	for _, method := range methods {
+		where, err := autostart.Uninstall(method)
+		switch {
+		case errors.Is(err, autostart.ErrNotInstalled):
+		case err != nil:
+			fmt.Fprintf(os.Stderr, "Error: %s: %v\n", where, err)
+			failed = true
+		default:
+			fmt.Printf("Removed %s\n", where)
+			removed++
+		}
+	}
+	if failed {
+		os.Exit(1)
+	}
+	if removed == 0 {
+		fmt.Println("No autostart entry to remove")
+	}
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+
+	"github.com/benworks/menuworks/config"
+	"github.com/benworks/menuworks/export"
+	"github.com/benworks/menuworks/menu"
+)
+
+// runExport handles the "menuworks export" subcommand.
+// It writes a document of every menu, item, command, and help text.
+func runExport(args []string) {
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	configFlag := fs.String("config", "", "Path to config.yaml file (default: user config directory, then binary directory)")
+	profileFlag := fs.String("profile", "", "Profile to load (name of a .yaml file in the config directory)")
+	portableFlag := fs.Bool("portable", false, "Use config.yaml next to the binary instead of the user config directory")
+	formatFlag := fs.String("format", "md", "Output format: md, html, launcher, or shortcuts")
+	outputFlag := fs.String("output", "", "File to write, or directory for shortcuts (default: stdout / the OS launcher directory)")
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: menuworks export [flags]\n")
+		fmt.Fprintf(os.Stderr, "       menuworks export -format shortcuts [flags] [item path or id ...]\n\n")
+		fmt.Fprintf(os.Stderr, "Export the menu as Markdown or HTML documentation. Commands are shown\n")
+		fmt.Fprintf(os.Stderr, "as they run on this platform.\n\n")
+		fmt.Fprintf(os.Stderr, "With -format launcher, write a themed HTML page of clickable items. Commands\n")
+		fmt.Fprintf(os.Stderr, "that open a URL or file (steam://, start, xdg-open, ...) become links.\n\n")
+		fmt.Fprintf(os.Stderr, "With -format shortcuts, write an OS shortcut for each listed command item\n")
+		fmt.Fprintf(os.Stderr, "(all commands if none are listed): .desktop files on Linux, .lnk shortcuts\n")
+		fmt.Fprintf(os.Stderr, "on Windows, and .command files on macOS. Each runs `menuworks run`.\n\n")
+		fmt.Fprintf(os.Stderr, "Flags:\n")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	var write func(io.Writer, string, []menu.Node) error
+	switch *formatFlag {
+	case "md", "markdown":
+		write = export.Markdown
+	case "html":
+		write = export.HTML
+	case "launcher", "shortcuts":
+	default:
+		fmt.Fprintf(os.Stderr, "Error: unknown format '%s' (expected md, html, launcher, or shortcuts)\n", *formatFlag)
+		os.Exit(2)
+	}
+
+	cfg, configPath, err := loadExistingConfig(*configFlag, *profileFlag, *portableFlag)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if *formatFlag == "shortcuts" {
+		exportShortcuts(menu.Tree(cfg), configPath, *outputFlag, fs.Args())
+		return
+	}
+	if *formatFlag == "launcher" {
+		palette := export.PaletteFor(config.GetThemeColors(cfg))
+		write = func(w io.Writer, title string, tree []menu.Node) error {
+			return export.LauncherPage(w, title, tree, palette)
+		}
+	}
+
+	out := os.Stdout
+	if *outputFlag != "" {
+		f, err := os.Create(*outputFlag)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: failed to create output file: %v\n", err)
+			os.Exit(1)
+		}
+		defer f.Close()
+		out = f
+	}
+
+	if err := write(out, cfg.Title, menu.Tree(cfg)); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: failed to write export: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// exportShortcuts writes OS shortcuts for the selected command items into dir
+func exportShortcuts(tree []menu.Node, configPath, dir string, selectors []string) {
+	nodes, err := export.Commands(tree, selectors)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	exe, err := os.Executable()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: failed to determine executable path: %v\n", err)
+		os.Exit(1)
+	}
+	if dir == "" {
+		if dir, err = export.DefaultShortcutDir(runtime.GOOS); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: failed to determine shortcut directory: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	files, err := export.WriteShortcuts(dir, runtime.GOOS, export.Launcher{Exe: exe, ConfigPath: configPath}, nodes)
+	for _, file := range files {
+		fmt.Printf("Wrote %s\n", file)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
@@ -1,6 +1,10 @@
 package main
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
 	"os"
@@ -8,6 +12,7 @@ import (
 
 	"github.com/benworks/menuworks/discover"
 	discoverlinux "github.com/benworks/menuworks/discover/linux"
+	discovermame "github.com/benworks/menuworks/discover/mame"
 	discoverwin "github.com/benworks/menuworks/discover/windows"
 )
 
@@ -17,9 +22,20 @@ func runGenerate(args []string) {
 	fs := flag.NewFlagSet("generate", flag.ExitOnError)
 	output := fs.String("output", "config.yaml", "Output file path")
 	sources := fs.String("sources", "", "Comma-separated list of sources (default: all available)")
+	categories := fs.String("categories", "", "Comma-separated source=category pairs that put a source's apps in another category, e.g. programfiles=Tools")
 	listSources := fs.Bool("list-sources", false, "List available sources and exit")
+	doctor := fs.Bool("doctor", false, "Show what each source checked to decide whether it is available, and exit")
 	dryRun := fs.Bool("dry-run", false, "Print config to stdout instead of writing a file")
 	base := fs.String("base", "", "Base config file to merge discovered apps into (base takes priority)")
+	prefer := fs.String("prefer", "", "Which side wins merge conflicts with --base: base or generated, for every section or per section (e.g. generated,menus=base)")
+	orderFlags := addOrderFlags(fs)
+	showOutput := fs.Bool("show-output", false, "Leave generated items to show the output dialog when they finish (default: showOutput: false for all but terminal programs)")
+	hotkeys := fs.Bool("hotkeys", false, "Write a hotkey, unique within its menu, into each generated item instead of leaving them to runtime auto-assignment")
+	jsonOut := fs.Bool("json", false, "Print the discovered applications and their metadata as JSON instead of a config")
+	details := fs.Bool("details", false, "Add each application's version, publisher, install date, and size to its help text")
+	icons := fs.Bool("icons", false, "Give each category's submenu item an icon suggested by its source or category name (e.g. 🎮 for Games)")
+	minConfidence := fs.Int("min-confidence", 0, "Skip applications whose discovery confidence (1-100) is below this")
+	timeout := fs.Duration("timeout", discover.DefaultSourceTimeout, "How long to wait for each source before skipping it")
 	fs.Usage = func() {
 		fmt.Fprintf(os.Stderr, "Usage: menuworks generate [flags]\n\n")
 		fmt.Fprintf(os.Stderr, "Discover installed applications and generate a config.yaml file.\n\n")
@@ -28,10 +44,21 @@ func runGenerate(args []string) {
 	}
 	fs.Parse(args)
 
+	mergeOpts, err := discover.ParseMergeOptions(*prefer)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: --prefer: %v\n", err)
+		os.Exit(2)
+	}
+
 	// Build registry with platform sources
-	registry := discover.NewRegistry()
-	discoverwin.RegisterAll(registry)
-	discoverlinux.RegisterAll(registry)
+	registry := newRegistry()
+
+	// Category overrides from the flag win over the base config's, which
+	// Configure only applies to sources the flag left alone
+	if err := applyCategories(registry, *categories); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: --categories: %v\n", err)
+		os.Exit(2)
+	}
 
 	// List sources mode
 	if *listSources {
@@ -46,13 +73,28 @@ func runGenerate(args []string) {
 			if !s.Available() {
 				avail = "not found"
 			}
-			fmt.Printf("  %-20s [%s] (%s)\n", s.Name(), s.Category(), avail)
+			fmt.Printf("  %-20s [%s] (%s)\n", s.Name(), registry.CategoryOf(s), avail)
 		}
 		return
 	}
 
+	// Doctor mode: apply source options and register custom directories too,
+	// so their checks show up
+	if *doctor {
+		if *base != "" {
+			if data, err := os.ReadFile(*base); err == nil {
+				if discoverCfg, err := discover.ParseDiscoverConfig(data); err == nil {
+					registry.Configure(discoverCfg)
+					discoverwin.RegisterCustomDirs(registry, discoverCfg.Dirs)
+				}
+			}
+		}
+		printDiagnoses(registry.Diagnose())
+		return
+	}
+
 	// Check output file does not already exist (unless dry-run)
-	if !*dryRun {
+	if !*dryRun && !*jsonOut {
 		if _, err := os.Stat(*output); err == nil {
 			fmt.Fprintf(os.Stderr, "Error: output file already exists: %s\nWill not overwrite existing files. Choose a different --output path or remove the existing file.\n", *output)
 			os.Exit(1)
@@ -73,17 +115,28 @@ func runGenerate(args []string) {
 		}
 	}
 
-	// Register any custom directory sources declared in the base config.
+	// Apply source options and register any custom directory sources
+	// declared in the base config.
+	var order discover.Ordering
 	if baseYAML != nil {
 		discoverCfg, err := discover.ParseDiscoverConfig(baseYAML)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Warning: could not parse discover block in base config: %v\n", err)
-		} else if len(discoverCfg.Dirs) > 0 {
-			discoverwin.RegisterCustomDirs(registry, discoverCfg.Dirs)
-			fmt.Fprintf(os.Stderr, "Custom directories: %d configured\n", len(discoverCfg.Dirs))
+		} else {
+			registry.Configure(discoverCfg)
+			order = discoverCfg.Order
+			if len(discoverCfg.Dirs) > 0 {
+				discoverwin.RegisterCustomDirs(registry, discoverCfg.Dirs)
+				fmt.Fprintf(os.Stderr, "Custom directories: %d configured\n", len(discoverCfg.Dirs))
+			}
 		}
 	}
 
+	if order, err = orderFlags.apply(order); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(2)
+	}
+
 	// Parse source filter
 	var sourceNames []string
 	if *sources != "" {
@@ -97,7 +150,8 @@ func runGenerate(args []string) {
 
 	// Run discovery
 	fmt.Fprintf(os.Stderr, "Discovering applications...\n")
-	results, err := registry.DiscoverAll(sourceNames)
+	registry.SetTimeout(*timeout)
+	results, err := registry.DiscoverAll(context.Background(), sourceNames)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
@@ -106,7 +160,9 @@ func runGenerate(args []string) {
 	// Report per-source results
 	totalApps := 0
 	for _, r := range results {
-		if r.Err != nil {
+		if errors.Is(r.Err, discover.ErrSourceTimeout) {
+			fmt.Fprintf(os.Stderr, "  Warning: %s: %v, skipped (use --timeout to wait longer)\n", r.Source, r.Err)
+		} else if r.Err != nil {
 			fmt.Fprintf(os.Stderr, "  Warning: %s: %v\n", r.Source, r.Err)
 		} else {
 			fmt.Fprintf(os.Stderr, "  %s: found %d applications\n", r.Source, len(r.Apps))
@@ -114,7 +170,7 @@ func runGenerate(args []string) {
 		}
 	}
 
-	if totalApps == 0 {
+	if totalApps == 0 && !*jsonOut {
 		fmt.Fprintf(os.Stderr, "No applications discovered.\n")
 		return
 	}
@@ -122,34 +178,187 @@ func runGenerate(args []string) {
 	// Collect, deduplicate, and generate
 	apps := discover.CollectApps(results)
 	apps = discover.DeduplicateApps(apps)
+	if *minConfidence > 0 {
+		kept := discover.FilterByConfidence(apps, *minConfidence)
+		if skipped := len(apps) - len(kept); skipped > 0 {
+			fmt.Fprintf(os.Stderr, "Skipped %d applications with confidence below %d\n", skipped, *minConfidence)
+		}
+		apps = kept
+	}
 	fmt.Fprintf(os.Stderr, "Total: %d unique applications\n", len(apps))
 
-	if *dryRun {
-		if baseYAML != nil {
-			if err := discover.RenderMergedConfig(baseYAML, apps, os.Stdout); err != nil {
-				fmt.Fprintf(os.Stderr, "Error generating config: %v\n", err)
-				os.Exit(1)
-			}
-		} else {
-			if err := discover.WriteConfigStdout(apps); err != nil {
-				fmt.Fprintf(os.Stderr, "Error generating config: %v\n", err)
-				os.Exit(1)
-			}
+	if *jsonOut {
+		if apps == nil {
+			apps = []discover.DiscoveredApp{} // encode as [] rather than null
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(apps); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
 		}
 		return
 	}
+	if *details {
+		apps = discover.AppendDetails(apps)
+	}
+	if *icons {
+		apps = registry.SuggestIcons(apps)
+	}
 
-	// Write to file
-	if baseYAML != nil {
-		if err := discover.WriteMergedConfig(baseYAML, apps, *output); err != nil {
-			fmt.Fprintf(os.Stderr, "Error writing config: %v\n", err)
-			os.Exit(1)
+	if !*showOutput {
+		apps = discover.HideLauncherOutput(apps)
+	}
+	prov := discover.NewProvenance("generate", currentBuildInfo().Short(), results, len(apps))
+	if err := writeGenerated(baseYAML, apps, order, mergeOpts, *hotkeys, prov, *output, *dryRun); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing config: %v\n", err)
+		os.Exit(1)
+	}
+	if !*dryRun {
+		fmt.Printf("Config written to: %s\n", *output)
+	}
+}
+
+// writeGenerated renders apps as a config laid out per order, merged into
+// baseYAML using opts when it is not nil, with hotkeys assigned if asked, and
+// writes it under the provenance header to output, or to stdout when dryRun
+// is set. The conflicts a merge resolved are reported on stderr so they never
+// end up in the config.
+func writeGenerated(baseYAML []byte, apps []discover.DiscoveredApp, order discover.Ordering, opts discover.MergeOptions, hotkeys bool, prov discover.Provenance, output string, dryRun bool) error {
+	data, err := renderGenerated(baseYAML, apps, order, opts, hotkeys, prov)
+	if err != nil {
+		return err
+	}
+	if dryRun {
+		_, err := os.Stdout.Write(data)
+		return err
+	}
+	return os.WriteFile(output, data, 0644)
+}
+
+// renderGenerated returns the config writeGenerated writes.
+func renderGenerated(baseYAML []byte, apps []discover.DiscoveredApp, order discover.Ordering, opts discover.MergeOptions, hotkeys bool, prov discover.Provenance) ([]byte, error) {
+	var data []byte
+	if baseYAML == nil {
+		var buf bytes.Buffer
+		if err := discover.RenderConfigOrdered(apps, order, &buf); err != nil {
+			return nil, err
 		}
+		data = buf.Bytes()
 	} else {
-		if err := discover.WriteConfig(apps, *output); err != nil {
-			fmt.Fprintf(os.Stderr, "Error writing config: %v\n", err)
-			os.Exit(1)
+		merged, conflicts, err := discover.MergeWithBaseOptions(baseYAML, apps, order, opts)
+		if err != nil {
+			return nil, err
+		}
+		if len(conflicts) > 0 {
+			fmt.Fprintf(os.Stderr, "Merge conflicts resolved: %d\n", len(conflicts))
+			for _, c := range conflicts {
+				fmt.Fprintf(os.Stderr, "  %s\n", c)
+			}
+		}
+		data = merged
+	}
+	if hotkeys {
+		var err error
+		if data, err = discover.AssignHotkeys(data); err != nil {
+			return nil, err
+		}
+	}
+	return append([]byte(prov.Header()), data...), nil
+}
+
+// newRegistry returns a registry with every discovery source of this build.
+func newRegistry() *discover.Registry {
+	registry := discover.NewRegistry()
+	discoverwin.RegisterAll(registry)
+	discoverlinux.RegisterAll(registry)
+	registry.Register(&discovermame.Source{})
+	return registry
+}
+
+// applyCategories applies a --categories value of comma-separated
+// source=category pairs to registry.
+func applyCategories(registry *discover.Registry, spec string) error {
+	for _, pair := range splitList(spec) {
+		source, category, ok := strings.Cut(pair, "=")
+		source, category = strings.TrimSpace(source), strings.TrimSpace(category)
+		if !ok || category == "" {
+			return fmt.Errorf("expected source=category, got %q", pair)
+		}
+		if registry.SourceByName(source) == nil {
+			return fmt.Errorf("unknown source: %s", source)
+		}
+		registry.SetCategory(source, category)
+	}
+	return nil
+}
+
+// orderFlags are the flags, shared by generate and import, that override the
+// order: settings of the base config's discover: block.
+type orderFlags struct {
+	categories *string
+	sources    *string
+	items      *string
+}
+
+func addOrderFlags(fs *flag.FlagSet) orderFlags {
+	return orderFlags{
+		categories: fs.String("category-order", "", "Comma-separated categories to list first in the root menu, in this order (the rest follow alphabetically)"),
+		sources:    fs.String("source-order", "", "Comma-separated sources to list first in categories split by source, in this order"),
+		items:      fs.String("item-order", "", "Order of items within a menu: source (as collected) or alphabetical (default: source)"),
+	}
+}
+
+// apply returns order with every flag that was given replacing its setting.
+func (f orderFlags) apply(order discover.Ordering) (discover.Ordering, error) {
+	if *f.categories != "" {
+		order.Categories = splitList(*f.categories)
+	}
+	if *f.sources != "" {
+		order.Sources = splitList(*f.sources)
+	}
+	if *f.items != "" {
+		order.Items = *f.items
+	}
+	return order, order.Validate()
+}
+
+// splitList splits a comma-separated flag value, dropping empty entries.
+func splitList(s string) []string {
+	var out []string
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// printDiagnoses prints each source's availability and the checks behind it.
+func printDiagnoses(diagnoses []discover.Diagnosis) {
+	if len(diagnoses) == 0 {
+		fmt.Println("No discovery sources available on this platform.")
+		return
+	}
+	for _, d := range diagnoses {
+		avail := "available"
+		if !d.Available {
+			avail = "not found"
+		}
+		fmt.Printf("%s [%s]: %s\n", d.Source, d.Category, avail)
+		if len(d.Checks) == 0 {
+			fmt.Println("  (no details reported)")
+		}
+		for _, c := range d.Checks {
+			mark := "ok"
+			if !c.OK {
+				mark = "--"
+			}
+			if c.Detail != "" {
+				fmt.Printf("  [%s] %s: %s\n", mark, c.What, c.Detail)
+			} else {
+				fmt.Printf("  [%s] %s\n", mark, c.What)
+			}
 		}
 	}
-	fmt.Printf("Config written to: %s\n", *output)
 }
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/benworks/menuworks/discover"
+	"github.com/benworks/menuworks/discover/launcher"
+)
+
+// runImport handles the "menuworks import" subcommand: it reads the menus of
+// other launchers and writes them as a config, merged into --base if given.
+// Like generate, it never touches the TUI code path.
+func runImport(args []string) {
+	fs := flag.NewFlagSet("import", flag.ExitOnError)
+	output := fs.String("output", "config.yaml", "Output file path")
+	format := fs.String("format", "", "Input format: "+strings.Join(launcher.Formats, ", ")+" (default: from each path)")
+	base := fs.String("base", "", "Path to an existing config.yaml to merge imported items into")
+	prefer := fs.String("prefer", "", "Which side wins merge conflicts with --base: base or generated, for every section or per section (e.g. generated,menus=base)")
+	orderFlags := addOrderFlags(fs)
+	showOutput := fs.Bool("show-output", false, "Leave imported items to show the output dialog when they finish (default: showOutput: false for all but terminal programs)")
+	hotkeys := fs.Bool("hotkeys", false, "Write a hotkey, unique within its menu, into each imported item instead of leaving them to runtime auto-assignment")
+	category := fs.String("category", "", "Put every imported item in this category instead of the one the input gives")
+	dryRun := fs.Bool("dry-run", false, "Print config to stdout instead of writing a file")
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: menuworks import [flags] <path>...\n\n")
+		fmt.Fprintf(os.Stderr, "Import items from other launchers and generate a config.yaml file.\n")
+		fmt.Fprintf(os.Stderr, "A path is a folder or file of .desktop entries, a CSV file, or a JSON\n")
+		fmt.Fprintf(os.Stderr, "export of Windows shortcuts.\n\n")
+		fmt.Fprintf(os.Stderr, "Flags:\n")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	if fs.NArg() == 0 {
+		fs.Usage()
+		os.Exit(2)
+	}
+	mergeOpts, err := discover.ParseMergeOptions(*prefer)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: --prefer: %v\n", err)
+		os.Exit(2)
+	}
+
+	// Check output file does not already exist (unless dry-run)
+	if !*dryRun {
+		if _, err := os.Stat(*output); err == nil {
+			fmt.Fprintf(os.Stderr, "Error: output file already exists: %s\nWill not overwrite existing files. Choose a different --output path or remove the existing file.\n", *output)
+			os.Exit(1)
+		} else if !os.IsNotExist(err) {
+			fmt.Fprintf(os.Stderr, "Error checking output file: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	var baseYAML []byte
+	if *base != "" {
+		baseYAML, err = os.ReadFile(*base)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error reading base config: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	var order discover.Ordering
+	if baseYAML != nil {
+		if discoverCfg, err := discover.ParseDiscoverConfig(baseYAML); err == nil {
+			order = discoverCfg.Order
+		}
+	}
+	if order, err = orderFlags.apply(order); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(2)
+	}
+
+	var apps []discover.DiscoveredApp
+	var results []discover.DiscoverResult
+	for _, path := range fs.Args() {
+		f := *format
+		if f == "" {
+			var err error
+			if f, err = launcher.DetectFormat(path); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+		}
+		imported, err := launcher.Read(f, path)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error importing %s: %v\n", path, err)
+			os.Exit(1)
+		}
+		fmt.Fprintf(os.Stderr, "  %s (%s): found %d items\n", path, f, len(imported))
+		apps = append(apps, imported...)
+		results = append(results, discover.DiscoverResult{Source: path, Apps: imported})
+	}
+
+	if *category != "" {
+		for i := range apps {
+			apps[i].Category = *category
+		}
+	}
+	apps = discover.DeduplicateApps(apps)
+	if len(apps) == 0 {
+		fmt.Fprintf(os.Stderr, "No items imported.\n")
+		return
+	}
+	fmt.Fprintf(os.Stderr, "Total: %d unique items\n", len(apps))
+
+	if !*showOutput {
+		apps = discover.HideLauncherOutput(apps)
+	}
+	prov := discover.NewProvenance("import", currentBuildInfo().Short(), results, len(apps))
+	if err := writeGenerated(baseYAML, apps, order, mergeOpts, *hotkeys, prov, *output, *dryRun); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing config: %v\n", err)
+		os.Exit(1)
+	}
+	if !*dryRun {
+		fmt.Printf("Config written to: %s\n", *output)
+	}
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/benworks/menuworks/menu"
+)
+
+// runList handles the "menuworks list" subcommand.
+// It prints the full menu tree with each command resolved for the current OS.
+func runList(args []string) {
+	fs := flag.NewFlagSet("list", flag.ExitOnError)
+	configFlag := fs.String("config", "", "Path to config.yaml file (default: user config directory, then binary directory)")
+	profileFlag := fs.String("profile", "", "Profile to load (name of a .yaml file in the config directory)")
+	portableFlag := fs.Bool("portable", false, "Use config.yaml next to the binary instead of the user config directory")
+	jsonFlag := fs.Bool("json", false, "Print the tree as JSON")
+	pathsFlag := fs.Bool("paths", false, "Print only the path of each runnable command, one per line (for fzf and `menuworks run`)")
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: menuworks list [flags]\n\n")
+		fmt.Fprintf(os.Stderr, "Print the menu tree: labels, hotkeys, types, submenu targets, and the\n")
+		fmt.Fprintf(os.Stderr, "command each item runs on this platform.\n\n")
+		fmt.Fprintf(os.Stderr, "Flags:\n")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	cfg, _, err := loadExistingConfig(*configFlag, *profileFlag, *portableFlag)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	tree := menu.Tree(cfg)
+
+	switch {
+	case *jsonFlag:
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(map[string]interface{}{"title": cfg.Title, "items": tree}); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	case *pathsFlag:
+		printPaths(tree)
+	default:
+		fmt.Println(cfg.Title)
+		printTree(tree, 1)
+	}
+}
+
+// printTree prints nodes indented by depth, one item per line
+func printTree(nodes []menu.Node, depth int) {
+	indent := strings.Repeat("    ", depth)
+	for _, node := range nodes {
+		if node.Type == "separator" {
+			fmt.Printf("%s────────\n", indent)
+			continue
+		}
+
+		hotkey := "   "
+		if node.Hotkey != "" {
+			hotkey = "[" + node.Hotkey + "]"
+		}
+		line := fmt.Sprintf("%s%s %s", indent, hotkey, node.Label)
+		switch node.Type {
+		case "submenu":
+			line += "  → " + node.Target
+		case "command":
+			line += "  $ " + node.Exec
+		case "back":
+			line += "  (back)"
+		case "quit":
+			line += "  (quit)"
+		case "parallel":
+			line += "  (parallel)"
+		case "group":
+			line += "  (group)"
+		case "edit_config":
+			line += "  (edit config)"
+		case "fallback_shell":
+			line += "  (fallback shell)"
+		case "switch_role":
+			line += "  (switch role)"
+		case "url":
+			line += "  ↗ " + node.URL
+		case "browse":
+			line += "  (browse " + node.Browse + ")  $ " + node.Exec
+		}
+		if len(node.Roles) > 0 {
+			line += "  (roles: " + strings.Join(node.Roles, ", ") + ")"
+		}
+		if node.Disabled {
+			line += "  (disabled)"
+		}
+		fmt.Println(line)
+		printTree(node.Children, depth+1)
+	}
+}
+
+// printPaths prints the path of every enabled command item
+func printPaths(nodes []menu.Node) {
+	for _, node := range nodes {
+		if node.Type == "command" && !node.Disabled {
+			fmt.Println(node.Path)
+		}
+		printPaths(node.Children)
+	}
+}
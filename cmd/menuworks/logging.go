@@ -0,0 +1,40 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+
+	"github.com/benworks/menuworks/logging"
+)
+
+// setupLogging opens the debug log requested by -debug, -log-file, and
+// -log-level. With none of them set, logging stays off and the returned
+// closer does nothing. -debug alone logs at debug level to logging.DefaultPath.
+func setupLogging(debug bool, file, level string) (io.Closer, error) {
+	if !debug && file == "" && level == "" {
+		return io.NopCloser(nil), nil
+	}
+
+	lvl := slog.LevelInfo
+	if debug {
+		lvl = slog.LevelDebug
+	}
+	if level != "" {
+		parsed, ok := logging.ParseLevel(level)
+		if !ok {
+			return nil, fmt.Errorf("invalid log level '%s' (use debug, info, warn, or error)", level)
+		}
+		lvl = parsed
+	}
+	if file == "" {
+		file = logging.DefaultPath()
+	}
+
+	closer, err := logging.Open(file, lvl)
+	if err != nil {
+		return nil, err
+	}
+	logging.Info("menuworks starting", "version", currentBuildInfo().Short(), "level", lvl.String())
+	return closer, nil
+}
@@ -1,19 +1,17 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
-	"time"
-
-	"github.com/gdamore/tcell/v2"
 
+	"github.com/benworks/menuworks"
 	"github.com/benworks/menuworks/config"
-	"github.com/benworks/menuworks/exec"
-	"github.com/benworks/menuworks/menu"
-	"github.com/benworks/menuworks/ui"
+	"github.com/benworks/menuworks/logging"
+	"github.com/benworks/menuworks/secrets"
 )
 
 // version is injected at build time via -ldflags "-X main.version=X.Y.Z"
@@ -21,647 +19,187 @@ import (
 var version string
 
 func main() {
+	// As a login shell, sshd and scp ask for commands with "-c"; a menu can't
+	// run them, and must not fall through to showing itself
+	if len(os.Args) > 1 && os.Args[1] == "-c" {
+		fmt.Fprintf(os.Stderr, "menuworks: this account's shell is a menu, which doesn't run commands\n")
+		os.Exit(1)
+	}
+
 	// Check for subcommands before entering TUI mode
 	if len(os.Args) > 1 && os.Args[1] == "generate" {
 		runGenerate(os.Args[2:])
 		return
 	}
+	if len(os.Args) > 1 && os.Args[1] == "watch" {
+		runWatch(os.Args[2:])
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "import" {
+		runImport(os.Args[2:])
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "themes" {
+		runThemes(os.Args[2:])
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "version" {
+		runVersion(os.Args[2:])
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "self-update" {
+		runSelfUpdate(os.Args[2:])
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "list" {
+		runList(os.Args[2:])
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "export" {
+		runExport(os.Args[2:])
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "serve" {
+		runServe(os.Args[2:])
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "run" {
+		runItem(os.Args[2:])
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "secret" {
+		runSecret(os.Args[2:])
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "install-autostart" {
+		runInstallAutostart(os.Args[2:])
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "uninstall-autostart" {
+		runUninstallAutostart(os.Args[2:])
+		return
+	}
 
 	// Parse command-line flags
-	configFlag := flag.String("config", "", "Path to config.yaml file (default: same directory as binary)")
-	menuFlag := flag.String("menu", "", "Initial menu to display (default: root menu)")
+	configFlag := flag.String("config", "", "Path to config.yaml file (default: user config directory, then binary directory)")
+	profileFlag := flag.String("profile", "", "Profile to load (name of a .yaml file in the config directory)")
+	portableFlag := flag.Bool("portable", false, "Use config.yaml next to the binary instead of the user config directory")
+	menuFlag := flag.String("menu", "", "Initial menu to display, or a path such as games/steam (default: root menu)")
 	noSplashFlag := flag.Bool("no-splash", false, "Skip the splash screen on startup")
+	wrapperFlag := flag.Bool("wrapper", false, "Wrapper mode for a login shell or desktop replacement: Esc never exits and a crash restarts the menu")
+	roleFlag := flag.String("role", "", "Role whose items to show (default: $MENUWORKS_ROLE, then the config's default_role)")
+	debugFlag := flag.Bool("debug", false, "Write a debug log (default file: menuworks.log in the user cache directory)")
+	logFileFlag := flag.String("log-file", "", "Write the log to this file instead of the default")
+	logLevelFlag := flag.String("log-level", "", "Minimum log level: debug, info, warn, or error (default: debug with -debug, otherwise info)")
 
 	flag.Usage = func() {
 		fmt.Fprintf(os.Stderr, "Usage: %s [flags]\n", filepath.Base(os.Args[0]))
-		fmt.Fprintf(os.Stderr, "       %s generate [flags]\n\n", filepath.Base(os.Args[0]))
+		fmt.Fprintf(os.Stderr, "       %s generate [flags]\n", filepath.Base(os.Args[0]))
+		fmt.Fprintf(os.Stderr, "       %s watch [flags]\n", filepath.Base(os.Args[0]))
+		fmt.Fprintf(os.Stderr, "       %s import [flags] <path>...\n", filepath.Base(os.Args[0]))
+		fmt.Fprintf(os.Stderr, "       %s themes [flags]\n", filepath.Base(os.Args[0]))
+		fmt.Fprintf(os.Stderr, "       %s version [flags]\n", filepath.Base(os.Args[0]))
+		fmt.Fprintf(os.Stderr, "       %s self-update [flags]\n", filepath.Base(os.Args[0]))
+		fmt.Fprintf(os.Stderr, "       %s list [flags]\n", filepath.Base(os.Args[0]))
+		fmt.Fprintf(os.Stderr, "       %s export [flags]\n", filepath.Base(os.Args[0]))
+		fmt.Fprintf(os.Stderr, "       %s serve [flags]\n", filepath.Base(os.Args[0]))
+		fmt.Fprintf(os.Stderr, "       %s run [flags] <menu path or id>\n", filepath.Base(os.Args[0]))
+		fmt.Fprintf(os.Stderr, "       %s secret [flags] set|delete|list [name]\n", filepath.Base(os.Args[0]))
+		fmt.Fprintf(os.Stderr, "       %s install-autostart [flags]\n", filepath.Base(os.Args[0]))
+		fmt.Fprintf(os.Stderr, "       %s uninstall-autostart [flags]\n\n", filepath.Base(os.Args[0]))
 		fmt.Fprintf(os.Stderr, "A retro TUI menu system with hierarchical menus and menu chaining.\n\n")
 		fmt.Fprintf(os.Stderr, "Flags:\n")
 		flag.PrintDefaults()
 		fmt.Fprintf(os.Stderr, "\nSubcommands:\n")
 		fmt.Fprintf(os.Stderr, "  generate    Discover installed applications and generate a config.yaml file\n")
+		fmt.Fprintf(os.Stderr, "  watch       Regenerate the config whenever applications are installed or removed\n")
+		fmt.Fprintf(os.Stderr, "  import      Import items from other launchers (.desktop, CSV, shortcut JSON)\n")
+		fmt.Fprintf(os.Stderr, "  themes      List built-in themes\n")
+		fmt.Fprintf(os.Stderr, "  version     Print version and build information\n")
+		fmt.Fprintf(os.Stderr, "  self-update Download and install the latest release\n")
+		fmt.Fprintf(os.Stderr, "  list        Print the menu tree as text or JSON\n")
+		fmt.Fprintf(os.Stderr, "  export      Export the menu as Markdown or HTML documentation\n")
+		fmt.Fprintf(os.Stderr, "  serve       Serve the menu and a run endpoint over HTTP, or the menu over SSH\n")
+		fmt.Fprintf(os.Stderr, "  run         Execute a menu item without starting the menu\n")
+		fmt.Fprintf(os.Stderr, "  secret      Manage the encrypted file behind secret://name references\n")
+		fmt.Fprintf(os.Stderr, "  install-autostart    Start the menu when you log in\n")
+		fmt.Fprintf(os.Stderr, "  uninstall-autostart  Stop starting the menu at login\n")
 		fmt.Fprintf(os.Stderr, "\nRun '%s generate --help' for generate-specific flags.\n", filepath.Base(os.Args[0]))
 	}
 
 	flag.Parse()
 
-	// Determine config path and whether auto-creation is allowed
-	var configPath string
-	customConfig := *configFlag != ""
-	if customConfig {
-		// Use the user-specified path
-		absPath, err := filepath.Abs(*configFlag)
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error: invalid config path: %v\n", err)
-			os.Exit(1)
-		}
-		configPath = absPath
-	} else {
-		// Default: config.yaml in binary directory
-		ex, err := os.Executable()
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error: failed to determine executable path: %v\n", err)
-			os.Exit(1)
-		}
-		configPath = filepath.Join(filepath.Dir(ex), "config.yaml")
-	}
-
-	// Initialize screen
-	screen, err := ui.NewScreen()
+	logCloser, err := setupLogging(*debugFlag, *logFileFlag, *logLevelFlag)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error: failed to initialize screen: %v\n", err)
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}
-	defer screen.Close()
-
-	// Start event poller IMMEDIATELY after screen init (needed by all functions)
-	eventChan := screen.StartEventPoller()
-
-	// Check terminal size and show resize loop if needed
-	ensureTerminalSize(screen, eventChan)
-
-	// If a custom config path was specified, verify it exists before proceeding
-	if customConfig {
-		if _, err := os.Stat(configPath); os.IsNotExist(err) {
-			showMessageDialog(screen, eventChan, "Error", fmt.Sprintf("The specified configuration file was not found:\n%s", configPath))
-			os.Exit(1)
-		}
-	}
-
-	// Load configuration
-	var cfg *config.Config
-	wasCreated := false
-	for {
-		loadedCfg, created, loadErr := config.Load(configPath)
-		if loadErr == nil {
-			cfg = loadedCfg
-			wasCreated = created
-			break
-		}
-		handleConfigError(screen, eventChan, configPath, loadErr, customConfig)
-		// If handleConfigError didn't exit, assume we should retry
-		wasCreated = false // Error recovery means not a fresh creation
-	}
-
-	// Enable mouse support if configured (default: enabled)
-	if cfg.IsMouseEnabled() {
-		screen.EnableMouse()
-	}
-
-	// Apply theme from config (if specified)
-	applyThemeFromConfig(screen, cfg)
-
-	// Determine if splash screen should be shown (CLI flag overrides config)
-	showSplash := cfg.IsSplashEnabled()
-	if *noSplashFlag {
-		showSplash = false
-	}
-
-	if showSplash {
-		// Show splash screen with fixed 1000ms delay
-		screen.DrawSplashScreen(version)
-
-		// Consume and discard all events during splash (prevents macOS hang)
-		// Per spec: "key events are consumed and discarded by reading and ignoring tcell events"
-		splashStart := time.Now()
-		for time.Since(splashStart) < 1000*time.Millisecond {
-			select {
-			case <-eventChan:
-				// Event discarded (consumed but ignored)
-			case <-time.After(10 * time.Millisecond):
-				// No event, continue waiting
-			}
-		}
-	}
-
-	// Explicitly clear screen before transitioning to menu
-	screen.Clear()
-	screen.Sync()
-
-	// Show first-run notification if config was just created
-	if wasCreated {
-		showMessageDialog(screen, eventChan, "First Run", "A configuration file could not be found, so one has been created for you in the directory you ran MenuWorks. Edit this file to modify menu items. Press \"R\" to reload it.")
-	}
-
-	// Create navigator
-	navigator := menu.NewNavigator(cfg)
-
-	// Navigate to initial menu (CLI flag overrides config; silently ignored if not found)
-	initialMenu := cfg.InitialMenu
-	if *menuFlag != "" {
-		initialMenu = *menuFlag
-	}
-	if initialMenu != "" {
-		navigator.NavigateToMenu(initialMenu)
-	}
-
-	// Check for missing submenu targets on startup and report once per session
-	checkAndReportMissingTargets(screen, navigator)
-
-	// Main event loop
-	mainLoop(screen, configPath, navigator, cfg, eventChan)
-}
-
-// ensureTerminalSize verifies terminal is at least 80x25 and loops until resized if too small
-func ensureTerminalSize(screen *ui.Screen, eventChan <-chan tcell.Event) {
-	for {
-		w, h := screen.Size()
-		if w >= 80 && h >= 25 {
-			return // Terminal is large enough, proceed
-		}
-
-		// Draw error pop-up
-		screen.Clear()
-		dialogWidth := 50
-		dialogHeight := 8
-		startX := (w - dialogWidth) / 2
-		if startX < 0 {
-			startX = 0
-		}
-		startY := (h - dialogHeight) / 2
-		if startY < 0 {
-			startY = 0
-		}
-
-		screen.DrawBorder(startX, startY, dialogWidth, dialogHeight, " Terminal Too Small ")
-
-		// Draw message
-		msg := "Please resize your terminal to at least 80×25"
-		msgX := startX + (dialogWidth - len(msg)) / 2
-		if msgX < 0 {
-			msgX = 0
-		}
-		msgY := startY + 2
-		for i, ch := range msg {
-			screen.DrawChar(msgX+i, msgY, ch, ui.StyleNormal())
-		}
-
-		msg2 := fmt.Sprintf("Current size: %d×%d", w, h)
-		msg2X := startX + (dialogWidth - len(msg2)) / 2
-		if msg2X < 0 {
-			msg2X = 0
-		}
-		screen.DrawChar(msg2X, msgY+2, ' ', ui.StyleNormal())
-		for i, ch := range msg2 {
-			screen.DrawChar(msg2X+i, msgY+2, ch, ui.StyleNormal())
-		}
-
-		screen.Sync()
-
-		// Wait for resize or other events
-		ev := <-eventChan
-		if ev != nil {
-			// Check if Escape key was pressed
-			if keyEv, ok := ev.(*tcell.EventKey); ok {
-				if keyEv.Key() == tcell.KeyEscape {
-					screen.Close()
-					os.Exit(0)
-				}
-			}
-			// Otherwise, discard event and loop to check size again
-			continue
-		}
-	}
-}
-
-// checkTerminalSize verifies terminal is at least 80x25
-func checkTerminalSize(screen *ui.Screen) error {
-	w, h := screen.Size()
-	if w < 80 || h < 25 {
-		return fmt.Errorf("terminal too small (minimum 80x25, got %dx%d)", w, h)
-	}
-	return nil
-}
-
-// handleConfigError shows a dialog for config errors
-// When customConfig is true (user specified -config), the "Use Default" option is hidden
-// to prevent overwriting an unrelated config.yaml.
-func handleConfigError(screen *ui.Screen, eventChan <-chan tcell.Event, configPath string, err error, customConfig bool) {
-	w, h := screen.Size()
+	defer logCloser.Close()
 
-	// Ensure screen is large enough
-	if w < 80 || h < 25 {
-		fmt.Fprintf(os.Stderr, "Terminal too small for error dialog and cannot load config\n")
+	// Determine config path and whether auto-creation is allowed
+	configPath, customConfig, err := resolveConfigPath(*configFlag, *profileFlag, *portableFlag)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}
 
-	// Show error dialog with three options
-	dialogWidth := 60
-	dialogHeight := 14
-	startX := (w - dialogWidth) / 2
-	startY := (h - dialogHeight) / 2
-
-	selectedBtn := 0
-
-	for {
-		screen.ClearRect(0, 0, w, h)
-		screen.DrawBorder(startX, startY, dialogWidth, dialogHeight, " Config Error ")
-
-		// Draw error message with wrapping
-		errorLines := ui.WrapText(fmt.Sprintf("%v", err), dialogWidth-4)
-		lines := []string{
-			"Failed to load configuration.",
-			"Error:",
-		}
-		lines = append(lines, errorLines...)
-		msgY := startY + 2
-		buttonY := startY + dialogHeight - 3
-		maxLines := buttonY - msgY
-		for i, line := range lines {
-			if i >= maxLines {
-				break
-			}
-			if msgY+i < h {
-				screen.DrawString(startX+2, msgY+i, line, ui.StyleNormal())
-			}
-		}
-
-		// Draw buttons (hide "Use Default" for custom config paths)
-		var buttons []string
-		if customConfig {
-			buttons = []string{"Retry", "Exit"}
-		} else {
-			buttons = []string{"Retry", "Use Default", "Exit"}
-		}
-		buttonSpacing := (dialogWidth - 4) / len(buttons)
-
-		for i, btn := range buttons {
-			btnX := startX + 2 + (i * buttonSpacing)
-			btnText := fmt.Sprintf("[%s]", btn)
-			style := ui.StyleNormal()
-			if i == selectedBtn {
-				style = ui.StyleHighlight()
-			}
-			if btnX+len(btnText) < startX+dialogWidth-1 {
-				if buttonY < h {
-					screen.DrawString(btnX, buttonY, btnText, style)
-				}
-			}
-		}
-
-		screen.Sync()
-
-		// Handle input
-		ev := <-eventChan
-		if keyEv, ok := ev.(*tcell.EventKey); ok {
-			switch keyEv.Key() {
-			case tcell.KeyLeft:
-				selectedBtn = (selectedBtn - 1 + len(buttons)) % len(buttons)
-			case tcell.KeyRight:
-				selectedBtn = (selectedBtn + 1) % len(buttons)
-			case tcell.KeyEnter:
-				selectedLabel := buttons[selectedBtn]
-				switch selectedLabel {
-				case "Retry":
-					return
-				case "Use Default":
-					if err := config.WriteDefaultWithBackup(configPath); err != nil {
-						showErrorDialog(screen, eventChan, "Backup Exists", "A backup already exists. Remove config.yaml.bak or rename it, then try again.")
-						break
-					}
-					showMessageDialog(screen, eventChan, "Config Updated", "Default config written. Backup saved as config.yaml.bak.")
-					return
-				case "Exit":
-					os.Exit(0)
-				}
-			case tcell.KeyEscape:
-				return
-			}
-		}
-	}
-}
-
-// checkAndReportMissingTargets checks for missing submenu targets and reports them
-func checkAndReportMissingTargets(screen *ui.Screen, navigator *menu.Navigator) {
-	// Missing target errors will be reported per-menu the first time they're encountered
-	// This is handled dynamically in the main event loop
-}
-
-// showErrorDialog shows a single-button error dialog
-func showErrorDialog(screen *ui.Screen, eventChan <-chan tcell.Event, title, message string) {
-	w, h := screen.Size()
-
-	dialogWidth := 50
-	dialogHeight := 11
-	startX := (w - dialogWidth) / 2
-	startY := (h - dialogHeight) / 2
-
-	for {
-		screen.ClearRect(0, 0, w, h)
-		screen.DrawBorder(startX, startY, dialogWidth, dialogHeight, " "+title+" ")
-
-		// Draw message with wrapping (preserve explicit line breaks)
-		rawLines := strings.Split(message, "\n")
-		var lines []string
-		for _, rawLine := range rawLines {
-			wrapped := ui.WrapText(rawLine, dialogWidth-4)
-			if len(wrapped) == 0 {
-				lines = append(lines, "")
-				continue
-			}
-			lines = append(lines, wrapped...)
-		}
-		msgY := startY + 2
-		for i, line := range lines {
-			if i >= 5 {
-				break
-			}
-			if msgY+i < h {
-				screen.DrawString(startX+2, msgY+i, line, ui.StyleNormal())
-			}
-		}
-
-		// Draw button
-		buttonY := startY + dialogHeight - 2
-		btnX := startX + (dialogWidth-len("[OK]"))/2 - 1
-		if buttonY < h {
-			screen.DrawString(btnX, buttonY, "[OK]", ui.StyleHighlight())
-		}
-
-		screen.Sync()
-
-		// Handle input
-		ev := <-eventChan
-		if _, ok := ev.(*tcell.EventKey); ok {
-			break
-		}
+	// The menu itself lives in the menuworks package; this binary only
+	// parses flags and resolves the config path
+	a := menuworks.NewAppFromFile(configPath, customConfig)
+	a.InitialMenu = *menuFlag
+	a.NoSplash = *noSplashFlag
+	// login(1) starts a login shell with "-" before its name
+	a.Wrapper = *wrapperFlag || strings.HasPrefix(filepath.Base(os.Args[0]), "-")
+	a.Role = *roleFlag
+	if a.Role == "" {
+		a.Role = os.Getenv("MENUWORKS_ROLE")
+	}
+	a.Version = currentBuildInfo().Short()
+	a.CheckForUpdate = checkForUpdate
+	if err := a.Run(context.Background()); err != nil {
+		logging.Error("menuworks exited with error", "err", err)
+		logCloser.Close()
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
 	}
 }
 
-// mainLoop handles the main event loop
-func mainLoop(screen *ui.Screen, configPath string, navigator *menu.Navigator, cfg *config.Config, eventChan <-chan tcell.Event) {
-	// Track previous mouse button state for edge detection (act only on new presses)
-	var lastMouseButtons tcell.ButtonMask
-
-	handleSelection := func() {
-		item, _ := navigator.GetSelectedItem()
-		if item.Type == "submenu" {
-			if err := navigator.Open(); err != nil {
-				if !navigator.IsTargetErrorReported(navigator.GetCurrentMenuName()) {
-					showErrorDialog(screen, eventChan, "Error", fmt.Sprintf("Error: %v", err))
-					navigator.MarkTargetErrorReported(navigator.GetCurrentMenuName())
-				}
-			}
-			return
-		}
-
-		if item.Type == "command" {
-			// Determine if we should show output
-			showOutput := true // Default
-			if item.ShowOutput != nil {
-				showOutput = *item.ShowOutput
-			}
-
-			// Get the command for the current OS
-			command := item.Exec.CommandForOS(exec.GetOS())
-
-			// Execute command and capture output
-			output := exec.ExecuteAndCapture(command, item.Exec.WorkDir)
-
-			if showOutput && output != "" {
-				// Display output in scrollable viewer
-				screen.DrawCommandOutput(output, eventChan)
-			} else {
-				// No output or user chose to hide output
-				showMessageDialog(screen, eventChan, "Command Executed", "Command finished successfully.")
-			}
-			return
-		}
-
-		if item.Type == "back" {
-			if navigator.IsAtRoot() {
-				os.Exit(0)
-			}
-			navigator.Back()
-		}
-	}
-
-	// Main event loop
-	for {
-		// Check terminal size
-		w, h := screen.Size()
-		if w < 80 || h < 25 {
-			showResizeError(screen)
-			if err := waitForResize(screen, eventChan); err != nil {
-				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-				return
-			}
-			// Reload config after resize
-			newCfg, _, err := config.Load(configPath)
-			if err == nil {
-				cfg = newCfg
-				navigator = menu.NewNavigator(cfg)
-			}
-			continue
-		}
-
-		// Draw current menu
-		disabledItems := make(map[string]bool) // Placeholder for now
-		screen.DrawMenu(navigator, disabledItems)
-
-		// Get event from poller channel
-		ev := <-eventChan
-		if ev == nil {
-			continue
-		}
-
-		switch e := ev.(type) {
-		case *tcell.EventKey:
-			switch e.Key() {
-			case tcell.KeyUp:
-				navigator.PrevSelectable()
-
-			case tcell.KeyDown:
-				navigator.NextSelectable()
-
-			case tcell.KeyPgUp:
-				navigator.PageUp(14)
-
-			case tcell.KeyPgDn:
-				navigator.PageDown(14)
-
-			case tcell.KeyRight, tcell.KeyEnter:
-				handleSelection()
-
-			case tcell.KeyLeft, tcell.KeyEscape:
-				if navigator.IsAtRoot() {
-					return // Exit
-				}
-				navigator.Back()
-
-			case tcell.KeyF2:
-				// Show help for current item (if it's a command)
-				item, err := navigator.GetSelectedItem()
-				if err == nil && item.Type == "command" {
-					command := item.Exec.CommandForOS(exec.GetOS())
-					if command == "" {
-						command = "(No command defined for this platform)"
-					}
-					screen.ShowItemHelp(command, item.Help, eventChan)
-				}
-
-			case tcell.KeyRune:
-				if e.Rune() == 'R' || e.Rune() == 'r' {
-					// Reload config
-					newCfg, _, err := config.Load(configPath)
-					if err != nil {
-						showErrorDialog(screen, eventChan, "Reload Error", fmt.Sprintf("Failed to reload config: %v", err))
-					} else {
-						cfg = newCfg
-						// Apply theme from reloaded config
-						applyThemeFromConfig(screen, cfg)
-						// Preserve selection state as much as possible
-						oldNavState := navigator.RememberSelection()
-
-						navigator = menu.NewNavigator(cfg)
-						navigator.RecallSelection(oldNavState)
-
-						showMessageDialog(screen, eventChan, "Config Reloaded", "Configuration reloaded successfully.")
-					}
-					break
-				}
-
-				idx := navigator.SelectItemByHotkey(string(e.Rune()))
-				if idx >= 0 {
-					navigator.SetSelectionIndex(idx)
-					handleSelection()
-				}
-			}
-
-		case *tcell.EventResize:
-			// Just re-render on resize
-			continue
-
-		case *tcell.EventMouse:
-			buttons := e.Buttons()
-			// Edge detection: only act on NEW presses (not held buttons)
-			newPresses := buttons &^ lastMouseButtons
-			// Release detection: buttons that were pressed but now aren't
-			released := lastMouseButtons &^ buttons
-			lastMouseButtons = buttons
-
-			// Check wheel first (transient one-shot events)
-			if newPresses&tcell.WheelUp != 0 {
-				navigator.PrevSelectable()
-			} else if newPresses&tcell.WheelDown != 0 {
-				navigator.NextSelectable()
-			} else if newPresses&tcell.ButtonPrimary != 0 {
-				// Left click = Enter/select (on press)
-				handleSelection()
-			} else if released&tcell.ButtonSecondary != 0 {
-				// Right click = Back/exit (on release, to filter phantom events)
-				if navigator.IsAtRoot() {
-					return
-				}
-				navigator.Back()
-			}
+// resolveConfigPath returns the config file to use and whether it was chosen
+// explicitly (-config or -profile), in which case it must already exist.
+func resolveConfigPath(configFlag, profile string, portable bool) (string, bool, error) {
+	if configFlag != "" {
+		// Use the user-specified path
+		absPath, err := filepath.Abs(configFlag)
+		if err != nil {
+			return "", false, fmt.Errorf("invalid config path: %w", err)
 		}
+		return absPath, true, nil
 	}
-}
-
-// showResizeError shows an error when terminal is too small
-func showResizeError(screen *ui.Screen) {
-	w, h := screen.Size()
 
-	if w >= 80 && h >= 25 {
-		return // No error if big enough
+	// Default: user config directory, falling back to config.yaml in binary directory
+	ex, err := os.Executable()
+	if err != nil {
+		return "", false, fmt.Errorf("failed to determine executable path: %w", err)
 	}
-
-	// Show error in small terminal
-	fmt.Printf("Terminal too small (%dx%d). Minimum required: 80x25\n", w, h)
-	fmt.Println("Resize your terminal and try again.")
-}
-
-// waitForResize waits for terminal to be resized to at least 80x25
-func waitForResize(screen *ui.Screen, eventChan <-chan tcell.Event) error {
-	for {
-		ev := <-eventChan
-		if _, ok := ev.(*tcell.EventResize); ok {
-			w, h := screen.Size()
-			if w >= 80 && h >= 25 {
-				return nil
-			}
-		}
-		time.Sleep(10 * time.Millisecond)
+	configPath := config.ResolvePath(filepath.Dir(ex), portable)
+	if profile != "" {
+		// Profiles live alongside the default config; a missing profile is an error, not a first run
+		return config.ProfilePath(filepath.Dir(configPath), profile), true, nil
 	}
+	return configPath, false, nil
 }
 
-// showMessageDialog shows a message dialog
-func showMessageDialog(screen *ui.Screen, eventChan <-chan tcell.Event, title, message string) {
-	w, h := screen.Size()
-
-	dialogWidth := 50
-	dialogHeight := 12
-	startX := (w - dialogWidth) / 2
-	startY := (h - dialogHeight) / 2
-
-	for {
-		screen.ClearRect(0, 0, w, h)
-		screen.DrawBorder(startX, startY, dialogWidth, dialogHeight, " "+title+" ")
-
-		// Draw message with text wrapping
-		lines := ui.WrapText(message, dialogWidth-4)
-		msgY := startY + 2
-		for i, line := range lines {
-			if i >= 6 {
-				break
-			}
-			if msgY+i < h {
-				screen.DrawString(startX+2, msgY+i, line, ui.StyleNormal())
-			}
-		}
-
-		// Draw button
-		buttonY := startY + dialogHeight - 2
-		btnX := startX + (dialogWidth-len("[OK]"))/2 - 1
-		if buttonY < h {
-			screen.DrawString(btnX, buttonY, "[OK]", ui.StyleHighlight())
-		}
-
-		screen.Sync()
-
-		// Handle input
-		ev := <-eventChan
-		if _, ok := ev.(*tcell.EventKey); ok {
-			break
-		}
+// loadExistingConfig resolves and loads the config for subcommands that work
+// without the menu. Unlike the menu, a missing file is an error, not a first run.
+func loadExistingConfig(configFlag, profile string, portable bool) (*config.Config, string, error) {
+	configPath, _, err := resolveConfigPath(configFlag, profile, portable)
+	if err != nil {
+		return nil, "", err
 	}
-}
-
-// applyThemeFromConfig loads and applies the theme from the config
-// If theme is not specified or invalid, uses default colors
-func applyThemeFromConfig(screen *ui.Screen, cfg *config.Config) {
-	// Validate theme first
-	warnings := config.ValidateTheme(cfg)
-	
-	// Get theme colors
-	themeColors := config.GetThemeColors(cfg)
-	if themeColors != nil {
-		// Convert config.ThemeColors to ui.ThemeColors
-		uiTheme := ui.ThemeColors{
-			Background:  themeColors.Background,
-			Text:        themeColors.Text,
-			Border:      themeColors.Border,
-			HighlightBg: themeColors.HighlightBg,
-			HighlightFg: themeColors.HighlightFg,
-			Hotkey:      themeColors.Hotkey,
-			Shadow:      themeColors.Shadow,
-			Disabled:    themeColors.Disabled,
-			MenuBg:      themeColors.MenuBg,
-		}
-		
-		// Apply theme with color parser
-		ui.ApplyTheme(uiTheme, config.ParseColorName)
-		
-		// Refresh screen's default style to pick up new theme colors
-		screen.RefreshTheme()
-		
-		// Log warnings if any (could be shown in footer or ignored)
-		if len(warnings) > 0 {
-			// For now, silently continue with defaults for invalid colors
-			// The color parser will use defaults for invalid names
-		}
+	if _, err := os.Stat(configPath); err != nil {
+		return nil, "", fmt.Errorf("config file not found: %s", configPath)
 	}
-	// If themeColors is nil, keep using default colors (no action needed)
+	secrets.SetFile(secrets.DefaultFile(configPath))
+	cfg, _, err := config.Load(configPath)
+	return cfg, configPath, err
 }
-
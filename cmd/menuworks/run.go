@@ -0,0 +1,153 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/benworks/menuworks/config"
+	"github.com/benworks/menuworks/exec"
+	"github.com/benworks/menuworks/menu"
+)
+
+// runItem handles the "menuworks run" subcommand.
+// It resolves a command item by menu path or id and executes it without the TUI,
+// passing the command's stdio through and exiting with its exit code. A
+// parallel item's commands run together with their output captured.
+func runItem(args []string) {
+	fs := flag.NewFlagSet("run", flag.ExitOnError)
+	configFlag := fs.String("config", "", "Path to config.yaml file (default: user config directory, then binary directory)")
+	profileFlag := fs.String("profile", "", "Profile to load (name of a .yaml file in the config directory)")
+	portableFlag := fs.Bool("portable", false, "Use config.yaml next to the binary instead of the user config directory")
+	selectFlag := fs.String("select", "", "Item to run, as a menu path (\"Games/Steam/Portal 2\") or item id")
+	debugFlag := fs.Bool("debug", false, "Write a debug log (default file: menuworks.log in the user cache directory)")
+	logFileFlag := fs.String("log-file", "", "Write the log to this file instead of the default")
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: menuworks run [flags] <menu path or id>\n\n")
+		fmt.Fprintf(os.Stderr, "Execute a command or parallel item without starting the menu. The path is\n")
+		fmt.Fprintf(os.Stderr, "the chain of labels from the root menu separated by \"/\", matched\n")
+		fmt.Fprintf(os.Stderr, "case-insensitively.\n\n")
+		fmt.Fprintf(os.Stderr, "Flags:\n")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	path := *selectFlag
+	if path == "" && fs.NArg() > 0 {
+		path = fs.Arg(0)
+	}
+	if path == "" {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	logCloser, err := setupLogging(*debugFlag, *logFileFlag, "")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	defer logCloser.Close()
+
+	cfg, _, err := loadExistingConfig(*configFlag, *profileFlag, *portableFlag)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	item, err := menu.FindItem(cfg, path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	if item.Type != "command" && item.Type != "parallel" {
+		fmt.Fprintf(os.Stderr, "Error: '%s' is a %s item, not a command\n", path, item.Type)
+		os.Exit(1)
+	}
+	if item.Disabled {
+		msg := fmt.Sprintf("'%s' is disabled", path)
+		if item.DisabledReason != "" {
+			msg += ": " + item.DisabledReason
+		}
+		fmt.Fprintf(os.Stderr, "Error: %s\n", msg)
+		os.Exit(1)
+	}
+	if !item.AvailableAt(time.Now()) {
+		fmt.Fprintf(os.Stderr, "Error: '%s' is only available %s\n", path, item.ScheduleText())
+		os.Exit(1)
+	}
+	if item.Type == "parallel" {
+		os.Exit(runParallelItem(item, path))
+	}
+	command := item.Exec.CommandForOS(exec.GetOS())
+	if command == "" {
+		fmt.Fprintf(os.Stderr, "Error: '%s' has no command for this platform\n", path)
+		os.Exit(1)
+	}
+
+	// A prompt needs no dialog here: the command reads the terminal directly
+	stdin, err := item.OpenStdin()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	if stdin != nil {
+		defer stdin.Close()
+	}
+
+	// The terminal is attached, so sudo can ask for a password
+	command, workDir, err := exec.RunAs(command, item.Exec.WorkDir, item.RunAs, true)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if err := exec.Execute(command, item.ArgvForOS(exec.GetOS()), workDir, stdin); err != nil {
+		var exitErr interface{ ExitCode() int }
+		if errors.As(err, &exitErr) && exitErr.ExitCode() > 0 {
+			os.Exit(exitErr.ExitCode())
+		}
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// runParallelItem runs a parallel item's commands at the same time and prints
+// their combined output, each line prefixed with its command's label. It
+// returns the exit code for menuworks: the first failure's, or 0.
+func runParallelItem(item config.MenuItem, path string) int {
+	commands := item.CommandsForOS(exec.GetOS())
+	if len(commands) == 0 {
+		fmt.Fprintf(os.Stderr, "Error: '%s' has no command for this platform\n", path)
+		return 1
+	}
+	jobs, err := exec.ParallelJobs(commands, exec.GetOS())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	defer exec.CloseJobs(jobs)
+
+	// Ctrl+C interrupts every command, as in the menu
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	if d := item.TimeoutDuration(); d > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, d)
+		defer cancel()
+	}
+	results := exec.RunParallel(ctx, jobs)
+	if output := exec.CombineOutput(results); output != "" {
+		fmt.Println(output)
+	}
+
+	succeeded, exitCode := exec.CountSucceeded(results)
+	fmt.Fprintf(os.Stderr, "%d of %d succeeded\n", succeeded, len(results))
+	if exitCode < 0 {
+		return 1
+	}
+	return exitCode
+}
@@ -0,0 +1,159 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"golang.org/x/term"
+
+	"github.com/benworks/menuworks/secrets"
+)
+
+// runSecret handles the "menuworks secret" subcommand.
+// It adds, removes, and lists the secrets in the encrypted file that
+// secret://name references fall back to when a name isn't in the OS keyring.
+func runSecret(args []string) {
+	fs := flag.NewFlagSet("secret", flag.ExitOnError)
+	configFlag := fs.String("config", "", "Path to config.yaml file (default: user config directory, then binary directory)")
+	profileFlag := fs.String("profile", "", "Profile to load (name of a .yaml file in the config directory)")
+	portableFlag := fs.Bool("portable", false, "Use config.yaml next to the binary instead of the user config directory")
+	fileFlag := fs.String("file", "", "Encrypted secrets file (default: "+secrets.FileName+" next to the config)")
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: menuworks secret [flags] set <name>\n")
+		fmt.Fprintf(os.Stderr, "       menuworks secret [flags] delete <name>\n")
+		fmt.Fprintf(os.Stderr, "       menuworks secret [flags] list\n\n")
+		fmt.Fprintf(os.Stderr, "Manage the encrypted file behind secret://name references in commands.\n")
+		fmt.Fprintf(os.Stderr, "The passphrase is read from %s, or asked for. set reads the\n", secrets.KeyEnv)
+		fmt.Fprintf(os.Stderr, "value from the terminal without echoing it, or from standard input.\n\n")
+		fmt.Fprintf(os.Stderr, "Flags:\n")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	action, name := fs.Arg(0), fs.Arg(1)
+	switch {
+	case action == "list" && fs.NArg() == 1:
+	case (action == "set" || action == "delete") && fs.NArg() == 2:
+		if !secrets.ValidName(name) {
+			fmt.Fprintf(os.Stderr, "Error: invalid secret name %q (use letters, digits, '.', '_' and '-')\n", name)
+			os.Exit(2)
+		}
+	default:
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	path := *fileFlag
+	if path == "" {
+		configPath, _, err := resolveConfigPath(*configFlag, *profileFlag, *portableFlag)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		path = secrets.DefaultFile(configPath)
+	}
+
+	if err := editSecrets(path, action, name); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// editSecrets carries out action on the secrets file at path
+func editSecrets(path, action, name string) error {
+	_, statErr := os.Stat(path)
+	exists := statErr == nil
+	if !exists && action != "set" {
+		return fmt.Errorf("no secrets file at %s", path)
+	}
+
+	passphrase, err := secretsPassphrase(path, !exists)
+	if err != nil {
+		return err
+	}
+	values := map[string]string{}
+	if exists {
+		if values, err = secrets.ReadFile(path, passphrase); err != nil {
+			return err
+		}
+	}
+
+	switch action {
+	case "list":
+		for _, n := range secrets.Names(values) {
+			fmt.Println(n)
+		}
+		return nil
+	case "delete":
+		if _, ok := values[name]; !ok {
+			return fmt.Errorf("no secret named %q in %s", name, path)
+		}
+		delete(values, name)
+	case "set":
+		value, err := readSecretValue(name)
+		if err != nil {
+			return err
+		}
+		values[name] = value
+	}
+	if err := secrets.WriteFile(path, passphrase, values); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "Updated %s\n", path)
+	return nil
+}
+
+// secretsPassphrase returns the file's passphrase from the environment, or
+// asks for it; a new file's passphrase is asked for twice
+func secretsPassphrase(path string, create bool) (string, error) {
+	if p := os.Getenv(secrets.KeyEnv); p != "" {
+		return p, nil
+	}
+	if !term.IsTerminal(int(os.Stdin.Fd())) {
+		return "", fmt.Errorf("set %s to the passphrase for %s", secrets.KeyEnv, path)
+	}
+	passphrase, err := readHidden(fmt.Sprintf("Passphrase for %s: ", path))
+	if err != nil {
+		return "", err
+	}
+	if passphrase == "" {
+		return "", errors.New("the passphrase must not be empty")
+	}
+	if create {
+		again, err := readHidden("Repeat the passphrase: ")
+		if err != nil {
+			return "", err
+		}
+		if again != passphrase {
+			return "", errors.New("the passphrases don't match")
+		}
+	}
+	return passphrase, nil
+}
+
+// readSecretValue reads the value for name from the terminal without echoing
+// it, or else the first line of standard input, so it never appears in the
+// shell's history
+func readSecretValue(name string) (string, error) {
+	if term.IsTerminal(int(os.Stdin.Fd())) {
+		return readHidden(fmt.Sprintf("Value for %s: ", name))
+	}
+	line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && err != io.EOF {
+		return "", err
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+// readHidden prompts on stderr and reads a line from the terminal without echo
+func readHidden(prompt string) (string, error) {
+	fmt.Fprint(os.Stderr, prompt)
+	b, err := term.ReadPassword(int(os.Stdin.Fd()))
+	fmt.Fprintln(os.Stderr)
+	return string(b), err
+}
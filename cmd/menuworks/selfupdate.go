@@ -0,0 +1,76 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+
+	"github.com/benworks/menuworks/update"
+)
+
+// checkForUpdate reports the latest release if it is newer than this build.
+// Used for the opt-in startup check; errors just mean no badge.
+func checkForUpdate(ctx context.Context) (string, bool) {
+	release, err := update.NewChecker().Latest(ctx)
+	if err != nil {
+		return "", false
+	}
+	return release.Version, update.IsNewer(version, release.Version)
+}
+
+// runSelfUpdate handles the "menuworks self-update" subcommand.
+// It replaces the running binary with the latest release after verifying its checksum.
+func runSelfUpdate(args []string) {
+	fs := flag.NewFlagSet("self-update", flag.ExitOnError)
+	checkFlag := fs.Bool("check", false, "Only report whether an update is available")
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: menuworks self-update [flags]\n\n")
+		fmt.Fprintf(os.Stderr, "Download the latest release from GitHub, verify it against the release's\n")
+		fmt.Fprintf(os.Stderr, "checksums.txt, and replace this binary (the old one is kept as .old).\n\n")
+		fmt.Fprintf(os.Stderr, "Flags:\n")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	current := currentBuildInfo().Version
+	checker := update.NewChecker()
+	ctx := context.Background()
+
+	release, err := checker.Latest(ctx)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: failed to check for updates: %v\n", err)
+		os.Exit(1)
+	}
+	if !update.IsNewer(current, release.Version) {
+		fmt.Printf("menuworks %s is up to date (latest release: %s)\n", current, release.Version)
+		return
+	}
+	if *checkFlag {
+		fmt.Printf("Update available: %s -> %s\n", current, release.Version)
+		return
+	}
+
+	asset := update.AssetName(runtime.GOOS, runtime.GOARCH)
+	if asset == "" {
+		fmt.Fprintf(os.Stderr, "Error: no release binary is published for %s/%s\n", runtime.GOOS, runtime.GOARCH)
+		os.Exit(1)
+	}
+	exe, err := os.Executable()
+	if err == nil {
+		exe, err = filepath.EvalSymlinks(exe)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: failed to determine executable path: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("Updating menuworks %s -> %s...\n", current, release.Version)
+	if err := checker.Install(ctx, release, asset, exe); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("Installed %s\n", exe)
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"time"
+
+	"github.com/gliderlabs/ssh"
+
+	"github.com/benworks/menuworks/logging"
+	"github.com/benworks/menuworks/server"
+)
+
+// runServe handles the "menuworks serve" subcommand.
+// It serves the menu structure and a token-protected run endpoint over HTTP,
+// and with -ssh the menu itself to SSH clients, until interrupted.
+func runServe(args []string) {
+	fs := flag.NewFlagSet("serve", flag.ExitOnError)
+	configFlag := fs.String("config", "", "Path to config.yaml file (default: user config directory, then binary directory)")
+	profileFlag := fs.String("profile", "", "Profile to load (name of a .yaml file in the config directory)")
+	portableFlag := fs.Bool("portable", false, "Use config.yaml next to the binary instead of the user config directory")
+	listenFlag := fs.String("listen", "127.0.0.1:8080", "Address to listen on (use :8080 for all interfaces)")
+	tokenFlag := fs.String("token", "", "Bearer token required to run items (default: $MENUWORKS_TOKEN)")
+	sshFlag := fs.String("ssh", "", "Serve the menu to SSH clients on this address, e.g. :2222 (HTTP is then only served if -listen is given)")
+	hostKeyFlag := fs.String("host-key", "", "SSH host key file, created if missing (default: "+hostKeyFileName+" next to the config)")
+	authorizedKeysFlag := fs.String("authorized-keys", "", "Public keys allowed to connect over SSH (default: ~/.ssh/authorized_keys)")
+	debugFlag := fs.Bool("debug", false, "Write a debug log (default file: menuworks.log in the user cache directory)")
+	logFileFlag := fs.String("log-file", "", "Write the log to this file instead of the default")
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: menuworks serve [flags]\n\n")
+		fmt.Fprintf(os.Stderr, "Serve the menu over HTTP:\n")
+		fmt.Fprintf(os.Stderr, "  GET  /api/menu   menu tree as JSON\n")
+		fmt.Fprintf(os.Stderr, "  POST /api/run    {\"item\": \"<menu path or id>\"} with Authorization: Bearer <token>\n\n")
+		fmt.Fprintf(os.Stderr, "With -ssh, show the menu itself to SSH clients whose key is authorized.\n\n")
+		fmt.Fprintf(os.Stderr, "Flags:\n")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	// -ssh on its own serves SSH only; HTTP needs -listen alongside it
+	serveHTTP := *sshFlag == ""
+	fs.Visit(func(f *flag.Flag) {
+		if f.Name == "listen" {
+			serveHTTP = true
+		}
+	})
+
+	token := *tokenFlag
+	if token == "" {
+		token = os.Getenv("MENUWORKS_TOKEN")
+	}
+	if serveHTTP && token == "" {
+		fmt.Fprintf(os.Stderr, "Error: a token is required; pass -token or set MENUWORKS_TOKEN\n")
+		os.Exit(1)
+	}
+
+	logCloser, err := setupLogging(*debugFlag, *logFileFlag, "")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	defer logCloser.Close()
+
+	cfg, configPath, err := loadExistingConfig(*configFlag, *profileFlag, *portableFlag)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	var httpServer *http.Server
+	if serveHTTP {
+		srv, err := server.New(cfg, token)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		httpServer = &http.Server{Addr: *listenFlag, Handler: srv.Handler(), ReadHeaderTimeout: 10 * time.Second}
+	}
+	var sshServer *ssh.Server
+	if *sshFlag != "" {
+		hostKey := *hostKeyFlag
+		if hostKey == "" {
+			hostKey = filepath.Join(filepath.Dir(configPath), hostKeyFileName)
+		}
+		authorizedKeys := *authorizedKeysFlag
+		if authorizedKeys == "" {
+			home, err := os.UserHomeDir()
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			authorizedKeys = filepath.Join(home, ".ssh", "authorized_keys")
+		}
+		if sshServer, err = newSSHServer(*sshFlag, configPath, hostKey, authorizedKeys); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	// Shut down cleanly on Ctrl+C, letting running commands finish
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	shutdownDone := make(chan struct{})
+	go func() {
+		defer close(shutdownDone)
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		if httpServer != nil {
+			httpServer.Shutdown(shutdownCtx)
+		}
+		if sshServer != nil {
+			// Menus in open sessions are not waited for
+			sshServer.Close()
+		}
+	}()
+
+	errs := make(chan error, 2)
+	if httpServer != nil {
+		fmt.Fprintf(os.Stderr, "Serving %s on http://%s\n", configPath, *listenFlag)
+		logging.Info("serving", "config", configPath, "listen", *listenFlag)
+		go func() {
+			if err := httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				errs <- err
+			}
+		}()
+	}
+	if sshServer != nil {
+		fmt.Fprintf(os.Stderr, "Serving %s over SSH on %s\n", configPath, *sshFlag)
+		logging.Info("serving ssh", "config", configPath, "listen", *sshFlag)
+		go func() {
+			if err := sshServer.ListenAndServe(); err != nil && !errors.Is(err, ssh.ErrServerClosed) {
+				errs <- err
+			}
+		}()
+	}
+	select {
+	case err := <-errs:
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	case <-ctx.Done():
+	}
+	<-shutdownDone
+}
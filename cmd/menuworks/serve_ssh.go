@@ -0,0 +1,147 @@
+package main
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/gliderlabs/ssh"
+	gossh "golang.org/x/crypto/ssh"
+
+	"github.com/benworks/menuworks"
+	"github.com/benworks/menuworks/logging"
+	"github.com/benworks/menuworks/ui"
+)
+
+// hostKeyFileName is the default SSH host key file, kept next to the config
+const hostKeyFileName = "ssh_host_ed25519_key"
+
+// newSSHServer returns a server that shows the menu in configPath to every
+// SSH client holding one of the keys in authorizedKeysPath. Each session gets
+// its own menu, drawn to the client's terminal; its commands run on this
+// machine as the user running serve.
+func newSSHServer(addr, configPath, hostKeyPath, authorizedKeysPath string) (*ssh.Server, error) {
+	authorized, err := readAuthorizedKeys(authorizedKeysPath)
+	if err != nil {
+		return nil, err
+	}
+	hostKey, err := loadHostKey(hostKeyPath)
+	if err != nil {
+		return nil, err
+	}
+
+	srv := &ssh.Server{
+		Addr: addr,
+		Handler: func(sess ssh.Session) {
+			serveSSHSession(sess, configPath)
+		},
+		PublicKeyHandler: func(ctx ssh.Context, key ssh.PublicKey) bool {
+			for _, k := range authorized {
+				if ssh.KeysEqual(k, key) {
+					return true
+				}
+			}
+			logging.Warn("rejected ssh key", "user", ctx.User(), "remote", ctx.RemoteAddr())
+			return false
+		},
+	}
+	srv.AddHostKey(hostKey)
+	return srv, nil
+}
+
+// serveSSHSession runs a menu on one SSH session until the user exits it or
+// the client disconnects
+func serveSSHSession(sess ssh.Session, configPath string) {
+	pty, windows, ok := sess.Pty()
+	if !ok {
+		fmt.Fprintf(sess.Stderr(), "menuworks needs a terminal; connect with ssh -t\n")
+		sess.Exit(1)
+		return
+	}
+
+	// The screen closes its tty when the menu exits; hide the session's Close
+	// so the exit status can still be sent afterwards
+	tty := ui.NewSessionTty(struct{ io.ReadWriter }{sess}, pty.Window.Width, pty.Window.Height)
+	go func() {
+		for w := range windows {
+			tty.Resize(w.Width, w.Height)
+		}
+	}()
+	backend, err := ui.NewSessionBackend(tty, pty.Term)
+	if err != nil {
+		fmt.Fprintf(sess.Stderr(), "Error: terminal %q: %v\n", pty.Term, err)
+		sess.Exit(1)
+		return
+	}
+
+	logging.Info("ssh session started", "user", sess.User(), "remote", sess.RemoteAddr())
+	a := menuworks.NewAppFromFile(configPath, true)
+	a.Backend = backend
+	a.Remote = true
+	a.Version = currentBuildInfo().Short()
+	if err := a.Run(sess.Context()); err != nil {
+		logging.Error("ssh session failed", "user", sess.User(), "err", err)
+		fmt.Fprintf(sess.Stderr(), "Error: %v\n", err)
+		sess.Exit(1)
+		return
+	}
+	logging.Info("ssh session ended", "user", sess.User(), "remote", sess.RemoteAddr())
+	sess.Exit(0)
+}
+
+// readAuthorizedKeys reads the public keys allowed to connect, in the format
+// of OpenSSH's authorized_keys. At least one is required.
+func readAuthorizedKeys(path string) ([]ssh.PublicKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading authorized keys: %w", err)
+	}
+	var keys []ssh.PublicKey
+	for len(data) > 0 {
+		key, _, _, rest, err := gossh.ParseAuthorizedKey(data)
+		if err != nil {
+			break // no more keys
+		}
+		keys = append(keys, key)
+		data = rest
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("no public keys found in %s", path)
+	}
+	return keys, nil
+}
+
+// loadHostKey reads the server's SSH host key, creating an Ed25519 key the
+// first time so clients see the same host key on every start
+func loadHostKey(path string) (ssh.Signer, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		_, key, genErr := ed25519.GenerateKey(rand.Reader)
+		if genErr != nil {
+			return nil, genErr
+		}
+		block, genErr := gossh.MarshalPrivateKey(key, "menuworks host key")
+		if genErr != nil {
+			return nil, genErr
+		}
+		data = pem.EncodeToMemory(block)
+		if err = os.MkdirAll(filepath.Dir(path), 0755); err == nil {
+			err = os.WriteFile(path, data, 0600)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("saving host key: %w", err)
+		}
+		fmt.Fprintf(os.Stderr, "Created SSH host key %s\n", path)
+	} else if err != nil {
+		return nil, fmt.Errorf("reading host key: %w", err)
+	}
+	signer, err := gossh.ParsePrivateKey(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return signer, nil
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/benworks/menuworks/config"
+)
+
+// runThemes handles the "menuworks themes" subcommand.
+// It lists the built-in themes that can be selected with `theme:` in config.yaml.
+func runThemes(args []string) {
+	fs := flag.NewFlagSet("themes", flag.ExitOnError)
+	showColors := fs.Bool("colors", false, "Show the colors defined by each theme")
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: menuworks themes [flags]\n\n")
+		fmt.Fprintf(os.Stderr, "List built-in themes. Select one with `theme: <name>` in config.yaml.\n\n")
+		fmt.Fprintf(os.Stderr, "Flags:\n")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	fmt.Println("Built-in themes:")
+	for _, name := range config.BuiltinThemeNames() {
+		fmt.Printf("  %s\n", name)
+		if !*showColors {
+			continue
+		}
+		theme, _ := config.BuiltinTheme(name)
+		fmt.Printf("      background: %-9s text: %-9s border: %s\n", theme.Background, theme.Text, theme.Border)
+		fmt.Printf("      highlight_bg: %-7s highlight_fg: %-7s hotkey: %s\n", theme.HighlightBg, theme.HighlightFg, theme.Hotkey)
+		fmt.Printf("      shadow: %-13s disabled: %-11s menu_bg: %s\n", theme.Shadow, theme.Disabled, theme.MenuBg)
+		if theme.Success != "" || theme.Failure != "" {
+			fmt.Printf("      success: %-12s failure: %s\n", theme.Success, theme.Failure)
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"runtime"
+	"runtime/debug"
+	"strings"
+)
+
+// commit and buildDate are injected at build time alongside version:
+// -ldflags "-X main.commit=<sha> -X main.buildDate=<RFC 3339 time>"
+var (
+	commit    string
+	buildDate string
+)
+
+// buildInfo describes the running binary
+type buildInfo struct {
+	Version   string
+	Commit    string
+	BuildDate string
+	GoVersion string
+	Platform  string
+}
+
+// currentBuildInfo returns the injected build details, falling back to the
+// VCS stamp Go records for `go build` in a checkout when ldflags were not used
+func currentBuildInfo() buildInfo {
+	info := buildInfo{
+		Version:   version,
+		Commit:    commit,
+		BuildDate: buildDate,
+		GoVersion: runtime.Version(),
+		Platform:  runtime.GOOS + "/" + runtime.GOARCH,
+	}
+	if bi, ok := debug.ReadBuildInfo(); ok {
+		for _, setting := range bi.Settings {
+			switch {
+			case setting.Key == "vcs.revision" && info.Commit == "":
+				info.Commit = setting.Value
+			case setting.Key == "vcs.time" && info.BuildDate == "":
+				info.BuildDate = setting.Value
+			}
+		}
+	}
+	if info.Version == "" {
+		info.Version = "dev"
+	}
+	return info
+}
+
+// Short returns the version with abbreviated commit and date, e.g. "1.2.0 (3f2a9c1, 2024-05-01)"
+func (b buildInfo) Short() string {
+	var details []string
+	if b.Commit != "" {
+		c := b.Commit
+		if len(c) > 7 {
+			c = c[:7]
+		}
+		details = append(details, c)
+	}
+	if b.BuildDate != "" {
+		d := b.BuildDate
+		if len(d) > 10 {
+			d = d[:10]
+		}
+		details = append(details, d)
+	}
+	if len(details) == 0 {
+		return b.Version
+	}
+	return fmt.Sprintf("%s (%s)", b.Version, strings.Join(details, ", "))
+}
+
+// runVersion handles the "menuworks version" subcommand
+func runVersion(args []string) {
+	fs := flag.NewFlagSet("version", flag.ExitOnError)
+	shortFlag := fs.Bool("short", false, "Print only the one-line version shown on the splash screen")
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: menuworks version [flags]\n\n")
+		fmt.Fprintf(os.Stderr, "Print version, commit, build date, Go version, and platform.\n\n")
+		fmt.Fprintf(os.Stderr, "Flags:\n")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	info := currentBuildInfo()
+	if *shortFlag {
+		fmt.Println(info.Short())
+		return
+	}
+
+	unknown := func(s string) string {
+		if s == "" {
+			return "unknown"
+		}
+		return s
+	}
+	fmt.Printf("menuworks %s\n", info.Version)
+	fmt.Printf("  commit:     %s\n", unknown(info.Commit))
+	fmt.Printf("  built:      %s\n", unknown(info.BuildDate))
+	fmt.Printf("  go:         %s\n", info.GoVersion)
+	fmt.Printf("  platform:   %s\n", info.Platform)
+}
@@ -0,0 +1,247 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"time"
+
+	"github.com/benworks/menuworks/config"
+	"github.com/benworks/menuworks/discover"
+	discoverwin "github.com/benworks/menuworks/discover/windows"
+)
+
+// runWatch handles the "menuworks watch" subcommand. It regenerates a config
+// the way generate does, then keeps it up to date as applications are
+// installed or removed, until interrupted. A menu running with auto_reload
+// picks up each new version.
+func runWatch(args []string) {
+	fs := flag.NewFlagSet("watch", flag.ExitOnError)
+	w := &configWatcher{}
+	fs.StringVar(&w.output, "output", "config.yaml", "Config file to keep up to date (replaced only if menuworks generated it)")
+	fs.StringVar(&w.sources, "sources", "", "Comma-separated list of sources (default: all available)")
+	fs.StringVar(&w.categories, "categories", "", "Comma-separated source=category pairs that put a source's apps in another category, e.g. programfiles=Tools")
+	fs.StringVar(&w.base, "base", "", "Base config file to merge discovered apps into (base takes priority); it is watched too")
+	prefer := fs.String("prefer", "", "Which side wins merge conflicts with --base: base or generated, for every section or per section (e.g. generated,menus=base)")
+	w.orderFlags = addOrderFlags(fs)
+	fs.BoolVar(&w.showOutput, "show-output", false, "Leave generated items to show the output dialog when they finish (default: showOutput: false for all but terminal programs)")
+	fs.BoolVar(&w.hotkeys, "hotkeys", false, "Write a hotkey, unique within its menu, into each generated item instead of leaving them to runtime auto-assignment")
+	fs.BoolVar(&w.details, "details", false, "Add each application's version, publisher, install date, and size to its help text")
+	fs.BoolVar(&w.icons, "icons", false, "Give each category's submenu item an icon suggested by its source or category name (e.g. 🎮 for Games)")
+	fs.IntVar(&w.minConfidence, "min-confidence", 0, "Skip applications whose discovery confidence (1-100) is below this")
+	fs.DurationVar(&w.timeout, "timeout", discover.DefaultSourceTimeout, "How long to wait for each source before skipping it")
+	interval := fs.Duration("interval", 5*time.Second, "How often to check the watched folders for changes")
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: menuworks watch [flags]\n\n")
+		fmt.Fprintf(os.Stderr, "Generate a config.yaml file like generate, then regenerate it whenever\n")
+		fmt.Fprintf(os.Stderr, "applications are installed or removed. A menu started with auto_reload: true\n")
+		fmt.Fprintf(os.Stderr, "in its config reloads each new version.\n\n")
+		fmt.Fprintf(os.Stderr, "Flags:\n")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	var err error
+	if w.mergeOpts, err = discover.ParseMergeOptions(*prefer); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: --prefer: %v\n", err)
+		os.Exit(2)
+	}
+	if *interval <= 0 {
+		fmt.Fprintf(os.Stderr, "Error: --interval must be positive\n")
+		os.Exit(2)
+	}
+	if w.base != "" && sameFile(w.base, w.output) {
+		fmt.Fprintf(os.Stderr, "Error: --base and --output must be different files\n")
+		os.Exit(2)
+	}
+	if err := checkGeneratedOutput(w.output); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	if err := w.regenerate(ctx); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	if cfg, _, err := config.Load(w.output); err == nil && !cfg.IsAutoReloadEnabled() {
+		fmt.Fprintf(os.Stderr, "Note: set auto_reload: true in the config (or --base) so a running menu reloads each change.\n")
+	}
+
+	paths := w.registry.WatchPaths(w.sourceNames())
+	fmt.Fprintf(os.Stderr, "Watching %d folders for changes (Ctrl+C to stop)\n", len(paths))
+	discover.Watch(ctx, *interval, w.fingerprint, func() {
+		fmt.Fprintf(os.Stderr, "%s: change detected\n", time.Now().Format("15:04:05"))
+		if err := w.regenerate(ctx); err != nil && ctx.Err() == nil {
+			fmt.Fprintf(os.Stderr, "  Warning: %v; keeping the previous config\n", err)
+		}
+	})
+}
+
+// configWatcher holds the watch flags and the registry of the last run.
+type configWatcher struct {
+	output        string
+	sources       string
+	categories    string
+	base          string
+	mergeOpts     discover.MergeOptions
+	orderFlags    orderFlags
+	showOutput    bool
+	hotkeys       bool
+	details       bool
+	icons         bool
+	minConfidence int
+	timeout       time.Duration
+
+	// registry is rebuilt on every run, since the base config's discover:
+	// block decides its options and custom directories
+	registry *discover.Registry
+}
+
+// sourceNames returns the --sources filter as a list.
+func (w *configWatcher) sourceNames() []string {
+	return splitList(w.sources)
+}
+
+// fingerprint summarizes the watched folders and the base config, changing
+// whenever a run might produce a different config.
+func (w *configWatcher) fingerprint() string {
+	fp := discover.Fingerprint(w.registry.WatchPaths(w.sourceNames()))
+	if w.base != "" {
+		if info, err := os.Stat(w.base); err == nil {
+			fp += fmt.Sprintf("|%d|%d", info.Size(), info.ModTime().UnixNano())
+		}
+	}
+	return fp
+}
+
+// regenerate runs discovery and writes the output config. The file is only
+// replaced when the config itself changed, so a running menu does not reload
+// for a new provenance timestamp alone.
+func (w *configWatcher) regenerate(ctx context.Context) error {
+	registry := newRegistry()
+	if err := applyCategories(registry, w.categories); err != nil {
+		return fmt.Errorf("--categories: %w", err)
+	}
+	w.registry = registry
+
+	var baseYAML []byte
+	var order discover.Ordering
+	if w.base != "" {
+		var err error
+		if baseYAML, err = os.ReadFile(w.base); err != nil {
+			return fmt.Errorf("reading base config: %w", err)
+		}
+		if discoverCfg, err := discover.ParseDiscoverConfig(baseYAML); err == nil {
+			registry.Configure(discoverCfg)
+			order = discoverCfg.Order
+			discoverwin.RegisterCustomDirs(registry, discoverCfg.Dirs)
+		}
+	}
+	order, err := w.orderFlags.apply(order)
+	if err != nil {
+		return err
+	}
+
+	registry.SetTimeout(w.timeout)
+	results, err := registry.DiscoverAll(ctx, w.sourceNames())
+	if err != nil {
+		return err
+	}
+	for _, r := range results {
+		if errors.Is(r.Err, discover.ErrSourceTimeout) {
+			fmt.Fprintf(os.Stderr, "  Warning: %s: %v, skipped (use --timeout to wait longer)\n", r.Source, r.Err)
+		} else if r.Err != nil {
+			fmt.Fprintf(os.Stderr, "  Warning: %s: %v\n", r.Source, r.Err)
+		}
+	}
+	apps := discover.DeduplicateApps(discover.CollectApps(results))
+	if w.minConfidence > 0 {
+		apps = discover.FilterByConfidence(apps, w.minConfidence)
+	}
+	if w.details {
+		apps = discover.AppendDetails(apps)
+	}
+	if w.icons {
+		apps = registry.SuggestIcons(apps)
+	}
+	if !w.showOutput {
+		apps = discover.HideLauncherOutput(apps)
+	}
+
+	prov := discover.NewProvenance("watch", currentBuildInfo().Short(), results, len(apps))
+	data, err := renderGenerated(baseYAML, apps, order, w.mergeOpts, w.hotkeys, prov)
+	if err != nil {
+		return err
+	}
+	body := data[len(prov.Header()):]
+	if existing, err := os.ReadFile(w.output); err == nil && bytes.HasSuffix(existing, body) {
+		if _, ok := discover.ParseProvenance(existing); ok {
+			fmt.Fprintf(os.Stderr, "  %d applications, config unchanged\n", len(apps))
+			return nil
+		}
+	}
+	if err := checkGeneratedOutput(w.output); err != nil {
+		return err
+	}
+	if err := writeFileAtomic(w.output, data); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "  %d applications, config written to %s\n", len(apps), w.output)
+	return nil
+}
+
+// checkGeneratedOutput returns an error if path holds a config menuworks did
+// not generate, which watch must not overwrite. A missing file is fine.
+func checkGeneratedOutput(path string) error {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("checking output file: %w", err)
+	}
+	if _, ok := discover.ParseProvenance(data); !ok {
+		return fmt.Errorf("%s was not generated by menuworks and will not be overwritten; keep your own settings in a --base config instead", path)
+	}
+	return nil
+}
+
+// writeFileAtomic replaces path with data, so a menu reloading it never
+// reads a half-written config.
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".menuworks-watch-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	_, err = tmp.Write(data)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Chmod(tmp.Name(), 0644)
+	}
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// sameFile reports whether a and b name the same file.
+func sameFile(a, b string) bool {
+	infoA, errA := os.Stat(a)
+	infoB, errB := os.Stat(b)
+	if errA == nil && errB == nil {
+		return os.SameFile(infoA, infoB)
+	}
+	absA, _ := filepath.Abs(a)
+	absB, _ := filepath.Abs(b)
+	return absA == absB
+}
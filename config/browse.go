@@ -0,0 +1,108 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/benworks/menuworks/shell"
+)
+
+// browseFileName is the placeholder a browse item's exec uses for the file
+// picked in its file picker: {file}
+const browseFileName = "file"
+
+// BrowseRoot returns the directory a browse item's file picker opens in, with
+// a leading ~/ expanded to the home directory
+func (item MenuItem) BrowseRoot() string {
+	path := strings.TrimSpace(item.Path)
+	if rest, ok := strings.CutPrefix(path, "~/"); ok {
+		if home, err := os.UserHomeDir(); err == nil {
+			path = filepath.Join(home, rest)
+		}
+	}
+	return path
+}
+
+// ListsFile reports whether a browse item's file picker shows the file called
+// name: every file if the item has no extensions, otherwise the files with one
+// of them, in any case
+func (item MenuItem) ListsFile(name string) bool {
+	if len(item.Extensions) == 0 {
+		return true
+	}
+	ext := filepath.Ext(name)
+	for _, want := range item.Extensions {
+		if strings.EqualFold(ext, "."+strings.TrimPrefix(want, ".")) {
+			return true
+		}
+	}
+	return false
+}
+
+// WithFile returns the command item a browse item runs on file. {file} in a
+// command line becomes the path quoted for that OS's shell; in a program's
+// arguments it becomes the path as is.
+func (item MenuItem) WithFile(file string) MenuItem {
+	command := item
+	command.Type = "command"
+	command.Exec = ExecConfig{WorkDir: item.Exec.WorkDir}
+	for _, key := range []string{"windows", "linux", "mac"} {
+		if argv := item.Exec.Argv[key]; argv != nil {
+			filled := make([]string, len(argv))
+			for i, arg := range argv {
+				filled[i] = fillFile(arg, file)
+			}
+			command.Exec.setArgv(key, filled)
+			continue
+		}
+		if line := *item.Exec.variant(key); line != "" {
+			*command.Exec.variant(key) = fillFile(line, shell.Quote(key, []string{file}))
+		}
+	}
+	return command
+}
+
+// fillFile replaces {file} in s with value, leaving ${file} to the shell
+func fillFile(s, value string) string {
+	return placeholder.ReplaceAllStringFunc(s, func(m string) string {
+		if m != "{"+browseFileName+"}" {
+			return m
+		}
+		return value
+	})
+}
+
+// usesFile reports whether s has a {file} placeholder
+func usesFile(s string) bool {
+	return fillFile(s, "") != s
+}
+
+// validateBrowse checks a browse item's path, exec, and extensions
+func validateBrowse(item MenuItem, index int) []string {
+	var errs []string
+	if item.Label == "" {
+		errs = append(errs, fmt.Sprintf("item %d: browse missing label", index))
+	}
+	if strings.TrimSpace(item.Path) == "" {
+		errs = append(errs, fmt.Sprintf("item %d: browse missing path", index))
+	}
+	if item.Exec.Windows == "" && item.Exec.Linux == "" && item.Exec.Mac == "" {
+		errs = append(errs, fmt.Sprintf("item %d: browse missing exec variant (windows, linux, or mac)", index))
+	}
+	for _, key := range []string{"windows", "linux", "mac"} {
+		if line := *item.Exec.variant(key); line != "" && !usesFile(line) {
+			errs = append(errs, fmt.Sprintf("item %d: browse exec.%s doesn't use {file}", index, key))
+		}
+	}
+	for _, ext := range item.Extensions {
+		if strings.TrimPrefix(ext, ".") == "" || strings.ContainsAny(ext, `/\ `) {
+			errs = append(errs, fmt.Sprintf("item %d: invalid extension '%s' (expected a file extension such as .nes)", index, ext))
+		}
+	}
+	if item.Launch != "" && !isLaunchMode(item.Launch) {
+		errs = append(errs, fmt.Sprintf("item %d: invalid launch mode '%s' (expected %s)", index, item.Launch, strings.Join(LaunchModes, ", ")))
+	}
+	return errs
+}
@@ -0,0 +1,65 @@
+package config
+
+import "sort"
+
+// Changes summarizes how a reloaded config differs from the one it replaces
+type Changes struct {
+	MenusAdded   []string
+	MenusRemoved []string
+	Items        []ItemCountChange // menus kept whose number of items changed, root first
+	Warnings     []string          // validation warnings for the new config
+}
+
+// ItemCountChange is a menu whose number of items changed. Menu is "" for
+// the root menu.
+type ItemCountChange struct {
+	Menu          string
+	Before, After int
+}
+
+// CompareConfigs returns the differences between old and new, with new's
+// validation warnings
+func CompareConfigs(old, new *Config) Changes {
+	var c Changes
+	if before, after := countItems(old.Items), countItems(new.Items); before != after {
+		c.Items = append(c.Items, ItemCountChange{"", before, after})
+	}
+	for _, name := range sortedMenuNames(new) {
+		oldMenu, ok := old.Menus[name]
+		if !ok {
+			c.MenusAdded = append(c.MenusAdded, name)
+			continue
+		}
+		if before, after := countItems(oldMenu.Items), countItems(new.Menus[name].Items); before != after {
+			c.Items = append(c.Items, ItemCountChange{name, before, after})
+		}
+	}
+	for _, name := range sortedMenuNames(old) {
+		if _, ok := new.Menus[name]; !ok {
+			c.MenusRemoved = append(c.MenusRemoved, name)
+		}
+	}
+	c.Warnings = Validate(new)
+	return c
+}
+
+// countItems returns the number of items other than separators
+func countItems(items []MenuItem) int {
+	n := 0
+	for _, item := range items {
+		if item.Type != "separator" {
+			n++
+		}
+	}
+	return n
+}
+
+// sortedMenuNames returns the names of cfg's menus in order
+func sortedMenuNames(cfg *Config) []string {
+	names := make([]string, 0, len(cfg.Menus))
+	for name := range cfg.Menus {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
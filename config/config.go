@@ -3,12 +3,25 @@ package config
 import (
 	_ "embed"
 	"fmt"
+	"io"
+	"net/url"
 	"os"
 	"path/filepath"
+	"runtime"
+	"slices"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
+	"unicode"
 
 	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/uniseg"
 	"gopkg.in/yaml.v3"
+
+	"github.com/benworks/menuworks/i18n"
+	"github.com/benworks/menuworks/logging"
+	"github.com/benworks/menuworks/shell"
 )
 
 //go:embed config.yaml
@@ -16,13 +29,151 @@ var defaultConfigYAML string
 
 // MenuItem represents a single item in a menu
 type MenuItem struct {
-	Type       string      `yaml:"type"`   // command, submenu, back, separator
-	Label      string      `yaml:"label"`
-	Hotkey     string      `yaml:"hotkey,omitempty"`
-	Target     string      `yaml:"target,omitempty"`     // for submenu type
-	Exec       ExecConfig  `yaml:"exec,omitempty"`       // for command type
-	ShowOutput *bool       `yaml:"showOutput,omitempty"` // for command type (default: true)
-	Help       string      `yaml:"help,omitempty"`       // for command type (optional help text)
+	Type           string                  `yaml:"type"`          // command, parallel, submenu, back, quit, separator, group
+	ID             string                  `yaml:"id,omitempty"`  // optional stable identifier; other items can reference it
+	Ref            string                  `yaml:"ref,omitempty"` // id of another item to copy (resolved at load time)
+	Label          string                  `yaml:"label"`
+	Hotkey         string                  `yaml:"hotkey,omitempty"`
+	Icon           string                  `yaml:"icon,omitempty"`            // one character or emoji drawn before the label; the theme's icons give defaults per type
+	Target         string                  `yaml:"target,omitempty"`          // for submenu type
+	Exec           ExecConfig              `yaml:"exec,omitempty"`            // for command type
+	URL            string                  `yaml:"url,omitempty"`             // for url type: link opened with the OS's handler for its scheme
+	Path           string                  `yaml:"path,omitempty"`            // for browse type: directory the file picker opens in and stays within
+	Extensions     []string                `yaml:"extensions,omitempty"`      // for browse type: only list files with these extensions (".nes", ".sfc")
+	ShowOutput     *bool                   `yaml:"showOutput,omitempty"`      // for command type (default: true)
+	Help           string                  `yaml:"help,omitempty"`            // for command type (optional help text)
+	OS             []string                `yaml:"os,omitempty"`              // restrict item to these OSes (windows, linux, mac)
+	Overrides      map[string]ItemOverride `yaml:"overrides,omitempty"`       // per-OS field overrides keyed by OS
+	Launch         string                  `yaml:"launch,omitempty"`          // for command type: where to run it (see LaunchModes)
+	Webhook        string                  `yaml:"webhook,omitempty"`         // for command type: URL notified on start/finish, or "none"
+	Collapsed      bool                    `yaml:"collapsed,omitempty"`       // for group type: start with the group's items hidden
+	Stdin          string                  `yaml:"stdin,omitempty"`           // for command type: text, "file:<path>", or "prompt" piped into the command
+	Commands       []MenuItem              `yaml:"commands,omitempty"`        // for parallel type: command items run at the same time
+	RunAs          string                  `yaml:"run_as,omitempty"`          // for command type: user to run the command as, through sudo (Unix only)
+	Template       string                  `yaml:"template,omitempty"`        // name of a template to fill in exec from (resolved at load time)
+	Args           map[string]string       `yaml:"args,omitempty"`            // values for the template's {placeholders}
+	Confirm        *bool                   `yaml:"confirm,omitempty"`         // for command, parallel, and quit types: ask before going ahead
+	Timeout        string                  `yaml:"timeout,omitempty"`         // for command and parallel types: stop the command after this long ("30s", "5m")
+	Disabled       bool                    `yaml:"disabled,omitempty"`        // show the item greyed out; selecting it shows DisabledReason
+	DisabledReason string                  `yaml:"disabled_reason,omitempty"` // why the item is disabled ("Coming soon", "Requires VPN")
+	Badge          string                  `yaml:"badge,omitempty"`           // short text drawn at the right end of the row
+	BadgeExec      ExecConfig              `yaml:"badge_exec,omitempty"`      // quick command whose first line of output replaces badge
+	AvailableHours string                  `yaml:"available_hours,omitempty"` // times the item can be selected ("16:00-20:00", "08:00-12:00, 22:00-02:00")
+	AvailableDays  string                  `yaml:"available_days,omitempty"`  // days the item can be selected ("mon-fri", "sat, sun")
+	Roles          []string                `yaml:"roles,omitempty"`           // show the item only to these roles (default: every role)
+
+	srcIndex int // position of the item in its menu in the config file (before OS filtering)
+}
+
+// ItemOverride holds per-OS replacements for item fields.
+// Empty fields leave the base item value unchanged.
+type ItemOverride struct {
+	Label   string `yaml:"label,omitempty"`
+	Hotkey  string `yaml:"hotkey,omitempty"`
+	Help    string `yaml:"help,omitempty"`
+	WorkDir string `yaml:"workdir,omitempty"`
+	Hidden  bool   `yaml:"hidden,omitempty"`
+}
+
+// osKey maps a runtime OS type to the key used in config files ("darwin" -> "mac")
+func osKey(osType string) string {
+	switch strings.ToLower(osType) {
+	case "darwin", "mac", "macos":
+		return "mac"
+	default:
+		return strings.ToLower(osType)
+	}
+}
+
+// isKnownOSKey reports whether key is an OS identifier accepted in config files
+func isKnownOSKey(key string) bool {
+	switch osKey(key) {
+	case "windows", "linux", "mac":
+		return true
+	}
+	return false
+}
+
+// VisibleOn returns true if the item should be shown on the given OS.
+// Items with no os filter are visible everywhere; an override with hidden: true hides the item.
+func (item MenuItem) VisibleOn(osType string) bool {
+	key := osKey(osType)
+	if len(item.OS) > 0 {
+		found := false
+		for _, o := range item.OS {
+			if osKey(o) == key {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	for k, ov := range item.Overrides {
+		if osKey(k) == key && ov.Hidden {
+			return false
+		}
+	}
+	return true
+}
+
+// ForOS returns a copy of the item with any override for the given OS applied
+func (item MenuItem) ForOS(osType string) MenuItem {
+	key := osKey(osType)
+	for k, ov := range item.Overrides {
+		if osKey(k) != key {
+			continue
+		}
+		if ov.Label != "" {
+			item.Label = ov.Label
+		}
+		if ov.Hotkey != "" {
+			item.Hotkey = ov.Hotkey
+		}
+		if ov.Help != "" {
+			item.Help = ov.Help
+		}
+		if ov.WorkDir != "" {
+			item.Exec.WorkDir = ov.WorkDir
+		}
+	}
+	return item
+}
+
+// CommandsForOS returns the commands of a parallel item that have a command for osType
+func (item MenuItem) CommandsForOS(osType string) []MenuItem {
+	var commands []MenuItem
+	for _, c := range item.Commands {
+		if c.Exec.CommandForOS(osType) != "" {
+			commands = append(commands, c)
+		}
+	}
+	return commands
+}
+
+// Key returns a stable identity for the item within its menu: its id if set,
+// otherwise a slug of its label (e.g. "Portal 2" -> "portal-2"). Unlike the
+// item's position it survives reordering, so remembered selections and
+// disabled state are keyed by it. Derived keys are not valid for ref or run.
+func (item MenuItem) Key() string {
+	if item.ID != "" {
+		return item.ID
+	}
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(item.Label) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if dash && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			b.WriteRune(r)
+			dash = false
+		} else {
+			dash = true
+		}
+	}
+	return b.String()
 }
 
 // ExecConfig holds command execution details with OS-specific variants
@@ -31,6 +182,103 @@ type ExecConfig struct {
 	Linux   string `yaml:"linux,omitempty"`
 	Mac     string `yaml:"mac,omitempty"`
 	WorkDir string `yaml:"workdir,omitempty"`
+
+	// Argv holds the variants given as a program and its arguments instead of
+	// a command line, keyed by OS ("windows", "linux", "mac"). They run without
+	// a shell; the matching string field holds them quoted as a command line.
+	Argv map[string][]string `yaml:"-"`
+}
+
+// execProgram is the structured form of an exec variant
+type execProgram struct {
+	Program string   `yaml:"program"`
+	Args    []string `yaml:"args,omitempty"`
+}
+
+// UnmarshalYAML accepts each OS variant either as a command line or as a
+// program with its arguments (program: and args:)
+func (ec *ExecConfig) UnmarshalYAML(value *yaml.Node) error {
+	var raw struct {
+		Windows yaml.Node `yaml:"windows"`
+		Linux   yaml.Node `yaml:"linux"`
+		Mac     yaml.Node `yaml:"mac"`
+		WorkDir string    `yaml:"workdir"`
+	}
+	if err := value.Decode(&raw); err != nil {
+		return err
+	}
+	*ec = ExecConfig{WorkDir: raw.WorkDir}
+	variants := []struct {
+		key  string
+		node *yaml.Node
+		line *string
+	}{
+		{"windows", &raw.Windows, &ec.Windows},
+		{"linux", &raw.Linux, &ec.Linux},
+		{"mac", &raw.Mac, &ec.Mac},
+	}
+	for _, v := range variants {
+		if v.node.IsZero() {
+			continue
+		}
+		if v.node.Kind != yaml.MappingNode {
+			if err := v.node.Decode(v.line); err != nil {
+				return err
+			}
+			continue
+		}
+		var p execProgram
+		if err := v.node.Decode(&p); err != nil {
+			return err
+		}
+		if p.Program == "" {
+			return fmt.Errorf("line %d: exec.%s: program is required", v.node.Line, v.key)
+		}
+		ec.setArgv(v.key, append([]string{p.Program}, p.Args...))
+	}
+	return nil
+}
+
+// setArgv sets the variant for key ("windows", "linux", "mac") to run argv
+// without a shell, with its command line quoted for that OS
+func (ec *ExecConfig) setArgv(key string, argv []string) {
+	if ec.Argv == nil {
+		ec.Argv = make(map[string][]string)
+	}
+	ec.Argv[key] = argv
+	goos := key
+	if key == "mac" {
+		goos = "darwin"
+	}
+	*ec.variant(key) = shell.Quote(goos, argv)
+}
+
+// variant returns the command line field for key ("windows", "linux", "mac")
+func (ec *ExecConfig) variant(key string) *string {
+	switch key {
+	case "windows":
+		return &ec.Windows
+	case "linux":
+		return &ec.Linux
+	default:
+		return &ec.Mac
+	}
+}
+
+// ArgvForOS returns the program and arguments to run without a shell on the
+// given OS, or nil if the variant is a command line (or there is none)
+func (ec ExecConfig) ArgvForOS(osType string) []string {
+	return ec.Argv[osKey(osType)]
+}
+
+// ArgvForOS returns the program and arguments the item runs without a shell
+// on the given OS, or nil to run its command line. Items with run_as always
+// run the command line, which sudo's shell splits back into the same arguments.
+func (item MenuItem) ArgvForOS(osType string) []string {
+	if item.RunAs != "" {
+		return nil
+	}
+	return item.Exec.ArgvForOS(osType)
 }
 
 // CommandForOS returns the command for the given OS, or empty string if not defined
@@ -47,10 +295,104 @@ func (ec ExecConfig) CommandForOS(osType string) string {
 	}
 }
 
+// HasBadgeCommand reports whether the item sets badge_exec for any OS
+func (item MenuItem) HasBadgeCommand() bool {
+	return item.BadgeExec.Windows != "" || item.BadgeExec.Linux != "" || item.BadgeExec.Mac != ""
+}
+
 // Menu represents a menu with a title and list of items
 type Menu struct {
-	Title string      `yaml:"title"`
-	Items []MenuItem  `yaml:"items"`
+	Title     string        `yaml:"title"`
+	Items     []MenuItem    `yaml:"items"`
+	Footer    string        `yaml:"footer,omitempty"`     // hint shown before the key bindings
+	Defaults  *ItemDefaults `yaml:"defaults,omitempty"`   // settings for this menu's items, over the config-wide defaults
+	EmptyText string        `yaml:"empty_text,omitempty"` // shown when the menu has no items (default: "(No items)")
+	Lock      *MenuLock     `yaml:"lock,omitempty"`       // sign-in required before the menu opens
+}
+
+// MenuLock asks for an account's credentials before a menu opens. Once
+// opened, the menu stays unlocked until MenuWorks exits.
+type MenuLock struct {
+	Auth  string   `yaml:"auth"`            // "os": the password of an OS account (PAM, LogonUser)
+	Users []string `yaml:"users,omitempty"` // accounts that may open the menu (default: the user running MenuWorks)
+}
+
+// Role is an audience that items can be limited to with their roles list,
+// such as "kids" and "parent" on a shared PC
+type Role struct {
+	Label string `yaml:"label,omitempty"` // name shown in the role switcher (default: the role's key)
+	PIN   string `yaml:"pin,omitempty"`   // asked for before switch_role changes to this role; may be a secret://name reference
+}
+
+// RoleNames returns the names of the configured roles in order
+func (c *Config) RoleNames() []string {
+	names := make([]string, 0, len(c.Roles))
+	for name := range c.Roles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// RoleLabel returns the name shown for the role called name
+func (c *Config) RoleLabel(name string) string {
+	if r, ok := c.Roles[name]; ok && r.Label != "" {
+		return r.Label
+	}
+	return name
+}
+
+// VisibleTo reports whether the item is shown to role: items without roles
+// are shown to everyone
+func (item MenuItem) VisibleTo(role string) bool {
+	return len(item.Roles) == 0 || slices.Contains(item.Roles, role)
+}
+
+// Walk calls fn for every item reachable from the root menu, depth-first in
+// display order: each submenu item is followed by the items of the menu it
+// opens. path holds the labels of the submenus leading to the item's menu
+// (empty for the root menu); copy it before keeping it. Submenus whose target
+// is missing, or already open on the current branch, are visited but not
+// expanded. Items are visited as configured, including separators and items
+// that are disabled at runtime.
+func (c *Config) Walk(fn func(path []string, item MenuItem)) {
+	c.walk(c.Items, nil, map[string]bool{}, fn)
+}
+
+// OpenMenus returns the names of the menus that can be reached from the root
+// menu without opening a menu that has a lock
+func (c *Config) OpenMenus() map[string]bool {
+	open := map[string]bool{}
+	var visit func(items []MenuItem)
+	visit = func(items []MenuItem) {
+		for _, item := range items {
+			menu, exists := c.Menus[item.Target]
+			if item.Type != "submenu" || !exists || menu.Lock != nil || open[item.Target] {
+				continue
+			}
+			open[item.Target] = true
+			visit(menu.Items)
+		}
+	}
+	visit(c.Items)
+	return open
+}
+
+// walk visits items and their submenus; open holds the menus on the current branch
+func (c *Config) walk(items []MenuItem, path []string, open map[string]bool, fn func(path []string, item MenuItem)) {
+	for _, item := range items {
+		fn(path[:len(path):len(path)], item)
+		if item.Type != "submenu" || open[item.Target] {
+			continue
+		}
+		menu, exists := c.Menus[item.Target]
+		if !exists {
+			continue
+		}
+		open[item.Target] = true
+		c.walk(menu.Items, append(path[:len(path):len(path)], item.Label), open, fn)
+		delete(open, item.Target)
+	}
 }
 
 // ThemeColors defines the color scheme for the UI
@@ -64,64 +406,1105 @@ type ThemeColors struct {
 	Shadow      string `yaml:"shadow"`
 	Disabled    string `yaml:"disabled"`
 	MenuBg      string `yaml:"menu_bg,omitempty"`
+	Success     string `yaml:"success,omitempty"` // status of a command that succeeded
+	Failure     string `yaml:"failure,omitempty"` // status of a command that failed
+	Extends     string `yaml:"extends,omitempty"` // name of a theme to inherit unset colors from
+
+	// Style attributes ("true"/"false"; empty means unset)
+	TitleBold       string `yaml:"title_bold,omitempty"`
+	SelectedReverse string `yaml:"selected_reverse,omitempty"`
+	HotkeyUnderline string `yaml:"hotkey_underline,omitempty"`
+	SeparatorDim    string `yaml:"separator_dim,omitempty"`
+
+	// Icons are drawn before the labels of items of each type (e.g.
+	// "submenu": "▸") that set no icon of their own
+	Icons map[string]string `yaml:"icons,omitempty"`
+}
+
+// inheritFrom returns a copy of t with every unset field taken from parent
+func (t ThemeColors) inheritFrom(parent ThemeColors) ThemeColors {
+	fill := func(child *string, inherited string) {
+		if *child == "" {
+			*child = inherited
+		}
+	}
+	fill(&t.Background, parent.Background)
+	fill(&t.Text, parent.Text)
+	fill(&t.Border, parent.Border)
+	fill(&t.HighlightBg, parent.HighlightBg)
+	fill(&t.HighlightFg, parent.HighlightFg)
+	fill(&t.Hotkey, parent.Hotkey)
+	fill(&t.Shadow, parent.Shadow)
+	fill(&t.Disabled, parent.Disabled)
+	fill(&t.MenuBg, parent.MenuBg)
+	fill(&t.Success, parent.Success)
+	fill(&t.Failure, parent.Failure)
+	fill(&t.TitleBold, parent.TitleBold)
+	fill(&t.SelectedReverse, parent.SelectedReverse)
+	fill(&t.HotkeyUnderline, parent.HotkeyUnderline)
+	fill(&t.SeparatorDim, parent.SeparatorDim)
+	if len(parent.Icons) > 0 {
+		icons := make(map[string]string, len(parent.Icons)+len(t.Icons))
+		for itemType, icon := range parent.Icons {
+			icons[itemType] = icon
+		}
+		for itemType, icon := range t.Icons {
+			icons[itemType] = icon
+		}
+		t.Icons = icons
+	}
+	t.Extends = ""
+	return t
 }
 
 // Config is the root configuration structure
 type Config struct {
-	Title        string               `yaml:"title"`
-	Items        []MenuItem           `yaml:"items"`
-	Menus        map[string]Menu      `yaml:"menus"`
-	Theme        string               `yaml:"theme,omitempty"`
-	Themes       map[string]ThemeColors `yaml:"themes,omitempty"`
-	MouseSupport *bool                `yaml:"mouse_support,omitempty"`
-	InitialMenu  string               `yaml:"initial_menu,omitempty"`
-	SplashScreen *bool                `yaml:"splash_screen,omitempty"`
+	Title                 string                 `yaml:"title"`
+	Items                 []MenuItem             `yaml:"items"`
+	Menus                 map[string]Menu        `yaml:"menus"`
+	Theme                 string                 `yaml:"theme,omitempty"`
+	Themes                map[string]ThemeColors `yaml:"themes,omitempty"`
+	MouseSupport          *bool                  `yaml:"mouse_support,omitempty"`
+	InitialMenu           string                 `yaml:"initial_menu,omitempty"`
+	SplashScreen          *bool                  `yaml:"splash_screen,omitempty"`
+	Shadow                *bool                  `yaml:"shadow,omitempty"`
+	TransparentBackground *bool                  `yaml:"transparent_background,omitempty"`
+	TitleBar              *TitleBar              `yaml:"title_bar,omitempty"`
+	Footer                *bool                  `yaml:"footer,omitempty"`
+	FooterHint            string                 `yaml:"footer_hint,omitempty"`  // root menu footer hint
+	EmptyText             string                 `yaml:"empty_text,omitempty"`   // shown when the root menu has no items (default: "(No items)")
+	TitleFormat           string                 `yaml:"title_format,omitempty"` // menu frame title with {title}, {root}, {path}, and {count}
+	BigTitle              string                 `yaml:"big_title,omitempty"`    // where the title is drawn in the block font (see BigTitleModes)
+	Layout                *Layout                `yaml:"layout,omitempty"`
+	WrapNavigation        *bool                  `yaml:"wrap_navigation,omitempty"` // moving past the last item selects the first (default: true)
+	Animations            *bool                  `yaml:"animations,omitempty"`      // wipe transitions between menus (default: true)
+	UpdateCheck           *bool                  `yaml:"update_check,omitempty"`
+	RestorePosition       *bool                  `yaml:"restore_position,omitempty"`
+	AutoReload            *bool                  `yaml:"auto_reload,omitempty"`     // reload when the config file changes on disk
+	SessionTimeout        string                 `yaml:"session_timeout,omitempty"` // end the session after this long without input ("10m")
+	SessionMenu           string                 `yaml:"session_menu,omitempty"`    // menu or menu path a session ends in (default: root)
+	SingleInstance        string                 `yaml:"single_instance,omitempty"` // what a second MenuWorks with this config does (see SingleInstanceModes)
+	Wrapper               *bool                  `yaml:"wrapper,omitempty"`         // stand in for a login shell or desktop: Esc never exits, a crash restarts the menu
+	Roles                 map[string]Role        `yaml:"roles,omitempty"`           // audiences items can be limited to, by name
+	DefaultRole           string                 `yaml:"default_role,omitempty"`    // role MenuWorks starts in without -role or MENUWORKS_ROLE
+	CtrlC                 *CtrlC                 `yaml:"ctrl_c,omitempty"`
+	KeyBindings           *KeyBindings           `yaml:"keybindings,omitempty"`
+	Bell                  *Bell                  `yaml:"bell,omitempty"`
+	Launch                string                 `yaml:"launch,omitempty"`  // default launch mode for commands
+	Webhook               string                 `yaml:"webhook,omitempty"` // URL notified when any command starts and finishes
+	Locale                string                 `yaml:"locale,omitempty"`  // UI language, e.g. "de" (default: from LANG)
+	Accessibility         *Accessibility         `yaml:"accessibility,omitempty"`
+	StatusSymbols         *bool                  `yaml:"status_symbols,omitempty"` // mark command results with ✓/✗ as well as color
+	DisabledNotes         *DisabledNotes         `yaml:"disabled_notes,omitempty"`
+	Templates             map[string]Template    `yaml:"templates,omitempty"` // reusable commands with {placeholders}
+	Defaults              *ItemDefaults          `yaml:"defaults,omitempty"`  // settings for items that don't set their own
+}
+
+// ItemDefaults holds settings applied to command and parallel items that leave
+// them unset, so a menu of similar items needn't repeat them on every one
+type ItemDefaults struct {
+	ShowOutput *bool  `yaml:"showOutput,omitempty"`
+	Launch     string `yaml:"launch,omitempty"` // command items only; parallel items always run inline
+	Confirm    *bool  `yaml:"confirm,omitempty"`
+	Timeout    string `yaml:"timeout,omitempty"`
+}
+
+// over returns d with the settings it leaves unset filled in from base
+func (d *ItemDefaults) over(base *ItemDefaults) *ItemDefaults {
+	if d == nil {
+		return base
+	}
+	if base == nil {
+		return d
+	}
+	merged := *d
+	if merged.ShowOutput == nil {
+		merged.ShowOutput = base.ShowOutput
+	}
+	if merged.Launch == "" {
+		merged.Launch = base.Launch
+	}
+	if merged.Confirm == nil {
+		merged.Confirm = base.Confirm
+	}
+	if merged.Timeout == "" {
+		merged.Timeout = base.Timeout
+	}
+	return &merged
+}
+
+// validateDefaults reports an unknown launch mode or a bad timeout in a defaults block
+func validateDefaults(d *ItemDefaults, where string) []string {
+	if d == nil {
+		return nil
+	}
+	var errs []string
+	if d.Launch != "" && !isLaunchMode(d.Launch) {
+		errs = append(errs, fmt.Sprintf("%s: invalid launch mode '%s' (expected %s)", where, d.Launch, strings.Join(LaunchModes, ", ")))
+	}
+	if d.Timeout != "" && !isTimeout(d.Timeout) {
+		errs = append(errs, fmt.Sprintf("%s: invalid timeout '%s' (expected a duration such as 30s or 5m)", where, d.Timeout))
+	}
+	return errs
+}
+
+// applyDefaults fills in the settings that command, parallel, and browse items
+// leave unset from their menu's defaults, then the config-wide ones. Invalid
+// default values are skipped; Validate reports them once, against the
+// defaults block.
+func applyDefaults(cfg *Config) {
+	fill := func(items []MenuItem, d *ItemDefaults) {
+		if d == nil {
+			return
+		}
+		for i := range items {
+			item := &items[i]
+			if item.Type != "command" && item.Type != "parallel" && item.Type != "browse" {
+				continue
+			}
+			if item.ShowOutput == nil {
+				item.ShowOutput = d.ShowOutput
+			}
+			if item.Confirm == nil && item.Type != "browse" {
+				item.Confirm = d.Confirm
+			}
+			if item.Timeout == "" && isTimeout(d.Timeout) {
+				item.Timeout = d.Timeout
+			}
+			if item.Type != "parallel" && item.Launch == "" && isLaunchMode(d.Launch) {
+				item.Launch = d.Launch
+			}
+		}
+	}
+	fill(cfg.Items, cfg.Defaults)
+	for _, m := range cfg.Menus {
+		fill(m.Items, m.Defaults.over(cfg.Defaults))
+	}
+}
+
+// isTimeout reports whether s is a positive duration such as "30s" or "5m"
+func isTimeout(s string) bool {
+	d, err := time.ParseDuration(s)
+	return err == nil && d > 0
+}
+
+// NeedsConfirm returns true if the item asks before running or quitting (default: false)
+func (item MenuItem) NeedsConfirm() bool {
+	return item.Confirm != nil && *item.Confirm
+}
+
+// TimeoutDuration returns how long the item's command may run, or 0 for no limit
+func (item MenuItem) TimeoutDuration() time.Duration {
+	d, err := time.ParseDuration(item.Timeout)
+	if err != nil || d <= 0 {
+		return 0
+	}
+	return d
+}
+
+// DisabledNotes configures the text shown after unavailable items, saying why
+// they can't be used. Empty texts use the built-in wording.
+type DisabledNotes struct {
+	Enabled       bool   `yaml:"enabled"`
+	NoCommand     string `yaml:"no_command,omitempty"`     // command with no variant for this OS
+	MissingTarget string `yaml:"missing_target,omitempty"` // submenu whose target menu doesn't exist
+}
+
+// IsDisabledNotesEnabled returns true if unavailable items are annotated with
+// the reason (default: false)
+func (c *Config) IsDisabledNotesEnabled() bool {
+	return c.DisabledNotes != nil && c.DisabledNotes.Enabled
+}
+
+// WebhookFor returns the URL to notify about item's executions: its own
+// webhook, then the config-wide one. An item can opt out with "none".
+func (c *Config) WebhookFor(item MenuItem) string {
+	if item.Webhook == "none" {
+		return ""
+	}
+	if item.Webhook != "" {
+		return item.Webhook
+	}
+	return c.Webhook
+}
+
+// BigTitleModes are the accepted big_title values: where the config's title
+// is drawn in a large block font. "off" is the default.
+var BigTitleModes = []string{"off", "splash", "menu", "both"}
+
+// isBigTitleMode reports whether mode is one of BigTitleModes
+func isBigTitleMode(mode string) bool {
+	for _, m := range BigTitleModes {
+		if mode == m {
+			return true
+		}
+	}
+	return false
+}
+
+// BigTitleOnSplash reports whether the splash screen shows the title big
+func (c *Config) BigTitleOnSplash() bool {
+	return c.BigTitle == "splash" || c.BigTitle == "both"
+}
+
+// BigTitleOnMenu reports whether the title is shown big above the menu
+func (c *Config) BigTitleOnMenu() bool {
+	return c.BigTitle == "menu" || c.BigTitle == "both"
+}
+
+// SingleInstanceModes are the accepted single_instance values: what MenuWorks
+// does when another instance is already running with the same config. "off"
+// (the default) starts anyway; "exit" quietly leaves the running one on
+// screen; "refuse" exits with an error saying which process has it.
+var SingleInstanceModes = []string{"off", "exit", "refuse"}
+
+// isSingleInstanceMode reports whether mode is one of SingleInstanceModes
+func isSingleInstanceMode(mode string) bool {
+	for _, m := range SingleInstanceModes {
+		if mode == m {
+			return true
+		}
+	}
+	return false
+}
+
+// SingleInstanceMode returns single_instance, or "off" when it is omitted
+func (c *Config) SingleInstanceMode() string {
+	if c.SingleInstance == "" {
+		return "off"
+	}
+	return c.SingleInstance
+}
+
+// LaunchModes are the accepted launch values. "inline" (the default) runs the
+// command inside MenuWorks and shows its output; "interactive" hands it the
+// terminal until it exits; the others open it in a new terminal window, a tmux
+// split, or a Windows Terminal tab and return to the menu.
+var LaunchModes = []string{"inline", "interactive", "new-window", "tmux-pane", "wt-tab"}
+
+// isLaunchMode reports whether mode is one of LaunchModes
+func isLaunchMode(mode string) bool {
+	for _, m := range LaunchModes {
+		if mode == m {
+			return true
+		}
+	}
+	return false
+}
+
+// LaunchFor returns where item should run: its own launch setting, then the
+// config-wide default, then "inline"
+func (c *Config) LaunchFor(item MenuItem) string {
+	if item.Launch != "" {
+		return item.Launch
+	}
+	if c.Launch != "" {
+		return c.Launch
+	}
+	return "inline"
+}
+
+// StdinPrompt as an item's stdin asks for the input in a dialog each time the item runs
+const StdinPrompt = "prompt"
+
+// stdinFilePrefix marks an item's stdin as the path of a file to read
+const stdinFilePrefix = "file:"
+
+// StdinFile returns the path of the file an item's stdin names ("file:<path>"),
+// with a leading ~/ expanded to the home directory, or "" if it names none
+func (item MenuItem) StdinFile() string {
+	path, ok := strings.CutPrefix(item.Stdin, stdinFilePrefix)
+	if !ok {
+		return ""
+	}
+	path = strings.TrimSpace(path)
+	if rest, ok := strings.CutPrefix(path, "~/"); ok {
+		if home, err := os.UserHomeDir(); err == nil {
+			path = filepath.Join(home, rest)
+		}
+	}
+	return path
+}
+
+// OpenStdin returns the input to pipe into a command item: the file its stdin
+// names, or the stdin text itself. Returns nil when the item has no stdin or
+// uses StdinPrompt, which the caller has to ask for. The caller closes it.
+func (item MenuItem) OpenStdin() (io.ReadCloser, error) {
+	switch {
+	case item.Stdin == "" || item.Stdin == StdinPrompt:
+		return nil, nil
+	case strings.HasPrefix(item.Stdin, stdinFilePrefix):
+		f, err := os.Open(item.StdinFile())
+		if err != nil {
+			return nil, fmt.Errorf("failed to open stdin file: %w", err)
+		}
+		return f, nil
+	default:
+		return io.NopCloser(strings.NewReader(item.Stdin)), nil
+	}
+}
+
+// Accessibility configures the accessibility mode. When enabled, the selection
+// and unavailable items are marked with text as well as color, the cursor
+// follows the selection for screen readers, and the clock is hidden so the
+// header does not change every minute.
+type Accessibility struct {
+	Enabled      bool  `yaml:"enabled"`
+	HighContrast *bool `yaml:"high_contrast,omitempty"` // use the high-contrast theme (default: true)
+	Decorations  *bool `yaml:"decorations,omitempty"`   // keep shadows, box art, and arrow glyphs (default: false)
+}
+
+// IsAccessibilityEnabled returns true if the accessibility mode is on (default: false)
+func (c *Config) IsAccessibilityEnabled() bool {
+	return c.Accessibility != nil && c.Accessibility.Enabled
+}
+
+// IsHighContrastEnabled returns true if the accessibility mode replaces the
+// configured theme with the high-contrast theme
+func (c *Config) IsHighContrastEnabled() bool {
+	if !c.IsAccessibilityEnabled() {
+		return false
+	}
+	return c.Accessibility.HighContrast == nil || *c.Accessibility.HighContrast
+}
+
+// IsDecorationEnabled returns true if purely decorative glyphs (shadows, box
+// borders, arrows) should be drawn; the accessibility mode drops them unless
+// decorations is set
+func (c *Config) IsDecorationEnabled() bool {
+	if !c.IsAccessibilityEnabled() {
+		return true
+	}
+	return c.Accessibility.Decorations != nil && *c.Accessibility.Decorations
+}
+
+// Bell configures the audible or visual bell and which events ring it
+type Bell struct {
+	Style        string `yaml:"style,omitempty"`          // "off" (default), "audible", or "visual"
+	OnComplete   *bool  `yaml:"on_complete,omitempty"`    // a command finished (default: true)
+	OnError      *bool  `yaml:"on_error,omitempty"`       // an error dialog opened (default: true)
+	OnInvalidKey *bool  `yaml:"on_invalid_key,omitempty"` // a key matched no hotkey (default: true)
+}
+
+// Bell events passed to Config.BellStyle
+const (
+	BellComplete   = "complete"
+	BellError      = "error"
+	BellInvalidKey = "invalid_key"
+)
+
+// BellStyle returns how to ring the bell for event ("audible" or "visual"),
+// or "" if the bell is off or disabled for that event
+func (c *Config) BellStyle(event string) string {
+	if c.Bell == nil || c.Bell.Style == "" || c.Bell.Style == "off" {
+		return ""
+	}
+	var enabled *bool
+	switch event {
+	case BellComplete:
+		enabled = c.Bell.OnComplete
+	case BellError:
+		enabled = c.Bell.OnError
+	case BellInvalidKey:
+		enabled = c.Bell.OnInvalidKey
+	default:
+		return ""
+	}
+	if enabled != nil && !*enabled {
+		return ""
+	}
+	return c.Bell.Style
+}
+
+// validateLock reports a menu lock without a known auth method
+func validateLock(l *MenuLock, menuName string) []string {
+	if l == nil || l.Auth == "os" {
+		return nil
+	}
+	if l.Auth == "" {
+		return []string{fmt.Sprintf("%s: lock missing auth (expected os)", menuName)}
+	}
+	return []string{fmt.Sprintf("%s: lock: invalid auth '%s' (expected os)", menuName, l.Auth)}
+}
+
+// validateBell reports an unknown bell style
+func validateBell(b *Bell) []string {
+	if b == nil || b.Style == "" {
+		return nil
+	}
+	switch b.Style {
+	case "off", "audible", "visual":
+		return nil
+	}
+	return []string{fmt.Sprintf("bell: invalid style '%s' (expected off, audible, or visual)", b.Style)}
+}
+
+// CtrlC configures what Ctrl+C does at the menu and while a command runs
+type CtrlC struct {
+	Menu             string `yaml:"menu,omitempty"`              // "quit" (default), "ignore", or "confirm"
+	InterruptCommand *bool  `yaml:"interrupt_command,omitempty"` // forward to a running command (default: true)
+}
+
+// CtrlCMenuAction returns what Ctrl+C does at the menu: quit, ignore, or confirm
+func (c *Config) CtrlCMenuAction() string {
+	if c.CtrlC == nil || c.CtrlC.Menu == "" {
+		return "quit"
+	}
+	return c.CtrlC.Menu
+}
+
+// IsCtrlCInterruptEnabled returns true if Ctrl+C should interrupt a running
+// command (default: true when omitted)
+func (c *Config) IsCtrlCInterruptEnabled() bool {
+	if c.CtrlC == nil || c.CtrlC.InterruptCommand == nil {
+		return true
+	}
+	return *c.CtrlC.InterruptCommand
+}
+
+// validateCtrlC reports an unknown Ctrl+C menu action
+func validateCtrlC(c *CtrlC) []string {
+	if c == nil || c.Menu == "" {
+		return nil
+	}
+	switch c.Menu {
+	case "quit", "ignore", "confirm":
+		return nil
+	}
+	return []string{fmt.Sprintf("ctrl_c: invalid menu action '%s' (expected quit, ignore, or confirm)", c.Menu)}
+}
+
+// KeyBindings moves the menu's built-in keys. Each key is a letter or digit,
+// F1-F12, or Ctrl+ and a letter.
+type KeyBindings struct {
+	Reload string `yaml:"reload,omitempty"` // reloads the config (default: R)
+}
+
+// DefaultReloadKey reloads the config unless keybindings.reload moves it
+const DefaultReloadKey = "R"
+
+// reservedKeys are the built-in keys that can't be rebound to another action
+var reservedKeys = map[string]string{
+	"F2":     "help",
+	"F3":     "profiles",
+	"F4":     "hotkey reassignment",
+	"F5":     "themes",
+	"Ctrl+C": "quitting",
+	"Ctrl+M": "selecting (terminals send it for Enter)",
+	"Ctrl+P": "command preview",
+	"Ctrl+Y": "copying commands",
+}
+
+// ReloadKey returns the key that reloads the config, as ParseKey writes it
+func (c *Config) ReloadKey() string {
+	if c.KeyBindings != nil {
+		if key, ok := ParseKey(c.KeyBindings.Reload); ok {
+			return key
+		}
+	}
+	return DefaultReloadKey
+}
+
+// ParseKey parses a key binding, ignoring case: a letter or digit ("r" ->
+// "R"), a function key ("f6" -> "F6"), or Ctrl and a letter ("ctrl+r" ->
+// "Ctrl+R"). It reports false for anything else.
+func ParseKey(value string) (string, bool) {
+	key := strings.ToUpper(strings.TrimSpace(value))
+	if r := []rune(key); len(r) == 1 {
+		return key, unicode.IsLetter(r[0]) || unicode.IsDigit(r[0])
+	}
+	if letter, ok := strings.CutPrefix(key, "CTRL+"); ok {
+		if len(letter) == 1 && letter[0] >= 'A' && letter[0] <= 'Z' {
+			return "Ctrl+" + letter, true
+		}
+		return "", false
+	}
+	if n, err := strconv.Atoi(strings.TrimPrefix(key, "F")); err == nil && key[0] == 'F' && n >= 1 && n <= 12 {
+		return fmt.Sprintf("F%d", n), true
+	}
+	return "", false
+}
+
+// validateKeyBindings reports keys that aren't valid or are taken by another
+// built-in action
+func validateKeyBindings(k *KeyBindings) []string {
+	if k == nil || k.Reload == "" {
+		return nil
+	}
+	key, ok := ParseKey(k.Reload)
+	if !ok {
+		return []string{fmt.Sprintf("keybindings: reload: invalid key '%s' (expected a letter or digit, F1-F12, or Ctrl+ and a letter)", k.Reload)}
+	}
+	if action, taken := reservedKeys[key]; taken {
+		return []string{fmt.Sprintf("keybindings: reload: %s is already used for %s", key, action)}
+	}
+	return nil
+}
+
+// Layout configures the size and placement of the main menu box
+type Layout struct {
+	Width  string `yaml:"width,omitempty"`  // columns, or "full" for the whole terminal
+	Height string `yaml:"height,omitempty"` // rows, or "full"
+	Align  string `yaml:"align,omitempty"`  // "center" (default) or "top-left"
+}
+
+// ParseLayoutSize parses a layout width or height: a positive number or "full".
+// Returns the size, whether it is "full", and whether the value was valid.
+func ParseLayoutSize(value string) (int, bool, bool) {
+	value = strings.TrimSpace(value)
+	if strings.EqualFold(value, "full") {
+		return 0, true, true
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n <= 0 {
+		return 0, false, false
+	}
+	return n, false, true
+}
+
+// validateLayout reports invalid layout values
+func validateLayout(l *Layout) []string {
+	if l == nil {
+		return nil
+	}
+	var errs []string
+	if l.Width != "" {
+		if _, _, ok := ParseLayoutSize(l.Width); !ok {
+			errs = append(errs, fmt.Sprintf("layout: invalid width '%s' (expected a number or \"full\")", l.Width))
+		}
+	}
+	if l.Height != "" {
+		if _, _, ok := ParseLayoutSize(l.Height); !ok {
+			errs = append(errs, fmt.Sprintf("layout: invalid height '%s' (expected a number or \"full\")", l.Height))
+		}
+	}
+	if l.Align != "" && l.Align != "center" && l.Align != "top-left" {
+		errs = append(errs, fmt.Sprintf("layout: invalid align '%s' (expected center or top-left)", l.Align))
+	}
+	return errs
+}
+
+// TitlePlaceholders are the placeholders title_format accepts
+var TitlePlaceholders = []string{"title", "root", "path", "count"}
+
+// validateTitleFormat reports placeholders title_format does not know
+func validateTitleFormat(format string) []string {
+	var errs []string
+	for _, m := range placeholder.FindAllStringSubmatch(format, -1) {
+		known := false
+		for _, p := range TitlePlaceholders {
+			known = known || m[1] == p
+		}
+		if !known {
+			errs = append(errs, fmt.Sprintf("title_format: unknown placeholder '{%s}' (expected {%s})", m[1], strings.Join(TitlePlaceholders, "}, {")))
+		}
+	}
+	return errs
+}
+
+// TitleBar configures the header line drawn inside the menu title bar.
+// Date and time formats are Go time layouts (e.g. "2006-01-02", "15:04").
+type TitleBar struct {
+	Text       string `yaml:"text,omitempty"`      // replaces "Menu Works"
+	Date       *bool  `yaml:"date,omitempty"`      // show the date (default: true)
+	Clock      *bool  `yaml:"clock,omitempty"`     // show the time (default: true)
+	Clock24h   bool   `yaml:"clock_24h,omitempty"` // 24-hour time when time_format is unset
+	DateFormat string `yaml:"date_format,omitempty"`
+	TimeFormat string `yaml:"time_format,omitempty"`
+	Hostname   bool   `yaml:"hostname,omitempty"` // show the machine's hostname
+	Username   bool   `yaml:"username,omitempty"` // show the current user's name
+}
+
+// IsMouseEnabled returns true if mouse support is enabled (default: true when omitted)
+func (c *Config) IsMouseEnabled() bool {
+	if c.MouseSupport == nil {
+		return true
+	}
+	return *c.MouseSupport
+}
+
+// IsSplashEnabled returns true if the splash screen should be shown (default: true when omitted)
+func (c *Config) IsSplashEnabled() bool {
+	if c.SplashScreen == nil {
+		return true
+	}
+	return *c.SplashScreen
+}
+
+// IsFooterEnabled returns true if the key binding footer should be drawn (default: true when omitted)
+func (c *Config) IsFooterEnabled() bool {
+	if c.Footer == nil {
+		return true
+	}
+	return *c.Footer
+}
+
+// IsAnimationsEnabled returns true if entering and leaving submenus plays a
+// short wipe transition (default: true when omitted)
+func (c *Config) IsAnimationsEnabled() bool {
+	if c.Animations == nil {
+		return true
+	}
+	return *c.Animations
+}
+
+// IsWrapNavigationEnabled returns true if moving down from the last item
+// selects the first and up from the first selects the last (default: true when omitted)
+func (c *Config) IsWrapNavigationEnabled() bool {
+	if c.WrapNavigation == nil {
+		return true
+	}
+	return *c.WrapNavigation
+}
+
+// IsShadowEnabled returns true if drop shadows should be drawn (default: true when omitted)
+func (c *Config) IsShadowEnabled() bool {
+	if c.Shadow == nil {
+		return true
+	}
+	return *c.Shadow
+}
+
+// IsUpdateCheckEnabled returns true if a newer release should be looked up
+// on startup (default: false when omitted; the check is opt-in)
+func (c *Config) IsUpdateCheckEnabled() bool {
+	if c.UpdateCheck == nil {
+		return false
+	}
+	return *c.UpdateCheck
+}
+
+// IsRestorePositionEnabled returns true if the last menu path and selection
+// should be restored on startup (default: false when omitted)
+func (c *Config) IsRestorePositionEnabled() bool {
+	if c.RestorePosition == nil {
+		return false
+	}
+	return *c.RestorePosition
+}
+
+// IsAutoReloadEnabled returns true if the config should be reloaded when its
+// file changes on disk (default: false when omitted)
+func (c *Config) IsAutoReloadEnabled() bool {
+	if c.AutoReload == nil {
+		return false
+	}
+	return *c.AutoReload
+}
+
+// IsWrapperEnabled returns true if MenuWorks stands in for the user's login
+// shell or desktop, so nothing but a quit item leaves it (default: false
+// when omitted)
+func (c *Config) IsWrapperEnabled() bool {
+	if c.Wrapper == nil {
+		return false
+	}
+	return *c.Wrapper
+}
+
+// SessionTimeoutDuration returns how long the menu may go without input
+// before the session ends, or 0 if sessions don't time out
+func (c *Config) SessionTimeoutDuration() time.Duration {
+	if !isTimeout(c.SessionTimeout) {
+		return 0
+	}
+	d, _ := time.ParseDuration(c.SessionTimeout)
+	return d
+}
+
+// IsStatusSymbolsEnabled returns true if command results are marked with
+// ✓ and ✗ in addition to the success and failure colors (default: false
+// when omitted; always on in the accessibility mode)
+func (c *Config) IsStatusSymbolsEnabled() bool {
+	if c.StatusSymbols == nil {
+		return c.IsAccessibilityEnabled()
+	}
+	return *c.StatusSymbols
+}
+
+// IsTransparentBackground returns true if the terminal's default background
+// should be used instead of the theme background (default: false when omitted)
+func (c *Config) IsTransparentBackground() bool {
+	if c.TransparentBackground == nil {
+		return false
+	}
+	return *c.TransparentBackground
+}
+
+// DefaultFileName is the name of the config file in the user and portable locations
+const DefaultFileName = "config.yaml"
+
+// UserConfigDir returns the per-user MenuWorks config directory:
+// $XDG_CONFIG_HOME/menuworks on Linux, %APPDATA%\MenuWorks on Windows,
+// and ~/Library/Application Support/MenuWorks on macOS.
+func UserConfigDir() (string, error) {
+	base, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	name := "menuworks"
+	if runtime.GOOS == "windows" || runtime.GOOS == "darwin" {
+		name = "MenuWorks"
+	}
+	return filepath.Join(base, name), nil
+}
+
+// ResolvePath chooses the config file to use when no explicit path is given.
+// In portable mode the file next to the executable is always used. Otherwise the
+// user config directory is preferred, an existing exe-adjacent file is used as a
+// fallback, and a missing config is created in the user config directory.
+func ResolvePath(exeDir string, portable bool) string {
+	portablePath := filepath.Join(exeDir, DefaultFileName)
+	if portable {
+		return portablePath
+	}
+
+	userDir, err := UserConfigDir()
+	if err != nil {
+		return portablePath
+	}
+	userPath := filepath.Join(userDir, DefaultFileName)
+
+	if _, err := os.Stat(userPath); err == nil {
+		return userPath
+	}
+	if _, err := os.Stat(portablePath); err == nil {
+		return portablePath
+	}
+	return userPath
+}
+
+// ListProfiles returns the names of all profiles (*.yaml / *.yml files) in dir, sorted.
+// The profile name is the file name without its extension, e.g. "work" for work.yaml.
+func ListProfiles(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		ext := strings.ToLower(filepath.Ext(entry.Name()))
+		if ext != ".yaml" && ext != ".yml" {
+			continue
+		}
+		names = append(names, strings.TrimSuffix(entry.Name(), filepath.Ext(entry.Name())))
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// ProfilePath returns the path of the named profile in dir, preferring .yaml over .yml
+func ProfilePath(dir, name string) string {
+	ymlPath := filepath.Join(dir, name+".yml")
+	if _, err := os.Stat(ymlPath); err == nil {
+		if _, err := os.Stat(filepath.Join(dir, name+".yaml")); os.IsNotExist(err) {
+			return ymlPath
+		}
+	}
+	return filepath.Join(dir, name+".yaml")
+}
+
+// ProfileName returns the profile name for a config file path
+func ProfileName(path string) string {
+	base := filepath.Base(path)
+	return strings.TrimSuffix(base, filepath.Ext(base))
+}
+
+// Load reads the config file from disk, or writes embedded default if missing
+// Returns (config, wasCreated, error) where wasCreated indicates if config was just created on first run
+func Load(filePath string) (*Config, bool, error) {
+	logging.Debug("loading config", "path", filePath)
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			// File doesn't exist, write embedded default
+			logging.Info("config not found, writing default", "path", filePath)
+			if writeErr := WriteDefault(filePath); writeErr != nil {
+				return nil, false, fmt.Errorf("failed to write default config: %w", writeErr)
+			}
+			// Parse the embedded default and signal that it was created
+			cfg, parseErr := parseYAML([]byte(defaultConfigYAML))
+			return cfg, true, parseErr
+		}
+		return nil, false, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	cfg, err := parseYAML(data)
+	if err != nil {
+		logging.Error("config failed to load", "path", filePath, "err", err)
+		return nil, false, err
+	}
+	logging.Info("config loaded", "path", filePath, "items", len(cfg.Items), "menus", len(cfg.Menus))
+	for _, warning := range Validate(cfg) {
+		logging.Warn("config validation", "path", filePath, "warning", warning)
+	}
+	return cfg, false, nil
+}
+
+// parseYAML unmarshals YAML bytes into Config struct and resolves per-OS item settings
+func parseYAML(data []byte) (*Config, error) {
+	var cfg Config
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse YAML: %w", newYAMLError(data, err))
+	}
+	recordSourceIndexes(&cfg)
+	if err := ResolveTemplates(&cfg); err != nil {
+		return nil, err
+	}
+	if err := ResolveRefs(&cfg); err != nil {
+		return nil, err
+	}
+	ResolveForOS(&cfg, runtime.GOOS)
+	applyDefaults(&cfg)
+	applyQuitDefaults(&cfg)
+	return &cfg, nil
+}
+
+// DefaultQuitLabel and DefaultQuitHotkey are used for quit items that don't set their own
+const (
+	DefaultQuitLabel  = "Quit"
+	DefaultQuitHotkey = "Q"
+)
+
+// applyQuitDefaults gives quit items without a label the default one. The default
+// hotkey is applied by the navigator, so an explicit Q elsewhere in the menu still wins.
+func applyQuitDefaults(cfg *Config) {
+	fill := func(items []MenuItem) {
+		for i := range items {
+			if items[i].Type == "quit" && items[i].Label == "" {
+				items[i].Label = DefaultQuitLabel
+			}
+		}
+	}
+	fill(cfg.Items)
+	for _, m := range cfg.Menus {
+		fill(m.Items)
+	}
+}
+
+// recordSourceIndexes remembers each item's position in the file so edits can be written back
+func recordSourceIndexes(cfg *Config) {
+	for i := range cfg.Items {
+		cfg.Items[i].srcIndex = i
+	}
+	for _, m := range cfg.Menus {
+		for i := range m.Items {
+			m.Items[i].srcIndex = i
+		}
+	}
+}
+
+// ResolveRefs replaces every item that has a ref with a copy of the item whose id matches.
+// Fields set on the referencing item (label, hotkey, help, showOutput, os, overrides)
+// take precedence over the referenced item. Unknown ids, duplicate ids, and reference
+// cycles are reported as errors.
+func ResolveRefs(cfg *Config) error {
+	byID := make(map[string]MenuItem)
+	collect := func(items []MenuItem) error {
+		for _, item := range items {
+			if item.ID == "" {
+				continue
+			}
+			if _, dup := byID[item.ID]; dup {
+				return fmt.Errorf("duplicate item id '%s'", item.ID)
+			}
+			byID[item.ID] = item
+		}
+		return nil
+	}
+	if err := collect(cfg.Items); err != nil {
+		return err
+	}
+	for _, m := range cfg.Menus {
+		if err := collect(m.Items); err != nil {
+			return err
+		}
+	}
+
+	var err error
+	if cfg.Items, err = resolveItemRefs(cfg.Items, byID); err != nil {
+		return err
+	}
+	for name, m := range cfg.Menus {
+		if m.Items, err = resolveItemRefs(m.Items, byID); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		cfg.Menus[name] = m
+	}
+	return nil
+}
+
+// resolveItemRefs resolves references in a single list of items
+func resolveItemRefs(items []MenuItem, byID map[string]MenuItem) ([]MenuItem, error) {
+	for i, item := range items {
+		if item.Ref == "" {
+			continue
+		}
+		resolved, err := resolveRef(item, byID, map[string]bool{})
+		if err != nil {
+			return nil, fmt.Errorf("item %d: %w", i, err)
+		}
+		items[i] = resolved
+	}
+	for i := range items {
+		if len(items[i].Commands) == 0 {
+			continue
+		}
+		commands, err := resolveItemRefs(items[i].Commands, byID)
+		if err != nil {
+			return nil, fmt.Errorf("item %d: %w", i, err)
+		}
+		items[i].Commands = commands
+	}
+	return items, nil
 }
 
-// IsMouseEnabled returns true if mouse support is enabled (default: true when omitted)
-func (c *Config) IsMouseEnabled() bool {
-	if c.MouseSupport == nil {
-		return true
+// resolveRef follows a single reference chain, detecting cycles via seen
+func resolveRef(item MenuItem, byID map[string]MenuItem, seen map[string]bool) (MenuItem, error) {
+	if item.Ref == "" {
+		return item, nil
 	}
-	return *c.MouseSupport
-}
+	if seen[item.Ref] {
+		return MenuItem{}, fmt.Errorf("reference cycle at id '%s'", item.Ref)
+	}
+	seen[item.Ref] = true
 
-// IsSplashEnabled returns true if the splash screen should be shown (default: true when omitted)
-func (c *Config) IsSplashEnabled() bool {
-	if c.SplashScreen == nil {
-		return true
+	target, ok := byID[item.Ref]
+	if !ok {
+		return MenuItem{}, fmt.Errorf("ref '%s' does not match any item id", item.Ref)
 	}
-	return *c.SplashScreen
+	target, err := resolveRef(target, byID, seen)
+	if err != nil {
+		return MenuItem{}, err
+	}
+
+	// The copy is a concrete item; it does not re-export the target's id
+	target.srcIndex = item.srcIndex
+	target.ID = item.ID
+	target.Ref = ""
+	return overlayItem(target, item), nil
 }
 
-// Load reads the config file from disk, or writes embedded default if missing
-// Returns (config, wasCreated, error) where wasCreated indicates if config was just created on first run
-func Load(filePath string) (*Config, bool, error) {
-	data, err := os.ReadFile(filePath)
-	if err != nil {
-		if os.IsNotExist(err) {
-			// File doesn't exist, write embedded default
-			if writeErr := WriteDefault(filePath); writeErr != nil {
-				return nil, false, fmt.Errorf("failed to write default config: %w", writeErr)
+// overlayItem returns target with every field that item sets replacing its
+// own, so a referencing item keeps all of its settings. Exec variants are
+// replaced one at a time, so setting only workdir keeps the target's commands.
+func overlayItem(target, item MenuItem) MenuItem {
+	setString := func(dst *string, src string) {
+		if src != "" {
+			*dst = src
+		}
+	}
+	setString(&target.Type, item.Type)
+	setString(&target.Label, item.Label)
+	setString(&target.Hotkey, item.Hotkey)
+	setString(&target.Icon, item.Icon)
+	setString(&target.Target, item.Target)
+	setString(&target.URL, item.URL)
+	setString(&target.Path, item.Path)
+	setString(&target.Help, item.Help)
+	setString(&target.Launch, item.Launch)
+	setString(&target.Webhook, item.Webhook)
+	setString(&target.Stdin, item.Stdin)
+	setString(&target.RunAs, item.RunAs)
+	setString(&target.Template, item.Template)
+	setString(&target.Timeout, item.Timeout)
+	setString(&target.DisabledReason, item.DisabledReason)
+	setString(&target.Badge, item.Badge)
+	setString(&target.AvailableHours, item.AvailableHours)
+	setString(&target.AvailableDays, item.AvailableDays)
+	if len(item.Extensions) > 0 {
+		target.Extensions = item.Extensions
+	}
+	if len(item.Roles) > 0 {
+		target.Roles = item.Roles
+	}
+
+	setString(&target.Exec.WorkDir, item.Exec.WorkDir)
+	for _, v := range []struct {
+		key       string
+		dst, line *string
+	}{
+		{"windows", &target.Exec.Windows, &item.Exec.Windows},
+		{"linux", &target.Exec.Linux, &item.Exec.Linux},
+		{"mac", &target.Exec.Mac, &item.Exec.Mac},
+	} {
+		if *v.line == "" {
+			continue
+		}
+		*v.dst = *v.line
+		// The variant comes with its program form, or drops the target's
+		argv := make(map[string][]string)
+		for k, a := range target.Exec.Argv {
+			if k != v.key {
+				argv[k] = a
 			}
-			// Parse the embedded default and signal that it was created
-			cfg, parseErr := parseYAML([]byte(defaultConfigYAML))
-			return cfg, true, parseErr
 		}
-		return nil, false, fmt.Errorf("failed to read config file: %w", err)
+		if a, ok := item.Exec.Argv[v.key]; ok {
+			argv[v.key] = a
+		}
+		target.Exec.Argv = argv
+		if len(argv) == 0 {
+			target.Exec.Argv = nil
+		}
 	}
 
-	cfg, err := parseYAML(data)
-	return cfg, false, err
+	if item.ShowOutput != nil {
+		target.ShowOutput = item.ShowOutput
+	}
+	if item.HasBadgeCommand() {
+		target.BadgeExec = item.BadgeExec
+	}
+	if item.Confirm != nil {
+		target.Confirm = item.Confirm
+	}
+	if len(item.OS) > 0 {
+		target.OS = item.OS
+	}
+	if len(item.Overrides) > 0 {
+		target.Overrides = item.Overrides
+	}
+	if len(item.Commands) > 0 {
+		target.Commands = item.Commands
+	}
+	if len(item.Args) > 0 {
+		target.Args = item.Args
+	}
+	if item.Collapsed {
+		target.Collapsed = true
+	}
+	if item.Disabled {
+		target.Disabled = true
+	}
+	return target
 }
 
-// parseYAML unmarshals YAML bytes into Config struct
-func parseYAML(data []byte) (*Config, error) {
-	var cfg Config
-	if err := yaml.Unmarshal(data, &cfg); err != nil {
-		return nil, fmt.Errorf("failed to parse YAML: %w", err)
+// ResolveForOS drops items not visible on osType and applies per-OS overrides
+// to the remaining items, for the root menu and every submenu.
+func ResolveForOS(cfg *Config, osType string) {
+	cfg.Items = resolveItemsForOS(cfg.Items, osType)
+	for name, m := range cfg.Menus {
+		m.Items = resolveItemsForOS(m.Items, osType)
+		cfg.Menus[name] = m
 	}
-	return &cfg, nil
+}
+
+// resolveItemsForOS filters and overrides a single list of items
+func resolveItemsForOS(items []MenuItem, osType string) []MenuItem {
+	if items == nil {
+		return nil
+	}
+	resolved := make([]MenuItem, 0, len(items))
+	for _, item := range items {
+		if !item.VisibleOn(osType) {
+			continue
+		}
+		item.Commands = resolveItemsForOS(item.Commands, osType)
+		resolved = append(resolved, item.ForOS(osType))
+	}
+	return resolved
 }
 
 // WriteDefault writes the embedded default config to filePath
@@ -138,27 +1521,32 @@ func WriteDefault(filePath string) error {
 	return nil
 }
 
-// WriteDefaultWithBackup backs up the existing config and writes the embedded default.
-func WriteDefaultWithBackup(filePath string) error {
+// WriteDefaultWithBackup moves the existing config aside as
+// <file>.broken-<timestamp> and writes the embedded default in its place. It
+// returns where the old config went, or "" if there was none.
+func WriteDefaultWithBackup(filePath string) (string, error) {
+	backupPath := ""
 	if _, err := os.Stat(filePath); err == nil {
-		data, readErr := os.ReadFile(filePath)
-		if readErr != nil {
-			return readErr
-		}
-		backupPath := filePath + ".bak"
-		if _, statErr := os.Stat(backupPath); statErr == nil {
-			return fmt.Errorf("backup file already exists: %s", backupPath)
-		} else if !os.IsNotExist(statErr) {
-			return statErr
+		stamp := filePath + ".broken-" + time.Now().Format("20060102-150405")
+		backupPath = stamp
+		// Two resets within a second keep both backups
+		for n := 2; ; n++ {
+			if _, statErr := os.Lstat(backupPath); os.IsNotExist(statErr) {
+				break
+			} else if statErr != nil {
+				return "", statErr
+			}
+			backupPath = fmt.Sprintf("%s-%d", stamp, n)
 		}
-		if writeErr := os.WriteFile(backupPath, data, 0644); writeErr != nil {
-			return writeErr
+		if err := os.Rename(filePath, backupPath); err != nil {
+			return "", err
 		}
+		logging.Info("moved broken config aside", "path", filePath, "backup", backupPath)
 	} else if !os.IsNotExist(err) {
-		return err
+		return "", err
 	}
 
-	return WriteDefault(filePath)
+	return backupPath, WriteDefault(filePath)
 }
 
 // Validate checks for invalid targets and item types
@@ -170,11 +1558,16 @@ func Validate(cfg *Config) []string {
 		if err := validateItem(item, i, cfg); err != nil {
 			errs = append(errs, err...)
 		}
+		if item.Type == "fallback_shell" {
+			errs = append(errs, fmt.Sprintf("item %d: fallback_shell must be in a menu with a lock", i))
+		}
 	}
 
 	// Check submenu items
 	if cfg.Menus != nil {
 		for menuName, menu := range cfg.Menus {
+			errs = append(errs, validateDefaults(menu.Defaults, menuName+": defaults")...)
+			errs = append(errs, validateLock(menu.Lock, menuName)...)
 			for i, item := range menu.Items {
 				if err := validateItem(item, i, cfg); err != nil {
 					// Prefix with menu name for context
@@ -184,17 +1577,249 @@ func Validate(cfg *Config) []string {
 					}
 					errs = append(errs, prefixed...)
 				}
+				// A shell is only ever a sign-in away, whatever leads to the menu
+				if item.Type == "fallback_shell" && menu.Lock == nil {
+					errs = append(errs, fmt.Sprintf("%s: item %d: fallback_shell must be in a menu with a lock", menuName, i))
+				}
 			}
 		}
 	}
 
+	errs = append(errs, HotkeyConflicts(cfg)...)
+	errs = append(errs, validateLayout(cfg.Layout)...)
+	errs = append(errs, validateCtrlC(cfg.CtrlC)...)
+	errs = append(errs, validateKeyBindings(cfg.KeyBindings)...)
+	errs = append(errs, validateBell(cfg.Bell)...)
+	errs = append(errs, validateDefaults(cfg.Defaults, "defaults")...)
+	if cfg.Launch != "" && !isLaunchMode(cfg.Launch) {
+		errs = append(errs, fmt.Sprintf("launch: invalid mode '%s' (expected %s)", cfg.Launch, strings.Join(LaunchModes, ", ")))
+	}
+	errs = append(errs, validateTitleFormat(cfg.TitleFormat)...)
+	if cfg.SessionTimeout != "" && !isTimeout(cfg.SessionTimeout) {
+		errs = append(errs, fmt.Sprintf("session_timeout: invalid duration '%s' (expected a duration such as 10m)", cfg.SessionTimeout))
+	}
+	if m, exists := cfg.Menus[cfg.SessionMenu]; exists && m.Lock != nil {
+		errs = append(errs, fmt.Sprintf("session_menu: '%s' has a lock, so sessions can't end there", cfg.SessionMenu))
+	}
+	if _, exists := cfg.Roles[cfg.DefaultRole]; cfg.DefaultRole != "" && !exists {
+		errs = append(errs, fmt.Sprintf("default_role: unknown role '%s'", cfg.DefaultRole))
+	}
+	if cfg.SingleInstance != "" && !isSingleInstanceMode(cfg.SingleInstance) {
+		errs = append(errs, fmt.Sprintf("single_instance: invalid value '%s' (expected %s)", cfg.SingleInstance, strings.Join(SingleInstanceModes, ", ")))
+	}
+	if cfg.BigTitle != "" && !isBigTitleMode(cfg.BigTitle) {
+		errs = append(errs, fmt.Sprintf("big_title: invalid value '%s' (expected %s)", cfg.BigTitle, strings.Join(BigTitleModes, ", ")))
+	}
+	if cfg.Locale != "" && !i18n.Supported(cfg.Locale) {
+		errs = append(errs, fmt.Sprintf("locale: unsupported locale '%s' (expected %s)", cfg.Locale, strings.Join(i18n.Locales(), ", ")))
+	}
+
 	return errs
 }
 
+// HotkeyConflicts lists explicit hotkeys that are used by more than one item in the same menu.
+// Only the first item with a given hotkey receives it at runtime; later ones lose it.
+func HotkeyConflicts(cfg *Config) []string {
+	reloadKey := cfg.ReloadKey()
+	conflicts := hotkeyConflictsInMenu(cfg.Items, reloadKey)
+
+	var names []string
+	for name := range cfg.Menus {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		for _, c := range hotkeyConflictsInMenu(cfg.Menus[name].Items, reloadKey) {
+			conflicts = append(conflicts, fmt.Sprintf("%s: %s", name, c))
+		}
+	}
+	return conflicts
+}
+
+// hotkeyConflictsInMenu checks a single menu's items for duplicate explicit
+// hotkeys, hotkeys longer than two keys, and hotkeys or sequences whose first
+// key is taken by another item or by reloadKey
+func hotkeyConflictsInMenu(items []MenuItem, reloadKey string) []string {
+	var conflicts []string
+	owner := make(map[string]int)
+	for i, item := range items {
+		if item.Hotkey == "" {
+			continue
+		}
+		hotkey := strings.ToUpper(item.Hotkey)
+		keys := []rune(hotkey)
+		if len(keys) > 2 {
+			conflicts = append(conflicts, fmt.Sprintf("item %d: hotkey '%s' is too long (use one key or a sequence of two)", i, hotkey))
+			continue
+		}
+		if len(keys) == 1 && hotkey == reloadKey {
+			conflicts = append(conflicts, fmt.Sprintf("item %d: hotkey '%s' reloads the config instead (see keybindings)", i, hotkey))
+			continue
+		}
+		if len(keys) == 2 && string(keys[0]) == reloadKey {
+			conflicts = append(conflicts, fmt.Sprintf("item %d: hotkey '%s' starts with %s, which reloads the config", i, hotkey, reloadKey))
+			continue
+		}
+		if first, used := owner[hotkey]; used {
+			conflicts = append(conflicts, fmt.Sprintf("item %d: hotkey '%s' already used by item %d (%s)", i, hotkey, first, items[first].Label))
+			continue
+		}
+		owner[hotkey] = i
+	}
+
+	// A single key that starts a sequence only runs after the sequence times out
+	for i, item := range items {
+		hotkey := strings.ToUpper(item.Hotkey)
+		if len([]rune(hotkey)) != 1 || owner[hotkey] != i {
+			continue
+		}
+		for j, other := range items {
+			sequence := strings.ToUpper(other.Hotkey)
+			if keys := []rune(sequence); len(keys) == 2 && string(keys[0]) == hotkey && owner[sequence] == j {
+				conflicts = append(conflicts, fmt.Sprintf("item %d: hotkey '%s' starts item %d's sequence '%s' (%s), so it only runs after a pause", i, hotkey, j, sequence, other.Label))
+				break
+			}
+		}
+	}
+	return conflicts
+}
+
+// SetItemHotkey writes a new explicit hotkey for item (from menu menuName) back to the
+// config file at filePath. The rest of the file, including comments, is preserved.
+func SetItemHotkey(filePath, menuName string, item MenuItem, hotkey string) error {
+	doc, err := readYAMLNode(filePath)
+	if err != nil {
+		return err
+	}
+
+	var itemsNode *yaml.Node
+	if menuName == "root" {
+		itemsNode = mappingValue(doc.Content[0], "items")
+	} else {
+		itemsNode = mappingValue(mappingValue(mappingValue(doc.Content[0], "menus"), menuName), "items")
+	}
+	if itemsNode == nil || itemsNode.Kind != yaml.SequenceNode || item.srcIndex >= len(itemsNode.Content) {
+		return fmt.Errorf("item '%s' not found in menu '%s'", item.Label, menuName)
+	}
+
+	setMappingString(itemsNode.Content[item.srcIndex], "hotkey", hotkey)
+
+	return writeYAMLNode(filePath, doc)
+}
+
+// SetTheme writes the selected theme name back to the config file at filePath,
+// preserving the rest of the file including comments.
+func SetTheme(filePath, name string) error {
+	doc, err := readYAMLNode(filePath)
+	if err != nil {
+		return err
+	}
+	setMappingString(doc.Content[0], "theme", name)
+	return writeYAMLNode(filePath, doc)
+}
+
+// readYAMLNode reads a config file as a YAML node tree for in-place edits
+func readYAMLNode(filePath string) (*yaml.Node, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse YAML: %w", err)
+	}
+	if len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("config file is empty")
+	}
+	return &doc, nil
+}
+
+// writeYAMLNode encodes a YAML node tree back to filePath using the config's 2-space indent
+func writeYAMLNode(filePath string, doc *yaml.Node) error {
+	var out strings.Builder
+	enc := yaml.NewEncoder(&out)
+	enc.SetIndent(2)
+	if err := enc.Encode(doc); err != nil {
+		return fmt.Errorf("failed to encode config: %w", err)
+	}
+	if err := enc.Close(); err != nil {
+		return fmt.Errorf("failed to encode config: %w", err)
+	}
+	return os.WriteFile(filePath, []byte(out.String()), 0644)
+}
+
+// setMappingString sets key to a quoted string value in a mapping node, adding the key if missing
+func setMappingString(node *yaml.Node, key, value string) {
+	if valueNode := mappingValue(node, key); valueNode != nil {
+		valueNode.Kind = yaml.ScalarNode
+		valueNode.Tag = "!!str"
+		valueNode.Value = value
+		return
+	}
+	node.Content = append(node.Content,
+		&yaml.Node{Kind: yaml.ScalarNode, Value: key},
+		&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value, Style: yaml.DoubleQuotedStyle},
+	)
+}
+
+// mappingValue returns the value node for key in a YAML mapping node, or nil
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
 // validateItem checks a single menu item
 func validateItem(item MenuItem, index int, cfg *Config) []string {
 	var errs []string
 
+	for _, o := range item.OS {
+		if !isKnownOSKey(o) {
+			errs = append(errs, fmt.Sprintf("item %d: unknown os '%s' (expected windows, linux, or mac)", index, o))
+		}
+	}
+	for o := range item.Overrides {
+		if !isKnownOSKey(o) {
+			errs = append(errs, fmt.Sprintf("item %d: unknown override os '%s' (expected windows, linux, or mac)", index, o))
+		}
+	}
+
+	if item.Confirm != nil && item.Type != "command" && item.Type != "parallel" && item.Type != "quit" {
+		errs = append(errs, fmt.Sprintf("item %d: confirm is only supported on command, parallel, and quit items", index))
+	}
+	if item.Timeout != "" {
+		if item.Type != "command" && item.Type != "parallel" && item.Type != "browse" {
+			errs = append(errs, fmt.Sprintf("item %d: timeout is only supported on command, parallel, and browse items", index))
+		} else if !isTimeout(item.Timeout) {
+			errs = append(errs, fmt.Sprintf("item %d: invalid timeout '%s' (expected a duration such as 30s or 5m)", index, item.Timeout))
+		}
+	}
+	for _, role := range item.Roles {
+		if _, exists := cfg.Roles[role]; !exists {
+			errs = append(errs, fmt.Sprintf("item %d: unknown role '%s'", index, role))
+		}
+	}
+	if item.DisabledReason != "" && !item.Disabled && !item.HasSchedule() {
+		errs = append(errs, fmt.Sprintf("item %d: disabled_reason without disabled: true or a schedule", index))
+	}
+	errs = append(errs, validateSchedule(item, index)...)
+	if item.Icon != "" && !isIcon(item.Icon) {
+		errs = append(errs, fmt.Sprintf("item %d: icon '%s' must be a single character or emoji", index, item.Icon))
+	}
+	if strings.ContainsAny(item.Badge, "\r\n") {
+		errs = append(errs, fmt.Sprintf("item %d: badge must be a single line", index))
+	}
+	if (item.Badge != "" || item.HasBadgeCommand()) && item.Type == "separator" {
+		errs = append(errs, fmt.Sprintf("item %d: separators can't have a badge", index))
+	}
+
 	switch item.Type {
 	case "command":
 		if item.Label == "" {
@@ -203,6 +1828,37 @@ func validateItem(item MenuItem, index int, cfg *Config) []string {
 		if item.Exec.Windows == "" && item.Exec.Linux == "" && item.Exec.Mac == "" {
 			errs = append(errs, fmt.Sprintf("item %d: command missing exec variant (windows, linux, or mac)", index))
 		}
+		if item.Launch != "" && !isLaunchMode(item.Launch) {
+			errs = append(errs, fmt.Sprintf("item %d: invalid launch mode '%s' (expected %s)", index, item.Launch, strings.Join(LaunchModes, ", ")))
+		}
+		if strings.HasPrefix(item.Stdin, stdinFilePrefix) && strings.TrimSpace(strings.TrimPrefix(item.Stdin, stdinFilePrefix)) == "" {
+			errs = append(errs, fmt.Sprintf("item %d: stdin file path is empty", index))
+		}
+		if item.RunAs != "" && (strings.HasPrefix(item.RunAs, "-") || strings.ContainsFunc(item.RunAs, unicode.IsSpace)) {
+			errs = append(errs, fmt.Sprintf("item %d: invalid run_as user '%s'", index, item.RunAs))
+		}
+	case "parallel":
+		if item.Label == "" {
+			errs = append(errs, fmt.Sprintf("item %d: parallel missing label", index))
+		}
+		if len(item.Commands) == 0 {
+			errs = append(errs, fmt.Sprintf("item %d: parallel has no commands", index))
+		}
+		for j, child := range item.Commands {
+			if child.Type != "command" {
+				errs = append(errs, fmt.Sprintf("item %d, command %d: type must be command", index, j))
+				continue
+			}
+			for _, err := range validateItem(child, j, cfg) {
+				errs = append(errs, fmt.Sprintf("item %d, command %s", index, strings.TrimPrefix(err, "item ")))
+			}
+			if child.Stdin == StdinPrompt {
+				errs = append(errs, fmt.Sprintf("item %d, command %d: stdin cannot be prompt in a parallel item", index, j))
+			}
+			if child.Launch != "" && child.Launch != "inline" {
+				errs = append(errs, fmt.Sprintf("item %d, command %d: commands in a parallel item always run inline", index, j))
+			}
+		}
 	case "submenu":
 		if item.Label == "" {
 			errs = append(errs, fmt.Sprintf("item %d: submenu missing label", index))
@@ -218,6 +1874,39 @@ func validateItem(item MenuItem, index int, cfg *Config) []string {
 		if item.Label == "" {
 			errs = append(errs, fmt.Sprintf("item %d: back missing label", index))
 		}
+	case "quit":
+		// The label and hotkey are optional; see DefaultQuitLabel and DefaultQuitHotkey
+	case "edit_config":
+		if item.Label == "" {
+			errs = append(errs, fmt.Sprintf("item %d: edit_config missing label", index))
+		}
+	case "fallback_shell":
+		// exec is optional; see exec.FallbackShell for the default
+		if item.Label == "" {
+			errs = append(errs, fmt.Sprintf("item %d: fallback_shell missing label", index))
+		}
+	case "url":
+		if item.Label == "" {
+			errs = append(errs, fmt.Sprintf("item %d: url missing label", index))
+		}
+		if item.URL == "" {
+			errs = append(errs, fmt.Sprintf("item %d: url missing url", index))
+		} else if u, err := url.Parse(item.URL); err != nil || u.Scheme == "" || strings.ContainsAny(item.URL, " \t\r\n") {
+			errs = append(errs, fmt.Sprintf("item %d: invalid url '%s' (expected a link with a scheme, such as https://example.com)", index, item.URL))
+		}
+	case "browse":
+		errs = append(errs, validateBrowse(item, index)...)
+	case "switch_role":
+		if item.Label == "" {
+			errs = append(errs, fmt.Sprintf("item %d: switch_role missing label", index))
+		}
+		if len(cfg.Roles) < 2 {
+			errs = append(errs, fmt.Sprintf("item %d: switch_role needs at least two roles to switch between", index))
+		}
+	case "group":
+		if item.Label == "" {
+			errs = append(errs, fmt.Sprintf("item %d: group missing label", index))
+		}
 	case "separator":
 		if item.Label != "" || item.Hotkey != "" {
 			errs = append(errs, fmt.Sprintf("item %d: separator must not have label or hotkey", index))
@@ -240,10 +1929,10 @@ func ParseColorName(name string) (tcell.Color, bool) {
 	if name == "" {
 		return tcell.ColorDefault, false
 	}
-	
+
 	// Normalize the color name (lowercase, trim spaces)
 	name = strings.ToLower(strings.TrimSpace(name))
-	
+
 	// Map of valid color names to tcell colors
 	colorMap := map[string]tcell.Color{
 		"black":   tcell.ColorBlack,
@@ -265,37 +1954,86 @@ func ParseColorName(name string) (tcell.Color, bool) {
 		"cyan":    tcell.ColorAqua,
 		"white":   tcell.ColorWhite,
 	}
-	
+
 	if color, ok := colorMap[name]; ok {
 		return color, true
 	}
-	
+
+	// Hex values: "#1e1e2e" or shorthand "#fff"
+	if strings.HasPrefix(name, "#") {
+		return parseHexColor(name[1:])
+	}
+
+	// 256-color palette indexes: "color0" .. "color255"
+	if strings.HasPrefix(name, "color") {
+		idx, err := strconv.Atoi(name[len("color"):])
+		if err != nil || idx < 0 || idx > 255 {
+			return tcell.ColorDefault, false
+		}
+		return tcell.PaletteColor(idx), true
+	}
+
+	// Any other color name known to tcell (e.g. "darkslategray", "orange")
+	if color, ok := tcell.ColorNames[name]; ok {
+		return color, true
+	}
+
 	return tcell.ColorDefault, false
 }
 
+// parseHexColor converts a 6-digit or 3-digit hex string (without "#") to a truecolor value.
+// tcell maps truecolor values to the nearest palette color on terminals with fewer colors.
+func parseHexColor(hex string) (tcell.Color, bool) {
+	if len(hex) == 3 {
+		hex = string([]byte{hex[0], hex[0], hex[1], hex[1], hex[2], hex[2]})
+	}
+	if len(hex) != 6 {
+		return tcell.ColorDefault, false
+	}
+	value, err := strconv.ParseInt(hex, 16, 32)
+	if err != nil {
+		return tcell.ColorDefault, false
+	}
+	return tcell.NewHexColor(int32(value)), true
+}
+
+// ParseAttribute converts a theme style attribute value to a bool.
+// Accepts true/false, yes/no, and on/off (case-insensitive); returns false, false otherwise.
+func ParseAttribute(value string) (bool, bool) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "true", "yes", "on":
+		return true, true
+	case "false", "no", "off":
+		return false, true
+	default:
+		return false, false
+	}
+}
+
 // ValidateTheme validates that the selected theme exists and has valid colors
 // Returns a list of warning messages (not fatal errors)
 func ValidateTheme(cfg *Config) []string {
 	var warnings []string
-	
+
 	// If no theme is specified, that's fine (use defaults)
 	if cfg.Theme == "" {
 		return warnings
 	}
-	
-	// Check if themes map exists
-	if cfg.Themes == nil || len(cfg.Themes) == 0 {
-		warnings = append(warnings, fmt.Sprintf("theme: selected theme '%s' but no themes defined", cfg.Theme))
-		return warnings
+
+	// Check if selected theme exists (config themes first, then built-in gallery)
+	theme, exists, err := resolveTheme(cfg, cfg.Theme)
+	if err != nil {
+		warnings = append(warnings, err.Error())
 	}
-	
-	// Check if selected theme exists
-	theme, exists := cfg.Themes[cfg.Theme]
 	if !exists {
-		warnings = append(warnings, fmt.Sprintf("theme: selected theme '%s' not found in themes", cfg.Theme))
+		if len(cfg.Themes) == 0 {
+			warnings = append(warnings, fmt.Sprintf("theme: selected theme '%s' is not built in and no themes defined", cfg.Theme))
+		} else {
+			warnings = append(warnings, fmt.Sprintf("theme: selected theme '%s' not found in themes", cfg.Theme))
+		}
 		return warnings
 	}
-	
+
 	// Validate each color in the theme
 	colorFields := map[string]string{
 		"background":   theme.Background,
@@ -307,7 +2045,7 @@ func ValidateTheme(cfg *Config) []string {
 		"shadow":       theme.Shadow,
 		"disabled":     theme.Disabled,
 	}
-	
+
 	for fieldName, colorName := range colorFields {
 		if colorName == "" {
 			warnings = append(warnings, fmt.Sprintf("theme '%s': %s color not specified", cfg.Theme, fieldName))
@@ -317,20 +2055,228 @@ func ValidateTheme(cfg *Config) []string {
 			warnings = append(warnings, fmt.Sprintf("theme '%s': invalid color name '%s' for %s", cfg.Theme, colorName, fieldName))
 		}
 	}
-	
+
+	// Optional colors fall back to the defaults when unset
+	for fieldName, colorName := range map[string]string{"menu_bg": theme.MenuBg, "success": theme.Success, "failure": theme.Failure} {
+		if colorName == "" {
+			continue
+		}
+		if _, valid := ParseColorName(colorName); !valid {
+			warnings = append(warnings, fmt.Sprintf("theme '%s': invalid color name '%s' for %s", cfg.Theme, colorName, fieldName))
+		}
+	}
+
+	// Validate style attributes (optional; empty means off)
+	attrFields := map[string]string{
+		"title_bold":       theme.TitleBold,
+		"selected_reverse": theme.SelectedReverse,
+		"hotkey_underline": theme.HotkeyUnderline,
+		"separator_dim":    theme.SeparatorDim,
+	}
+	for fieldName, value := range attrFields {
+		if value == "" {
+			continue
+		}
+		if _, valid := ParseAttribute(value); !valid {
+			warnings = append(warnings, fmt.Sprintf("theme '%s': invalid value '%s' for %s (expected true or false)", cfg.Theme, value, fieldName))
+		}
+	}
+
+	for itemType, icon := range theme.Icons {
+		if !isIconType(itemType) {
+			warnings = append(warnings, fmt.Sprintf("theme '%s': icons: unknown item type '%s' (expected %s)", cfg.Theme, itemType, strings.Join(IconTypes, ", ")))
+		} else if !isIcon(icon) {
+			warnings = append(warnings, fmt.Sprintf("theme '%s': icons: icon '%s' for %s must be a single character or emoji", cfg.Theme, icon, itemType))
+		}
+	}
+
 	return warnings
 }
 
-// GetThemeColors returns the ThemeColors for the selected theme, or nil if none/invalid
+// IconTypes are the item types a theme can give a default icon
+var IconTypes = []string{"command", "parallel", "submenu", "back", "quit", "group", "edit_config", "fallback_shell", "switch_role", "url", "browse"}
+
+// isIconType reports whether itemType is one of IconTypes
+func isIconType(itemType string) bool {
+	for _, t := range IconTypes {
+		if itemType == t {
+			return true
+		}
+	}
+	return false
+}
+
+// isIcon reports whether icon is a single grapheme: one character, possibly
+// with combining marks, or one emoji sequence
+func isIcon(icon string) bool {
+	return uniseg.GraphemeClusterCount(icon) == 1
+}
+
+// GetThemeColors returns the ThemeColors for the selected theme, or nil if none/invalid.
+// Themes defined in the config take priority over built-in themes with the same name.
 func GetThemeColors(cfg *Config) *ThemeColors {
-	if cfg.Theme == "" || cfg.Themes == nil {
+	if cfg.Theme == "" {
 		return nil
 	}
-	
-	theme, exists := cfg.Themes[cfg.Theme]
+
+	theme, exists, _ := resolveTheme(cfg, cfg.Theme)
 	if !exists {
 		return nil
 	}
-	
+
 	return &theme
 }
+
+// resolveTheme looks up a theme by name and applies its extends chain.
+// A theme that extends its own name inherits from the built-in theme of that name.
+// The error reports an unknown parent or a cycle; the partially resolved theme is still returned.
+func resolveTheme(cfg *Config, name string) (ThemeColors, bool, error) {
+	return resolveThemeChain(cfg, name, false, map[string]bool{})
+}
+
+// resolveThemeChain resolves one link of an extends chain, tracking visited themes in seen
+func resolveThemeChain(cfg *Config, name string, builtinOnly bool, seen map[string]bool) (ThemeColors, bool, error) {
+	theme, exists := cfg.Themes[name]
+	seenKey := name
+	if builtinOnly || !exists {
+		theme, exists = builtinThemes[name]
+		seenKey = "builtin:" + name
+	}
+	if !exists {
+		return ThemeColors{}, false, nil
+	}
+	if seen[seenKey] {
+		return theme, true, fmt.Errorf("theme '%s': extends cycle detected", name)
+	}
+	seen[seenKey] = true
+
+	if theme.Extends == "" {
+		return theme, true, nil
+	}
+	parent, found, err := resolveThemeChain(cfg, theme.Extends, theme.Extends == name, seen)
+	if !found {
+		return theme.inheritFrom(ThemeColors{}), true, fmt.Errorf("theme '%s': extends unknown theme '%s'", name, theme.Extends)
+	}
+	return theme.inheritFrom(parent), true, err
+}
+
+// builtinThemes are selectable by name without defining colors in config
+var builtinThemes = map[string]ThemeColors{
+	"classic-dos-blue": {
+		Background:  "navy",
+		Text:        "silver",
+		Border:      "white",
+		HighlightBg: "teal",
+		HighlightFg: "white",
+		Hotkey:      "yellow",
+		Shadow:      "black",
+		Disabled:    "gray",
+		MenuBg:      "blue",
+	},
+	"green-phosphor": {
+		Background:  "black",
+		Text:        "#33ff33",
+		Border:      "#33ff33",
+		HighlightBg: "#33ff33",
+		HighlightFg: "black",
+		Hotkey:      "#ccffcc",
+		Shadow:      "black",
+		Disabled:    "#1a7f1a",
+		MenuBg:      "black",
+	},
+	"amber-crt": {
+		Background:  "black",
+		Text:        "#ffb000",
+		Border:      "#ffb000",
+		HighlightBg: "#ffb000",
+		HighlightFg: "black",
+		Hotkey:      "#ffe0a0",
+		Shadow:      "black",
+		Disabled:    "#805800",
+		MenuBg:      "black",
+	},
+	"solarized-dark": {
+		Background:  "#002b36",
+		Text:        "#839496",
+		Border:      "#268bd2",
+		HighlightBg: "#268bd2",
+		HighlightFg: "#fdf6e3",
+		Hotkey:      "#b58900",
+		Shadow:      "black",
+		Disabled:    "#586e75",
+		MenuBg:      "#073642",
+	},
+	"high-contrast": {
+		Background:  "black",
+		Text:        "white",
+		Border:      "white",
+		HighlightBg: "white",
+		HighlightFg: "black",
+		Hotkey:      "yellow",
+		Shadow:      "gray",
+		Disabled:    "silver",
+		MenuBg:      "black",
+	},
+	// Okabe-Ito palettes: hotkeys and statuses stay distinct with red-green
+	// (deuteranopia, protanopia) and blue-yellow (tritanopia) color blindness
+	"colorblind-dark": {
+		Background:      "black",
+		Text:            "#e0e0e0",
+		Border:          "#56b4e9",
+		HighlightBg:     "#0072b2",
+		HighlightFg:     "white",
+		Hotkey:          "#e69f00",
+		Shadow:          "#444444",
+		Disabled:        "#8a8a8a",
+		MenuBg:          "#1c1c1c",
+		Success:         "#56b4e9",
+		Failure:         "#e69f00",
+		HotkeyUnderline: "true",
+	},
+	"colorblind-light": {
+		Background:      "white",
+		Text:            "black",
+		Border:          "#0072b2",
+		HighlightBg:     "#0072b2",
+		HighlightFg:     "white",
+		Hotkey:          "#d55e00",
+		Shadow:          "#767676",
+		Disabled:        "#767676",
+		MenuBg:          "#f0f0f0",
+		Success:         "#0072b2",
+		Failure:         "#d55e00",
+		HotkeyUnderline: "true",
+	},
+}
+
+// ThemeNames returns every selectable theme: themes defined in the config (sorted)
+// followed by built-in themes that are not overridden by the config.
+func ThemeNames(cfg *Config) []string {
+	var names []string
+	for name := range cfg.Themes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range BuiltinThemeNames() {
+		if _, overridden := cfg.Themes[name]; !overridden {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// BuiltinThemeNames returns the names of all built-in themes, sorted
+func BuiltinThemeNames() []string {
+	names := make([]string, 0, len(builtinThemes))
+	for name := range builtinThemes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// BuiltinTheme returns the built-in theme with the given name
+func BuiltinTheme(name string) (ThemeColors, bool) {
+	theme, ok := builtinThemes[name]
+	return theme, ok
+}
@@ -1,9 +1,17 @@
 package config
 
 import (
+	"errors"
+	"io"
 	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
 	"strings"
 	"testing"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
 )
 
 func containsAny(haystack []string, needle string) bool {
@@ -23,12 +31,16 @@ func TestValidateErrors(t *testing.T) {
 			{Type: "submenu", Label: "Sub", Target: ""},
 			{Type: "separator", Label: "-", Hotkey: "S"},
 			{Type: "weird"},
+			{Type: "group"},
+			{Type: "command", Label: "Psql", Exec: ExecConfig{Linux: "psql"}, Stdin: "file: "},
+			{Type: "edit_config"},
+			{Type: "edit_config", Label: "Edit Menu"},
 		},
 	}
 
 	errs := Validate(cfg)
-	if len(errs) != 5 {
-		t.Fatalf("expected 5 errors, got %d: %v", len(errs), errs)
+	if len(errs) != 8 {
+		t.Fatalf("expected 8 errors, got %d: %v", len(errs), errs)
 	}
 
 	expected := []string{
@@ -37,6 +49,9 @@ func TestValidateErrors(t *testing.T) {
 		"submenu missing target",
 		"separator must not have label or hotkey",
 		"unknown type",
+		"group missing label",
+		"stdin file path is empty",
+		"item 6: edit_config missing label",
 	}
 
 	for _, want := range expected {
@@ -271,3 +286,1907 @@ menus:
 	}
 }
 
+func TestResolveForOSFiltersAndOverrides(t *testing.T) {
+	cfg := &Config{
+		Title: "Root",
+		Items: []MenuItem{
+			{Type: "command", Label: "Everywhere", Exec: ExecConfig{Windows: "dir", Linux: "ls", Mac: "ls"}},
+			{Type: "command", Label: "Windows Only", OS: []string{"windows"}, Exec: ExecConfig{Windows: "dir"}},
+			{
+				Type:  "command",
+				Label: "Files",
+				Exec:  ExecConfig{Windows: "dir", Linux: "ls", Mac: "ls"},
+				Overrides: map[string]ItemOverride{
+					"mac":   {Label: "Finder Files", WorkDir: "/Users"},
+					"linux": {Hidden: true},
+				},
+			},
+		},
+		Menus: map[string]Menu{
+			"tools": {Title: "Tools", Items: []MenuItem{
+				{Type: "back", Label: "Back", OS: []string{"mac", "linux"}},
+			}},
+		},
+	}
+
+	ResolveForOS(cfg, "darwin")
+
+	if len(cfg.Items) != 2 {
+		t.Fatalf("expected 2 root items on mac, got %d", len(cfg.Items))
+	}
+	if cfg.Items[1].Label != "Finder Files" {
+		t.Errorf("expected mac label override, got %q", cfg.Items[1].Label)
+	}
+	if cfg.Items[1].Exec.WorkDir != "/Users" {
+		t.Errorf("expected mac workdir override, got %q", cfg.Items[1].Exec.WorkDir)
+	}
+	if len(cfg.Menus["tools"].Items) != 1 {
+		t.Errorf("expected submenu back item to remain on mac")
+	}
+}
+
+func TestParallelCommandsForOS(t *testing.T) {
+	cfg := &Config{
+		Title: "Root",
+		Items: []MenuItem{
+			{Type: "parallel", Label: "Dev Stack", Commands: []MenuItem{
+				{Type: "command", Label: "DB", Exec: ExecConfig{Linux: "pg_ctl start", Mac: "pg_ctl start"}},
+				{Type: "command", Label: "API", Exec: ExecConfig{Windows: "api.exe", Linux: "./api", Mac: "./api"},
+					Overrides: map[string]ItemOverride{"mac": {WorkDir: "/srv/api"}}},
+				{Type: "command", Label: "Tray", OS: []string{"windows"}, Exec: ExecConfig{Windows: "tray.exe"}},
+			}},
+		},
+	}
+
+	ResolveForOS(cfg, "darwin")
+
+	commands := cfg.Items[0].CommandsForOS("darwin")
+	if len(commands) != 2 || commands[0].Label != "DB" || commands[1].Label != "API" {
+		t.Fatalf("expected DB and API on mac, got %+v", commands)
+	}
+	if commands[1].Exec.WorkDir != "/srv/api" {
+		t.Errorf("expected the mac override applied to a parallel command, got %q", commands[1].Exec.WorkDir)
+	}
+	if got := (MenuItem{Type: "parallel", Commands: []MenuItem{{Type: "command", Exec: ExecConfig{Linux: "ls"}}}}).CommandsForOS("windows"); len(got) != 0 {
+		t.Errorf("expected no commands without a windows variant, got %+v", got)
+	}
+}
+
+func TestParallelCommandRefs(t *testing.T) {
+	cfg := &Config{
+		Items: []MenuItem{
+			{ID: "api", Type: "command", Label: "API", Exec: ExecConfig{Linux: "./api"}},
+			{Type: "parallel", Label: "Stack", Commands: []MenuItem{{Ref: "api"}}},
+		},
+	}
+	if err := ResolveRefs(cfg); err != nil {
+		t.Fatalf("ResolveRefs: %v", err)
+	}
+	if got := cfg.Items[1].Commands[0]; got.Type != "command" || got.Exec.Linux != "./api" {
+		t.Errorf("expected the parallel command to copy the referenced item, got %+v", got)
+	}
+}
+
+func TestTemplates(t *testing.T) {
+	cfg, err := parseYAML([]byte(`
+title: "Test"
+templates:
+  ssh_to: "ssh {user}@{host}"
+  tail_log:
+    windows: "Get-Content -Wait {file}"
+    linux: "tail -f {file} | grep \"${PATTERN}\""
+    workdir: "/var/log"
+items:
+  - label: "Web 1"
+    template: ssh_to
+    args: { user: admin, host: web1 }
+  - type: command
+    label: "Syslog"
+    template: tail_log
+    args: { file: syslog }
+    exec:
+      workdir: "/tmp"
+  - type: parallel
+    label: "Both"
+    commands:
+      - label: "Web 2"
+        template: ssh_to
+        args: { user: admin, host: web2 }
+`))
+	if err != nil {
+		t.Fatalf("parseYAML: %v", err)
+	}
+
+	web := cfg.Items[0]
+	if web.Type != "command" || web.Exec.Linux != "ssh admin@web1" || web.Exec.Windows != "ssh admin@web1" {
+		t.Errorf("expected a string template to become a command on every OS, got %+v", web)
+	}
+	syslog := cfg.Items[1].Exec
+	if syslog.Linux != `tail -f syslog | grep "${PATTERN}"` {
+		t.Errorf("expected {file} filled in and ${PATTERN} left to the shell, got %q", syslog.Linux)
+	}
+	if syslog.Mac != "" || syslog.WorkDir != "/tmp" {
+		t.Errorf("expected no mac variant and the item's own workdir, got %+v", syslog)
+	}
+	if got := cfg.Items[2].Commands[0].Exec.Linux; got != "ssh admin@web2" {
+		t.Errorf("expected templates in parallel commands, got %q", got)
+	}
+}
+
+func TestExecProgramArgs(t *testing.T) {
+	cfg, err := parseYAML([]byte(`
+title: "Test"
+templates:
+  open_in:
+    linux: { program: "/opt/Viewer/view", args: ["--file", "{file}"] }
+items:
+  - type: command
+    label: "Run"
+    exec:
+      linux:
+        program: "/opt/My App/run"
+        args: ["a b", "it's"]
+      windows: 'C:\Tools\run.exe'
+  - type: command
+    label: "Elevated"
+    run_as: root
+    exec:
+      linux: { program: "/usr/bin/apt", args: ["update"] }
+  - type: command
+    label: "Notes"
+    template: open_in
+    args: { file: "My Notes.txt" }
+`))
+	if err != nil {
+		t.Fatalf("parseYAML: %v", err)
+	}
+
+	run := cfg.Items[0]
+	want := []string{"/opt/My App/run", "a b", "it's"}
+	if got := run.ArgvForOS("linux"); !reflect.DeepEqual(got, want) {
+		t.Errorf("expected argv %q, got %q", want, got)
+	}
+	if got := run.Exec.Linux; got != `'/opt/My App/run' 'a b' 'it'\''s'` {
+		t.Errorf("expected the quoted command line for display, got %s", got)
+	}
+	if run.ArgvForOS("windows") != nil || run.Exec.Windows != `C:\Tools\run.exe` {
+		t.Errorf("expected a plain string variant to stay a shell command, got %+v", run.Exec)
+	}
+	if got := cfg.Items[1].ArgvForOS("linux"); got != nil {
+		t.Errorf("expected run_as items to run through the shell, got %q", got)
+	}
+	notes := []string{"/opt/Viewer/view", "--file", "My Notes.txt"}
+	if got := cfg.Items[2].ArgvForOS("linux"); !reflect.DeepEqual(got, notes) {
+		t.Errorf("expected template placeholders filled in each argument, got %q", got)
+	}
+
+	_, err = parseYAML([]byte(`
+title: "Test"
+items:
+  - type: command
+    label: "Broken"
+    exec:
+      linux: { args: ["x"] }
+`))
+	if err == nil || !strings.Contains(err.Error(), "program is required") {
+		t.Errorf("expected a missing program error, got %v", err)
+	}
+}
+
+func TestTemplateErrors(t *testing.T) {
+	templates := map[string]Template{"ssh_to": {Linux: "ssh {host}"}}
+	tests := []struct {
+		name string
+		item MenuItem
+		want string
+	}{
+		{"unknown template", MenuItem{Template: "scp_to"}, "template 'scp_to' not found"},
+		{"missing argument", MenuItem{Template: "ssh_to"}, "missing argument 'host'"},
+		{"unknown argument", MenuItem{Template: "ssh_to", Args: map[string]string{"host": "a", "hots": "b"}}, "unknown argument 'hots'"},
+		{"args without template", MenuItem{Args: map[string]string{"host": "a"}}, "args without a template"},
+	}
+	for _, tt := range tests {
+		err := ResolveTemplates(&Config{Templates: templates, Items: []MenuItem{tt.item}})
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: expected error containing %q, got %v", tt.name, tt.want, err)
+		}
+	}
+}
+
+func TestValidateRunAs(t *testing.T) {
+	exec := ExecConfig{Linux: "systemctl restart kiosk"}
+	cfg := &Config{
+		Title: "Root",
+		Items: []MenuItem{
+			{Type: "command", Label: "Restart", Exec: exec, RunAs: "svc-kiosk"},
+			{Type: "command", Label: "Flag", Exec: exec, RunAs: "-s"},
+			{Type: "command", Label: "Spaces", Exec: exec, RunAs: "svc kiosk"},
+		},
+	}
+
+	errs := Validate(cfg)
+	if len(errs) != 2 || !containsAny(errs, "item 1: invalid run_as user '-s'") || !containsAny(errs, "item 2: invalid run_as user") {
+		t.Errorf("expected the flag-like and spaced users to be rejected, got %v", errs)
+	}
+}
+
+func TestQuitItem(t *testing.T) {
+	cfg, err := parseYAML([]byte(`
+title: "Test"
+items:
+  - type: quit
+  - type: quit
+    label: "Log Off"
+    confirm: true
+`))
+	if err != nil {
+		t.Fatalf("parseYAML: %v", err)
+	}
+	if cfg.Items[0].Label != DefaultQuitLabel || cfg.Items[1].Label != "Log Off" {
+		t.Errorf("expected the default label only where none is set, got %q and %q", cfg.Items[0].Label, cfg.Items[1].Label)
+	}
+	if errs := Validate(cfg); len(errs) != 0 {
+		t.Errorf("expected quit items to be valid, got %v", errs)
+	}
+
+	if cfg.Items[0].NeedsConfirm() || !cfg.Items[1].NeedsConfirm() {
+		t.Errorf("expected only the second quit item to confirm")
+	}
+
+	yes := true
+	cfg.Items = append(cfg.Items, MenuItem{Type: "back", Label: "Back", Confirm: &yes})
+	if errs := Validate(cfg); !containsAny(errs, "item 2: confirm is only supported on command, parallel, and quit items") {
+		t.Errorf("expected confirm on a back item to be rejected, got %v", errs)
+	}
+}
+
+func TestItemDefaults(t *testing.T) {
+	cfg, err := parseYAML([]byte(`
+title: "Test"
+defaults:
+  confirm: true
+  timeout: 1m
+items:
+  - type: command
+    label: "Deploy"
+    exec: { linux: "./deploy.sh" }
+  - type: command
+    label: "Status"
+    exec: { linux: "./status.sh" }
+    confirm: false
+  - type: submenu
+    label: "Launchers"
+    target: launchers
+menus:
+  launchers:
+    title: "Quick Launchers"
+    defaults:
+      showOutput: false
+      launch: new-window
+      timeout: 10s
+    items:
+      - type: command
+        label: "Editor"
+        exec: { linux: "code" }
+      - type: command
+        label: "Top"
+        exec: { linux: "top" }
+        launch: interactive
+      - type: separator
+`))
+	if err != nil {
+		t.Fatalf("parseYAML: %v", err)
+	}
+
+	deploy, status := cfg.Items[0], cfg.Items[1]
+	if !deploy.NeedsConfirm() || deploy.Timeout != "1m" || deploy.ShowOutput != nil || deploy.Launch != "" {
+		t.Errorf("expected the config-wide defaults only, got %+v", deploy)
+	}
+	if status.NeedsConfirm() {
+		t.Error("expected an item's own confirm: false to win over the default")
+	}
+	editor, top := cfg.Menus["launchers"].Items[0], cfg.Menus["launchers"].Items[1]
+	if editor.ShowOutput == nil || *editor.ShowOutput || editor.Launch != "new-window" || editor.Timeout != "10s" || !editor.NeedsConfirm() {
+		t.Errorf("expected the menu's defaults over the config-wide ones, got %+v", editor)
+	}
+	if top.Launch != "interactive" {
+		t.Errorf("expected the item's own launch mode to be kept, got %q", top.Launch)
+	}
+	if d := editor.TimeoutDuration(); d != 10*time.Second {
+		t.Errorf("expected a 10s timeout, got %v", d)
+	}
+	if cfg.Menus["launchers"].Items[2].Launch != "" {
+		t.Error("expected separators to be left alone")
+	}
+}
+
+func TestValidateDefaults(t *testing.T) {
+	cfg, err := parseYAML([]byte(`
+title: "Test"
+defaults:
+  launch: sideways
+menus:
+  tools:
+    title: "Tools"
+    defaults:
+      timeout: soon
+    items:
+      - type: command
+        label: "Build"
+        exec: { linux: "make" }
+      - type: command
+        label: "Test"
+        exec: { linux: "make test" }
+        timeout: "-5s"
+      - type: back
+        label: "Back"
+        timeout: 5s
+items:
+  - type: command
+    label: "Run"
+    exec: { linux: "run" }
+`))
+	if err != nil {
+		t.Fatalf("parseYAML: %v", err)
+	}
+
+	errs := Validate(cfg)
+	for _, want := range []string{
+		"defaults: invalid launch mode 'sideways'",
+		"tools: defaults: invalid timeout 'soon'",
+		"tools: item 1: invalid timeout '-5s'",
+		"tools: item 2: timeout is only supported on command, parallel, and browse items",
+	} {
+		if !containsAny(errs, want) {
+			t.Errorf("expected error containing %q, got %v", want, errs)
+		}
+	}
+	if len(errs) != 4 {
+		t.Errorf("expected bad defaults to be reported once, not on every item, got %v", errs)
+	}
+}
+
+func TestValidateDisabledReason(t *testing.T) {
+	cfg := &Config{
+		Title: "Root",
+		Items: []MenuItem{
+			{Type: "command", Label: "VPN", Exec: ExecConfig{Linux: "vpn"}, Disabled: true, DisabledReason: "Requires VPN"},
+			{Type: "command", Label: "Stray", Exec: ExecConfig{Linux: "stray"}, DisabledReason: "Coming soon"},
+		},
+	}
+
+	errs := Validate(cfg)
+	if len(errs) != 1 || !containsAny(errs, "item 1: disabled_reason without disabled: true") {
+		t.Errorf("expected only the reason without disabled to be rejected, got %v", errs)
+	}
+}
+
+func TestAvailableAt(t *testing.T) {
+	// 2026-10-16 is a Friday
+	at := func(day int, clock string) time.Time {
+		tm, _ := time.Parse("15:04", clock)
+		return time.Date(2026, 10, day, tm.Hour(), tm.Minute(), 0, 0, time.Local)
+	}
+	tests := []struct {
+		name  string
+		item  MenuItem
+		at    time.Time
+		avail bool
+	}{
+		{"no schedule", MenuItem{}, at(16, "03:00"), true},
+		{"inside hours", MenuItem{AvailableHours: "16:00-20:00"}, at(16, "16:00"), true},
+		{"end is exclusive", MenuItem{AvailableHours: "16:00-20:00"}, at(16, "20:00"), false},
+		{"second range", MenuItem{AvailableHours: "08:00-12:00, 14:00-18:00"}, at(16, "15:30"), true},
+		{"until midnight", MenuItem{AvailableHours: "20:00-24:00"}, at(16, "23:59"), true},
+		{"weekdays", MenuItem{AvailableDays: "mon-fri"}, at(16, "12:00"), true},
+		{"weekend", MenuItem{AvailableDays: "Sat, Sunday"}, at(16, "12:00"), false},
+		{"range wraps the week", MenuItem{AvailableDays: "fri-mon"}, at(18, "12:00"), true},
+		{"past midnight on the next day", MenuItem{AvailableDays: "fri", AvailableHours: "22:00-02:00"}, at(17, "01:30"), true},
+		{"past midnight starts on a listed day", MenuItem{AvailableDays: "sat", AvailableHours: "22:00-02:00"}, at(17, "01:30"), false},
+		{"days and hours", MenuItem{AvailableDays: "sat-sun", AvailableHours: "10:00-18:00"}, at(17, "09:59"), false},
+		{"invalid schedule", MenuItem{AvailableHours: "soon"}, at(16, "12:00"), true},
+	}
+	for _, tt := range tests {
+		if got := tt.item.AvailableAt(tt.at); got != tt.avail {
+			t.Errorf("%s: expected available %v, got %v", tt.name, tt.avail, got)
+		}
+	}
+
+	item := MenuItem{AvailableDays: "sat-sun", AvailableHours: "10:00-18:00"}
+	if got := item.ScheduleText(); got != "sat-sun 10:00-18:00" {
+		t.Errorf("expected schedule text 'sat-sun 10:00-18:00', got %q", got)
+	}
+}
+
+func TestValidateSchedule(t *testing.T) {
+	cfg := &Config{
+		Title: "Root",
+		Items: []MenuItem{
+			{Type: "command", Label: "Games", Exec: ExecConfig{Linux: "games"}, AvailableHours: "16:00-20:00", AvailableDays: "mon-fri", DisabledReason: "After homework"},
+			{Type: "command", Label: "Bad hours", Exec: ExecConfig{Linux: "x"}, AvailableHours: "4pm-8pm"},
+			{Type: "command", Label: "Empty", Exec: ExecConfig{Linux: "x"}, AvailableHours: "10:00-10:00"},
+			{Type: "command", Label: "Bad days", Exec: ExecConfig{Linux: "x"}, AvailableDays: "weekdays"},
+			{Type: "separator", AvailableDays: "mon"},
+		},
+	}
+
+	errs := Validate(cfg)
+	for _, want := range []string{
+		"item 1: invalid available_hours: '4pm' is not a time",
+		"item 2: invalid available_hours: '10:00-10:00' is empty",
+		"item 3: invalid available_days: 'weekdays' is not a day",
+		"item 4: separators can't have available_hours or available_days",
+	} {
+		if !containsAny(errs, want) {
+			t.Errorf("expected error %q, got %v", want, errs)
+		}
+	}
+	if len(errs) != 4 {
+		t.Errorf("expected 4 errors, got %v", errs)
+	}
+}
+
+func TestValidateRoles(t *testing.T) {
+	cfg := &Config{
+		Title:       "Root",
+		Roles:       map[string]Role{"kids": {}},
+		DefaultRole: "guest",
+		Items: []MenuItem{
+			{Type: "command", Label: "Games", Exec: ExecConfig{Linux: "games"}, Roles: []string{"kids"}},
+			{Type: "command", Label: "Admin", Exec: ExecConfig{Linux: "admin"}, Roles: []string{"parent"}},
+			{Type: "switch_role", Label: "Switch"},
+		},
+	}
+
+	errs := Validate(cfg)
+	for _, want := range []string{
+		"item 1: unknown role 'parent'",
+		"item 2: switch_role needs at least two roles to switch between",
+		"default_role: unknown role 'guest'",
+	} {
+		if !containsAny(errs, want) {
+			t.Errorf("expected error %q, got %v", want, errs)
+		}
+	}
+	if len(errs) != 3 {
+		t.Errorf("expected 3 errors, got %v", errs)
+	}
+
+	cfg.Roles["parent"] = Role{Label: "Mum & Dad"}
+	if got := cfg.RoleNames(); len(got) != 2 || got[0] != "kids" || got[1] != "parent" {
+		t.Errorf("expected sorted role names, got %v", got)
+	}
+	if got := cfg.RoleLabel("parent"); got != "Mum & Dad" {
+		t.Errorf("expected the role's label, got %q", got)
+	}
+	if got := cfg.RoleLabel("kids"); got != "kids" {
+		t.Errorf("expected the role's name without a label, got %q", got)
+	}
+}
+
+func TestValidateURL(t *testing.T) {
+	cfg := &Config{
+		Title: "Root",
+		Items: []MenuItem{
+			{Type: "url", Label: "Docs", URL: "https://example.com/docs?q=a&b=c"},
+			{Type: "url", Label: "Mail", URL: "mailto:help@example.com"},
+			{Type: "url", Label: "Nowhere"},
+			{Type: "url", Label: "Relative", URL: "example.com"},
+			{Type: "url", URL: "https://example.com/has space"},
+		},
+	}
+
+	errs := Validate(cfg)
+	for _, want := range []string{
+		"item 2: url missing url",
+		"item 3: invalid url 'example.com'",
+		"item 4: url missing label",
+		"item 4: invalid url 'https://example.com/has space'",
+	} {
+		if !containsAny(errs, want) {
+			t.Errorf("expected error %q, got %v", want, errs)
+		}
+	}
+	if len(errs) != 4 {
+		t.Errorf("expected 4 errors, got %v", errs)
+	}
+}
+
+func TestValidateBrowse(t *testing.T) {
+	cfg := &Config{
+		Title: "Root",
+		Items: []MenuItem{
+			{Type: "browse", Label: "ROMs", Path: "~/roms", Exec: ExecConfig{Linux: "mednafen {file}"}, Extensions: []string{".nes", "sfc"}},
+			{Type: "browse", Label: "No Path", Exec: ExecConfig{Linux: "mednafen {file}"}},
+			{Type: "browse", Label: "No Exec", Path: "/srv"},
+			{Type: "browse", Label: "No File", Path: "/srv", Exec: ExecConfig{Linux: "mednafen ${file}"}},
+			{Type: "browse", Label: "Bad Extension", Path: "/srv", Exec: ExecConfig{Linux: "less {file}"}, Extensions: []string{"."}},
+		},
+	}
+
+	errs := Validate(cfg)
+	for _, want := range []string{
+		"item 1: browse missing path",
+		"item 2: browse missing exec variant",
+		"item 3: browse exec.linux doesn't use {file}",
+		"item 4: invalid extension '.'",
+	} {
+		if !containsAny(errs, want) {
+			t.Errorf("expected error %q, got %v", want, errs)
+		}
+	}
+	if len(errs) != 4 {
+		t.Errorf("expected 4 errors, got %v", errs)
+	}
+}
+
+func TestBrowseWithFile(t *testing.T) {
+	cfg, err := parseYAML([]byte(`
+title: "Test"
+templates:
+  emulator: "{emu} -fullscreen {file}"
+items:
+  - type: browse
+    label: "NES"
+    path: "/roms/nes"
+    extensions: [nes]
+    template: emulator
+    args: { emu: fceux }
+  - type: browse
+    label: "SNES"
+    path: "/roms/snes"
+    exec:
+      windows: { program: 'C:\Emu\snes9x.exe', args: ["{file}"] }
+      linux: "snes9x {file} && echo ${file}"
+`))
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if errs := Validate(cfg); len(errs) > 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+
+	nes := cfg.Items[0]
+	if nes.Type != "browse" || !nes.ListsFile("Zelda.NES") || nes.ListsFile("Zelda.sav") {
+		t.Errorf("expected a browse item listing .nes files, got %+v", nes)
+	}
+	command := nes.WithFile("/roms/nes/Super Mario.nes")
+	if command.Type != "command" || command.Exec.Linux != "fceux -fullscreen '/roms/nes/Super Mario.nes'" {
+		t.Errorf("unexpected command %+v", command)
+	}
+
+	snes := cfg.Items[1].WithFile(`C:\ROMs\Star Fox.sfc`)
+	if got := snes.Exec.ArgvForOS("windows"); len(got) != 2 || got[1] != `C:\ROMs\Star Fox.sfc` {
+		t.Errorf("expected the file as the program's argument, got %q", got)
+	}
+	if want := "snes9x 'C:\\ROMs\\Star Fox.sfc' && echo ${file}"; snes.Exec.Linux != want {
+		t.Errorf("expected %q, got %q", want, snes.Exec.Linux)
+	}
+}
+
+func TestValidateParallel(t *testing.T) {
+	cfg := &Config{
+		Title: "Root",
+		Items: []MenuItem{
+			{Type: "parallel"},
+			{Type: "parallel", Label: "Stack", Commands: []MenuItem{
+				{Type: "submenu", Label: "Tools", Target: "tools"},
+				{Type: "command", Label: "DB"},
+				{Type: "command", Label: "Psql", Exec: ExecConfig{Linux: "psql"}, Stdin: StdinPrompt, Launch: "new-window"},
+			}},
+		},
+	}
+
+	errs := Validate(cfg)
+	for _, want := range []string{
+		"item 0: parallel missing label",
+		"item 0: parallel has no commands",
+		"item 1, command 0: type must be command",
+		"item 1, command 1: command missing exec variant",
+		"item 1, command 2: stdin cannot be prompt",
+		"item 1, command 2: commands in a parallel item always run inline",
+	} {
+		if !containsAny(errs, want) {
+			t.Errorf("expected error containing %q, got %v", want, errs)
+		}
+	}
+}
+
+func TestMenuItemVisibleOn(t *testing.T) {
+	item := MenuItem{Type: "command", Label: "X", OS: []string{"linux", "mac"},
+		Overrides: map[string]ItemOverride{"linux": {Hidden: true}}}
+
+	if item.VisibleOn("windows") {
+		t.Error("expected item hidden on windows (not in os list)")
+	}
+	if item.VisibleOn("linux") {
+		t.Error("expected item hidden on linux (override hidden)")
+	}
+	if !item.VisibleOn("darwin") {
+		t.Error("expected item visible on darwin")
+	}
+}
+
+func TestValidateUnknownOS(t *testing.T) {
+	cfg := &Config{
+		Title: "Root",
+		Items: []MenuItem{
+			{Type: "back", Label: "Quit", OS: []string{"beos"},
+				Overrides: map[string]ItemOverride{"amiga": {Label: "Exit"}}},
+		},
+	}
+
+	errs := Validate(cfg)
+	if !containsAny(errs, "unknown os 'beos'") {
+		t.Errorf("expected unknown os error, got %v", errs)
+	}
+	if !containsAny(errs, "unknown override os 'amiga'") {
+		t.Errorf("expected unknown override os error, got %v", errs)
+	}
+}
+
+func TestLoadResolvesItemRefs(t *testing.T) {
+	yamlData := `
+title: "Test"
+items:
+  - ref: open_logs
+    hotkey: "L"
+  - type: submenu
+    label: "Favorites"
+    target: favs
+menus:
+  favs:
+    title: "Favorites"
+    items:
+      - id: open_logs
+        type: command
+        label: "Open Logs"
+        help: "Shows the log directory"
+        exec:
+          windows: "dir logs"
+          linux: "ls logs"
+          mac: "ls logs"
+      - ref: open_logs
+        label: "Logs Again"
+`
+	dir := t.TempDir()
+	path := dir + "/config.yaml"
+	if err := os.WriteFile(path, []byte(yamlData), 0644); err != nil {
+		t.Fatalf("failed to write test config: %v", err)
+	}
+
+	cfg, _, err := Load(path)
+	if err != nil {
+		t.Fatalf("failed to load config: %v", err)
+	}
+
+	root := cfg.Items[0]
+	if root.Type != "command" || root.Label != "Open Logs" || root.Hotkey != "L" {
+		t.Errorf("unexpected resolved root item: %+v", root)
+	}
+	if root.Exec.Linux != "ls logs" || root.Help != "Shows the log directory" {
+		t.Errorf("expected exec and help copied from referenced item, got %+v", root)
+	}
+	again := cfg.Menus["favs"].Items[1]
+	if again.Label != "Logs Again" || again.Ref != "" {
+		t.Errorf("expected label override and cleared ref, got %+v", again)
+	}
+}
+
+func TestResolveRefsKeepsReferencingFields(t *testing.T) {
+	yes := true
+	no := false
+	target := MenuItem{
+		ID:    "deploy",
+		Type:  "command",
+		Label: "Deploy",
+		Exec:  ExecConfig{Windows: "deploy.cmd", Linux: "./deploy.sh", Mac: "./deploy.sh", WorkDir: "/srv"},
+	}
+	tests := []struct {
+		name  string
+		ref   MenuItem
+		check func(MenuItem) bool
+	}{
+		{"label", MenuItem{Label: "Ship"}, func(m MenuItem) bool { return m.Label == "Ship" }},
+		{"hotkey", MenuItem{Hotkey: "S"}, func(m MenuItem) bool { return m.Hotkey == "S" }},
+		{"help", MenuItem{Help: "Ships it"}, func(m MenuItem) bool { return m.Help == "Ships it" }},
+		{"showOutput", MenuItem{ShowOutput: &no}, func(m MenuItem) bool { return m.ShowOutput != nil && !*m.ShowOutput }},
+		{"os", MenuItem{OS: []string{"linux"}}, func(m MenuItem) bool { return len(m.OS) == 1 && m.OS[0] == "linux" }},
+		{"overrides", MenuItem{Overrides: map[string]ItemOverride{"linux": {Label: "L"}}}, func(m MenuItem) bool { return m.Overrides["linux"].Label == "L" }},
+		{"disabled", MenuItem{Disabled: true, DisabledReason: "Frozen"}, func(m MenuItem) bool { return m.Disabled && m.DisabledReason == "Frozen" }},
+		{"confirm", MenuItem{Confirm: &yes}, func(m MenuItem) bool { return m.Confirm != nil && *m.Confirm }},
+		{"timeout", MenuItem{Timeout: "5s"}, func(m MenuItem) bool { return m.Timeout == "5s" }},
+		{"launch", MenuItem{Launch: "new-window"}, func(m MenuItem) bool { return m.Launch == "new-window" }},
+		{"webhook", MenuItem{Webhook: "none"}, func(m MenuItem) bool { return m.Webhook == "none" }},
+		{"stdin", MenuItem{Stdin: "prompt"}, func(m MenuItem) bool { return m.Stdin == "prompt" }},
+		{"run_as", MenuItem{RunAs: "svc"}, func(m MenuItem) bool { return m.RunAs == "svc" }},
+		{"icon", MenuItem{Icon: "🚀"}, func(m MenuItem) bool { return m.Icon == "🚀" }},
+		{"badge", MenuItem{Badge: "beta"}, func(m MenuItem) bool { return m.Badge == "beta" }},
+		{"badge_exec", MenuItem{BadgeExec: ExecConfig{Linux: "git branch --show-current"}}, func(m MenuItem) bool {
+			return m.BadgeExec.Linux == "git branch --show-current" && m.Exec.Linux == "./deploy.sh"
+		}},
+		{"workdir only", MenuItem{Exec: ExecConfig{WorkDir: "/tmp"}}, func(m MenuItem) bool {
+			return m.Exec.WorkDir == "/tmp" && m.Exec.Linux == "./deploy.sh"
+		}},
+		{"one exec variant", MenuItem{Exec: ExecConfig{Linux: "./deploy.sh --fast"}}, func(m MenuItem) bool {
+			return m.Exec.Linux == "./deploy.sh --fast" && m.Exec.Windows == "deploy.cmd" && m.Exec.WorkDir == "/srv"
+		}},
+	}
+
+	for _, tt := range tests {
+		ref := tt.ref
+		ref.Ref = "deploy"
+		cfg := &Config{Items: []MenuItem{target, ref}}
+		if err := ResolveRefs(cfg); err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		got := cfg.Items[1]
+		if !tt.check(got) {
+			t.Errorf("%s: expected the referencing item's value kept, got %+v", tt.name, got)
+		}
+		if got.Type != "command" || got.Ref != "" {
+			t.Errorf("%s: expected a resolved command item, got %+v", tt.name, got)
+		}
+	}
+}
+
+func TestResolveRefsErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		items []MenuItem
+		want  string
+	}{
+		{"unknown", []MenuItem{{Ref: "missing"}}, "does not match any item id"},
+		{"duplicate", []MenuItem{{ID: "a", Type: "back", Label: "A"}, {ID: "a", Type: "back", Label: "B"}}, "duplicate item id"},
+		{"cycle", []MenuItem{{ID: "a", Ref: "b"}, {ID: "b", Ref: "a"}}, "reference cycle"},
+	}
+
+	for _, tt := range tests {
+		err := ResolveRefs(&Config{Items: tt.items})
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: expected error containing %q, got %v", tt.name, tt.want, err)
+		}
+	}
+}
+
+func TestResolvePath(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("XDG_CONFIG_HOME only applies on Linux")
+	}
+	xdg := t.TempDir()
+	exeDir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", xdg)
+
+	userPath := filepath.Join(xdg, "menuworks", DefaultFileName)
+	portablePath := filepath.Join(exeDir, DefaultFileName)
+
+	// Nothing exists yet: create in the user config directory
+	if got := ResolvePath(exeDir, false); got != userPath {
+		t.Errorf("expected %s when no config exists, got %s", userPath, got)
+	}
+
+	// Portable mode always uses the binary directory
+	if got := ResolvePath(exeDir, true); got != portablePath {
+		t.Errorf("expected %s in portable mode, got %s", portablePath, got)
+	}
+
+	// An existing exe-adjacent config is used as a fallback
+	if err := os.WriteFile(portablePath, []byte("title: x\n"), 0644); err != nil {
+		t.Fatalf("failed to write portable config: %v", err)
+	}
+	if got := ResolvePath(exeDir, false); got != portablePath {
+		t.Errorf("expected portable fallback %s, got %s", portablePath, got)
+	}
+
+	// A user config takes priority over the exe-adjacent one
+	if err := WriteDefault(userPath); err != nil {
+		t.Fatalf("failed to write user config: %v", err)
+	}
+	if got := ResolvePath(exeDir, false); got != userPath {
+		t.Errorf("expected user config %s to win, got %s", userPath, got)
+	}
+}
+
+func TestListProfiles(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"work.yaml", "config.yaml", "games.yml", "config.yaml.bak", "notes.txt"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("title: x\n"), 0644); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+	}
+	if err := os.Mkdir(filepath.Join(dir, "kids.yaml"), 0755); err != nil {
+		t.Fatalf("failed to create dir: %v", err)
+	}
+
+	profiles, err := ListProfiles(dir)
+	if err != nil {
+		t.Fatalf("ListProfiles failed: %v", err)
+	}
+	expected := []string{"config", "games", "work"}
+	if strings.Join(profiles, ",") != strings.Join(expected, ",") {
+		t.Errorf("expected profiles %v, got %v", expected, profiles)
+	}
+
+	if got := ProfilePath(dir, "games"); got != filepath.Join(dir, "games.yml") {
+		t.Errorf("expected .yml profile path, got %s", got)
+	}
+	if got := ProfilePath(dir, "work"); got != filepath.Join(dir, "work.yaml") {
+		t.Errorf("expected .yaml profile path, got %s", got)
+	}
+	if got := ProfileName(filepath.Join(dir, "work.yaml")); got != "work" {
+		t.Errorf("expected profile name 'work', got %q", got)
+	}
+}
+
+func TestHotkeyConflicts(t *testing.T) {
+	cfg := &Config{
+		Title: "Root",
+		Items: []MenuItem{
+			{Type: "back", Label: "Save", Hotkey: "s"},
+			{Type: "back", Label: "Settings", Hotkey: "S"},
+			{Type: "back", Label: "Quit", Hotkey: "Q"},
+		},
+		Menus: map[string]Menu{
+			"tools": {Title: "Tools", Items: []MenuItem{
+				{Type: "back", Label: "One", Hotkey: "O"},
+				{Type: "back", Label: "Other", Hotkey: "o"},
+			}},
+		},
+	}
+
+	conflicts := HotkeyConflicts(cfg)
+	if len(conflicts) != 2 {
+		t.Fatalf("expected 2 conflicts, got %d: %v", len(conflicts), conflicts)
+	}
+	if !containsAny(conflicts, "item 1: hotkey 'S' already used by item 0 (Save)") {
+		t.Errorf("expected root conflict, got %v", conflicts)
+	}
+	if !containsAny(conflicts, "tools: item 1: hotkey 'O' already used by item 0 (One)") {
+		t.Errorf("expected submenu conflict, got %v", conflicts)
+	}
+	if !containsAny(Validate(cfg), "hotkey 'S' already used") {
+		t.Errorf("expected Validate to report hotkey conflicts")
+	}
+}
+
+func TestHotkeySequenceConflicts(t *testing.T) {
+	cfg := &Config{
+		Title: "Root",
+		Items: []MenuItem{
+			{Type: "back", Label: "Games", Hotkey: "g"},
+			{Type: "back", Label: "All Games", Hotkey: "ga"},
+			{Type: "back", Label: "Recent Games", Hotkey: "GR"},
+			{Type: "back", Label: "Reports", Hotkey: "rp"},
+			{Type: "back", Label: "Everything", Hotkey: "all"},
+		},
+	}
+
+	conflicts := HotkeyConflicts(cfg)
+	for _, want := range []string{
+		"item 0: hotkey 'G' starts item 1's sequence 'GA' (All Games), so it only runs after a pause",
+		"item 3: hotkey 'RP' starts with R, which reloads the config",
+		"item 4: hotkey 'ALL' is too long (use one key or a sequence of two)",
+	} {
+		if !containsAny(conflicts, want) {
+			t.Errorf("expected %q, got %v", want, conflicts)
+		}
+	}
+	if len(conflicts) != 3 {
+		t.Errorf("expected 3 conflicts, got %d: %v", len(conflicts), conflicts)
+	}
+}
+
+func TestSetItemHotkey(t *testing.T) {
+	yamlData := `title: "Test"
+# root items
+items:
+  - type: command
+    label: "Windows Only"
+    os: [windows]
+    exec:
+      windows: "dir"
+  - type: back
+    label: "Quit"
+menus:
+  tools:
+    title: "Tools"
+    items:
+      - type: back
+        label: "Back"
+        hotkey: "B"
+`
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(yamlData), 0644); err != nil {
+		t.Fatalf("failed to write test config: %v", err)
+	}
+
+	cfg, _, err := Load(path)
+	if err != nil {
+		t.Fatalf("failed to load config: %v", err)
+	}
+	quit := cfg.Items[len(cfg.Items)-1]
+	if err := SetItemHotkey(path, "root", quit, "X"); err != nil {
+		t.Fatalf("SetItemHotkey root failed: %v", err)
+	}
+	if err := SetItemHotkey(path, "tools", cfg.Menus["tools"].Items[0], "K"); err != nil {
+		t.Fatalf("SetItemHotkey tools failed: %v", err)
+	}
+
+	data, _ := os.ReadFile(path)
+	if !strings.Contains(string(data), "# root items") {
+		t.Errorf("expected comments to be preserved, got:\n%s", data)
+	}
+
+	reloaded, _, err := Load(path)
+	if err != nil {
+		t.Fatalf("failed to reload config: %v", err)
+	}
+	if got := reloaded.Items[len(reloaded.Items)-1].Hotkey; got != "X" {
+		t.Errorf("expected Quit hotkey X, got %q", got)
+	}
+	if got := reloaded.Menus["tools"].Items[0].Hotkey; got != "K" {
+		t.Errorf("expected Back hotkey K, got %q", got)
+	}
+}
+
+func TestParseColorNameExtended(t *testing.T) {
+	tests := []struct {
+		name  string
+		color tcell.Color
+		valid bool
+	}{
+		{"navy", tcell.ColorNavy, true},
+		{"#1e1e2e", tcell.NewRGBColor(0x1e, 0x1e, 0x2e), true},
+		{"#FFF", tcell.NewRGBColor(0xff, 0xff, 0xff), true},
+		{"color123", tcell.PaletteColor(123), true},
+		{"Color0", tcell.PaletteColor(0), true},
+		{"darkslategray", tcell.ColorDarkSlateGray, true},
+		{"#12345", tcell.ColorDefault, false},
+		{"#gggggg", tcell.ColorDefault, false},
+		{"color256", tcell.ColorDefault, false},
+		{"colorx", tcell.ColorDefault, false},
+		{"notacolor", tcell.ColorDefault, false},
+	}
+
+	for _, tt := range tests {
+		color, valid := ParseColorName(tt.name)
+		if valid != tt.valid || color != tt.color {
+			t.Errorf("ParseColorName(%q) = (%v, %v), expected (%v, %v)", tt.name, color, valid, tt.color, tt.valid)
+		}
+	}
+}
+
+func TestBuiltinThemes(t *testing.T) {
+	names := BuiltinThemeNames()
+	for _, want := range []string{"classic-dos-blue", "green-phosphor", "amber-crt", "solarized-dark", "high-contrast", "colorblind-dark", "colorblind-light"} {
+		found := false
+		for _, name := range names {
+			if name == want {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("expected built-in theme %q, got %v", want, names)
+		}
+	}
+
+	// Every built-in theme must pass validation with no warnings
+	for _, name := range names {
+		if warnings := ValidateTheme(&Config{Theme: name}); len(warnings) != 0 {
+			t.Errorf("built-in theme %q has warnings: %v", name, warnings)
+		}
+	}
+
+	// Built-in themes are selectable without a themes block
+	colors := GetThemeColors(&Config{Theme: "amber-crt"})
+	if colors == nil || colors.Text != "#ffb000" {
+		t.Fatalf("expected amber-crt built-in colors, got %+v", colors)
+	}
+
+	// Config themes override built-ins with the same name
+	cfg := &Config{Theme: "amber-crt", Themes: map[string]ThemeColors{"amber-crt": {Text: "red"}}}
+	if colors := GetThemeColors(cfg); colors == nil || colors.Text != "red" {
+		t.Errorf("expected config theme to override built-in, got %+v", colors)
+	}
+}
+
+func TestThemeNames(t *testing.T) {
+	cfg := &Config{Themes: map[string]ThemeColors{"zeta": {}, "alpha": {}, "amber-crt": {}}}
+	names := ThemeNames(cfg)
+
+	if names[0] != "alpha" || names[1] != "amber-crt" || names[2] != "zeta" {
+		t.Errorf("expected config themes first and sorted, got %v", names)
+	}
+	count := 0
+	for _, name := range names {
+		if name == "amber-crt" {
+			count++
+		}
+	}
+	if count != 1 {
+		t.Errorf("expected overridden built-in theme listed once, got %d", count)
+	}
+	if len(names) != 3+len(BuiltinThemeNames())-1 {
+		t.Errorf("unexpected theme count %d: %v", len(names), names)
+	}
+}
+
+func TestSetTheme(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte("title: \"Test\"\n# pick a theme\ntheme: \"dark\"\nitems: []\n"), 0644); err != nil {
+		t.Fatalf("failed to write test config: %v", err)
+	}
+
+	if err := SetTheme(path, "amber-crt"); err != nil {
+		t.Fatalf("SetTheme failed: %v", err)
+	}
+	cfg, _, err := Load(path)
+	if err != nil {
+		t.Fatalf("failed to reload config: %v", err)
+	}
+	if cfg.Theme != "amber-crt" {
+		t.Errorf("expected theme amber-crt, got %q", cfg.Theme)
+	}
+	data, _ := os.ReadFile(path)
+	if !strings.Contains(string(data), "# pick a theme") {
+		t.Errorf("expected comment to be preserved, got:\n%s", data)
+	}
+
+	// A config without a theme key gets one added
+	if err := os.WriteFile(path, []byte("title: \"Test\"\nitems: []\n"), 0644); err != nil {
+		t.Fatalf("failed to write test config: %v", err)
+	}
+	if err := SetTheme(path, "high-contrast"); err != nil {
+		t.Fatalf("SetTheme failed: %v", err)
+	}
+	if cfg, _, _ := Load(path); cfg == nil || cfg.Theme != "high-contrast" {
+		t.Errorf("expected theme key to be added")
+	}
+}
+
+func TestThemeExtends(t *testing.T) {
+	cfg := &Config{
+		Theme: "mine",
+		Themes: map[string]ThemeColors{
+			"dark": {Background: "black", Text: "silver", Border: "aqua", HighlightBg: "navy",
+				HighlightFg: "white", Hotkey: "yellow", Shadow: "gray", Disabled: "gray"},
+			"mine":  {Extends: "dark", Hotkey: "red"},
+			"child": {Extends: "mine", Border: "lime"},
+		},
+	}
+
+	colors := GetThemeColors(cfg)
+	if colors == nil {
+		t.Fatal("expected resolved theme")
+	}
+	if colors.Hotkey != "red" || colors.Background != "black" || colors.Extends != "" {
+		t.Errorf("unexpected resolved theme: %+v", colors)
+	}
+	if warnings := ValidateTheme(cfg); len(warnings) != 0 {
+		t.Errorf("expected no warnings for inherited theme, got %v", warnings)
+	}
+
+	cfg.Theme = "child"
+	if colors := GetThemeColors(cfg); colors.Border != "lime" || colors.Hotkey != "red" || colors.Text != "silver" {
+		t.Errorf("expected multi-level inheritance, got %+v", colors)
+	}
+}
+
+func TestThemeExtendsBuiltinAndErrors(t *testing.T) {
+	cfg := &Config{
+		Theme: "amber-crt",
+		Themes: map[string]ThemeColors{
+			"amber-crt": {Extends: "amber-crt", Hotkey: "white"},
+			"loop-a":    {Extends: "loop-b"},
+			"loop-b":    {Extends: "loop-a"},
+			"orphan":    {Extends: "nope", Text: "red"},
+		},
+	}
+
+	colors := GetThemeColors(cfg)
+	if colors == nil || colors.Hotkey != "white" || colors.Text != "#ffb000" {
+		t.Errorf("expected self-extend to inherit from built-in, got %+v", colors)
+	}
+
+	cfg.Theme = "loop-a"
+	if !containsAny(ValidateTheme(cfg), "extends cycle") {
+		t.Errorf("expected cycle warning, got %v", ValidateTheme(cfg))
+	}
+
+	cfg.Theme = "orphan"
+	if !containsAny(ValidateTheme(cfg), "extends unknown theme 'nope'") {
+		t.Errorf("expected unknown parent warning, got %v", ValidateTheme(cfg))
+	}
+}
+
+func TestThemeStyleAttributes(t *testing.T) {
+	for value, want := range map[string]bool{"true": true, "Yes": true, "on": true, "false": false, "off": false} {
+		if got, ok := ParseAttribute(value); !ok || got != want {
+			t.Errorf("ParseAttribute(%q) = %v, %v; want %v, true", value, got, ok, want)
+		}
+	}
+	if _, ok := ParseAttribute("sometimes"); ok {
+		t.Errorf("expected 'sometimes' to be rejected")
+	}
+
+	cfg := &Config{
+		Theme: "child",
+		Themes: map[string]ThemeColors{
+			"base":  {TitleBold: "true", HotkeyUnderline: "true"},
+			"child": {Extends: "base", HotkeyUnderline: "false", SeparatorDim: "maybe"},
+		},
+	}
+	colors := GetThemeColors(cfg)
+	if colors.TitleBold != "true" || colors.HotkeyUnderline != "false" {
+		t.Errorf("expected attributes to inherit, got %+v", colors)
+	}
+	if !containsAny(ValidateTheme(cfg), "invalid value 'maybe' for separator_dim") {
+		t.Errorf("expected invalid attribute warning, got %v", ValidateTheme(cfg))
+	}
+}
+
+func TestShadowAndTransparentConfig(t *testing.T) {
+	cfg := &Config{}
+	if !cfg.IsShadowEnabled() {
+		t.Errorf("expected shadow enabled by default when omitted")
+	}
+	if cfg.IsTransparentBackground() {
+		t.Errorf("expected transparent background off by default when omitted")
+	}
+
+	cfg, err := parseYAML([]byte("title: T\nitems: []\nshadow: false\ntransparent_background: true\n"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.IsShadowEnabled() {
+		t.Errorf("expected shadow disabled when set to false")
+	}
+	if !cfg.IsTransparentBackground() {
+		t.Errorf("expected transparent background when set to true")
+	}
+}
+
+func TestUpdateCheckIsOptIn(t *testing.T) {
+	if (&Config{}).IsUpdateCheckEnabled() {
+		t.Errorf("expected update check off by default when omitted")
+	}
+	cfg, err := parseYAML([]byte("title: T\nitems: []\nupdate_check: true\n"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !cfg.IsUpdateCheckEnabled() {
+		t.Errorf("expected update check enabled when set to true")
+	}
+}
+
+func TestRestorePositionIsOptIn(t *testing.T) {
+	if (&Config{}).IsRestorePositionEnabled() {
+		t.Errorf("expected restore position off by default when omitted")
+	}
+	cfg, err := parseYAML([]byte("title: T\nitems: []\nrestore_position: true\n"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !cfg.IsRestorePositionEnabled() {
+		t.Errorf("expected restore position enabled when set to true")
+	}
+}
+
+func TestAutoReloadIsOptIn(t *testing.T) {
+	if (&Config{}).IsAutoReloadEnabled() {
+		t.Errorf("expected auto reload off by default when omitted")
+	}
+	cfg, err := parseYAML([]byte("title: T\nitems: []\nauto_reload: true\n"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !cfg.IsAutoReloadEnabled() {
+		t.Errorf("expected auto reload enabled when set to true")
+	}
+}
+
+func TestCtrlCConfig(t *testing.T) {
+	defaults := &Config{}
+	if got := defaults.CtrlCMenuAction(); got != "quit" {
+		t.Errorf("expected Ctrl+C to quit by default, got %s", got)
+	}
+	if !defaults.IsCtrlCInterruptEnabled() {
+		t.Errorf("expected Ctrl+C to interrupt commands by default")
+	}
+
+	cfg, err := parseYAML([]byte("title: T\nitems: []\nctrl_c:\n  menu: confirm\n  interrupt_command: false\n"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := cfg.CtrlCMenuAction(); got != "confirm" {
+		t.Errorf("expected confirm, got %s", got)
+	}
+	if cfg.IsCtrlCInterruptEnabled() {
+		t.Errorf("expected interrupt_command: false to be honored")
+	}
+
+	cfg.CtrlC.Menu = "explode"
+	errs := Validate(cfg)
+	if len(errs) != 1 || !strings.Contains(errs[0], "ctrl_c") {
+		t.Errorf("expected one ctrl_c validation error, got %v", errs)
+	}
+}
+
+func TestParseKey(t *testing.T) {
+	cases := []struct {
+		value string
+		want  string
+		ok    bool
+	}{
+		{"r", "R", true},
+		{" 5 ", "5", true},
+		{"f6", "F6", true},
+		{"F12", "F12", true},
+		{"ctrl+r", "Ctrl+R", true},
+		{"F13", "", false},
+		{"F0", "", false},
+		{"Ctrl+1", "", false},
+		{"RR", "", false},
+		{"-", "", false},
+		{"", "", false},
+	}
+	for _, c := range cases {
+		got, ok := ParseKey(c.value)
+		if ok != c.ok || (ok && got != c.want) {
+			t.Errorf("ParseKey(%q) = %q, %v; expected %q, %v", c.value, got, ok, c.want, c.ok)
+		}
+	}
+}
+
+func TestReloadKeyBinding(t *testing.T) {
+	if got := (&Config{}).ReloadKey(); got != "R" {
+		t.Errorf("expected R to reload by default, got %s", got)
+	}
+
+	cfg, err := parseYAML([]byte("title: T\nitems:\n  - type: back\n    label: Reports\n    hotkey: r\n  - type: back\n    label: Fetch\n    hotkey: f\nkeybindings:\n  reload: f\n"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := cfg.ReloadKey(); got != "F" {
+		t.Errorf("expected F, got %s", got)
+	}
+	// R is an ordinary hotkey again, and F now belongs to reload
+	conflicts := HotkeyConflicts(cfg)
+	if len(conflicts) != 1 || conflicts[0] != "item 1: hotkey 'F' reloads the config instead (see keybindings)" {
+		t.Errorf("unexpected conflicts: %v", conflicts)
+	}
+
+	// A function key never clashes with a hotkey sequence spelled the same
+	cfg.KeyBindings.Reload = "F6"
+	cfg.Items[1].Hotkey = "f6"
+	if conflicts := HotkeyConflicts(cfg); len(conflicts) != 0 {
+		t.Errorf("unexpected conflicts: %v", conflicts)
+	}
+
+	for value, want := range map[string]string{
+		"F13":    "invalid key 'F13'",
+		"ctrl+c": "Ctrl+C is already used for quitting",
+		"f2":     "F2 is already used for help",
+	} {
+		cfg.KeyBindings.Reload = value
+		errs := validateKeyBindings(cfg.KeyBindings)
+		if len(errs) != 1 || !strings.Contains(errs[0], want) {
+			t.Errorf("reload: %s: expected %q, got %v", value, want, errs)
+		}
+		if got := cfg.ReloadKey(); value == "F13" && got != "R" {
+			t.Errorf("expected an invalid binding to fall back to R, got %s", got)
+		}
+	}
+}
+
+func TestBellConfig(t *testing.T) {
+	if got := (&Config{}).BellStyle(BellError); got != "" {
+		t.Errorf("expected bell off by default, got %q", got)
+	}
+
+	cfg, err := parseYAML([]byte("title: T\nitems: []\nbell:\n  style: visual\n  on_invalid_key: false\n"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := cfg.BellStyle(BellComplete); got != "visual" {
+		t.Errorf("expected visual bell on completion, got %q", got)
+	}
+	if got := cfg.BellStyle(BellInvalidKey); got != "" {
+		t.Errorf("expected on_invalid_key: false to silence the bell, got %q", got)
+	}
+
+	cfg.Bell.Style = "klaxon"
+	errs := Validate(cfg)
+	if len(errs) != 1 || !strings.Contains(errs[0], "bell") {
+		t.Errorf("expected one bell validation error, got %v", errs)
+	}
+}
+
+func TestLaunchFor(t *testing.T) {
+	item := MenuItem{Type: "command", Label: "Top", Exec: ExecConfig{Linux: "top"}}
+	cfg := &Config{}
+	if got := cfg.LaunchFor(item); got != "inline" {
+		t.Errorf("expected inline by default, got %s", got)
+	}
+	cfg.Launch = "new-window"
+	if got := cfg.LaunchFor(item); got != "new-window" {
+		t.Errorf("expected config default, got %s", got)
+	}
+	item.Launch = "tmux-pane"
+	if got := cfg.LaunchFor(item); got != "tmux-pane" {
+		t.Errorf("expected item setting to win, got %s", got)
+	}
+
+	item.Launch = "interactive"
+	cfg.Items = []MenuItem{item}
+	if errs := Validate(cfg); len(errs) != 0 {
+		t.Errorf("expected interactive to be a valid launch mode, got %v", errs)
+	}
+
+	item.Launch = "popup"
+	cfg.Items = []MenuItem{item}
+	errs := Validate(cfg)
+	if len(errs) != 1 || !strings.Contains(errs[0], "invalid launch mode 'popup'") {
+		t.Errorf("expected one launch validation error, got %v", errs)
+	}
+}
+
+func TestWebhookFor(t *testing.T) {
+	cfg := &Config{Webhook: "https://dash.example/hook"}
+	item := MenuItem{Type: "command", Label: "Backup"}
+	if got := cfg.WebhookFor(item); got != cfg.Webhook {
+		t.Errorf("expected the global webhook, got %q", got)
+	}
+	item.Webhook = "https://other.example/hook"
+	if got := cfg.WebhookFor(item); got != item.Webhook {
+		t.Errorf("expected the item webhook to win, got %q", got)
+	}
+	item.Webhook = "none"
+	if got := cfg.WebhookFor(item); got != "" {
+		t.Errorf("expected none to opt out, got %q", got)
+	}
+}
+
+func TestTitleBarConfig(t *testing.T) {
+	yamlData := `
+title: T
+items: []
+title_bar:
+  text: "Ops Console"
+  clock: false
+  date_format: "2006-01-02"
+  hostname: true
+`
+	cfg, err := parseYAML([]byte(yamlData))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	tb := cfg.TitleBar
+	if tb == nil || tb.Text != "Ops Console" || tb.DateFormat != "2006-01-02" || !tb.Hostname {
+		t.Fatalf("unexpected title bar: %+v", tb)
+	}
+	if tb.Clock == nil || *tb.Clock || tb.Date != nil {
+		t.Errorf("expected clock off and date unset, got %+v", tb)
+	}
+}
+
+func TestFooterConfig(t *testing.T) {
+	cfg := &Config{}
+	if !cfg.IsFooterEnabled() {
+		t.Errorf("expected footer enabled by default when omitted")
+	}
+
+	yamlData := `
+title: T
+footer: false
+footer_hint: "Pick a tool"
+items: []
+menus:
+  games:
+    title: Games
+    footer: "Saves live in ~/saves"
+    items: []
+`
+	cfg, err := parseYAML([]byte(yamlData))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.IsFooterEnabled() {
+		t.Errorf("expected footer disabled when set to false")
+	}
+	if cfg.FooterHint != "Pick a tool" || cfg.Menus["games"].Footer != "Saves live in ~/saves" {
+		t.Errorf("unexpected footer hints: %q / %q", cfg.FooterHint, cfg.Menus["games"].Footer)
+	}
+}
+
+func TestLayoutConfig(t *testing.T) {
+	tests := []struct {
+		value    string
+		size     int
+		full, ok bool
+	}{
+		{"70", 70, false, true},
+		{"full", 0, true, true},
+		{"FULL", 0, true, true},
+		{"0", 0, false, false},
+		{"wide", 0, false, false},
+	}
+	for _, tt := range tests {
+		size, full, ok := ParseLayoutSize(tt.value)
+		if size != tt.size || full != tt.full || ok != tt.ok {
+			t.Errorf("ParseLayoutSize(%q) = %d, %v, %v", tt.value, size, full, ok)
+		}
+	}
+
+	cfg, err := parseYAML([]byte("title: T\nitems: []\nlayout:\n  width: 70\n  height: full\n  align: top-left\n"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.Layout == nil || cfg.Layout.Width != "70" || cfg.Layout.Height != "full" || cfg.Layout.Align != "top-left" {
+		t.Fatalf("unexpected layout: %+v", cfg.Layout)
+	}
+	if errs := Validate(cfg); len(errs) != 0 {
+		t.Errorf("expected valid layout, got %v", errs)
+	}
+
+	cfg.Layout = &Layout{Width: "wide", Align: "middle"}
+	errs := Validate(cfg)
+	if !containsAny(errs, "layout: invalid width 'wide'") || !containsAny(errs, "layout: invalid align 'middle'") {
+		t.Errorf("expected layout errors, got %v", errs)
+	}
+}
+
+func TestLocaleValidation(t *testing.T) {
+	for _, locale := range []string{"", "en", "de", "de_DE.UTF-8"} {
+		if errs := Validate(&Config{Title: "T", Locale: locale}); len(errs) != 0 {
+			t.Errorf("expected locale %q to be valid, got %v", locale, errs)
+		}
+	}
+	errs := Validate(&Config{Title: "T", Locale: "xx"})
+	if len(errs) != 1 || !strings.Contains(errs[0], "locale") {
+		t.Errorf("expected one locale validation error, got %v", errs)
+	}
+}
+
+func TestAccessibilityConfig(t *testing.T) {
+	cfg := &Config{}
+	if cfg.IsAccessibilityEnabled() || cfg.IsHighContrastEnabled() || !cfg.IsDecorationEnabled() {
+		t.Errorf("expected accessibility off with decorations by default")
+	}
+
+	cfg, err := parseYAML([]byte("title: T\nitems: []\naccessibility:\n  enabled: true\n"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !cfg.IsAccessibilityEnabled() || !cfg.IsHighContrastEnabled() || cfg.IsDecorationEnabled() {
+		t.Errorf("expected high contrast without decorations when enabled")
+	}
+
+	cfg, err = parseYAML([]byte("title: T\nitems: []\naccessibility:\n  enabled: true\n  high_contrast: false\n  decorations: true\n"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.IsHighContrastEnabled() || !cfg.IsDecorationEnabled() {
+		t.Errorf("expected high_contrast and decorations to be overridable")
+	}
+}
+
+func TestStatusSymbols(t *testing.T) {
+	cfg := &Config{}
+	if cfg.IsStatusSymbolsEnabled() {
+		t.Errorf("expected status symbols off by default")
+	}
+	cfg.Accessibility = &Accessibility{Enabled: true}
+	if !cfg.IsStatusSymbolsEnabled() {
+		t.Errorf("expected status symbols on in the accessibility mode")
+	}
+	off := false
+	cfg.StatusSymbols = &off
+	if cfg.IsStatusSymbolsEnabled() {
+		t.Errorf("expected status_symbols: false to win")
+	}
+
+	colors := GetThemeColors(&Config{Theme: "colorblind-dark"})
+	if colors == nil || colors.Success == "" || colors.Failure == "" || colors.Success == colors.Failure {
+		t.Errorf("expected distinct status colors in colorblind-dark, got %+v", colors)
+	}
+}
+
+func TestDisabledNotesConfig(t *testing.T) {
+	data := "title: T\nitems: []\ndisabled_notes:\n  enabled: true\n  no_command: \"(Windows only)\"\n"
+	cfg, err := parseYAML([]byte(data))
+	if err != nil {
+		t.Fatalf("parseYAML: %v", err)
+	}
+	if !cfg.IsDisabledNotesEnabled() || cfg.DisabledNotes.NoCommand != "(Windows only)" {
+		t.Errorf("expected disabled notes enabled with custom text, got %+v", cfg.DisabledNotes)
+	}
+	if (&Config{}).IsDisabledNotesEnabled() {
+		t.Errorf("expected disabled notes off by default")
+	}
+}
+
+func TestMenuItemKey(t *testing.T) {
+	tests := []struct {
+		item MenuItem
+		want string
+	}{
+		{MenuItem{Label: "Portal 2"}, "portal-2"},
+		{MenuItem{Label: "  Edit config.yaml…"}, "edit-config-yaml"},
+		{MenuItem{Label: "Über Menü"}, "über-menü"},
+		{MenuItem{Label: "Portal 2", ID: "portal2"}, "portal2"},
+		{MenuItem{Type: "separator"}, ""},
+	}
+	for _, tt := range tests {
+		if got := tt.item.Key(); got != tt.want {
+			t.Errorf("Key() for %q = %q, want %q", tt.item.Label, got, tt.want)
+		}
+	}
+}
+
+func TestOpenStdin(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "input.sql")
+	if err := os.WriteFile(path, []byte("select 1;"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		stdin string
+		want  string
+		isNil bool
+	}{
+		{stdin: "", isNil: true},
+		{stdin: StdinPrompt, isNil: true},
+		{stdin: "apiVersion: v1\n", want: "apiVersion: v1\n"},
+		{stdin: "file:" + path, want: "select 1;"},
+	}
+	for _, tt := range tests {
+		r, err := MenuItem{Stdin: tt.stdin}.OpenStdin()
+		if err != nil {
+			t.Fatalf("OpenStdin(%q): %v", tt.stdin, err)
+		}
+		if tt.isNil {
+			if r != nil {
+				t.Errorf("OpenStdin(%q): expected no reader", tt.stdin)
+			}
+			continue
+		}
+		data, _ := io.ReadAll(r)
+		r.Close()
+		if string(data) != tt.want {
+			t.Errorf("OpenStdin(%q) = %q, want %q", tt.stdin, data, tt.want)
+		}
+	}
+
+	if _, err := (MenuItem{Stdin: "file:" + path + ".missing"}).OpenStdin(); err == nil {
+		t.Errorf("expected an error for a missing stdin file")
+	}
+
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+	if got, want := (MenuItem{Stdin: "file:~/q.sql"}).StdinFile(), filepath.Join(home, "q.sql"); got != want {
+		t.Errorf("StdinFile() = %q, want %q", got, want)
+	}
+	if got := (MenuItem{Stdin: "file"}).StdinFile(); got != "" {
+		t.Errorf("expected no file for literal text, got %q", got)
+	}
+}
+
+func TestWalk(t *testing.T) {
+	cfg := &Config{
+		Items: []MenuItem{
+			{Type: "submenu", Label: "Games", Target: "games"},
+			{Type: "separator"},
+			{Type: "submenu", Label: "Broken", Target: "missing"},
+			{Type: "back", Label: "Exit"},
+		},
+		Menus: map[string]Menu{
+			"games": {Items: []MenuItem{
+				{Type: "command", Label: "Portal"},
+				{Type: "submenu", Label: "Loop", Target: "games"},
+			}},
+			"orphan": {Items: []MenuItem{{Type: "command", Label: "Unreachable"}}},
+		},
+	}
+
+	var visited []string
+	cfg.Walk(func(path []string, item MenuItem) {
+		visited = append(visited, strings.Join(append(path, item.Label), "/"))
+	})
+	want := []string{"Games", "Games/Portal", "Games/Loop", "", "Broken", "Exit"}
+	if strings.Join(visited, ",") != strings.Join(want, ",") {
+		t.Errorf("expected %v, got %v", want, visited)
+	}
+}
+
+func TestMenuLock(t *testing.T) {
+	cfg, err := parseYAML([]byte(`
+title: "Test"
+items:
+  - type: submenu
+    label: "Admin"
+    target: admin
+  - type: submenu
+    label: "Tools"
+    target: tools
+menus:
+  admin:
+    title: "Admin"
+    lock:
+      auth: os
+      users: [root, alice]
+    items:
+      - type: submenu
+        label: "Logs"
+        target: logs
+  logs:
+    title: "Logs"
+    items: []
+  tools:
+    title: "Tools"
+    lock:
+      auth: pin
+    items: []
+`))
+	if err != nil {
+		t.Fatalf("parseYAML: %v", err)
+	}
+	if lock := cfg.Menus["admin"].Lock; lock == nil || lock.Auth != "os" || strings.Join(lock.Users, ",") != "root,alice" {
+		t.Errorf("expected admin's lock to be parsed, got %+v", lock)
+	}
+	errs := Validate(cfg)
+	if len(errs) != 1 || errs[0] != "tools: lock: invalid auth 'pin' (expected os)" {
+		t.Errorf("expected only tools' lock to be reported, got %v", errs)
+	}
+
+	// Logs is only reachable through the locked admin menu
+	if open := cfg.OpenMenus(); len(open) != 0 {
+		t.Errorf("expected no menus open without signing in, got %v", open)
+	}
+	cfg.Menus["tools"] = Menu{Title: "Tools", Items: []MenuItem{{Type: "submenu", Label: "Logs", Target: "logs"}}}
+	if open := cfg.OpenMenus(); len(open) != 2 || !open["tools"] || !open["logs"] {
+		t.Errorf("expected tools and logs to be open, got %v", open)
+	}
+}
+
+func TestSessionTimeout(t *testing.T) {
+	cfg := &Config{Title: "Root", SessionTimeout: "10m", SessionMenu: "public",
+		Menus: map[string]Menu{"public": {Title: "Public"}}}
+	if got := cfg.SessionTimeoutDuration(); got != 10*time.Minute {
+		t.Errorf("expected 10m, got %v", got)
+	}
+	if errs := Validate(cfg); len(errs) != 0 {
+		t.Errorf("expected no errors, got %v", errs)
+	}
+
+	cfg.SessionTimeout = "soon"
+	cfg.Menus["public"] = Menu{Title: "Public", Lock: &MenuLock{Auth: "os"}}
+	if got := cfg.SessionTimeoutDuration(); got != 0 {
+		t.Errorf("expected an invalid timeout to be off, got %v", got)
+	}
+	errs := Validate(cfg)
+	if !containsAny(errs, "session_timeout: invalid duration 'soon'") || !containsAny(errs, "session_menu: 'public' has a lock") {
+		t.Errorf("expected timeout and menu errors, got %v", errs)
+	}
+}
+
+func TestFallbackShellNeedsLock(t *testing.T) {
+	cfg := &Config{
+		Title: "Root",
+		Items: []MenuItem{{Type: "fallback_shell", Label: "Shell"}},
+		Menus: map[string]Menu{
+			"admin": {Title: "Admin", Lock: &MenuLock{Auth: "os"}, Items: []MenuItem{{Type: "fallback_shell", Label: "Shell"}}},
+			"tools": {Title: "Tools", Items: []MenuItem{{Type: "fallback_shell", Label: "Shell"}}},
+		},
+	}
+	errs := Validate(cfg)
+	if len(errs) != 2 || !containsAny(errs, "item 0: fallback_shell must be in a menu with a lock") || !containsAny(errs, "tools: item 0: fallback_shell must be in a menu with a lock") {
+		t.Errorf("expected the root and tools shells to be refused, got %v", errs)
+	}
+}
+
+func TestValidateTitleFormat(t *testing.T) {
+	cfg := &Config{Title: "Root", TitleFormat: "{path} - {count} {items}"}
+	errs := Validate(cfg)
+	if len(errs) != 1 || !strings.Contains(errs[0], "{items}") {
+		t.Errorf("expected one error for {items}, got %v", errs)
+	}
+}
+
+func TestSingleInstanceModes(t *testing.T) {
+	cfg := &Config{Title: "Root"}
+	if got := cfg.SingleInstanceMode(); got != "off" {
+		t.Errorf("expected off by default, got %q", got)
+	}
+	cfg.SingleInstance = "exit"
+	if errs := Validate(cfg); len(errs) != 0 {
+		t.Errorf("expected no errors, got %v", errs)
+	}
+	cfg.SingleInstance = "focus"
+	if errs := Validate(cfg); !containsAny(errs, "single_instance: invalid value 'focus'") {
+		t.Errorf("expected an invalid single_instance error, got %v", errs)
+	}
+}
+
+func TestBigTitleModes(t *testing.T) {
+	cfg := &Config{Title: "Root", BigTitle: "both"}
+	if !cfg.BigTitleOnSplash() || !cfg.BigTitleOnMenu() {
+		t.Error("expected both to enable the splash and the menu")
+	}
+	if errs := Validate(cfg); len(errs) != 0 {
+		t.Errorf("expected no errors, got %v", errs)
+	}
+
+	cfg.BigTitle = "banner"
+	if errs := Validate(cfg); !containsAny(errs, "big_title: invalid value 'banner'") {
+		t.Errorf("expected an invalid big_title error, got %v", errs)
+	}
+}
+
+func TestItemIcons(t *testing.T) {
+	cfg := &Config{Title: "Root", Items: []MenuItem{
+		{Type: "command", Label: "Deploy", Icon: "🚀", Exec: ExecConfig{Linux: "deploy"}},
+		{Type: "command", Label: "Flag", Icon: "🇩🇪", Exec: ExecConfig{Linux: "flag"}},
+		{Type: "command", Label: "Word", Icon: "go", Exec: ExecConfig{Linux: "word"}},
+	}}
+	errs := Validate(cfg)
+	if len(errs) != 1 || !containsAny(errs, "item 2: icon 'go' must be a single character or emoji") {
+		t.Errorf("expected only the two-letter icon to be rejected, got %v", errs)
+	}
+
+	cfg = &Config{
+		Theme: "child",
+		Themes: map[string]ThemeColors{
+			"base":  {Icons: map[string]string{"submenu": "▸", "quit": "✖"}},
+			"child": {Extends: "base", Icons: map[string]string{"submenu": "📁", "widget": "*", "back": "<-"}},
+		},
+	}
+	colors := GetThemeColors(cfg)
+	if colors.Icons["submenu"] != "📁" || colors.Icons["quit"] != "✖" {
+		t.Errorf("expected icons to inherit per type, got %v", colors.Icons)
+	}
+	if cfg.Themes["child"].Icons["quit"] != "" {
+		t.Error("expected inheriting to leave the child theme unchanged")
+	}
+	warnings := ValidateTheme(cfg)
+	if !containsAny(warnings, "icons: unknown item type 'widget'") || !containsAny(warnings, "icon '<-' for back") {
+		t.Errorf("expected warnings for the unknown type and the long icon, got %v", warnings)
+	}
+}
+
+func TestItemBadges(t *testing.T) {
+	cfg, err := parseYAML([]byte(`title: T
+items:
+  - type: command
+    label: Open repo
+    badge: "?"
+    badge_exec:
+      linux: git branch --show-current
+      workdir: ~/repo
+    exec:
+      linux: code ~/repo
+`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	item := cfg.Items[0]
+	if item.Badge != "?" || !item.HasBadgeCommand() || item.BadgeExec.WorkDir != "~/repo" {
+		t.Errorf("unexpected badge fields: %+v", item)
+	}
+	if errs := Validate(cfg); len(errs) != 0 {
+		t.Errorf("expected no errors, got %v", errs)
+	}
+
+	cfg.Items = append(cfg.Items,
+		MenuItem{Type: "command", Label: "Two", Badge: "a\nb", Exec: ExecConfig{Linux: "true"}},
+		MenuItem{Type: "separator", Badge: "x"},
+	)
+	errs := Validate(cfg)
+	if !containsAny(errs, "item 1: badge must be a single line") || !containsAny(errs, "item 2: separators can't have a badge") {
+		t.Errorf("expected badge errors, got %v", errs)
+	}
+}
+
+func TestCompareConfigs(t *testing.T) {
+	old := &Config{
+		Title: "Root",
+		Items: []MenuItem{{Type: "submenu", Label: "Games", Target: "games"}, {Type: "quit"}},
+		Menus: map[string]Menu{
+			"games": {Title: "Games", Items: []MenuItem{{Type: "back", Label: "Back"}}},
+			"old":   {Title: "Old", Items: []MenuItem{{Type: "back", Label: "Back"}}},
+		},
+	}
+	new := &Config{
+		Title: "Root",
+		Items: []MenuItem{{Type: "submenu", Label: "Games", Target: "games"}, {Type: "separator"}, {Type: "quit"}},
+		Menus: map[string]Menu{
+			"games": {Title: "Games", Items: []MenuItem{
+				{Type: "back", Label: "Quake", Hotkey: "b"},
+				{Type: "back", Label: "Back", Hotkey: "b"},
+			}},
+			"tools": {Title: "Tools", Items: []MenuItem{{Type: "back", Label: "Back"}}},
+		},
+	}
+
+	c := CompareConfigs(old, new)
+	if strings.Join(c.MenusAdded, ",") != "tools" || strings.Join(c.MenusRemoved, ",") != "old" {
+		t.Errorf("unexpected menus added %v, removed %v", c.MenusAdded, c.MenusRemoved)
+	}
+	// A separator is not an item, so the root menu counts as unchanged
+	if len(c.Items) != 1 || c.Items[0] != (ItemCountChange{"games", 1, 2}) {
+		t.Errorf("unexpected item counts: %+v", c.Items)
+	}
+	if len(c.Warnings) != 1 || !strings.Contains(c.Warnings[0], "games: ") {
+		t.Errorf("expected the duplicate hotkey as a warning, got %v", c.Warnings)
+	}
+}
+
+func TestYAMLErrorContext(t *testing.T) {
+	_, err := parseYAML([]byte("title: T\nmouse_support: true\nitems:\n  - type: command\n    label: Top\n    showOutput: maybe\n    exec:\n      linux: top\n"))
+	var yamlErr *YAMLError
+	if !errors.As(err, &yamlErr) {
+		t.Fatalf("expected a YAMLError, got %v", err)
+	}
+	if yamlErr.Line != 6 || yamlErr.Column != 17 {
+		t.Errorf("expected line 6, column 17, got %d, %d", yamlErr.Line, yamlErr.Column)
+	}
+	want := []string{
+		"  4 |   - type: command",
+		"  5 |     label: Top",
+		"> 6 |     showOutput: maybe",
+		"    |                 ^",
+		"  7 |     exec:",
+		"  8 |       linux: top",
+	}
+	if got := yamlErr.Context(2); strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("context:\n%s\nexpected:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	// Syntax errors have a line but no column; tabs are shown
+	_, err = parseYAML([]byte("title: T\n\tfoo: b\n"))
+	if !errors.As(err, &yamlErr) || yamlErr.Line != 2 || yamlErr.Column != 0 {
+		t.Fatalf("expected line 2 without a column, got %v", err)
+	}
+	if got := yamlErr.Context(5); len(got) != 3 || got[1] != "> 2 | →foo: b" {
+		t.Errorf("unexpected context: %q", got)
+	}
+	if !strings.HasPrefix(err.Error(), "failed to parse YAML: yaml: line 2:") {
+		t.Errorf("expected the error text unchanged, got %q", err)
+	}
+}
+
+func TestWriteDefaultWithBackup(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte("title: [broken\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	backup, err := WriteDefaultWithBackup(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(backup, path+".broken-") {
+		t.Errorf("unexpected backup path %s", backup)
+	}
+	if data, _ := os.ReadFile(backup); string(data) != "title: [broken\n" {
+		t.Errorf("expected the broken config kept in the backup, got %q", data)
+	}
+	if _, _, err := Load(path); err != nil {
+		t.Errorf("expected the default config written, got %v", err)
+	}
+
+	// A second reset within the same second doesn't overwrite the first backup
+	os.WriteFile(path, []byte("title: [again\n"), 0644)
+	second, err := WriteDefaultWithBackup(path)
+	if err != nil || second == backup {
+		t.Errorf("expected a second backup, got %s, %v", second, err)
+	}
+	if data, _ := os.ReadFile(backup); string(data) != "title: [broken\n" {
+		t.Errorf("first backup changed: %q", data)
+	}
+
+	if backup, err := WriteDefaultWithBackup(filepath.Join(t.TempDir(), "new.yaml")); backup != "" || err != nil {
+		t.Errorf("expected no backup without a config, got %q, %v", backup, err)
+	}
+}
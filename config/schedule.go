@@ -0,0 +1,168 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// dayNames are the names available_days accepts, in time.Weekday order
+var dayNames = []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}
+
+// hourRange is one "HH:MM-HH:MM" window of available_hours, as minutes since
+// midnight. An end before the start runs past midnight into the next day.
+type hourRange struct {
+	start, end int
+}
+
+// contains reports whether minute (since midnight) falls in the range on
+// the day it starts
+func (r hourRange) contains(minute int) bool {
+	if r.start < r.end {
+		return minute >= r.start && minute < r.end
+	}
+	return minute >= r.start
+}
+
+// spill reports whether minute falls in the part of a range that runs past
+// midnight from the day before
+func (r hourRange) spill(minute int) bool {
+	return r.start >= r.end && minute < r.end
+}
+
+// parseHours parses available_hours: comma-separated "HH:MM-HH:MM" ranges
+func parseHours(s string) ([]hourRange, error) {
+	var ranges []hourRange
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		from, to, ok := strings.Cut(part, "-")
+		if !ok {
+			return nil, fmt.Errorf("'%s' is not a range such as 16:00-20:00", part)
+		}
+		start, err := parseClock(strings.TrimSpace(from), false)
+		if err != nil {
+			return nil, err
+		}
+		end, err := parseClock(strings.TrimSpace(to), true)
+		if err != nil {
+			return nil, err
+		}
+		if start == end {
+			return nil, fmt.Errorf("'%s' is empty", part)
+		}
+		ranges = append(ranges, hourRange{start, end % (24 * 60)})
+	}
+	return ranges, nil
+}
+
+// parseClock parses "HH:MM" as minutes since midnight; "24:00" is allowed
+// as the end of a range
+func parseClock(s string, end bool) (int, error) {
+	if end && s == "24:00" {
+		return 24 * 60, nil
+	}
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, fmt.Errorf("'%s' is not a time such as 09:30", s)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// parseDays parses available_days: comma-separated day names ("mon") and
+// ranges of them ("mon-fri", "fri-mon")
+func parseDays(s string) ([7]bool, error) {
+	var days [7]bool
+	for _, part := range strings.Split(s, ",") {
+		part = strings.ToLower(strings.TrimSpace(part))
+		from, to, isRange := strings.Cut(part, "-")
+		first, ok := dayIndex(strings.TrimSpace(from))
+		last := first
+		if ok && isRange {
+			last, ok = dayIndex(strings.TrimSpace(to))
+		}
+		if !ok {
+			return days, fmt.Errorf("'%s' is not a day or range of days such as mon-fri", part)
+		}
+		for d := first; ; d = (d + 1) % 7 {
+			days[d] = true
+			if d == last {
+				break
+			}
+		}
+	}
+	return days, nil
+}
+
+// dayIndex returns the time.Weekday of a day name; the full name is
+// accepted too ("monday")
+func dayIndex(name string) (int, bool) {
+	for i, d := range dayNames {
+		if name == d || name == strings.ToLower(time.Weekday(i).String()) {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// HasSchedule reports whether the item sets available_hours or available_days
+func (item MenuItem) HasSchedule() bool {
+	return item.AvailableHours != "" || item.AvailableDays != ""
+}
+
+// AvailableAt reports whether the item can be selected at t, going by its
+// available_hours and available_days. A range of hours that runs past
+// midnight belongs to the day it starts on, so "fri" with "22:00-02:00"
+// covers the early hours of Saturday too. Items without a schedule, or with
+// one that doesn't parse (reported by Validate), are always available.
+func (item MenuItem) AvailableAt(t time.Time) bool {
+	if !item.HasSchedule() {
+		return true
+	}
+	days := [7]bool{true, true, true, true, true, true, true}
+	if item.AvailableDays != "" {
+		var err error
+		if days, err = parseDays(item.AvailableDays); err != nil {
+			return true
+		}
+	}
+	today, yesterday := int(t.Weekday()), (int(t.Weekday())+6)%7
+	if item.AvailableHours == "" {
+		return days[today]
+	}
+	ranges, err := parseHours(item.AvailableHours)
+	if err != nil {
+		return true
+	}
+	minute := t.Hour()*60 + t.Minute()
+	for _, r := range ranges {
+		if (days[today] && r.contains(minute)) || (days[yesterday] && r.spill(minute)) {
+			return true
+		}
+	}
+	return false
+}
+
+// ScheduleText describes when the item is available, as written in the
+// config ("mon-fri 16:00-20:00")
+func (item MenuItem) ScheduleText() string {
+	return strings.TrimSpace(strings.TrimSpace(item.AvailableDays) + " " + strings.TrimSpace(item.AvailableHours))
+}
+
+// validateSchedule checks an item's available_hours and available_days
+func validateSchedule(item MenuItem, index int) []string {
+	var errs []string
+	if item.HasSchedule() && (item.Type == "separator" || item.Type == "group") {
+		errs = append(errs, fmt.Sprintf("item %d: %ss can't have available_hours or available_days", index, item.Type))
+	}
+	if item.AvailableHours != "" {
+		if _, err := parseHours(item.AvailableHours); err != nil {
+			errs = append(errs, fmt.Sprintf("item %d: invalid available_hours: %v", index, err))
+		}
+	}
+	if item.AvailableDays != "" {
+		if _, err := parseDays(item.AvailableDays); err != nil {
+			errs = append(errs, fmt.Sprintf("item %d: invalid available_days: %v", index, err))
+		}
+	}
+	return errs
+}
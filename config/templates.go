@@ -0,0 +1,143 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Templates are reusable commands with {name} placeholders, defined once under
+// templates: and used by items with template: and args:. They are expanded
+// when the config is loaded, before refs, so afterwards the items that use
+// them are ordinary commands.
+
+// Template is a command with placeholders: either one command for every OS,
+// or exec-style per-OS variants with an optional workdir
+type Template ExecConfig
+
+// UnmarshalYAML accepts a plain string as the same command on every OS
+func (t *Template) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.ScalarNode {
+		*t = Template{Windows: value.Value, Linux: value.Value, Mac: value.Value}
+		return nil
+	}
+	return value.Decode((*ExecConfig)(t))
+}
+
+// placeholder matches {name} in a template. ${name} is matched too so it can
+// be left to the shell.
+var placeholder = regexp.MustCompile(`\$?\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// ResolveTemplates fills in the exec of every item that uses a template, in
+// the root menu, every submenu, and parallel items' commands. The item's own
+// exec variants and workdir win over the template's.
+func ResolveTemplates(cfg *Config) error {
+	if err := resolveItemTemplates(cfg.Items, cfg.Templates); err != nil {
+		return err
+	}
+	for name, m := range cfg.Menus {
+		if err := resolveItemTemplates(m.Items, cfg.Templates); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// resolveItemTemplates expands templates in a single list of items, in place
+func resolveItemTemplates(items []MenuItem, templates map[string]Template) error {
+	for i := range items {
+		item := &items[i]
+		if err := resolveItemTemplates(item.Commands, templates); err != nil {
+			return fmt.Errorf("item %d: %w", i, err)
+		}
+		if item.Template == "" {
+			if len(item.Args) > 0 {
+				return fmt.Errorf("item %d: args without a template", i)
+			}
+			continue
+		}
+		t, ok := templates[item.Template]
+		if !ok {
+			return fmt.Errorf("item %d: template '%s' not found", i, item.Template)
+		}
+		// A browse item's {file} is filled in with the picked file when it runs
+		var keep []string
+		if item.Type == "browse" {
+			keep = []string{browseFileName}
+		}
+		exec, err := t.expand(item.Args, keep)
+		if err != nil {
+			return fmt.Errorf("item %d: template '%s': %w", i, item.Template, err)
+		}
+		if item.Type == "" {
+			item.Type = "command"
+		}
+		for _, key := range []string{"windows", "linux", "mac"} {
+			if *item.Exec.variant(key) != "" {
+				continue
+			}
+			if argv := exec.Argv[key]; argv != nil {
+				item.Exec.setArgv(key, argv)
+			} else {
+				*item.Exec.variant(key) = *exec.variant(key)
+			}
+		}
+		if item.Exec.WorkDir == "" {
+			item.Exec.WorkDir = exec.WorkDir
+		}
+	}
+	return nil
+}
+
+// expand substitutes args for the template's placeholders. Every placeholder
+// needs an argument and every argument must be used, so typos are caught.
+// Placeholders named in keep are left for later unless args fill them.
+func (t Template) expand(args map[string]string, keep []string) (ExecConfig, error) {
+	used := make(map[string]bool, len(args))
+	var missing []string
+	fill := func(s string) string {
+		return placeholder.ReplaceAllStringFunc(s, func(m string) string {
+			if strings.HasPrefix(m, "$") {
+				return m
+			}
+			name := m[1 : len(m)-1]
+			value, ok := args[name]
+			if !ok && slices.Contains(keep, name) {
+				return m
+			}
+			if !ok {
+				missing = append(missing, name)
+				return m
+			}
+			used[name] = true
+			return value
+		})
+	}
+	exec := ExecConfig{Windows: fill(t.Windows), Linux: fill(t.Linux), Mac: fill(t.Mac), WorkDir: fill(t.WorkDir)}
+	for key, argv := range t.Argv {
+		filled := make([]string, len(argv))
+		for i, arg := range argv {
+			filled[i] = fill(arg)
+		}
+		exec.setArgv(key, filled)
+	}
+
+	if len(missing) > 0 {
+		return ExecConfig{}, fmt.Errorf("missing argument '%s'", missing[0])
+	}
+	var unused []string
+	for name := range args {
+		if !used[name] {
+			unused = append(unused, name)
+		}
+	}
+	if len(unused) > 0 {
+		sort.Strings(unused)
+		return ExecConfig{}, fmt.Errorf("unknown argument '%s'", unused[0])
+	}
+	return exec, nil
+}
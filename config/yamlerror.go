@@ -0,0 +1,89 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// YAMLError is a config file that isn't valid YAML or doesn't fit the config's
+// structure, with where yaml.v3 found the problem
+type YAMLError struct {
+	Line   int // 1-based; 0 if yaml.v3 didn't say
+	Column int // 1-based; 0 if unknown
+	err    error
+	lines  []string // the file's lines, for Context
+}
+
+func (e *YAMLError) Error() string { return e.err.Error() }
+
+func (e *YAMLError) Unwrap() error { return e.err }
+
+var (
+	// yamlLine finds the first line number in a yaml.v3 error
+	yamlLine = regexp.MustCompile(`line (\d+):`)
+	// yamlValue finds the value of an unmarshal error on that line; yaml.v3
+	// shortens long values to their first 7 bytes and "..."
+	yamlValue = regexp.MustCompile("line \\d+: cannot unmarshal !!\\w+ `([^`]*)`")
+)
+
+// newYAMLError wraps err, an error yaml.v3 returned for data, with its
+// position. Unmarshal errors name the value that didn't fit, which gives the
+// column; syntax errors only have a line.
+func newYAMLError(data []byte, err error) *YAMLError {
+	e := &YAMLError{err: err, lines: strings.Split(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n")}
+	m := yamlLine.FindStringSubmatch(err.Error())
+	if m == nil {
+		return e
+	}
+	e.Line, _ = strconv.Atoi(m[1])
+	if v := yamlValue.FindStringSubmatch(err.Error()); v != nil {
+		var doc yaml.Node
+		if yaml.Unmarshal(data, &doc) == nil {
+			e.Column = findScalarColumn(&doc, e.Line, strings.TrimSuffix(v[1], "..."))
+		}
+	}
+	return e
+}
+
+// findScalarColumn returns the column of the first scalar on line whose value
+// starts with prefix, or 0
+func findScalarColumn(node *yaml.Node, line int, prefix string) int {
+	if node.Kind == yaml.ScalarNode && node.Line == line && strings.HasPrefix(node.Value, prefix) {
+		return node.Column
+	}
+	for _, child := range node.Content {
+		if col := findScalarColumn(child, line, prefix); col > 0 {
+			return col
+		}
+	}
+	return 0
+}
+
+// Context returns the lines of the file from around lines before the error's
+// line to around lines after it, each prefixed with its number. The error's
+// line is marked with ">", and its column, when known, with a "^" on the line
+// below. It returns nil if the line is unknown.
+func (e *YAMLError) Context(around int) []string {
+	if e.Line < 1 || e.Line > len(e.lines) {
+		return nil
+	}
+	first, last := max(e.Line-around, 1), min(e.Line+around, len(e.lines))
+	width := len(strconv.Itoa(last))
+	var out []string
+	for n := first; n <= last; n++ {
+		marker := " "
+		if n == e.Line {
+			marker = ">"
+		}
+		text := strings.ReplaceAll(e.lines[n-1], "\t", "→")
+		out = append(out, fmt.Sprintf("%s %*d | %s", marker, width, n, text))
+		if n == e.Line && e.Column > 0 {
+			out = append(out, fmt.Sprintf("  %*s | %s^", width, "", strings.Repeat(" ", e.Column-1)))
+		}
+	}
+	return out
+}
@@ -0,0 +1,467 @@
+package menuworks
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+
+	"github.com/benworks/menuworks/config"
+	"github.com/benworks/menuworks/exec"
+	"github.com/benworks/menuworks/i18n"
+	"github.com/benworks/menuworks/logging"
+	"github.com/benworks/menuworks/ui"
+)
+
+// showMessage shows a message dialog and waits for it to close
+func (a *App) showMessage(title, message string) {
+	a.d.RunView(a.ctx, ui.NewMessageView(a.screen, title, message, a.d.Pop))
+}
+
+// showError shows a single-button error dialog and waits for it to close
+func (a *App) showError(title, message string) {
+	a.bell(config.BellError)
+	a.d.RunView(a.ctx, ui.NewMessageView(a.screen, title, message, a.d.Pop))
+}
+
+// confirmRun calls run, first asking whether to go ahead if the item wants confirmation
+func (a *App) confirmRun(item config.MenuItem, run func()) {
+	if !item.NeedsConfirm() {
+		run()
+		return
+	}
+	a.d.Push(ui.NewDialogView(a.screen, i18n.T("Confirm"), i18n.Tf("Run %s?", item.Label), []string{i18n.T("No"), i18n.T("Yes")}, func(choice int) {
+		a.d.Pop()
+		if choice == 1 {
+			run()
+		}
+	}).WithSize(50, 8))
+}
+
+// showCommandPreview shows what running a command item would do, without running it
+func (a *App) showCommandPreview(item config.MenuItem) {
+	osType := exec.GetOS()
+	command := item.Exec.CommandForOS(osType)
+	if command == "" {
+		a.d.Push(ui.NewMessageView(a.screen, i18n.T("Command Preview"), i18n.T("(No command defined for this platform)"), a.d.Pop))
+		return
+	}
+
+	variant := osType
+	if variant == "darwin" {
+		variant = "mac"
+	}
+	mode := a.cfg.LaunchFor(item)
+	command, workDir, err := exec.RunAs(command, item.Exec.WorkDir, item.RunAs, mode != "inline")
+	if err != nil {
+		a.d.Push(ui.NewMessageView(a.screen, i18n.T("Command Preview"), err.Error(), a.d.Pop))
+		return
+	}
+	dir := exec.ResolveWorkDir(command, workDir)
+	if dir == "" {
+		cwd, _ := os.Getwd()
+		dir = i18n.Tf("current directory (%s)", cwd)
+	}
+	var input string
+	switch {
+	case item.Stdin == "":
+		input = i18n.T("none")
+	case item.Stdin == config.StdinPrompt:
+		input = i18n.T("typed in when run")
+	case item.StdinFile() != "":
+		input = i18n.Tf("file %s", item.StdinFile())
+	default:
+		input = i18n.Tf("%d bytes of text", len(item.Stdin))
+	}
+
+	shell := exec.ShellArgs(command)
+	shellLine := strings.Join(shell[:len(shell)-1], " ")
+	if item.ArgvForOS(osType) != nil && (mode == "inline" || mode == "interactive") {
+		shellLine = i18n.T("none (the program is run directly)")
+	}
+	lines := []string{
+		i18n.T("Command:"),
+		command,
+		"",
+		i18n.Tf("Variant: exec.%s", variant),
+		i18n.Tf("Shell: %s", shellLine),
+		i18n.Tf("Directory: %s", dir),
+		i18n.Tf("Launch: %s", mode),
+		i18n.Tf("Input: %s", input),
+		i18n.T("Environment: inherited from MenuWorks"),
+	}
+	if item.RunAs != "" {
+		lines = append(lines, i18n.Tf("Run as: %s (sudo)", item.RunAs))
+	}
+	a.d.Push(ui.NewDialogView(a.screen, i18n.T("Command Preview"), strings.Join(lines, "\n"), []string{i18n.T("OK")}, func(int) { a.d.Pop() }).WithSize(70, 18))
+}
+
+// copyCommand puts a command item's command for this OS on the clipboard. The
+// terminal is asked to copy it (OSC 52), which works over SSH; on the local
+// terminal the platform's clipboard tool copies it too, for terminals that
+// ignore the request.
+func (a *App) copyCommand(item config.MenuItem) {
+	command := item.Exec.CommandForOS(exec.GetOS())
+	if command == "" {
+		a.bell(config.BellInvalidKey)
+		return
+	}
+
+	sent := a.screen.SetClipboard(command)
+	err := errors.New("clipboard: only available in the local terminal")
+	if a.Backend == nil {
+		err = exec.CopyToClipboard(command)
+	}
+	switch {
+	case err == nil:
+		a.d.Push(ui.NewMessageView(a.screen, i18n.T("Copied"), i18n.T("Command copied to the clipboard."), a.d.Pop))
+	case sent:
+		logging.Debug("clipboard tool unavailable, relying on OSC 52", "err", err)
+		a.d.Push(ui.NewMessageView(a.screen, i18n.T("Copied"), i18n.T("Command sent to the terminal's clipboard."), a.d.Pop))
+	default:
+		a.showError(i18n.T("Clipboard Error"), err.Error())
+	}
+}
+
+// ensureTerminalSize verifies terminal is at least 80x25 and waits until resized if too small
+func (a *App) ensureTerminalSize() {
+	if w, h := a.screen.Size(); w >= 80 && h >= 25 {
+		return // Terminal is large enough, proceed
+	}
+	a.d.RunView(a.ctx, &tooSmallView{a: a})
+}
+
+// tooSmallView is the "Terminal Too Small" pop-up; it closes once the terminal is large enough
+type tooSmallView struct {
+	a *App
+}
+
+// Draw renders the resize pop-up with the current size
+func (v *tooSmallView) Draw() {
+	screen := v.a.screen
+	w, h := screen.Size()
+
+	// Draw error pop-up
+	screen.Clear()
+	dialogWidth := 50
+	dialogHeight := 8
+	startX := (w - dialogWidth) / 2
+	if startX < 0 {
+		startX = 0
+	}
+	startY := (h - dialogHeight) / 2
+	if startY < 0 {
+		startY = 0
+	}
+
+	screen.DrawBorder(startX, startY, dialogWidth, dialogHeight, " "+i18n.T("Terminal Too Small")+" ")
+
+	// Draw message
+	msg := i18n.T("Please resize your terminal to at least 80×25")
+	msgX := startX + (dialogWidth-ui.StringWidth(msg))/2
+	if msgX < 0 {
+		msgX = 0
+	}
+	msgY := startY + 2
+	screen.DrawString(msgX, msgY, msg, screen.Theme().StyleNormal())
+
+	msg2 := i18n.Tf("Current size: %d×%d", w, h)
+	msg2X := startX + (dialogWidth-ui.StringWidth(msg2))/2
+	if msg2X < 0 {
+		msg2X = 0
+	}
+	screen.DrawString(msg2X, msgY+2, msg2, screen.Theme().StyleNormal())
+
+	screen.Show()
+}
+
+// HandleEvent quits on Escape, except in wrapper mode, and closes the pop-up
+// once the terminal is big enough
+func (v *tooSmallView) HandleEvent(ev tcell.Event) {
+	if keyEv, ok := ev.(*tcell.EventKey); ok && keyEv.Key() == tcell.KeyEscape && !v.a.wrapperMode() {
+		v.a.d.Stop()
+		return
+	}
+	if w, h := v.a.screen.Size(); w >= 80 && h >= 25 {
+		v.a.d.Pop()
+	}
+}
+
+// handleConfigError shows a dialog for config errors and reports whether to retry.
+// A YAML error shows the lines around it. For custom config paths the "Use
+// Default" option is hidden to prevent overwriting an unrelated config.yaml,
+// and in remote sessions "Open in Editor" is, since the editor would run on
+// this machine's terminal.
+func (a *App) handleConfigError(err error) bool {
+	buttons := []string{"Retry"}
+	if !a.Remote {
+		buttons = append(buttons, "Open in Editor")
+	}
+	if !a.customConfig {
+		buttons = append(buttons, "Use Default")
+	}
+	buttons = append(buttons, "Exit")
+
+	labels := make([]string, len(buttons))
+	for i, b := range buttons {
+		labels[i] = i18n.T(b)
+	}
+
+	message := i18n.Tf("Failed to load configuration.\nError:\n%v", err)
+	var context []string
+	var yamlErr *config.YAMLError
+	if errors.As(err, &yamlErr) {
+		context = yamlErr.Context(3)
+	}
+	for {
+		choice := 0
+		dialog := ui.NewDialogView(a.screen, i18n.T("Config Error"), message, labels, func(c int) {
+			choice = c
+			a.d.Pop()
+		}).WithSize(76, 14)
+		if len(context) > 0 {
+			dialog.WithCode(context).WithSize(76, 20)
+		}
+		a.d.RunView(a.ctx, dialog)
+		if a.d.Stopped() || a.ctx.Err() != nil {
+			return false
+		}
+
+		// Escape chooses the first button (Retry)
+		switch buttons[choice] {
+		case "Retry":
+			return true
+		case "Open in Editor":
+			a.editConfig()
+			return true
+		case "Use Default":
+			backup, err := config.WriteDefaultWithBackup(a.ConfigPath)
+			if err != nil {
+				a.showError(i18n.T("Backup Error"), i18n.Tf("Could not set the broken config aside: %v", err))
+				continue
+			}
+			if backup != "" {
+				a.showMessage(i18n.T("Config Updated"), i18n.Tf("Default config written. Your config was moved to %s, so nothing in it is lost.", backup))
+			}
+			return true
+		default:
+			return false
+		}
+	}
+}
+
+// editConfig opens the config file in the user's editor, handing it the
+// terminal until the editor exits
+func (a *App) editConfig() {
+	args, err := exec.EditorArgs(a.ConfigPath)
+	if err == nil {
+		_, err = exec.ExecuteInteractive(a.screen, strings.Join(args, " "), args, "", nil)
+	}
+	if err != nil {
+		a.showError(i18n.T("Editor Error"), err.Error())
+	}
+}
+
+// editAndReload opens the config file in the user's editor for an
+// edit_config item and reloads it once the editor exits. Remote sessions are
+// refused, since the editor would take over this machine's terminal.
+func (a *App) editAndReload() {
+	if a.Remote {
+		a.showError(i18n.T("Not Available"), i18n.T("The config can only be edited at this machine's terminal, not in a remote session."))
+		return
+	}
+	if a.ConfigPath == "" {
+		a.showError(i18n.T("Not Available"), i18n.T("This menu was not loaded from a config file."))
+		return
+	}
+	a.editConfig()
+	a.reload()
+}
+
+// showReloaded reports a successful reload with a summary of changes, and
+// offers the full list on a details screen
+func (a *App) showReloaded(changes config.Changes) {
+	message := i18n.T("Configuration reloaded successfully.") + "\n\n" + reloadSummary(changes)
+	choice := 0
+	a.d.RunView(a.ctx, ui.NewDialogView(a.screen, i18n.T("Config Reloaded"), message, []string{i18n.T("OK"), i18n.T("Details")}, func(c int) {
+		choice = c
+		a.d.Pop()
+	}))
+	if choice == 1 {
+		a.d.RunView(a.ctx, ui.NewOutputView(a.screen, reloadDetails(a.ConfigPath, changes), a.d.Pop).WithTitle(i18n.T("Reload Details")))
+	}
+}
+
+// reloadSummary counts the changes of a reload in a line or two
+func reloadSummary(c config.Changes) string {
+	var parts []string
+	if n := len(c.MenusAdded); n > 0 {
+		parts = append(parts, i18n.Tf("Menus added: %d", n))
+	}
+	if n := len(c.MenusRemoved); n > 0 {
+		parts = append(parts, i18n.Tf("Menus removed: %d", n))
+	}
+	if n := len(c.Items); n > 0 {
+		parts = append(parts, i18n.Tf("Menus with more or fewer items: %d", n))
+	}
+	if n := len(c.Warnings); n > 0 {
+		parts = append(parts, i18n.Tf("Warnings: %d", n))
+	}
+	if len(parts) == 0 {
+		return i18n.T("No menus or items were added or removed.")
+	}
+	return strings.Join(parts, "\n")
+}
+
+// reloadDetails lists the changes of a reload of the config at path, one per line
+func reloadDetails(path string, c config.Changes) string {
+	lines := []string{i18n.Tf("Reloaded %s", path)}
+	section := func(title string, entries []string) {
+		if len(entries) == 0 {
+			return
+		}
+		lines = append(lines, "", title)
+		for _, e := range entries {
+			lines = append(lines, "  "+e)
+		}
+	}
+	section(i18n.T("Menus added:"), c.MenusAdded)
+	section(i18n.T("Menus removed:"), c.MenusRemoved)
+	var counts []string
+	for _, ic := range c.Items {
+		name := ic.Menu
+		if name == "" {
+			name = i18n.T("(root menu)")
+		}
+		counts = append(counts, fmt.Sprintf("%s: %d → %d (%+d)", name, ic.Before, ic.After, ic.After-ic.Before))
+	}
+	section(i18n.T("Item counts:"), counts)
+	section(i18n.T("Warnings:"), c.Warnings)
+	if len(lines) == 1 {
+		lines = append(lines, "", i18n.T("No menus or items were added or removed."))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// warnHotkeyConflicts shows a dialog listing duplicate explicit hotkeys, if any
+func (a *App) warnHotkeyConflicts() {
+	conflicts := config.HotkeyConflicts(a.cfg)
+	if len(conflicts) == 0 {
+		return
+	}
+	a.showMessage(i18n.T("Hotkey Conflicts"), strings.Join(conflicts, "\n")+"\n"+i18n.T("Press F4 on an item to reassign its hotkey."))
+}
+
+// reassignHotkey prompts for a new hotkey for the selected item, writes it to the
+// config file, and reloads the config, staying in the current menu.
+func (a *App) reassignHotkey() {
+	navigator := a.navigator
+	item, err := navigator.GetSelectedItem()
+	if err != nil || item.Type == "separator" {
+		return
+	}
+
+	r, ok := a.screen.PromptKey(i18n.T("Assign Hotkey"), i18n.Tf("Press a new hotkey for '%s'.", item.Label), a.d.Events())
+	if !ok {
+		return
+	}
+	hotkey := strings.ToUpper(string(r))
+	if hotkey == a.cfg.ReloadKey() {
+		a.showError(i18n.T("Hotkey Reserved"), i18n.Tf("%s is reserved for reloading the config.", hotkey))
+		return
+	}
+	if owner := navigator.HotkeyOwner(hotkey); owner >= 0 && owner != navigator.GetSelectionIndex() {
+		ownerLabel := navigator.GetCurrentMenu()[owner].Label
+		a.showError(i18n.T("Hotkey In Use"), i18n.Tf("Hotkey %s is already used by '%s'.", hotkey, ownerLabel))
+		return
+	}
+
+	if err := config.SetItemHotkey(a.ConfigPath, navigator.GetCurrentMenuName(), item, hotkey); err != nil {
+		a.showError(i18n.T("Hotkey Error"), i18n.Tf("Failed to save hotkey: %v", err))
+		return
+	}
+	newCfg, _, err := config.Load(a.ConfigPath)
+	if err != nil {
+		a.showError(i18n.T("Reload Error"), i18n.Tf("Failed to reload config: %v", err))
+		return
+	}
+
+	a.cfg = newCfg
+	oldNavState := navigator.RememberSelection()
+	oldMenu := navigator.GetCurrentMenuName()
+	a.navigator = a.newNavigator(newCfg)
+	a.navigator.RestoreGroupState(navigator.GroupState())
+	a.navigator.RecallSelection(oldNavState)
+	a.navigator.NavigateToMenu(oldMenu)
+	a.navigator.BackOutOf(a.menuBarred)
+}
+
+// selectTheme shows the theme switcher, applying each highlighted theme as a preview.
+// The chosen theme is kept and written back to the config; cancelling restores the original.
+func (a *App) selectTheme() {
+	screen, cfg := a.screen, a.cfg
+	names := config.ThemeNames(cfg)
+	if len(names) == 0 {
+		return
+	}
+
+	current := -1
+	for i, name := range names {
+		if name == cfg.Theme {
+			current = i
+		}
+	}
+
+	preview := func(name string) {
+		previewCfg := *cfg
+		previewCfg.Theme = name
+		applyThemeFromConfig(screen, &previewCfg)
+	}
+
+	choice := screen.DrawThemeSelector(names, current, preview, a.d.Events())
+	if choice < 0 || choice == current {
+		applyThemeFromConfig(screen, cfg)
+		return
+	}
+
+	cfg.Theme = names[choice]
+	applyThemeFromConfig(screen, cfg)
+	if err := config.SetTheme(a.ConfigPath, cfg.Theme); err != nil {
+		a.showError(i18n.T("Theme Error"), i18n.Tf("Theme applied but could not be saved: %v", err))
+	}
+}
+
+// selectProfile shows the profile switcher for the directory containing ConfigPath
+// and switches to the chosen profile.
+func (a *App) selectProfile() {
+	dir := filepath.Dir(a.ConfigPath)
+	profiles, err := config.ListProfiles(dir)
+	if err != nil || len(profiles) < 2 {
+		a.showMessage(i18n.T("Profiles"), i18n.Tf("No other profiles found. Add more .yaml files to %s to switch between them.", dir))
+		return
+	}
+
+	current := -1
+	for i, name := range profiles {
+		if name == config.ProfileName(a.ConfigPath) {
+			current = i
+		}
+	}
+
+	choice := a.screen.DrawSelectList(i18n.T("Profiles"), profiles, current, current, a.d.Events())
+	if choice < 0 || choice == current {
+		return
+	}
+
+	newPath := config.ProfilePath(dir, profiles[choice])
+	newCfg, _, err := config.Load(newPath)
+	if err != nil {
+		a.showError(i18n.T("Profile Error"), i18n.Tf("Failed to load profile '%s': %v", profiles[choice], err))
+		return
+	}
+	a.ConfigPath = newPath
+	a.useConfig(newCfg)
+}
@@ -0,0 +1,88 @@
+package discover
+
+import (
+	"os"
+	"os/exec"
+)
+
+// Diagnoser is implemented by sources that can explain their Available
+// result. Diagnose returns the probes the source makes, in the order it makes
+// them, so `menuworks generate --doctor` can show where it looked.
+type Diagnoser interface {
+	Diagnose() []Check
+}
+
+// Check is one probe a source makes when deciding whether it is available:
+// a directory it looks in, an environment variable it reads, or a command it
+// runs.
+type Check struct {
+	What   string // what was probed, e.g. "path /home/me/.steam/steam"
+	OK     bool   // whether the probe succeeded
+	Detail string // what was found, or why the probe failed
+}
+
+// Diagnosis is a source's availability together with the checks behind it.
+type Diagnosis struct {
+	Source    string
+	Category  string
+	Available bool
+	Checks    []Check // empty for sources that do not implement Diagnoser
+}
+
+// Diagnose reports the availability of every registered source and, for
+// sources implementing Diagnoser, the checks behind it.
+func (r *Registry) Diagnose() []Diagnosis {
+	var out []Diagnosis
+	for _, s := range r.Sources() {
+		d := Diagnosis{Source: s.Name(), Category: r.CategoryOf(s), Available: s.Available()}
+		if dg, ok := s.(Diagnoser); ok {
+			d.Checks = dg.Diagnose()
+		}
+		out = append(out, d)
+	}
+	return out
+}
+
+// PathCheck reports whether path exists.
+func PathCheck(path string) Check {
+	c := Check{What: "path " + path}
+	if path == "" {
+		c.What = "path"
+		c.Detail = "empty"
+		return c
+	}
+	info, err := os.Stat(path)
+	switch {
+	case os.IsNotExist(err):
+		c.Detail = "does not exist"
+	case err != nil:
+		c.Detail = err.Error()
+	case info.IsDir():
+		c.OK, c.Detail = true, "directory"
+	default:
+		c.OK, c.Detail = true, "file"
+	}
+	return c
+}
+
+// EnvCheck reports whether the environment variable name is set, and to what.
+func EnvCheck(name string) Check {
+	c := Check{What: "env " + name}
+	if v := os.Getenv(name); v != "" {
+		c.OK, c.Detail = true, v
+	} else {
+		c.Detail = "not set"
+	}
+	return c
+}
+
+// LookPathCheck reports whether the command file is on PATH, and where.
+func LookPathCheck(file string) Check {
+	c := Check{What: "command " + file}
+	if path, err := exec.LookPath(file); err == nil {
+		c.OK, c.Detail = true, path
+	} else {
+		c.Detail = "not found on PATH"
+	}
+	return c
+}
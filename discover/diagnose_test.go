@@ -0,0 +1,66 @@
+package discover
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+type diagnosingSource struct {
+	mockSource
+	checks []Check
+}
+
+func (d *diagnosingSource) Diagnose() []Check { return d.checks }
+
+func TestRegistryDiagnose(t *testing.T) {
+	r := NewRegistry()
+	r.Register(&mockSource{name: "plain", category: "Apps", available: true})
+	r.Register(&diagnosingSource{
+		mockSource: mockSource{name: "steam", category: "Games"},
+		checks:     []Check{{What: "path /nowhere", Detail: "does not exist"}},
+	})
+
+	got := r.Diagnose()
+	if len(got) != 2 {
+		t.Fatalf("expected a diagnosis per source, got %d", len(got))
+	}
+	if got[0].Source != "plain" || !got[0].Available || len(got[0].Checks) != 0 {
+		t.Errorf("expected an available source without checks, got %+v", got[0])
+	}
+	if got[1].Source != "steam" || got[1].Available || len(got[1].Checks) != 1 || got[1].Checks[0].What != "path /nowhere" {
+		t.Errorf("expected the source's own checks, got %+v", got[1])
+	}
+}
+
+func TestPathCheck(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "libraryfolders.vdf")
+	if err := os.WriteFile(file, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if c := PathCheck(dir); !c.OK || c.Detail != "directory" || c.What != "path "+dir {
+		t.Errorf("expected the directory found, got %+v", c)
+	}
+	if c := PathCheck(file); !c.OK || c.Detail != "file" {
+		t.Errorf("expected the file found, got %+v", c)
+	}
+	if c := PathCheck(filepath.Join(dir, "missing")); c.OK || c.Detail != "does not exist" {
+		t.Errorf("expected a missing path, got %+v", c)
+	}
+	if c := PathCheck(""); c.OK {
+		t.Errorf("expected an empty path to fail, got %+v", c)
+	}
+}
+
+func TestEnvCheck(t *testing.T) {
+	t.Setenv("MENUWORKS_TEST_DIR", "/opt/games")
+	if c := EnvCheck("MENUWORKS_TEST_DIR"); !c.OK || c.Detail != "/opt/games" {
+		t.Errorf("expected the variable's value, got %+v", c)
+	}
+	t.Setenv("MENUWORKS_TEST_DIR", "")
+	if c := EnvCheck("MENUWORKS_TEST_DIR"); c.OK || c.Detail != "not set" {
+		t.Errorf("expected an unset variable, got %+v", c)
+	}
+}
@@ -2,13 +2,19 @@
 //
 // This package is intentionally isolated from the rest of MenuWorks (config, menu,
 // ui, exec). It discovers installed applications and generates YAML config directly.
+// Its only MenuWorks import is the standard-library-only logging package.
 package discover
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"sort"
 	"strings"
 	"sync"
+	"time"
+
+	"github.com/benworks/menuworks/logging"
 )
 
 // Source discovers applications from a specific location on the system.
@@ -22,22 +28,71 @@ type Source interface {
 	// Available reports whether this source is present on the current system.
 	Available() bool
 
-	// Discover scans for installed applications and returns them.
-	Discover() ([]DiscoveredApp, error)
+	// Discover scans for installed applications and returns them. It should
+	// stop early and return ctx.Err() once ctx is done.
+	Discover(ctx context.Context) ([]DiscoveredApp, error)
 }
 
-// DiscoveredApp represents a single application found by a Source.
+// DiscoveredApp represents a single application found by a Source. The
+// metadata fields are filled in where the source can supply them.
 type DiscoveredApp struct {
-	Name     string // display name (used as menu label)
-	Exec     string // command to launch the application (platform-specific)
-	Source   string // source that found it (e.g. "steam")
-	Category string // grouping category (e.g. "Games")
+	Name        string   `json:"name"`                   // display name (used as menu label)
+	Exec        string   `json:"exec"`                   // command to launch the application (platform-specific)
+	Argv        []string `json:"argv,omitempty"`         // if set, the program and its arguments, run without a shell; Exec then holds them quoted
+	Source      string   `json:"source"`                 // source that found it (e.g. "steam")
+	Category    string   `json:"category"`               // grouping category (e.g. "Games")
+	Description string   `json:"description,omitempty"`  // optional: what the app is, from the source (e.g. a shortcut's comment); becomes the item's help text
+	Version     string   `json:"version,omitempty"`      // installed version as the source reports it
+	Publisher   string   `json:"publisher,omitempty"`    // vendor or publisher name
+	InstallDate string   `json:"install_date,omitempty"` // YYYY-MM-DD
+	SizeBytes   int64    `json:"size_bytes,omitempty"`   // installed size on disk
+	Confidence  int      `json:"confidence,omitempty"`   // 1-100 for heuristic matches; 0 when the source is certain
+	WorkDir     string   `json:"workdir,omitempty"`      // optional directory to run it in
+	Terminal    bool     `json:"terminal,omitempty"`     // a console program, whose output is worth showing
+	ShowOutput  *bool    `json:"show_output,omitempty"`  // if set, written as the item's showOutput
+	// CategoryIcon, if set, is written as the icon of the submenu item that
+	// opens the app's category (see Registry.SuggestIcons)
+	CategoryIcon string `json:"category_icon,omitempty"`
+}
+
+// LowConfidence is the score below which generated items are marked with a
+// comment asking the user to check them.
+const LowConfidence = 50
+
+// Score returns the app's confidence from 1 to 100, treating an unscored app
+// as certain.
+func (a DiscoveredApp) Score() int {
+	if a.Confidence <= 0 {
+		return 100
+	}
+	return a.Confidence
+}
+
+// FilterByConfidence returns the apps scoring at least min.
+func FilterByConfidence(apps []DiscoveredApp, min int) []DiscoveredApp {
+	var out []DiscoveredApp
+	for _, a := range apps {
+		if a.Score() >= min {
+			out = append(out, a)
+		}
+	}
+	return out
 }
 
+// DefaultSourceTimeout is how long DiscoverAll waits for each source when no
+// other timeout is set.
+const DefaultSourceTimeout = 60 * time.Second
+
+// ErrSourceTimeout is wrapped by DiscoverResult.Err for a source that did not
+// finish within the per-source timeout.
+var ErrSourceTimeout = errors.New("timed out")
+
 // Registry holds all known discovery sources and orchestrates scanning.
 type Registry struct {
-	mu      sync.Mutex
-	sources []Source
+	mu         sync.Mutex
+	sources    []Source
+	timeout    time.Duration
+	categories map[string]string // lower-cased source name -> category override
 }
 
 // NewRegistry creates an empty Registry.
@@ -52,6 +107,51 @@ func (r *Registry) Register(s Source) {
 	r.sources = append(r.sources, s)
 }
 
+// SetTimeout sets how long DiscoverAll waits for each source. Zero or less
+// restores DefaultSourceTimeout.
+func (r *Registry) SetTimeout(d time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.timeout = d
+}
+
+// sourceTimeout returns the per-source timeout in effect.
+func (r *Registry) sourceTimeout() time.Duration {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.timeout <= 0 {
+		return DefaultSourceTimeout
+	}
+	return r.timeout
+}
+
+// SetCategory puts every app the named source discovers in category instead
+// of the source's own. An empty category removes the override.
+func (r *Registry) SetCategory(source, category string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	key := strings.ToLower(source)
+	if category == "" {
+		delete(r.categories, key)
+		return
+	}
+	if r.categories == nil {
+		r.categories = make(map[string]string)
+	}
+	r.categories[key] = category
+}
+
+// CategoryOf returns the category s's apps are put in: its override if one
+// is set, otherwise s.Category().
+func (r *Registry) CategoryOf(s Source) string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if category, ok := r.categories[strings.ToLower(s.Name())]; ok {
+		return category
+	}
+	return s.Category()
+}
+
 // Sources returns all registered sources.
 func (r *Registry) Sources() []Source {
 	r.mu.Lock()
@@ -95,8 +195,15 @@ type DiscoverResult struct {
 
 // DiscoverAll runs discovery on all available sources (or the filtered set).
 // If sourceNames is non-empty, only sources whose names match (case-insensitive) are used.
-func (r *Registry) DiscoverAll(sourceNames []string) ([]DiscoverResult, error) {
+// Each source gets its own timeout; one that runs over is reported with an
+// error wrapping ErrSourceTimeout and the remaining sources still run.
+func (r *Registry) DiscoverAll(ctx context.Context, sourceNames []string) ([]DiscoverResult, error) {
 	sources := r.AvailableSources()
+	for _, s := range r.Sources() {
+		if !s.Available() {
+			logging.Debug("discovery source not available", "source", s.Name())
+		}
+	}
 
 	// Filter by requested names if specified
 	if len(sourceNames) > 0 {
@@ -126,9 +233,24 @@ func (r *Registry) DiscoverAll(sourceNames []string) ([]DiscoverResult, error) {
 		sources = filtered
 	}
 
+	timeout := r.sourceTimeout()
 	var results []DiscoverResult
 	for _, s := range sources {
-		apps, err := s.Discover()
+		if err := ctx.Err(); err != nil {
+			return results, err
+		}
+		start := time.Now()
+		apps, err := discoverWithTimeout(ctx, s, timeout)
+		if err != nil {
+			logging.Warn("discovery source failed", "source", s.Name(), "duration", time.Since(start), "err", err)
+		} else {
+			logging.Info("discovery source finished", "source", s.Name(), "apps", len(apps), "duration", time.Since(start))
+		}
+		if category := r.CategoryOf(s); category != s.Category() {
+			for i := range apps {
+				apps[i].Category = category
+			}
+		}
 		results = append(results, DiscoverResult{
 			Source: s.Name(),
 			Apps:   apps,
@@ -138,6 +260,38 @@ func (r *Registry) DiscoverAll(sourceNames []string) ([]DiscoverResult, error) {
 	return results, nil
 }
 
+// discoverWithTimeout runs s.Discover with a context that expires after
+// timeout. A source blocked in a call that ignores the context (a COM call or
+// a hung child process) is abandoned: its goroutine finishes in the
+// background and its result is dropped.
+func discoverWithTimeout(ctx context.Context, s Source, timeout time.Duration) ([]DiscoveredApp, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	type result struct {
+		apps []DiscoveredApp
+		err  error
+	}
+	done := make(chan result, 1)
+	go func() {
+		apps, err := s.Discover(ctx)
+		done <- result{apps, err}
+	}()
+
+	select {
+	case res := <-done:
+		if res.err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return nil, fmt.Errorf("%w after %s", ErrSourceTimeout, timeout)
+		}
+		return res.apps, res.err
+	case <-ctx.Done():
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return nil, fmt.Errorf("%w after %s", ErrSourceTimeout, timeout)
+		}
+		return nil, ctx.Err()
+	}
+}
+
 // CollectApps gathers all successfully discovered apps from results, sorted by category then name.
 func CollectApps(results []DiscoverResult) []DiscoveredApp {
 	var apps []DiscoveredApp
@@ -155,6 +309,76 @@ func CollectApps(results []DiscoverResult) []DiscoveredApp {
 	return apps
 }
 
+// AppendDetails returns a copy of apps with each app's version, publisher,
+// install date, and size appended to its Description, so they show in the
+// generated help text. Apps without metadata are unchanged.
+func AppendDetails(apps []DiscoveredApp) []DiscoveredApp {
+	out := make([]DiscoveredApp, len(apps))
+	for i, a := range apps {
+		if details := a.Details(); details != "" {
+			if a.Description != "" {
+				a.Description += "\n"
+			}
+			a.Description += details
+		}
+		out[i] = a
+	}
+	return out
+}
+
+// HideLauncherOutput returns a copy of apps in which every app that is not a
+// Terminal program and has no ShowOutput of its own gets showOutput: false,
+// so launching a GUI app or game returns straight to the menu instead of
+// showing an empty output dialog.
+func HideLauncherOutput(apps []DiscoveredApp) []DiscoveredApp {
+	hide := false
+	out := make([]DiscoveredApp, len(apps))
+	for i, a := range apps {
+		if !a.Terminal && a.ShowOutput == nil {
+			a.ShowOutput = &hide
+		}
+		out[i] = a
+	}
+	return out
+}
+
+// Details formats the app's metadata as one line, e.g.
+// "Version 1.2.0 | Valve | Installed 2024-03-01 | 1.4 GB", or "" if it has none.
+func (a DiscoveredApp) Details() string {
+	var parts []string
+	if a.Version != "" {
+		parts = append(parts, "Version "+a.Version)
+	}
+	if a.Publisher != "" {
+		parts = append(parts, a.Publisher)
+	}
+	if a.InstallDate != "" {
+		parts = append(parts, "Installed "+a.InstallDate)
+	}
+	if a.SizeBytes > 0 {
+		parts = append(parts, formatSize(a.SizeBytes))
+	}
+	return strings.Join(parts, " | ")
+}
+
+// formatSize formats n bytes with a binary unit, e.g. "512 KB" or "1.4 GB".
+func formatSize(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	value := float64(n) / float64(div)
+	if value >= 10 || exp == 0 {
+		return fmt.Sprintf("%.0f %cB", value, "KMGT"[exp])
+	}
+	return fmt.Sprintf("%.1f %cB", value, "KMGT"[exp])
+}
+
 // GroupByCategory groups apps by their category name.
 func GroupByCategory(apps []DiscoveredApp) map[string][]DiscoveredApp {
 	groups := make(map[string][]DiscoveredApp)
@@ -2,8 +2,12 @@ package discover
 
 import (
 	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
 	"strings"
 	"testing"
+	"time"
 
 	"gopkg.in/yaml.v3"
 )
@@ -21,7 +25,7 @@ type mockSource struct {
 func (m *mockSource) Name() string     { return m.name }
 func (m *mockSource) Category() string { return m.category }
 func (m *mockSource) Available() bool  { return m.available }
-func (m *mockSource) Discover() ([]DiscoveredApp, error) {
+func (m *mockSource) Discover(ctx context.Context) ([]DiscoveredApp, error) {
 	return m.apps, m.err
 }
 
@@ -62,6 +66,38 @@ func TestRegistryRegisterMultiple(t *testing.T) {
 	}
 }
 
+func TestRegistryCategoryOverride(t *testing.T) {
+	r := NewRegistry()
+	pf := &mockSource{name: "programfiles", category: "Applications", available: true,
+		apps: []DiscoveredApp{{Name: "Tool", Exec: "tool.exe", Category: "Applications"}}}
+	sm := &mockSource{name: "startmenu", category: "Applications", available: true,
+		apps: []DiscoveredApp{{Name: "App", Exec: "app.exe", Category: "Applications"}}}
+	r.Register(pf)
+	r.Register(sm)
+
+	r.SetCategory("ProgramFiles", "Tools")
+	r.Configure(&DiscoverConfig{Categories: map[string]string{
+		"programfiles": "Ignored", // the SetCategory override wins
+		"startmenu":    "Shortcuts",
+	}})
+
+	if got := r.CategoryOf(pf); got != "Tools" {
+		t.Errorf("CategoryOf(programfiles) = %q", got)
+	}
+	results, err := r.DiscoverAll(context.Background(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if results[0].Apps[0].Category != "Tools" || results[1].Apps[0].Category != "Shortcuts" {
+		t.Errorf("unexpected categories: %+v", results)
+	}
+
+	r.SetCategory("startmenu", "")
+	if got := r.CategoryOf(sm); got != "Applications" {
+		t.Errorf("removing the override should restore the source's category, got %q", got)
+	}
+}
+
 func TestRegistryAvailableSources(t *testing.T) {
 	r := NewRegistry()
 	r.Register(&mockSource{name: "a", available: true})
@@ -152,7 +188,7 @@ func TestDiscoverAllNoFilter(t *testing.T) {
 		},
 	})
 
-	results, err := r.DiscoverAll(nil)
+	results, err := r.DiscoverAll(context.Background(), nil)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -174,7 +210,7 @@ func TestDiscoverAllWithFilter(t *testing.T) {
 		apps:      []DiscoveredApp{{Name: "App2", Exec: "app2"}},
 	})
 
-	results, err := r.DiscoverAll([]string{"src1"})
+	results, err := r.DiscoverAll(context.Background(), []string{"src1"})
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -190,7 +226,7 @@ func TestDiscoverAllUnknownSource(t *testing.T) {
 	r := NewRegistry()
 	r.Register(&mockSource{name: "src1", available: true})
 
-	_, err := r.DiscoverAll([]string{"nonexistent"})
+	_, err := r.DiscoverAll(context.Background(), []string{"nonexistent"})
 	if err == nil {
 		t.Fatal("expected error for unknown source")
 	}
@@ -207,7 +243,7 @@ func TestDiscoverAllSkipsUnavailable(t *testing.T) {
 		apps:      []DiscoveredApp{{Name: "Hidden"}},
 	})
 
-	results, err := r.DiscoverAll(nil)
+	results, err := r.DiscoverAll(context.Background(), nil)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -224,7 +260,7 @@ func TestDiscoverAllPropagatesSourceError(t *testing.T) {
 		err:       errTest,
 	})
 
-	results, err := r.DiscoverAll(nil)
+	results, err := r.DiscoverAll(context.Background(), nil)
 	if err != nil {
 		t.Fatalf("DiscoverAll should not return error for source-level errors: %v", err)
 	}
@@ -236,6 +272,139 @@ func TestDiscoverAllPropagatesSourceError(t *testing.T) {
 	}
 }
 
+// hangingSource blocks in Discover until release is closed, ignoring ctx
+// unless honorCtx is set.
+type hangingSource struct {
+	mockSource
+	release  chan struct{}
+	honorCtx bool
+}
+
+func (h *hangingSource) Discover(ctx context.Context) ([]DiscoveredApp, error) {
+	if h.honorCtx {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-h.release:
+		}
+	} else {
+		<-h.release
+	}
+	return []DiscoveredApp{{Name: "Late"}}, nil
+}
+
+func TestDiscoverAllTimesOutSlowSources(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+
+	r := NewRegistry()
+	r.SetTimeout(20 * time.Millisecond)
+	r.Register(&hangingSource{mockSource: mockSource{name: "com", available: true}, release: release})
+	r.Register(&hangingSource{mockSource: mockSource{name: "powershell", available: true}, release: release, honorCtx: true})
+	r.Register(&mockSource{name: "fast", available: true, apps: []DiscoveredApp{{Name: "App"}}})
+
+	results, err := r.DiscoverAll(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("DiscoverAll should not fail when a source times out: %v", err)
+	}
+	if len(results) != 3 {
+		t.Fatalf("expected 3 results, got %d", len(results))
+	}
+	for _, res := range results[:2] {
+		if !errors.Is(res.Err, ErrSourceTimeout) || res.Apps != nil {
+			t.Errorf("%s: expected a timeout and no apps, got %v, %v", res.Source, res.Apps, res.Err)
+		}
+	}
+	if results[2].Err != nil || len(results[2].Apps) != 1 {
+		t.Errorf("expected the remaining source to still run, got %+v", results[2])
+	}
+}
+
+func TestDiscoverAllStopsWhenCanceled(t *testing.T) {
+	r := NewRegistry()
+	r.Register(&mockSource{name: "a", available: true})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := r.DiscoverAll(ctx, nil); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
+
+func TestAppendDetails(t *testing.T) {
+	apps := []DiscoveredApp{
+		{Name: "Editor", Description: "Edit text.", Version: "2.1", Publisher: "Vendor", InstallDate: "2024-03-01", SizeBytes: 1536 * 1024 * 1024},
+		{Name: "Game", SizeBytes: 512 * 1024},
+		{Name: "Plain", Description: "No metadata."},
+	}
+
+	got := AppendDetails(apps)
+	want := []string{
+		"Edit text.\nVersion 2.1 | Vendor | Installed 2024-03-01 | 1.5 GB",
+		"512 KB",
+		"No metadata.",
+	}
+	for i, w := range want {
+		if got[i].Description != w {
+			t.Errorf("app[%d].Description = %q, want %q", i, got[i].Description, w)
+		}
+	}
+	if apps[0].Description != "Edit text." {
+		t.Error("expected the input apps to be left unchanged")
+	}
+}
+
+type iconSource struct {
+	mockSource
+	icon string
+}
+
+func (s *iconSource) CategoryIcon() string { return s.icon }
+
+func TestSuggestIcons(t *testing.T) {
+	r := NewRegistry()
+	r.Register(&iconSource{mockSource: mockSource{name: "steam", category: "Games", available: true}, icon: "🕹"})
+	r.Register(&mockSource{name: "desktop", category: "Apps", available: true})
+	r.Register(&mockSource{name: "custom", category: "Handhelds", available: true})
+
+	apps := []DiscoveredApp{
+		{Name: "Doom", Category: "Games", Source: "steam"},
+		{Name: "Quake", Category: "Shooters", Source: "steam"},
+		{Name: "Editor", Category: "Apps", Source: "desktop"},
+		{Name: "Emu", Category: "Handhelds", Source: "custom"},
+		{Name: "Kept", Category: "Apps", Source: "desktop", CategoryIcon: "✏"},
+	}
+	got := r.SuggestIcons(apps)
+	want := []string{"🕹", "", "📦", "", "✏"}
+	for i, w := range want {
+		if got[i].CategoryIcon != w {
+			t.Errorf("app[%d].CategoryIcon = %q, want %q", i, got[i].CategoryIcon, w)
+		}
+	}
+	if apps[0].CategoryIcon != "" {
+		t.Error("expected the input apps to be left unchanged")
+	}
+
+	var buf bytes.Buffer
+	if err := RenderConfig(got[:1], &buf); err != nil {
+		t.Fatalf("RenderConfig failed: %v", err)
+	}
+	if !strings.Contains(buf.String(), `icon: "🕹"`) {
+		t.Errorf("expected the category's submenu item to get the icon:\n%s", buf.String())
+	}
+}
+
+func TestDiscoveredAppJSON(t *testing.T) {
+	data, err := json.Marshal(DiscoveredApp{Name: "Game", Exec: "game", Source: "steam", Category: "Games", SizeBytes: 42})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"name":"Game","exec":"game","source":"steam","category":"Games","size_bytes":42}`
+	if string(data) != want {
+		t.Errorf("got %s, want %s", data, want)
+	}
+}
+
 var errTest = &testError{msg: "test error"}
 
 type testError struct{ msg string }
@@ -436,6 +605,192 @@ func TestRenderConfigBasic(t *testing.T) {
 	}
 }
 
+func TestRenderConfigProgramArgs(t *testing.T) {
+	origOS := writerOS
+	writerOS = "windows"
+	defer func() { writerOS = origOS }()
+
+	apps := []DiscoveredApp{
+		{
+			Name:     "Tool",
+			Exec:     `"C:\Program Files\Tool\tool.exe" --quiet`,
+			Argv:     []string{`C:\Program Files\Tool\tool.exe`, "--quiet"},
+			Category: "Applications",
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := RenderConfig(apps, &buf); err != nil {
+		t.Fatalf("RenderConfig failed: %v", err)
+	}
+
+	var parsed struct {
+		Menus map[string]struct {
+			Items []struct {
+				Exec struct {
+					Windows struct {
+						Program string   `yaml:"program"`
+						Args    []string `yaml:"args"`
+					} `yaml:"windows"`
+				} `yaml:"exec"`
+			} `yaml:"items"`
+		} `yaml:"menus"`
+	}
+	if err := yaml.Unmarshal(buf.Bytes(), &parsed); err != nil {
+		t.Fatalf("generated YAML is invalid: %v", err)
+	}
+	win := parsed.Menus["applications"].Items[0].Exec.Windows
+	if win.Program != `C:\Program Files\Tool\tool.exe` || len(win.Args) != 1 || win.Args[0] != "--quiet" {
+		t.Errorf("expected a program with args, got %+v\n%s", win, buf.String())
+	}
+}
+
+func TestRenderConfigDescriptionBecomesHelp(t *testing.T) {
+	origOS := writerOS
+	writerOS = "windows"
+	defer func() { writerOS = origOS }()
+
+	apps := []DiscoveredApp{
+		{Name: "Paint", Exec: "mspaint.exe", Category: "Applications", Description: "Create and edit drawings."},
+		{Name: "Tool", Exec: "tool.exe", Category: "Applications"},
+	}
+
+	var buf bytes.Buffer
+	if err := RenderConfig(apps, &buf); err != nil {
+		t.Fatalf("RenderConfig failed: %v", err)
+	}
+
+	var parsed struct {
+		Menus map[string]struct {
+			Items []struct {
+				Label string `yaml:"label"`
+				Help  string `yaml:"help"`
+			} `yaml:"items"`
+		} `yaml:"menus"`
+	}
+	if err := yaml.Unmarshal(buf.Bytes(), &parsed); err != nil {
+		t.Fatalf("generated YAML is invalid: %v", err)
+	}
+	items := parsed.Menus["applications"].Items
+	if items[0].Label != "Paint" || items[0].Help != "Create and edit drawings." {
+		t.Errorf("expected the description as help text, got %+v", items[0])
+	}
+	if items[1].Help != "" || strings.Count(buf.String(), "help:") != 1 {
+		t.Errorf("expected no help for apps without a description, got:\n%s", buf.String())
+	}
+}
+
+func TestRenderConfigWorkDir(t *testing.T) {
+	origOS := writerOS
+	writerOS = "linux"
+	defer func() { writerOS = origOS }()
+
+	apps := []DiscoveredApp{
+		{Name: "Game", Exec: "./run.sh", Argv: []string{"./run.sh"}, Category: "Games", WorkDir: "/opt/game"},
+		{Name: "Tool", Exec: "tool", Category: "Games"},
+	}
+
+	var buf bytes.Buffer
+	if err := RenderConfig(apps, &buf); err != nil {
+		t.Fatalf("RenderConfig failed: %v", err)
+	}
+
+	var parsed struct {
+		Menus map[string]struct {
+			Items []struct {
+				Exec struct {
+					WorkDir string `yaml:"workdir"`
+				} `yaml:"exec"`
+			} `yaml:"items"`
+		} `yaml:"menus"`
+	}
+	if err := yaml.Unmarshal(buf.Bytes(), &parsed); err != nil {
+		t.Fatalf("generated YAML is invalid: %v", err)
+	}
+	items := parsed.Menus["games"].Items
+	if items[0].Exec.WorkDir != "/opt/game" {
+		t.Errorf("expected workdir /opt/game, got:\n%s", buf.String())
+	}
+	if strings.Count(buf.String(), "workdir:") != 1 {
+		t.Errorf("expected no workdir for apps without one, got:\n%s", buf.String())
+	}
+}
+
+func TestRenderConfigHidesLauncherOutput(t *testing.T) {
+	show := true
+	apps := HideLauncherOutput([]DiscoveredApp{
+		{Name: "Game", Exec: "game", Category: "Games"},
+		{Name: "Htop", Exec: "htop", Category: "Games", Terminal: true},
+		{Name: "Report", Exec: "report", Category: "Games", ShowOutput: &show},
+	})
+
+	var buf bytes.Buffer
+	if err := RenderConfig(apps, &buf); err != nil {
+		t.Fatalf("RenderConfig failed: %v", err)
+	}
+
+	var parsed struct {
+		Menus map[string]struct {
+			Items []struct {
+				ShowOutput *bool `yaml:"showOutput"`
+			} `yaml:"items"`
+		} `yaml:"menus"`
+	}
+	if err := yaml.Unmarshal(buf.Bytes(), &parsed); err != nil {
+		t.Fatalf("generated YAML is invalid: %v", err)
+	}
+	items := parsed.Menus["games"].Items
+	if items[0].ShowOutput == nil || *items[0].ShowOutput {
+		t.Errorf("expected showOutput: false for a launcher, got:\n%s", buf.String())
+	}
+	if items[1].ShowOutput != nil {
+		t.Errorf("expected terminal programs to keep the default, got:\n%s", buf.String())
+	}
+	if items[2].ShowOutput == nil || !*items[2].ShowOutput {
+		t.Errorf("expected an app's own showOutput to be kept, got:\n%s", buf.String())
+	}
+}
+
+func TestRenderConfigMarksLowConfidence(t *testing.T) {
+	origOS := writerOS
+	writerOS = "windows"
+	defer func() { writerOS = origOS }()
+
+	apps := []DiscoveredApp{
+		{Name: "Guess", Exec: "a.exe", Category: "Applications", Source: "Program Files", Confidence: 20},
+		{Name: "Sure", Exec: "sure.exe", Category: "Applications", Source: "Program Files", Confidence: 95},
+		{Name: "Shortcut", Exec: "s.exe", Category: "Applications", Source: "Start Menu", Description: "From a shortcut."},
+	}
+
+	var buf bytes.Buffer
+	if err := RenderConfig(apps, &buf); err != nil {
+		t.Fatalf("RenderConfig failed: %v", err)
+	}
+	output := buf.String()
+
+	if strings.Count(output, "# Low confidence") != 1 || !strings.Contains(output, "# Low confidence (20%): check this is the right program\n") {
+		t.Errorf("expected only the guessed item marked, got:\n%s", output)
+	}
+	lines := strings.Split(output, "\n")
+	for i, line := range lines {
+		if strings.Contains(line, "# Low confidence") && (i+2 >= len(lines) || strings.TrimSpace(lines[i+2]) != "label: Guess") {
+			t.Errorf("expected the comment right above the Guess item, got:\n%s", output)
+		}
+	}
+	// Multi-source menus get help text too
+	if !strings.Contains(output, "help: From a shortcut.") {
+		t.Errorf("expected help text in the per-source menu, got:\n%s", output)
+	}
+}
+
+func TestFilterByConfidence(t *testing.T) {
+	apps := []DiscoveredApp{{Name: "Guess", Confidence: 20}, {Name: "Sure", Confidence: 95}, {Name: "Unscored"}}
+	got := FilterByConfidence(apps, 50)
+	if len(got) != 2 || got[0].Name != "Sure" || got[1].Name != "Unscored" {
+		t.Errorf("expected unscored apps treated as certain, got %+v", got)
+	}
+}
+
 func TestRenderConfigMultipleCategories(t *testing.T) {
 	origOS := writerOS
 	writerOS = "windows"
@@ -565,7 +920,7 @@ func TestEndToEndDiscoverAndRender(t *testing.T) {
 		},
 	})
 
-	results, err := r.DiscoverAll(nil)
+	results, err := r.DiscoverAll(context.Background(), nil)
 	if err != nil {
 		t.Fatalf("DiscoverAll failed: %v", err)
 	}
@@ -623,7 +978,7 @@ func TestEndToEndWithSourceFilter(t *testing.T) {
 		apps:      []DiscoveredApp{{Name: "App", Exec: "app", Category: "Applications"}},
 	})
 
-	results, err := r.DiscoverAll([]string{"steam"})
+	results, err := r.DiscoverAll(context.Background(), []string{"steam"})
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -656,7 +1011,7 @@ func TestEndToEndDeduplication(t *testing.T) {
 		},
 	})
 
-	results, _ := r.DiscoverAll(nil)
+	results, _ := r.DiscoverAll(context.Background(), nil)
 	apps := CollectApps(results)
 	apps = DeduplicateApps(apps)
 
@@ -911,7 +1266,7 @@ func TestEndToEndMultiSourceGames(t *testing.T) {
 		},
 	})
 
-	results, err := r.DiscoverAll(nil)
+	results, err := r.DiscoverAll(context.Background(), nil)
 	if err != nil {
 		t.Fatalf("DiscoverAll failed: %v", err)
 	}
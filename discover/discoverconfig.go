@@ -1,7 +1,12 @@
 // Package discover provides application discovery for automatic config generation.
 package discover
 
-import "gopkg.in/yaml.v3"
+import (
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
 
 // DirEntry specifies a single directory to scan for executable files, along with
 // the display name to use as the menu section label.
@@ -18,7 +23,60 @@ type DirEntry struct {
 // DiscoverConfig holds the optional discovery configuration block from a base YAML file.
 // It is read from the top-level "discover:" key and is silently ignored by the TUI at runtime.
 type DiscoverConfig struct {
-	Dirs []DirEntry `yaml:"dirs"`
+	Dirs  []DirEntry   `yaml:"dirs"`
+	Steam SteamOptions `yaml:"steam"`
+	Xbox  XboxOptions  `yaml:"xbox"`
+	MAME  MAMEOptions  `yaml:"mame"`
+	Order Ordering     `yaml:"order"`
+
+	// Categories maps a source name to the category its apps are put in,
+	// e.g. programfiles: Tools.
+	Categories map[string]string `yaml:"categories"`
+}
+
+// MAMEOptions are the MAME settings of the discover: block. The MAME source
+// is only available once RomDir is set.
+type MAMEOptions struct {
+	RomDir     string `yaml:"rom_dir"`
+	DAT        string `yaml:"dat"`        // optional: mame -listxml output or a Logiqx DAT
+	Executable string `yaml:"executable"` // default "mame"
+	SkipClones bool   `yaml:"skip_clones"`
+}
+
+// DefaultPowerShellTimeout bounds each PowerShell invocation of the Xbox
+// source when the discover: block does not set one.
+const DefaultPowerShellTimeout = 30 * time.Second
+
+// XboxOptions are the Xbox settings of the discover: block.
+type XboxOptions struct {
+	// PowerShellTimeout bounds each PowerShell invocation, e.g. "45s".
+	// Zero means DefaultPowerShellTimeout.
+	PowerShellTimeout time.Duration `yaml:"powershell_timeout"`
+}
+
+// Configurable is implemented by sources that take settings from the
+// discover: block.
+type Configurable interface {
+	Configure(cfg *DiscoverConfig)
+}
+
+// Configure passes cfg to every registered source that implements
+// Configurable, and applies its category overrides to sources that do not
+// already have one from SetCategory.
+func (r *Registry) Configure(cfg *DiscoverConfig) {
+	for source, category := range cfg.Categories {
+		r.mu.Lock()
+		_, set := r.categories[strings.ToLower(source)]
+		r.mu.Unlock()
+		if !set {
+			r.SetCategory(source, category)
+		}
+	}
+	for _, s := range r.Sources() {
+		if c, ok := s.(Configurable); ok {
+			c.Configure(cfg)
+		}
+	}
 }
 
 // ParseDiscoverConfig extracts the "discover:" block from a YAML config file.
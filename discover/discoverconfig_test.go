@@ -2,6 +2,7 @@ package discover
 
 import (
 	"testing"
+	"time"
 )
 
 func TestParseDiscoverConfig_Basic(t *testing.T) {
@@ -93,3 +94,63 @@ discover:
 		t.Fatalf("expected 1 dir, got %d", len(cfg.Dirs))
 	}
 }
+
+func TestParseDiscoverConfig_Steam(t *testing.T) {
+	yaml := `
+discover:
+  steam:
+    skip_soundtracks: true
+    skip_dlc: true
+    played_within_months: 6
+`
+	cfg, err := ParseDiscoverConfig([]byte(yaml))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := SteamOptions{SkipSoundtracks: true, SkipDLC: true, PlayedWithinMonths: 6}
+	if cfg.Steam != expected {
+		t.Errorf("got %+v, expected %+v", cfg.Steam, expected)
+	}
+}
+
+func TestParseDiscoverConfig_Xbox(t *testing.T) {
+	cfg, err := ParseDiscoverConfig([]byte("discover:\n  xbox:\n    powershell_timeout: 45s\n"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.Xbox.PowerShellTimeout != 45*time.Second {
+		t.Errorf("expected 45s, got %v", cfg.Xbox.PowerShellTimeout)
+	}
+
+	if _, err := ParseDiscoverConfig([]byte("discover:\n  xbox:\n    powershell_timeout: soon\n")); err == nil {
+		t.Error("expected error for an invalid duration")
+	}
+}
+
+func TestParseDiscoverConfig_Order(t *testing.T) {
+	yaml := `
+discover:
+  order:
+    categories: [Games, Applications]
+    sources: [xbox]
+    items: alphabetical
+`
+	cfg, err := ParseDiscoverConfig([]byte(yaml))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	o := cfg.Order
+	if len(o.Categories) != 2 || o.Categories[0] != "Games" || len(o.Sources) != 1 || o.Items != ItemOrderAlphabetical {
+		t.Errorf("unexpected order: %+v", o)
+	}
+}
+
+func TestParseDiscoverConfig_Categories(t *testing.T) {
+	cfg, err := ParseDiscoverConfig([]byte("discover:\n  categories:\n    programfiles: Tools\n"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.Categories["programfiles"] != "Tools" {
+		t.Errorf("unexpected categories: %v", cfg.Categories)
+	}
+}
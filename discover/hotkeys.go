@@ -0,0 +1,170 @@
+package discover
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"gopkg.in/yaml.v3"
+)
+
+// quitHotkey is the hotkey the TUI gives a quit item that has none.
+const quitHotkey = "Q"
+
+// reloadHotkey is the key the TUI handles as reload before any item hotkey,
+// so an item given it could never be reached. keybindings.reload moves it.
+const reloadHotkey = "R"
+
+// AssignHotkeys gives every item of a rendered config that has no hotkey one
+// that is unique within its menu, and returns the re-encoded config. Hotkeys
+// already in the config are kept and never reused.
+//
+// Each item gets the first letter of its label if free, else the first letter
+// of a later word, else any letter of its label, so an item only loses its
+// first letter to an item before it. A quit item gets Q, as it would at
+// runtime. The reload key (R unless keybindings.reload moves it) is never
+// assigned. Items whose letters are all taken get none and are left to
+// runtime auto-assignment.
+func AssignHotkeys(data []byte) ([]byte, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse config: %w", err)
+	}
+	if len(doc.Content) == 0 {
+		return data, nil
+	}
+	root := doc.Content[0]
+
+	reload := strings.ToUpper(strings.TrimSpace(scalarValue(mappingValue(root, "keybindings"), "reload")))
+	if reload == "" {
+		reload = reloadHotkey
+	}
+	assignMenuHotkeys(mappingValue(root, "items"), reload)
+	if menus := mappingValue(root, "menus"); menus != nil && menus.Kind == yaml.MappingNode {
+		for i := 1; i < len(menus.Content); i += 2 {
+			assignMenuHotkeys(mappingValue(menus.Content[i], "items"), reload)
+		}
+	}
+
+	out, err := marshalYAML(&doc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal config: %w", err)
+	}
+	return out, nil
+}
+
+// assignMenuHotkeys fills in the hotkeys of one menu's items sequence,
+// leaving out the reload key.
+func assignMenuHotkeys(items *yaml.Node, reload string) {
+	if items == nil || items.Kind != yaml.SequenceNode {
+		return
+	}
+
+	used := map[string]bool{reload: true}
+	var pending []*yaml.Node
+	for _, item := range items.Content {
+		if item.Kind != yaml.MappingNode {
+			continue
+		}
+		if hotkey := mappingValue(item, "hotkey"); hotkey != nil {
+			used[strings.ToUpper(hotkey.Value)] = true
+			continue
+		}
+		if scalarValue(item, "type") != "separator" {
+			pending = append(pending, item)
+		}
+	}
+
+	// The first quit item takes Q unless an item claimed it explicitly
+	for i, item := range pending {
+		if scalarValue(item, "type") == "quit" {
+			if !used[quitHotkey] {
+				setHotkey(item, quitHotkey)
+				used[quitHotkey] = true
+				pending = append(pending[:i], pending[i+1:]...)
+			}
+			break
+		}
+	}
+
+	// One pass per preference, so an item only falls back to a worse letter
+	// once every item has tried the better ones
+	assigned := make(map[*yaml.Node]bool)
+	for tier := 0; tier < 3; tier++ {
+		for _, item := range pending {
+			if assigned[item] {
+				continue
+			}
+			for _, hotkey := range hotkeyCandidates(scalarValue(item, "label"), tier) {
+				if !used[hotkey] {
+					setHotkey(item, hotkey)
+					used[hotkey] = true
+					assigned[item] = true
+					break
+				}
+			}
+		}
+	}
+}
+
+// hotkeyCandidates returns the upper-cased letters of label for a preference
+// tier: 0 is its first letter, 1 the first letters of its other words, and
+// 2 all its letters.
+func hotkeyCandidates(label string, tier int) []string {
+	var out []string
+	wordStart := true
+	for _, ch := range label {
+		if !unicode.IsLetter(ch) {
+			wordStart = wordStart || unicode.IsSpace(ch) || ch == '-' || ch == '_'
+			continue
+		}
+		if tier == 2 || wordStart {
+			out = append(out, strings.ToUpper(string(ch)))
+		}
+		wordStart = false
+	}
+	switch {
+	case tier == 0 && len(out) > 1:
+		return out[:1]
+	case tier == 1 && len(out) > 0:
+		return out[1:]
+	}
+	return out
+}
+
+// setHotkey adds a hotkey key to an item, after its label when it has one.
+func setHotkey(item *yaml.Node, hotkey string) {
+	key := &yaml.Node{Kind: yaml.ScalarNode, Value: "hotkey"}
+	value := &yaml.Node{Kind: yaml.ScalarNode, Value: hotkey}
+	at := len(item.Content)
+	for i := 0; i+1 < len(item.Content); i += 2 {
+		if item.Content[i].Value == "label" {
+			at = i + 2
+			break
+		}
+	}
+	content := append([]*yaml.Node{}, item.Content[:at]...)
+	content = append(content, key, value)
+	item.Content = append(content, item.Content[at:]...)
+}
+
+// mappingValue returns the value node for key in a mapping node, or nil.
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// scalarValue returns the string value for key in a mapping node, or "".
+func scalarValue(node *yaml.Node, key string) string {
+	if v := mappingValue(node, key); v != nil && v.Kind == yaml.ScalarNode {
+		return v.Value
+	}
+	return ""
+}
@@ -0,0 +1,159 @@
+package discover
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestAssignHotkeys(t *testing.T) {
+	apps := []DiscoveredApp{
+		{Name: "Editor", Exec: "vim", Category: "Tools"},
+		{Name: "Emacs", Exec: "emacs", Category: "Tools"},
+		{Name: "Visual Editor", Exec: "code", Category: "Tools"},
+		{Name: "Bash", Exec: "bash", Category: "Tools", Confidence: 20},
+		{Name: "Quake", Exec: "quake", Category: "Games"},
+	}
+	var buf bytes.Buffer
+	if err := RenderConfig(apps, &buf); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := AssignHotkeys(buf.Bytes())
+	if err != nil {
+		t.Fatalf("AssignHotkeys failed: %v", err)
+	}
+	var cfg fullConfig
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		t.Fatalf("result is invalid: %v", err)
+	}
+
+	hotkeys := func(items []fullItem) string {
+		var keys []string
+		for _, item := range items {
+			keys = append(keys, item.Hotkey)
+		}
+		return strings.Join(keys, ",")
+	}
+	// Games and Tools, the separator, then Quit keeps Q
+	if got := hotkeys(cfg.Items); got != "G,T,,Q" {
+		t.Errorf("root hotkeys = %s", got)
+	}
+	// Emacs loses E to Editor and falls back to M; Back loses B to Bash
+	if got := hotkeys(cfg.Menus["tools"].Items); got != "E,M,V,B,,A" {
+		t.Errorf("tools hotkeys = %s", got)
+	}
+	// Quit is only reserved in menus that have a quit item
+	if got := hotkeys(cfg.Menus["games"].Items); got != "Q,,B" {
+		t.Errorf("games hotkeys = %s", got)
+	}
+	if !strings.Contains(string(data), "# Low confidence") {
+		t.Errorf("comments should be kept:\n%s", data)
+	}
+}
+
+func TestAssignHotkeysKeepsExplicit(t *testing.T) {
+	base := `
+items:
+  - type: command
+    label: "Quit Smoking Tracker"
+    hotkey: q
+  - type: command
+    label: "Tracker"
+  - type: quit
+`
+	data, err := AssignHotkeys([]byte(base))
+	if err != nil {
+		t.Fatalf("AssignHotkeys failed: %v", err)
+	}
+	var cfg fullConfig
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Items[0].Hotkey != "q" || cfg.Items[1].Hotkey != "T" {
+		t.Errorf("unexpected hotkeys: %+v", cfg.Items)
+	}
+	// Q is taken and the quit item has no label to pick from
+	if cfg.Items[2].Hotkey != "" {
+		t.Errorf("quit should be left to the runtime, got %q", cfg.Items[2].Hotkey)
+	}
+}
+
+func TestAssignHotkeysSkipsReloadKey(t *testing.T) {
+	base := `
+items:
+  - type: command
+    label: "RetroArch"
+  - type: command
+    label: "Rocket League"
+  - type: command
+    label: "R"
+`
+	data, err := AssignHotkeys([]byte(base))
+	if err != nil {
+		t.Fatalf("AssignHotkeys failed: %v", err)
+	}
+	var cfg fullConfig
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		t.Fatal(err)
+	}
+	// R reloads the config, so each item falls back to another letter
+	if cfg.Items[0].Hotkey != "E" || cfg.Items[1].Hotkey != "L" {
+		t.Errorf("expected R skipped, got %q and %q", cfg.Items[0].Hotkey, cfg.Items[1].Hotkey)
+	}
+	if cfg.Items[2].Hotkey != "" {
+		t.Errorf("expected an item with only R left to the runtime, got %q", cfg.Items[2].Hotkey)
+	}
+}
+
+func TestAssignHotkeysFollowsReloadBinding(t *testing.T) {
+	base := `
+keybindings:
+  reload: f6
+items:
+  - type: command
+    label: "RetroArch"
+menus:
+  tools:
+    items:
+      - type: command
+        label: "Reader"
+`
+	data, err := AssignHotkeys([]byte(base))
+	if err != nil {
+		t.Fatalf("AssignHotkeys failed: %v", err)
+	}
+	var cfg fullConfig
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		t.Fatal(err)
+	}
+	// With reload on F6, R is free again in every menu
+	if cfg.Items[0].Hotkey != "R" || cfg.Menus["tools"].Items[0].Hotkey != "R" {
+		t.Errorf("expected R assigned, got %q and %q", cfg.Items[0].Hotkey, cfg.Menus["tools"].Items[0].Hotkey)
+	}
+	if cfg.KeyBindings == nil || cfg.KeyBindings.Reload != "f6" {
+		t.Errorf("keybindings not kept: %+v", cfg.KeyBindings)
+	}
+}
+
+func TestHotkeyCandidates(t *testing.T) {
+	cases := []struct {
+		label string
+		tier  int
+		want  string
+	}{
+		{"Visual Studio Code", 0, "V"},
+		{"Visual Studio Code", 1, "S,C"},
+		{"7 Days to Die", 0, "D"},
+		{"Baldur's Gate", 1, "G"},
+		{"Go", 2, "G,O"},
+		{"", 0, ""},
+	}
+	for _, c := range cases {
+		if got := strings.Join(hotkeyCandidates(c.label, c.tier), ","); got != c.want {
+			t.Errorf("hotkeyCandidates(%q, %d) = %s, expected %s", c.label, c.tier, got, c.want)
+		}
+	}
+}
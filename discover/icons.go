@@ -0,0 +1,54 @@
+package discover
+
+import "strings"
+
+// IconSuggester is implemented by sources that suggest an icon for the menu
+// their category becomes, such as a game controller for Steam.
+type IconSuggester interface {
+	CategoryIcon() string
+}
+
+// categoryIcons are the icons suggested for well-known category names, for
+// sources that suggest none and for categories set with SetCategory.
+var categoryIcons = map[string]string{
+	"applications": "📦",
+	"apps":         "📦",
+	"development":  "💻",
+	"education":    "🎓",
+	"emulators":    "🕹",
+	"games":        "🎮",
+	"graphics":     "🎨",
+	"internet":     "🌐",
+	"media":        "🎬",
+	"music":        "🎵",
+	"office":       "📄",
+	"settings":     "⚙",
+	"system":       "⚙",
+	"tools":        "🔧",
+	"utilities":    "🔧",
+}
+
+// SuggestIcon returns the icon suggested for a category name, or "" if it is
+// not a well-known one.
+func SuggestIcon(category string) string {
+	return categoryIcons[strings.ToLower(category)]
+}
+
+// SuggestIcons returns a copy of apps with CategoryIcon filled in: the icon
+// its source suggests while the app is in the source's own category, or else
+// the one SuggestIcon gives its category.
+func (r *Registry) SuggestIcons(apps []DiscoveredApp) []DiscoveredApp {
+	out := make([]DiscoveredApp, len(apps))
+	for i, a := range apps {
+		if a.CategoryIcon == "" {
+			src := r.SourceByName(a.Source)
+			if s, ok := src.(IconSuggester); ok && a.Category == src.Category() {
+				a.CategoryIcon = s.CategoryIcon()
+			} else {
+				a.CategoryIcon = SuggestIcon(a.Category)
+			}
+		}
+		out[i] = a
+	}
+	return out
+}
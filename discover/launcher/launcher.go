@@ -0,0 +1,415 @@
+// Package launcher reads the menus of other launchers (a folder of .desktop
+// files, a CSV list, or a PowerShell export of Windows shortcuts) as
+// discovered apps, so "menuworks import" can merge them into a config the
+// same way "menuworks generate" does.
+package launcher
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/benworks/menuworks/discover"
+	"github.com/benworks/menuworks/shell"
+)
+
+// Formats lists the formats Read understands.
+var Formats = []string{"desktop", "csv", "json"}
+
+// Source is the source label given to imported apps.
+const Source = "Imported"
+
+// DefaultCategory is used for entries that do not name a category.
+const DefaultCategory = "Applications"
+
+// DetectFormat picks a format for path: "desktop" for a directory or .desktop
+// file, otherwise from the file extension.
+func DetectFormat(path string) (string, error) {
+	if info, err := os.Stat(path); err == nil && info.IsDir() {
+		return "desktop", nil
+	}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".desktop":
+		return "desktop", nil
+	case ".csv":
+		return "csv", nil
+	case ".json":
+		return "json", nil
+	}
+	return "", fmt.Errorf("cannot tell the format of %s; use --format (%s)", path, strings.Join(Formats, ", "))
+}
+
+// Read imports the apps described at path in the given format.
+func Read(format, path string) ([]discover.DiscoveredApp, error) {
+	switch format {
+	case "desktop":
+		return ReadDesktop(path)
+	case "csv", "json":
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		if format == "csv" {
+			return ReadCSV(f)
+		}
+		return ReadShortcutsJSON(f)
+	}
+	return nil, fmt.Errorf("unknown format %q (expected %s)", format, strings.Join(Formats, ", "))
+}
+
+// ReadDesktop reads a .desktop file, or every .desktop file under a
+// directory, skipping entries that are hidden or not applications.
+func ReadDesktop(path string) ([]discover.DiscoveredApp, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	files := []string{path}
+	if info.IsDir() {
+		files = nil
+		err := filepath.Walk(path, func(p string, fi os.FileInfo, err error) error {
+			if err == nil && !fi.IsDir() && strings.HasSuffix(p, ".desktop") {
+				files = append(files, p)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		sort.Strings(files)
+	}
+
+	var apps []discover.DiscoveredApp
+	for _, file := range files {
+		f, err := os.Open(file)
+		if err != nil {
+			return nil, err
+		}
+		app, err := parseDesktopEntry(f)
+		f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", file, err)
+		}
+		if app != nil {
+			apps = append(apps, *app)
+		}
+	}
+	return apps, nil
+}
+
+// desktopCategories maps freedesktop.org main categories to menu categories.
+var desktopCategories = map[string]string{
+	"AudioVideo":  "Multimedia",
+	"Audio":       "Multimedia",
+	"Video":       "Multimedia",
+	"Development": "Development",
+	"Education":   "Education",
+	"Game":        "Games",
+	"Graphics":    "Graphics",
+	"Network":     "Internet",
+	"Office":      "Office",
+	"Science":     "Science",
+	"Settings":    "Settings",
+	"System":      "System",
+	"Utility":     "Utilities",
+}
+
+// parseDesktopEntry reads the [Desktop Entry] group of a .desktop file. It
+// returns nil for entries that are hidden or not applications. Unlike the
+// Linux desktop source it keeps terminal apps, since the user chose to list
+// them, and takes the category from Categories=.
+func parseDesktopEntry(r io.Reader) (*discover.DiscoveredApp, error) {
+	var name, execCmd, comment, path, categories, entryType string
+	var noDisplay, hidden, terminal bool
+	inEntry := false
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if strings.HasPrefix(line, "[") {
+			inEntry = line == "[Desktop Entry]"
+			continue
+		}
+		if !inEntry {
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !ok || strings.ContainsRune(key, '[') { // skip localized keys like Name[fr]
+			continue
+		}
+		switch strings.TrimSpace(key) {
+		case "Name":
+			name = strings.TrimSpace(value)
+		case "Exec":
+			execCmd = strings.TrimSpace(value)
+		case "Comment":
+			comment = strings.TrimSpace(value)
+		case "Path":
+			path = strings.TrimSpace(value)
+		case "Categories":
+			categories = value
+		case "Type":
+			entryType = strings.TrimSpace(value)
+		case "NoDisplay":
+			noDisplay = strings.EqualFold(strings.TrimSpace(value), "true")
+		case "Hidden":
+			hidden = strings.EqualFold(strings.TrimSpace(value), "true")
+		case "Terminal":
+			terminal = strings.EqualFold(strings.TrimSpace(value), "true")
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if entryType != "Application" || noDisplay || hidden || name == "" || execCmd == "" {
+		return nil, nil
+	}
+
+	execCmd = removeFieldCodes(execCmd)
+	argv, err := shell.Split("linux", execCmd)
+	if err != nil {
+		argv = nil
+	}
+	return &discover.DiscoveredApp{
+		Name:        name,
+		Exec:        execCmd,
+		Argv:        argv,
+		Source:      Source,
+		Category:    desktopCategory(categories),
+		Description: comment,
+		WorkDir:     path,
+		Terminal:    terminal,
+	}, nil
+}
+
+// desktopCategory returns the menu category for the first main category in a
+// Categories= list.
+func desktopCategory(categories string) string {
+	for _, c := range strings.Split(categories, ";") {
+		if cat, ok := desktopCategories[strings.TrimSpace(c)]; ok {
+			return cat
+		}
+	}
+	return DefaultCategory
+}
+
+// removeFieldCodes drops XDG field codes (%f, %U, ...) from an Exec value
+// and turns %% into %.
+func removeFieldCodes(execCmd string) string {
+	var kept []string
+	for _, f := range strings.Fields(execCmd) {
+		if len(f) == 2 && f[0] == '%' && f[1] != '%' {
+			continue
+		}
+		kept = append(kept, strings.ReplaceAll(f, "%%", "%"))
+	}
+	return strings.Join(kept, " ")
+}
+
+// csvColumns maps the header names ReadCSV accepts, lower-cased, to the
+// field they fill. TargetPath, Arguments, WorkingDirectory, and Description
+// are the property names of a WScript.Shell shortcut, so Export-Csv output of
+// shortcuts reads as is.
+var csvColumns = map[string]string{
+	"name":             "name",
+	"label":            "name",
+	"title":            "name",
+	"fullname":         "fullname",
+	"command":          "command",
+	"exec":             "command",
+	"targetpath":       "program",
+	"target":           "program",
+	"program":          "program",
+	"arguments":        "args",
+	"args":             "args",
+	"workingdirectory": "workdir",
+	"workdir":          "workdir",
+	"category":         "category",
+	"description":      "description",
+	"help":             "description",
+}
+
+// ReadCSV reads apps from CSV with a header row. A command column holds a
+// whole command line; a program (or TargetPath) column holds a path, with an
+// optional arguments column. Without a name, a row is named after its
+// shortcut file (FullName). Rows without a name or command are skipped. A
+// leading "#TYPE" line, as Windows PowerShell's Export-Csv writes, is ignored.
+func ReadCSV(r io.Reader) ([]discover.DiscoveredApp, error) {
+	br := bufio.NewReader(r)
+	if first, err := br.Peek(5); err == nil && string(first) == "#TYPE" {
+		br.ReadString('\n')
+	}
+	cr := csv.NewReader(br)
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+
+	header, err := cr.Read()
+	if err == io.EOF {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	cols := make(map[string]int)
+	for i, h := range header {
+		h = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(h, "\ufeff")))
+		if field, ok := csvColumns[h]; ok {
+			if _, dup := cols[field]; !dup {
+				cols[field] = i
+			}
+		}
+	}
+	_, hasName := cols["name"]
+	_, hasFullName := cols["fullname"]
+	if !hasName && !hasFullName {
+		return nil, fmt.Errorf("CSV header has no name column (name, label, title, or FullName)")
+	}
+	_, hasCommand := cols["command"]
+	_, hasProgram := cols["program"]
+	if !hasCommand && !hasProgram {
+		return nil, fmt.Errorf("CSV header has no command column (command, exec, program, or TargetPath)")
+	}
+
+	var apps []discover.DiscoveredApp
+	for {
+		rec, err := cr.Read()
+		if err == io.EOF {
+			return apps, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		get := func(field string) string {
+			if i, ok := cols[field]; ok && i < len(rec) {
+				return strings.TrimSpace(rec[i])
+			}
+			return ""
+		}
+		name := get("name")
+		if name == "" {
+			name = shortcutName(get("fullname"))
+		}
+		app, ok := newApp(name, get("command"), get("program"), get("args"))
+		if !ok {
+			continue
+		}
+		app.WorkDir = get("workdir")
+		app.Description = get("description")
+		if c := get("category"); c != "" {
+			app.Category = c
+		}
+		apps = append(apps, app)
+	}
+}
+
+// shortcut is one object of a PowerShell shortcut export, such as
+//
+//	Get-ChildItem *.lnk | ForEach-Object { $sh.CreateShortcut($_.FullName) } |
+//	    Select-Object FullName, TargetPath, Arguments, WorkingDirectory, Description |
+//	    ConvertTo-Json
+type shortcut struct {
+	Name             string `json:"Name"`
+	FullName         string `json:"FullName"`
+	TargetPath       string `json:"TargetPath"`
+	Arguments        string `json:"Arguments"`
+	WorkingDirectory string `json:"WorkingDirectory"`
+	Description      string `json:"Description"`
+	Category         string `json:"Category"`
+}
+
+// ReadShortcutsJSON reads a ConvertTo-Json export of Windows shortcuts: an
+// array, or a single object when there was one shortcut. An entry without a
+// Name is named after its .lnk file (FullName).
+func ReadShortcutsJSON(r io.Reader) ([]discover.DiscoveredApp, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	data = []byte(strings.TrimPrefix(strings.TrimSpace(string(data)), "\ufeff"))
+	if len(data) == 0 {
+		return nil, nil
+	}
+
+	var shortcuts []shortcut
+	if data[0] == '[' {
+		err = json.Unmarshal(data, &shortcuts)
+	} else {
+		var single shortcut
+		err = json.Unmarshal(data, &single)
+		shortcuts = []shortcut{single}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid JSON: %w", err)
+	}
+
+	var apps []discover.DiscoveredApp
+	for _, s := range shortcuts {
+		name := s.Name
+		if name == "" {
+			name = shortcutName(s.FullName)
+		}
+		app, ok := newApp(name, "", s.TargetPath, s.Arguments)
+		if !ok {
+			continue
+		}
+		app.WorkDir = s.WorkingDirectory
+		app.Description = s.Description
+		if s.Category != "" {
+			app.Category = s.Category
+		}
+		apps = append(apps, app)
+	}
+	return apps, nil
+}
+
+// shortcutName names a shortcut after its file, e.g. "Notepad++" for
+// C:\Users\me\Desktop\Notepad++.lnk.
+func shortcutName(fullName string) string {
+	if fullName == "" {
+		return ""
+	}
+	base := filepath.Base(strings.ReplaceAll(fullName, `\`, "/"))
+	return strings.TrimSuffix(base, filepath.Ext(base))
+}
+
+// newApp builds an imported app from either a command line or a program path
+// and its argument string. It reports false if there is nothing to run.
+func newApp(name, command, program, args string) (discover.DiscoveredApp, bool) {
+	app := discover.DiscoveredApp{Name: name, Source: Source, Category: DefaultCategory}
+	switch {
+	case name == "":
+		return app, false
+	case program != "":
+		goos := runtime.GOOS
+		if strings.Contains(program, `\`) {
+			goos = "windows" // a Windows path, as shortcuts have
+		}
+		argv := []string{program}
+		if args != "" {
+			extra, err := shell.Split(goos, args)
+			if err != nil {
+				app.Exec = shell.Quote(goos, argv) + " " + args
+				return app, true
+			}
+			argv = append(argv, extra...)
+		}
+		app.Exec = shell.Quote(goos, argv)
+		app.Argv = argv
+	case command != "":
+		app.Exec = command
+	default:
+		return app, false
+	}
+	return app, true
+}
@@ -0,0 +1,177 @@
+package launcher
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestReadDesktopDir(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"gimp.desktop": `[Desktop Entry]
+Type=Application
+Name=GIMP
+Name[fr]=GIMP (fr)
+Comment=Edit images
+Exec=gimp-2.10 %U
+Path=/tmp
+Categories=Graphics;2DGraphics;
+`,
+		"sub/htop.desktop": `[Desktop Entry]
+Type=Application
+Name=Htop
+Exec=htop
+Terminal=true
+Categories=System;Monitor;
+`,
+		"hidden.desktop": `[Desktop Entry]
+Type=Application
+Name=Hidden
+Exec=hidden
+NoDisplay=true
+`,
+		"link.desktop": `[Desktop Entry]
+Type=Link
+Name=Website
+URL=https://example.com
+`,
+		"notes.txt": "not a desktop file",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		os.MkdirAll(filepath.Dir(path), 0755)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	apps, err := ReadDesktop(dir)
+	if err != nil {
+		t.Fatalf("ReadDesktop failed: %v", err)
+	}
+	if len(apps) != 2 {
+		t.Fatalf("expected 2 apps, got %d: %v", len(apps), apps)
+	}
+	gimp := apps[0]
+	if gimp.Name != "GIMP" || gimp.Category != "Graphics" || gimp.Description != "Edit images" || gimp.WorkDir != "/tmp" {
+		t.Errorf("unexpected GIMP entry: %+v", gimp)
+	}
+	if !reflect.DeepEqual(gimp.Argv, []string{"gimp-2.10"}) || gimp.Source != Source {
+		t.Errorf("unexpected GIMP command: %+v", gimp)
+	}
+	if apps[1].Name != "Htop" || apps[1].Category != "System" || !apps[1].Terminal {
+		t.Errorf("terminal apps should be imported: %+v", apps[1])
+	}
+}
+
+func TestRemoveFieldCodes(t *testing.T) {
+	got := removeFieldCodes("app --rate 100%% %f %U")
+	if got != "app --rate 100%" {
+		t.Errorf("removeFieldCodes = %q", got)
+	}
+}
+
+func TestReadCSV(t *testing.T) {
+	csv := `name,command,category,help
+Editor,vim ~/notes.txt,Tools,Edit notes
+,missing-name,,
+Broken,,,
+Top,htop,,
+`
+	apps, err := ReadCSV(strings.NewReader(csv))
+	if err != nil {
+		t.Fatalf("ReadCSV failed: %v", err)
+	}
+	if len(apps) != 2 {
+		t.Fatalf("expected 2 apps, got %d: %v", len(apps), apps)
+	}
+	if apps[0].Exec != "vim ~/notes.txt" || apps[0].Argv != nil || apps[0].Category != "Tools" || apps[0].Description != "Edit notes" {
+		t.Errorf("unexpected first app: %+v", apps[0])
+	}
+	if apps[1].Category != DefaultCategory {
+		t.Errorf("expected default category, got %q", apps[1].Category)
+	}
+}
+
+func TestReadCSVPowerShellExport(t *testing.T) {
+	csv := `#TYPE System.__ComObject#{a274d7f4-8ff6-4e24-b7ab-c5f3ed6c8ee6}
+"FullName","TargetPath","Arguments","WorkingDirectory","Description"
+"C:\Users\me\Desktop\Notepad++.lnk","C:\Program Files\Notepad++\notepad++.exe","-multiInst ""C:\notes.txt""","C:\Program Files\Notepad++","Text editor"
+`
+	apps, err := ReadCSV(strings.NewReader(csv))
+	if err != nil {
+		t.Fatalf("ReadCSV failed: %v", err)
+	}
+	if len(apps) != 1 {
+		t.Fatalf("expected 1 app, got %d", len(apps))
+	}
+	a := apps[0]
+	expectedArgv := []string{`C:\Program Files\Notepad++\notepad++.exe`, "-multiInst", `C:\notes.txt`}
+	if a.Name != "Notepad++" || !reflect.DeepEqual(a.Argv, expectedArgv) {
+		t.Errorf("unexpected app: %+v", a)
+	}
+	if a.Exec != `"C:\Program Files\Notepad++\notepad++.exe" -multiInst C:\notes.txt` {
+		t.Errorf("unexpected exec: %q", a.Exec)
+	}
+	if a.WorkDir != `C:\Program Files\Notepad++` || a.Description != "Text editor" {
+		t.Errorf("unexpected metadata: %+v", a)
+	}
+}
+
+func TestReadCSVRequiresColumns(t *testing.T) {
+	for _, csv := range []string{"command\nls\n", "name\nls\n"} {
+		if _, err := ReadCSV(strings.NewReader(csv)); err == nil {
+			t.Errorf("expected error for header %q", strings.SplitN(csv, "\n", 2)[0])
+		}
+	}
+}
+
+func TestReadShortcutsJSON(t *testing.T) {
+	data := `[
+  {"FullName": "C:\\Users\\me\\Desktop\\PuTTY.lnk", "TargetPath": "C:\\Tools\\putty.exe", "Arguments": "", "WorkingDirectory": "", "Description": ""},
+  {"Name": "Control Panel", "TargetPath": "", "Arguments": ""}
+]`
+	apps, err := ReadShortcutsJSON(strings.NewReader(data))
+	if err != nil {
+		t.Fatalf("ReadShortcutsJSON failed: %v", err)
+	}
+	if len(apps) != 1 || apps[0].Name != "PuTTY" || !reflect.DeepEqual(apps[0].Argv, []string{`C:\Tools\putty.exe`}) {
+		t.Errorf("unexpected apps: %v", apps)
+	}
+
+	// ConvertTo-Json writes a bare object for a single shortcut
+	single := `{"Name": "Calc", "TargetPath": "C:\\Windows\\System32\\calc.exe", "Category": "Utilities"}`
+	apps, err = ReadShortcutsJSON(strings.NewReader(single))
+	if err != nil || len(apps) != 1 || apps[0].Category != "Utilities" {
+		t.Errorf("single object: got %v, %v", apps, err)
+	}
+
+	if _, err := ReadShortcutsJSON(strings.NewReader("{nope")); err == nil {
+		t.Error("expected error for invalid JSON")
+	}
+}
+
+func TestDetectFormat(t *testing.T) {
+	dir := t.TempDir()
+	cases := map[string]string{
+		dir:              "desktop",
+		"apps.desktop":   "desktop",
+		"menu.CSV":       "csv",
+		"shortcuts.json": "json",
+	}
+	for path, expected := range cases {
+		got, err := DetectFormat(path)
+		if err != nil || got != expected {
+			t.Errorf("DetectFormat(%q) = %q, %v; expected %q", path, got, err, expected)
+		}
+	}
+	if _, err := DetectFormat("menu.txt"); err == nil {
+		t.Error("expected error for unknown extension")
+	}
+	if _, err := Read("xml", "menu.xml"); err == nil {
+		t.Error("expected error for unknown format")
+	}
+}
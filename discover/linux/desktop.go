@@ -4,11 +4,13 @@ package linux
 
 import (
 	"bufio"
+	"context"
 	"os"
 	"path/filepath"
 	"strings"
 
 	"github.com/benworks/menuworks/discover"
+	"github.com/benworks/menuworks/shell"
 )
 
 // DesktopSource discovers applications from XDG .desktop files.
@@ -17,6 +19,8 @@ type DesktopSource struct{}
 func (s *DesktopSource) Name() string     { return "desktop" }
 func (s *DesktopSource) Category() string { return "Applications" }
 
+func (s *DesktopSource) CategoryIcon() string { return "📦" }
+
 func (s *DesktopSource) Available() bool {
 	for _, dir := range desktopDirs() {
 		if _, err := os.Stat(dir); err == nil {
@@ -26,11 +30,29 @@ func (s *DesktopSource) Available() bool {
 	return false
 }
 
-func (s *DesktopSource) Discover() ([]discover.DiscoveredApp, error) {
+// Diagnose lists the XDG application directories looked in.
+func (s *DesktopSource) Diagnose() []discover.Check {
+	var checks []discover.Check
+	for _, dir := range desktopDirs() {
+		checks = append(checks, discover.PathCheck(dir))
+	}
+	return checks
+}
+
+// WatchPaths returns the XDG application directories, where a package
+// installs its .desktop file.
+func (s *DesktopSource) WatchPaths() []string {
+	return desktopDirs()
+}
+
+func (s *DesktopSource) Discover(ctx context.Context) ([]discover.DiscoveredApp, error) {
 	var apps []discover.DiscoveredApp
 	seen := make(map[string]bool)
 
 	for _, dir := range desktopDirs() {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
 		if _, err := os.Stat(dir); err != nil {
 			continue
 		}
@@ -163,9 +185,17 @@ func parseDesktopReader(scanner *bufio.Scanner) (*discover.DiscoveredApp, error)
 	// Clean up Exec line: remove field codes (%f, %F, %u, %U, etc.)
 	execCmd = cleanExecLine(execCmd)
 
+	// Exec lines quote arguments like sh does; one that doesn't parse is kept
+	// as a command line for the shell
+	argv, err := shell.Split("linux", execCmd)
+	if err != nil {
+		argv = nil
+	}
+
 	return &discover.DiscoveredApp{
 		Name:     name,
 		Exec:     execCmd,
+		Argv:     argv,
 		Source:   "Desktop",
 		Category: "Applications",
 	}, nil
@@ -3,12 +3,14 @@
 package linux
 
 import (
+	"context"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"strings"
 
 	"github.com/benworks/menuworks/discover"
+	"github.com/benworks/menuworks/shell"
 )
 
 // FlatpakSource discovers applications installed via Flatpak.
@@ -17,18 +19,25 @@ type FlatpakSource struct{}
 func (s *FlatpakSource) Name() string     { return "flatpak" }
 func (s *FlatpakSource) Category() string { return "Applications" }
 
+func (s *FlatpakSource) CategoryIcon() string { return "📦" }
+
 func (s *FlatpakSource) Available() bool {
 	_, err := exec.LookPath("flatpak")
 	return err == nil
 }
 
-func (s *FlatpakSource) Discover() ([]discover.DiscoveredApp, error) {
-	return discoverFlatpak()
+// Diagnose reports where the flatpak command was found.
+func (s *FlatpakSource) Diagnose() []discover.Check {
+	return []discover.Check{discover.LookPathCheck("flatpak")}
+}
+
+func (s *FlatpakSource) Discover(ctx context.Context) ([]discover.DiscoveredApp, error) {
+	return discoverFlatpak(ctx)
 }
 
-func discoverFlatpak() ([]discover.DiscoveredApp, error) {
+func discoverFlatpak(ctx context.Context) ([]discover.DiscoveredApp, error) {
 	// List installed Flatpak apps (not runtimes)
-	out, err := exec.Command("flatpak", "list", "--app", "--columns=application,name").Output()
+	out, err := exec.CommandContext(ctx, "flatpak", "list", "--app", "--columns=application,name,version").Output()
 	if err != nil {
 		return nil, err
 	}
@@ -36,7 +45,8 @@ func discoverFlatpak() ([]discover.DiscoveredApp, error) {
 	return parseFlatpakOutput(string(out))
 }
 
-// parseFlatpakOutput parses the output of `flatpak list --app --columns=application,name`.
+// parseFlatpakOutput parses the output of `flatpak list --app --columns=application,name,version`.
+// The version column is optional.
 func parseFlatpakOutput(output string) ([]discover.DiscoveredApp, error) {
 	var apps []discover.DiscoveredApp
 	seen := make(map[string]bool)
@@ -47,14 +57,18 @@ func parseFlatpakOutput(output string) ([]discover.DiscoveredApp, error) {
 			continue
 		}
 
-		// Format: "application.id\tDisplay Name"
-		parts := strings.SplitN(line, "\t", 2)
+		// Format: "application.id\tDisplay Name\tVersion"
+		parts := strings.SplitN(line, "\t", 3)
 		if len(parts) < 2 {
 			continue
 		}
 
 		appID := strings.TrimSpace(parts[0])
 		name := strings.TrimSpace(parts[1])
+		version := ""
+		if len(parts) == 3 {
+			version = strings.TrimSpace(parts[2])
+		}
 
 		if appID == "" || name == "" {
 			continue
@@ -68,12 +82,15 @@ func parseFlatpakOutput(output string) ([]discover.DiscoveredApp, error) {
 
 		// Check for .desktop file to get the actual Exec command
 		execCmd := flatpakExecCommand(appID)
+		argv, _ := shell.Split("linux", execCmd)
 
 		apps = append(apps, discover.DiscoveredApp{
 			Name:     name,
 			Exec:     execCmd,
+			Argv:     argv,
 			Source:   "Flatpak",
 			Category: "Applications",
+			Version:  version,
 		})
 	}
 
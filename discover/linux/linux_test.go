@@ -268,6 +268,16 @@ func TestParseFlatpakOutput(t *testing.T) {
 	}
 }
 
+func TestParseFlatpakOutputVersion(t *testing.T) {
+	apps, err := parseFlatpakOutput("org.gimp.GIMP\tGIMP\t2.10.38\ncom.example.App\tExample\t\n")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(apps) != 2 || apps[0].Version != "2.10.38" || apps[1].Version != "" {
+		t.Errorf("expected the version column read when present, got %+v", apps)
+	}
+}
+
 func TestParseFlatpakOutputEmpty(t *testing.T) {
 	apps, err := parseFlatpakOutput("")
 	if err != nil {
@@ -321,6 +331,28 @@ snapd     2.63     101  latest/stable  canonical    snapd
 	}
 }
 
+func TestParseSnapOutputMetadata(t *testing.T) {
+	output := `Name      Version  Rev  Tracking       Publisher     Notes
+firefox   128.0    123  latest/stable  mozilla✓      -
+htop      3.3.0    45   latest/stable  maxiberta**   -
+mytool    0.1      2    latest/edge    -             -
+`
+	apps, err := parseSnapOutput(output)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []struct{ version, publisher string }{
+		{"128.0", "mozilla"},
+		{"3.3.0", "maxiberta"},
+		{"0.1", ""},
+	}
+	for i, w := range want {
+		if apps[i].Version != w.version || apps[i].Publisher != w.publisher {
+			t.Errorf("app[%d] = %q by %q, want %q by %q", i, apps[i].Version, apps[i].Publisher, w.version, w.publisher)
+		}
+	}
+}
+
 func TestParseSnapOutputEmpty(t *testing.T) {
 	output := `Name  Version  Rev  Tracking  Publisher  Notes
 `
@@ -3,10 +3,12 @@
 package linux
 
 import (
+	"context"
 	"os/exec"
 	"strings"
 
 	"github.com/benworks/menuworks/discover"
+	"github.com/benworks/menuworks/shell"
 )
 
 // SnapSource discovers applications installed via Snap.
@@ -15,17 +17,24 @@ type SnapSource struct{}
 func (s *SnapSource) Name() string     { return "snap" }
 func (s *SnapSource) Category() string { return "Applications" }
 
+func (s *SnapSource) CategoryIcon() string { return "📦" }
+
 func (s *SnapSource) Available() bool {
 	_, err := exec.LookPath("snap")
 	return err == nil
 }
 
-func (s *SnapSource) Discover() ([]discover.DiscoveredApp, error) {
-	return discoverSnap()
+// Diagnose reports where the snap command was found.
+func (s *SnapSource) Diagnose() []discover.Check {
+	return []discover.Check{discover.LookPathCheck("snap")}
+}
+
+func (s *SnapSource) Discover(ctx context.Context) ([]discover.DiscoveredApp, error) {
+	return discoverSnap(ctx)
 }
 
-func discoverSnap() ([]discover.DiscoveredApp, error) {
-	out, err := exec.Command("snap", "list").Output()
+func discoverSnap(ctx context.Context) ([]discover.DiscoveredApp, error) {
+	out, err := exec.CommandContext(ctx, "snap", "list").Output()
 	if err != nil {
 		return nil, err
 	}
@@ -57,6 +66,11 @@ func parseSnapOutput(output string) ([]discover.DiscoveredApp, error) {
 		}
 
 		name := fields[0]
+		version := fields[1]
+		publisher := ""
+		if len(fields) >= 5 {
+			publisher = snapPublisher(fields[4])
+		}
 
 		// Filter out system snaps
 		if isSystemSnap(name) {
@@ -69,17 +83,31 @@ func parseSnapOutput(output string) ([]discover.DiscoveredApp, error) {
 		}
 		seen[key] = true
 
+		argv := []string{"snap", "run", name}
 		apps = append(apps, discover.DiscoveredApp{
-			Name:     name,
-			Exec:     "snap run " + name,
-			Source:   "Snap",
-			Category: "Applications",
+			Name:      name,
+			Exec:      shell.Quote("linux", argv),
+			Argv:      argv,
+			Source:    "Snap",
+			Category:  "Applications",
+			Version:   version,
+			Publisher: publisher,
 		})
 	}
 
 	return apps, nil
 }
 
+// snapPublisher strips the verified (✓) and starred (✪, or * and ** without
+// Unicode) marks snap list adds to publisher names; "-" means none.
+func snapPublisher(field string) string {
+	field = strings.TrimRight(field, "✓✪*")
+	if field == "-" {
+		return ""
+	}
+	return field
+}
+
 // isSystemSnap returns true if the snap is a core/system component.
 func isSystemSnap(name string) bool {
 	systemSnaps := map[string]bool{
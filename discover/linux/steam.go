@@ -4,49 +4,117 @@ package linux
 
 import (
 	"bufio"
+	"context"
 	"fmt"
 	"os"
 	"path/filepath"
 	"regexp"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/benworks/menuworks/discover"
+	"github.com/benworks/menuworks/logging"
+	"github.com/benworks/menuworks/shell"
 )
 
 // SteamSource discovers games from Steam on Linux.
-type SteamSource struct{}
+type SteamSource struct {
+	Options discover.SteamOptions
+}
 
 func (s *SteamSource) Name() string     { return "steam" }
 func (s *SteamSource) Category() string { return "Games" }
 
+func (s *SteamSource) CategoryIcon() string { return "🎮" }
+
+// Configure takes the steam: options of the discover block.
+func (s *SteamSource) Configure(cfg *discover.DiscoverConfig) {
+	s.Options = cfg.Steam
+}
+
 func (s *SteamSource) Available() bool {
 	_, err := os.Stat(defaultSteamPath())
 	return err == nil
 }
 
-func (s *SteamSource) Discover() ([]discover.DiscoveredApp, error) {
+// Diagnose lists the Steam directories looked in and, once one is found, its
+// library list.
+func (s *SteamSource) Diagnose() []discover.Check {
+	home := discover.EnvCheck("HOME")
+	if !home.OK {
+		return []discover.Check{home}
+	}
+	var checks []discover.Check
+	for _, c := range steamCandidates(home.Detail) {
+		check := discover.PathCheck(c)
+		checks = append(checks, check)
+		if check.OK {
+			checks = append(checks, discover.PathCheck(filepath.Join(c, "steamapps", "libraryfolders.vdf")))
+			break
+		}
+	}
+	return checks
+}
+
+// WatchPaths returns the steamapps directories of every library, where an
+// appmanifest file appears or goes as a game is installed or removed.
+func (s *SteamSource) WatchPaths() []string {
+	steamPath := defaultSteamPath()
+	folders, err := parseLibraryFolders(filepath.Join(steamPath, "steamapps", "libraryfolders.vdf"))
+	if err != nil {
+		folders = []string{filepath.Join(steamPath, "steamapps")}
+	}
+	return folders
+}
+
+func (s *SteamSource) Discover(ctx context.Context) ([]discover.DiscoveredApp, error) {
 	steamPath := defaultSteamPath()
 	libraryFolders, err := parseLibraryFolders(filepath.Join(steamPath, "steamapps", "libraryfolders.vdf"))
 	if err != nil {
 		libraryFolders = []string{filepath.Join(steamPath, "steamapps")}
 	}
 
-	var apps []discover.DiscoveredApp
-	seen := make(map[string]bool)
-
+	var paths []string
+	var manifests []discover.SteamManifest
 	for _, libDir := range libraryFolders {
-		manifests, _ := filepath.Glob(filepath.Join(libDir, "appmanifest_*.acf"))
-		for _, manifest := range manifests {
-			app, err := parseAppManifest(manifest)
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		files, _ := filepath.Glob(filepath.Join(libDir, "appmanifest_*.acf"))
+		for _, file := range files {
+			m, err := discover.ReadSteamManifest(file)
 			if err != nil {
 				continue
 			}
-			if seen[app.Name] {
-				continue
-			}
-			seen[app.Name] = true
-			apps = append(apps, *app)
+			paths = append(paths, file)
+			manifests = append(manifests, m)
+		}
+	}
+
+	var lastPlayed map[string]time.Time
+	if s.Options.PlayedWithinMonths > 0 {
+		lastPlayed = discover.SteamLastPlayed(steamPath)
+	}
+	filter := discover.NewSteamFilter(s.Options, manifests, lastPlayed, time.Now())
+
+	var apps []discover.DiscoveredApp
+	seen := make(map[string]bool)
+
+	for i, m := range manifests {
+		if skip, reason := filter.Skip(m); skip {
+			logging.Debug("steam app skipped", "name", m.Name, "reason", reason)
+			continue
+		}
+		app, err := parseAppManifest(paths[i])
+		if err != nil {
+			continue
 		}
+		if seen[app.Name] {
+			continue
+		}
+		seen[app.Name] = true
+		apps = append(apps, *app)
 	}
 
 	return apps, nil
@@ -59,12 +127,7 @@ func defaultSteamPath() string {
 		return ""
 	}
 
-	// Check common Linux Steam locations
-	candidates := []string{
-		filepath.Join(home, ".steam", "steam"),
-		filepath.Join(home, ".local", "share", "Steam"),
-	}
-
+	candidates := steamCandidates(home)
 	for _, c := range candidates {
 		if _, err := os.Stat(c); err == nil {
 			return c
@@ -74,6 +137,15 @@ func defaultSteamPath() string {
 	return candidates[0] // default fallback
 }
 
+// steamCandidates returns the common Linux Steam locations under home, in the
+// order they are checked.
+func steamCandidates(home string) []string {
+	return []string{
+		filepath.Join(home, ".steam", "steam"),
+		filepath.Join(home, ".local", "share", "Steam"),
+	}
+}
+
 // parseLibraryFolders parses Steam's libraryfolders.vdf to find all library paths.
 func parseLibraryFolders(path string) ([]string, error) {
 	data, err := os.ReadFile(path)
@@ -108,6 +180,7 @@ func parseAppManifest(path string) (*discover.DiscoveredApp, error) {
 	defer f.Close()
 
 	var appID, name string
+	var size int64
 	scanner := bufio.NewScanner(f)
 	for scanner.Scan() {
 		line := strings.TrimSpace(scanner.Text())
@@ -117,6 +190,8 @@ func parseAppManifest(path string) (*discover.DiscoveredApp, error) {
 				appID = v
 			case "name":
 				name = v
+			case "sizeondisk":
+				size, _ = strconv.ParseInt(v, 10, 64)
 			}
 		}
 	}
@@ -129,11 +204,14 @@ func parseAppManifest(path string) (*discover.DiscoveredApp, error) {
 		return nil, fmt.Errorf("filtered tool: %s", name)
 	}
 
+	argv := []string{"steam", "steam://rungameid/" + appID}
 	return &discover.DiscoveredApp{
-		Name:     name,
-		Exec:     fmt.Sprintf("steam steam://rungameid/%s", appID),
-		Source:   "Steam",
-		Category: "Games",
+		Name:      name,
+		Exec:      shell.Quote("linux", argv),
+		Argv:      argv,
+		Source:    "Steam",
+		Category:  "Games",
+		SizeBytes: size,
 	}, nil
 }
 
@@ -0,0 +1,238 @@
+// Package mame provides a discovery source for MAME arcade ROM sets. It is
+// not tied to an OS: the ROM directory and DAT file come from the base
+// config's discover: block.
+package mame
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/benworks/menuworks/discover"
+	"github.com/benworks/menuworks/shell"
+)
+
+// Source discovers MAME games from the ROM sets in a configured directory,
+// naming them from a DAT file when one is given.
+type Source struct {
+	Options discover.MAMEOptions
+}
+
+func (s *Source) Name() string     { return "mame" }
+func (s *Source) Category() string { return "Games" }
+
+func (s *Source) CategoryIcon() string { return "🕹" }
+
+// Configure takes the mame: options of the discover block.
+func (s *Source) Configure(cfg *discover.DiscoverConfig) {
+	s.Options = cfg.MAME
+}
+
+// Available reports whether a ROM directory is configured and exists.
+func (s *Source) Available() bool {
+	if s.Options.RomDir == "" {
+		return false
+	}
+	info, err := os.Stat(s.Options.RomDir)
+	return err == nil && info.IsDir()
+}
+
+// Diagnose reports the configured ROM directory, the DAT file, and where the
+// MAME executable was found.
+func (s *Source) Diagnose() []discover.Check {
+	if s.Options.RomDir == "" {
+		return []discover.Check{{What: "discover: mame: rom_dir", Detail: "not set"}}
+	}
+	checks := []discover.Check{discover.PathCheck(s.Options.RomDir)}
+	if s.Options.DAT != "" {
+		checks = append(checks, discover.PathCheck(s.Options.DAT))
+	}
+	return append(checks, discover.LookPathCheck(s.executable()))
+}
+
+// executable returns the configured MAME binary, or "mame".
+func (s *Source) executable() string {
+	if s.Options.Executable != "" {
+		return s.Options.Executable
+	}
+	return "mame"
+}
+
+// unknownROMConfidence scores a ROM set the DAT does not list: it may be from
+// a different MAME version, or not a game at all.
+const unknownROMConfidence = 30
+
+func (s *Source) Discover(ctx context.Context) ([]discover.DiscoveredApp, error) {
+	roms, err := romSets(s.Options.RomDir)
+	if err != nil {
+		return nil, fmt.Errorf("mame: %w", err)
+	}
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	var machines map[string]machine
+	if s.Options.DAT != "" {
+		machines, err = readDAT(s.Options.DAT, roms)
+		if err != nil {
+			return nil, fmt.Errorf("mame: %w", err)
+		}
+	}
+
+	var apps []discover.DiscoveredApp
+	for _, rom := range roms {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		argv := []string{s.executable(), "-rompath", s.Options.RomDir, rom}
+		app := discover.DiscoveredApp{
+			Name:     rom,
+			Exec:     shell.Quote(runtime.GOOS, argv),
+			Argv:     argv,
+			Source:   "MAME",
+			Category: "Games",
+		}
+		if machines != nil {
+			m, ok := machines[rom]
+			switch {
+			case !ok:
+				app.Confidence = unknownROMConfidence
+			case !m.runnable():
+				continue
+			case s.Options.SkipClones && m.CloneOf != "":
+				continue
+			default:
+				app.Name = m.Description
+				app.Publisher = m.Manufacturer
+				app.Description = m.help(rom)
+			}
+		}
+		apps = append(apps, app)
+	}
+	return apps, nil
+}
+
+// romSets returns the names of the ROM sets in dir: .zip and .7z archives,
+// without their extension, sorted and without duplicates.
+func romSets(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	seen := make(map[string]bool)
+	var roms []string
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+		ext := strings.ToLower(filepath.Ext(e.Name()))
+		if ext != ".zip" && ext != ".7z" {
+			continue
+		}
+		rom := strings.ToLower(strings.TrimSuffix(e.Name(), filepath.Ext(e.Name())))
+		if !seen[rom] {
+			seen[rom] = true
+			roms = append(roms, rom)
+		}
+	}
+	sort.Strings(roms)
+	return roms, nil
+}
+
+// machine is one <machine> (mame -listxml) or <game> (Logiqx DAT, older
+// listxml) entry.
+type machine struct {
+	Name         string `xml:"name,attr"`
+	CloneOf      string `xml:"cloneof,attr"`
+	IsBIOS       string `xml:"isbios,attr"`
+	IsDevice     string `xml:"isdevice,attr"`
+	Runnable     string `xml:"runnable,attr"`
+	Description  string `xml:"description"`
+	Year         string `xml:"year"`
+	Manufacturer string `xml:"manufacturer"`
+}
+
+// runnable reports whether the entry is a game rather than a BIOS or device.
+func (m machine) runnable() bool {
+	return m.IsBIOS != "yes" && m.IsDevice != "yes" && m.Runnable != "no"
+}
+
+// help describes the ROM set for the item's help text, e.g.
+// "pacman, 1980, Namco (Midway license), clone of puckman".
+func (m machine) help(rom string) string {
+	parts := []string{rom}
+	for _, p := range []string{m.Year, m.Manufacturer} {
+		if p != "" {
+			parts = append(parts, p)
+		}
+	}
+	if m.CloneOf != "" {
+		parts = append(parts, "clone of "+m.CloneOf)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// readDAT reads the entries for roms from a DAT file. The full MAME list
+// runs to hundreds of megabytes, so it is streamed and only the entries for
+// ROM sets present are kept.
+func readDAT(path string, roms []string) (map[string]machine, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return parseDAT(f, roms)
+}
+
+func parseDAT(r io.Reader, roms []string) (map[string]machine, error) {
+	want := make(map[string]bool, len(roms))
+	for _, rom := range roms {
+		want[rom] = true
+	}
+
+	machines := make(map[string]machine)
+	dec := xml.NewDecoder(r)
+	dec.Strict = false
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			return machines, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("reading DAT: %w", err)
+		}
+		start, ok := tok.(xml.StartElement)
+		if !ok || start.Name.Local != "machine" && start.Name.Local != "game" {
+			continue
+		}
+		if !want[strings.ToLower(attr(start, "name"))] {
+			if err := dec.Skip(); err != nil {
+				return nil, fmt.Errorf("reading DAT: %w", err)
+			}
+			continue
+		}
+		var m machine
+		if err := dec.DecodeElement(&m, &start); err != nil {
+			return nil, fmt.Errorf("reading DAT: %w", err)
+		}
+		if m.Description == "" {
+			m.Description = m.Name
+		}
+		machines[strings.ToLower(m.Name)] = m
+	}
+}
+
+func attr(e xml.StartElement, name string) string {
+	for _, a := range e.Attr {
+		if a.Name.Local == name {
+			return a.Value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,185 @@
+package mame
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/benworks/menuworks/discover"
+)
+
+const testDAT = `<?xml version="1.0"?>
+<!DOCTYPE mame [
+<!ELEMENT mame (machine+)>
+]>
+<mame build="0.261">
+	<machine name="neogeo" isbios="yes">
+		<description>Neo-Geo MV-6F</description>
+	</machine>
+	<machine name="puckman">
+		<description>PuckMan (Japan set 1)</description>
+		<year>1980</year>
+		<manufacturer>Namco</manufacturer>
+		<rom name="pm1_prg1.6e" size="2048"/>
+	</machine>
+	<machine name="pacman" cloneof="puckman" romof="puckman">
+		<description>Pac-Man (Midway)</description>
+		<year>1980</year>
+		<manufacturer>Namco (Midway license)</manufacturer>
+	</machine>
+	<machine name="z80" isdevice="yes" runnable="no">
+		<description>Zilog Z80</description>
+	</machine>
+	<machine name="galaga">
+		<description>Galaga (Namco rev. B)</description>
+	</machine>
+</mame>
+`
+
+func writeROMs(t *testing.T, names ...string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for _, n := range names {
+		if err := os.WriteFile(filepath.Join(dir, n), nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestRomSets(t *testing.T) {
+	dir := writeROMs(t, "pacman.zip", "Galaga.ZIP", "galaga.7z", "readme.txt", "mame.ini")
+	os.Mkdir(filepath.Join(dir, "samples.zip"), 0755)
+
+	roms, err := romSets(dir)
+	if err != nil {
+		t.Fatalf("romSets failed: %v", err)
+	}
+	expected := []string{"galaga", "pacman"}
+	if !reflect.DeepEqual(roms, expected) {
+		t.Errorf("got %v, expected %v", roms, expected)
+	}
+}
+
+func TestParseDATKeepsOnlyPresentROMs(t *testing.T) {
+	machines, err := parseDAT(strings.NewReader(testDAT), []string{"pacman", "neogeo"})
+	if err != nil {
+		t.Fatalf("parseDAT failed: %v", err)
+	}
+	if len(machines) != 2 {
+		t.Fatalf("expected 2 machines, got %d: %v", len(machines), machines)
+	}
+	pac := machines["pacman"]
+	if pac.Description != "Pac-Man (Midway)" || pac.CloneOf != "puckman" || pac.Year != "1980" {
+		t.Errorf("unexpected pacman entry: %+v", pac)
+	}
+	if machines["neogeo"].runnable() {
+		t.Error("BIOS entry should not be runnable")
+	}
+}
+
+func TestParseDATLogiqx(t *testing.T) {
+	dat := `<?xml version="1.0"?>
+<datafile>
+	<header><name>MAME</name></header>
+	<game name="dkong">
+		<description>Donkey Kong (US set 1)</description>
+		<manufacturer>Nintendo of America</manufacturer>
+	</game>
+</datafile>`
+	machines, err := parseDAT(strings.NewReader(dat), []string{"dkong"})
+	if err != nil {
+		t.Fatalf("parseDAT failed: %v", err)
+	}
+	if machines["dkong"].Description != "Donkey Kong (US set 1)" {
+		t.Errorf("unexpected entry: %+v", machines["dkong"])
+	}
+}
+
+func TestDiscoverWithDAT(t *testing.T) {
+	dir := writeROMs(t, "puckman.zip", "pacman.zip", "neogeo.zip", "z80.zip", "galaga.zip", "homebrew.zip")
+	dat := filepath.Join(t.TempDir(), "mame.xml")
+	os.WriteFile(dat, []byte(testDAT), 0644)
+
+	s := &Source{}
+	s.Configure(&discover.DiscoverConfig{MAME: discover.MAMEOptions{RomDir: dir, DAT: dat, Executable: "mame64"}})
+	apps, err := s.Discover(context.Background())
+	if err != nil {
+		t.Fatalf("Discover failed: %v", err)
+	}
+
+	byName := map[string]discover.DiscoveredApp{}
+	for _, a := range apps {
+		byName[a.Name] = a
+	}
+	if len(apps) != 4 {
+		t.Fatalf("expected 4 apps (BIOS and device skipped), got %d: %v", len(apps), apps)
+	}
+	pac, ok := byName["Pac-Man (Midway)"]
+	if !ok {
+		t.Fatalf("Pac-Man not named from the DAT: %v", apps)
+	}
+	expectedArgv := []string{"mame64", "-rompath", dir, "pacman"}
+	if !reflect.DeepEqual(pac.Argv, expectedArgv) {
+		t.Errorf("argv = %v, expected %v", pac.Argv, expectedArgv)
+	}
+	if pac.Publisher != "Namco (Midway license)" || pac.Category != "Games" || pac.Source != "MAME" {
+		t.Errorf("unexpected metadata: %+v", pac)
+	}
+	if pac.Description != "pacman, 1980, Namco (Midway license), clone of puckman" {
+		t.Errorf("unexpected help text: %q", pac.Description)
+	}
+	if hb := byName["homebrew"]; hb.Confidence != unknownROMConfidence {
+		t.Errorf("ROM missing from the DAT should be low confidence, got %+v", hb)
+	}
+	if byName["Galaga (Namco rev. B)"].Score() != 100 {
+		t.Error("ROM found in the DAT should be certain")
+	}
+}
+
+func TestDiscoverSkipClones(t *testing.T) {
+	dir := writeROMs(t, "puckman.zip", "pacman.zip")
+	dat := filepath.Join(t.TempDir(), "mame.xml")
+	os.WriteFile(dat, []byte(testDAT), 0644)
+
+	s := &Source{Options: discover.MAMEOptions{RomDir: dir, DAT: dat, SkipClones: true}}
+	apps, err := s.Discover(context.Background())
+	if err != nil {
+		t.Fatalf("Discover failed: %v", err)
+	}
+	if len(apps) != 1 || apps[0].Name != "PuckMan (Japan set 1)" {
+		t.Errorf("expected only the parent set, got %v", apps)
+	}
+	if apps[0].Argv[0] != "mame" {
+		t.Errorf("default executable should be mame, got %q", apps[0].Argv[0])
+	}
+}
+
+func TestDiscoverWithoutDAT(t *testing.T) {
+	dir := writeROMs(t, "pacman.zip")
+	s := &Source{Options: discover.MAMEOptions{RomDir: dir}}
+	apps, err := s.Discover(context.Background())
+	if err != nil {
+		t.Fatalf("Discover failed: %v", err)
+	}
+	if len(apps) != 1 || apps[0].Name != "pacman" || apps[0].Confidence != 0 {
+		t.Errorf("expected pacman named after its set, got %v", apps)
+	}
+}
+
+func TestAvailable(t *testing.T) {
+	s := &Source{}
+	if s.Available() {
+		t.Error("source should be unavailable without rom_dir")
+	}
+	if d := s.Diagnose(); len(d) != 1 || d[0].OK {
+		t.Errorf("expected one failed check, got %v", d)
+	}
+	s.Options.RomDir = t.TempDir()
+	if !s.Available() {
+		t.Error("source should be available with an existing rom_dir")
+	}
+}
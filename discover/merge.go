@@ -1,9 +1,13 @@
 package discover
 
 import (
+	"bytes"
 	"fmt"
 	"io"
 	"os"
+	"reflect"
+	"sort"
+	"strings"
 
 	"gopkg.in/yaml.v3"
 )
@@ -11,62 +15,299 @@ import (
 // fullConfig is used for merge operations. It includes all known config fields
 // to preserve base config values during YAML round-trip.
 type fullConfig struct {
-	Title        string               `yaml:"title"`
-	Theme        string               `yaml:"theme,omitempty"`
-	Themes       map[string]yamlTheme `yaml:"themes,omitempty"`
-	Items        []fullItem           `yaml:"items"`
-	Menus        map[string]fullMenu  `yaml:"menus,omitempty"`
-	MouseSupport *bool                `yaml:"mouse_support,omitempty"`
-	InitialMenu  string               `yaml:"initial_menu,omitempty"`
-	SplashScreen *bool                `yaml:"splash_screen,omitempty"`
+	Title                 string               `yaml:"title"`
+	Theme                 string               `yaml:"theme,omitempty"`
+	Themes                map[string]yamlTheme `yaml:"themes,omitempty"`
+	Items                 []fullItem           `yaml:"items"`
+	Menus                 map[string]fullMenu  `yaml:"menus,omitempty"`
+	MouseSupport          *bool                `yaml:"mouse_support,omitempty"`
+	InitialMenu           string               `yaml:"initial_menu,omitempty"`
+	SplashScreen          *bool                `yaml:"splash_screen,omitempty"`
+	Shadow                *bool                `yaml:"shadow,omitempty"`
+	TransparentBackground *bool                `yaml:"transparent_background,omitempty"`
+	TitleBar              *fullTitleBar        `yaml:"title_bar,omitempty"`
+	Footer                *bool                `yaml:"footer,omitempty"`
+	FooterHint            string               `yaml:"footer_hint,omitempty"`
+	EmptyText             string               `yaml:"empty_text,omitempty"`
+	TitleFormat           string               `yaml:"title_format,omitempty"`
+	BigTitle              string               `yaml:"big_title,omitempty"`
+	Layout                *fullLayout          `yaml:"layout,omitempty"`
+	WrapNavigation        *bool                `yaml:"wrap_navigation,omitempty"`
+	Animations            *bool                `yaml:"animations,omitempty"`
+	UpdateCheck           *bool                `yaml:"update_check,omitempty"`
+	RestorePosition       *bool                `yaml:"restore_position,omitempty"`
+	AutoReload            *bool                `yaml:"auto_reload,omitempty"`
+	SessionTimeout        string               `yaml:"session_timeout,omitempty"`
+	SessionMenu           string               `yaml:"session_menu,omitempty"`
+	SingleInstance        string               `yaml:"single_instance,omitempty"`
+	Wrapper               *bool                `yaml:"wrapper,omitempty"`
+	Roles                 map[string]fullRole  `yaml:"roles,omitempty"`
+	DefaultRole           string               `yaml:"default_role,omitempty"`
+	CtrlC                 *fullCtrlC           `yaml:"ctrl_c,omitempty"`
+	KeyBindings           *fullKeyBindings     `yaml:"keybindings,omitempty"`
+	Bell                  *fullBell            `yaml:"bell,omitempty"`
+	Launch                string               `yaml:"launch,omitempty"`
+	Webhook               string               `yaml:"webhook,omitempty"`
+	Locale                string               `yaml:"locale,omitempty"`
+	Accessibility         *fullAccessibility   `yaml:"accessibility,omitempty"`
+	StatusSymbols         *bool                `yaml:"status_symbols,omitempty"`
+	DisabledNotes         *fullDisabledNotes   `yaml:"disabled_notes,omitempty"`
+	Templates             map[string]yaml.Node `yaml:"templates,omitempty"` // kept as written: a string or per-OS variants
+	Defaults              *fullDefaults        `yaml:"defaults,omitempty"`
+}
+
+// fullDisabledNotes mirrors the disabled item annotations so merges keep them.
+type fullDisabledNotes struct {
+	Enabled       bool   `yaml:"enabled"`
+	NoCommand     string `yaml:"no_command,omitempty"`
+	MissingTarget string `yaml:"missing_target,omitempty"`
+}
+
+// fullAccessibility mirrors the accessibility settings so merges keep them.
+type fullAccessibility struct {
+	Enabled      bool  `yaml:"enabled"`
+	HighContrast *bool `yaml:"high_contrast,omitempty"`
+	Decorations  *bool `yaml:"decorations,omitempty"`
+}
+
+// fullBell mirrors the bell settings so merges keep them.
+type fullBell struct {
+	Style        string `yaml:"style,omitempty"`
+	OnComplete   *bool  `yaml:"on_complete,omitempty"`
+	OnError      *bool  `yaml:"on_error,omitempty"`
+	OnInvalidKey *bool  `yaml:"on_invalid_key,omitempty"`
+}
+
+// fullCtrlC mirrors the Ctrl+C settings so merges keep them.
+type fullCtrlC struct {
+	Menu             string `yaml:"menu,omitempty"`
+	InterruptCommand *bool  `yaml:"interrupt_command,omitempty"`
+}
+
+// fullKeyBindings mirrors the key bindings so merges keep them.
+type fullKeyBindings struct {
+	Reload string `yaml:"reload,omitempty"`
+}
+
+// fullLayout mirrors the menu layout settings so merges keep them.
+type fullLayout struct {
+	Width  string `yaml:"width,omitempty"`
+	Height string `yaml:"height,omitempty"`
+	Align  string `yaml:"align,omitempty"`
+}
+
+// fullTitleBar mirrors the title bar settings so merges keep them.
+type fullTitleBar struct {
+	Text       string `yaml:"text,omitempty"`
+	Date       *bool  `yaml:"date,omitempty"`
+	Clock      *bool  `yaml:"clock,omitempty"`
+	Clock24h   bool   `yaml:"clock_24h,omitempty"`
+	DateFormat string `yaml:"date_format,omitempty"`
+	TimeFormat string `yaml:"time_format,omitempty"`
+	Hostname   bool   `yaml:"hostname,omitempty"`
+	Username   bool   `yaml:"username,omitempty"`
 }
 
 // fullItem includes all known item fields to preserve base config values.
 type fullItem struct {
-	Type       string    `yaml:"type"`
-	Label      string    `yaml:"label,omitempty"`
-	Hotkey     string    `yaml:"hotkey,omitempty"`
-	Target     string    `yaml:"target,omitempty"`
-	Exec       *fullExec `yaml:"exec,omitempty"`
-	ShowOutput *bool     `yaml:"showOutput,omitempty"`
-	Help       string    `yaml:"help,omitempty"`
+	Type           string                  `yaml:"type,omitempty"`
+	ID             string                  `yaml:"id,omitempty"`
+	Ref            string                  `yaml:"ref,omitempty"`
+	Label          string                  `yaml:"label,omitempty"`
+	Hotkey         string                  `yaml:"hotkey,omitempty"`
+	Icon           string                  `yaml:"icon,omitempty"`
+	Target         string                  `yaml:"target,omitempty"`
+	Exec           *fullExec               `yaml:"exec,omitempty"`
+	ShowOutput     *bool                   `yaml:"showOutput,omitempty"`
+	Help           string                  `yaml:"help,omitempty"`
+	OS             []string                `yaml:"os,omitempty"`
+	Overrides      map[string]fullOverride `yaml:"overrides,omitempty"`
+	Launch         string                  `yaml:"launch,omitempty"`
+	Webhook        string                  `yaml:"webhook,omitempty"`
+	Collapsed      bool                    `yaml:"collapsed,omitempty"`
+	Stdin          string                  `yaml:"stdin,omitempty"`
+	Commands       []fullItem              `yaml:"commands,omitempty"`
+	RunAs          string                  `yaml:"run_as,omitempty"`
+	Template       string                  `yaml:"template,omitempty"`
+	Args           map[string]string       `yaml:"args,omitempty"`
+	Confirm        *bool                   `yaml:"confirm,omitempty"`
+	Timeout        string                  `yaml:"timeout,omitempty"`
+	Disabled       bool                    `yaml:"disabled,omitempty"`
+	DisabledReason string                  `yaml:"disabled_reason,omitempty"`
+	Badge          string                  `yaml:"badge,omitempty"`
+	BadgeExec      *fullExec               `yaml:"badge_exec,omitempty"`
+	AvailableHours string                  `yaml:"available_hours,omitempty"`
+	AvailableDays  string                  `yaml:"available_days,omitempty"`
+	Roles          []string                `yaml:"roles,omitempty"`
+	URL            string                  `yaml:"url,omitempty"`
+	Path           string                  `yaml:"path,omitempty"`
+	Extensions     []string                `yaml:"extensions,omitempty"`
 }
 
-// fullExec includes all known exec fields.
-type fullExec struct {
-	Windows string `yaml:"windows,omitempty"`
-	Linux   string `yaml:"linux,omitempty"`
-	Mac     string `yaml:"mac,omitempty"`
+// fullRole includes all known role fields.
+type fullRole struct {
+	Label string `yaml:"label,omitempty"`
+	PIN   string `yaml:"pin,omitempty"`
+}
+
+// fullOverride includes all known per-OS item override fields.
+type fullOverride struct {
+	Label   string `yaml:"label,omitempty"`
+	Hotkey  string `yaml:"hotkey,omitempty"`
+	Help    string `yaml:"help,omitempty"`
 	WorkDir string `yaml:"workdir,omitempty"`
+	Hidden  bool   `yaml:"hidden,omitempty"`
+}
+
+// fullExec includes all known exec fields. Each OS variant is kept as written:
+// a command line, or a program with its arguments.
+type fullExec struct {
+	Windows yaml.Node `yaml:"windows,omitempty"`
+	Linux   yaml.Node `yaml:"linux,omitempty"`
+	Mac     yaml.Node `yaml:"mac,omitempty"`
+	WorkDir string    `yaml:"workdir,omitempty"`
 }
 
 // fullMenu includes all known menu fields.
 type fullMenu struct {
-	Title string     `yaml:"title"`
-	Items []fullItem `yaml:"items"`
+	Title     string        `yaml:"title"`
+	Items     []fullItem    `yaml:"items"`
+	Footer    string        `yaml:"footer,omitempty"`
+	Defaults  *fullDefaults `yaml:"defaults,omitempty"`
+	EmptyText string        `yaml:"empty_text,omitempty"`
+	Lock      *fullLock     `yaml:"lock,omitempty"`
+}
+
+// fullLock mirrors a menu's sign-in lock.
+type fullLock struct {
+	Auth  string   `yaml:"auth"`
+	Users []string `yaml:"users,omitempty"`
+}
+
+// fullDefaults mirrors the item defaults of the config and its menus.
+type fullDefaults struct {
+	ShowOutput *bool  `yaml:"showOutput,omitempty"`
+	Launch     string `yaml:"launch,omitempty"`
+	Confirm    *bool  `yaml:"confirm,omitempty"`
+	Timeout    string `yaml:"timeout,omitempty"`
+}
+
+// Strategy says which side wins when the base config and the generated one
+// both define the same thing.
+type Strategy string
+
+const (
+	PreferBase      Strategy = "base"
+	PreferGenerated Strategy = "generated"
+)
+
+// MergeSections lists the parts of a config that MergeOptions sets a
+// strategy for.
+var MergeSections = []string{"title", "theme", "themes", "items", "menus"}
+
+// MergeOptions picks the winning side per section. The zero value keeps
+// the base on every conflict, as MergeWithBase does.
+type MergeOptions struct {
+	Title  Strategy // the title: value
+	Theme  Strategy // the selected theme: name
+	Themes Strategy // theme definitions, per theme name
+	Items  Strategy // root submenu entries, per target
+	Menus  Strategy // menus, per menu name; the winner replaces the whole menu
+}
+
+// ParseMergeOptions reads a --prefer value: a comma-separated list of
+// strategies, each either "base" or "generated" for every section, or
+// section=strategy for one, e.g. "generated,menus=base".
+func ParseMergeOptions(spec string) (MergeOptions, error) {
+	var opts MergeOptions
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		section, value, scoped := strings.Cut(part, "=")
+		if !scoped {
+			section, value = "", part
+		}
+		strategy := Strategy(strings.TrimSpace(value))
+		if strategy != PreferBase && strategy != PreferGenerated {
+			return MergeOptions{}, fmt.Errorf("unknown merge strategy %q (expected base or generated)", value)
+		}
+		if !scoped {
+			opts = MergeOptions{strategy, strategy, strategy, strategy, strategy}
+			continue
+		}
+		field := opts.section(strings.TrimSpace(section))
+		if field == nil {
+			return MergeOptions{}, fmt.Errorf("unknown merge section %q (expected %s)", section, strings.Join(MergeSections, ", "))
+		}
+		*field = strategy
+	}
+	return opts, nil
+}
+
+// section returns the strategy field for a MergeSections name, or nil.
+func (o *MergeOptions) section(name string) *Strategy {
+	switch name {
+	case "title":
+		return &o.Title
+	case "theme":
+		return &o.Theme
+	case "themes":
+		return &o.Themes
+	case "items":
+		return &o.Items
+	case "menus":
+		return &o.Menus
+	}
+	return nil
+}
+
+// MergeConflict records one thing both configs defined differently and the
+// side that was kept.
+type MergeConflict struct {
+	Section string // one of MergeSections
+	Key     string // theme or menu name, or submenu target; "" for title and theme
+	Winner  Strategy
+}
+
+func (c MergeConflict) String() string {
+	where := c.Section
+	if c.Key != "" {
+		where += "." + c.Key
+	}
+	return fmt.Sprintf("%s: kept %s", where, c.Winner)
 }
 
 // MergeWithBase merges discovered apps into a base config YAML.
 // The base config takes priority: its title, theme, items, and menus are preserved.
 // Generated content (discovered app categories and menus) fills in the gaps.
 func MergeWithBase(baseYAML []byte, apps []DiscoveredApp) ([]byte, error) {
+	data, _, err := MergeWithBaseOptions(baseYAML, apps, Ordering{}, MergeOptions{})
+	return data, err
+}
+
+// MergeWithBaseOptions merges like MergeWithBase, laying out the generated
+// config per order and resolving conflicts per opts, and reports each
+// conflict it resolved.
+func MergeWithBaseOptions(baseYAML []byte, apps []DiscoveredApp, order Ordering, opts MergeOptions) ([]byte, []MergeConflict, error) {
 	var base fullConfig
 	if err := yaml.Unmarshal(baseYAML, &base); err != nil {
-		return nil, fmt.Errorf("failed to parse base config: %w", err)
+		return nil, nil, fmt.Errorf("failed to parse base config: %w", err)
 	}
 
-	gen, err := generatedToFull(apps)
+	gen, err := generatedToFull(apps, order)
 	if err != nil {
-		return nil, fmt.Errorf("failed to build generated config: %w", err)
+		return nil, nil, fmt.Errorf("failed to build generated config: %w", err)
 	}
 
-	merged := mergeConfigs(base, gen)
+	merged, conflicts := mergeConfigs(base, gen, opts)
 
-	data, err := yaml.Marshal(merged)
+	data, err := marshalYAML(merged)
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshal merged config: %w", err)
+		return nil, nil, fmt.Errorf("failed to marshal merged config: %w", err)
 	}
-	return data, nil
+	return data, conflicts, nil
 }
 
 // RenderMergedConfig merges discovered apps with a base config and writes YAML to w.
@@ -91,8 +332,8 @@ func WriteMergedConfig(baseYAML []byte, apps []DiscoveredApp, outputPath string)
 // generatedToFull converts discovered apps to a fullConfig via YAML round-trip.
 // This reuses buildYAMLConfig (which uses yaml.Node for menu ordering) and
 // converts the result to fullConfig (which uses maps for easy merging).
-func generatedToFull(apps []DiscoveredApp) (fullConfig, error) {
-	cfg := buildYAMLConfig(apps)
+func generatedToFull(apps []DiscoveredApp, order Ordering) (fullConfig, error) {
+	cfg := buildYAMLConfig(apps, order)
 	data, err := yaml.Marshal(cfg)
 	if err != nil {
 		return fullConfig{}, err
@@ -104,75 +345,138 @@ func generatedToFull(apps []DiscoveredApp) (fullConfig, error) {
 	return full, nil
 }
 
-// mergeConfigs merges base and generated configs. Base takes priority on all conflicts.
-func mergeConfigs(base, gen fullConfig) fullConfig {
+// mergeConfigs merges base and generated configs, resolving each conflict
+// with the strategy opts gives its section, and returns the conflicts found.
+func mergeConfigs(base, gen fullConfig, opts MergeOptions) (fullConfig, []MergeConflict) {
 	result := base
+	var conflicts []MergeConflict
 
-	// Scalars: base wins if non-empty
-	if result.Title == "" {
-		result.Title = gen.Title
-	}
-	if result.Theme == "" {
-		result.Theme = gen.Theme
-	}
+	// Scalars: base wins if non-empty, unless the section prefers generated
+	result.Title, conflicts = mergeScalar("title", base.Title, gen.Title, opts.Title, conflicts)
+	result.Theme, conflicts = mergeScalar("theme", base.Theme, gen.Theme, opts.Theme, conflicts)
 
-	// Themes: merge by key, base wins per-key
-	result.Themes = mergeThemes(base.Themes, gen.Themes)
+	// Themes: merge by key
+	result.Themes, conflicts = mergeThemes(base.Themes, gen.Themes, opts.Themes, conflicts)
 
 	// Root items: insert generated submenu entries before trailing separator/back block
-	result.Items = mergeRootItems(base.Items, gen.Items)
+	result.Items, conflicts = mergeRootItems(base.Items, gen.Items, opts.Items, conflicts)
 
-	// Menus: merge by key, base wins per-key
-	result.Menus = mergeMenus(base.Menus, gen.Menus)
+	// Menus: merge by key
+	result.Menus, conflicts = mergeMenus(base.Menus, gen.Menus, opts.Menus, conflicts)
 
 	// Other fields (MouseSupport, InitialMenu, SplashScreen) are preserved from base
-	return result
+	return result, conflicts
+}
+
+// mergeScalar returns the value to keep for a field both sides may set.
+func mergeScalar(section, base, gen string, strategy Strategy, conflicts []MergeConflict) (string, []MergeConflict) {
+	if base == "" {
+		return gen, conflicts
+	}
+	if gen == "" || gen == base {
+		return base, conflicts
+	}
+	conflicts = append(conflicts, MergeConflict{Section: section, Winner: winner(strategy)})
+	if strategy == PreferGenerated {
+		return gen, conflicts
+	}
+	return base, conflicts
 }
 
-// mergeThemes merges theme maps. Base themes take priority per-key.
-func mergeThemes(base, gen map[string]yamlTheme) map[string]yamlTheme {
+// winner normalizes an unset strategy to PreferBase.
+func winner(s Strategy) Strategy {
+	if s == PreferGenerated {
+		return PreferGenerated
+	}
+	return PreferBase
+}
+
+// sameYAML reports whether a and b encode to the same YAML. Decoded nodes
+// remember their line numbers, so values are compared by their encoding.
+func sameYAML(a, b interface{}) bool {
+	da, errA := yaml.Marshal(a)
+	db, errB := yaml.Marshal(b)
+	return errA == nil && errB == nil && bytes.Equal(da, db)
+}
+
+// mergeThemes merges theme maps per key, keeping the side strategy names
+// when both define a theme differently.
+func mergeThemes(base, gen map[string]yamlTheme, strategy Strategy, conflicts []MergeConflict) (map[string]yamlTheme, []MergeConflict) {
 	if base == nil && gen == nil {
-		return nil
+		return nil, conflicts
 	}
 	result := make(map[string]yamlTheme)
 	for k, v := range base {
 		result[k] = v
 	}
-	for k, v := range gen {
-		if _, exists := result[k]; !exists {
+	keys := make([]string, 0, len(gen))
+	for k := range gen {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys) // report conflicts in a stable order
+	for _, k := range keys {
+		v := gen[k]
+		existing, exists := result[k]
+		if !exists {
+			result[k] = v
+			continue
+		}
+		if reflect.DeepEqual(existing, v) {
+			continue
+		}
+		conflicts = append(conflicts, MergeConflict{Section: "themes", Key: k, Winner: winner(strategy)})
+		if strategy == PreferGenerated {
 			result[k] = v
 		}
 	}
 	if len(result) == 0 {
-		return nil
+		return nil, conflicts
 	}
-	return result
+	return result, conflicts
 }
 
 // mergeRootItems merges root menu items. Base items are preserved in order.
 // Generated submenu entries for new categories are inserted before the trailing
-// separator/back block in the base items.
-func mergeRootItems(base, gen []fullItem) []fullItem {
+// separator/back block in the base items. An entry both sides have for the
+// same target is replaced in place when strategy prefers generated.
+func mergeRootItems(base, gen []fullItem, strategy Strategy, conflicts []MergeConflict) ([]fullItem, []MergeConflict) {
 	// Collect existing submenu targets in base
-	existingTargets := make(map[string]bool)
-	for _, item := range base {
+	existingTargets := make(map[string]int)
+	for i, item := range base {
 		if item.Type == "submenu" && item.Target != "" {
-			existingTargets[item.Target] = true
+			existingTargets[item.Target] = i
 		}
 	}
 
 	// Collect new submenu entries from generated that don't exist in base
 	var newItems []fullItem
+	var replaced []fullItem
 	for _, item := range gen {
-		if item.Type == "submenu" && item.Target != "" {
-			if !existingTargets[item.Target] {
-				newItems = append(newItems, item)
+		if item.Type != "submenu" || item.Target == "" {
+			continue
+		}
+		i, exists := existingTargets[item.Target]
+		if !exists {
+			newItems = append(newItems, item)
+			continue
+		}
+		if sameYAML(base[i], item) {
+			continue
+		}
+		conflicts = append(conflicts, MergeConflict{Section: "items", Key: item.Target, Winner: winner(strategy)})
+		if strategy == PreferGenerated {
+			if replaced == nil {
+				replaced = append([]fullItem(nil), base...)
 			}
+			replaced[i] = item
 		}
 	}
+	if replaced != nil {
+		base = replaced
+	}
 
 	if len(newItems) == 0 {
-		return base
+		return base, conflicts
 	}
 
 	// Find insertion point: before trailing separator/back block
@@ -183,15 +487,15 @@ func mergeRootItems(base, gen []fullItem) []fullItem {
 	result = append(result, newItems...)
 	result = append(result, base[insertIdx:]...)
 
-	return result
+	return result, conflicts
 }
 
 // findInsertionPoint returns the index where new items should be inserted,
-// which is just before the trailing block of separator/back items.
+// which is just before the trailing block of separator/back/quit items.
 func findInsertionPoint(items []fullItem) int {
 	idx := len(items)
 	for i := len(items) - 1; i >= 0; i-- {
-		if items[i].Type == "separator" || items[i].Type == "back" {
+		if items[i].Type == "separator" || items[i].Type == "back" || items[i].Type == "quit" {
 			idx = i
 		} else {
 			break
@@ -200,22 +504,38 @@ func findInsertionPoint(items []fullItem) int {
 	return idx
 }
 
-// mergeMenus merges menu maps. Base menus take priority per-key.
-func mergeMenus(base, gen map[string]fullMenu) map[string]fullMenu {
+// mergeMenus merges menu maps per key, keeping the whole menu from the side
+// strategy names when both define it differently.
+func mergeMenus(base, gen map[string]fullMenu, strategy Strategy, conflicts []MergeConflict) (map[string]fullMenu, []MergeConflict) {
 	if base == nil && gen == nil {
-		return nil
+		return nil, conflicts
 	}
 	result := make(map[string]fullMenu)
 	for k, v := range base {
 		result[k] = v
 	}
-	for k, v := range gen {
-		if _, exists := result[k]; !exists {
+	keys := make([]string, 0, len(gen))
+	for k := range gen {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys) // report conflicts in a stable order
+	for _, k := range keys {
+		v := gen[k]
+		existing, exists := result[k]
+		if !exists {
+			result[k] = v
+			continue
+		}
+		if sameYAML(existing, v) {
+			continue
+		}
+		conflicts = append(conflicts, MergeConflict{Section: "menus", Key: k, Winner: winner(strategy)})
+		if strategy == PreferGenerated {
 			result[k] = v
 		}
 	}
 	if len(result) == 0 {
-		return nil
+		return nil, conflicts
 	}
-	return result
+	return result, conflicts
 }
@@ -217,6 +217,121 @@ menus:
 	}
 }
 
+const strategyBase = `
+title: "Test"
+theme: "retro"
+items:
+  - type: submenu
+    label: "My Games"
+    hotkey: "G"
+    target: "games"
+  - type: back
+    label: "Quit"
+menus:
+  games:
+    title: "Old Games"
+    items:
+      - type: command
+        label: "Uninstalled Game"
+        exec:
+          windows: "gone.exe"
+`
+
+func TestMergeWithBaseOptionsPrefersGeneratedMenus(t *testing.T) {
+	apps := []DiscoveredApp{
+		{Name: "Discovered Game", Exec: "disc.exe", Source: "steam", Category: "Games"},
+	}
+
+	result, conflicts, err := MergeWithBaseOptions([]byte(strategyBase), apps, Ordering{}, MergeOptions{Menus: PreferGenerated})
+	if err != nil {
+		t.Fatalf("MergeWithBaseOptions failed: %v", err)
+	}
+
+	var cfg fullConfig
+	if err := yaml.Unmarshal(result, &cfg); err != nil {
+		t.Fatalf("failed to parse result: %v", err)
+	}
+	games := cfg.Menus["games"]
+	if games.Title != "Games" || games.Items[0].Label != "Discovered Game" {
+		t.Errorf("expected the generated games menu, got %+v", games)
+	}
+	if cfg.Items[0].Label != "My Games" || cfg.Theme != "retro" || cfg.Title != "Test" {
+		t.Errorf("other sections should keep the base: %+v", cfg)
+	}
+
+	var got []string
+	for _, c := range conflicts {
+		got = append(got, c.String())
+	}
+	expected := []string{"title: kept base", "theme: kept base", "items.games: kept base", "menus.games: kept generated"}
+	if strings.Join(got, "; ") != strings.Join(expected, "; ") {
+		t.Errorf("conflicts = %v, expected %v", got, expected)
+	}
+}
+
+func TestMergeWithBaseOptionsPrefersGeneratedEverywhere(t *testing.T) {
+	apps := []DiscoveredApp{
+		{Name: "Discovered Game", Exec: "disc.exe", Source: "steam", Category: "Games"},
+	}
+	opts, err := ParseMergeOptions("generated")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result, _, err := MergeWithBaseOptions([]byte(strategyBase), apps, Ordering{}, opts)
+	if err != nil {
+		t.Fatalf("MergeWithBaseOptions failed: %v", err)
+	}
+	var cfg fullConfig
+	if err := yaml.Unmarshal(result, &cfg); err != nil {
+		t.Fatalf("failed to parse result: %v", err)
+	}
+	if cfg.Theme != "dark" || cfg.Title == "Test" {
+		t.Errorf("expected generated title and theme, got %q, %q", cfg.Title, cfg.Theme)
+	}
+	if len(cfg.Items) != 2 || cfg.Items[0].Label != "Games" || cfg.Items[0].Hotkey != "" || cfg.Items[1].Type != "back" {
+		t.Errorf("expected the generated submenu entry in place, got %+v", cfg.Items)
+	}
+}
+
+func TestMergeWithBaseOptionsNoConflicts(t *testing.T) {
+	base := `
+items:
+  - type: back
+    label: "Quit"
+`
+	apps := []DiscoveredApp{{Name: "App", Exec: "app.exe", Category: "Tools"}}
+	_, conflicts, err := MergeWithBaseOptions([]byte(base), apps, Ordering{}, MergeOptions{})
+	if err != nil {
+		t.Fatalf("MergeWithBaseOptions failed: %v", err)
+	}
+	if len(conflicts) != 0 {
+		t.Errorf("expected no conflicts when the base leaves gaps, got %v", conflicts)
+	}
+}
+
+func TestParseMergeOptions(t *testing.T) {
+	opts, err := ParseMergeOptions("generated, menus=base")
+	if err != nil {
+		t.Fatalf("ParseMergeOptions failed: %v", err)
+	}
+	expected := MergeOptions{Title: PreferGenerated, Theme: PreferGenerated, Themes: PreferGenerated, Items: PreferGenerated, Menus: PreferBase}
+	if opts != expected {
+		t.Errorf("got %+v, expected %+v", opts, expected)
+	}
+
+	opts, err = ParseMergeOptions("themes=generated")
+	if err != nil || opts != (MergeOptions{Themes: PreferGenerated}) {
+		t.Errorf("got %+v, %v", opts, err)
+	}
+
+	for _, bad := range []string{"newest", "menus=mine", "colors=base"} {
+		if _, err := ParseMergeOptions(bad); err == nil {
+			t.Errorf("ParseMergeOptions(%q) should fail", bad)
+		}
+	}
+}
+
 func TestMergeWithBaseInsertsItemsBeforeTrailingBlock(t *testing.T) {
 	base := `
 title: "Test"
@@ -331,6 +446,75 @@ items:
 	}
 }
 
+func TestMergeWithBasePreservesTemplates(t *testing.T) {
+	base := `
+title: "Test"
+templates:
+  ssh_to: "ssh {host}"
+  tail_log:
+    linux: "tail -f {file}"
+items:
+  - label: "Web 1"
+    template: ssh_to
+    args: { host: web1 }
+  - type: back
+    label: "Quit"
+`
+	apps := []DiscoveredApp{
+		{Name: "App1", Exec: "app1.exe", Source: "test", Category: "Tools"},
+	}
+
+	result, err := MergeWithBase([]byte(base), apps)
+	if err != nil {
+		t.Fatalf("MergeWithBase failed: %v", err)
+	}
+
+	var cfg fullConfig
+	if err := yaml.Unmarshal(result, &cfg); err != nil {
+		t.Fatalf("failed to parse result: %v", err)
+	}
+	if ssh := cfg.Templates["ssh_to"]; ssh.Value != "ssh {host}" {
+		t.Errorf("expected the string template to be kept as written, got %q", ssh.Value)
+	}
+	if _, ok := cfg.Templates["tail_log"]; !ok {
+		t.Error("expected the per-OS template to be preserved")
+	}
+	if cfg.Items[0].Template != "ssh_to" || cfg.Items[0].Args["host"] != "web1" {
+		t.Errorf("expected the item's template and args to be preserved, got %+v", cfg.Items[0])
+	}
+}
+
+func TestMergeWithBasePreservesProgramArgs(t *testing.T) {
+	base := `
+title: "Test"
+items:
+  - type: command
+    label: "Viewer"
+    exec:
+      windows:
+        program: 'C:\Program Files\Viewer\view.exe'
+        args: ["--new-window"]
+  - type: back
+    label: "Quit"
+`
+	apps := []DiscoveredApp{
+		{Name: "App1", Exec: "app1.exe", Source: "test", Category: "Tools"},
+	}
+
+	result, err := MergeWithBase([]byte(base), apps)
+	if err != nil {
+		t.Fatalf("MergeWithBase failed: %v", err)
+	}
+
+	output := string(result)
+	if !strings.Contains(output, "program: 'C:\\Program Files\\Viewer\\view.exe'") || !strings.Contains(output, "--new-window") {
+		t.Errorf("expected the program and args to be preserved, got:\n%s", output)
+	}
+	if strings.Contains(output, "linux:") || strings.Contains(output, "null") {
+		t.Errorf("expected absent variants to be left out, got:\n%s", output)
+	}
+}
+
 func TestMergeWithBasePreservesItemHotkeys(t *testing.T) {
 	base := `
 title: "Test"
@@ -534,7 +718,7 @@ func TestFindInsertionPointBackOnly(t *testing.T) {
 // --- mergeThemes Tests ---
 
 func TestMergeThemesBothNil(t *testing.T) {
-	result := mergeThemes(nil, nil)
+	result, _ := mergeThemes(nil, nil, PreferBase, nil)
 	if result != nil {
 		t.Errorf("expected nil, got %v", result)
 	}
@@ -544,7 +728,7 @@ func TestMergeThemesBaseNil(t *testing.T) {
 	gen := map[string]yamlTheme{
 		"dark": {Background: "blue"},
 	}
-	result := mergeThemes(nil, gen)
+	result, _ := mergeThemes(nil, gen, PreferBase, nil)
 	if len(result) != 1 {
 		t.Fatalf("expected 1 theme, got %d", len(result))
 	}
@@ -557,7 +741,7 @@ func TestMergeThemesGenNil(t *testing.T) {
 	base := map[string]yamlTheme{
 		"custom": {Background: "black"},
 	}
-	result := mergeThemes(base, nil)
+	result, _ := mergeThemes(base, nil, PreferBase, nil)
 	if len(result) != 1 {
 		t.Fatalf("expected 1 theme, got %d", len(result))
 	}
@@ -573,7 +757,7 @@ func TestMergeThemesBaseWinsOnConflict(t *testing.T) {
 	gen := map[string]yamlTheme{
 		"dark": {Background: "blue", Text: "silver"},
 	}
-	result := mergeThemes(base, gen)
+	result, _ := mergeThemes(base, gen, PreferBase, nil)
 	if result["dark"].Background != "black" {
 		t.Errorf("expected base 'black', got %q", result["dark"].Background)
 	}
@@ -589,7 +773,7 @@ func TestMergeThemesAddsNew(t *testing.T) {
 	gen := map[string]yamlTheme{
 		"dark": {Background: "blue"},
 	}
-	result := mergeThemes(base, gen)
+	result, _ := mergeThemes(base, gen, PreferBase, nil)
 	if len(result) != 2 {
 		t.Fatalf("expected 2 themes, got %d", len(result))
 	}
@@ -604,7 +788,7 @@ func TestMergeThemesAddsNew(t *testing.T) {
 // --- mergeMenus Tests ---
 
 func TestMergeMenusBothNil(t *testing.T) {
-	result := mergeMenus(nil, nil)
+	result, _ := mergeMenus(nil, nil, PreferBase, nil)
 	if result != nil {
 		t.Errorf("expected nil, got %v", result)
 	}
@@ -617,7 +801,7 @@ func TestMergeMenusBaseWinsOnConflict(t *testing.T) {
 	gen := map[string]fullMenu{
 		"games": {Title: "Games", Items: []fullItem{{Type: "command", Label: "Game1"}}},
 	}
-	result := mergeMenus(base, gen)
+	result, _ := mergeMenus(base, gen, PreferBase, nil)
 	if result["games"].Title != "My Games" {
 		t.Errorf("expected base title 'My Games', got %q", result["games"].Title)
 	}
@@ -630,7 +814,7 @@ func TestMergeMenusAddsNew(t *testing.T) {
 	gen := map[string]fullMenu{
 		"games": {Title: "Games"},
 	}
-	result := mergeMenus(base, gen)
+	result, _ := mergeMenus(base, gen, PreferBase, nil)
 	if len(result) != 2 {
 		t.Fatalf("expected 2 menus, got %d", len(result))
 	}
@@ -646,7 +830,7 @@ func TestMergeRootItemsNoNewItems(t *testing.T) {
 	gen := []fullItem{
 		{Type: "submenu", Label: "Games", Target: "games"},
 	}
-	result := mergeRootItems(base, gen)
+	result, _ := mergeRootItems(base, gen, PreferBase, nil)
 	if len(result) != 2 {
 		t.Errorf("expected 2 items (no additions), got %d", len(result))
 	}
@@ -658,7 +842,7 @@ func TestMergeRootItemsEmptyBase(t *testing.T) {
 		{Type: "separator"},
 		{Type: "back", Label: "Quit"},
 	}
-	result := mergeRootItems(nil, gen)
+	result, _ := mergeRootItems(nil, gen, PreferBase, nil)
 	// With nil base, new submenu entries are appended
 	if len(result) != 1 {
 		t.Errorf("expected 1 item (just the submenu), got %d", len(result))
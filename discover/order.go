@@ -0,0 +1,79 @@
+package discover
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Item orders for Ordering.Items.
+const (
+	ItemOrderSource       = "source"       // as collected from the sources (the default)
+	ItemOrderAlphabetical = "alphabetical" // by name, ignoring case
+)
+
+// Ordering controls how a generated config is laid out. The zero value sorts
+// categories and sources alphabetically and keeps items in source order.
+type Ordering struct {
+	// Categories are pinned to the top of the root menu in this order;
+	// categories not listed follow alphabetically.
+	Categories []string `yaml:"categories"`
+	// Sources are pinned first, in this order, in categories that have a
+	// submenu per source; sources not listed follow alphabetically.
+	Sources []string `yaml:"sources"`
+	// Items is ItemOrderSource or ItemOrderAlphabetical. Empty means
+	// ItemOrderSource.
+	Items string `yaml:"items"`
+}
+
+// Validate reports an unknown item order.
+func (o Ordering) Validate() error {
+	switch o.Items {
+	case "", ItemOrderSource, ItemOrderAlphabetical:
+		return nil
+	}
+	return fmt.Errorf("unknown item order %q (expected %s or %s)", o.Items, ItemOrderSource, ItemOrderAlphabetical)
+}
+
+// sortCategories sorts category names in place: pinned ones first.
+func (o Ordering) sortCategories(names []string) {
+	sortPinned(names, o.Categories)
+}
+
+// sortSources sorts source names in place: pinned ones first.
+func (o Ordering) sortSources(names []string) {
+	sortPinned(names, o.Sources)
+}
+
+// sortItems returns apps in the configured item order. It does not modify apps.
+func (o Ordering) sortItems(apps []DiscoveredApp) []DiscoveredApp {
+	if o.Items != ItemOrderAlphabetical {
+		return apps
+	}
+	sorted := make([]DiscoveredApp, len(apps))
+	copy(sorted, apps)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return strings.ToLower(sorted[i].Name) < strings.ToLower(sorted[j].Name)
+	})
+	return sorted
+}
+
+// sortPinned sorts names so those in pinned (matched ignoring case) come
+// first in pinned order, followed by the rest alphabetically.
+func sortPinned(names []string, pinned []string) {
+	rank := func(name string) int {
+		for i, p := range pinned {
+			if strings.EqualFold(p, name) {
+				return i
+			}
+		}
+		return len(pinned)
+	}
+	sort.SliceStable(names, func(i, j int) bool {
+		ri, rj := rank(names[i]), rank(names[j])
+		if ri != rj {
+			return ri < rj
+		}
+		return names[i] < names[j]
+	})
+}
@@ -0,0 +1,84 @@
+package discover
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestRenderConfigOrdered(t *testing.T) {
+	origOS := writerOS
+	writerOS = "windows"
+	defer func() { writerOS = origOS }()
+
+	apps := []DiscoveredApp{
+		{Name: "zeta", Exec: "zeta.exe", Source: "steam", Category: "Games"},
+		{Name: "Alpha", Exec: "alpha.exe", Source: "steam", Category: "Games"},
+		{Name: "Halo", Exec: "halo.exe", Source: "xbox", Category: "Games"},
+		{Name: "Editor", Exec: "edit.exe", Source: "registry", Category: "Applications"},
+		{Name: "Zip", Exec: "zip.exe", Source: "registry", Category: "Utilities"},
+	}
+	order := Ordering{
+		Categories: []string{"utilities", "Games"},
+		Sources:    []string{"xbox"},
+		Items:      ItemOrderAlphabetical,
+	}
+
+	var buf bytes.Buffer
+	if err := RenderConfigOrdered(apps, order, &buf); err != nil {
+		t.Fatalf("RenderConfigOrdered failed: %v", err)
+	}
+	var parsed struct {
+		Items []yamlItem
+		Menus map[string]yamlMenu
+	}
+	if err := yaml.Unmarshal(buf.Bytes(), &parsed); err != nil {
+		t.Fatalf("generated YAML is invalid: %v", err)
+	}
+
+	var root []string
+	for _, item := range parsed.Items {
+		root = append(root, item.Label)
+	}
+	if got := strings.Join(root, ","); got != "Utilities,Games,Applications,,Quit" {
+		t.Errorf("root order = %s", got)
+	}
+	if games := parsed.Menus["games"].Items; games[0].Label != "Xbox" || games[1].Label != "Steam" {
+		t.Errorf("pinned source should come first: %+v", games)
+	}
+	if steam := parsed.Menus["games_steam"].Items; steam[0].Label != "Alpha" || steam[1].Label != "zeta" {
+		t.Errorf("items should be alphabetical ignoring case: %+v", steam)
+	}
+}
+
+func TestRenderConfigDefaultOrder(t *testing.T) {
+	apps := []DiscoveredApp{
+		{Name: "Zip", Exec: "zip", Category: "Utilities"},
+		{Name: "Alpha", Exec: "alpha", Category: "Utilities"},
+		{Name: "Editor", Exec: "edit", Category: "Applications"},
+	}
+	cfg := buildYAMLConfig(apps, Ordering{})
+	if cfg.Items[0].Label != "Applications" || cfg.Items[1].Label != "Utilities" {
+		t.Errorf("categories should be alphabetical by default: %+v", cfg.Items)
+	}
+	var menus map[string]yamlMenu
+	if err := cfg.Menus.Decode(&menus); err != nil {
+		t.Fatal(err)
+	}
+	if menus["utilities"].Items[0].Label != "Zip" {
+		t.Errorf("items should keep source order by default: %+v", menus["utilities"].Items)
+	}
+}
+
+func TestOrderingValidate(t *testing.T) {
+	for _, items := range []string{"", ItemOrderSource, ItemOrderAlphabetical} {
+		if err := (Ordering{Items: items}).Validate(); err != nil {
+			t.Errorf("Validate(%q): %v", items, err)
+		}
+	}
+	if err := (Ordering{Items: "newest"}).Validate(); err == nil {
+		t.Error("expected error for an unknown item order")
+	}
+}
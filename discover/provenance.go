@@ -0,0 +1,106 @@
+package discover
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// provenanceTitle opens the comment header of a generated config.
+const provenanceTitle = "# Generated by menuworks. The lines below record where this config came from."
+
+// SourceCount is the number of items one source contributed.
+type SourceCount struct {
+	Source string
+	Count  int
+}
+
+// Provenance describes where a generated config came from. It is written as
+// a YAML comment header, so it never changes how the config loads.
+type Provenance struct {
+	Generated time.Time
+	Version   string        // menuworks version that wrote the config
+	Command   string        // "generate", "import", or "watch"
+	Sources   []SourceCount // in the order they ran
+	Items     int           // items in the generated menus, after deduplication
+}
+
+// NewProvenance records each successful result's app count. Failed sources
+// are left out since they contributed nothing.
+func NewProvenance(command, version string, results []DiscoverResult, items int) Provenance {
+	p := Provenance{
+		Generated: time.Now().UTC().Truncate(time.Second),
+		Version:   version,
+		Command:   command,
+		Items:     items,
+	}
+	for _, r := range results {
+		if r.Err == nil {
+			p.Sources = append(p.Sources, SourceCount{r.Source, len(r.Apps)})
+		}
+	}
+	return p
+}
+
+// Header returns the provenance as YAML comment lines, each ending in a
+// newline, to put above a generated config.
+func (p Provenance) Header() string {
+	sources := "none"
+	if len(p.Sources) > 0 {
+		var counts []string
+		for _, s := range p.Sources {
+			counts = append(counts, fmt.Sprintf("%s=%d", s.Source, s.Count))
+		}
+		sources = strings.Join(counts, ", ")
+	}
+	var b strings.Builder
+	b.WriteString(provenanceTitle + "\n")
+	fmt.Fprintf(&b, "# generated: %s\n", p.Generated.Format(time.RFC3339))
+	fmt.Fprintf(&b, "# version: %s\n", p.Version)
+	fmt.Fprintf(&b, "# command: %s\n", p.Command)
+	fmt.Fprintf(&b, "# sources: %s\n", sources)
+	fmt.Fprintf(&b, "# items: %d\n", p.Items)
+	return b.String()
+}
+
+// ParseProvenance reads the header Header wrote from the top of a config.
+// It reports false when data does not start with one.
+func ParseProvenance(data []byte) (Provenance, bool) {
+	var p Provenance
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	if !scanner.Scan() || scanner.Text() != provenanceTitle {
+		return p, false
+	}
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(strings.TrimPrefix(scanner.Text(), "# "), ": ")
+		if !ok || !strings.HasPrefix(scanner.Text(), "# ") {
+			break
+		}
+		switch key {
+		case "generated":
+			p.Generated, _ = time.Parse(time.RFC3339, value)
+		case "version":
+			p.Version = value
+		case "command":
+			p.Command = value
+		case "sources":
+			for _, field := range strings.Split(value, ", ") {
+				i := strings.LastIndex(field, "=")
+				if i < 0 {
+					continue
+				}
+				n, err := strconv.Atoi(field[i+1:])
+				if err != nil {
+					continue
+				}
+				p.Sources = append(p.Sources, SourceCount{field[:i], n})
+			}
+		case "items":
+			p.Items, _ = strconv.Atoi(value)
+		}
+	}
+	return p, true
+}
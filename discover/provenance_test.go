@@ -0,0 +1,65 @@
+package discover
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestProvenanceRoundTrip(t *testing.T) {
+	p := Provenance{
+		Generated: time.Date(2026, 10, 16, 9, 30, 0, 0, time.UTC),
+		Version:   "3.2.0 (3f2a9c1, 2026-10-01)",
+		Command:   "generate",
+		Sources:   []SourceCount{{"steam", 12}, {"startmenu", 40}},
+		Items:     50,
+	}
+	data := []byte(p.Header() + "title: Test\n")
+
+	got, ok := ParseProvenance(data)
+	if !ok {
+		t.Fatalf("header not recognised:\n%s", data)
+	}
+	if !got.Generated.Equal(p.Generated) || !reflect.DeepEqual(got.Sources, p.Sources) {
+		t.Errorf("got %+v, expected %+v", got, p)
+	}
+	if got.Version != p.Version || got.Command != p.Command || got.Items != p.Items {
+		t.Errorf("got %+v, expected %+v", got, p)
+	}
+
+	// The header is only comments
+	var cfg struct{ Title string }
+	if err := yaml.Unmarshal(data, &cfg); err != nil || cfg.Title != "Test" {
+		t.Errorf("config with header did not load: %+v, %v", cfg, err)
+	}
+}
+
+func TestNewProvenanceSkipsFailedSources(t *testing.T) {
+	results := []DiscoverResult{
+		{Source: "steam", Apps: make([]DiscoveredApp, 3)},
+		{Source: "xbox", Err: errors.New("no powershell")},
+		{Source: "startmenu"},
+	}
+	p := NewProvenance("generate", "dev", results, 3)
+	expected := []SourceCount{{"steam", 3}, {"startmenu", 0}}
+	if !reflect.DeepEqual(p.Sources, expected) {
+		t.Errorf("sources = %v, expected %v", p.Sources, expected)
+	}
+	if p.Generated.IsZero() || p.Generated.Location() != time.UTC {
+		t.Errorf("expected a UTC generation time, got %v", p.Generated)
+	}
+}
+
+func TestParseProvenanceWithoutHeader(t *testing.T) {
+	if _, ok := ParseProvenance([]byte("# my launcher\ntitle: Test\n")); ok {
+		t.Error("a hand-written config should have no provenance")
+	}
+
+	p, ok := ParseProvenance([]byte(Provenance{Command: "import"}.Header()))
+	if !ok || p.Sources != nil {
+		t.Errorf("expected no sources, got %+v, %v", p, ok)
+	}
+}
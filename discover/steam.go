@@ -0,0 +1,328 @@
+package discover
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SteamOptions are the Steam settings of the discover: block. They let the
+// generated Games menu leave out soundtracks, DLC, and games not played lately.
+type SteamOptions struct {
+	SkipSoundtracks    bool `yaml:"skip_soundtracks"`
+	SkipDLC            bool `yaml:"skip_dlc"`
+	PlayedWithinMonths int  `yaml:"played_within_months"` // 0 keeps everything
+}
+
+// SteamManifest is what an appmanifest_*.acf says about an installed app.
+type SteamManifest struct {
+	AppID      string
+	Name       string
+	InstallDir string
+	SizeOnDisk int64 // -1 if the manifest does not say
+}
+
+// ReadSteamManifest reads an appmanifest_*.acf file.
+func ReadSteamManifest(path string) (SteamManifest, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return SteamManifest{}, err
+	}
+	defer f.Close()
+
+	root, err := ParseVDF(f)
+	if err != nil {
+		return SteamManifest{}, fmt.Errorf("%s: %w", path, err)
+	}
+	state := root.Child("AppState")
+	m := SteamManifest{
+		AppID:      state.Value("appid"),
+		Name:       state.Value("name"),
+		InstallDir: state.Value("installdir"),
+	}
+	m.SizeOnDisk = -1
+	if size, err := strconv.ParseInt(state.Value("SizeOnDisk"), 10, 64); err == nil {
+		m.SizeOnDisk = size
+	}
+	if m.AppID == "" || m.Name == "" {
+		return m, fmt.Errorf("incomplete manifest: %s", path)
+	}
+	return m, nil
+}
+
+// VDF is a node of Valve's KeyValues text format: a string value or a block
+// of child nodes. Keys are matched case-insensitively, as Steam does.
+type VDF struct {
+	value    string
+	children map[string]*VDF
+}
+
+// Value returns the string value of the child named key, or "".
+func (v *VDF) Value(key string) string {
+	if c := v.Child(key); c != nil {
+		return c.value
+	}
+	return ""
+}
+
+// Child returns the child named key, or nil. It is safe to call on nil.
+func (v *VDF) Child(key string) *VDF {
+	if v == nil {
+		return nil
+	}
+	return v.children[strings.ToLower(key)]
+}
+
+// Path follows Child through each key in turn.
+func (v *VDF) Path(keys ...string) *VDF {
+	for _, k := range keys {
+		v = v.Child(k)
+	}
+	return v
+}
+
+// Keys returns the names of v's children, lower-cased.
+func (v *VDF) Keys() []string {
+	if v == nil {
+		return nil
+	}
+	keys := make([]string, 0, len(v.children))
+	for k := range v.children {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+// ParseVDF parses KeyValues text: quoted keys followed by a quoted value or a
+// { } block, with // comments. A key repeated in one block keeps its last value.
+func ParseVDF(r io.Reader) (*VDF, error) {
+	tokens, err := vdfTokens(r)
+	if err != nil {
+		return nil, err
+	}
+	root := &VDF{children: map[string]*VDF{}}
+	stack := []*VDF{root}
+	for i := 0; i < len(tokens); i++ {
+		cur := stack[len(stack)-1]
+		switch tok := tokens[i]; {
+		case tok.text == "}" && !tok.quoted:
+			if len(stack) == 1 {
+				return nil, fmt.Errorf("unexpected }")
+			}
+			stack = stack[:len(stack)-1]
+		case tok.text == "{" && !tok.quoted:
+			return nil, fmt.Errorf("unexpected {")
+		default:
+			if i+1 >= len(tokens) {
+				return nil, fmt.Errorf("key %q has no value", tok.text)
+			}
+			next := tokens[i+1]
+			i++
+			if next.text == "{" && !next.quoted {
+				child := &VDF{children: map[string]*VDF{}}
+				cur.children[strings.ToLower(tok.text)] = child
+				stack = append(stack, child)
+			} else {
+				cur.children[strings.ToLower(tok.text)] = &VDF{value: next.text}
+			}
+		}
+	}
+	if len(stack) != 1 {
+		return nil, fmt.Errorf("unterminated block")
+	}
+	return root, nil
+}
+
+type vdfToken struct {
+	text   string
+	quoted bool
+}
+
+// vdfTokens splits KeyValues text into quoted strings, bare words, and braces.
+func vdfTokens(r io.Reader) ([]vdfToken, error) {
+	var tokens []vdfToken
+	br := bufio.NewReader(r)
+	for {
+		c, _, err := br.ReadRune()
+		if err == io.EOF {
+			return tokens, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case c == ' ' || c == '\t' || c == '\r' || c == '\n':
+		case c == '{' || c == '}':
+			tokens = append(tokens, vdfToken{text: string(c)})
+		case c == '/':
+			if next, _, _ := br.ReadRune(); next == '/' {
+				br.ReadString('\n')
+			} else {
+				return nil, fmt.Errorf("unexpected /")
+			}
+		case c == '"':
+			var b strings.Builder
+			for {
+				c, _, err := br.ReadRune()
+				if err != nil {
+					return nil, fmt.Errorf("unterminated string")
+				}
+				if c == '"' {
+					break
+				}
+				if c == '\\' {
+					if esc, _, err := br.ReadRune(); err == nil {
+						switch esc {
+						case 'n':
+							c = '\n'
+						case 't':
+							c = '\t'
+						default:
+							c = esc
+						}
+					}
+				}
+				b.WriteRune(c)
+			}
+			tokens = append(tokens, vdfToken{text: b.String(), quoted: true})
+		default:
+			var b strings.Builder
+			b.WriteRune(c)
+			for {
+				c, _, err := br.ReadRune()
+				if err != nil || strings.ContainsRune(" \t\r\n{}\"", c) {
+					if err == nil {
+						br.UnreadRune()
+					}
+					break
+				}
+				b.WriteRune(c)
+			}
+			tokens = append(tokens, vdfToken{text: b.String()})
+		}
+	}
+}
+
+// SteamLastPlayed reads when each app was last launched from the
+// localconfig.vdf of every Steam account under steamPath/userdata, keeping
+// the latest time per app ID. It returns nil if no account's file is readable.
+func SteamLastPlayed(steamPath string) map[string]time.Time {
+	files, _ := filepath.Glob(filepath.Join(steamPath, "userdata", "*", "config", "localconfig.vdf"))
+	var played map[string]time.Time
+	for _, file := range files {
+		f, err := os.Open(file)
+		if err != nil {
+			continue
+		}
+		root, err := ParseVDF(f)
+		f.Close()
+		if err != nil {
+			continue
+		}
+		if played == nil {
+			played = make(map[string]time.Time)
+		}
+		apps := root.Path("UserLocalConfigStore", "Software", "Valve", "Steam", "apps")
+		for _, id := range apps.Keys() {
+			secs, err := strconv.ParseInt(apps.Child(id).Value("LastPlayed"), 10, 64)
+			if err != nil || secs <= 0 {
+				continue
+			}
+			if t := time.Unix(secs, 0); t.After(played[id]) {
+				played[id] = t
+			}
+		}
+	}
+	return played
+}
+
+// SteamFilter decides which installed Steam apps the options leave out.
+type SteamFilter struct {
+	opts       SteamOptions
+	lastPlayed map[string]time.Time // nil when playtime is unknown
+	cutoff     time.Time
+	dirOwner   map[string]string // install dir -> lowest app ID using it
+}
+
+// NewSteamFilter prepares a filter over the manifests of one Steam install.
+// lastPlayed comes from SteamLastPlayed; when it is nil the played-within
+// option is ignored rather than leaving out every game.
+func NewSteamFilter(opts SteamOptions, manifests []SteamManifest, lastPlayed map[string]time.Time, now time.Time) *SteamFilter {
+	f := &SteamFilter{opts: opts, lastPlayed: lastPlayed, dirOwner: make(map[string]string)}
+	if opts.PlayedWithinMonths > 0 {
+		f.cutoff = now.AddDate(0, -opts.PlayedWithinMonths, 0)
+	}
+	for _, m := range manifests {
+		dir := strings.ToLower(m.InstallDir)
+		if dir == "" {
+			continue
+		}
+		if owner, ok := f.dirOwner[dir]; !ok || lessAppID(m.AppID, owner) {
+			f.dirOwner[dir] = m.AppID
+		}
+	}
+	return f
+}
+
+// Skip reports whether m should be left out, and why.
+func (f *SteamFilter) Skip(m SteamManifest) (bool, string) {
+	if f.opts.SkipSoundtracks && isSoundtrack(m.Name) {
+		return true, "soundtrack"
+	}
+	if f.opts.SkipDLC && f.isDLC(m) {
+		return true, "DLC"
+	}
+	if !f.cutoff.IsZero() && f.lastPlayed != nil && f.lastPlayed[m.AppID].Before(f.cutoff) {
+		return true, fmt.Sprintf("not played in %d months", f.opts.PlayedWithinMonths)
+	}
+	return false, ""
+}
+
+// isDLC reports whether m looks like an add-on rather than a game: it has
+// nothing installed of its own, it installs into another app's directory, or
+// its name says so.
+func (f *SteamFilter) isDLC(m SteamManifest) bool {
+	if m.SizeOnDisk == 0 {
+		return true
+	}
+	if owner, ok := f.dirOwner[strings.ToLower(m.InstallDir)]; ok && owner != m.AppID {
+		return true
+	}
+	lower := strings.ToLower(m.Name)
+	for _, p := range []string{" dlc", "season pass", "expansion pass", "bonus content"} {
+		if strings.Contains(lower, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// isSoundtrack reports whether name is a soundtrack app, e.g. "Portal 2
+// Soundtrack" or "Celeste OST".
+func isSoundtrack(name string) bool {
+	lower := strings.ToLower(name)
+	if strings.Contains(lower, "soundtrack") || strings.Contains(lower, "original score") {
+		return true
+	}
+	for _, w := range strings.FieldsFunc(lower, func(r rune) bool { return !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9') }) {
+		if w == "ost" {
+			return true
+		}
+	}
+	return false
+}
+
+// lessAppID compares numeric app IDs, falling back to string order.
+func lessAppID(a, b string) bool {
+	ai, errA := strconv.Atoi(a)
+	bi, errB := strconv.Atoi(b)
+	if errA != nil || errB != nil {
+		return a < b
+	}
+	return ai < bi
+}
@@ -0,0 +1,198 @@
+package discover
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseVDF(t *testing.T) {
+	content := `// written by Steam
+"AppState"
+{
+	"appid"		"620"
+	"Name"		"Portal 2"
+	"installdir"	"Portal 2"
+	"UserConfig"
+	{
+		"language"	"english"
+	}
+	"quote"		"say \"hi\""
+	bare		word
+}
+`
+	root, err := ParseVDF(strings.NewReader(content))
+	if err != nil {
+		t.Fatalf("ParseVDF failed: %v", err)
+	}
+	state := root.Child("appstate")
+	if got := state.Value("name"); got != "Portal 2" {
+		t.Errorf("name = %q, expected %q", got, "Portal 2")
+	}
+	if got := root.Path("AppState", "UserConfig").Value("language"); got != "english" {
+		t.Errorf("language = %q, expected %q", got, "english")
+	}
+	if got := state.Value("quote"); got != `say "hi"` {
+		t.Errorf("quote = %q, expected %q", got, `say "hi"`)
+	}
+	if got := state.Value("bare"); got != "word" {
+		t.Errorf("bare = %q, expected %q", got, "word")
+	}
+	if got := root.Path("AppState", "missing", "deeper").Value("x"); got != "" {
+		t.Errorf("missing path returned %q", got)
+	}
+}
+
+func TestParseVDFErrors(t *testing.T) {
+	cases := []string{
+		`"a" { "b" "c"`,
+		`"a" "b" }`,
+		`"a"`,
+		`"a" "unterminated`,
+	}
+	for _, c := range cases {
+		if _, err := ParseVDF(strings.NewReader(c)); err == nil {
+			t.Errorf("ParseVDF(%q) should fail", c)
+		}
+	}
+}
+
+func TestReadSteamManifest(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "appmanifest_620.acf")
+	content := `"AppState"
+{
+	"appid"		"620"
+	"name"		"Portal 2"
+	"installdir"	"Portal 2"
+	"SizeOnDisk"	"12345"
+}
+`
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	m, err := ReadSteamManifest(path)
+	if err != nil {
+		t.Fatalf("ReadSteamManifest failed: %v", err)
+	}
+	expected := SteamManifest{AppID: "620", Name: "Portal 2", InstallDir: "Portal 2", SizeOnDisk: 12345}
+	if m != expected {
+		t.Errorf("got %+v, expected %+v", m, expected)
+	}
+
+	noSize := filepath.Join(dir, "appmanifest_1.acf")
+	os.WriteFile(noSize, []byte(`"AppState" { "appid" "1" "name" "Old" }`), 0644)
+	if m, err := ReadSteamManifest(noSize); err != nil || m.SizeOnDisk != -1 {
+		t.Errorf("missing SizeOnDisk: got %+v, %v; expected size -1", m, err)
+	}
+
+	incomplete := filepath.Join(dir, "appmanifest_2.acf")
+	os.WriteFile(incomplete, []byte(`"AppState" { "appid" "2" }`), 0644)
+	if _, err := ReadSteamManifest(incomplete); err == nil {
+		t.Error("expected error for manifest without a name")
+	}
+}
+
+func TestSteamLastPlayed(t *testing.T) {
+	steam := t.TempDir()
+	if got := SteamLastPlayed(steam); got != nil {
+		t.Errorf("expected nil without userdata, got %v", got)
+	}
+
+	write := func(user, content string) {
+		dir := filepath.Join(steam, "userdata", user, "config")
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, "localconfig.vdf"), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("111", `"UserLocalConfigStore" { "Software" { "Valve" { "Steam" { "apps" {
+		"620" { "LastPlayed" "1600000000" }
+		"730" { "LastPlayed" "1700000000" }
+		"440" { "Playtime" "5" }
+	} } } } }`)
+	write("222", `"UserLocalConfigStore" { "Software" { "Valve" { "Steam" { "apps" {
+		"620" { "LastPlayed" "1650000000" }
+	} } } } }`)
+
+	got := SteamLastPlayed(steam)
+	if !got["620"].Equal(time.Unix(1650000000, 0)) {
+		t.Errorf("620: expected the later of both accounts, got %v", got["620"])
+	}
+	if !got["730"].Equal(time.Unix(1700000000, 0)) {
+		t.Errorf("730: got %v", got["730"])
+	}
+	if _, ok := got["440"]; ok {
+		t.Error("440 has no LastPlayed and should be absent")
+	}
+}
+
+func TestSteamFilter(t *testing.T) {
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	game := SteamManifest{AppID: "620", Name: "Portal 2", InstallDir: "Portal 2", SizeOnDisk: 100}
+	ost := SteamManifest{AppID: "1000", Name: "Celeste OST", InstallDir: "Celeste OST", SizeOnDisk: 50}
+	shared := SteamManifest{AppID: "900", Name: "Portal 2 Extras", InstallDir: "portal 2", SizeOnDisk: 10}
+	empty := SteamManifest{AppID: "901", Name: "Portal 2 Hats", InstallDir: "Hats", SizeOnDisk: 0}
+	named := SteamManifest{AppID: "902", Name: "Game - Season Pass", InstallDir: "SP", SizeOnDisk: 10}
+	unknownSize := SteamManifest{AppID: "903", Name: "Old Game", InstallDir: "Old", SizeOnDisk: -1}
+	manifests := []SteamManifest{game, ost, shared, empty, named, unknownSize}
+
+	played := map[string]time.Time{
+		"620": now.AddDate(0, -1, 0),
+		"903": now.AddDate(-2, 0, 0),
+	}
+
+	// No options: nothing is skipped
+	f := NewSteamFilter(SteamOptions{}, manifests, played, now)
+	for _, m := range manifests {
+		if skip, reason := f.Skip(m); skip {
+			t.Errorf("%s skipped (%s) with no options set", m.Name, reason)
+		}
+	}
+
+	opts := SteamOptions{SkipSoundtracks: true, SkipDLC: true, PlayedWithinMonths: 6}
+	f = NewSteamFilter(opts, manifests, played, now)
+	cases := []struct {
+		m      SteamManifest
+		skip   bool
+		reason string
+	}{
+		{game, false, ""},
+		{ost, true, "soundtrack"},
+		{shared, true, "DLC"}, // Portal 2 has the lower app ID, so owns the directory
+		{empty, true, "DLC"},
+		{named, true, "DLC"},
+		{unknownSize, true, "not played in 6 months"},
+	}
+	for _, tc := range cases {
+		skip, reason := f.Skip(tc.m)
+		if skip != tc.skip || reason != tc.reason {
+			t.Errorf("%s: got (%v, %q), expected (%v, %q)", tc.m.Name, skip, reason, tc.skip, tc.reason)
+		}
+	}
+
+	// Without playtime data the played-within option keeps everything
+	f = NewSteamFilter(SteamOptions{PlayedWithinMonths: 1}, manifests, nil, now)
+	if skip, _ := f.Skip(unknownSize); skip {
+		t.Error("played-within option should be ignored when playtime is unknown")
+	}
+}
+
+func TestIsSoundtrack(t *testing.T) {
+	cases := map[string]bool{
+		"Portal 2 Soundtrack":           true,
+		"Celeste OST":                   true,
+		"Doom Eternal (Original Score)": true,
+		"Hostage":                       false,
+		"Frostpunk":                     false,
+	}
+	for name, expected := range cases {
+		if got := isSoundtrack(name); got != expected {
+			t.Errorf("isSoundtrack(%q) = %v, expected %v", name, got, expected)
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package discover
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Watcher is implemented by sources that can name the directories whose
+// entries change when applications are installed or removed, so a watch can
+// tell when discovery would find something new without running it.
+type Watcher interface {
+	// WatchPaths returns the directories to watch. Only their direct entries
+	// are compared, so a source whose layout nests includes the subdirectories.
+	WatchPaths() []string
+}
+
+// WatchPaths returns the directories watched for the sources DiscoverAll
+// would run with sourceNames, sorted and without duplicates. Sources that do
+// not implement Watcher contribute none.
+func (r *Registry) WatchPaths(sourceNames []string) []string {
+	seen := make(map[string]bool)
+	var paths []string
+	for _, s := range r.AvailableSources() {
+		if len(sourceNames) > 0 && !containsFold(sourceNames, s.Name()) {
+			continue
+		}
+		w, ok := s.(Watcher)
+		if !ok {
+			continue
+		}
+		for _, p := range w.WatchPaths() {
+			if p != "" && !seen[p] {
+				seen[p] = true
+				paths = append(paths, p)
+			}
+		}
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// Fingerprint summarizes the direct entries of dirs (name, size, and
+// modification time) as a short string that changes whenever an entry is
+// added, removed, or rewritten. Missing directories count as empty.
+func Fingerprint(dirs []string) string {
+	h := fnv.New64a()
+	for _, dir := range dirs {
+		fmt.Fprintf(h, "%s\n", dir)
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, e := range entries {
+			info, err := e.Info()
+			if err != nil {
+				continue
+			}
+			fmt.Fprintf(h, "%s|%d|%d\n", filepath.Join(dir, e.Name()), info.Size(), info.ModTime().UnixNano())
+		}
+	}
+	return fmt.Sprintf("%016x", h.Sum64())
+}
+
+// Watch calls fingerprint every interval until ctx is done, and calls changed
+// once a new fingerprint has held for a whole interval. An install writes many
+// files over a while, so waiting for it to settle reports it once, after it
+// finishes. A change that is undone before it settles is not reported.
+func Watch(ctx context.Context, interval time.Duration, fingerprint func() string, changed func()) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	last, pending := fingerprint(), ""
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		switch fp := fingerprint(); {
+		case fp == last:
+			pending = ""
+		case fp != pending:
+			pending = fp // wait for it to hold
+		default:
+			last, pending = fp, ""
+			changed()
+		}
+	}
+}
+
+// containsFold reports whether names contains name, ignoring case.
+func containsFold(names []string, name string) bool {
+	for _, n := range names {
+		if strings.EqualFold(n, name) {
+			return true
+		}
+	}
+	return false
+}
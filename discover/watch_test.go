@@ -0,0 +1,108 @@
+package discover
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+type watchingSource struct {
+	mockSource
+	paths []string
+}
+
+func (w *watchingSource) WatchPaths() []string { return w.paths }
+
+func TestRegistryWatchPaths(t *testing.T) {
+	r := NewRegistry()
+	r.Register(&mockSource{name: "plain", category: "Apps", available: true})
+	r.Register(&watchingSource{mockSource: mockSource{name: "steam", category: "Games", available: true}, paths: []string{"/b", "/a"}})
+	r.Register(&watchingSource{mockSource: mockSource{name: "desktop", category: "Apps", available: true}, paths: []string{"/a", "", "/c"}})
+	r.Register(&watchingSource{mockSource: mockSource{name: "gone", category: "Apps"}, paths: []string{"/gone"}})
+
+	if got, want := r.WatchPaths(nil), []string{"/a", "/b", "/c"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("expected the sorted paths of available sources, got %v, want %v", got, want)
+	}
+	if got, want := r.WatchPaths([]string{"STEAM", "plain"}), []string{"/a", "/b"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("expected only the named sources' paths, got %v, want %v", got, want)
+	}
+}
+
+func TestFingerprint(t *testing.T) {
+	dir := t.TempDir()
+	missing := filepath.Join(dir, "missing")
+	dirs := []string{dir, missing}
+
+	empty := Fingerprint(dirs)
+	if Fingerprint(dirs) != empty {
+		t.Fatal("expected the same fingerprint for unchanged directories")
+	}
+
+	manifest := filepath.Join(dir, "appmanifest_620.acf")
+	if err := os.WriteFile(manifest, []byte("one"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	added := Fingerprint(dirs)
+	if added == empty {
+		t.Error("expected a new file to change the fingerprint")
+	}
+
+	if err := os.WriteFile(manifest, []byte("longer"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if Fingerprint(dirs) == added {
+		t.Error("expected a rewritten file to change the fingerprint")
+	}
+
+	if err := os.Remove(manifest); err != nil {
+		t.Fatal(err)
+	}
+	if Fingerprint(dirs) != empty {
+		t.Error("expected removing the file to restore the fingerprint")
+	}
+}
+
+func TestWatchReportsSettledChanges(t *testing.T) {
+	var mu sync.Mutex
+	fingerprints := []string{"a", "a", "b", "a", "c", "d", "d", "d"}
+	next := func() string {
+		mu.Lock()
+		defer mu.Unlock()
+		fp := fingerprints[0]
+		if len(fingerprints) > 1 {
+			fingerprints = fingerprints[1:]
+		}
+		return fp
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	changes := 0
+	done := make(chan struct{})
+	go func() {
+		Watch(ctx, time.Millisecond, next, func() { changes++ })
+		close(done)
+	}()
+
+	for {
+		mu.Lock()
+		left := len(fingerprints)
+		mu.Unlock()
+		if left == 1 {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	time.Sleep(20 * time.Millisecond)
+	cancel()
+	<-done
+
+	// "b" was undone and "c" never held; only "d" is reported, once
+	if changes != 1 {
+		t.Errorf("expected one settled change, got %d", changes)
+	}
+}
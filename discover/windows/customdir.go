@@ -3,6 +3,7 @@
 package windows
 
 import (
+	"context"
 	"io/fs"
 	"math"
 	"os"
@@ -11,6 +12,7 @@ import (
 	"strings"
 
 	"github.com/benworks/menuworks/discover"
+	"github.com/benworks/menuworks/shell"
 )
 
 // archDirNames is the set of directory names considered architecture-specific.
@@ -147,8 +149,13 @@ func (s *CustomDirSource) Available() bool {
 	return err == nil
 }
 
+// Diagnose reports whether the configured directory exists.
+func (s *CustomDirSource) Diagnose() []discover.Check {
+	return []discover.Check{discover.PathCheck(s.Dir)}
+}
+
 // Discover walks Dir recursively, applies filtering, and returns discovered apps.
-func (s *CustomDirSource) Discover() ([]discover.DiscoveredApp, error) {
+func (s *CustomDirSource) Discover(ctx context.Context) ([]discover.DiscoveredApp, error) {
 	rootAbs, err := filepath.Abs(s.Dir)
 	if err != nil {
 		rootAbs = s.Dir
@@ -159,6 +166,9 @@ func (s *CustomDirSource) Discover() ([]discover.DiscoveredApp, error) {
 	groups := make(map[string][]string)
 
 	walkerr := filepath.WalkDir(s.Dir, func(path string, d fs.DirEntry, err error) error {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
 		if err != nil {
 			return nil // skip inaccessible entries
 		}
@@ -231,14 +241,10 @@ func (s *CustomDirSource) Discover() ([]discover.DiscoveredApp, error) {
 		displayName = strings.TrimSuffix(displayName, ".EXE")
 		displayName = cleanRelPath(displayName)
 
-		execPath := absPath
-		if strings.Contains(execPath, " ") {
-			execPath = `"` + execPath + `"`
-		}
-
 		apps = append(apps, discover.DiscoveredApp{
 			Name:     displayName,
-			Exec:     execPath,
+			Exec:     shell.Quote("windows", []string{absPath}),
+			Argv:     []string{absPath},
 			Source:   s.MenuName,
 			Category: s.MenuName,
 		})
@@ -3,11 +3,13 @@
 package windows
 
 import (
+	"context"
 	"os"
 	"path/filepath"
 	"strings"
 
 	"github.com/benworks/menuworks/discover"
+	"github.com/benworks/menuworks/shell"
 )
 
 // ProgramFilesSource discovers applications from Program Files directories.
@@ -16,6 +18,8 @@ type ProgramFilesSource struct{}
 func (s *ProgramFilesSource) Name() string     { return "programfiles" }
 func (s *ProgramFilesSource) Category() string { return "Applications" }
 
+func (s *ProgramFilesSource) CategoryIcon() string { return "📦" }
+
 func (s *ProgramFilesSource) Available() bool {
 	for _, dir := range programFilesDirs() {
 		if _, err := os.Stat(dir); err == nil {
@@ -25,9 +29,20 @@ func (s *ProgramFilesSource) Available() bool {
 	return false
 }
 
-func (s *ProgramFilesSource) Discover() ([]discover.DiscoveredApp, error) {
+// Diagnose lists the Program Files variables read and the directories they
+// point to.
+func (s *ProgramFilesSource) Diagnose() []discover.Check {
+	checks := []discover.Check{discover.EnvCheck("ProgramFiles"), discover.EnvCheck("ProgramFiles(x86)")}
+	for _, dir := range programFilesDirs() {
+		checks = append(checks, discover.PathCheck(dir))
+	}
+	return checks
+}
+
+func (s *ProgramFilesSource) Discover(ctx context.Context) ([]discover.DiscoveredApp, error) {
 	var apps []discover.DiscoveredApp
 	seen := make(map[string]bool)
+	installed := readUninstallEntries()
 
 	for _, baseDir := range programFilesDirs() {
 		entries, err := os.ReadDir(baseDir)
@@ -36,12 +51,15 @@ func (s *ProgramFilesSource) Discover() ([]discover.DiscoveredApp, error) {
 		}
 
 		for _, entry := range entries {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
 			if !entry.IsDir() {
 				continue
 			}
 
 			subDir := filepath.Join(baseDir, entry.Name())
-			exe := findMainExecutable(subDir, entry.Name())
+			exe, confidence := findMainExecutable(subDir, entry.Name())
 			if exe == "" {
 				continue
 			}
@@ -54,12 +72,16 @@ func (s *ProgramFilesSource) Discover() ([]discover.DiscoveredApp, error) {
 
 			name := cleanAppName(entry.Name(), filepath.Base(exe))
 
-			apps = append(apps, discover.DiscoveredApp{
-				Name:     name,
-				Exec:     exe,
-				Source:   "Program Files",
-				Category: "Applications",
-			})
+			app := discover.DiscoveredApp{
+				Name:       name,
+				Exec:       shell.Quote("windows", []string{exe}),
+				Argv:       []string{exe},
+				Source:     "Program Files",
+				Category:   "Applications",
+				Confidence: confidence,
+			}
+			applyUninstall(&app, matchUninstall(installed, exe))
+			apps = append(apps, app)
 		}
 	}
 
@@ -78,16 +100,19 @@ func programFilesDirs() []string {
 	return dirs
 }
 
-// findMainExecutable finds the single best .exe in a directory.
-// Prefers an exe whose name matches the directory name; otherwise picks the first non-filtered one.
-func findMainExecutable(dir string, dirName string) string {
+// findMainExecutable finds the single best .exe in a directory and scores how
+// sure the pick is, from 1 to 100. An exe named after the directory wins
+// outright; otherwise one whose name shares a word with the directory is
+// preferred, then the sole candidate, then the first of several.
+func findMainExecutable(dir string, dirName string) (string, int) {
 	entries, err := os.ReadDir(dir)
 	if err != nil {
-		return ""
+		return "", 0
 	}
 
 	normDir := strings.ToLower(strings.ReplaceAll(dirName, " ", ""))
-	var fallback string
+	var candidates []string
+	partial := ""
 
 	for _, entry := range entries {
 		if entry.IsDir() {
@@ -104,16 +129,39 @@ func findMainExecutable(dir string, dirName string) string {
 		// Prefer exe whose base name (without .exe) matches the directory name
 		normExe := strings.ToLower(strings.ReplaceAll(strings.TrimSuffix(name, filepath.Ext(name)), " ", ""))
 		if normExe == normDir {
-			return filepath.Join(dir, name)
+			return filepath.Join(dir, name), 95
 		}
-
-		// Keep first valid exe as fallback
-		if fallback == "" {
-			fallback = filepath.Join(dir, name)
+		if partial == "" && sharesWord(dirName, normExe) {
+			partial = filepath.Join(dir, name)
 		}
+		candidates = append(candidates, filepath.Join(dir, name))
+	}
+
+	switch {
+	case len(candidates) == 0:
+		return "", 0
+	case partial != "":
+		return partial, 70
+	case len(candidates) == 1:
+		return candidates[0], 60
+	case len(candidates) == 2:
+		return candidates[0], 35
+	default:
+		return candidates[0], 20
 	}
+}
 
-	return fallback
+// sharesWord reports whether normExe contains a word of at least three
+// letters from dirName, or dirName's initials (e.g. "vlc" in "VideoLAN VLC").
+func sharesWord(dirName, normExe string) bool {
+	var initials strings.Builder
+	for _, w := range strings.Fields(strings.ToLower(dirName)) {
+		initials.WriteByte(w[0])
+		if len(w) >= 3 && strings.Contains(normExe, w) {
+			return true
+		}
+	}
+	return initials.Len() >= 2 && normExe == initials.String()
 }
 
 // isFilteredExecutable returns true if the exe name suggests it should be excluded.
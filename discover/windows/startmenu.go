@@ -3,14 +3,18 @@
 package windows
 
 import (
+	"context"
+	"fmt"
 	"os"
 	"path/filepath"
+	"runtime"
 	"strings"
 	"syscall"
 	"unicode/utf16"
 	"unsafe"
 
 	"github.com/benworks/menuworks/discover"
+	"github.com/benworks/menuworks/shell"
 )
 
 // StartMenuSource discovers applications from Windows Start Menu shortcuts.
@@ -19,6 +23,8 @@ type StartMenuSource struct{}
 func (s *StartMenuSource) Name() string     { return "startmenu" }
 func (s *StartMenuSource) Category() string { return "Applications" }
 
+func (s *StartMenuSource) CategoryIcon() string { return "📦" }
+
 func (s *StartMenuSource) Available() bool {
 	for _, dir := range startMenuDirs() {
 		if _, err := os.Stat(dir); err == nil {
@@ -28,9 +34,40 @@ func (s *StartMenuSource) Available() bool {
 	return false
 }
 
-func (s *StartMenuSource) Discover() ([]discover.DiscoveredApp, error) {
+// Diagnose lists the variables read and the Start Menu directories looked in.
+func (s *StartMenuSource) Diagnose() []discover.Check {
+	checks := []discover.Check{discover.EnvCheck("ProgramData"), discover.EnvCheck("APPDATA")}
+	for _, dir := range startMenuDirs() {
+		checks = append(checks, discover.PathCheck(dir))
+	}
+	return checks
+}
+
+// WatchPaths returns the Start Menu directories and every folder inside
+// them, since installers put their shortcuts in a folder of their own.
+func (s *StartMenuSource) WatchPaths() []string {
+	var dirs []string
+	for _, dir := range startMenuDirs() {
+		filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+			if err == nil && info.IsDir() {
+				dirs = append(dirs, path)
+			}
+			return nil
+		})
+	}
+	return dirs
+}
+
+func (s *StartMenuSource) Discover(ctx context.Context) ([]discover.DiscoveredApp, error) {
+	resolver, err := newShortcutResolver()
+	if err != nil {
+		return nil, fmt.Errorf("startmenu: %w", err)
+	}
+	defer resolver.Close()
+
 	var apps []discover.DiscoveredApp
 	seen := make(map[string]bool)
+	installed := readUninstallEntries()
 
 	for _, dir := range startMenuDirs() {
 		if _, err := os.Stat(dir); err != nil {
@@ -38,6 +75,9 @@ func (s *StartMenuSource) Discover() ([]discover.DiscoveredApp, error) {
 		}
 
 		err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
 			if err != nil {
 				return nil // skip inaccessible entries
 			}
@@ -53,10 +93,11 @@ func (s *StartMenuSource) Discover() ([]discover.DiscoveredApp, error) {
 				return nil
 			}
 
-			target := resolveShortcut(path)
+			target := resolver.Resolve(path)
 			if target == "" {
 				return nil
 			}
+			description := resolver.Description()
 
 			// Normalize for dedup
 			key := strings.ToLower(target)
@@ -65,14 +106,21 @@ func (s *StartMenuSource) Discover() ([]discover.DiscoveredApp, error) {
 			}
 			seen[key] = true
 
-			apps = append(apps, discover.DiscoveredApp{
-				Name:     name,
-				Exec:     target,
-				Source:   "Start Menu",
-				Category: "Applications",
-			})
+			app := discover.DiscoveredApp{
+				Name:        name,
+				Exec:        shell.Quote("windows", []string{target}),
+				Argv:        []string{target},
+				Source:      "Start Menu",
+				Category:    "Applications",
+				Description: description,
+			}
+			applyUninstall(&app, matchUninstall(installed, target))
+			apps = append(apps, app)
 			return nil
 		})
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
 		if err != nil {
 			// Continue scanning other directories
 			continue
@@ -117,23 +165,14 @@ func isFilteredShortcut(name string) bool {
 	return false
 }
 
-// resolveShortcut reads a .lnk file and returns the target path.
-// Uses the Windows IShellLink COM interface via raw syscall.
-func resolveShortcut(lnkPath string) string {
-	// Initialize COM
-	ole32 := syscall.NewLazyDLL("ole32.dll")
-	coInitialize := ole32.NewProc("CoInitializeEx")
-	coUninitialize := ole32.NewProc("CoUninitialize")
-	coCreateInstance := ole32.NewProc("CoCreateInstance")
-
-	ret, _, _ := coInitialize.Call(0, 0) // COINIT_MULTITHREADED
-	if ret != 0 && ret != 1 {            // S_OK or S_FALSE (already initialized)
-		return ""
-	}
-	defer coUninitialize.Call()
+var (
+	ole32            = syscall.NewLazyDLL("ole32.dll")
+	coInitializeEx   = ole32.NewProc("CoInitializeEx")
+	coUninitialize   = ole32.NewProc("CoUninitialize")
+	coCreateInstance = ole32.NewProc("CoCreateInstance")
 
 	// CLSID_ShellLink = {00021401-0000-0000-C000-000000000046}
-	clsidShellLink := &syscall.GUID{
+	clsidShellLink = &syscall.GUID{
 		Data1: 0x00021401,
 		Data2: 0x0000,
 		Data3: 0x0000,
@@ -141,7 +180,7 @@ func resolveShortcut(lnkPath string) string {
 	}
 
 	// IID_IShellLinkW = {000214F9-0000-0000-C000-000000000046}
-	iidShellLink := &syscall.GUID{
+	iidShellLink = &syscall.GUID{
 		Data1: 0x000214F9,
 		Data2: 0x0000,
 		Data3: 0x0000,
@@ -149,52 +188,121 @@ func resolveShortcut(lnkPath string) string {
 	}
 
 	// IID_IPersistFile = {0000010B-0000-0000-C000-000000000046}
-	iidPersistFile := &syscall.GUID{
+	iidPersistFile = &syscall.GUID{
 		Data1: 0x0000010B,
 		Data2: 0x0000,
 		Data3: 0x0000,
 		Data4: [8]byte{0xC0, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x46},
 	}
+)
+
+// infoTipSize is INFOTIPSIZE, the longest shortcut description Windows
+// stores; it is also longer than MAX_PATH, so one buffer serves both reads.
+const infoTipSize = 1024
+
+// shortcutResolver reads .lnk targets through one IShellLink instance, so a
+// Start Menu scan initializes COM and creates the object once rather than
+// per shortcut. COM state belongs to the OS thread, so the calling goroutine
+// is locked to its thread until Close.
+type shortcutResolver struct {
+	psl    uintptr // IShellLinkW
+	ppf    uintptr // IPersistFile on the same object
+	buf    []uint16
+	comOwn bool // whether Close must call CoUninitialize
+}
+
+// newShortcutResolver initializes COM on the current thread and creates the
+// ShellLink object. Callers must Close the resolver on the same goroutine.
+func newShortcutResolver() (*shortcutResolver, error) {
+	runtime.LockOSThread()
+	r := &shortcutResolver{buf: make([]uint16, infoTipSize)}
+
+	ret, _, _ := coInitializeEx.Call(0, 0) // COINIT_MULTITHREADED
+	if ret != 0 && ret != 1 {              // S_OK or S_FALSE (already initialized)
+		runtime.UnlockOSThread()
+		return nil, fmt.Errorf("CoInitializeEx failed: 0x%08x", ret)
+	}
+	r.comOwn = true
 
-	var psl uintptr
 	ret, _, _ = coCreateInstance.Call(
 		uintptr(unsafe.Pointer(clsidShellLink)),
 		0,
 		1, // CLSCTX_INPROC_SERVER
 		uintptr(unsafe.Pointer(iidShellLink)),
-		uintptr(unsafe.Pointer(&psl)),
+		uintptr(unsafe.Pointer(&r.psl)),
 	)
 	if ret != 0 {
-		return ""
+		r.Close()
+		return nil, fmt.Errorf("CoCreateInstance(ShellLink) failed: 0x%08x", ret)
 	}
-	defer callRelease(psl)
 
-	// QueryInterface for IPersistFile
-	var ppf uintptr
-	ret = queryInterface(psl, iidPersistFile, &ppf)
-	if ret != 0 {
-		return ""
+	if ret = queryInterface(r.psl, iidPersistFile, &r.ppf); ret != 0 {
+		r.Close()
+		return nil, fmt.Errorf("QueryInterface(IPersistFile) failed: 0x%08x", ret)
 	}
-	defer callRelease(ppf)
+	return r, nil
+}
 
-	// IPersistFile::Load
+// Resolve loads lnkPath into the ShellLink object and returns its target
+// path, or "" if the shortcut cannot be read or has no file target.
+func (r *shortcutResolver) Resolve(lnkPath string) string {
 	lnkPathUTF16, err := syscall.UTF16PtrFromString(lnkPath)
 	if err != nil {
 		return ""
 	}
-	ret = persistFileLoad(ppf, lnkPathUTF16, 0) // STGM_READ
-	if ret != 0 {
+	if ret := persistFileLoad(r.ppf, lnkPathUTF16, 0); ret != 0 { // STGM_READ
 		return ""
 	}
 
-	// IShellLinkW::GetPath
-	buf := make([]uint16, syscall.MAX_PATH)
-	ret = shellLinkGetPath(psl, &buf[0], int32(len(buf)), 0, 0) // SLGP_RAWPATH
-	if ret != 0 {
+	for i := range r.buf {
+		r.buf[i] = 0
+	}
+	if ret := shellLinkGetPath(r.psl, &r.buf[0], int32(len(r.buf)), 0, 0); ret != 0 { // SLGP_RAWPATH
 		return ""
 	}
+	return strings.TrimRight(syscall.UTF16ToString(r.buf), "\x00")
+}
 
-	return strings.TrimRight(syscall.UTF16ToString(buf), "\x00")
+// Description returns the comment of the shortcut last passed to Resolve,
+// the text Explorer shows as its tooltip, or "" if it has none.
+func (r *shortcutResolver) Description() string {
+	for i := range r.buf {
+		r.buf[i] = 0
+	}
+	if ret := shellLinkGetDescription(r.psl, &r.buf[0], int32(len(r.buf))); ret != 0 {
+		return ""
+	}
+	return strings.TrimSpace(syscall.UTF16ToString(r.buf))
+}
+
+// Close releases the ShellLink object, uninitializes COM, and unlocks the
+// goroutine from its thread.
+func (r *shortcutResolver) Close() {
+	if r.ppf != 0 {
+		callRelease(r.ppf)
+		r.ppf = 0
+	}
+	if r.psl != 0 {
+		callRelease(r.psl)
+		r.psl = 0
+	}
+	if r.comOwn {
+		coUninitialize.Call()
+		r.comOwn = false
+	}
+	runtime.UnlockOSThread()
+}
+
+// resolveShortcut reads a single .lnk file and returns the target path,
+// setting up and tearing down COM around it. Scans should use a
+// shortcutResolver instead.
+func resolveShortcut(lnkPath string) string {
+	r, err := newShortcutResolver()
+	if err != nil {
+		return ""
+	}
+	defer r.Close()
+	return r.Resolve(lnkPath)
 }
 
 // COM vtable helpers
@@ -226,6 +334,13 @@ func shellLinkGetPath(psl uintptr, buf *uint16, bufLen int32, findData uintptr,
 	return ret
 }
 
+func shellLinkGetDescription(psl uintptr, buf *uint16, bufLen int32) uintptr {
+	// IShellLinkW vtable: QI, AddRef, Release, GetPath, GetIDList, SetIDList, GetDescription(6), ...
+	vtable := *(*[20]uintptr)(unsafe.Pointer(*(*uintptr)(unsafe.Pointer(psl))))
+	ret, _, _ := syscall.SyscallN(vtable[6], psl, uintptr(unsafe.Pointer(buf)), uintptr(bufLen))
+	return ret
+}
+
 // utf16ToString converts a UTF-16 byte slice to string. Used for internal processing.
 func utf16ToString(s []uint16) string {
 	for i, v := range s {
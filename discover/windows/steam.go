@@ -4,27 +4,63 @@ package windows
 
 import (
 	"bufio"
+	"context"
 	"fmt"
 	"os"
 	"path/filepath"
 	"regexp"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/benworks/menuworks/discover"
+	"github.com/benworks/menuworks/logging"
 )
 
 // SteamSource discovers games from Steam library.
-type SteamSource struct{}
+type SteamSource struct {
+	Options discover.SteamOptions
+}
 
 func (s *SteamSource) Name() string     { return "steam" }
 func (s *SteamSource) Category() string { return "Games" }
 
+func (s *SteamSource) CategoryIcon() string { return "🎮" }
+
+// Configure takes the steam: options of the discover block.
+func (s *SteamSource) Configure(cfg *discover.DiscoverConfig) {
+	s.Options = cfg.Steam
+}
+
 func (s *SteamSource) Available() bool {
 	_, err := os.Stat(defaultSteamPath())
 	return err == nil
 }
 
-func (s *SteamSource) Discover() ([]discover.DiscoveredApp, error) {
+// Diagnose lists the Program Files variables read, the Steam directory looked
+// in and, if it exists, its library list.
+func (s *SteamSource) Diagnose() []discover.Check {
+	checks := []discover.Check{discover.EnvCheck("ProgramFiles(x86)"), discover.EnvCheck("ProgramFiles")}
+	steamPath := discover.PathCheck(defaultSteamPath())
+	checks = append(checks, steamPath)
+	if steamPath.OK {
+		checks = append(checks, discover.PathCheck(filepath.Join(defaultSteamPath(), "steamapps", "libraryfolders.vdf")))
+	}
+	return checks
+}
+
+// WatchPaths returns the steamapps directories of every library, where an
+// appmanifest file appears or goes as a game is installed or removed.
+func (s *SteamSource) WatchPaths() []string {
+	steamPath := defaultSteamPath()
+	folders, err := parseLibraryFolders(filepath.Join(steamPath, "steamapps", "libraryfolders.vdf"))
+	if err != nil {
+		folders = []string{filepath.Join(steamPath, "steamapps")}
+	}
+	return folders
+}
+
+func (s *SteamSource) Discover(ctx context.Context) ([]discover.DiscoveredApp, error) {
 	steamPath := defaultSteamPath()
 	libraryFolders, err := parseLibraryFolders(filepath.Join(steamPath, "steamapps", "libraryfolders.vdf"))
 	if err != nil {
@@ -32,22 +68,46 @@ func (s *SteamSource) Discover() ([]discover.DiscoveredApp, error) {
 		libraryFolders = []string{filepath.Join(steamPath, "steamapps")}
 	}
 
-	var apps []discover.DiscoveredApp
-	seen := make(map[string]bool)
-
+	var paths []string
+	var manifests []discover.SteamManifest
 	for _, libDir := range libraryFolders {
-		manifests, _ := filepath.Glob(filepath.Join(libDir, "appmanifest_*.acf"))
-		for _, manifest := range manifests {
-			app, err := parseAppManifest(manifest)
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		files, _ := filepath.Glob(filepath.Join(libDir, "appmanifest_*.acf"))
+		for _, file := range files {
+			m, err := discover.ReadSteamManifest(file)
 			if err != nil {
 				continue
 			}
-			if seen[app.Name] {
-				continue
-			}
-			seen[app.Name] = true
-			apps = append(apps, *app)
+			paths = append(paths, file)
+			manifests = append(manifests, m)
+		}
+	}
+
+	var lastPlayed map[string]time.Time
+	if s.Options.PlayedWithinMonths > 0 {
+		lastPlayed = discover.SteamLastPlayed(steamPath)
+	}
+	filter := discover.NewSteamFilter(s.Options, manifests, lastPlayed, time.Now())
+
+	var apps []discover.DiscoveredApp
+	seen := make(map[string]bool)
+
+	for i, m := range manifests {
+		if skip, reason := filter.Skip(m); skip {
+			logging.Debug("steam app skipped", "name", m.Name, "reason", reason)
+			continue
+		}
+		app, err := parseAppManifest(paths[i])
+		if err != nil {
+			continue
+		}
+		if seen[app.Name] {
+			continue
 		}
+		seen[app.Name] = true
+		apps = append(apps, *app)
 	}
 
 	return apps, nil
@@ -98,6 +158,7 @@ func parseAppManifest(path string) (*discover.DiscoveredApp, error) {
 	defer f.Close()
 
 	var appID, name string
+	var size int64
 	scanner := bufio.NewScanner(f)
 	for scanner.Scan() {
 		line := strings.TrimSpace(scanner.Text())
@@ -107,6 +168,8 @@ func parseAppManifest(path string) (*discover.DiscoveredApp, error) {
 				appID = v
 			case "name":
 				name = v
+			case "sizeondisk":
+				size, _ = strconv.ParseInt(v, 10, 64)
 			}
 		}
 	}
@@ -121,10 +184,11 @@ func parseAppManifest(path string) (*discover.DiscoveredApp, error) {
 	}
 
 	return &discover.DiscoveredApp{
-		Name:     name,
-		Exec:     fmt.Sprintf("start steam://rungameid/%s", appID),
-		Source:   "steam",
-		Category: "Games",
+		Name:      name,
+		Exec:      fmt.Sprintf("start steam://rungameid/%s", appID),
+		Source:    "steam",
+		Category:  "Games",
+		SizeBytes: size,
 	}, nil
 }
 
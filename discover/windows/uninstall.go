@@ -0,0 +1,165 @@
+//go:build windows
+
+package windows
+
+import (
+	"encoding/binary"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"unsafe"
+
+	"github.com/benworks/menuworks/discover"
+)
+
+// uninstallKey is where installers register the programs listed under
+// "Apps & features", with their version, publisher, install date, and size.
+const uninstallKey = `SOFTWARE\Microsoft\Windows\CurrentVersion\Uninstall`
+
+// uninstallEntry is the metadata one Uninstall registry key holds.
+type uninstallEntry struct {
+	DisplayName     string
+	DisplayVersion  string
+	Publisher       string
+	InstallDate     string // YYYYMMDD as installers write it
+	InstallLocation string
+	EstimatedSizeKB uint32
+}
+
+// readUninstallEntries reads the machine-wide (64- and 32-bit views) and
+// per-user Uninstall keys. Keys that cannot be read are skipped.
+func readUninstallEntries() []uninstallEntry {
+	var entries []uninstallEntry
+	views := []struct {
+		root   syscall.Handle
+		access uint32
+	}{
+		{syscall.HKEY_LOCAL_MACHINE, syscall.KEY_WOW64_64KEY},
+		{syscall.HKEY_LOCAL_MACHINE, syscall.KEY_WOW64_32KEY},
+		{syscall.HKEY_CURRENT_USER, 0},
+	}
+	for _, v := range views {
+		entries = append(entries, readUninstallView(v.root, v.access)...)
+	}
+	return entries
+}
+
+// readUninstallView reads every subkey of uninstallKey under root.
+func readUninstallView(root syscall.Handle, access uint32) []uninstallEntry {
+	key, err := openKey(root, uninstallKey, access)
+	if err != nil {
+		return nil
+	}
+	defer syscall.RegCloseKey(key)
+
+	var entries []uninstallEntry
+	buf := make([]uint16, 256)
+	for i := uint32(0); ; i++ {
+		n := uint32(len(buf))
+		if err := syscall.RegEnumKeyEx(key, i, &buf[0], &n, nil, nil, nil, nil); err != nil {
+			break
+		}
+		sub, err := openKey(key, syscall.UTF16ToString(buf[:n]), access)
+		if err != nil {
+			continue
+		}
+		e := uninstallEntry{
+			DisplayName:     regString(sub, "DisplayName"),
+			DisplayVersion:  regString(sub, "DisplayVersion"),
+			Publisher:       regString(sub, "Publisher"),
+			InstallDate:     regString(sub, "InstallDate"),
+			InstallLocation: regString(sub, "InstallLocation"),
+			EstimatedSizeKB: regDWORD(sub, "EstimatedSize"),
+		}
+		syscall.RegCloseKey(sub)
+		if e.DisplayName != "" {
+			entries = append(entries, e)
+		}
+	}
+	return entries
+}
+
+func openKey(parent syscall.Handle, path string, access uint32) (syscall.Handle, error) {
+	p, err := syscall.UTF16PtrFromString(path)
+	if err != nil {
+		return 0, err
+	}
+	var key syscall.Handle
+	err = syscall.RegOpenKeyEx(parent, p, 0, syscall.KEY_READ|access, &key)
+	return key, err
+}
+
+// regString reads a REG_SZ or REG_EXPAND_SZ value, or "" if it is missing.
+func regString(key syscall.Handle, name string) string {
+	data, typ := regValue(key, name)
+	if typ != syscall.REG_SZ && typ != syscall.REG_EXPAND_SZ || len(data) < 2 {
+		return ""
+	}
+	u := unsafe.Slice((*uint16)(unsafe.Pointer(&data[0])), len(data)/2)
+	return strings.TrimSpace(utf16ToString(u))
+}
+
+// regDWORD reads a REG_DWORD value, or 0 if it is missing.
+func regDWORD(key syscall.Handle, name string) uint32 {
+	data, typ := regValue(key, name)
+	if typ != syscall.REG_DWORD || len(data) < 4 {
+		return 0
+	}
+	return binary.LittleEndian.Uint32(data)
+}
+
+func regValue(key syscall.Handle, name string) ([]byte, uint32) {
+	p, err := syscall.UTF16PtrFromString(name)
+	if err != nil {
+		return nil, 0
+	}
+	var typ, n uint32
+	if syscall.RegQueryValueEx(key, p, nil, &typ, nil, &n) != nil || n == 0 {
+		return nil, 0
+	}
+	data := make([]byte, n)
+	if syscall.RegQueryValueEx(key, p, nil, &typ, &data[0], &n) != nil {
+		return nil, 0
+	}
+	return data[:n], typ
+}
+
+// matchUninstall returns the entry whose InstallLocation contains exe,
+// preferring the deepest location, or nil if none does.
+func matchUninstall(entries []uninstallEntry, exe string) *uninstallEntry {
+	exe = strings.ToLower(filepath.Clean(exe))
+	var best *uninstallEntry
+	bestLen := 0
+	for i := range entries {
+		loc := strings.TrimSpace(strings.Trim(entries[i].InstallLocation, `"`))
+		if loc == "" {
+			continue
+		}
+		loc = strings.ToLower(filepath.Clean(loc))
+		if !strings.HasPrefix(exe, loc+`\`) || len(loc) <= bestLen {
+			continue
+		}
+		best, bestLen = &entries[i], len(loc)
+	}
+	return best
+}
+
+// applyUninstall copies the entry's metadata onto app.
+func applyUninstall(app *discover.DiscoveredApp, e *uninstallEntry) {
+	if e == nil {
+		return
+	}
+	app.Version = e.DisplayVersion
+	app.Publisher = e.Publisher
+	app.InstallDate = formatInstallDate(e.InstallDate)
+	app.SizeBytes = int64(e.EstimatedSizeKB) * 1024
+}
+
+// formatInstallDate turns the registry's YYYYMMDD into YYYY-MM-DD; anything
+// else is dropped, since installers write the value inconsistently.
+func formatInstallDate(s string) string {
+	if len(s) != 8 || strings.Trim(s, "0123456789") != "" {
+		return ""
+	}
+	return s[:4] + "-" + s[4:6] + "-" + s[6:]
+}
@@ -3,10 +3,13 @@
 package windows
 
 import (
+	"context"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/benworks/menuworks/discover"
 )
@@ -146,6 +149,7 @@ func TestParseAppManifest(t *testing.T) {
 	"name"		"Half-Life 2"
 	"StateFlags"		"4"
 	"installdir"		"Half-Life 2"
+	"SizeOnDisk"		"6543210987"
 }`
 	manifestPath := filepath.Join(tmpDir, "appmanifest_220.acf")
 	os.WriteFile(manifestPath, []byte(manifest), 0644)
@@ -167,6 +171,9 @@ func TestParseAppManifest(t *testing.T) {
 	if app.Category != "Games" {
 		t.Errorf("expected category 'Games', got '%s'", app.Category)
 	}
+	if app.SizeBytes != 6543210987 {
+		t.Errorf("expected SizeOnDisk as the size, got %d", app.SizeBytes)
+	}
 }
 
 func TestParseAppManifestIncomplete(t *testing.T) {
@@ -203,6 +210,38 @@ func TestParseAppManifestFiltersTool(t *testing.T) {
 	}
 }
 
+// --- Uninstall Registry Metadata Tests ---
+
+func TestMatchUninstall(t *testing.T) {
+	entries := []uninstallEntry{
+		{DisplayName: "Vendor Suite", InstallLocation: `C:\Program Files\Vendor`},
+		{DisplayName: "Vendor Editor", InstallLocation: `"C:\Program Files\Vendor\Editor\"`, DisplayVersion: "2.1"},
+		{DisplayName: "No Location"},
+	}
+
+	if e := matchUninstall(entries, `C:\Program Files\Vendor\Editor\editor.exe`); e == nil || e.DisplayName != "Vendor Editor" {
+		t.Errorf("expected the deepest install location to win, got %+v", e)
+	}
+	if e := matchUninstall(entries, `c:\program files\vendor\tool.exe`); e == nil || e.DisplayName != "Vendor Suite" {
+		t.Errorf("expected a case-insensitive match, got %+v", e)
+	}
+	if e := matchUninstall(entries, `C:\Program Files\VendorX\x.exe`); e != nil {
+		t.Errorf("expected no match for a sibling directory, got %+v", e)
+	}
+}
+
+func TestApplyUninstall(t *testing.T) {
+	app := discover.DiscoveredApp{Name: "Editor"}
+	applyUninstall(&app, &uninstallEntry{DisplayVersion: "2.1", Publisher: "Vendor", InstallDate: "20240301", EstimatedSizeKB: 2048})
+	if app.Version != "2.1" || app.Publisher != "Vendor" || app.InstallDate != "2024-03-01" || app.SizeBytes != 2048*1024 {
+		t.Errorf("unexpected metadata: %+v", app)
+	}
+
+	if got := formatInstallDate("3/1/2024"); got != "" {
+		t.Errorf("expected a non-YYYYMMDD date dropped, got %q", got)
+	}
+}
+
 // --- Program Files Filter Tests ---
 
 func TestIsFilteredExecutable(t *testing.T) {
@@ -253,10 +292,13 @@ func TestFindMainExecutable(t *testing.T) {
 	os.WriteFile(filepath.Join(subDir, "nested.exe"), []byte{}, 0644)
 
 	// Returns single best exe (first non-filtered alphabetically as fallback)
-	exe := findMainExecutable(tmpDir, "testdir")
+	exe, confidence := findMainExecutable(tmpDir, "testdir")
 	if exe == "" {
 		t.Fatal("expected an executable to be found")
 	}
+	if confidence >= discover.LowConfidence {
+		t.Errorf("expected a low score for a guess among several exes, got %d", confidence)
+	}
 	base := filepath.Base(exe)
 	// mainprogram.exe comes before myapp.exe alphabetically
 	if base != "mainprogram.exe" {
@@ -270,18 +312,45 @@ func TestFindMainExecutablePrefersDirNameMatch(t *testing.T) {
 	os.WriteFile(filepath.Join(tmpDir, "other.exe"), []byte{}, 0644)
 	os.WriteFile(filepath.Join(tmpDir, "myapp.exe"), []byte{}, 0644)
 
-	exe := findMainExecutable(tmpDir, "MyApp")
+	exe, confidence := findMainExecutable(tmpDir, "MyApp")
 	if exe == "" {
 		t.Fatal("expected an executable to be found")
 	}
+	if confidence < 90 {
+		t.Errorf("expected a high score for a dir name match, got %d", confidence)
+	}
 	if filepath.Base(exe) != "myapp.exe" {
 		t.Errorf("expected myapp.exe (matches dir name), got %s", filepath.Base(exe))
 	}
 }
 
+func TestFindMainExecutableConfidence(t *testing.T) {
+	tests := []struct {
+		dirName string
+		files   []string
+		want    string
+		score   int
+	}{
+		{"VideoLAN VLC", []string{"a.exe", "vlc.exe"}, "vlc.exe", 70},
+		{"Mozilla Firefox", []string{"crashhandler.exe", "firefox.exe"}, "firefox.exe", 70},
+		{"Some Tool", []string{"st.exe"}, "st.exe", 70},
+		{"Acme", []string{"launcher.exe"}, "launcher.exe", 60},
+	}
+	for _, tt := range tests {
+		dir := t.TempDir()
+		for _, f := range tt.files {
+			os.WriteFile(filepath.Join(dir, f), []byte{}, 0644)
+		}
+		exe, score := findMainExecutable(dir, tt.dirName)
+		if filepath.Base(exe) != tt.want || score != tt.score {
+			t.Errorf("%s: got %s (%d), want %s (%d)", tt.dirName, filepath.Base(exe), score, tt.want, tt.score)
+		}
+	}
+}
+
 func TestFindMainExecutableEmpty(t *testing.T) {
 	tmpDir := t.TempDir()
-	exe := findMainExecutable(tmpDir, "empty")
+	exe, _ := findMainExecutable(tmpDir, "empty")
 	if exe != "" {
 		t.Errorf("expected empty result for empty dir, got %s", exe)
 	}
@@ -294,6 +363,73 @@ func TestCleanAppName(t *testing.T) {
 	}
 }
 
+// startMenuShortcuts returns up to max .lnk files from the Start Menu.
+func startMenuShortcuts(max int) []string {
+	var lnks []string
+	for _, dir := range startMenuDirs() {
+		filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+			if err == nil && !info.IsDir() && strings.HasSuffix(strings.ToLower(path), ".lnk") && len(lnks) < max {
+				lnks = append(lnks, path)
+			}
+			return nil
+		})
+	}
+	return lnks
+}
+
+func TestShortcutResolverReuse(t *testing.T) {
+	lnks := startMenuShortcuts(20)
+	if len(lnks) == 0 {
+		t.Skip("no Start Menu shortcuts on this system")
+	}
+
+	r, err := newShortcutResolver()
+	if err != nil {
+		t.Fatalf("newShortcutResolver: %v", err)
+	}
+	defer r.Close()
+	for _, lnk := range lnks {
+		if got, want := r.Resolve(lnk), resolveShortcut(lnk); got != want {
+			t.Errorf("%s: reused resolver gave %q, one-shot gave %q", lnk, got, want)
+		}
+	}
+}
+
+// BenchmarkResolveShortcutPerCall initializes COM and creates the ShellLink
+// object for every shortcut, as Start Menu scans used to.
+func BenchmarkResolveShortcutPerCall(b *testing.B) {
+	lnks := startMenuShortcuts(50)
+	if len(lnks) == 0 {
+		b.Skip("no Start Menu shortcuts on this system")
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, lnk := range lnks {
+			resolveShortcut(lnk)
+		}
+	}
+}
+
+// BenchmarkResolveShortcutReused resolves the same shortcuts through one
+// resolver, as a Start Menu scan does now.
+func BenchmarkResolveShortcutReused(b *testing.B) {
+	lnks := startMenuShortcuts(50)
+	if len(lnks) == 0 {
+		b.Skip("no Start Menu shortcuts on this system")
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		r, err := newShortcutResolver()
+		if err != nil {
+			b.Fatalf("newShortcutResolver: %v", err)
+		}
+		for _, lnk := range lnks {
+			r.Resolve(lnk)
+		}
+		r.Close()
+	}
+}
+
 func TestStartMenuDirs(t *testing.T) {
 	dirs := startMenuDirs()
 	// Should have at least one directory on a Windows system
@@ -449,7 +585,7 @@ func TestParseAppxJSON(t *testing.T) {
 }
 
 func TestParseAppxJSONFields(t *testing.T) {
-	input := `{"Name":"Microsoft.MinecraftUWP","PackageFamilyName":"Microsoft.MinecraftUWP_8wekyb3d8bbwe","DisplayName":"Minecraft"}`
+	input := `{"Name":"Microsoft.MinecraftUWP","PackageFamilyName":"Microsoft.MinecraftUWP_8wekyb3d8bbwe","DisplayName":"Minecraft","Version":"1.21.2.0","Publisher":"Microsoft Studios"}`
 	pkgs, err := parseAppxJSON([]byte(input))
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
@@ -466,6 +602,9 @@ func TestParseAppxJSONFields(t *testing.T) {
 	if pkgs[0].DisplayName != "Minecraft" {
 		t.Errorf("expected DisplayName 'Minecraft', got %q", pkgs[0].DisplayName)
 	}
+	if pkgs[0].Version != "1.21.2.0" || pkgs[0].Publisher != "Microsoft Studios" {
+		t.Errorf("expected version and publisher, got %+v", pkgs[0])
+	}
 }
 
 func TestIsGamePackage(t *testing.T) {
@@ -514,12 +653,12 @@ func TestXboxDiscoverWithMockPowerShell(t *testing.T) {
 
 	// DisplayName provided for Starfield; empty for Minecraft (falls back to cleanPackageName)
 	// AppId varies per game (Game, Minecraft, etc.) — uses manifest values
-	runPowerShellCommand = func(script string) ([]byte, error) {
+	runPowerShellCommand = func(ctx context.Context, script string) ([]byte, error) {
 		return []byte(`[{"Name":"Bethesda.Starfield","PackageFamilyName":"Bethesda.Starfield_3275kfvn8vcwc","DisplayName":"Starfield","AppId":"Game"},{"Name":"Microsoft.MinecraftUWP","PackageFamilyName":"Microsoft.MinecraftUWP_8wekyb3d8bbwe","DisplayName":"","AppId":"Minecraft"},{"Name":"Microsoft.GamingServices","PackageFamilyName":"Microsoft.GamingServices_abc","DisplayName":"","AppId":"App"}]`), nil
 	}
 
 	s := &XboxSource{}
-	apps, err := s.Discover()
+	apps, err := s.Discover(context.Background())
 	if err != nil {
 		t.Fatalf("Discover failed: %v", err)
 	}
@@ -575,12 +714,12 @@ func TestXboxDiscoverPrefersDisplayName(t *testing.T) {
 	defer func() { runPowerShellCommand = origRunner }()
 
 	// Codenames that would produce bad cleaned names — DisplayName saves us
-	runPowerShellCommand = func(script string) ([]byte, error) {
+	runPowerShellCommand = func(ctx context.Context, script string) ([]byte, error) {
 		return []byte(`[{"Name":"Microsoft.Limitless","PackageFamilyName":"Microsoft.Limitless_8wekyb3d8bbwe","DisplayName":"Microsoft Flight Simulator 2024","AppId":"App"},{"Name":"SEGAofAmericaInc.D0cb6b3aet","PackageFamilyName":"SEGAofAmericaInc.D0cb6b3aet_s751p9cej88mt","DisplayName":"Persona 4 Golden","AppId":"Game"}]`), nil
 	}
 
 	s := &XboxSource{}
-	apps, err := s.Discover()
+	apps, err := s.Discover(context.Background())
 	if err != nil {
 		t.Fatalf("Discover failed: %v", err)
 	}
@@ -604,12 +743,12 @@ func TestXboxDiscoverEmptyResult(t *testing.T) {
 	origRunner := runPowerShellCommand
 	defer func() { runPowerShellCommand = origRunner }()
 
-	runPowerShellCommand = func(script string) ([]byte, error) {
+	runPowerShellCommand = func(ctx context.Context, script string) ([]byte, error) {
 		return []byte(`[]`), nil
 	}
 
 	s := &XboxSource{}
-	apps, err := s.Discover()
+	apps, err := s.Discover(context.Background())
 	if err != nil {
 		t.Fatalf("Discover failed: %v", err)
 	}
@@ -622,12 +761,12 @@ func TestXboxDiscoverPowerShellError(t *testing.T) {
 	origRunner := runPowerShellCommand
 	defer func() { runPowerShellCommand = origRunner }()
 
-	runPowerShellCommand = func(script string) ([]byte, error) {
+	runPowerShellCommand = func(ctx context.Context, script string) ([]byte, error) {
 		return nil, os.ErrNotExist
 	}
 
 	s := &XboxSource{}
-	apps, err := s.Discover()
+	apps, err := s.Discover(context.Background())
 	if err == nil {
 		t.Fatal("expected error when PowerShell fails")
 	}
@@ -641,12 +780,12 @@ func TestXboxDiscoverDeduplicates(t *testing.T) {
 	defer func() { runPowerShellCommand = origRunner }()
 
 	// Same game name appearing twice (different package versions)
-	runPowerShellCommand = func(script string) ([]byte, error) {
+	runPowerShellCommand = func(ctx context.Context, script string) ([]byte, error) {
 		return []byte(`[{"Name":"Publisher.MyGame","PackageFamilyName":"Publisher.MyGame_abc","DisplayName":"My Game","AppId":"Game"},{"Name":"Publisher.MyGame","PackageFamilyName":"Publisher.MyGame_def","DisplayName":"My Game","AppId":"Game"}]`), nil
 	}
 
 	s := &XboxSource{}
-	apps, err := s.Discover()
+	apps, err := s.Discover(context.Background())
 	if err != nil {
 		t.Fatalf("Discover failed: %v", err)
 	}
@@ -702,7 +841,7 @@ func TestCustomDirSource_Discover_RootLevelKeepsAll(t *testing.T) {
 	}
 
 	s := &CustomDirSource{Dir: dir, MenuName: "Utilities"}
-	apps, err := s.Discover()
+	apps, err := s.Discover(context.Background())
 	if err != nil {
 		t.Fatalf("Discover failed: %v", err)
 	}
@@ -735,7 +874,7 @@ func TestCustomDirSource_Discover_SubdirDeduplication(t *testing.T) {
 	}
 
 	s := &CustomDirSource{Dir: dir, MenuName: "Utilities"}
-	apps, err := s.Discover()
+	apps, err := s.Discover(context.Background())
 	if err != nil {
 		t.Fatalf("Discover failed: %v", err)
 	}
@@ -773,7 +912,7 @@ func TestCustomDirSource_Discover_MixedRootAndSubdir(t *testing.T) {
 	}
 
 	s := &CustomDirSource{Dir: dir, MenuName: "Tools"}
-	apps, err := s.Discover()
+	apps, err := s.Discover(context.Background())
 	if err != nil {
 		t.Fatalf("Discover failed: %v", err)
 	}
@@ -804,7 +943,7 @@ func TestCustomDirSource_Discover_SpaceInPath(t *testing.T) {
 	}
 
 	s := &CustomDirSource{Dir: dir, MenuName: "Tools"}
-	apps, err := s.Discover()
+	apps, err := s.Discover(context.Background())
 	if err != nil {
 		t.Fatalf("Discover failed: %v", err)
 	}
@@ -825,7 +964,7 @@ func TestCustomDirSource_Exclude(t *testing.T) {
 	}
 
 	s := &CustomDirSource{Dir: dir, MenuName: "Utilities", Exclude: []string{"rufus*"}}
-	apps, err := s.Discover()
+	apps, err := s.Discover(context.Background())
 	if err != nil {
 		t.Fatalf("Discover failed: %v", err)
 	}
@@ -891,7 +1030,7 @@ func TestCustomDirSource_Discover_ArchDirCollapse(t *testing.T) {
 	}
 
 	s := &CustomDirSource{Dir: dir, MenuName: "Utilities"}
-	apps, err := s.Discover()
+	apps, err := s.Discover(context.Background())
 	if err != nil {
 		t.Fatalf("Discover failed: %v", err)
 	}
@@ -942,7 +1081,7 @@ func TestCustomDirSource_Discover_ArchDirMixed(t *testing.T) {
 	}
 
 	s := &CustomDirSource{Dir: dir, MenuName: "Utilities"}
-	apps, err := s.Discover()
+	apps, err := s.Discover(context.Background())
 	if err != nil {
 		t.Fatalf("Discover failed: %v", err)
 	}
@@ -1029,3 +1168,40 @@ func appNames(apps []discover.DiscoveredApp) []string {
 	}
 	return names
 }
+
+func TestXboxDiscoverTimesOut(t *testing.T) {
+	origRunner := runPowerShellCommand
+	defer func() { runPowerShellCommand = origRunner }()
+
+	runPowerShellCommand = func(ctx context.Context, script string) ([]byte, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+
+	s := &XboxSource{}
+	s.Configure(&discover.DiscoverConfig{Xbox: discover.XboxOptions{PowerShellTimeout: 10 * time.Millisecond}})
+	_, err := s.Discover(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "timed out after 10ms") {
+		t.Fatalf("expected timeout error, got %v", err)
+	}
+}
+
+func TestXboxDefaultTimeout(t *testing.T) {
+	s := &XboxSource{}
+	if got := s.timeout(); got != discover.DefaultPowerShellTimeout {
+		t.Errorf("timeout() = %v, expected %v", got, discover.DefaultPowerShellTimeout)
+	}
+}
+
+func TestWithStderr(t *testing.T) {
+	err := withStderr(&exec.ExitError{Stderr: []byte("Get-AppxPackage : Access is denied.\r\n")})
+	if !strings.HasSuffix(err.Error(), ": Get-AppxPackage : Access is denied.") {
+		t.Errorf("stderr not in error: %q", err.Error())
+	}
+	if err := withStderr(os.ErrNotExist); err != os.ErrNotExist {
+		t.Errorf("non-exit errors should pass through, got %v", err)
+	}
+	if err := withStderr(nil); err != nil {
+		t.Errorf("withStderr(nil) = %v", err)
+	}
+}
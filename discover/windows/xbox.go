@@ -3,35 +3,70 @@
 package windows
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os/exec"
 	"strings"
+	"time"
 	"unicode"
 
 	"github.com/benworks/menuworks/discover"
+	"github.com/benworks/menuworks/shell"
 )
 
 // XboxSource discovers games installed via the Xbox app / Microsoft Store.
 // It uses PowerShell's Get-AppxPackage to enumerate packages and filters
 // to games using the GamingServices package repository.
-type XboxSource struct{}
+type XboxSource struct {
+	Options discover.XboxOptions
+}
 
 func (s *XboxSource) Name() string     { return "xbox" }
 func (s *XboxSource) Category() string { return "Games" }
 
+func (s *XboxSource) CategoryIcon() string { return "🎮" }
+
+// Configure takes the xbox: options of the discover block.
+func (s *XboxSource) Configure(cfg *discover.DiscoverConfig) {
+	s.Options = cfg.Xbox
+}
+
+// timeout returns the per-invocation PowerShell timeout.
+func (s *XboxSource) timeout() time.Duration {
+	if s.Options.PowerShellTimeout > 0 {
+		return s.Options.PowerShellTimeout
+	}
+	return discover.DefaultPowerShellTimeout
+}
+
 // Available checks whether PowerShell and Get-AppxPackage are present.
 // Returns false gracefully if PowerShell is not installed or the cmdlet
 // is unavailable, allowing discovery to continue with other sources.
 func (s *XboxSource) Available() bool {
-	return isPowerShellAvailable()
+	return isPowerShellAvailable(s.timeout())
+}
+
+// Diagnose reports which PowerShell was found and the result of probing for
+// Get-AppxPackage.
+func (s *XboxSource) Diagnose() []discover.Check {
+	return powerShellChecks(s.timeout())
 }
 
 // Discover enumerates Xbox/Microsoft Store games via PowerShell.
 // Returns nil, error if PowerShell invocation fails (non-fatal in the pipeline).
-func (s *XboxSource) Discover() ([]discover.DiscoveredApp, error) {
-	data, err := runPowerShellCommand(xboxDiscoveryScript)
+func (s *XboxSource) Discover(ctx context.Context) ([]discover.DiscoveredApp, error) {
+	psCtx, cancel := context.WithTimeout(ctx, s.timeout())
+	defer cancel()
+	data, err := runPowerShellCommand(psCtx, xboxDiscoveryScript)
 	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		if psCtx.Err() != nil {
+			return nil, fmt.Errorf("xbox: powershell timed out after %s (raise discover: xbox: powershell_timeout)", s.timeout())
+		}
 		return nil, fmt.Errorf("xbox: powershell command failed: %w", err)
 	}
 
@@ -62,12 +97,15 @@ func (s *XboxSource) Discover() ([]discover.DiscoveredApp, error) {
 		if appID == "" {
 			appID = "App"
 		}
-		aumid := buildAUMID(pkg.PackageFamilyName, appID)
+		argv := []string{"explorer.exe", `shell:AppsFolder\` + buildAUMID(pkg.PackageFamilyName, appID)}
 		apps = append(apps, discover.DiscoveredApp{
-			Name:     name,
-			Exec:     fmt.Sprintf("explorer.exe shell:AppsFolder\\%s", aumid),
-			Source:   "xbox",
-			Category: "Games",
+			Name:      name,
+			Exec:      shell.Quote("windows", argv),
+			Argv:      argv,
+			Source:    "xbox",
+			Category:  "Games",
+			Version:   pkg.Version,
+			Publisher: pkg.Publisher,
 		})
 	}
 
@@ -90,6 +128,7 @@ if ($gameNames.Count -eq 0) { '[]'; exit 0 }
 $results = @()
 Get-AppxPackage | Where-Object { -not $_.IsFramework -and $gameNames.ContainsKey($_.Name) } | ForEach-Object {
     $dn = ''
+    $pub = ''
     $aid = 'App'
     $mp = Join-Path $_.InstallLocation 'AppxManifest.xml'
     if (Test-Path $mp) {
@@ -97,11 +136,13 @@ Get-AppxPackage | Where-Object { -not $_.IsFramework -and $gameNames.ContainsKey
             [xml]$m = Get-Content $mp
             $d = $m.Package.Properties.DisplayName
             if ($d -and $d -notmatch '^ms-resource:') { $dn = $d }
+            $p = $m.Package.Properties.PublisherDisplayName
+            if ($p -and $p -notmatch '^ms-resource:') { $pub = $p }
             $a = $m.Package.Applications.Application
             if ($a -is [array]) { $aid = $a[0].Id } elseif ($a) { $aid = $a.Id }
         } catch {}
     }
-    $results += [PSCustomObject]@{ Name = $_.Name; PackageFamilyName = $_.PackageFamilyName; DisplayName = $dn; AppId = $aid }
+    $results += [PSCustomObject]@{ Name = $_.Name; PackageFamilyName = $_.PackageFamilyName; DisplayName = $dn; AppId = $aid; Version = [string]$_.Version; Publisher = $pub }
 }
 if ($results.Count -eq 0) { '[]' } else { $results | ConvertTo-Json -Compress }`
 
@@ -111,32 +152,93 @@ type appxPackage struct {
 	PackageFamilyName string `json:"PackageFamilyName"`
 	DisplayName       string `json:"DisplayName"`
 	AppID             string `json:"AppId"`
+	Version           string `json:"Version"`
+	Publisher         string `json:"Publisher"`
 }
 
-// isPowerShellAvailable checks if powershell.exe can be found and the
+// powerShellNames are the PowerShell executables tried in order: Windows
+// PowerShell, then PowerShell 7+ for systems without it.
+var powerShellNames = []string{"powershell.exe", "pwsh.exe"}
+
+// findPowerShell returns the first of powerShellNames on PATH.
+func findPowerShell() (string, error) {
+	for _, name := range powerShellNames {
+		if path, err := exec.LookPath(name); err == nil {
+			return path, nil
+		}
+	}
+	return "", fmt.Errorf("none of %s found on PATH", strings.Join(powerShellNames, ", "))
+}
+
+// isPowerShellAvailable checks if PowerShell can be found and the
 // Get-AppxPackage cmdlet exists.
-func isPowerShellAvailable() bool {
-	path, err := exec.LookPath("powershell.exe")
-	if err != nil || path == "" {
-		return false
+func isPowerShellAvailable(timeout time.Duration) bool {
+	for _, c := range powerShellChecks(timeout) {
+		if !c.OK {
+			return false
+		}
 	}
-	// Verify Get-AppxPackage cmdlet is available
-	cmd := exec.Command("powershell.exe", "-NoProfile", "-NonInteractive", "-Command",
-		"if (Get-Command Get-AppxPackage -ErrorAction SilentlyContinue) { 'ok' } else { exit 1 }")
-	out, err := cmd.Output()
-	if err != nil {
-		return false
+	return true
+}
+
+// powerShellChecks looks for each of powerShellNames until one is found and
+// then verifies the Get-AppxPackage cmdlet is available through it. It stops
+// at the first failure.
+func powerShellChecks(timeout time.Duration) []discover.Check {
+	var checks []discover.Check
+	for _, name := range powerShellNames {
+		lookup := discover.LookPathCheck(name)
+		checks = append(checks, lookup)
+		if lookup.OK {
+			break
+		}
+	}
+	if !checks[len(checks)-1].OK {
+		return checks
 	}
-	return strings.TrimSpace(string(out)) == "ok"
+	probe := discover.Check{What: "powershell probe Get-Command Get-AppxPackage"}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	out, err := runPowerShellCommand(ctx, "if (Get-Command Get-AppxPackage -ErrorAction SilentlyContinue) { 'ok' } else { exit 1 }")
+	switch {
+	case ctx.Err() != nil:
+		probe.Detail = fmt.Sprintf("timed out after %s", timeout)
+	case err != nil:
+		probe.Detail = "cmdlet not available: " + err.Error()
+	case strings.TrimSpace(string(out)) != "ok":
+		probe.Detail = fmt.Sprintf("unexpected output %q", strings.TrimSpace(string(out)))
+	default:
+		probe.OK, probe.Detail = true, "cmdlet available"
+	}
+	return append(checks, probe)
 }
 
 // runPowerShellCommand executes a PowerShell script and returns stdout bytes.
 // This is a package-level var so tests can override it.
 var runPowerShellCommand = runPowerShellCommandImpl
 
-func runPowerShellCommandImpl(script string) ([]byte, error) {
-	cmd := exec.Command("powershell.exe", "-NoProfile", "-NonInteractive", "-Command", script)
-	return cmd.Output()
+func runPowerShellCommandImpl(ctx context.Context, script string) ([]byte, error) {
+	ps, err := findPowerShell()
+	if err != nil {
+		return nil, err
+	}
+	cmd := exec.CommandContext(ctx, ps, "-NoProfile", "-NonInteractive", "-Command", script)
+	out, err := cmd.Output()
+	return out, withStderr(err)
+}
+
+// withStderr appends what a failed PowerShell run wrote to stderr, which is
+// where script errors go, to its exit error.
+func withStderr(err error) error {
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) {
+		return err
+	}
+	stderr := strings.TrimSpace(string(exitErr.Stderr))
+	if stderr == "" {
+		return err
+	}
+	return fmt.Errorf("%w: %s", err, stderr)
 }
 
 // parseAppxJSON parses the JSON output from Get-AppxPackage.
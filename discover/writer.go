@@ -4,7 +4,8 @@ import (
 	"fmt"
 	"io"
 	"os"
-	"sort"
+	"regexp"
+	"strconv"
 	"strings"
 	"unicode"
 
@@ -38,27 +39,49 @@ type yamlConfig struct {
 }
 
 type yamlTheme struct {
-	Background  string `yaml:"background"`
-	Text        string `yaml:"text"`
-	Border      string `yaml:"border"`
-	HighlightBg string `yaml:"highlight_bg"`
-	HighlightFg string `yaml:"highlight_fg"`
-	Hotkey      string `yaml:"hotkey"`
-	Shadow      string `yaml:"shadow"`
-	Disabled    string `yaml:"disabled"`
+	Background  string `yaml:"background,omitempty"`
+	Text        string `yaml:"text,omitempty"`
+	Border      string `yaml:"border,omitempty"`
+	HighlightBg string `yaml:"highlight_bg,omitempty"`
+	HighlightFg string `yaml:"highlight_fg,omitempty"`
+	Hotkey      string `yaml:"hotkey,omitempty"`
+	Shadow      string `yaml:"shadow,omitempty"`
+	Disabled    string `yaml:"disabled,omitempty"`
+	MenuBg      string `yaml:"menu_bg,omitempty"`
+	Success     string `yaml:"success,omitempty"`
+	Failure     string `yaml:"failure,omitempty"`
+	Extends     string `yaml:"extends,omitempty"`
+
+	TitleBold       string `yaml:"title_bold,omitempty"`
+	SelectedReverse string `yaml:"selected_reverse,omitempty"`
+	HotkeyUnderline string `yaml:"hotkey_underline,omitempty"`
+	SeparatorDim    string `yaml:"separator_dim,omitempty"`
+
+	Icons map[string]string `yaml:"icons,omitempty"`
 }
 
 type yamlItem struct {
-	Type   string    `yaml:"type"`
-	Label  string    `yaml:"label,omitempty"`
-	Target string    `yaml:"target,omitempty"`
-	Exec   *yamlExec `yaml:"exec,omitempty"`
+	Type       string    `yaml:"type"`
+	Label      string    `yaml:"label,omitempty"`
+	Icon       string    `yaml:"icon,omitempty"`
+	Target     string    `yaml:"target,omitempty"`
+	Exec       *yamlExec `yaml:"exec,omitempty"`
+	ShowOutput *bool     `yaml:"showOutput,omitempty"`
+	Help       string    `yaml:"help,omitempty"`
 }
 
+// yamlExec holds each OS variant as a command line (string) or, for apps
+// discovered with Argv, as a yamlProgram that runs without a shell
 type yamlExec struct {
-	Windows string `yaml:"windows,omitempty"`
-	Linux   string `yaml:"linux,omitempty"`
-	Mac     string `yaml:"mac,omitempty"`
+	Windows interface{} `yaml:"windows,omitempty"`
+	Linux   interface{} `yaml:"linux,omitempty"`
+	Mac     interface{} `yaml:"mac,omitempty"`
+	WorkDir string      `yaml:"workdir,omitempty"`
+}
+
+type yamlProgram struct {
+	Program string   `yaml:"program"`
+	Args    []string `yaml:"args,omitempty"`
 }
 
 type yamlMenu struct {
@@ -68,12 +91,17 @@ type yamlMenu struct {
 
 // WriteConfig generates a MenuWorks config.yaml from discovered apps and writes it to the given path.
 func WriteConfig(apps []DiscoveredApp, outputPath string) error {
+	return WriteConfigOrdered(apps, Ordering{}, outputPath)
+}
+
+// WriteConfigOrdered is WriteConfig with the menus laid out per order.
+func WriteConfigOrdered(apps []DiscoveredApp, order Ordering, outputPath string) error {
 	f, err := os.Create(outputPath)
 	if err != nil {
 		return fmt.Errorf("failed to create output file: %w", err)
 	}
 	defer f.Close()
-	return RenderConfig(apps, f)
+	return RenderConfigOrdered(apps, order, f)
 }
 
 // WriteConfigStdout generates a MenuWorks config.yaml and writes it to stdout.
@@ -84,9 +112,14 @@ func WriteConfigStdout(apps []DiscoveredApp) error {
 // RenderConfig generates the config YAML from apps and writes to w.
 // Uses yaml.Marshal to ensure correct escaping of all values.
 func RenderConfig(apps []DiscoveredApp, w io.Writer) error {
-	cfg := buildYAMLConfig(apps)
+	return RenderConfigOrdered(apps, Ordering{}, w)
+}
+
+// RenderConfigOrdered is RenderConfig with the menus laid out per order.
+func RenderConfigOrdered(apps []DiscoveredApp, order Ordering, w io.Writer) error {
+	cfg := buildYAMLConfig(apps, order)
 
-	data, err := yaml.Marshal(cfg)
+	data, err := marshalYAML(cfg)
 	if err != nil {
 		return fmt.Errorf("failed to marshal config: %w", err)
 	}
@@ -95,8 +128,47 @@ func RenderConfig(apps []DiscoveredApp, w io.Writer) error {
 	return err
 }
 
+// quotedLine matches a line whose value is a single double-quoted scalar.
+var quotedLine = regexp.MustCompile(`(?m)^([ -]*(?:[\w.-]+: )?)"((?:[^"\\\n]|\\.)*)"$`)
+
+// marshalYAML is yaml.Marshal, except that characters outside the Basic
+// Multilingual Plane, which yaml.v3 escapes, are written as themselves, so
+// an icon reads as 🎮 rather than "\U0001F3AE".
+func marshalYAML(v interface{}) ([]byte, error) {
+	data, err := yaml.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return quotedLine.ReplaceAllFunc(data, func(line []byte) []byte {
+		m := quotedLine.FindSubmatch(line)
+		return []byte(string(m[1]) + `"` + unescapeWide(string(m[2])) + `"`)
+	}), nil
+}
+
+// unescapeWide replaces the \U escapes of printable characters in the body of
+// a double-quoted YAML scalar with the characters, leaving other escapes be.
+func unescapeWide(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i+1 >= len(s) {
+			b.WriteByte(s[i])
+			continue
+		}
+		if s[i+1] == 'U' && i+10 <= len(s) {
+			if n, err := strconv.ParseUint(s[i+2:i+10], 16, 32); err == nil && unicode.IsPrint(rune(n)) {
+				b.WriteRune(rune(n))
+				i += 9
+				continue
+			}
+		}
+		b.WriteString(s[i : i+2]) // keep the escape
+		i++
+	}
+	return b.String()
+}
+
 // buildYAMLConfig transforms discovered apps into a marshallable config struct.
-func buildYAMLConfig(apps []DiscoveredApp) yamlConfig {
+func buildYAMLConfig(apps []DiscoveredApp, order Ordering) yamlConfig {
 	groups := GroupByCategory(apps)
 
 	// Sort category names for deterministic output
@@ -104,7 +176,7 @@ func buildYAMLConfig(apps []DiscoveredApp) yamlConfig {
 	for name := range groups {
 		catNames = append(catNames, name)
 	}
-	sort.Strings(catNames)
+	order.sortCategories(catNames)
 
 	osKey := writerOS
 
@@ -114,23 +186,24 @@ func buildYAMLConfig(apps []DiscoveredApp) yamlConfig {
 		rootItems = append(rootItems, yamlItem{
 			Type:   "submenu",
 			Label:  name,
+			Icon:   categoryIcon(groups[name]),
 			Target: sanitizeID(name),
 		})
 	}
 	rootItems = append(rootItems, yamlItem{Type: "separator"})
-	rootItems = append(rootItems, yamlItem{Type: "back", Label: "Quit"})
+	rootItems = append(rootItems, yamlItem{Type: "quit", Label: "Quit"})
 
 	// Build menus as an ordered yaml.Node to preserve category order
 	menusNode := yaml.Node{Kind: yaml.MappingNode}
 	for _, name := range catNames {
-		catApps := groups[name]
+		catApps := order.sortItems(groups[name])
 
 		// Check if this category has apps from multiple sources
 		sourceGroups := GroupBySource(catApps)
 
 		if len(sourceGroups) > 1 {
 			// Multiple sources: create sub-menus per source
-			buildMultiSourceMenus(name, sourceGroups, osKey, &menusNode)
+			buildMultiSourceMenus(name, sourceGroups, order, osKey, &menusNode)
 		} else {
 			// Single source (or no source): flat list of commands
 			buildFlatMenu(name, catApps, osKey, &menusNode)
@@ -159,16 +232,7 @@ func buildYAMLConfig(apps []DiscoveredApp) yamlConfig {
 
 // buildFlatMenu adds a single category menu with command items directly listed.
 func buildFlatMenu(category string, apps []DiscoveredApp, osKey string, menusNode *yaml.Node) {
-	var menuItems []yamlItem
-	for _, a := range apps {
-		item := yamlItem{
-			Type:  "command",
-			Label: a.Name,
-			Exec:  &yamlExec{},
-		}
-		setExecOS(item.Exec, osKey, a.Exec)
-		menuItems = append(menuItems, item)
-	}
+	menuItems := commandItems(apps, osKey)
 	if len(menuItems) > 0 {
 		menuItems = append(menuItems, yamlItem{Type: "separator"})
 	}
@@ -183,6 +247,7 @@ func buildFlatMenu(category string, apps []DiscoveredApp, osKey string, menusNod
 	if err := menuNode.Encode(menu); err != nil {
 		return
 	}
+	markLowConfidence(&menuNode, apps)
 	keyNode := yaml.Node{Kind: yaml.ScalarNode, Value: sanitizeID(category)}
 	menusNode.Content = append(menusNode.Content, &keyNode, &menuNode)
 }
@@ -194,13 +259,13 @@ func buildFlatMenu(category string, apps []DiscoveredApp, osKey string, menusNod
 //	games:       submenu -> games_steam, submenu -> games_xbox, Back
 //	games_steam: command items..., Back
 //	games_xbox:  command items..., Back
-func buildMultiSourceMenus(category string, sourceGroups map[string][]DiscoveredApp, osKey string, menusNode *yaml.Node) {
+func buildMultiSourceMenus(category string, sourceGroups map[string][]DiscoveredApp, order Ordering, osKey string, menusNode *yaml.Node) {
 	// Sort source names for deterministic output
 	var sourceNames []string
 	for src := range sourceGroups {
 		sourceNames = append(sourceNames, src)
 	}
-	sort.Strings(sourceNames)
+	order.sortSources(sourceNames)
 
 	// Build the parent category menu with submenu entries per source
 	var catItems []yamlItem
@@ -209,6 +274,7 @@ func buildMultiSourceMenus(category string, sourceGroups map[string][]Discovered
 		catItems = append(catItems, yamlItem{
 			Type:   "submenu",
 			Label:  titleCase(src),
+			Icon:   categoryIcon(sourceGroups[src]),
 			Target: subID,
 		})
 	}
@@ -234,16 +300,7 @@ func buildMultiSourceMenus(category string, sourceGroups map[string][]Discovered
 		apps := sourceGroups[src]
 		subID := sanitizeID(category + "_" + src)
 
-		var subItems []yamlItem
-		for _, a := range apps {
-			item := yamlItem{
-				Type:  "command",
-				Label: a.Name,
-				Exec:  &yamlExec{},
-			}
-			setExecOS(item.Exec, osKey, a.Exec)
-			subItems = append(subItems, item)
-		}
+		subItems := commandItems(apps, osKey)
 		if len(subItems) > 0 {
 			subItems = append(subItems, yamlItem{Type: "separator"})
 		}
@@ -258,13 +315,66 @@ func buildMultiSourceMenus(category string, sourceGroups map[string][]Discovered
 		if err := subNode.Encode(subMenu); err != nil {
 			continue
 		}
+		markLowConfidence(&subNode, apps)
 		subKey := yaml.Node{Kind: yaml.ScalarNode, Value: subID}
 		menusNode.Content = append(menusNode.Content, &subKey, &subNode)
 	}
 }
 
-// setExecOS sets the appropriate OS field on a yamlExec struct.
-func setExecOS(e *yamlExec, osKey, cmd string) {
+// categoryIcon returns the first CategoryIcon among apps, or "".
+func categoryIcon(apps []DiscoveredApp) string {
+	for _, a := range apps {
+		if a.CategoryIcon != "" {
+			return a.CategoryIcon
+		}
+	}
+	return ""
+}
+
+// commandItems builds a command item per app, in order.
+func commandItems(apps []DiscoveredApp, osKey string) []yamlItem {
+	var items []yamlItem
+	for _, a := range apps {
+		item := yamlItem{
+			Type:       "command",
+			Label:      a.Name,
+			Exec:       &yamlExec{WorkDir: a.WorkDir},
+			ShowOutput: a.ShowOutput,
+			Help:       a.Description,
+		}
+		setExecOS(item.Exec, osKey, a)
+		items = append(items, item)
+	}
+	return items
+}
+
+// markLowConfidence adds a comment above each item of the encoded menu whose
+// app scored below LowConfidence. The menu's first items must be the apps'
+// command items, in order.
+func markLowConfidence(menuNode *yaml.Node, apps []DiscoveredApp) {
+	var items *yaml.Node
+	for i := 0; i+1 < len(menuNode.Content); i += 2 {
+		if menuNode.Content[i].Value == "items" {
+			items = menuNode.Content[i+1]
+		}
+	}
+	if items == nil {
+		return
+	}
+	for i, a := range apps {
+		if i < len(items.Content) && a.Score() < LowConfidence {
+			items.Content[i].HeadComment = fmt.Sprintf("Low confidence (%d%%): check this is the right program", a.Score())
+		}
+	}
+}
+
+// setExecOS sets the appropriate OS field on a yamlExec struct to run app:
+// its program and arguments when it has them, otherwise its command line.
+func setExecOS(e *yamlExec, osKey string, app DiscoveredApp) {
+	var cmd interface{} = app.Exec
+	if len(app.Argv) > 0 {
+		cmd = yamlProgram{Program: app.Argv[0], Args: app.Argv[1:]}
+	}
 	switch osKey {
 	case "windows":
 		e.Windows = cmd
@@ -0,0 +1,240 @@
+package menuworks
+
+import (
+	"os"
+	"os/user"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+
+	"github.com/benworks/menuworks/config"
+	"github.com/benworks/menuworks/i18n"
+	"github.com/benworks/menuworks/menu"
+	"github.com/benworks/menuworks/ui"
+)
+
+// menuKeyHints returns the key bindings that apply to the current menu and
+// selection, with reloadKey as the configured reload key. canExit is false in
+// wrapper mode, where Esc doesn't exit the root menu.
+func menuKeyHints(navigator *menu.Navigator, reloadKey string, canExit bool) []ui.KeyHint {
+	hints := []ui.KeyHint{
+		{Key: "↑↓", Action: i18n.T("Navigate")},
+		{Key: "ENTER", Action: i18n.T("Select"), Press: pressKey(tcell.KeyEnter, 0)},
+	}
+	if navigator.IsAtRoot() {
+		if canExit {
+			hints = append(hints, ui.KeyHint{Key: "ESC", Action: i18n.T("Exit"), Press: pressKey(tcell.KeyEscape, 0)})
+		}
+	} else {
+		hints = append(hints, ui.KeyHint{Key: "ESC", Action: i18n.T("Back"), Press: pressKey(tcell.KeyEscape, 0)})
+	}
+	hints = append(hints, ui.KeyHint{Key: strings.ToUpper(reloadKey), Action: i18n.T("Reload"), Press: bindingKey(reloadKey)})
+	// F2 only does something for commands
+	if item, err := navigator.GetSelectedItem(); err == nil && item.Type == "command" {
+		hints = append(hints, ui.KeyHint{Key: "F2", Action: i18n.T("Help"), Press: pressKey(tcell.KeyF2, 0)})
+	}
+	return hints
+}
+
+// pressKey returns the key event a click on a footer hint stands for
+func pressKey(key tcell.Key, r rune) *tcell.EventKey {
+	return tcell.NewEventKey(key, r, tcell.ModNone)
+}
+
+// bindingKey returns the key event of a key binding, as config.ParseKey
+// writes it
+func bindingKey(key string) *tcell.EventKey {
+	if letter, ok := strings.CutPrefix(key, "Ctrl+"); ok {
+		return pressKey(tcell.KeyCtrlA+tcell.Key(letter[0]-'A'), 0)
+	}
+	if n, err := strconv.Atoi(strings.TrimPrefix(key, "F")); err == nil && len(key) > 1 {
+		return pressKey(tcell.KeyF1+tcell.Key(n-1), 0)
+	}
+	return pressKey(tcell.KeyRune, []rune(key)[0])
+}
+
+// isBindingKey reports whether e presses the key binding key. Letters match
+// either case.
+func isBindingKey(e *tcell.EventKey, key string) bool {
+	want := bindingKey(key)
+	if want.Key() == tcell.KeyRune {
+		return e.Key() == tcell.KeyRune && strings.ToUpper(string(e.Rune())) == key
+	}
+	return e.Key() == want.Key()
+}
+
+// applyLayoutFromConfig sets the menu box size and alignment from the config's layout settings
+func applyLayoutFromConfig(screen *ui.Screen, cfg *config.Config) {
+	layout := ui.DefaultLayout()
+	if c := cfg.Layout; c != nil {
+		if size, full, ok := config.ParseLayoutSize(c.Width); ok {
+			layout.Width, layout.FullWidth = size, full
+		}
+		if size, full, ok := config.ParseLayoutSize(c.Height); ok {
+			layout.Height, layout.FullHeight = size, full
+		}
+		if c.Align != "" {
+			layout.Align = c.Align
+		}
+	}
+	screen.SetLayout(layout)
+}
+
+// applyTitleBarFromConfig sets the menu header from the config's title_bar
+// settings, and where the title is drawn big from big_title
+func applyTitleBarFromConfig(screen *ui.Screen, cfg *config.Config) {
+	tb := ui.DefaultTitleBar()
+	if c := cfg.TitleBar; c != nil {
+		if c.Text != "" {
+			tb.Text = c.Text
+		}
+		tb.HideDate = c.Date != nil && !*c.Date
+		tb.HideClock = c.Clock != nil && !*c.Clock
+		tb.DateLayout = c.DateFormat
+		tb.TimeLayout = c.TimeFormat
+		if tb.TimeLayout == "" && c.Clock24h {
+			tb.TimeLayout = "15:04"
+		}
+		tb.Identity = titleBarIdentity(c.Username, c.Hostname)
+	}
+	// A ticking clock makes screen readers re-read the header every minute
+	if cfg.IsAccessibilityEnabled() && (cfg.TitleBar == nil || cfg.TitleBar.Clock == nil) {
+		tb.HideClock = true
+	}
+	screen.SetTitleBar(tb)
+	screen.SetBigTitle(cfg.Title, cfg.BigTitleOnMenu(), cfg.BigTitleOnSplash())
+}
+
+// applyAccessibilityFromConfig sets the screen's accessibility drawing options
+func applyAccessibilityFromConfig(screen *ui.Screen, cfg *config.Config) {
+	screen.SetAccessibility(ui.Accessibility{
+		Plain:        !cfg.IsDecorationEnabled(),
+		ScreenReader: cfg.IsAccessibilityEnabled(),
+	})
+	screen.SetStatusSymbols(cfg.IsStatusSymbolsEnabled())
+}
+
+// applyDisabledNotesFromConfig sets the text shown after unavailable items
+func applyDisabledNotesFromConfig(screen *ui.Screen, cfg *config.Config) {
+	if !cfg.IsDisabledNotesEnabled() {
+		screen.SetDisabledNotes(nil)
+		return
+	}
+	notes := map[string]string{
+		menu.DisabledNoCommand:     i18n.T("(not available on this OS)"),
+		menu.DisabledMissingTarget: i18n.T("(menu not found)"),
+	}
+	c := cfg.DisabledNotes
+	if c.NoCommand != "" {
+		notes[menu.DisabledNoCommand] = c.NoCommand
+	}
+	if c.MissingTarget != "" {
+		notes[menu.DisabledMissingTarget] = c.MissingTarget
+	}
+	screen.SetDisabledNotes(notes)
+}
+
+// titleBarIdentity returns "user@host", "user", or "host" depending on what is requested
+func titleBarIdentity(showUser, showHost bool) string {
+	var name, host string
+	if showUser {
+		if u, err := user.Current(); err == nil {
+			name = u.Username
+			// Windows reports DOMAIN\user
+			if i := strings.LastIndex(name, "\\"); i >= 0 {
+				name = name[i+1:]
+			}
+		}
+	}
+	if showHost {
+		host, _ = os.Hostname()
+	}
+	switch {
+	case name != "" && host != "":
+		return name + "@" + host
+	case name != "":
+		return name
+	default:
+		return host
+	}
+}
+
+// applyLocaleFromConfig selects the UI language from the config's locale,
+// falling back to the environment (LANG) when cfg is nil or leaves it unset
+func applyLocaleFromConfig(cfg *config.Config) {
+	locale := ""
+	if cfg != nil {
+		locale = cfg.Locale
+	}
+	i18n.SetLocale(i18n.Detect(locale))
+}
+
+// applyThemeFromConfig loads and applies the theme from the config
+// If theme is not specified or invalid, uses default colors
+func applyThemeFromConfig(screen *ui.Screen, cfg *config.Config) {
+	// Validate theme first
+	warnings := config.ValidateTheme(cfg)
+
+	// No theme selected: empty colors give the defaults (also undoes a previously applied theme)
+	var uiTheme ui.ThemeColors
+
+	// Get theme colors
+	themeColors := config.GetThemeColors(cfg)
+	if cfg.IsHighContrastEnabled() {
+		// Bold titles and underlined hotkeys don't rely on color alone
+		highContrast, _ := config.BuiltinTheme("high-contrast")
+		highContrast.TitleBold, highContrast.HotkeyUnderline = "true", "true"
+		themeColors = &highContrast
+	}
+	if themeColors != nil {
+		// Convert config.ThemeColors to ui.ThemeColors
+		uiTheme = ui.ThemeColors{
+			Background:  themeColors.Background,
+			Text:        themeColors.Text,
+			Border:      themeColors.Border,
+			HighlightBg: themeColors.HighlightBg,
+			HighlightFg: themeColors.HighlightFg,
+			Hotkey:      themeColors.Hotkey,
+			Shadow:      themeColors.Shadow,
+			Disabled:    themeColors.Disabled,
+			MenuBg:      themeColors.MenuBg,
+			Success:     themeColors.Success,
+			Failure:     themeColors.Failure,
+		}
+		uiTheme.TitleBold, _ = config.ParseAttribute(themeColors.TitleBold)
+		uiTheme.SelectedReverse, _ = config.ParseAttribute(themeColors.SelectedReverse)
+		uiTheme.HotkeyUnderline, _ = config.ParseAttribute(themeColors.HotkeyUnderline)
+		uiTheme.SeparatorDim, _ = config.ParseAttribute(themeColors.SeparatorDim)
+		uiTheme.Icons = themeColors.Icons
+
+		// Log warnings if any (could be shown in footer or ignored)
+		if len(warnings) > 0 {
+			// For now, silently continue with defaults for invalid colors
+			// The color parser will use defaults for invalid names
+		}
+	}
+	uiTheme.NoShadow = !cfg.IsShadowEnabled()
+	uiTheme.Transparent = cfg.IsTransparentBackground()
+
+	// Apply theme with color parser (also refreshes the screen's default style)
+	screen.ApplyTheme(uiTheme, config.ParseColorName)
+}
+
+// bellFlashDuration is how long the visual bell highlights borders
+const bellFlashDuration = 150 * time.Millisecond
+
+// bell rings the audible or visual bell for event if the config enables it
+func (a *App) bell(event string) {
+	if a.cfg == nil {
+		return
+	}
+	switch a.cfg.BellStyle(event) {
+	case "audible":
+		a.screen.Beep()
+	case "visual":
+		a.screen.SetFlash(true)
+		a.d.AfterFunc(bellFlashDuration, func() { a.screen.SetFlash(false) })
+	}
+}
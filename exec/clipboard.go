@@ -0,0 +1,58 @@
+package exec
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"github.com/benworks/menuworks/logging"
+)
+
+// CopyToClipboard puts text on the system clipboard with the platform's
+// clipboard tool: clip on Windows, pbcopy on macOS, and wl-copy, xclip, or
+// xsel on Linux
+func CopyToClipboard(text string) error {
+	args, err := clipboardArgs(runtime.GOOS, exec.LookPath, os.Getenv)
+	if err != nil {
+		return err
+	}
+	logging.Debug("copying to clipboard", "argv", args)
+
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Stdin = strings.NewReader(text)
+	// No output pipes: xclip and xsel stay running to serve the selection,
+	// and waiting on their output would wait for them to exit
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("%s failed: %w", args[0], err)
+	}
+	return nil
+}
+
+// clipboardArgs returns the argv of the tool that copies its input to the
+// clipboard on goos. lookPath and getenv are exec.LookPath and os.Getenv
+// outside of tests.
+func clipboardArgs(goos string, lookPath func(string) (string, error), getenv func(string) string) ([]string, error) {
+	switch goos {
+	case "windows":
+		return []string{"clip"}, nil
+	case "darwin":
+		return []string{"pbcopy"}, nil
+	}
+
+	if getenv("WAYLAND_DISPLAY") != "" {
+		if _, err := lookPath("wl-copy"); err == nil {
+			return []string{"wl-copy"}, nil
+		}
+	}
+	if getenv("DISPLAY") != "" {
+		if _, err := lookPath("xclip"); err == nil {
+			return []string{"xclip", "-selection", "clipboard"}, nil
+		}
+		if _, err := lookPath("xsel"); err == nil {
+			return []string{"xsel", "--clipboard", "--input"}, nil
+		}
+	}
+	return nil, fmt.Errorf("clipboard: no clipboard tool found (install wl-copy, xclip, or xsel)")
+}
@@ -0,0 +1,60 @@
+package exec
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestClipboardArgs(t *testing.T) {
+	found := func(names ...string) func(string) (string, error) {
+		return func(name string) (string, error) {
+			for _, n := range names {
+				if n == name {
+					return "/usr/bin/" + name, nil
+				}
+			}
+			return "", errors.New("not found")
+		}
+	}
+	env := func(vars map[string]string) func(string) string {
+		return func(k string) string { return vars[k] }
+	}
+
+	tests := []struct {
+		name    string
+		goos    string
+		tools   []string
+		env     map[string]string
+		want    []string
+		wantErr string
+	}{
+		{"windows", "windows", nil, nil, []string{"clip"}, ""},
+		{"mac", "darwin", nil, nil, []string{"pbcopy"}, ""},
+		{"wayland", "linux", []string{"wl-copy", "xclip"}, map[string]string{"WAYLAND_DISPLAY": "wayland-0", "DISPLAY": ":0"},
+			[]string{"wl-copy"}, ""},
+		{"x11 prefers xclip", "linux", []string{"xclip", "xsel"}, map[string]string{"DISPLAY": ":0"},
+			[]string{"xclip", "-selection", "clipboard"}, ""},
+		{"x11 falls back to xsel", "linux", []string{"xsel"}, map[string]string{"DISPLAY": ":0"},
+			[]string{"xsel", "--clipboard", "--input"}, ""},
+		{"no display", "linux", []string{"xclip"}, nil, nil, "no clipboard tool"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := clipboardArgs(tt.goos, found(tt.tools...), env(tt.env))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,14 @@
+//go:build !windows
+
+package exec
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// setDetached starts cmd in a session of its own, so the terminal it opens is
+// not tied to MenuWorks' terminal and keeps running after MenuWorks exits
+func setDetached(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setsid: true}
+}
@@ -0,0 +1,24 @@
+//go:build windows
+
+package exec
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// Process creation flags missing from the syscall package
+const (
+	createNoWindow  = 0x08000000 // CREATE_NO_WINDOW: console program runs without a console window
+	detachedProcess = 0x00000008 // DETACHED_PROCESS: no console at all, not even MenuWorks'
+)
+
+// setDetached starts cmd without MenuWorks' console and in a process group of
+// its own, so closing MenuWorks or pressing Ctrl+C in it doesn't reach the
+// window it opens, and no extra console window flashes up for the launcher
+func setDetached(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{
+		HideWindow:    true,
+		CreationFlags: detachedProcess | syscall.CREATE_NEW_PROCESS_GROUP,
+	}
+}
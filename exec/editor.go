@@ -0,0 +1,43 @@
+package exec
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"github.com/benworks/menuworks/shell"
+)
+
+// EditorArgs returns the argv that opens path in the user's editor: $VISUAL
+// or $EDITOR, which may include arguments ("code --wait"), else notepad on
+// Windows and vi elsewhere. It is an error if the editor isn't installed.
+func EditorArgs(path string) ([]string, error) {
+	return editorArgs(runtime.GOOS, path, exec.LookPath, os.Getenv)
+}
+
+// editorArgs is EditorArgs for goos. lookPath and getenv are exec.LookPath
+// and os.Getenv outside of tests.
+func editorArgs(goos, path string, lookPath func(string) (string, error), getenv func(string) string) ([]string, error) {
+	editor := strings.TrimSpace(getenv("VISUAL"))
+	if editor == "" {
+		editor = strings.TrimSpace(getenv("EDITOR"))
+	}
+	var args []string
+	switch {
+	case editor != "":
+		var err error
+		if args, err = shell.Split(goos, editor); err != nil {
+			return nil, fmt.Errorf("editor %q: %w", editor, err)
+		}
+	case goos == "windows":
+		args = []string{"notepad"}
+	default:
+		args = []string{"vi"}
+	}
+	if _, err := lookPath(args[0]); err != nil {
+		return nil, fmt.Errorf("editor %s not found; set EDITOR to the one you use", args[0])
+	}
+	return append(args, path), nil
+}
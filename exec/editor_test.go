@@ -0,0 +1,42 @@
+package exec
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestEditorArgs(t *testing.T) {
+	installed := func(name string) (string, error) { return name, nil }
+	tests := []struct {
+		name string
+		goos string
+		env  map[string]string
+		want string
+	}{
+		{"default", "linux", nil, "vi|/tmp/config.yaml"},
+		{"windows default", "windows", nil, "notepad|/tmp/config.yaml"},
+		{"editor", "linux", map[string]string{"EDITOR": "nano"}, "nano|/tmp/config.yaml"},
+		{"visual wins", "linux", map[string]string{"EDITOR": "nano", "VISUAL": "code --wait"}, "code|--wait|/tmp/config.yaml"},
+		{"quoted path", "linux", map[string]string{"EDITOR": "'/opt/my editor/ed'"}, "/opt/my editor/ed|/tmp/config.yaml"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args, err := editorArgs(tt.goos, "/tmp/config.yaml", installed, func(k string) string { return tt.env[k] })
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := strings.Join(args, "|"); got != tt.want {
+				t.Errorf("got %s, expected %s", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := editorArgs("linux", "x", installed, func(string) string { return `"unterminated` }); err == nil {
+		t.Error("expected an error for an editor that can't be split")
+	}
+	missing := func(string) (string, error) { return "", errors.New("not found") }
+	if _, err := editorArgs("linux", "x", missing, func(string) string { return "" }); err == nil || !strings.Contains(err.Error(), "vi not found") {
+		t.Errorf("expected a missing editor to be reported, got %v", err)
+	}
+}
@@ -1,15 +1,22 @@
 package exec
 
 import (
+	"bufio"
 	"bytes"
+	"context"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
+	"os/signal"
 	"path/filepath"
 	"runtime"
 	"strings"
+	"time"
 
-	"github.com/gdamore/tcell/v2"
+	"github.com/benworks/menuworks/logging"
+	"github.com/benworks/menuworks/secrets"
+	"github.com/benworks/menuworks/shell"
 	"github.com/benworks/menuworks/ui"
 )
 
@@ -27,124 +34,186 @@ func GetOS() string {
 	}
 }
 
-// Execute runs a command using the platform-appropriate shell
-func Execute(command, workDir string) error {
-	var cmd *exec.Cmd
+// ShellArgs returns the program and arguments that run command in the
+// platform's shell: cmd /c on Windows, sh -c elsewhere
+func ShellArgs(command string) []string {
+	if runtime.GOOS == "windows" {
+		return []string{"cmd", "/c", command}
+	}
+	return []string{"sh", "-c", command}
+}
 
-	switch runtime.GOOS {
-	case "windows":
-		cmd = exec.Command("cmd", "/c", command)
-	default:
-		cmd = exec.Command("sh", "-c", command)
+// commandArgs returns argv when it is set, to run a program without a shell,
+// and otherwise the shell running command, with secret://name references
+// replaced by their values. It is called after the command is logged, so the
+// values never reach the log.
+func commandArgs(command string, argv []string) ([]string, error) {
+	if len(argv) > 0 {
+		return secrets.ResolveAll(argv)
+	}
+	if !secrets.Contains(command) {
+		return ShellArgs(command), nil
+	}
+	resolved, err := secrets.Resolve(command)
+	if err != nil {
+		return nil, err
+	}
+	return ShellArgs(resolved), nil
+}
+
+// Execute runs a command using the platform-appropriate shell, or argv
+// directly without a shell when it is not nil (command is then only logged).
+// stdin replaces the terminal as the command's input when it is not nil.
+func Execute(command string, argv []string, workDir string, stdin io.Reader) error {
+	args, err := commandArgs(command, argv)
+	if err != nil {
+		logging.Warn("command failed", "command", command, "err", err)
+		return err
 	}
+	cmd := exec.Command(args[0], args[1:]...)
 
 	// Inherit stdio/stdout/stderr so commands display naturally
 	cmd.Stdin = os.Stdin
+	if stdin != nil {
+		cmd.Stdin = stdin
+	}
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
 
-	if resolvedDir := resolveWorkDir(command, workDir); resolvedDir != "" {
+	if resolvedDir := ResolveWorkDir(command, workDir); resolvedDir != "" {
 		cmd.Dir = resolvedDir
 	}
 
+	logging.Info("running command", "command", command, "dir", cmd.Dir)
+	start := time.Now()
 	if err := cmd.Run(); err != nil {
+		logging.Warn("command failed", "command", command, "duration", time.Since(start), "err", err)
 		return err
 	}
+	logging.Info("command finished", "command", command, "duration", time.Since(start))
 
 	return nil
 }
 
+// interruptGrace is how long an interrupted command may take to exit before it is killed
+const interruptGrace = 3 * time.Second
+
 // ExecuteAndCapture runs a command and captures its output
 // Returns the combined stdout+stderr as a string
 func ExecuteAndCapture(command, workDir string) string {
-	var cmd *exec.Cmd
+	output, _ := ExecuteAndCaptureContext(context.Background(), command, nil, workDir, nil)
+	return output
+}
+
+// ExecuteAndCaptureContext is ExecuteAndCapture that interrupts the command when
+// ctx is cancelled (Ctrl+C forwarding). The command gets an interrupt signal
+// and is killed if it has not exited after a short grace period; Windows has
+// no interrupt signal, so there it is killed straight away.
+// argv, if not nil, is run directly instead of command, without a shell.
+// stdin, if not nil, is piped into the command; otherwise it reads nothing.
+// Also returns the exit code, or -1 if the command could not be started or was killed.
+func ExecuteAndCaptureContext(ctx context.Context, command string, argv []string, workDir string, stdin io.Reader) (string, int) {
 	var output bytes.Buffer
 
-	switch runtime.GOOS {
-	case "windows":
-		cmd = exec.Command("cmd", "/c", command)
-	default:
-		cmd = exec.Command("sh", "-c", command)
+	args, err := commandArgs(command, argv)
+	if err != nil {
+		logging.Warn("command failed", "command", command, "err", err)
+		return err.Error(), -1
 	}
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	setInterruptible(cmd)
+	cmd.WaitDelay = interruptGrace
 
-	if resolvedDir := resolveWorkDir(command, workDir); resolvedDir != "" {
+	if resolvedDir := ResolveWorkDir(command, workDir); resolvedDir != "" {
 		cmd.Dir = resolvedDir
 	}
 
 	// Capture both stdout and stderr
+	cmd.Stdin = stdin
 	cmd.Stdout = &output
 	cmd.Stderr = &output
 
 	// Run the command, ignore errors (user will see output anyway)
-	_ = cmd.Run()
+	logging.Info("running command", "command", command, "dir", cmd.Dir, "capture", true)
+	start := time.Now()
+	exitCode := 0
+	if err := cmd.Run(); err != nil {
+		logging.Warn("command failed", "command", command, "duration", time.Since(start), "err", err)
+		exitCode = -1
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			exitCode = exitErr.ExitCode()
+		}
+	} else {
+		logging.Info("command finished", "command", command, "duration", time.Since(start))
+	}
 
 	// Split output into lines and return
 	result := strings.TrimSpace(output.String())
-	return result
+	return result, exitCode
 }
-// showing the output, then prompts to return
-func ExecuteInAltScreen(screen *ui.Screen, command, workDir string) error {
-	// Close current screen to release tcell
-	screen.Close()
 
-	// Enable alternate screen buffer
-	altScreen, err := tcell.NewScreen()
+// ExecuteInteractive runs a command attached to the terminal, for programs
+// that need it (editors, ssh, top). The screen is suspended so the command
+// gets the terminal to itself; once it exits, the user presses Enter to
+// return and the screen is resumed and repainted. argv, if not nil, is run
+// directly instead of command, without a shell. stdin replaces the terminal
+// as the command's input when it is not nil.
+// Returns the exit code, or -1 if the command could not be started.
+func ExecuteInteractive(screen *ui.Screen, command string, argv []string, workDir string, stdin io.Reader) (int, error) {
+	args, err := commandArgs(command, argv)
 	if err != nil {
-		return fmt.Errorf("failed to create alternate screen: %w", err)
+		logging.Warn("command failed", "command", command, "err", err)
+		return -1, err
 	}
-	if err := altScreen.Init(); err != nil {
-		return fmt.Errorf("failed to init alternate screen: %w", err)
+	if err := screen.Suspend(); err != nil {
+		return -1, fmt.Errorf("failed to suspend screen: %w", err)
 	}
-	defer altScreen.Fini()
 
-	// Clear alt screen
-	altScreen.Clear()
-	altScreen.Sync()
-
-	// Execute the command with inherited I/O (shows output)
-	var cmd *exec.Cmd
-	switch runtime.GOOS {
-	case "windows":
-		cmd = exec.Command("cmd", "/c", command)
-	default:
-		cmd = exec.Command("sh", "-c", command)
-	}
+	cmd := exec.Command(args[0], args[1:]...)
 
 	cmd.Stdin = os.Stdin
+	if stdin != nil {
+		cmd.Stdin = stdin
+	}
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
-	if resolvedDir := resolveWorkDir(command, workDir); resolvedDir != "" {
+	if resolvedDir := ResolveWorkDir(command, workDir); resolvedDir != "" {
 		cmd.Dir = resolvedDir
 	}
 
-	_ = cmd.Run() // Run command, ignore errors for now (user sees output anyway)
-
-	// Print prompt
-	fmt.Println("\nCommand finished. Press any key to return.")
+	// Ctrl+C reaches the whole foreground process group; catch it so only the
+	// command stops. A caught (rather than ignored) signal is reset for the child.
+	interrupts := make(chan os.Signal, 1)
+	signal.Notify(interrupts, os.Interrupt)
+	defer signal.Stop(interrupts)
 
-	// Wait for any key press
-	for {
-		ev := altScreen.PollEvent()
-		if _, ok := ev.(*tcell.EventKey); ok {
-			break
+	logging.Info("running command", "command", command, "dir", cmd.Dir, "interactive", true)
+	start := time.Now()
+	exitCode := 0
+	if err := cmd.Run(); err != nil {
+		logging.Warn("command failed", "command", command, "duration", time.Since(start), "err", err)
+		exitCode = -1
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			exitCode = exitErr.ExitCode()
 		}
+	} else {
+		logging.Info("command finished", "command", command, "duration", time.Since(start))
 	}
 
-	altScreen.Fini()
+	// The terminal is in its normal mode while suspended, so a line read waits for Enter
+	fmt.Print("\nCommand finished. Press Enter to return.")
+	bufio.NewReader(os.Stdin).ReadString('\n')
 
-	// Reinitialize tcell screen for menu
-	newScreen, err := ui.NewScreen()
-	if err != nil {
-		return fmt.Errorf("failed to restore screen: %w", err)
+	if err := screen.Resume(); err != nil {
+		return exitCode, fmt.Errorf("failed to restore screen: %w", err)
 	}
-
-	// Copy screen pointer back
-	*screen = *newScreen
-
-	return nil
+	return exitCode, nil
 }
 
-func resolveWorkDir(command, workDir string) string {
+// ResolveWorkDir returns the directory a command runs in: workDir if set,
+// otherwise the directory of the command's program when it is given as a path
+// to an existing file. Empty means the current directory.
+func ResolveWorkDir(command, workDir string) string {
 	if strings.TrimSpace(workDir) != "" {
 		return workDir
 	}
@@ -162,24 +231,9 @@ func resolveWorkDir(command, workDir string) string {
 }
 
 func firstCommandToken(command string) string {
-	trimmed := strings.TrimSpace(command)
-	if trimmed == "" {
+	args, err := shell.Split(runtime.GOOS, command)
+	if err != nil || len(args) == 0 {
 		return ""
 	}
-
-	if strings.HasPrefix(trimmed, "\"") {
-		endIdx := strings.Index(trimmed[1:], "\"")
-		if endIdx == -1 {
-			return ""
-		}
-		return trimmed[1 : 1+endIdx]
-	}
-
-	for i, ch := range trimmed {
-		if ch == ' ' || ch == '\t' || ch == '\n' {
-			return trimmed[:i]
-		}
-	}
-
-	return trimmed
+	return args[0]
 }
@@ -0,0 +1,124 @@
+package exec
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/benworks/menuworks/secrets"
+)
+
+func TestExecuteAndCaptureContextPipesStdin(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses cat")
+	}
+
+	output, exitCode := ExecuteAndCaptureContext(context.Background(), "cat", nil, "", strings.NewReader("select 1;\n"))
+	if exitCode != 0 || output != "select 1;" {
+		t.Errorf("expected stdin echoed back, got %q (exit %d)", output, exitCode)
+	}
+
+	// Without stdin the command reads end of input straight away
+	output, exitCode = ExecuteAndCaptureContext(context.Background(), "cat", nil, "", nil)
+	if exitCode != 0 || output != "" {
+		t.Errorf("expected no output without stdin, got %q (exit %d)", output, exitCode)
+	}
+}
+
+func TestExecuteAndCaptureContextArgv(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses printf")
+	}
+
+	// The program runs directly, so the argument's space and $ reach it intact
+	output, exitCode := ExecuteAndCaptureContext(context.Background(), "ignored", []string{"printf", "%s", "a b $HOME"}, "", nil)
+	if exitCode != 0 || output != "a b $HOME" {
+		t.Errorf("expected the argument passed through unchanged, got %q (exit %d)", output, exitCode)
+	}
+}
+
+func TestResolveWorkDir(t *testing.T) {
+	dir := t.TempDir()
+	program := filepath.Join(dir, "tool")
+	if err := os.WriteFile(program, nil, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := ResolveWorkDir(program, "/srv"); got != "/srv" {
+		t.Errorf("expected the configured workdir, got %q", got)
+	}
+	if got := ResolveWorkDir(program+" --flag", ""); got != dir {
+		t.Errorf("expected the program's directory %q, got %q", dir, got)
+	}
+	if got := ResolveWorkDir("echo hi", ""); got != "" {
+		t.Errorf("expected the current directory for a bare command, got %q", got)
+	}
+}
+
+func TestRunAsCommand(t *testing.T) {
+	got, err := runAsCommand("linux", "systemctl restart kiosk && echo 'done'", "svc", true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := `sudo -u 'svc' -- sh -c 'systemctl restart kiosk && echo '\''done'\'''`; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	// Captured commands have no terminal, so sudo must not prompt
+	if got, _ := runAsCommand("darwin", "id", "svc", false); got != "sudo -n -u 'svc' -- sh -c 'id'" {
+		t.Errorf("expected a non-interactive sudo, got %q", got)
+	}
+	if _, err := runAsCommand("windows", "dir", "svc", true); err == nil {
+		t.Errorf("expected an error on Windows")
+	}
+}
+
+func TestRunAsKeepsWorkDir(t *testing.T) {
+	if got, dir, err := RunAs("ls", "/srv", "", false); err != nil || got != "ls" || dir != "/srv" {
+		t.Errorf("expected no change without a user, got %q %q %v", got, dir, err)
+	}
+	if runtime.GOOS == "windows" {
+		t.Skip("no sudo on Windows")
+	}
+
+	dir := t.TempDir()
+	program := filepath.Join(dir, "backup.sh")
+	if err := os.WriteFile(program, nil, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	// The directory comes from the program, not from sudo at the start of the wrapped command
+	if _, got, err := RunAs(program, "", "svc", false); err != nil || got != dir {
+		t.Errorf("expected the program's directory %q, got %q (%v)", dir, got, err)
+	}
+}
+
+func TestExecuteAndCaptureContextResolvesSecrets(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses printf")
+	}
+	path := filepath.Join(t.TempDir(), secrets.FileName)
+	if err := secrets.WriteFile(path, "pw", map[string]string{"menuworks-test-token": "s3cret"}); err != nil {
+		t.Fatal(err)
+	}
+	secrets.SetFile(path)
+	defer secrets.SetFile("")
+	t.Setenv(secrets.KeyEnv, "pw")
+
+	output, exitCode := ExecuteAndCaptureContext(context.Background(), "printf %s secret://menuworks-test-token", nil, "", nil)
+	if exitCode != 0 || output != "s3cret" {
+		t.Errorf("expected the secret's value, got %q (exit %d)", output, exitCode)
+	}
+	output, exitCode = ExecuteAndCaptureContext(context.Background(), "ignored", []string{"printf", "%s", "secret://menuworks-test-token"}, "", nil)
+	if exitCode != 0 || output != "s3cret" {
+		t.Errorf("expected the secret's value in argv, got %q (exit %d)", output, exitCode)
+	}
+
+	// A missing secret fails the command without running it
+	output, exitCode = ExecuteAndCaptureContext(context.Background(), "echo secret://menuworks-missing", nil, "", nil)
+	if exitCode != -1 || !strings.Contains(output, "menuworks-missing") {
+		t.Errorf("expected an error naming the missing secret, got %q (exit %d)", output, exitCode)
+	}
+}
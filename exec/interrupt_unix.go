@@ -0,0 +1,18 @@
+//go:build !windows
+
+package exec
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// setInterruptible runs cmd in its own process group and makes cancelling it
+// send SIGINT to the whole group, so the shell and whatever it started both
+// see Ctrl+C as they would in a terminal
+func setInterruptible(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	cmd.Cancel = func() error {
+		return syscall.Kill(-cmd.Process.Pid, syscall.SIGINT)
+	}
+}
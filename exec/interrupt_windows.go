@@ -0,0 +1,22 @@
+//go:build windows
+
+package exec
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// setInterruptible makes cancelling cmd kill it; Windows has no interrupt
+// signal to forward to a process without a console of its own. Its output is
+// captured, so it runs without a console window, and in its own process group
+// so GUI programs it starts aren't tied to MenuWorks' console.
+func setInterruptible(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{
+		HideWindow:    true,
+		CreationFlags: createNoWindow | syscall.CREATE_NEW_PROCESS_GROUP,
+	}
+	cmd.Cancel = func() error {
+		return cmd.Process.Kill()
+	}
+}
@@ -0,0 +1,161 @@
+package exec
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"github.com/benworks/menuworks/logging"
+	"github.com/benworks/menuworks/secrets"
+	"github.com/benworks/menuworks/shell"
+)
+
+// holdPrompt keeps a new window open after the command exits so its output can be read
+const holdPrompt = `; printf '\nPress Enter to close...'; read _`
+
+// linuxTerminals are tried in order when $TERMINAL is unset, with the
+// arguments that precede the command each one runs
+var linuxTerminals = []struct {
+	name string
+	args []string
+}{
+	{"x-terminal-emulator", []string{"-e"}},
+	{"gnome-terminal", []string{"--"}},
+	{"konsole", []string{"-e"}},
+	{"xfce4-terminal", []string{"-x"}},
+	{"alacritty", []string{"-e"}},
+	{"kitty", nil},
+	{"xterm", []string{"-e"}},
+}
+
+// Launch starts command outside the TUI according to mode ("new-window",
+// "tmux-pane", or "wt-tab") and returns once the terminal has been asked to
+// open it; it does not wait for the command to finish
+func Launch(mode, command, workDir string) error {
+	dir := ResolveWorkDir(command, workDir)
+	args, err := launchArgs(mode, runtime.GOOS, command, dir, exec.LookPath, os.Getenv)
+	if err != nil {
+		return err
+	}
+	logging.Info("launching command", "mode", mode, "command", command, "argv", args)
+
+	// The log has the command as written; the terminal gets its secrets
+	if secrets.Contains(command) {
+		resolved, err := secrets.Resolve(command)
+		if err != nil {
+			return err
+		}
+		if args, err = launchArgs(mode, runtime.GOOS, resolved, dir, exec.LookPath, os.Getenv); err != nil {
+			return err
+		}
+	}
+
+	cmd := exec.Command(args[0], args[1:]...)
+	setDetached(cmd)
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed to start %s: %w", args[0], err)
+	}
+	// Reap the launcher in the background; the terminal it opens outlives it
+	go cmd.Wait()
+	return nil
+}
+
+// launchArgs returns the argv that opens command in a new terminal for goos.
+// lookPath and getenv are exec.LookPath and os.Getenv outside of tests.
+func launchArgs(mode, goos, command, dir string, lookPath func(string) (string, error), getenv func(string) string) ([]string, error) {
+	switch mode {
+	case "tmux-pane":
+		if getenv("TMUX") == "" {
+			return nil, fmt.Errorf("launch: tmux-pane needs MenuWorks to be running inside tmux")
+		}
+		args := []string{"tmux", "split-window"}
+		if dir != "" {
+			args = append(args, "-c", dir)
+		}
+		return append(args, "sh", "-c", command+holdPrompt), nil
+
+	case "wt-tab":
+		if goos != "windows" {
+			return nil, fmt.Errorf("launch: wt-tab is only available on Windows")
+		}
+		args := []string{"wt.exe", "-w", "0", "new-tab"}
+		if dir != "" {
+			args = append(args, "-d", dir)
+		}
+		return append(args, "cmd", "/k", command), nil
+
+	case "new-window":
+		switch goos {
+		case "windows":
+			// start takes its first quoted argument as the window title; the
+			// space makes Go quote it
+			args := []string{"cmd", "/c", "start", "MenuWorks Command"}
+			if dir != "" {
+				args = append(args, "/D", dir)
+			}
+			return append(args, "cmd", "/k", command), nil
+		case "darwin":
+			script := command
+			if dir != "" {
+				script = "cd " + shellQuote(dir) + " && " + command
+			}
+			return macTerminalArgs(script), nil
+		default:
+			script := command + holdPrompt
+			if dir != "" {
+				script = "cd " + shellQuote(dir) + " && " + script
+			}
+			return linuxTerminalArgs([]string{"sh", "-c", script}, lookPath, getenv)
+		}
+	}
+	return nil, fmt.Errorf("launch: unknown mode '%s'", mode)
+}
+
+// TerminalArgs returns the argv that opens a new terminal window on goos
+// ("linux" or "darwin") running argv. Unlike Launch, the window isn't held
+// open after argv exits. Autostart entries use it, since MenuWorks needs a
+// terminal that nothing starting it at login provides.
+func TerminalArgs(goos string, argv []string) ([]string, error) {
+	switch goos {
+	case "linux":
+		return linuxTerminalArgs(argv, exec.LookPath, os.Getenv)
+	case "darwin":
+		return macTerminalArgs("exec " + shell.Quote(goos, argv)), nil
+	}
+	return nil, fmt.Errorf("no terminal to open on %s", goos)
+}
+
+// linuxTerminalArgs returns the argv that runs argv in $TERMINAL, or else the
+// first of linuxTerminals that is installed
+func linuxTerminalArgs(argv []string, lookPath func(string) (string, error), getenv func(string) string) ([]string, error) {
+	if term := getenv("TERMINAL"); term != "" {
+		return append([]string{term, "-e"}, argv...), nil
+	}
+	for _, t := range linuxTerminals {
+		if _, err := lookPath(t.name); err == nil {
+			args := append([]string{t.name}, t.args...)
+			return append(args, argv...), nil
+		}
+	}
+	return nil, fmt.Errorf("launch: no terminal emulator found (set $TERMINAL)")
+}
+
+// macTerminalArgs returns the argv that runs the shell script in a new
+// Terminal.app window
+func macTerminalArgs(script string) []string {
+	return []string{"osascript",
+		"-e", fmt.Sprintf("tell application \"Terminal\" to do script %s", appleScriptString(script)),
+		"-e", "tell application \"Terminal\" to activate"}
+}
+
+// shellQuote quotes s for a POSIX shell
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// appleScriptString quotes s as an AppleScript string literal
+func appleScriptString(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
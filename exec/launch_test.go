@@ -0,0 +1,83 @@
+package exec
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLaunchArgs(t *testing.T) {
+	onlyXterm := func(name string) (string, error) {
+		if name == "xterm" {
+			return "/usr/bin/xterm", nil
+		}
+		return "", errors.New("not found")
+	}
+	env := func(vars map[string]string) func(string) string {
+		return func(k string) string { return vars[k] }
+	}
+
+	tests := []struct {
+		name    string
+		mode    string
+		goos    string
+		dir     string
+		env     map[string]string
+		want    []string
+		wantErr string
+	}{
+		{"tmux pane", "tmux-pane", "linux", "/srv", map[string]string{"TMUX": "/tmp/tmux-1/default"},
+			[]string{"tmux", "split-window", "-c", "/srv", "sh", "-c", "top" + holdPrompt}, ""},
+		{"tmux outside tmux", "tmux-pane", "linux", "", nil, nil, "inside tmux"},
+		{"linux falls back to xterm", "new-window", "linux", "", nil,
+			[]string{"xterm", "-e", "sh", "-c", "top" + holdPrompt}, ""},
+		{"linux $TERMINAL wins", "new-window", "linux", "/srv", map[string]string{"TERMINAL": "foot"},
+			[]string{"foot", "-e", "sh", "-c", "cd '/srv' && top" + holdPrompt}, ""},
+		{"windows terminal tab", "wt-tab", "windows", `C:\work`, nil,
+			[]string{"wt.exe", "-w", "0", "new-tab", "-d", `C:\work`, "cmd", "/k", "top"}, ""},
+		{"wt tab off windows", "wt-tab", "linux", "", nil, nil, "only available on Windows"},
+		{"windows new window", "new-window", "windows", "", nil,
+			[]string{"cmd", "/c", "start", "MenuWorks Command", "cmd", "/k", "top"}, ""},
+		{"unknown mode", "popup", "linux", "", nil, nil, "unknown mode"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := launchArgs(tt.mode, tt.goos, "top", tt.dir, onlyXterm, env(tt.env))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLaunchArgsMacQuotesScript(t *testing.T) {
+	got, err := launchArgs("new-window", "darwin", `say "hi"`, "/Users/me/My Dir", nil, func(string) string { return "" })
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := `tell application "Terminal" to do script "cd '/Users/me/My Dir' && say \"hi\""`
+	if got[0] != "osascript" || got[2] != want {
+		t.Errorf("got %q", got)
+	}
+}
+
+func TestTerminalArgsMac(t *testing.T) {
+	got, err := TerminalArgs("darwin", []string{"/Applications/Menu Works/menuworks", "-no-splash"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := `tell application "Terminal" to do script "exec '/Applications/Menu Works/menuworks' -no-splash"`
+	if len(got) < 3 || got[0] != "osascript" || got[2] != want {
+		t.Errorf("expected osascript running %q, got %q", want, got)
+	}
+}
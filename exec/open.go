@@ -0,0 +1,38 @@
+package exec
+
+import (
+	"fmt"
+	"os/exec"
+	"runtime"
+
+	"github.com/benworks/menuworks/logging"
+)
+
+// OpenURL opens url with the program the OS uses for its scheme, such as
+// the default browser for https://. It returns once the opener has started.
+func OpenURL(url string) error {
+	args := openerArgs(runtime.GOOS, url)
+	logging.Info("opening url", "url", url, "argv", args)
+
+	cmd := exec.Command(args[0], args[1:]...)
+	setDetached(cmd)
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed to start %s: %w", args[0], err)
+	}
+	// Reap the opener in the background; the browser it starts outlives it
+	go cmd.Wait()
+	return nil
+}
+
+// openerArgs returns the argv that opens url on goos. On Windows this is the
+// handler start uses, called directly so cmd never parses the URL's & and ^.
+func openerArgs(goos, url string) []string {
+	switch goos {
+	case "windows":
+		return []string{"rundll32", "url.dll,FileProtocolHandler", url}
+	case "darwin":
+		return []string{"open", url}
+	default:
+		return []string{"xdg-open", url}
+	}
+}
@@ -0,0 +1,24 @@
+package exec
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestOpenerArgs(t *testing.T) {
+	url := "https://example.com/search?q=a&b=c"
+	tests := []struct {
+		goos string
+		want []string
+	}{
+		{"windows", []string{"rundll32", "url.dll,FileProtocolHandler", url}},
+		{"darwin", []string{"open", url}},
+		{"linux", []string{"xdg-open", url}},
+		{"freebsd", []string{"xdg-open", url}},
+	}
+	for _, tt := range tests {
+		if got := openerArgs(tt.goos, url); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: expected %v, got %v", tt.goos, tt.want, got)
+		}
+	}
+}
@@ -0,0 +1,138 @@
+package exec
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/benworks/menuworks/config"
+	"github.com/benworks/menuworks/i18n"
+	"github.com/benworks/menuworks/ui"
+)
+
+// Job is one command of a parallel item, ready to run with its output captured
+type Job struct {
+	Item    config.MenuItem // the command item the job was made from
+	Command string          // wrapped for the item's run_as user, if any
+	Argv    []string        // run directly instead of Command when not nil
+	WorkDir string
+	Stdin   io.ReadCloser // nil when the command reads nothing
+
+	// Run, if set, replaces the built-in runner. It returns the output and
+	// the exit code, or 0 when it cannot tell.
+	Run func(ctx context.Context) (string, int)
+}
+
+// JobResult is the outcome of one Job
+type JobResult struct {
+	Label    string
+	Output   string
+	ExitCode int // -1 if the command could not be started or was stopped
+}
+
+// ParallelJobs prepares commands (a parallel item's CommandsForOS) to run on
+// osType. Output is captured, so sudo gets no terminal to ask for a password
+// on. Every input is opened up front, so a missing file stops the item before
+// anything runs. On success the caller closes the inputs with CloseJobs.
+func ParallelJobs(commands []config.MenuItem, osType string) ([]Job, error) {
+	jobs := make([]Job, 0, len(commands))
+	for _, c := range commands {
+		command, dir, err := RunAs(c.Exec.CommandForOS(osType), c.Exec.WorkDir, c.RunAs, false)
+		if err == nil {
+			var stdin io.ReadCloser
+			if stdin, err = c.OpenStdin(); err == nil {
+				jobs = append(jobs, Job{Item: c, Command: command, Argv: c.ArgvForOS(osType), WorkDir: dir, Stdin: stdin})
+				continue
+			}
+		}
+		CloseJobs(jobs)
+		return nil, fmt.Errorf("%s: %w", c.Label, err)
+	}
+	return jobs, nil
+}
+
+// CloseJobs closes the jobs' inputs
+func CloseJobs(jobs []Job) {
+	for _, j := range jobs {
+		if j.Stdin != nil {
+			j.Stdin.Close()
+		}
+	}
+}
+
+// RunParallel runs jobs at the same time and returns their results, in the
+// order of jobs, once every one has finished. Cancelling ctx interrupts them.
+func RunParallel(ctx context.Context, jobs []Job) []JobResult {
+	results := make([]JobResult, len(jobs))
+	var wg sync.WaitGroup
+	for i, j := range jobs {
+		wg.Add(1)
+		go func(i int, j Job) {
+			defer wg.Done()
+			r := JobResult{Label: j.Item.Label}
+			if j.Run != nil {
+				r.Output, r.ExitCode = j.Run(ctx)
+			} else {
+				// j.Stdin is an io.ReadCloser; keep a nil one nil as an io.Reader
+				var stdin io.Reader
+				if j.Stdin != nil {
+					stdin = j.Stdin
+				}
+				r.Output, r.ExitCode = ExecuteAndCaptureContext(ctx, j.Command, j.Argv, j.WorkDir, stdin)
+			}
+			results[i] = r
+		}(i, j)
+	}
+	wg.Wait()
+	return results
+}
+
+// CountSucceeded returns how many results exited with 0, and the first
+// failure's exit code (0 when every command succeeded)
+func CountSucceeded(results []JobResult) (succeeded, exitCode int) {
+	for _, r := range results {
+		if r.ExitCode == 0 {
+			succeeded++
+		} else if exitCode == 0 {
+			exitCode = r.ExitCode
+		}
+	}
+	return succeeded, exitCode
+}
+
+// CombineOutput joins the output of a parallel item's commands in order,
+// each line prefixed with its command's label (padded so the output lines
+// up), and each failure followed by its exit status
+func CombineOutput(results []JobResult) string {
+	width := 0
+	for _, r := range results {
+		if w := ui.StringWidth(r.Label); w > width {
+			width = w
+		}
+	}
+
+	var b strings.Builder
+	for _, r := range results {
+		prefix := fmt.Sprintf("[%s]%s ", r.Label, strings.Repeat(" ", width-ui.StringWidth(r.Label)))
+		if r.Output != "" {
+			for _, line := range strings.Split(r.Output, "\n") {
+				b.WriteString(prefix + line + "\n")
+			}
+		}
+		if r.ExitCode != 0 {
+			b.WriteString(prefix + ExitStatus(r.ExitCode) + "\n")
+		}
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// ExitStatus describes a command's exit code; -1 means it could not be
+// started or was stopped by a signal
+func ExitStatus(exitCode int) string {
+	if exitCode < 0 {
+		return i18n.T("Did not finish")
+	}
+	return i18n.Tf("Exit code %d", exitCode)
+}
@@ -0,0 +1,25 @@
+package exec
+
+import (
+	"context"
+	"testing"
+
+	"github.com/benworks/menuworks/config"
+)
+
+func TestRunParallelKeepsOrder(t *testing.T) {
+	jobs := []Job{
+		{Item: config.MenuItem{Label: "first"}, Run: func(context.Context) (string, int) { return "a\nb", 0 }},
+		{Item: config.MenuItem{Label: "x"}, Run: func(context.Context) (string, int) { return "", -1 }},
+		{Item: config.MenuItem{Label: "ok"}, Run: func(context.Context) (string, int) { return "", 0 }},
+	}
+	results := RunParallel(context.Background(), jobs)
+
+	if succeeded, exitCode := CountSucceeded(results); succeeded != 2 || exitCode != -1 {
+		t.Errorf("expected 2 succeeded and exit code -1, got %d and %d", succeeded, exitCode)
+	}
+	want := "[first] a\n[first] b\n[x]     Did not finish"
+	if got := CombineOutput(results); got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+}
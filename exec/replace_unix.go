@@ -0,0 +1,28 @@
+//go:build !windows
+
+package exec
+
+import (
+	"os"
+	"os/exec"
+	"syscall"
+)
+
+// FallbackShell is the shell a fallback_shell item opens when it has no
+// exec for this OS
+func FallbackShell() string {
+	return "/bin/sh"
+}
+
+// ReplaceProcess runs argv in place of MenuWorks: the process image is
+// replaced, keeping its ID and terminal, so a login session carries on in
+// argv. $SHELL is set to argv's program, which may otherwise be MenuWorks
+// itself. It only returns on error.
+func ReplaceProcess(argv []string) error {
+	path, err := exec.LookPath(argv[0])
+	if err != nil {
+		return err
+	}
+	os.Setenv("SHELL", path)
+	return syscall.Exec(path, argv, os.Environ())
+}
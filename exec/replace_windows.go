@@ -0,0 +1,26 @@
+//go:build windows
+
+package exec
+
+import (
+	"os"
+	"os/exec"
+)
+
+// FallbackShell is the shell a fallback_shell item opens when it has no
+// exec for this OS: %ComSpec%, normally cmd.exe
+func FallbackShell() string {
+	if comspec := os.Getenv("ComSpec"); comspec != "" {
+		return comspec
+	}
+	return "cmd.exe"
+}
+
+// ReplaceProcess starts argv on MenuWorks' console and returns, so MenuWorks
+// can exit and leave the console to it. Windows can't replace a running
+// process's image.
+func ReplaceProcess(argv []string) error {
+	cmd := exec.Command(argv[0], argv[1:]...)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	return cmd.Start()
+}
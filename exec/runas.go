@@ -0,0 +1,36 @@
+package exec
+
+import (
+	"fmt"
+	"runtime"
+)
+
+// RunAs wraps command so it runs as user through sudo, and returns it with
+// the directory to run it in, which is worked out from the original command
+// since the wrapped one starts with sudo. An empty user returns both unchanged.
+// canPrompt means the command will have a terminal, so sudo may ask for a
+// password on it; when output is captured there is no terminal to ask on, so
+// sudo fails with a message instead of prompting. Windows has no sudo and
+// returns an error.
+func RunAs(command, workDir, user string, canPrompt bool) (string, string, error) {
+	if user == "" {
+		return command, workDir, nil
+	}
+	wrapped, err := runAsCommand(runtime.GOOS, command, user, canPrompt)
+	if err != nil {
+		return "", "", err
+	}
+	return wrapped, ResolveWorkDir(command, workDir), nil
+}
+
+// runAsCommand returns the command that runs command as user on goos
+func runAsCommand(goos, command, user string, canPrompt bool) (string, error) {
+	if goos == "windows" {
+		return "", fmt.Errorf("run_as: running as another user is not supported on Windows")
+	}
+	sudo := "sudo"
+	if !canPrompt {
+		sudo += " -n"
+	}
+	return fmt.Sprintf("%s -u %s -- sh -c %s", sudo, shellQuote(user), shellQuote(command)), nil
+}
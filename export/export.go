@@ -0,0 +1,137 @@
+// Package export renders a menu tree as documentation, so operators can get a
+// printed cheat sheet of every menu, item, command, and help text.
+package export
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"strings"
+
+	"github.com/benworks/menuworks/menu"
+)
+
+// Markdown writes the menu tree as a Markdown document, one section per menu
+func Markdown(w io.Writer, title string, tree []menu.Node) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n", title)
+	writeMarkdownMenu(&b, "Main Menu", tree, 2)
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// writeMarkdownMenu writes one menu's items and then a section for each of its submenus
+func writeMarkdownMenu(b *strings.Builder, heading string, nodes []menu.Node, level int) {
+	if level > 6 {
+		level = 6
+	}
+	fmt.Fprintf(b, "\n%s %s\n\n", strings.Repeat("#", level), heading)
+
+	for _, node := range nodes {
+		if node.Type == "separator" {
+			b.WriteString("\n---\n\n")
+			continue
+		}
+
+		key := ""
+		if node.Hotkey != "" {
+			key = "[" + node.Hotkey + "] "
+		}
+		fmt.Fprintf(b, "- **%s%s**", key, node.Label)
+		switch node.Type {
+		case "submenu":
+			fmt.Fprintf(b, " — opens *%s*", node.Path)
+		case "command":
+			if node.Exec != "" {
+				fmt.Fprintf(b, " — `%s`", node.Exec)
+			}
+		case "parallel":
+			fmt.Fprintf(b, " — runs the commands in *%s* at once", node.Path)
+		case "back":
+			b.WriteString(" — goes back")
+		case "quit":
+			b.WriteString(" — quits")
+		case "edit_config":
+			b.WriteString(" — edits the menu's config")
+		case "fallback_shell":
+			b.WriteString(" — leaves the menu for a shell")
+		case "switch_role":
+			b.WriteString(" — switches role")
+		case "url":
+			fmt.Fprintf(b, " — opens <%s>", node.URL)
+		case "browse":
+			fmt.Fprintf(b, " — picks a file in `%s` for `%s`", node.Browse, node.Exec)
+		}
+		if node.Disabled {
+			b.WriteString(" *(unavailable)*")
+		}
+		b.WriteString("\n")
+		if node.Help != "" {
+			for _, line := range strings.Split(strings.TrimSpace(node.Help), "\n") {
+				fmt.Fprintf(b, "  > %s\n", line)
+			}
+		}
+	}
+
+	for _, node := range nodes {
+		if len(node.Children) > 0 {
+			writeMarkdownMenu(b, node.Path, node.Children, level+1)
+		}
+	}
+}
+
+// htmlTemplate renders each menu as a section; submenus follow their parent
+var htmlTemplate = template.Must(template.New("page").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body { font-family: sans-serif; max-width: 50em; margin: 2em auto; color: #222; }
+section { border-left: 3px solid #339; padding-left: 1em; margin: 1.5em 0; }
+kbd { border: 1px solid #999; border-radius: 3px; padding: 0 .3em; }
+code { background: #eee; padding: 0 .3em; }
+.help { color: #555; margin: .2em 0 .6em 1em; white-space: pre-wrap; }
+.disabled { color: #999; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+{{template "menu" .Root}}
+</body>
+</html>
+{{define "menu"}}<section>
+<h2>{{.Heading}}</h2>
+<ul>
+{{range .Nodes}}{{if eq .Type "separator"}}<hr>
+{{else}}<li{{if .Disabled}} class="disabled"{{end}}>{{if .Hotkey}}<kbd>{{.Hotkey}}</kbd> {{end}}<strong>{{.Label}}</strong>{{if eq .Type "submenu"}} — opens <em>{{.Path}}</em>{{else if and (eq .Type "command") .Exec}} — <code>{{.Exec}}</code>{{else if eq .Type "parallel"}} — runs the commands in <em>{{.Path}}</em> at once{{else if eq .Type "back"}} — goes back{{else if eq .Type "quit"}} — quits{{else if eq .Type "edit_config"}} — edits the menu's config{{else if eq .Type "fallback_shell"}} — leaves the menu for a shell{{else if eq .Type "switch_role"}} — switches role{{else if eq .Type "url"}} — opens <a href="{{.URL}}">{{.URL}}</a>{{else if eq .Type "browse"}} — picks a file in <code>{{.Browse}}</code> for <code>{{.Exec}}</code>{{end}}{{if .Disabled}} (unavailable){{end}}{{if .Help}}
+<div class="help">{{.Help}}</div>{{end}}</li>
+{{end}}{{end}}</ul>
+</section>
+{{range .Submenus}}{{template "menu" .}}{{end}}{{end}}`))
+
+// htmlMenu is one menu section in the HTML template
+type htmlMenu struct {
+	Heading  string
+	Nodes    []menu.Node
+	Submenus []htmlMenu
+}
+
+// newHTMLMenu builds the section for nodes and, recursively, their submenus
+func newHTMLMenu(heading string, nodes []menu.Node) htmlMenu {
+	m := htmlMenu{Heading: heading, Nodes: nodes}
+	for _, node := range nodes {
+		if len(node.Children) > 0 {
+			m.Submenus = append(m.Submenus, newHTMLMenu(node.Path, node.Children))
+		}
+	}
+	return m
+}
+
+// HTML writes the menu tree as a standalone HTML page, one section per menu
+func HTML(w io.Writer, title string, tree []menu.Node) error {
+	return htmlTemplate.Execute(w, struct {
+		Title string
+		Root  htmlMenu
+	}{title, newHTMLMenu("Main Menu", tree)})
+}
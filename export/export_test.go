@@ -0,0 +1,117 @@
+package export
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/benworks/menuworks/menu"
+)
+
+func testTree() []menu.Node {
+	return []menu.Node{
+		{Label: "Games", Type: "submenu", Hotkey: "G", Path: "Games", Target: "games", Children: []menu.Node{
+			{Label: "Portal <2>", Type: "command", Hotkey: "P", Path: "Games/Portal <2>", Exec: "steam://run/620", Help: "Needs Steam"},
+			{Label: "ROMs", Type: "browse", Hotkey: "R", Path: "Games/ROMs", Exec: "mednafen {file}", Browse: "~/roms"},
+		}},
+		{Label: "Docs", Type: "url", Hotkey: "D", Path: "Docs", URL: "https://example.com/docs"},
+		{Type: "separator"},
+		{Label: "Exit", Type: "back", Hotkey: "E", Path: "Exit"},
+	}
+}
+
+func TestMarkdown(t *testing.T) {
+	var b strings.Builder
+	if err := Markdown(&b, "Kiosk", testTree()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	out := b.String()
+	for _, want := range []string{
+		"# Kiosk\n",
+		"## Main Menu\n",
+		"- **[G] Games** — opens *Games*\n",
+		"### Games\n",
+		"- **[P] Portal <2>** — `steam://run/620`\n  > Needs Steam\n",
+		"- **[R] ROMs** — picks a file in `~/roms` for `mednafen {file}`\n",
+		"- **[D] Docs** — opens <https://example.com/docs>\n",
+		"- **[E] Exit** — goes back\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in output:\n%s", want, out)
+		}
+	}
+}
+
+func TestHTMLEscapes(t *testing.T) {
+	var b strings.Builder
+	if err := HTML(&b, "Kiosk", testTree()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	out := b.String()
+	if !strings.Contains(out, "<strong>Portal &lt;2&gt;</strong>") {
+		t.Errorf("expected escaped label in output:\n%s", out)
+	}
+	if strings.Count(out, "<section>") != 2 {
+		t.Errorf("expected a section per menu, got:\n%s", out)
+	}
+	if !strings.Contains(out, `<div class="help">Needs Steam</div>`) {
+		t.Errorf("expected help text in output:\n%s", out)
+	}
+}
+
+func TestCommandsSelection(t *testing.T) {
+	all, err := Commands(testTree(), nil)
+	if err != nil || len(all) != 1 || all[0].Label != "Portal <2>" {
+		t.Fatalf("expected the one command, got %v, %v", all, err)
+	}
+	if got, err := Commands(testTree(), []string{"games/portal <2>"}); err != nil || len(got) != 1 {
+		t.Errorf("expected case-insensitive path match, got %v, %v", got, err)
+	}
+	if _, err := Commands(testTree(), []string{"Games"}); err == nil {
+		t.Errorf("expected an error selecting a submenu")
+	}
+}
+
+func TestShortcutFormats(t *testing.T) {
+	l := Launcher{Exe: "/opt/menu works/menuworks", ConfigPath: "/etc/menu.yaml"}
+	node := menu.Node{Label: "Disk 100%", Type: "command", Path: "Tools/Disk 100%", Help: "Shows usage\nin detail"}
+
+	entry := DesktopEntry(node, l)
+	for _, want := range []string{
+		"Name=Disk 100%\n",
+		"Comment=Shows usage\n",
+		`Exec="/opt/menu works/menuworks" run -config /etc/menu.yaml "Tools/Disk 100%%"` + "\n",
+		"Terminal=true\n",
+	} {
+		if !strings.Contains(entry, want) {
+			t.Errorf("expected %q in desktop entry:\n%s", want, entry)
+		}
+	}
+
+	node.Path = "Tools/Bob's Disk"
+	script := CommandScript(node, l)
+	if !strings.Contains(script, `exec '/opt/menu works/menuworks' run -config /etc/menu.yaml 'Tools/Bob'\''s Disk'`) {
+		t.Errorf("unexpected command script:\n%s", script)
+	}
+
+	// A newline in the label must not end the comment and start a command
+	node.Label = "Disk\nrm -rf ~"
+	script = CommandScript(node, l)
+	if lines := strings.Split(script, "\n"); len(lines) != 4 || lines[1] != "# Disk rm -rf ~ (MenuWorks shortcut)" {
+		t.Errorf("expected the label kept on the comment line, got:\n%s", script)
+	}
+
+	if got := shortcutFileName(`Games/Portal: "2"`); got != "Games-Portal- -2-" {
+		t.Errorf("unexpected file name %q", got)
+	}
+}
+
+func TestWriteShortcutsDesktop(t *testing.T) {
+	dir := t.TempDir()
+	files, err := WriteShortcuts(dir, "linux", Launcher{Exe: "/usr/bin/menuworks", ConfigPath: "/etc/menu.yaml"}, testTree())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(files) != 1 || !strings.HasSuffix(files[0], "menuworks-Games-Portal -2-.desktop") {
+		t.Errorf("unexpected files %v", files)
+	}
+}
@@ -0,0 +1,198 @@
+package export
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+
+	"github.com/benworks/menuworks/config"
+	"github.com/benworks/menuworks/menu"
+)
+
+// Palette is the CSS colors of a launcher page, taken from a menu theme
+type Palette struct {
+	Background, Text, Border, HighlightBg, HighlightFg, Hotkey, Disabled, MenuBg string
+}
+
+// defaultPalette matches the TUI's default blue theme
+var defaultPalette = Palette{
+	Background:  "#0000ff",
+	Text:        "#bcbcbc",
+	Border:      "#00ffff",
+	HighlightBg: "#0000ff",
+	HighlightFg: "#ffffff",
+	Hotkey:      "#ffff00",
+	Disabled:    "#585858",
+	MenuBg:      "#000080",
+}
+
+// PaletteFor converts theme colors to CSS. A nil theme, or a color that is
+// unset or has no RGB value (e.g. "default"), uses the default theme's color.
+func PaletteFor(theme *config.ThemeColors) Palette {
+	p := defaultPalette
+	if theme == nil {
+		return p
+	}
+	css := func(name, fallback string) string {
+		c, ok := config.ParseColorName(name)
+		if !ok || c == tcell.ColorDefault || c.Hex() < 0 {
+			return fallback
+		}
+		return fmt.Sprintf("#%06x", c.Hex())
+	}
+	p.Background = css(theme.Background, p.Background)
+	p.Text = css(theme.Text, p.Text)
+	p.Border = css(theme.Border, p.Border)
+	p.HighlightBg = css(theme.HighlightBg, p.HighlightBg)
+	p.HighlightFg = css(theme.HighlightFg, p.HighlightFg)
+	p.Hotkey = css(theme.Hotkey, p.Hotkey)
+	p.Disabled = css(theme.Disabled, p.Disabled)
+	if theme.MenuBg != "" {
+		p.MenuBg = css(theme.MenuBg, p.MenuBg)
+	} else {
+		p.MenuBg = p.Background
+	}
+	return p
+}
+
+// openers are commands that hand their argument to the OS, so a browser can
+// do the same with a link. "steam" accepts steam:// URLs the same way.
+var openers = map[string]bool{
+	"start": true, "open": true, "xdg-open": true, "explorer": true,
+	"explorer.exe": true, "steam": true,
+}
+
+// urlPattern matches a URL with a scheme, e.g. steam://rungameid/620
+var urlPattern = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9+.-]*://\S+$`)
+
+// LaunchLink returns a link that opens what command opens, or "" if the
+// command is not a plain URL or a single opener call ("start steam://...",
+// "xdg-open /srv/report.pdf"). Other commands can only run in a terminal.
+func LaunchLink(command string) string {
+	fields := strings.Fields(command)
+	if len(fields) >= 2 && strings.EqualFold(fields[0], "cmd") && strings.EqualFold(fields[1], "/c") {
+		fields = fields[2:]
+	}
+	opened := false
+	if len(fields) > 0 && openers[strings.ToLower(fields[0])] {
+		fields = fields[1:]
+		opened = true
+		// start treats a leading quoted argument as the window title
+		if len(fields) > 1 && fields[0] == `""` {
+			fields = fields[1:]
+		}
+	}
+	if len(fields) != 1 {
+		return ""
+	}
+	target := strings.Trim(fields[0], `"'`)
+
+	if urlPattern.MatchString(target) {
+		if u, err := url.Parse(target); err == nil && u.Scheme != "javascript" {
+			return target
+		}
+		return ""
+	}
+	if !opened {
+		return ""
+	}
+	// Absolute paths become file:// links
+	if strings.HasPrefix(target, "/") {
+		return (&url.URL{Scheme: "file", Path: target}).String()
+	}
+	if len(target) > 2 && target[1] == ':' && (target[2] == '\\' || target[2] == '/') {
+		return (&url.URL{Scheme: "file", Path: "/" + strings.ReplaceAll(target, `\`, "/")}).String()
+	}
+	return ""
+}
+
+// launcherTemplate renders each menu as a panel of buttons; links open
+// directly, other commands are shown so they can be copied into a terminal
+var launcherTemplate = template.Must(template.New("launcher").Funcs(template.FuncMap{
+	"link":   func(command string) template.URL { return template.URL(LaunchLink(command)) },
+	"anchor": anchor,
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Title}}</title>
+<style>
+body { background: {{.Palette.Background}}; color: {{.Palette.Text}}; font-family: "Cascadia Mono", Consolas, "DejaVu Sans Mono", monospace; margin: 0; padding: 2em; }
+h1 { text-align: center; color: {{.Palette.HighlightFg}}; }
+section { background: {{.Palette.MenuBg}}; border: 4px double {{.Palette.Border}}; max-width: 40em; margin: 1.5em auto; padding: 0 1em 1em; box-shadow: 0.6em 0.6em 0 #000; }
+h2 { color: {{.Palette.Border}}; text-align: center; margin: 0 0 .5em; position: relative; top: -.7em; }
+h2 span { background: {{.Palette.MenuBg}}; padding: 0 .5em; }
+ul { list-style: none; padding: 0; margin: 0; }
+li { margin: .3em 0; }
+a, .item { display: block; padding: .2em .6em; color: {{.Palette.Text}}; text-decoration: none; }
+a:hover, a:focus { background: {{.Palette.HighlightBg}}; color: {{.Palette.HighlightFg}}; outline: 1px solid {{.Palette.Border}}; }
+kbd { color: {{.Palette.Hotkey}}; font-family: inherit; }
+code { color: {{.Palette.Disabled}}; user-select: all; }
+.disabled { color: {{.Palette.Disabled}}; }
+.help { color: {{.Palette.Disabled}}; padding-left: 1.6em; font-size: .9em; white-space: pre-wrap; }
+hr { border: 0; border-top: 1px solid {{.Palette.Border}}; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+{{template "menu" .Root}}
+</body>
+</html>
+{{define "menu"}}<section id="{{.Anchor}}">
+<h2><span>{{.Heading}}</span></h2>
+<ul>
+{{range .Nodes}}{{if eq .Type "separator"}}<hr>
+{{else if or (eq .Type "back") (eq .Type "quit") (eq .Type "edit_config") (eq .Type "fallback_shell") (eq .Type "switch_role") (eq .Type "browse")}}{{else}}<li>{{$link := link (or .URL .Exec)}}{{if .Disabled}}<span class="item disabled">{{.Label}} (unavailable)</span>{{else if eq .Type "submenu"}}<a href="#{{anchor .Path}}">{{template "label" .}} ▸</a>{{else if $link}}<a href="{{$link}}">{{template "label" .}}</a>{{else}}<span class="item">{{template "label" .}} <code>{{.Exec}}</code></span>{{end}}{{if .Help}}
+<div class="help">{{.Help}}</div>{{end}}</li>
+{{end}}{{end}}</ul>
+</section>
+{{range .Submenus}}{{template "menu" .}}{{end}}{{end}}
+{{define "label"}}{{if .Hotkey}}<kbd>{{.Hotkey}}</kbd> {{end}}{{.Label}}{{end}}`))
+
+// launcherMenu is one menu panel in the launcher template
+type launcherMenu struct {
+	Heading  string
+	Anchor   string
+	Nodes    []menu.Node
+	Submenus []launcherMenu
+}
+
+// newLauncherMenu builds the panel for nodes and, recursively, their submenus
+func newLauncherMenu(heading, path string, nodes []menu.Node) launcherMenu {
+	m := launcherMenu{Heading: heading, Anchor: anchor(path), Nodes: nodes}
+	for _, node := range nodes {
+		if len(node.Children) > 0 && !node.Disabled {
+			m.Submenus = append(m.Submenus, newLauncherMenu(node.Path, node.Path, node.Children))
+		}
+	}
+	return m
+}
+
+// anchor turns a menu path into an HTML id
+func anchor(path string) string {
+	if path == "" {
+		return "menu"
+	}
+	return "menu-" + strings.Map(func(r rune) rune {
+		if r == ' ' || r == '/' {
+			return '-'
+		}
+		return r
+	}, strings.ToLower(path))
+}
+
+// LauncherPage writes the menu tree as a themed, standalone HTML page. Commands
+// that open a URL or file become links; the rest show the command to run.
+func LauncherPage(w io.Writer, title string, tree []menu.Node, palette Palette) error {
+	return launcherTemplate.Execute(w, struct {
+		Title   string
+		Palette Palette
+		Root    launcherMenu
+	}{title, palette, newLauncherMenu("Main Menu", "", tree)})
+}
@@ -0,0 +1,64 @@
+package export
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/benworks/menuworks/config"
+)
+
+func TestLaunchLink(t *testing.T) {
+	for command, want := range map[string]string{
+		"steam://rungameid/620":                "steam://rungameid/620",
+		"start steam://rungameid/620":          "steam://rungameid/620",
+		`cmd /c start "" steam://run/620`:      "steam://run/620",
+		"steam steam://rungameid/620":          "steam://rungameid/620",
+		"xdg-open /srv/report.pdf":             "file:///srv/report.pdf",
+		`explorer C:\Games`:                    "file:///C:/Games",
+		"open https://example.com/docs":        "https://example.com/docs",
+		"ls -la":                               "",
+		"/usr/bin/htop":                        "",
+		"xdg-open javascript://alert(1)":       "",
+		"start steam://run/620 extra-argument": "",
+	} {
+		if got := LaunchLink(command); got != want {
+			t.Errorf("LaunchLink(%q) = %q, want %q", command, got, want)
+		}
+	}
+}
+
+func TestPaletteFor(t *testing.T) {
+	if p := PaletteFor(nil); p != defaultPalette {
+		t.Errorf("expected the default palette for no theme, got %+v", p)
+	}
+	p := PaletteFor(&config.ThemeColors{Background: "black", Text: "white", Border: "default"})
+	if p.Background != "#000000" || p.Text != "#ffffff" {
+		t.Errorf("expected theme colors, got %+v", p)
+	}
+	if p.Border != defaultPalette.Border || p.MenuBg != "#000000" {
+		t.Errorf("expected fallbacks for unset colors, got %+v", p)
+	}
+}
+
+func TestLauncherPage(t *testing.T) {
+	var b strings.Builder
+	palette := PaletteFor(&config.ThemeColors{Background: "black"})
+	if err := LauncherPage(&b, "Kiosk", testTree(), palette); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	out := b.String()
+	for _, want := range []string{
+		"body { background: #000000;",
+		`<a href="#menu-games"><kbd>G</kbd> Games ▸</a>`,
+		`<section id="menu-games">`,
+		`<a href="steam://run/620"><kbd>P</kbd> Portal &lt;2&gt;</a>`,
+		`<a href="https://example.com/docs"><kbd>D</kbd> Docs</a>`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in output:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "Exit") || strings.Contains(out, "ROMs") {
+		t.Errorf("expected back and browse items to be left out:\n%s", out)
+	}
+}
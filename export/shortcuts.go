@@ -0,0 +1,199 @@
+package export
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/benworks/menuworks/menu"
+	"github.com/benworks/menuworks/shell"
+)
+
+// Launcher describes how a shortcut starts an item: the menuworks binary and
+// the config it should load. Shortcuts call `menuworks run`, so the item keeps
+// its working directory and per-OS command.
+type Launcher struct {
+	Exe        string // absolute path to the menuworks binary
+	ConfigPath string // absolute path to the config containing the items
+}
+
+// args returns the command-line arguments that run the item at path
+func (l Launcher) args(path string) []string {
+	return []string{"run", "-config", l.ConfigPath, path}
+}
+
+// DefaultShortcutDir returns where the OS launcher picks up shortcuts:
+// the XDG applications directory on Linux and the Desktop elsewhere.
+func DefaultShortcutDir(goos string) (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	if goos == "linux" {
+		if dataHome := os.Getenv("XDG_DATA_HOME"); dataHome != "" {
+			return filepath.Join(dataHome, "applications"), nil
+		}
+		return filepath.Join(home, ".local", "share", "applications"), nil
+	}
+	return filepath.Join(home, "Desktop"), nil
+}
+
+// WriteShortcuts writes one shortcut per enabled command node into dir, in the
+// format for goos: .desktop entries (linux), .lnk shortcuts (windows), or
+// double-clickable .command scripts (darwin). Returns the files written.
+func WriteShortcuts(dir, goos string, l Launcher, nodes []menu.Node) ([]string, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create shortcut directory: %w", err)
+	}
+
+	var written []string
+	for _, node := range commandNodes(nodes) {
+		name := shortcutFileName(node.Path)
+		var file string
+		var err error
+		switch goos {
+		case "linux":
+			file = filepath.Join(dir, "menuworks-"+name+".desktop")
+			err = os.WriteFile(file, []byte(DesktopEntry(node, l)), 0755)
+		case "windows":
+			file = filepath.Join(dir, name+".lnk")
+			err = writeWindowsShortcut(file, node, l)
+		case "darwin":
+			file = filepath.Join(dir, name+".command")
+			err = os.WriteFile(file, []byte(CommandScript(node, l)), 0755)
+		default:
+			return written, fmt.Errorf("shortcuts are not supported on %s", goos)
+		}
+		if err != nil {
+			return written, fmt.Errorf("failed to write shortcut for '%s': %w", node.Path, err)
+		}
+		written = append(written, file)
+	}
+	return written, nil
+}
+
+// commandNodes flattens the tree to its enabled command items
+func commandNodes(nodes []menu.Node) []menu.Node {
+	var commands []menu.Node
+	for _, node := range nodes {
+		if node.Type == "command" && !node.Disabled {
+			commands = append(commands, node)
+		}
+		commands = append(commands, commandNodes(node.Children)...)
+	}
+	return commands
+}
+
+// Commands returns the enabled command items to export. Each selector is an
+// item path (matched case-insensitively) or id; with no selectors every command is returned.
+func Commands(nodes []menu.Node, selectors []string) ([]menu.Node, error) {
+	all := commandNodes(nodes)
+	if len(selectors) == 0 {
+		return all, nil
+	}
+
+	var selected []menu.Node
+	for _, selector := range selectors {
+		found := false
+		for _, node := range all {
+			if strings.EqualFold(node.Path, selector) || (node.ID != "" && node.ID == selector) {
+				selected = append(selected, node)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("no runnable command '%s'", selector)
+		}
+	}
+	return selected, nil
+}
+
+// shortcutFileName turns an item path into a file name that is valid on every OS
+func shortcutFileName(path string) string {
+	name := strings.Map(func(r rune) rune {
+		switch r {
+		case '/', '\\', ':', '*', '?', '"', '<', '>', '|':
+			return '-'
+		}
+		if r < ' ' {
+			return -1
+		}
+		return r
+	}, path)
+	return strings.Trim(name, " .")
+}
+
+// DesktopEntry returns a freedesktop.org .desktop file that runs node in a terminal
+func DesktopEntry(node menu.Node, l Launcher) string {
+	var b strings.Builder
+	b.WriteString("[Desktop Entry]\n")
+	b.WriteString("Type=Application\n")
+	fmt.Fprintf(&b, "Name=%s\n", desktopValue(node.Label))
+	if node.Help != "" {
+		fmt.Fprintf(&b, "Comment=%s\n", desktopValue(strings.SplitN(strings.TrimSpace(node.Help), "\n", 2)[0]))
+	}
+	fmt.Fprintf(&b, "Exec=%s\n", DesktopExec(append([]string{l.Exe}, l.args(node.Path)...)))
+	b.WriteString("Terminal=true\n")
+	b.WriteString("Categories=Utility;\n")
+	return b.String()
+}
+
+// DesktopExec returns the value of a .desktop Exec key that runs argv
+func DesktopExec(argv []string) string {
+	quoted := make([]string, len(argv))
+	for i, arg := range argv {
+		quoted[i] = desktopQuote(arg)
+	}
+	return strings.Join(quoted, " ")
+}
+
+// desktopValue escapes a string value for a .desktop key
+func desktopValue(s string) string {
+	return strings.NewReplacer("\\", "\\\\", "\n", "\\n", "\t", "\\t").Replace(s)
+}
+
+// desktopQuote quotes one Exec argument following the Desktop Entry spec.
+// '%' is doubled so it is not read as a field code.
+func desktopQuote(arg string) string {
+	arg = strings.ReplaceAll(arg, "%", "%%")
+	if !strings.ContainsAny(arg, " \t\"'\\><~|&;$*?#()`") {
+		return desktopValue(arg)
+	}
+	quoted := strings.NewReplacer(`"`, `\"`, "`", "\\`", "$", `\$`, `\`, `\\`).Replace(arg)
+	return desktopValue(`"` + quoted + `"`)
+}
+
+// CommandScript returns a macOS .command script that runs node when double-clicked in Finder
+func CommandScript(node menu.Node, l Launcher) string {
+	args := shell.Quote("darwin", append([]string{l.Exe}, l.args(node.Path)...))
+	// A label may span lines; keep it all on the comment line
+	label := strings.NewReplacer("\r\n", " ", "\n", " ", "\r", " ").Replace(node.Label)
+	return fmt.Sprintf("#!/bin/sh\n# %s (MenuWorks shortcut)\nexec %s\n", label, args)
+}
+
+// writeWindowsShortcut creates a .lnk file that runs node
+func writeWindowsShortcut(file string, node menu.Node, l Launcher) error {
+	return WriteWindowsShortcut(file, l.Exe, l.args(node.Path), node.Label)
+}
+
+// WriteWindowsShortcut creates a .lnk file that runs exe with args, through
+// the WScript.Shell COM object
+func WriteWindowsShortcut(file, exe string, args []string, description string) error {
+	psQuote := func(s string) string { return "'" + strings.ReplaceAll(s, "'", "''") + "'" }
+	script := strings.Join([]string{
+		"$s = (New-Object -ComObject WScript.Shell).CreateShortcut(" + psQuote(file) + ")",
+		"$s.TargetPath = " + psQuote(exe),
+		"$s.Arguments = " + psQuote(shell.Quote("windows", args)),
+		"$s.Description = " + psQuote(description),
+		"$s.Save()",
+	}, "; ")
+
+	out, err := exec.Command("powershell", "-NoProfile", "-NonInteractive", "-Command", script).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%w: %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
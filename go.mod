@@ -4,15 +4,18 @@ go 1.21
 
 require (
 	github.com/gdamore/tcell/v2 v2.7.4
+	github.com/gliderlabs/ssh v0.3.7
+	github.com/mattn/go-runewidth v0.0.15
+	github.com/rivo/uniseg v0.4.7
+	golang.org/x/crypto v0.17.0
+	golang.org/x/term v0.17.0
 	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
+	github.com/anmitsu/go-shlex v0.0.0-20200514113438-38f4b401e2be // indirect
 	github.com/gdamore/encoding v1.0.0 // indirect
 	github.com/lucasb-eyer/go-colorful v1.2.0 // indirect
-	github.com/mattn/go-runewidth v0.0.15 // indirect
-	github.com/rivo/uniseg v0.4.7 // indirect
 	golang.org/x/sys v0.17.0 // indirect
-	golang.org/x/term v0.17.0 // indirect
 	golang.org/x/text v0.14.0 // indirect
 )
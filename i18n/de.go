@@ -0,0 +1,171 @@
+package i18n
+
+// german is the German ("de") catalog
+var german = map[string]string{
+	// Footer hints and buttons
+	"Navigate": "Navigieren",
+	"Select":   "Auswählen",
+	"Exit":     "Beenden",
+	"Back":     "Zurück",
+	"Reload":   "Neu laden",
+	"Help":     "Hilfe",
+	"OK":       "OK",
+	"Yes":      "Ja",
+	"No":       "Nein",
+	"Retry":    "Wiederholen",
+	"Details":  "Details",
+
+	"Use Default":                 "Standard verwenden",
+	"Open in Editor":              "Bearbeiten",
+	"ENTER: Select | ESC: Cancel": "ENTER: Auswählen | ESC: Abbrechen",
+	"ENTER: Apply | ESC: Cancel":  "ENTER: Übernehmen | ESC: Abbrechen",
+	"ESC: Cancel":                 "ESC: Abbrechen",
+	"Press any key to return":     "Beliebige Taste drücken, um zurückzukehren",
+
+	"Lines %d-%d of %d | ↑↓ or PgUp/PgDn to scroll": "Zeilen %d-%d von %d | ↑↓ oder Bild↑/Bild↓ zum Blättern",
+
+	// Menus and screens
+	"(unavailable)":                          "(nicht verfügbar)",
+	"(menu not found)":                       "(Menü nicht gefunden)",
+	"(expanded)":                             "(ausgeklappt)",
+	"(collapsed)":                            "(eingeklappt)",
+	"This item is not available.":            "Dieser Eintrag ist nicht verfügbar.",
+	"Confirm":                                "Bestätigen",
+	"Run %s?":                                "%s ausführen?",
+	"(Stopped after the %s timeout)":         "(Nach Ablauf von %s abgebrochen)",
+	"(No items)":                             "(Keine Einträge)",
+	"Command Output":                         "Befehlsausgabe",
+	"Command:":                               "Befehl:",
+	"Item Info":                              "Eintragsinfo",
+	"Command Preview":                        "Befehlsvorschau",
+	"(No command defined for this platform)": "(Kein Befehl für diese Plattform definiert)",
+	"Variant: exec.%s":                       "Variante: exec.%s",
+	"none (the program is run directly)":     "keine (das Programm wird direkt gestartet)",
+	"Shell: %s":                              "Shell: %s",
+	"Directory: %s":                          "Verzeichnis: %s",
+	"current directory (%s)":                 "aktuelles Verzeichnis (%s)",
+	"Launch: %s":                             "Start: %s",
+	"Run as: %s (sudo)":                      "Ausführen als: %s (sudo)",
+	"Input: %s":                              "Eingabe: %s",
+	"none":                                   "keine",
+	"typed in when run":                      "wird beim Ausführen eingegeben",
+	"file %s":                                "Datei %s",
+	"%d bytes of text":                       "%d Byte Text",
+	"Environment: inherited from MenuWorks":  "Umgebung: von MenuWorks geerbt",
+	"Themes":                                 "Farbschemata",
+	"Preview":                                "Vorschau",
+	"Normal item":                            "Normaler Eintrag",
+	"Selected item":                          "Ausgewählter Eintrag",
+	"Hotkey item":                            "Tastenkürzel-Eintrag",
+	"Disabled item":                          "Deaktivierter Eintrag",
+	"Submenu":                                "Untermenü",
+	"Profiles":                               "Profile",
+	"Version: %s":                            "Version: %s",
+	"A Retro DOS-Style TUI":                  "Eine Text-Oberfläche im Retro-DOS-Stil",
+	"Update %s available":                    "Update %s verfügbar",
+
+	// Dialogs
+	"Quit":                             "Beenden",
+	"Quit MenuWorks?":                  "MenuWorks beenden?",
+	"Error":                            "Fehler",
+	"Error: %v":                        "Fehler: %v",
+	"First Run":                        "Erster Start",
+	"Command Failed":                   "Befehl fehlgeschlagen",
+	"%d of %d succeeded":               "%d von %d erfolgreich",
+	"Exit code %d":                     "Exit-Code %d",
+	"Did not finish":                   "Nicht beendet",
+	"Command Executed":                 "Befehl ausgeführt",
+	"Command finished successfully.":   "Der Befehl wurde erfolgreich beendet.",
+	"(Interrupted with Ctrl+C)":        "(Mit Strg+C abgebrochen)",
+	"Copied":                           "Kopiert",
+	"Command copied to the clipboard.": "Befehl in die Zwischenablage kopiert.",
+	"Command sent to the terminal's clipboard.": "Befehl an die Zwischenablage des Terminals gesendet.",
+	"Clipboard Error":              "Fehler beim Kopieren",
+	"Launch Error":                 "Startfehler",
+	"Input for %s":                 "Eingabe für %s",
+	"Ctrl+D to run, ESC to cancel": "Strg+D zum Ausführen, ESC zum Abbrechen",
+	"Terminal Too Small":           "Terminal zu klein",
+	"Current size: %d×%d":          "Aktuelle Größe: %d×%d",
+	"Config Error":                 "Konfigurationsfehler",
+	"Config Reloaded":              "Konfiguration neu geladen",
+	"Reload Details":               "Details zum Neuladen",
+	"Editor Error":                 "Editor-Fehler",
+	"Not Available":                "Nicht verfügbar",
+	"Config Unchanged":             "Konfiguration unverändert",
+	"Config Updated":               "Konfiguration aktualisiert",
+	"Reload Error":                 "Fehler beim Neuladen",
+	"Backup Error":                 "Sicherungsfehler",
+	"Hotkey Conflicts":             "Tastenkürzel-Konflikte",
+	"Assign Hotkey":                "Tastenkürzel zuweisen",
+	"Hotkey Reserved":              "Tastenkürzel reserviert",
+	"Hotkey In Use":                "Tastenkürzel belegt",
+	"Hotkey Error":                 "Tastenkürzel-Fehler",
+	"Theme Error":                  "Farbschema-Fehler",
+	"Profile Error":                "Profilfehler",
+
+	"Menus added: %d":                          "Hinzugefügte Menüs: %d",
+	"Menus removed: %d":                        "Entfernte Menüs: %d",
+	"Menus with more or fewer items: %d":       "Menüs mit mehr oder weniger Einträgen: %d",
+	"Warnings: %d":                             "Warnungen: %d",
+	"No menus or items were added or removed.": "Es wurden keine Menüs oder Einträge hinzugefügt oder entfernt.",
+	"Reloaded %s":                              "%s neu geladen",
+	"Menus added:":                             "Hinzugefügte Menüs:",
+	"Menus removed:":                           "Entfernte Menüs:",
+	"Item counts:":                             "Anzahl der Einträge:",
+	"Warnings:":                                "Warnungen:",
+	"(root menu)":                              "(Hauptmenü)",
+
+	"The config can only be edited at this machine's terminal, not in a remote session.": "Die Konfiguration kann nur am Terminal dieses Rechners bearbeitet werden, nicht in einer entfernten Sitzung.",
+	"This menu was not loaded from a config file.":                                       "Dieses Menü wurde nicht aus einer Konfigurationsdatei geladen.",
+
+	"(not available on this OS)":                                                     "(auf diesem Betriebssystem nicht verfügbar)",
+	"Please resize your terminal to at least 80×25":                                  "Bitte vergrößern Sie das Terminal auf mindestens 80×25",
+	"Configuration reloaded successfully.":                                           "Die Konfiguration wurde erfolgreich neu geladen.",
+	"The config file has not changed since it was loaded.":                           "Die Konfigurationsdatei wurde seit dem Laden nicht geändert.",
+	"Failed to reload config: %v":                                                    "Konfiguration konnte nicht neu geladen werden: %v",
+	"Failed to load configuration.\nError:\n%v":                                      "Konfiguration konnte nicht geladen werden.\nFehler:\n%v",
+	"Failed to save hotkey: %v":                                                      "Tastenkürzel konnte nicht gespeichert werden: %v",
+	"Failed to load profile '%s': %v":                                                "Profil '%s' konnte nicht geladen werden: %v",
+	"Theme applied but could not be saved: %v":                                       "Farbschema angewendet, aber nicht gespeichert: %v",
+	"Press a new hotkey for '%s'.":                                                   "Neues Tastenkürzel für '%s' drücken.",
+	"Hotkey %s is already used by '%s'.":                                             "Tastenkürzel %s wird bereits von '%s' verwendet.",
+	"%s is reserved for reloading the config.":                                       "%s ist für das Neuladen der Konfiguration reserviert.",
+	"Press F4 on an item to reassign its hotkey.":                                    "F4 auf einem Eintrag drücken, um sein Tastenkürzel zu ändern.",
+	"The specified configuration file was not found:\n%s":                            "Die angegebene Konfigurationsdatei wurde nicht gefunden:\n%s",
+	"No other profiles found. Add more .yaml files to %s to switch between them.":    "Keine weiteren Profile gefunden. Legen Sie weitere .yaml-Dateien in %s ab, um zwischen ihnen zu wechseln.",
+	"Could not set the broken config aside: %v":                                      "Die fehlerhafte Konfiguration konnte nicht beiseitegelegt werden: %v",
+	"Default config written. Your config was moved to %s, so nothing in it is lost.": "Standardkonfiguration geschrieben. Ihre Konfiguration wurde nach %s verschoben, es geht also nichts verloren.",
+	"A configuration file could not be found, so one has been created for you at %s. Edit this file to modify menu items. Press \"%s\" to reload it.": "Es wurde keine Konfigurationsdatei gefunden, daher wurde eine unter %s angelegt. Bearbeiten Sie diese Datei, um Menüeinträge zu ändern. Drücken Sie \"%s\", um sie neu zu laden.",
+	"'%s' uses launch: %s, which needs this machine's terminal and cannot run in a remote session.":                                                   "'%s' verwendet launch: %s, das das Terminal dieses Rechners braucht und in einer entfernten Sitzung nicht laufen kann.",
+	"Hotkey %s…": "Tastenkürzel %s…",
+
+	"Locked Menu":                       "Gesperrtes Menü",
+	"User:":                             "Benutzer:",
+	"Password:":                         "Passwort:",
+	"Checking…":                         "Wird geprüft…",
+	"Enter to sign in, ESC to cancel":   "Enter zum Anmelden, ESC zum Abbrechen",
+	"%s is locked. Sign in to open it.": "%s ist gesperrt. Melden Sie sich an, um es zu öffnen.",
+	"%s may not open this menu.":        "%s darf dieses Menü nicht öffnen.",
+	"Wrong user name or password.":      "Falscher Benutzername oder falsches Passwort.",
+
+	"Menu Restarted": "Menü neu gestartet",
+	"Something went wrong, so the menu started over. The details are in the log.": "Etwas ist schiefgegangen, daher wurde das Menü neu gestartet. Einzelheiten stehen im Protokoll.",
+	"A fallback shell can't be opened in a remote session.":                       "In einer entfernten Sitzung kann keine Ersatz-Shell geöffnet werden.",
+	"Invalid fallback shell: %s":                                                  "Ungültige Ersatz-Shell: %s",
+
+	"available %s":            "verfügbar %s",
+	"%s can only be used %s.": "%s kann nur %s verwendet werden.",
+
+	"Switch Role":                          "Rolle wechseln",
+	"PIN:":                                 "PIN:",
+	"Enter the PIN for %s.":                "Geben Sie die PIN für %s ein.",
+	"Wrong PIN.":                           "Falsche PIN.",
+	"The PIN for %s could not be read: %v": "Die PIN für %s konnte nicht gelesen werden: %v",
+
+	"'%s' opens a link on this machine and cannot be used in a remote session.": "'%s' öffnet einen Link auf diesem Rechner und kann in einer entfernten Sitzung nicht verwendet werden.",
+
+	"(No files)":                        "(Keine Dateien)",
+	"Can't read this folder: %v":        "Dieser Ordner kann nicht gelesen werden: %v",
+	"ENTER: Open | ←: Up | ESC: Cancel": "ENTER: Öffnen | ←: Nach oben | ESC: Abbrechen",
+	"Can't browse %s: %v":               "%s kann nicht durchsucht werden: %v",
+}
@@ -0,0 +1,109 @@
+// Package i18n translates MenuWorks' built-in UI text: dialog titles, status
+// messages, and footer hints. Menu labels come from the user's config and are
+// never translated. Messages are looked up by their English text, so a
+// missing translation falls back to English rather than to a key name.
+package i18n
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync/atomic"
+)
+
+// Default is the locale of the untranslated messages
+const Default = "en"
+
+// catalogs maps a locale to its translations, keyed by the English message
+var catalogs = map[string]map[string]string{
+	"de": german,
+}
+
+// active holds the catalog in use; nil means English
+var active atomic.Pointer[map[string]string]
+
+// Locales returns the supported locales, English first
+func Locales() []string {
+	locales := []string{Default}
+	var others []string
+	for locale := range catalogs {
+		others = append(others, locale)
+	}
+	sort.Strings(others)
+	return append(locales, others...)
+}
+
+// Supported reports whether locale (e.g. "de" or "de_DE.UTF-8") has a catalog
+func Supported(locale string) bool {
+	_, ok := match(locale)
+	return ok
+}
+
+// match returns the catalog locale for a locale name, trying the full name
+// ("pt_br") before the language ("pt")
+func match(locale string) (string, bool) {
+	locale = strings.ToLower(locale)
+	if i := strings.IndexAny(locale, ".@"); i >= 0 {
+		locale = locale[:i]
+	}
+	locale = strings.ReplaceAll(locale, "-", "_")
+	if locale == Default || locale == "c" || locale == "posix" {
+		return Default, true
+	}
+	if _, ok := catalogs[locale]; ok {
+		return locale, true
+	}
+	if lang, _, found := strings.Cut(locale, "_"); found {
+		if lang == Default {
+			return Default, true
+		}
+		if _, ok := catalogs[lang]; ok {
+			return lang, true
+		}
+	}
+	return "", false
+}
+
+// Detect returns the locale to use: configured if set, otherwise the first
+// of LC_ALL, LC_MESSAGES, and LANG that is set. Unsupported locales fall back
+// to English.
+func Detect(configured string) string {
+	locale := configured
+	for _, name := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		if locale != "" {
+			break
+		}
+		locale = os.Getenv(name)
+	}
+	if m, ok := match(locale); ok {
+		return m
+	}
+	return Default
+}
+
+// SetLocale switches the messages returned by T and Tf. Unsupported locales
+// select English.
+func SetLocale(locale string) {
+	m, _ := match(locale)
+	if catalog, ok := catalogs[m]; ok {
+		active.Store(&catalog)
+		return
+	}
+	active.Store(nil)
+}
+
+// T returns msg in the current locale, or msg itself if it has no translation
+func T(msg string) string {
+	if catalog := active.Load(); catalog != nil {
+		if translated, ok := (*catalog)[msg]; ok {
+			return translated
+		}
+	}
+	return msg
+}
+
+// Tf translates format and formats it with args like fmt.Sprintf
+func Tf(format string, args ...any) string {
+	return fmt.Sprintf(T(format), args...)
+}
@@ -0,0 +1,63 @@
+package i18n
+
+import (
+	"regexp"
+	"testing"
+)
+
+func TestDetect(t *testing.T) {
+	t.Setenv("LC_ALL", "")
+	t.Setenv("LC_MESSAGES", "")
+	t.Setenv("LANG", "de_AT.UTF-8")
+	if got := Detect(""); got != "de" {
+		t.Errorf("expected de from LANG, got %q", got)
+	}
+	if got := Detect("en"); got != "en" {
+		t.Errorf("expected the configured locale to win, got %q", got)
+	}
+	t.Setenv("LC_ALL", "fr_FR.UTF-8")
+	if got := Detect(""); got != Default {
+		t.Errorf("expected English for an unsupported locale, got %q", got)
+	}
+}
+
+func TestTranslate(t *testing.T) {
+	defer SetLocale(Default)
+
+	SetLocale("de-DE")
+	if got := T("Reload"); got != "Neu laden" {
+		t.Errorf("expected German, got %q", got)
+	}
+	if got := T("Not in any catalog"); got != "Not in any catalog" {
+		t.Errorf("expected untranslated text to fall back to English, got %q", got)
+	}
+	if got := Tf("Current size: %d×%d", 70, 20); got != "Aktuelle Größe: 70×20" {
+		t.Errorf("unexpected formatted text %q", got)
+	}
+
+	SetLocale("xx")
+	if got := T("Reload"); got != "Reload" {
+		t.Errorf("expected English for an unsupported locale, got %q", got)
+	}
+}
+
+// verbs matches fmt verbs, which translations must keep in order
+var verbs = regexp.MustCompile(`%[-+# 0-9.]*[a-zA-Z%]`)
+
+func TestCatalogsKeepVerbs(t *testing.T) {
+	for locale, catalog := range catalogs {
+		for msg, translated := range catalog {
+			want, got := verbs.FindAllString(msg, -1), verbs.FindAllString(translated, -1)
+			if len(want) != len(got) {
+				t.Errorf("%s: %q has verbs %v, translation has %v", locale, msg, want, got)
+				continue
+			}
+			for i := range want {
+				if want[i] != got[i] {
+					t.Errorf("%s: %q has verbs %v, translation has %v", locale, msg, want, got)
+					break
+				}
+			}
+		}
+	}
+}
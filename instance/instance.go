@@ -0,0 +1,70 @@
+// Package instance keeps a second MenuWorks from running with the same
+// config, so two menus started by an auto-start entry don't both write its
+// state file. The lock is an OS file lock on a file next to the config: it is
+// released when the process exits, even by crashing, so a leftover file
+// never blocks the next start.
+package instance
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// ErrRunning is returned by Acquire when another process holds the lock
+var ErrRunning = errors.New("MenuWorks is already running")
+
+// errLocked is returned by lockFile when another process holds the lock
+var errLocked = errors.New("locked")
+
+// Lock is a held instance lock
+type Lock struct {
+	f *os.File
+}
+
+// Path returns the lock file for the config at configPath: config.lock for
+// config.yaml, so each profile has its own
+func Path(configPath string) string {
+	base := strings.TrimSuffix(filepath.Base(configPath), filepath.Ext(configPath))
+	return filepath.Join(filepath.Dir(configPath), base+".lock")
+}
+
+// Acquire takes the lock at path and records this process's ID in it. If
+// another process holds it, the error wraps ErrRunning and names that
+// process's ID.
+func Acquire(path string) (*Lock, error) {
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	if err := lockFile(f); err != nil {
+		f.Close()
+		if errors.Is(err, errLocked) {
+			return nil, runningError(path)
+		}
+		return nil, err
+	}
+	if err := f.Truncate(0); err == nil {
+		f.WriteAt([]byte(strconv.Itoa(os.Getpid())+"\n"), 0)
+	}
+	return &Lock{f: f}, nil
+}
+
+// Release gives up the lock. The file is emptied rather than removed, since
+// another process may already have it open and be about to lock it.
+func (l *Lock) Release() {
+	l.f.Truncate(0)
+	l.f.Close()
+}
+
+// runningError returns ErrRunning with the holder's process ID from path
+func runningError(path string) error {
+	data, _ := os.ReadFile(path)
+	if pid, err := strconv.Atoi(strings.TrimSpace(string(data))); err == nil {
+		return fmt.Errorf("%w (process %d)", ErrRunning, pid)
+	}
+	return ErrRunning
+}
@@ -0,0 +1,40 @@
+package instance
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestPath(t *testing.T) {
+	got := Path(filepath.Join("cfg", "games.yaml"))
+	if want := filepath.Join("cfg", "games.lock"); got != want {
+		t.Errorf("expected %s, got %s", want, got)
+	}
+}
+
+func TestAcquireRefusesSecondHolder(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.lock")
+	lock, err := Acquire(path)
+	if err != nil {
+		t.Fatalf("Acquire: %v", err)
+	}
+
+	_, err = Acquire(path)
+	if !errors.Is(err, ErrRunning) {
+		t.Fatalf("expected ErrRunning, got %v", err)
+	}
+	if want := fmt.Sprintf("(process %d)", os.Getpid()); !strings.Contains(err.Error(), want) {
+		t.Errorf("expected the error to name the holder %s, got %v", want, err)
+	}
+
+	lock.Release()
+	again, err := Acquire(path)
+	if err != nil {
+		t.Fatalf("expected the released lock to be free, got %v", err)
+	}
+	again.Release()
+}
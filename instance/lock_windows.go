@@ -0,0 +1,41 @@
+//go:build windows
+
+package instance
+
+import (
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+var (
+	kernel32       = syscall.NewLazyDLL("kernel32.dll")
+	procLockFileEx = kernel32.NewProc("LockFileEx")
+)
+
+const (
+	lockfileFailImmediately = 0x1
+	lockfileExclusiveLock   = 0x2
+
+	errorLockViolation = syscall.Errno(33)
+)
+
+// lockFile takes an exclusive LockFileEx lock on f without waiting for it.
+// Windows locks are mandatory, so the locked byte is far past the process ID
+// at the start of the file, which other processes still need to read.
+func lockFile(f *os.File) error {
+	ol := syscall.Overlapped{OffsetHigh: 1}
+	ok, _, err := procLockFileEx.Call(
+		f.Fd(),
+		lockfileExclusiveLock|lockfileFailImmediately,
+		0,
+		1, 0,
+		uintptr(unsafe.Pointer(&ol)))
+	if ok == 0 {
+		if err == errorLockViolation {
+			return errLocked
+		}
+		return err
+	}
+	return nil
+}
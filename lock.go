@@ -0,0 +1,70 @@
+package menuworks
+
+import (
+	"errors"
+
+	"github.com/benworks/menuworks/auth"
+	"github.com/benworks/menuworks/i18n"
+	"github.com/benworks/menuworks/logging"
+	"github.com/benworks/menuworks/ui"
+)
+
+// verifyLogin checks an OS account's password; tests replace it
+var verifyLogin = auth.Verify
+
+// menuLocked reports whether the menu called name has a lock that hasn't
+// been signed in to this session
+func (a *App) menuLocked(name string) bool {
+	m, exists := a.cfg.Menus[name]
+	return exists && m.Lock != nil && !a.unlocked[name]
+}
+
+// unlockMenu asks for the credentials of an account the menu called name
+// lets in, and calls open once they check out. The password is checked in
+// the background, since PAM may pause after a wrong one.
+func (a *App) unlockMenu(name string, open func()) {
+	m := a.cfg.Menus[name]
+	title := m.Title
+	if title == "" {
+		title = name
+	}
+	user := auth.CurrentUser()
+	if len(m.Lock.Users) == 1 {
+		user = m.Lock.Users[0]
+	}
+
+	var view *ui.LoginView
+	view = ui.NewLoginView(a.screen, i18n.T("Locked Menu"), i18n.Tf("%s is locked. Sign in to open it.", title), user, func(user, password string) {
+		if !auth.Allowed(user, m.Lock.Users) {
+			logging.Warn("menu unlock refused", "menu", name, "user", user)
+			view.Fail(i18n.Tf("%s may not open this menu.", user))
+			return
+		}
+		view.SetBusy()
+		go func() {
+			err := verifyLogin(user, password)
+			a.d.Post(func() {
+				if a.d.Focused() != view {
+					return
+				}
+				if err != nil {
+					logging.Warn("menu unlock failed", "menu", name, "user", user, "err", err)
+					reason := i18n.T("Wrong user name or password.")
+					if !errors.Is(err, auth.ErrDenied) {
+						reason = err.Error()
+					}
+					view.Fail(reason)
+					return
+				}
+				logging.Info("menu unlocked", "menu", name, "user", user)
+				if a.unlocked == nil {
+					a.unlocked = map[string]bool{}
+				}
+				a.unlocked[name] = true
+				a.d.Pop()
+				open()
+			})
+		}()
+	}, a.d.Pop)
+	a.d.Push(view)
+}
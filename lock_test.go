@@ -0,0 +1,105 @@
+package menuworks
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+
+	"github.com/benworks/menuworks/auth"
+	"github.com/benworks/menuworks/config"
+)
+
+// lockedConfig has a root item opening an admin menu only admin may open
+func lockedConfig() *config.Config {
+	return &config.Config{
+		Title: "Root",
+		Items: []config.MenuItem{{Type: "submenu", Label: "Admin", Target: "admin"}},
+		Menus: map[string]config.Menu{
+			"admin": {Title: "Admin", Lock: &config.MenuLock{Auth: "os", Users: []string{"admin"}},
+				Items: []config.MenuItem{{Type: "command", Label: "Reboot", Exec: config.ExecConfig{Linux: "true"}}}},
+		},
+	}
+}
+
+// fakeLogin accepts only admin's password "pw" for the test
+func fakeLogin(t *testing.T) {
+	t.Helper()
+	saved := verifyLogin
+	verifyLogin = func(user, password string) error {
+		if user == "admin" && password == "pw" {
+			return nil
+		}
+		return auth.ErrDenied
+	}
+	t.Cleanup(func() { verifyLogin = saved })
+}
+
+// typeKeys queues a key event for each rune of s
+func typeKeys(events chan<- tcell.Event, s string) {
+	for _, r := range s {
+		events <- tcell.NewEventKey(tcell.KeyRune, r, tcell.ModNone)
+	}
+}
+
+func TestLockedMenuOpensAfterSignIn(t *testing.T) {
+	fakeLogin(t)
+	a, _, events := newTestApp(t, lockedConfig())
+
+	// The only allowed user is filled in, so the password field has focus
+	typeKeys(events, "pw")
+	events <- tcell.NewEventKey(tcell.KeyEnter, 0, tcell.ModNone)
+	(&menuView{a: a}).handleSelection()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := a.d.Run(ctx); err != nil {
+		t.Fatalf("sign-in did not finish: %v", err)
+	}
+	if got := a.navigator.GetCurrentMenuName(); got != "admin" {
+		t.Errorf("expected the admin menu to open, got %q", got)
+	}
+
+	// Signed in once, the menu opens straight away until MenuWorks exits
+	a.navigator.Back()
+	(&menuView{a: a}).handleSelection()
+	if got := a.navigator.GetCurrentMenuName(); got != "admin" || a.d.Focused() != nil {
+		t.Errorf("expected the unlocked menu to open without signing in, got %q", got)
+	}
+}
+
+func TestLockedMenuRefusesOtherUsers(t *testing.T) {
+	fakeLogin(t)
+	a, sim, events := newTestApp(t, lockedConfig())
+
+	events <- tcell.NewEventKey(tcell.KeyUp, 0, tcell.ModNone)
+	events <- tcell.NewEventKey(tcell.KeyCtrlU, 0, tcell.ModNone)
+	typeKeys(events, "eve")
+	events <- tcell.NewEventKey(tcell.KeyEnter, 0, tcell.ModNone)
+	events <- tcell.NewEventKey(tcell.KeyEnter, 0, tcell.ModNone)
+	events <- tcell.NewEventKey(tcell.KeyEscape, 0, tcell.ModNone)
+	(&menuView{a: a}).handleSelection()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := a.d.Run(ctx); err != nil {
+		t.Fatalf("sign-in was not cancelled: %v", err)
+	}
+	if text := screenText(sim); !strings.Contains(text, "eve may not open this menu.") {
+		t.Errorf("expected eve to be refused, got:\n%s", text)
+	}
+	if !a.navigator.IsAtRoot() {
+		t.Errorf("expected to stay in the root menu, got %q", a.navigator.GetCurrentMenuName())
+	}
+}
+
+func TestStartupBacksOutOfLockedMenus(t *testing.T) {
+	a, _, _ := newTestApp(t, lockedConfig())
+	a.navigator.NavigateToMenu("admin")
+	a.navigator.BackOutOf(a.menuLocked)
+	if !a.navigator.IsAtRoot() {
+		t.Errorf("expected the locked menu to be closed, got %q", a.navigator.GetCurrentMenuName())
+	}
+}
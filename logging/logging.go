@@ -0,0 +1,78 @@
+// Package logging is MenuWorks' internal debug log. The terminal belongs to the
+// TUI, so log records only ever go to a file; until Open is called they are
+// discarded. It depends only on the standard library so every package,
+// including the isolated discover packages, can use it.
+package logging
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+)
+
+// logger holds the active *slog.Logger; it discards everything until Open
+var logger atomic.Pointer[slog.Logger]
+
+func init() {
+	logger.Store(slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{Level: slog.LevelError + 1})))
+}
+
+// DefaultPath returns the log file used by --debug: menuworks.log in the
+// per-user cache directory, or the temp directory if there is none
+func DefaultPath() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		dir = os.TempDir()
+	}
+	return filepath.Join(dir, "menuworks", "menuworks.log")
+}
+
+// ParseLevel parses debug, info, warn, or error (case-insensitive)
+func ParseLevel(s string) (slog.Level, bool) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "debug":
+		return slog.LevelDebug, true
+	case "info":
+		return slog.LevelInfo, true
+	case "warn", "warning":
+		return slog.LevelWarn, true
+	case "error":
+		return slog.LevelError, true
+	}
+	return slog.LevelInfo, false
+}
+
+// Open starts appending records at level and above to the file at path.
+// Close the returned file when the program exits.
+func Open(path string, level slog.Level) (io.Closer, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create log directory: %w", err)
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open log file: %w", err)
+	}
+	SetOutput(f, level)
+	return f, nil
+}
+
+// SetOutput sends records at level and above to w
+func SetOutput(w io.Writer, level slog.Level) {
+	logger.Store(slog.New(slog.NewTextHandler(w, &slog.HandlerOptions{Level: level})))
+}
+
+// Debug logs detail useful when diagnosing a problem
+func Debug(msg string, args ...any) { logger.Load().Debug(msg, args...) }
+
+// Info logs a notable event
+func Info(msg string, args ...any) { logger.Load().Info(msg, args...) }
+
+// Warn logs something that went wrong but was handled
+func Warn(msg string, args ...any) { logger.Load().Warn(msg, args...) }
+
+// Error logs a failure
+func Error(msg string, args ...any) { logger.Load().Error(msg, args...) }
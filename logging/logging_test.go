@@ -0,0 +1,42 @@
+package logging
+
+import (
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDiscardedUntilOpened(t *testing.T) {
+	// Nothing is written anywhere before Open; this must not panic or print
+	Error("not written")
+
+	path := filepath.Join(t.TempDir(), "logs", "menuworks.log")
+	f, err := Open(path, slog.LevelInfo)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer SetOutput(os.Stderr, slog.LevelError+1)
+	Debug("hidden detail")
+	Info("config loaded", "path", "/etc/menu.yaml")
+	f.Close()
+
+	data, _ := os.ReadFile(path)
+	out := string(data)
+	if !strings.Contains(out, `msg="config loaded" path=/etc/menu.yaml`) {
+		t.Errorf("expected info record in log, got %q", out)
+	}
+	if strings.Contains(out, "hidden detail") || strings.Contains(out, "not written") {
+		t.Errorf("expected records below the level to be dropped, got %q", out)
+	}
+}
+
+func TestParseLevel(t *testing.T) {
+	if level, ok := ParseLevel("DEBUG"); !ok || level != slog.LevelDebug {
+		t.Errorf("expected debug level, got %v %v", level, ok)
+	}
+	if _, ok := ParseLevel("verbose"); ok {
+		t.Errorf("expected unknown level to be rejected")
+	}
+}
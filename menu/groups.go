@@ -0,0 +1,170 @@
+package menu
+
+import (
+	"slices"
+
+	"github.com/benworks/menuworks/config"
+	"github.com/benworks/menuworks/i18n"
+	"github.com/benworks/menuworks/logging"
+)
+
+// A group is a header item (type: group) that owns the items after it, up to
+// the next separator, group, or the end of the menu. Collapsing the group
+// hides those items in place; expanding shows them again. The state lasts for
+// the session and is never written to the config or the state file.
+
+// refreshMenu recomputes which items of a menu are shown and maps hotkeys onto
+// the shown positions. Call it whenever a group in the menu changes state.
+func (n *Navigator) refreshMenu(menuName string) {
+	items := n.configItems(menuName)
+	visible := make([]int, 0, len(items))
+	shown := make([]config.MenuItem, 0, len(items))
+	grouped := make([]bool, 0, len(items))
+	position := make(map[int]int, len(items))
+	hidden, inGroup := false, false
+	for i, item := range items {
+		if item.Type == "group" || item.Type == "separator" {
+			hidden, inGroup = false, false
+		}
+		if !hidden {
+			position[i] = len(visible)
+			visible = append(visible, i)
+			shown = append(shown, item)
+			grouped = append(grouped, inGroup)
+		}
+		if item.Type == "group" {
+			hidden, inGroup = n.groupCollapsed(menuName, i), true
+		}
+	}
+	n.visible[menuName] = visible
+	n.shown[menuName] = shown
+	n.grouped[menuName] = grouped
+
+	hotkeys := make(map[string]int)
+	for hotkey, configIdx := range n.allHotkeys[menuName] {
+		if idx, ok := position[configIdx]; ok {
+			hotkeys[hotkey] = idx
+		}
+	}
+	n.hotkeyMap[menuName] = hotkeys
+}
+
+// groupCollapsed reports whether the group at configIdx in a menu is collapsed,
+// using the session state if it was toggled and the config's collapsed otherwise
+func (n *Navigator) groupCollapsed(menuName string, configIdx int) bool {
+	if collapsed, ok := n.collapsedGroups[qualifiedKey(menuName, n.itemKeys[menuName][configIdx])]; ok {
+		return collapsed
+	}
+	return n.configItems(menuName)[configIdx].Collapsed
+}
+
+// configItems returns all of a menu's items as configured, including hidden
+// ones. A menu with nothing to select gets a Back item (Quit at the root) in
+// their place, so there is always a way out.
+func (n *Navigator) configItems(menuName string) []config.MenuItem {
+	items := n.configuredItems(menuName)
+	if !isEmptyMenu(items) {
+		return items
+	}
+	if menuName == "root" {
+		return []config.MenuItem{{Type: "quit", Label: i18n.T("Quit")}}
+	}
+	return []config.MenuItem{{Type: "back", Label: i18n.T("Back")}}
+}
+
+// configuredItems returns a menu's items as in the config, less those
+// limited to roles other than the navigator's when it is for a role
+func (n *Navigator) configuredItems(menuName string) []config.MenuItem {
+	items := n.cfg.Items
+	if menuName != "root" {
+		items = n.cfg.Menus[menuName].Items
+	}
+	if !n.forRole {
+		return items
+	}
+	if filtered, ok := n.roleItems[menuName]; ok {
+		return filtered
+	}
+	// Menus where every item is shown keep the config's slice
+	filtered := items
+	if slices.ContainsFunc(items, func(item config.MenuItem) bool { return !item.VisibleTo(n.role) }) {
+		filtered = nil
+		for _, item := range items {
+			if item.VisibleTo(n.role) {
+				filtered = append(filtered, item)
+			}
+		}
+	}
+	n.roleItems[menuName] = filtered
+	return filtered
+}
+
+// isEmptyMenu reports whether items has nothing but separators
+func isEmptyMenu(items []config.MenuItem) bool {
+	for _, item := range items {
+		if item.Type != "separator" {
+			return false
+		}
+	}
+	return true
+}
+
+// ToggleGroup expands or collapses the selected group and reports whether the
+// selection was a group. The selection stays on the group header.
+func (n *Navigator) ToggleGroup() bool {
+	menuName := n.GetCurrentMenuName()
+	idx := n.GetSelectionIndex()
+	items := n.GetCurrentMenu()
+	if idx < 0 || idx >= len(items) || items[idx].Type != "group" {
+		return false
+	}
+	collapsed := !n.IsGroupCollapsed(idx)
+	n.collapsedGroups[qualifiedKey(menuName, n.itemKey(menuName, idx))] = collapsed
+	n.refreshMenu(menuName)
+	logging.Debug("toggle group", "menu", menuName, "label", items[idx].Label, "collapsed", collapsed)
+	return true
+}
+
+// IsGroupCollapsed reports whether the item at index in the current menu is a
+// collapsed group
+func (n *Navigator) IsGroupCollapsed(index int) bool {
+	menuName := n.GetCurrentMenuName()
+	n.prepare(menuName)
+	visible := n.visible[menuName]
+	if index < 0 || index >= len(visible) {
+		return false
+	}
+	items := n.GetCurrentMenu()
+	return items[index].Type == "group" && n.groupCollapsed(menuName, visible[index])
+}
+
+// InGroup reports whether the item at index in the current menu belongs to a
+// group, so it can be drawn indented under the group header
+func (n *Navigator) InGroup(index int) bool {
+	menuName := n.GetCurrentMenuName()
+	n.prepare(menuName)
+	grouped := n.grouped[menuName]
+	return index >= 0 && index < len(grouped) && grouped[index]
+}
+
+// GroupState returns the groups expanded or collapsed this session, for
+// carrying over to a navigator built from a reloaded config
+func (n *Navigator) GroupState() map[string]bool {
+	state := make(map[string]bool, len(n.collapsedGroups))
+	for key, collapsed := range n.collapsedGroups {
+		state[key] = collapsed
+	}
+	return state
+}
+
+// RestoreGroupState applies group state saved by GroupState. Call it before
+// RecallSelection so selections are matched against the items it shows.
+func (n *Navigator) RestoreGroupState(state map[string]bool) {
+	for key, collapsed := range state {
+		n.collapsedGroups[key] = collapsed
+	}
+	// Menus not prepared yet pick up the state when they are
+	for name := range n.prepared {
+		n.refreshMenu(name)
+	}
+}
@@ -1,12 +1,17 @@
 package menu
 
 import (
+	"errors"
 	"fmt"
 	"runtime"
 	"strings"
+	"time"
 	"unicode"
 
+	"github.com/mattn/go-runewidth"
+
 	"github.com/benworks/menuworks/config"
+	"github.com/benworks/menuworks/logging"
 )
 
 // Navigator manages menu navigation state and selection memory
@@ -15,53 +20,100 @@ type Navigator struct {
 	menuPath         []string           // Stack of menu names, e.g., ["root", "system"]
 	selectionIndex   map[string]int    // Remembers selection index for each menu
 	scrollOffset     map[string]int    // Scroll offset per menu for large menus
-	disabledItems    map[string]bool   // Tracks disabled submenu key names (e.g., "system:target_name")
+	disabledItems    map[string]string // Why items are disabled, by menu and item key (e.g., "system:portal-2")
+	itemKeys         map[string][]string // itemKeys[menuName][configIndex] = unique item key
+	allHotkeys       map[string]map[string]int // allHotkeys[menuName][hotkey] = configIndex
+	visible          map[string][]int  // visible[menuName][itemIndex] = configIndex of each item shown
+	shown            map[string][]config.MenuItem // Items shown per menu (collapsed group members hidden)
+	grouped          map[string][]bool // grouped[menuName][itemIndex] = shown item belongs to a group
+	collapsedGroups  map[string]bool   // Groups expanded or collapsed this session, by menu and item key
 	errorReported    map[string]bool   // Track which missing targets have been reported
 	hotkeyMap        map[string]map[string]int // hotkeyMap[menuName][hotkey] = itemIndex
+	prepared         map[string]bool   // Menus whose hotkeys, keys, and disabled items are built
+	now              func() time.Time  // Clock that available_hours and available_days are checked against
+	forRole          bool              // Items limited to roles other than role are left out
+	role             string            // Active role when forRole is set
+	roleItems        map[string][]config.MenuItem // Items per menu with other roles' items left out
+	hiddenMenus      map[string]bool   // Menus the role can't reach, built by Hidden
 }
 
-// NewNavigator creates a new Navigator from a config
+// NewNavigator creates a new Navigator from a config, with every item
+// whatever its roles
 func NewNavigator(cfg *config.Config) *Navigator {
+	return newNavigator(cfg, false, "")
+}
+
+// NewNavigatorForRole creates a new Navigator from a config that shows the
+// items for role: those limited to no role, and those whose roles include
+// it. An empty role sees only the items limited to no role.
+func NewNavigatorForRole(cfg *config.Config, role string) *Navigator {
+	return newNavigator(cfg, true, role)
+}
+
+// newNavigator creates a Navigator, leaving out other roles' items if forRole
+func newNavigator(cfg *config.Config, forRole bool, role string) *Navigator {
 	nav := &Navigator{
 		cfg:            cfg,
 		menuPath:       []string{"root"},
 		selectionIndex: make(map[string]int),
 		scrollOffset:   make(map[string]int),
-		disabledItems:  make(map[string]bool),
+		disabledItems:  make(map[string]string),
+		itemKeys:       make(map[string][]string),
+		allHotkeys:     make(map[string]map[string]int),
+		visible:        make(map[string][]int),
+		shown:          make(map[string][]config.MenuItem),
+		grouped:        make(map[string][]bool),
+		collapsedGroups: make(map[string]bool),
 		errorReported:  make(map[string]bool),
 		hotkeyMap:      make(map[string]map[string]int),
+		prepared:       make(map[string]bool),
+		now:            time.Now,
+		forRole:        forRole,
+		role:           role,
+		roleItems:      make(map[string][]config.MenuItem),
 	}
 
-	// Build hotkey maps for all menus
-	nav.buildHotkeys("root", cfg.Items)
-	if cfg.Menus != nil {
-		for name, menu := range cfg.Menus {
-			nav.buildHotkeys(name, menu.Items)
-		}
-	}
-
-	// Validate submenu targets and mark disabled items
-	nav.validateTargets()
-
 	// Initialize selection to first selectable item
 	nav.selectionIndex["root"] = nav.firstSelectableIndex("root")
 
 	return nav
 }
 
-// buildHotkeys builds hotkey map for a menu
+// buildHotkeys builds hotkey map for a menu. Hotkeys are assigned over all of
+// the menu's items, so expanding a group never moves another item's hotkey.
 func (n *Navigator) buildHotkeys(menuName string, items []config.MenuItem) {
-	n.hotkeyMap[menuName] = make(map[string]int)
+	n.allHotkeys[menuName] = make(map[string]int)
 	usedHotkeys := make(map[string]bool)
+	prefixes := make(map[string]bool)
+	// The reload key is never handed out; pressing it reloads the config
+	prefixes[n.cfg.ReloadKey()] = true
 
-	// First pass: mark explicitly defined hotkeys (first one wins for duplicates)
+	// First pass: mark explicitly defined hotkeys (first one wins for duplicates).
+	// The first key of a two-key sequence is not auto-assigned, since pressing it
+	// waits for the second key.
 	for i, item := range items {
 		if item.Hotkey != "" {
 			hotkey := strings.ToUpper(item.Hotkey)
 			if !usedHotkeys[hotkey] {
-				n.hotkeyMap[menuName][hotkey] = i
+				n.allHotkeys[menuName][hotkey] = i
 				usedHotkeys[hotkey] = true
 			}
+			if keys := []rune(hotkey); len(keys) == 2 {
+				prefixes[string(keys[0])] = true
+			}
+		}
+	}
+
+	// The first quit item without its own hotkey gets Q unless an item claimed it explicitly
+	quitIdx := -1
+	for i, item := range items {
+		if item.Type == "quit" && item.Hotkey == "" {
+			if !usedHotkeys[config.DefaultQuitHotkey] && !prefixes[config.DefaultQuitHotkey] {
+				n.allHotkeys[menuName][config.DefaultQuitHotkey] = i
+				usedHotkeys[config.DefaultQuitHotkey] = true
+				quitIdx = i
+			}
+			break
 		}
 	}
 
@@ -74,13 +126,17 @@ func (n *Navigator) buildHotkeys(menuName string, items []config.MenuItem) {
 			// Already explicitly set
 			continue
 		}
+		if i == quitIdx {
+			// Given the default quit hotkey above
+			continue
+		}
 
 		// Scan label left-to-right for first available letter
 		for _, ch := range item.Label {
 			if unicode.IsLetter(ch) {
 				hotkey := strings.ToUpper(string(ch))
-				if !usedHotkeys[hotkey] {
-					n.hotkeyMap[menuName][hotkey] = i
+				if !usedHotkeys[hotkey] && !prefixes[hotkey] {
+					n.allHotkeys[menuName][hotkey] = i
 					usedHotkeys[hotkey] = true
 					break
 				}
@@ -89,38 +145,133 @@ func (n *Navigator) buildHotkeys(menuName string, items []config.MenuItem) {
 	}
 }
 
-// validateTargets checks that all submenu targets exist and marks disabled items
-func (n *Navigator) validateTargets() {
-	n.checkMenuTargets("root", n.cfg.Items)
-	if n.cfg.Menus != nil {
-		for name, menu := range n.cfg.Menus {
-			n.checkMenuTargets(name, menu.Items)
+// buildItemKeys records a key for each item in a menu that stays the same when
+// items are reordered. Items without a label are keyed by type. Each key is
+// kept by the first item that has it; repeats get the lowest numeric suffix
+// that no other item uses ("separator", "separator-2"), so an item labelled
+// "Foo 2" keeps "foo-2" and a second "Foo" becomes "foo-3".
+func (n *Navigator) buildItemKeys(menuName string, items []config.MenuItem) {
+	keys := make([]string, len(items))
+	bases := make([]string, len(items))
+	used := make(map[string]bool)
+	for i, item := range items {
+		key := item.Key()
+		if key == "" {
+			key = item.Type
+		}
+		bases[i] = key
+		if !used[key] {
+			keys[i] = key
+			used[key] = true
+		}
+	}
+	for i := range items {
+		if keys[i] != "" {
+			continue
+		}
+		for c := 2; ; c++ {
+			key := fmt.Sprintf("%s-%d", bases[i], c)
+			if !used[key] {
+				keys[i] = key
+				used[key] = true
+				break
+			}
+		}
+	}
+	n.itemKeys[menuName] = keys
+}
+
+// itemKey returns the key of the shown item at index in a menu, or "" if out of range
+func (n *Navigator) itemKey(menuName string, index int) string {
+	n.prepare(menuName)
+	visible := n.visible[menuName]
+	if index < 0 || index >= len(visible) {
+		return ""
+	}
+	return n.itemKeys[menuName][visible[index]]
+}
+
+// itemIndex returns the index of the shown item with key in a menu, or -1 if
+// no shown item has it
+func (n *Navigator) itemIndex(menuName, key string) int {
+	n.prepare(menuName)
+	for i, configIdx := range n.visible[menuName] {
+		if n.itemKeys[menuName][configIdx] == key {
+			return i
 		}
 	}
+	return -1
 }
 
+// qualifiedKey joins a menu name and item key into the key used by
+// disabledItems and collapsedGroups (e.g., "system:portal-2")
+func qualifiedKey(menuName, key string) string {
+	return menuName + ":" + key
+}
+
+// prepare builds a menu's hotkeys, item keys, and disabled items, and hides
+// the items of its collapsed groups. Menus are prepared the first time they
+// are used rather than up front, so a config with thousands of generated
+// items only pays for the menus that are actually opened.
+func (n *Navigator) prepare(menuName string) {
+	if n.prepared[menuName] {
+		return
+	}
+	if _, exists := n.cfg.Menus[menuName]; !exists && menuName != "root" {
+		return
+	}
+	n.prepared[menuName] = true
+	items := n.configItems(menuName)
+	n.buildHotkeys(menuName, items)
+	n.buildItemKeys(menuName, items)
+	// Validate submenu targets and mark disabled items
+	n.checkMenuTargets(menuName, items)
+	// Hide the items of groups that start collapsed
+	n.refreshMenu(menuName)
+}
+
+// Reasons an item is disabled, as returned by DisabledReason
+const (
+	DisabledMissingTarget = "missing_target" // submenu whose target menu doesn't exist
+	DisabledNoCommand     = "no_command"     // command with no variant for this OS
+	DisabledByConfig      = "disabled"       // item with disabled: true in the config
+	DisabledBySchedule    = "schedule"       // item outside its available_hours or available_days
+)
+
 // checkMenuTargets checks targets in a menu's items
 func (n *Navigator) checkMenuTargets(menuName string, items []config.MenuItem) {
 	osType := getOSType()
 	for i, item := range items {
+		if item.Disabled && item.Type != "separator" {
+			n.disabledItems[qualifiedKey(menuName, n.itemKeys[menuName][i])] = DisabledByConfig
+			logging.Debug("item disabled", "menu", menuName, "label", item.Label, "reason", "disabled in config")
+			continue
+		}
 		if item.Type == "submenu" {
-			if n.cfg.Menus == nil {
-				// Target doesn't exist - mark as disabled
-				disabledKey := fmt.Sprintf("%s:%d", menuName, i)
-				n.disabledItems[disabledKey] = true
-			} else if _, exists := n.cfg.Menus[item.Target]; !exists {
+			if _, exists := n.cfg.Menus[item.Target]; !exists {
 				// Target doesn't exist in menus map - mark as disabled
-				disabledKey := fmt.Sprintf("%s:%d", menuName, i)
-				n.disabledItems[disabledKey] = true
+				n.disabledItems[qualifiedKey(menuName, n.itemKeys[menuName][i])] = DisabledMissingTarget
+				logging.Debug("item disabled", "menu", menuName, "label", item.Label, "reason", "submenu target not found", "target", item.Target)
 			}
-		} else if item.Type == "command" {
+		} else if item.Type == "command" || item.Type == "browse" {
 			// Check if command has a variant for the current OS
 			if item.Exec.CommandForOS(osType) == "" {
 				// No variant for this OS - mark as disabled
-				disabledKey := fmt.Sprintf("%s:%d", menuName, i)
-				n.disabledItems[disabledKey] = true
+				n.disabledItems[qualifiedKey(menuName, n.itemKeys[menuName][i])] = DisabledNoCommand
+				logging.Debug("item disabled", "menu", menuName, "label", item.Label, "reason", "no command for this OS", "os", osType)
+			}
+		} else if item.Type == "parallel" {
+			// Runs whichever of its commands have a variant for this OS
+			if len(item.CommandsForOS(osType)) == 0 {
+				n.disabledItems[qualifiedKey(menuName, n.itemKeys[menuName][i])] = DisabledNoCommand
+				logging.Debug("item disabled", "menu", menuName, "label", item.Label, "reason", "no command for this OS", "os", osType)
 			}
 		}
+		key := qualifiedKey(menuName, n.itemKeys[menuName][i])
+		if _, disabled := n.disabledItems[key]; !disabled && !item.AvailableAt(n.now()) {
+			n.disabledItems[key] = DisabledBySchedule
+			logging.Debug("item disabled", "menu", menuName, "label", item.Label, "reason", "outside its available hours")
+		}
 	}
 }
 
@@ -138,19 +289,12 @@ func getOSType() string {
 	}
 }
 
-// GetCurrentMenu returns the current menu items
+// GetCurrentMenu returns the current menu items, without the items of collapsed groups
 func (n *Navigator) GetCurrentMenu() []config.MenuItem {
-	if len(n.menuPath) == 0 || n.menuPath[len(n.menuPath)-1] == "root" {
-		return n.cfg.Items
-	}
-
-	menuName := n.menuPath[len(n.menuPath)-1]
-	if n.cfg.Menus != nil {
-		if menu, exists := n.cfg.Menus[menuName]; exists {
-			return menu.Items
-		}
+	if items, ok := n.menuItems(n.GetCurrentMenuName()); ok {
+		return items
 	}
-	return n.cfg.Items
+	return n.shown["root"]
 }
 
 // GetCurrentMenuName returns the name of the current menu
@@ -176,13 +320,100 @@ func (n *Navigator) GetCurrentMenuTitle() string {
 	return ""
 }
 
-// GetFormattedTitle returns the title formatted for display with root title prefix for submenus
-func (n *Navigator) GetFormattedTitle() string {
+// IsEmpty reports whether the current menu has no items in the config, so
+// it shows only the Back (or Quit) item it was given and a placeholder
+func (n *Navigator) IsEmpty() bool {
+	return isEmptyMenu(n.configuredItems(n.GetCurrentMenuName()))
+}
+
+// GetEmptyText returns the placeholder configured for the current menu when
+// it is empty, or "" for the default
+func (n *Navigator) GetEmptyText() string {
 	menuName := n.GetCurrentMenuName()
 	if menuName == "root" {
-		return n.GetCurrentMenuTitle()
+		return n.cfg.EmptyText
 	}
-	return n.cfg.Title + " - " + n.GetCurrentMenuTitle()
+	return n.cfg.Menus[menuName].EmptyText
+}
+
+// GetFooterHint returns the footer hint for the current menu, if any
+func (n *Navigator) GetFooterHint() string {
+	menuName := n.GetCurrentMenuName()
+	if menuName == "root" {
+		return n.cfg.FooterHint
+	}
+	if menu, exists := n.cfg.Menus[menuName]; exists {
+		return menu.Footer
+	}
+	return ""
+}
+
+// breadcrumbSeparator joins the menu titles of the {path} placeholder
+const breadcrumbSeparator = " > "
+
+// defaultTitleFormats are used without a title_format: the root menu shows its
+// title, and submenus show the root title before their own
+const (
+	defaultRootTitleFormat    = "{title}"
+	defaultSubmenuTitleFormat = "{root} - {title}"
+)
+
+// GetFormattedTitle returns the current menu's title for its frame, formatted
+// with the config's title_format, fitted to maxWidth terminal columns where it
+// can be. A {path} breadcrumb that is too wide loses its outer menus first
+// ("… > Steam > Favorites"); anything still too wide is left to the caller to
+// truncate. maxWidth <= 0 means no limit.
+func (n *Navigator) GetFormattedTitle(maxWidth int) string {
+	format := n.cfg.TitleFormat
+	if format == "" {
+		format = defaultSubmenuTitleFormat
+		if n.IsAtRoot() {
+			format = defaultRootTitleFormat
+		}
+	}
+
+	crumbs := n.breadcrumb()
+	title := n.formatTitle(format, crumbs)
+	for skip := 1; maxWidth > 0 && runewidth.StringWidth(title) > maxWidth && skip < len(crumbs) && strings.Contains(format, "{path}"); skip++ {
+		title = n.formatTitle(format, append([]string{"…"}, crumbs[skip:]...))
+	}
+	return title
+}
+
+// formatTitle expands a title format's placeholders for the current menu,
+// with crumbs as the {path} breadcrumb
+func (n *Navigator) formatTitle(format string, crumbs []string) string {
+	return strings.NewReplacer(
+		"{title}", n.GetCurrentMenuTitle(),
+		"{root}", n.cfg.Title,
+		"{path}", strings.Join(crumbs, breadcrumbSeparator),
+		"{count}", fmt.Sprint(n.itemCount(n.GetCurrentMenuName())),
+	).Replace(format)
+}
+
+// breadcrumb returns the titles of the menus from the root to the current one
+func (n *Navigator) breadcrumb() []string {
+	crumbs := make([]string, 0, len(n.menuPath))
+	for _, name := range n.menuPath {
+		if name == "root" {
+			crumbs = append(crumbs, n.cfg.Title)
+		} else {
+			crumbs = append(crumbs, n.cfg.Menus[name].Title)
+		}
+	}
+	return crumbs
+}
+
+// itemCount returns the number of items in a menu, including those in
+// collapsed groups but not separators or group headers
+func (n *Navigator) itemCount(menuName string) int {
+	count := 0
+	for _, item := range n.configuredItems(menuName) {
+		if item.Type != "separator" && item.Type != "group" {
+			count++
+		}
+	}
+	return count
 }
 
 // GetSelectionIndex returns the current selection index
@@ -250,11 +481,19 @@ func (n *Navigator) EnsureVisible(maxVisible int) {
 	n.SetScrollOffset(offset)
 }
 
-// IsItemDisabled checks if an item is disabled (submenu with missing target)
+// IsItemDisabled checks if an item in the current menu is disabled
+// (submenu with missing target, command with no variant for this OS,
+// disabled in the config, or outside its available hours)
 func (n *Navigator) IsItemDisabled(itemIndex int) bool {
+	return n.DisabledReason(itemIndex) != ""
+}
+
+// DisabledReason returns why an item in the current menu is disabled
+// (DisabledMissingTarget, DisabledNoCommand, DisabledByConfig, or
+// DisabledBySchedule), or "" if it is enabled
+func (n *Navigator) DisabledReason(itemIndex int) string {
 	menuName := n.GetCurrentMenuName()
-	disabledKey := fmt.Sprintf("%s:%d", menuName, itemIndex)
-	return n.disabledItems[disabledKey]
+	return n.disabledItems[qualifiedKey(menuName, n.itemKey(menuName, itemIndex))]
 }
 
 // IsTargetErrorReported checks if a missing target error has been reported
@@ -269,15 +508,7 @@ func (n *Navigator) MarkTargetErrorReported(menuName string) {
 
 // firstSelectableIndex returns the index of the first selectable item (not separator)
 func (n *Navigator) firstSelectableIndex(menuName string) int {
-	var items []config.MenuItem
-	if menuName == "root" {
-		items = n.cfg.Items
-	} else if n.cfg.Menus != nil {
-		if menu, exists := n.cfg.Menus[menuName]; exists {
-			items = menu.Items
-		}
-	}
-
+	items, _ := n.menuItems(menuName)
 	for i, item := range items {
 		if item.Type != "separator" {
 			return i
@@ -286,20 +517,22 @@ func (n *Navigator) firstSelectableIndex(menuName string) int {
 	return 0
 }
 
-// NextSelectable moves to next non-separator item
+// NextSelectable moves to next non-separator item, wrapping from the last
+// item to the first unless the config turns wrap_navigation off
 func (n *Navigator) NextSelectable() {
 	items := n.GetCurrentMenu()
 	currentIdx := n.GetSelectionIndex()
-	nextIdx := currentIdx + 1
-
-	// Wrap around
-	if nextIdx >= len(items) {
-		nextIdx = 0
-	}
+	wrap := n.cfg.IsWrapNavigationEnabled()
 
 	// Skip separators
-	for i := 0; i < len(items); i++ {
-		idx := (nextIdx + i) % len(items)
+	for i := 1; i <= len(items); i++ {
+		idx := currentIdx + i
+		if idx >= len(items) {
+			if !wrap {
+				break
+			}
+			idx -= len(items)
+		}
 		if items[idx].Type != "separator" {
 			n.SetSelectionIndex(idx)
 			return
@@ -310,22 +543,21 @@ func (n *Navigator) NextSelectable() {
 	n.SetSelectionIndex(currentIdx)
 }
 
-// PrevSelectable moves to previous non-separator item
+// PrevSelectable moves to previous non-separator item, wrapping from the
+// first item to the last unless the config turns wrap_navigation off
 func (n *Navigator) PrevSelectable() {
 	items := n.GetCurrentMenu()
 	currentIdx := n.GetSelectionIndex()
-	prevIdx := currentIdx - 1
-
-	// Wrap around
-	if prevIdx < 0 {
-		prevIdx = len(items) - 1
-	}
+	wrap := n.cfg.IsWrapNavigationEnabled()
 
 	// Skip separators
-	for i := 0; i < len(items); i++ {
-		idx := (prevIdx - i) % len(items)
+	for i := 1; i <= len(items); i++ {
+		idx := currentIdx - i
 		if idx < 0 {
-			idx = len(items) + idx
+			if !wrap {
+				break
+			}
+			idx += len(items)
 		}
 		if items[idx].Type != "separator" {
 			n.SetSelectionIndex(idx)
@@ -337,13 +569,15 @@ func (n *Navigator) PrevSelectable() {
 	n.SetSelectionIndex(currentIdx)
 }
 
-// PageDown moves selection down by pageSize items, skipping separators
+// PageDown moves selection down by pageSize items, skipping separators. A
+// page stops at the last item; pressed there, it wraps to the first like
+// NextSelectable unless wrap_navigation is off.
 func (n *Navigator) PageDown(pageSize int) {
 	items := n.GetCurrentMenu()
 	currentIdx := n.GetSelectionIndex()
 	targetIdx := currentIdx + pageSize
 
-	// Clamp to last item (no wrapping for page navigation)
+	// Clamp to last item
 	if targetIdx >= len(items) {
 		targetIdx = len(items) - 1
 	}
@@ -355,15 +589,20 @@ func (n *Navigator) PageDown(pageSize int) {
 			return
 		}
 	}
+	if n.cfg.IsWrapNavigationEnabled() {
+		n.SelectFirst()
+	}
 }
 
-// PageUp moves selection up by pageSize items, skipping separators
+// PageUp moves selection up by pageSize items, skipping separators. A page
+// stops at the first item; pressed there, it wraps to the last like
+// PrevSelectable unless wrap_navigation is off.
 func (n *Navigator) PageUp(pageSize int) {
 	items := n.GetCurrentMenu()
 	currentIdx := n.GetSelectionIndex()
 	targetIdx := currentIdx - pageSize
 
-	// Clamp to first item (no wrapping for page navigation)
+	// Clamp to first item
 	if targetIdx < 0 {
 		targetIdx = 0
 	}
@@ -375,6 +614,25 @@ func (n *Navigator) PageUp(pageSize int) {
 			return
 		}
 	}
+	if n.cfg.IsWrapNavigationEnabled() {
+		n.SelectLast()
+	}
+}
+
+// SelectFirst moves selection to the first selectable item
+func (n *Navigator) SelectFirst() {
+	n.SetSelectionIndex(n.firstSelectableIndex(n.GetCurrentMenuName()))
+}
+
+// SelectLast moves selection to the last selectable item
+func (n *Navigator) SelectLast() {
+	items := n.GetCurrentMenu()
+	for i := len(items) - 1; i >= 0; i-- {
+		if items[i].Type != "separator" {
+			n.SetSelectionIndex(i)
+			return
+		}
+	}
 }
 
 // GetSelectedItem returns the currently selected item
@@ -390,6 +648,7 @@ func (n *Navigator) GetSelectedItem() (config.MenuItem, error) {
 // SelectItemByHotkey returns the item index matching a hotkey, or -1 if not found
 func (n *Navigator) SelectItemByHotkey(hotkey string) int {
 	menuName := n.GetCurrentMenuName()
+	n.prepare(menuName)
 	hotkeyUpper := strings.ToUpper(hotkey)
 	if idx, exists := n.hotkeyMap[menuName][hotkeyUpper]; exists {
 		// Don't move selection if disabled
@@ -400,6 +659,32 @@ func (n *Navigator) SelectItemByHotkey(hotkey string) int {
 	return -1
 }
 
+// IsHotkeyPrefix reports whether key is the first key of a two-key hotkey
+// sequence in the current menu, so a second key should be awaited
+func (n *Navigator) IsHotkeyPrefix(key string) bool {
+	menuName := n.GetCurrentMenuName()
+	n.prepare(menuName)
+	key = strings.ToUpper(key)
+	for hotkey := range n.hotkeyMap[menuName] {
+		if keys := []rune(hotkey); len(keys) == 2 && string(keys[0]) == key {
+			return true
+		}
+	}
+	return false
+}
+
+// HotkeyOwner returns the index of the item in the current menu that owns hotkey
+// (explicit or auto-assigned), or -1 if the hotkey is free. Unlike SelectItemByHotkey,
+// disabled items are still reported as owners.
+func (n *Navigator) HotkeyOwner(hotkey string) int {
+	menuName := n.GetCurrentMenuName()
+	n.prepare(menuName)
+	if idx, exists := n.hotkeyMap[menuName][strings.ToUpper(hotkey)]; exists {
+		return idx
+	}
+	return -1
+}
+
 // Open opens a submenu (moves to submenu if target exists)
 func (n *Navigator) Open() error {
 	item, err := n.GetSelectedItem()
@@ -419,6 +704,7 @@ func (n *Navigator) Open() error {
 
 	// Push menu to path
 	n.menuPath = append(n.menuPath, item.Target)
+	logging.Debug("open submenu", "from", n.menuPath[len(n.menuPath)-2], "to", item.Target)
 
 	// Initialize selection for this menu if not already set
 	if _, exists := n.selectionIndex[item.Target]; !exists {
@@ -439,9 +725,11 @@ func (n *Navigator) NavigateToMenu(name string) bool {
 		return true
 	}
 	if n.cfg.Menus == nil {
+		logging.Warn("menu not found", "menu", name)
 		return false
 	}
 	if _, exists := n.cfg.Menus[name]; !exists {
+		logging.Warn("menu not found", "menu", name)
 		return false
 	}
 	// Push the menu onto the path (root -> name)
@@ -452,26 +740,228 @@ func (n *Navigator) NavigateToMenu(name string) bool {
 	return true
 }
 
+// NavigateToPath opens a nested menu by its path from the root, e.g.
+// "games/games_steam". Each segment is a menu name or the label of the
+// submenu item that opens it (case-insensitive), so "Games/Steam" works too.
+// Every hop must be an enabled submenu of the previous menu; the back stack
+// and each parent's selection are set as if the user had opened them in turn.
+// On error the navigator is left unchanged.
+func (n *Navigator) NavigateToPath(path string) error {
+	segments := strings.Split(strings.Trim(strings.TrimSpace(path), "/"), "/")
+	if len(segments) > 0 && strings.EqualFold(strings.TrimSpace(segments[0]), "root") {
+		segments = segments[1:]
+	}
+
+	menuPath := []string{"root"}
+	selections := make(map[string]int)
+	for i, segment := range segments {
+		segment = strings.TrimSpace(segment)
+		if segment == "" {
+			return fmt.Errorf("empty menu in path '%s'", path)
+		}
+		parent := menuPath[len(menuPath)-1]
+		items, _ := n.menuItems(parent)
+		idx := -1
+		for j, item := range items {
+			if item.Type == "submenu" && (item.Target == segment || strings.EqualFold(item.Label, segment)) {
+				idx = j
+				break
+			}
+		}
+		if idx < 0 {
+			return fmt.Errorf("no submenu '%s' in '%s'", segment, strings.Join(segments[:i], "/"))
+		}
+		if n.disabledItems[qualifiedKey(parent, n.itemKey(parent, idx))] != "" {
+			return fmt.Errorf("submenu target '%s' not found", items[idx].Target)
+		}
+		selections[parent] = idx
+		menuPath = append(menuPath, items[idx].Target)
+	}
+
+	for name, idx := range selections {
+		n.selectionIndex[name] = idx
+	}
+	target := menuPath[len(menuPath)-1]
+	if _, exists := n.selectionIndex[target]; !exists {
+		n.selectionIndex[target] = n.firstSelectableIndex(target)
+	}
+	n.menuPath = menuPath
+	logging.Debug("navigate to path", "path", path, "menus", strings.Join(menuPath, "/"))
+	return nil
+}
+
 // Back returns to parent menu
 func (n *Navigator) Back() {
 	if len(n.menuPath) > 1 {
+		logging.Debug("back", "from", n.menuPath[len(n.menuPath)-1])
 		n.menuPath = n.menuPath[:len(n.menuPath)-1]
 	}
 }
 
+// BackOutOf goes back out of the first menu on the path for which leave
+// returns true, closing it and every menu opened from it. Reports whether
+// any menu was closed.
+func (n *Navigator) BackOutOf(leave func(menuName string) bool) bool {
+	for i := 1; i < len(n.menuPath); i++ {
+		if leave(n.menuPath[i]) {
+			logging.Debug("back out of", "menu", n.menuPath[i], "depth", len(n.menuPath)-i)
+			n.menuPath = n.menuPath[:i]
+			return true
+		}
+	}
+	return false
+}
+
+// Depth returns how many menus deep the current menu is; the root is 1
+func (n *Navigator) Depth() int {
+	return len(n.menuPath)
+}
+
 // IsAtRoot returns true if at root menu
 func (n *Navigator) IsAtRoot() bool {
 	return len(n.menuPath) == 1 && n.menuPath[0] == "root"
 }
 
-// RememberSelection stores the current selection for recovery after reload
-func (n *Navigator) RememberSelection() map[string]int {
-	return n.selectionIndex
+// RememberSelection returns the selected item's key in each menu, for recovery
+// after reload. Keys rather than indexes keep the selection on the same item
+// when the reloaded config adds, removes, or reorders items.
+func (n *Navigator) RememberSelection() map[string]string {
+	remembered := make(map[string]string, len(n.selectionIndex))
+	for menuName, idx := range n.selectionIndex {
+		if key := n.itemKey(menuName, idx); key != "" {
+			remembered[menuName] = key
+		}
+	}
+	return remembered
+}
+
+// RecallSelection applies selections stored by RememberSelection, skipping
+// menus and items that no longer exist
+func (n *Navigator) RecallSelection(remembered map[string]string) {
+	for menuName, key := range remembered {
+		if idx := n.itemIndex(menuName, key); idx >= 0 {
+			n.selectionIndex[menuName] = idx
+		}
+	}
 }
 
-// RecallSelection applies stored selection
-func (n *Navigator) RecallSelection(remembered map[string]int) {
-	for menuName, idx := range remembered {
-		n.selectionIndex[menuName] = idx
+// SelectedPath returns the selected item's path in the form FindItem accepts:
+// the labels of the submenus leading to the current menu and of the selected
+// item, joined by "/" (e.g. "Games/Steam/Portal 2")
+func (n *Navigator) SelectedPath() string {
+	var labels []string
+	for i := 0; i+1 < len(n.menuPath); i++ {
+		items, _ := n.menuItems(n.menuPath[i])
+		next := n.menuPath[i+1]
+		// The parent's selection is normally the submenu item that was opened,
+		// but a menu reached with NavigateToMenu was never opened from its parent
+		label := next
+		if idx, ok := n.selectionIndex[n.menuPath[i]]; ok && idx < len(items) && items[idx].Type == "submenu" && items[idx].Target == next {
+			label = items[idx].Label
+		} else {
+			for _, item := range items {
+				if item.Type == "submenu" && item.Target == next {
+					label = item.Label
+					break
+				}
+			}
+		}
+		labels = append(labels, label)
+	}
+	if item, err := n.GetSelectedItem(); err == nil {
+		labels = append(labels, item.Label)
+	}
+	return strings.Join(labels, "/")
+}
+
+// ErrLocked is returned by FindItem for an item that can only be reached
+// through a menu with a lock
+var ErrLocked = errors.New("the item is in a locked menu; open it from the menu")
+
+// FindItem resolves an item by path or id without a Navigator. A path is the
+// chain of labels from the root menu separated by "/" (e.g. "Games/Steam/Portal 2"),
+// matched case-insensitively; a path without "/" may also be an item id, which wins.
+// A parallel item's commands are reached like a submenu's items ("Dev/Stack/API").
+// Items in menus that can't be reached without signing in to a locked menu
+// return ErrLocked, since there is nobody to sign in.
+func FindItem(cfg *config.Config, path string) (config.MenuItem, error) {
+	item, menuName, err := findItem(cfg, path)
+	if err != nil {
+		return config.MenuItem{}, err
+	}
+	if menuName != "root" && !cfg.OpenMenus()[menuName] {
+		return config.MenuItem{}, fmt.Errorf("'%s': %w", path, ErrLocked)
+	}
+	return item, nil
+}
+
+// findItem is FindItem without the lock check; it also returns the name of
+// the menu holding the item
+func findItem(cfg *config.Config, path string) (config.MenuItem, string, error) {
+	path = strings.TrimSpace(path)
+	if path == "" {
+		return config.MenuItem{}, "", fmt.Errorf("empty item path")
+	}
+
+	if !strings.Contains(path, "/") {
+		if item, menuName, ok := findItemByID(cfg, path); ok {
+			return item, menuName, nil
+		}
+	}
+
+	items, menuName := cfg.Items, "root"
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		segment = strings.TrimSpace(segment)
+		item, ok := findItemByLabel(items, segment)
+		if !ok {
+			return config.MenuItem{}, "", fmt.Errorf("no item '%s' in '%s'", segment, strings.Join(segments[:i], "/"))
+		}
+		if i == len(segments)-1 {
+			return item, menuName, nil
+		}
+
+		// A parallel item's commands can be run on their own
+		if item.Type == "parallel" {
+			items = item.Commands
+			continue
+		}
+		if item.Type != "submenu" {
+			return config.MenuItem{}, "", fmt.Errorf("'%s' is not a submenu", strings.Join(segments[:i+1], "/"))
+		}
+		menu, exists := cfg.Menus[item.Target]
+		if !exists {
+			return config.MenuItem{}, "", fmt.Errorf("submenu target '%s' not found", item.Target)
+		}
+		items, menuName = menu.Items, item.Target
+	}
+	return config.MenuItem{}, "", fmt.Errorf("no item '%s'", path)
+}
+
+// findItemByID searches the root menu and all submenus for an item with the
+// given id, returning it with the name of its menu
+func findItemByID(cfg *config.Config, id string) (config.MenuItem, string, bool) {
+	for _, item := range cfg.Items {
+		if item.ID == id {
+			return item, "root", true
+		}
+	}
+	for name, menu := range cfg.Menus {
+		for _, item := range menu.Items {
+			if item.ID == id {
+				return item, name, true
+			}
+		}
+	}
+	return config.MenuItem{}, "", false
+}
+
+// findItemByLabel returns the first non-separator item whose label matches case-insensitively
+func findItemByLabel(items []config.MenuItem, label string) (config.MenuItem, bool) {
+	for _, item := range items {
+		if item.Type != "separator" && strings.EqualFold(item.Label, label) {
+			return item, true
+		}
 	}
+	return config.MenuItem{}, false
 }
@@ -1,8 +1,10 @@
 package menu
 
 import (
+	"errors"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/benworks/menuworks/config"
 )
@@ -31,6 +33,30 @@ func TestHotkeyAutoAssignment(t *testing.T) {
 	}
 }
 
+func TestHotkeyAutoAssignmentSkipsReloadKey(t *testing.T) {
+	echo := config.ExecConfig{Windows: "echo", Linux: "echo", Mac: "echo"}
+	cfg := &config.Config{
+		Title: "Root",
+		Items: []config.MenuItem{
+			{Type: "command", Label: "Run", Exec: echo},
+			{Type: "command", Label: "Quiet", Exec: echo},
+		},
+		KeyBindings: &config.KeyBindings{Reload: "q"},
+	}
+
+	nav := NewNavigator(cfg)
+
+	if got := nav.SelectItemByHotkey("R"); got != 0 {
+		t.Errorf("expected R free for items when reload is moved, got %d", got)
+	}
+	if got := nav.SelectItemByHotkey("Q"); got != -1 {
+		t.Errorf("expected the reload key Q never assigned, got %d", got)
+	}
+	if got := nav.SelectItemByHotkey("U"); got != 1 {
+		t.Errorf("expected Quiet to fall back to U, got %d", got)
+	}
+}
+
 func TestHotkeyDisabledSubmenu(t *testing.T) {
 	cfg := &config.Config{
 		Title: "Root",
@@ -503,6 +529,34 @@ func TestScrollOffsetPerMenu(t *testing.T) {
 	}
 }
 
+func TestQuitDefaultHotkey(t *testing.T) {
+	echo := config.ExecConfig{Windows: "echo", Linux: "echo", Mac: "echo"}
+	nav := NewNavigator(&config.Config{
+		Title: "Root",
+		Items: []config.MenuItem{
+			{Type: "command", Label: "Query", Exec: echo},
+			{Type: "quit", Label: "Exit"},
+		},
+	})
+	if got := nav.SelectItemByHotkey("Q"); got != 1 {
+		t.Errorf("expected the quit item to take Q ahead of an auto-assigned label letter, got %d", got)
+	}
+
+	nav = NewNavigator(&config.Config{
+		Title: "Root",
+		Items: []config.MenuItem{
+			{Type: "quit", Label: "Exit"},
+			{Type: "command", Label: "Query", Hotkey: "Q", Exec: echo},
+		},
+	})
+	if got := nav.SelectItemByHotkey("Q"); got != 1 {
+		t.Errorf("expected an explicit Q to win over the quit default, got %d", got)
+	}
+	if got := nav.SelectItemByHotkey("E"); got != 0 {
+		t.Errorf("expected the quit item to fall back to its label, got %d", got)
+	}
+}
+
 func TestDuplicateExplicitHotkeys(t *testing.T) {
 	cfg := &config.Config{
 		Title: "Root",
@@ -594,3 +648,716 @@ func TestPageDownSkipsSeparators(t *testing.T) {
 		t.Fatalf("expected PageDown to skip separator and land on 2, got %d", got)
 	}
 }
+
+func TestHotkeyOwner(t *testing.T) {
+	cfg := &config.Config{
+		Title: "Root",
+		Items: []config.MenuItem{
+			{Type: "command", Label: "Alpha", Exec: config.ExecConfig{Windows: "echo", Linux: "echo", Mac: "echo"}},
+			{Type: "submenu", Label: "Missing", Target: "nowhere"},
+		},
+	}
+
+	nav := NewNavigator(cfg)
+
+	if got := nav.HotkeyOwner("a"); got != 0 {
+		t.Errorf("expected A owned by item 0, got %d", got)
+	}
+	if got := nav.HotkeyOwner("M"); got != 1 {
+		t.Errorf("expected M owned by disabled item 1, got %d", got)
+	}
+	if got := nav.HotkeyOwner("Z"); got != -1 {
+		t.Errorf("expected Z to be free, got %d", got)
+	}
+}
+
+func TestHotkeySequences(t *testing.T) {
+	echo := config.ExecConfig{Windows: "echo", Linux: "echo", Mac: "echo"}
+	cfg := &config.Config{
+		Title: "Root",
+		Items: []config.MenuItem{
+			{Type: "command", Label: "Gallery", Exec: echo},
+			{Type: "command", Label: "All Games", Hotkey: "ga", Exec: echo},
+			{Type: "command", Label: "Recent Games", Hotkey: "GR", Exec: echo},
+		},
+	}
+
+	nav := NewNavigator(cfg)
+
+	if !nav.IsHotkeyPrefix("g") {
+		t.Error("expected G to start a sequence")
+	}
+	if nav.IsHotkeyPrefix("A") {
+		t.Error("expected A not to start a sequence")
+	}
+	if got := nav.SelectItemByHotkey("GA"); got != 1 {
+		t.Errorf("expected GA to select index 1, got %d", got)
+	}
+	if got := nav.SelectItemByHotkey("gr"); got != 2 {
+		t.Errorf("expected GR to select index 2, got %d", got)
+	}
+	// G starts sequences, so Gallery is auto-assigned its next free letter
+	if got := nav.HotkeyOwner("G"); got != -1 {
+		t.Errorf("expected G not to be auto-assigned, got %d", got)
+	}
+	if got := nav.SelectItemByHotkey("A"); got != 0 {
+		t.Errorf("expected A to select Gallery, got %d", got)
+	}
+}
+
+func TestGetFooterHint(t *testing.T) {
+	cfg := &config.Config{
+		Title:      "Root",
+		FooterHint: "Pick a tool",
+		Items: []config.MenuItem{
+			{Type: "submenu", Label: "Games", Target: "games"},
+		},
+		Menus: map[string]config.Menu{
+			"games": {Title: "Games", Footer: "Saves live in ~/saves", Items: []config.MenuItem{
+				{Type: "back", Label: "Back"},
+			}},
+		},
+	}
+
+	nav := NewNavigator(cfg)
+	if got := nav.GetFooterHint(); got != "Pick a tool" {
+		t.Fatalf("expected root footer hint, got %q", got)
+	}
+	if err := nav.Open(); err != nil {
+		t.Fatalf("unexpected error opening submenu: %v", err)
+	}
+	if got := nav.GetFooterHint(); got != "Saves live in ~/saves" {
+		t.Fatalf("expected submenu footer hint, got %q", got)
+	}
+}
+
+func TestFindItem(t *testing.T) {
+	echo := config.ExecConfig{Windows: "echo", Linux: "echo", Mac: "echo"}
+	cfg := &config.Config{
+		Title: "Root",
+		Items: []config.MenuItem{
+			{Type: "submenu", Label: "Games", Target: "games"},
+			{Type: "command", Label: "Status", ID: "status", Exec: echo},
+			{Type: "parallel", Label: "Stack", Commands: []config.MenuItem{
+				{Type: "command", Label: "API", Exec: echo},
+			}},
+		},
+		Menus: map[string]config.Menu{
+			"games": {Title: "Games", Items: []config.MenuItem{
+				{Type: "submenu", Label: "Steam", Target: "steam"},
+			}},
+			"steam": {Title: "Steam", Items: []config.MenuItem{
+				{Type: "command", Label: "Portal 2", ID: "portal2", Exec: echo},
+			}},
+		},
+	}
+
+	tests := []struct {
+		path      string
+		wantLabel string
+		wantErr   bool
+	}{
+		{path: "Games/Steam/Portal 2", wantLabel: "Portal 2"},
+		{path: "games / steam / portal 2", wantLabel: "Portal 2"},
+		{path: "portal2", wantLabel: "Portal 2"},
+		{path: "Status", wantLabel: "Status"},
+		{path: "Games/Origin", wantErr: true},
+		{path: "Stack/API", wantLabel: "API"},
+		{path: "Stack", wantLabel: "Stack"},
+		{path: "Status/Extra", wantErr: true},
+		{path: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		item, err := FindItem(cfg, tt.path)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("FindItem(%q): expected error, got %q", tt.path, item.Label)
+			}
+			continue
+		}
+		if err != nil || item.Label != tt.wantLabel {
+			t.Errorf("FindItem(%q) = %q, %v; want %q", tt.path, item.Label, err, tt.wantLabel)
+		}
+	}
+}
+
+func TestFindItemInLockedMenu(t *testing.T) {
+	echo := config.ExecConfig{Windows: "echo", Linux: "echo", Mac: "echo"}
+	cfg := &config.Config{
+		Title: "Root",
+		Items: []config.MenuItem{{Type: "submenu", Label: "Admin", Target: "admin"}},
+		Menus: map[string]config.Menu{
+			"admin": {Title: "Admin", Lock: &config.MenuLock{Auth: "os"}, Items: []config.MenuItem{
+				{Type: "command", Label: "Reboot", ID: "reboot", Exec: echo},
+			}},
+		},
+	}
+	for _, path := range []string{"Admin/Reboot", "reboot"} {
+		if _, err := FindItem(cfg, path); !errors.Is(err, ErrLocked) {
+			t.Errorf("FindItem(%q): expected ErrLocked, got %v", path, err)
+		}
+	}
+}
+
+func TestBackOutOf(t *testing.T) {
+	cfg := &config.Config{
+		Title: "Root",
+		Items: []config.MenuItem{{Type: "submenu", Label: "Games", Target: "games"}},
+		Menus: map[string]config.Menu{
+			"games": {Title: "Games", Items: []config.MenuItem{{Type: "submenu", Label: "Steam", Target: "steam"}}},
+			"steam": {Title: "Steam", Items: []config.MenuItem{{Type: "back", Label: "Back"}}},
+		},
+	}
+	n := NewNavigator(cfg)
+	if err := n.NavigateToPath("games/steam"); err != nil {
+		t.Fatal(err)
+	}
+	if n.BackOutOf(func(name string) bool { return name == "admin" }) {
+		t.Error("expected nothing to close when no menu on the path matches")
+	}
+	if !n.BackOutOf(func(name string) bool { return name == "games" }) || !n.IsAtRoot() {
+		t.Errorf("expected games and steam to close, got %q", n.GetCurrentMenuName())
+	}
+}
+
+func TestParallelDisabledWithoutCommands(t *testing.T) {
+	cfg := &config.Config{
+		Title: "Root",
+		Items: []config.MenuItem{
+			{Type: "parallel", Label: "Empty Here", Commands: []config.MenuItem{
+				{Type: "command", Label: "Elsewhere", Exec: config.ExecConfig{Windows: "x", Linux: "x", Mac: "x"}},
+			}},
+			{Type: "parallel", Label: "Stack", Commands: []config.MenuItem{
+				{Type: "command", Label: "API", Exec: config.ExecConfig{Windows: "api", Linux: "api", Mac: "api"}},
+			}},
+		},
+	}
+	// Blank out the current OS's variant of the first item's only command
+	switch getOSType() {
+	case "windows":
+		cfg.Items[0].Commands[0].Exec.Windows = ""
+	case "darwin":
+		cfg.Items[0].Commands[0].Exec.Mac = ""
+	default:
+		cfg.Items[0].Commands[0].Exec.Linux = ""
+	}
+
+	nav := NewNavigator(cfg)
+	if nav.DisabledReason(0) != DisabledNoCommand {
+		t.Errorf("expected a parallel item without commands for this OS to be disabled, got %q", nav.DisabledReason(0))
+	}
+	if nav.IsItemDisabled(1) {
+		t.Errorf("expected a parallel item with commands to be enabled")
+	}
+
+	tree := Tree(cfg)
+	if len(tree[1].Children) != 1 || tree[1].Children[0].Path != "Stack/API" || tree[1].Children[0].Exec != "api" {
+		t.Errorf("expected the parallel item's command in the tree, got %+v", tree[1].Children)
+	}
+}
+
+func TestSelectedPath(t *testing.T) {
+	nav := NewNavigator(stateTestConfig())
+	nav.SetSelectionIndex(1)
+	nav.Open()
+	nav.Open()
+	nav.SetSelectionIndex(2)
+	if got := nav.SelectedPath(); got != "Games/Steam/Portal 2" {
+		t.Errorf("expected Games/Steam/Portal 2, got %q", got)
+	}
+
+	// steam is reached directly and no root item opens it, so its name stands in
+	direct := NewNavigator(stateTestConfig())
+	direct.NavigateToMenu("steam")
+	if got := direct.SelectedPath(); got != "steam/Portal" {
+		t.Errorf("expected the menu name when no item leads to it from root, got %q", got)
+	}
+}
+
+func TestNavigateToPath(t *testing.T) {
+	for _, path := range []string{"games/steam", "Games/Steam", "root/games/steam", "/games/steam/"} {
+		nav := NewNavigator(stateTestConfig())
+		if err := nav.NavigateToPath(path); err != nil {
+			t.Fatalf("NavigateToPath(%q): %v", path, err)
+		}
+		if got := nav.GetCurrentMenuName(); got != "steam" {
+			t.Errorf("NavigateToPath(%q): expected steam, got %s", path, got)
+		}
+		// The parents select the submenus that were followed
+		if got := nav.SelectedPath(); got != "Games/Steam/Portal" {
+			t.Errorf("NavigateToPath(%q): expected Games/Steam/Portal, got %q", path, got)
+		}
+		nav.Back()
+		if got := nav.GetCurrentMenuName(); got != "games" {
+			t.Errorf("NavigateToPath(%q): expected Back to reach games, got %s", path, got)
+		}
+		nav.Back()
+		if !nav.IsAtRoot() || nav.GetSelectionIndex() != 1 {
+			t.Errorf("NavigateToPath(%q): expected root with Games selected, got index %d", path, nav.GetSelectionIndex())
+		}
+	}
+
+	// steam exists but is not opened from root, and the navigator is left alone on errors
+	cfg := stateTestConfig()
+	cfg.Items = append(cfg.Items, config.MenuItem{Type: "submenu", Label: "Broken", Target: "missing"})
+	for _, path := range []string{"steam", "games/nowhere", "games//steam", "Broken"} {
+		nav := NewNavigator(cfg)
+		if err := nav.NavigateToPath(path); err == nil {
+			t.Errorf("NavigateToPath(%q): expected an error", path)
+		}
+		if !nav.IsAtRoot() || nav.GetSelectionIndex() != 0 {
+			t.Errorf("NavigateToPath(%q): expected the navigator unchanged", path)
+		}
+	}
+}
+
+func TestRecallSelectionByItemKey(t *testing.T) {
+	echo := config.ExecConfig{Windows: "echo", Linux: "echo", Mac: "echo"}
+	cfg := &config.Config{
+		Title: "Root",
+		Items: []config.MenuItem{
+			{Type: "command", Label: "Build", Exec: echo},
+			{Type: "command", Label: "Deploy", ID: "ship", Exec: echo},
+			{Type: "command", Label: "Build", Exec: echo},
+		},
+	}
+
+	nav := NewNavigator(cfg)
+	if got := nav.itemKeys["root"]; fmt.Sprint(got) != "[build ship build-2]" {
+		t.Fatalf("expected unique keys [build ship build-2], got %v", got)
+	}
+	nav.SetSelectionIndex(1)
+	remembered := nav.RememberSelection()
+
+	// Reorder and relabel; the id keeps the selection on the same item
+	cfg.Items = []config.MenuItem{
+		{Type: "command", Label: "Release", ID: "ship", Exec: echo},
+		{Type: "command", Label: "Build", Exec: echo},
+	}
+	reloaded := NewNavigator(cfg)
+	reloaded.RecallSelection(remembered)
+	if got := reloaded.GetSelectionIndex(); got != 0 {
+		t.Errorf("expected selection to follow id ship to index 0, got %d", got)
+	}
+
+	// A remembered item that no longer exists leaves the default selection
+	reloaded = NewNavigator(cfg)
+	reloaded.RecallSelection(map[string]string{"root": "removed"})
+	if got := reloaded.GetSelectionIndex(); got != 0 {
+		t.Errorf("expected default selection for a removed item, got %d", got)
+	}
+}
+
+func TestItemKeySuffixesAvoidRealKeys(t *testing.T) {
+	echo := config.ExecConfig{Windows: "echo", Linux: "echo", Mac: "echo"}
+	cfg := &config.Config{
+		Title: "Root",
+		Items: []config.MenuItem{
+			{Type: "command", Label: "Foo", Exec: echo},
+			{Type: "command", Label: "Foo", Exec: echo},
+			{Type: "command", Label: "Foo 2", Exec: echo},
+			{Type: "command", Label: "Other", ID: "foo-3", Exec: echo},
+			{Type: "command", Label: "Foo", Exec: echo},
+		},
+	}
+
+	nav := NewNavigator(cfg)
+	if got := nav.itemKeys["root"]; fmt.Sprint(got) != "[foo foo-4 foo-2 foo-3 foo-5]" {
+		t.Errorf("expected suffixes to skip keys other items have, got %v", got)
+	}
+}
+
+func TestToggleGroup(t *testing.T) {
+	echo := config.ExecConfig{Windows: "echo", Linux: "echo", Mac: "echo"}
+	cfg := &config.Config{
+		Title: "Root",
+		Items: []config.MenuItem{
+			{Type: "group", Label: "Network", Collapsed: true},
+			{Type: "command", Label: "Ping", Exec: echo},
+			{Type: "command", Label: "Trace", Exec: echo},
+			{Type: "separator"},
+			{Type: "command", Label: "Quit", Exec: echo},
+		},
+	}
+
+	nav := NewNavigator(cfg)
+	if got := len(nav.GetCurrentMenu()); got != 3 {
+		t.Fatalf("expected collapsed group to hide 2 items, got %d shown", got)
+	}
+	if !nav.IsGroupCollapsed(0) {
+		t.Errorf("expected group to start collapsed")
+	}
+	// Hotkeys are assigned over all items, so hidden ones keep theirs
+	if got := nav.SelectItemByHotkey("P"); got != -1 {
+		t.Errorf("expected hidden item's hotkey to be inactive, got %d", got)
+	}
+	if got := nav.SelectItemByHotkey("Q"); got != 2 {
+		t.Errorf("expected Q at shown index 2, got %d", got)
+	}
+
+	if !nav.ToggleGroup() {
+		t.Fatalf("expected ToggleGroup on a group header to report true")
+	}
+	if got := len(nav.GetCurrentMenu()); got != 5 {
+		t.Fatalf("expected expanded group to show all 5 items, got %d", got)
+	}
+	if nav.GetSelectionIndex() != 0 || nav.IsGroupCollapsed(0) {
+		t.Errorf("expected selection to stay on the expanded group header")
+	}
+	if !nav.InGroup(1) || nav.InGroup(4) {
+		t.Errorf("expected Ping in the group and Quit outside it")
+	}
+	if got := nav.SelectItemByHotkey("P"); got != 1 {
+		t.Errorf("expected P at shown index 1, got %d", got)
+	}
+	if got := nav.SelectItemByHotkey("Q"); got != 4 {
+		t.Errorf("expected Q to move to shown index 4, got %d", got)
+	}
+
+	nav.NextSelectable()
+	if nav.ToggleGroup() {
+		t.Errorf("expected ToggleGroup on a command to report false")
+	}
+
+	// Group state carries over to a navigator for the reloaded config
+	reloaded := NewNavigator(cfg)
+	reloaded.RestoreGroupState(nav.GroupState())
+	reloaded.RecallSelection(nav.RememberSelection())
+	if reloaded.IsGroupCollapsed(0) {
+		t.Errorf("expected group to stay expanded after reload")
+	}
+	if got := reloaded.GetSelectionIndex(); got != 1 {
+		t.Errorf("expected selection on Ping after reload, got %d", got)
+	}
+}
+
+// largeConfig returns a generated library of n commands split across submenus
+// of 500 items each, like a big `menuworks generate` result
+func largeConfig(n int) *config.Config {
+	echo := config.ExecConfig{Windows: "echo", Linux: "echo", Mac: "echo"}
+	cfg := &config.Config{Title: "Library", Menus: map[string]config.Menu{}}
+	for i := 0; i < n; i += 500 {
+		name := fmt.Sprintf("shelf%d", i/500)
+		var items []config.MenuItem
+		for j := i; j < i+500 && j < n; j++ {
+			items = append(items, config.MenuItem{Type: "command", Label: fmt.Sprintf("Game %d", j), Exec: echo})
+		}
+		cfg.Menus[name] = config.Menu{Title: name, Items: items}
+		cfg.Items = append(cfg.Items, config.MenuItem{Type: "submenu", Label: name, Target: name})
+	}
+	return cfg
+}
+
+func TestMenusPreparedOnFirstUse(t *testing.T) {
+	nav := NewNavigator(largeConfig(1500))
+	if len(nav.prepared) != 1 || !nav.prepared["root"] {
+		t.Fatalf("expected only root prepared after NewNavigator, got %v", nav.prepared)
+	}
+	if err := nav.Open(); err != nil {
+		t.Fatalf("open shelf0: %v", err)
+	}
+	if !nav.prepared["shelf0"] || nav.prepared["shelf1"] {
+		t.Errorf("expected only the opened menu to be prepared, got %v", nav.prepared)
+	}
+	if got := nav.SelectItemByHotkey("G"); got != 0 {
+		t.Errorf("expected hotkey G on the first game, got %d", got)
+	}
+}
+
+func BenchmarkNewNavigator(b *testing.B) {
+	cfg := largeConfig(5000)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		nav := NewNavigator(cfg)
+		nav.NavigateToMenu("shelf0")
+	}
+}
+
+func BenchmarkNavigateLargeMenu(b *testing.B) {
+	cfg := largeConfig(5000)
+	cfg.Items = cfg.Menus["shelf0"].Items
+	for i := 1; i < 10; i++ {
+		cfg.Items = append(cfg.Items, cfg.Menus[fmt.Sprintf("shelf%d", i)].Items...)
+	}
+	nav := NewNavigator(cfg)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		nav.NextSelectable()
+		nav.EnsureVisible(14)
+	}
+}
+
+func TestSelectFirstAndLast(t *testing.T) {
+	echo := config.ExecConfig{Windows: "echo", Linux: "echo", Mac: "echo"}
+	cfg := &config.Config{
+		Title: "Root",
+		Items: []config.MenuItem{
+			{Type: "separator"},
+			{Type: "command", Label: "First", Exec: echo},
+			{Type: "command", Label: "Middle", Exec: echo},
+			{Type: "command", Label: "Last", Exec: echo},
+			{Type: "separator"},
+		},
+	}
+
+	nav := NewNavigator(cfg)
+	nav.SelectLast()
+	if got := nav.GetSelectionIndex(); got != 3 {
+		t.Errorf("expected End to skip the trailing separator to index 3, got %d", got)
+	}
+	nav.SelectFirst()
+	if got := nav.GetSelectionIndex(); got != 1 {
+		t.Errorf("expected Home to skip the leading separator to index 1, got %d", got)
+	}
+}
+
+func TestDisabledReason(t *testing.T) {
+	cfg := &config.Config{
+		Title: "Root",
+		Items: []config.MenuItem{
+			{Type: "submenu", Label: "Tools", Target: "tools"},
+			{Type: "command", Label: "Nowhere", Exec: config.ExecConfig{}},
+			{Type: "command", Label: "Everywhere", Exec: config.ExecConfig{Windows: "echo", Linux: "echo", Mac: "echo"}},
+			{Type: "submenu", Label: "Reports", Target: "tools", Disabled: true, DisabledReason: "Coming soon"},
+		},
+	}
+
+	nav := NewNavigator(cfg)
+	for idx, want := range []string{DisabledMissingTarget, DisabledNoCommand, "", DisabledByConfig} {
+		if got := nav.DisabledReason(idx); got != want {
+			t.Errorf("item %d: expected reason %q, got %q", idx, want, got)
+		}
+		if got := nav.IsItemDisabled(idx); got != (want != "") {
+			t.Errorf("item %d: expected disabled %v, got %v", idx, want != "", got)
+		}
+	}
+
+	if got := nav.SelectItemByHotkey("R"); got != -1 {
+		t.Errorf("expected the hotkey of an item disabled in the config to be ignored, got %d", got)
+	}
+
+	nodes := Tree(cfg)
+	if nodes[0].DisabledReason != DisabledMissingTarget || !nodes[0].Disabled {
+		t.Errorf("expected tree to report the missing target, got %+v", nodes[0])
+	}
+}
+
+func TestRefreshSchedule(t *testing.T) {
+	echo := config.ExecConfig{Windows: "echo", Linux: "echo", Mac: "echo"}
+	cfg := &config.Config{
+		Title: "Home",
+		Items: []config.MenuItem{
+			{Type: "submenu", Label: "Games", Target: "games", AvailableHours: "16:00-20:00"},
+			{Type: "command", Label: "Homework", Exec: echo},
+			{Type: "command", Label: "Later", Exec: echo, AvailableHours: "16:00-20:00", Disabled: true},
+		},
+		Menus: map[string]config.Menu{
+			"games": {Title: "Games", Items: []config.MenuItem{{Type: "command", Label: "Chess", Exec: echo}}},
+		},
+	}
+	clock := time.Date(2026, 10, 16, 15, 59, 0, 0, time.Local)
+	nav := NewNavigator(cfg)
+	nav.now = func() time.Time { return clock }
+	// Prepare the root again against the test clock
+	nav.prepared = map[string]bool{}
+	nav.disabledItems = map[string]string{}
+	nav.prepare("root")
+
+	if got := nav.DisabledReason(0); got != DisabledBySchedule {
+		t.Fatalf("expected Games outside its hours, got reason %q", got)
+	}
+	if got := nav.DisabledReason(2); got != DisabledByConfig {
+		t.Errorf("expected disabled: true to win over the schedule, got %q", got)
+	}
+	if nav.RefreshSchedule() {
+		t.Error("expected no change before the window opens")
+	}
+
+	clock = clock.Add(time.Minute)
+	if !nav.RefreshSchedule() || nav.IsItemDisabled(0) {
+		t.Fatal("expected Games to be enabled once its window opens")
+	}
+	if got := nav.DisabledReason(2); got != DisabledByConfig {
+		t.Errorf("expected the refresh to leave disabled: true alone, got %q", got)
+	}
+	nav.SetSelectionIndex(0)
+	if err := nav.Open(); err != nil {
+		t.Fatalf("open Games: %v", err)
+	}
+
+	clock = time.Date(2026, 10, 16, 20, 0, 0, 0, time.Local)
+	if !nav.RefreshSchedule() {
+		t.Fatal("expected a change when the window closes")
+	}
+	if !nav.IsAtRoot() {
+		t.Errorf("expected the Games menu to close with its window, at %q", nav.GetCurrentMenuName())
+	}
+	if got := nav.DisabledReason(0); got != DisabledBySchedule {
+		t.Errorf("expected Games outside its hours again, got %q", got)
+	}
+}
+
+func TestNavigatorForRole(t *testing.T) {
+	echo := config.ExecConfig{Windows: "echo", Linux: "echo", Mac: "echo"}
+	cfg := &config.Config{
+		Title: "Home",
+		Roles: map[string]config.Role{"kids": {}, "parent": {}},
+		Items: []config.MenuItem{
+			{Type: "command", Label: "Admin", Exec: echo, Roles: []string{"parent"}},
+			{Type: "command", Label: "Games", Exec: echo, Roles: []string{"kids", "parent"}},
+			{Type: "command", Label: "Homework", Exec: echo},
+		},
+	}
+
+	for _, tt := range []struct {
+		role string
+		want []string
+	}{
+		{"parent", []string{"Admin", "Games", "Homework"}},
+		{"kids", []string{"Games", "Homework"}},
+		{"", []string{"Homework"}},
+	} {
+		nav := NewNavigatorForRole(cfg, tt.role)
+		var got []string
+		for _, item := range nav.GetCurrentMenu() {
+			got = append(got, item.Label)
+		}
+		if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+			t.Errorf("role %q: expected %v, got %v", tt.role, tt.want, got)
+		}
+		if nav.GetSelectionIndex() != 0 {
+			t.Errorf("role %q: expected the first shown item selected, got %d", tt.role, nav.GetSelectionIndex())
+		}
+	}
+
+	if got := len(NewNavigator(cfg).GetCurrentMenu()); got != 3 {
+		t.Errorf("expected NewNavigator to show every item, got %d", got)
+	}
+
+	cfg.Items = append(cfg.Items, config.MenuItem{Type: "submenu", Label: "Tools", Target: "tools", Roles: []string{"parent"}})
+	cfg.Menus = map[string]config.Menu{
+		"tools": {Title: "Tools", Items: []config.MenuItem{{Type: "command", Label: "Backup", Exec: echo}}},
+		"kiosk": {Title: "Kiosk", Items: []config.MenuItem{{Type: "command", Label: "Browser", Exec: echo}}},
+	}
+	kids := NewNavigatorForRole(cfg, "kids")
+	if !kids.Hidden("tools") || kids.Hidden("kiosk") || kids.Hidden("root") {
+		t.Error("expected only the menu behind the parent's submenu to be hidden from kids")
+	}
+	if NewNavigatorForRole(cfg, "parent").Hidden("tools") || NewNavigator(cfg).Hidden("tools") {
+		t.Error("expected tools to be reachable for the parent and without a role")
+	}
+}
+
+func TestGetFormattedTitle(t *testing.T) {
+	echo := config.ExecConfig{Windows: "echo", Linux: "echo", Mac: "echo"}
+	cfg := &config.Config{
+		Title: "Home",
+		Items: []config.MenuItem{{Type: "submenu", Label: "Games", Target: "games"}},
+		Menus: map[string]config.Menu{
+			"games": {Title: "Games", Items: []config.MenuItem{{Type: "submenu", Label: "Steam", Target: "steam"}}},
+			"steam": {Title: "Steam Library", Items: []config.MenuItem{
+				{Type: "group", Label: "Favorites", Collapsed: true},
+				{Type: "command", Label: "Portal 2", Exec: echo},
+				{Type: "separator"},
+				{Type: "command", Label: "Hades", Exec: echo},
+			}},
+		},
+	}
+	nav := NewNavigator(cfg)
+	if got := nav.GetFormattedTitle(0); got != "Home" {
+		t.Errorf("expected the root title by default, got %q", got)
+	}
+	if err := nav.NavigateToPath("games/steam"); err != nil {
+		t.Fatal(err)
+	}
+	if got := nav.GetFormattedTitle(0); got != "Home - Steam Library" {
+		t.Errorf("expected the root title before a submenu's by default, got %q", got)
+	}
+
+	cfg.TitleFormat = "{path} ({count} items)"
+	if got, want := nav.GetFormattedTitle(0), "Home > Games > Steam Library (2 items)"; got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+	// Outer menus are dropped before the current one
+	if got, want := nav.GetFormattedTitle(32), "… > Steam Library (2 items)"; got != want {
+		t.Errorf("expected %q within 32 columns, got %q", want, got)
+	}
+	if got, want := nav.GetFormattedTitle(10), "… > Steam Library (2 items)"; got != want {
+		t.Errorf("expected the current menu to be kept even when too wide, got %q", got)
+	}
+}
+
+func TestWrapNavigationOff(t *testing.T) {
+	echo := config.ExecConfig{Windows: "echo", Linux: "echo", Mac: "echo"}
+	wrap := false
+	cfg := &config.Config{
+		Title: "Root",
+		Items: []config.MenuItem{
+			{Type: "separator"},
+			{Type: "command", Label: "First", Exec: echo},
+			{Type: "command", Label: "Last", Exec: echo},
+			{Type: "separator"},
+		},
+		WrapNavigation: &wrap,
+	}
+	nav := NewNavigator(cfg)
+
+	nav.PrevSelectable()
+	nav.PageUp(5)
+	if got := nav.GetSelectionIndex(); got != 1 {
+		t.Errorf("expected Up and Page Up to stay on the first item, got %d", got)
+	}
+	nav.NextSelectable()
+	nav.NextSelectable()
+	nav.PageDown(5)
+	if got := nav.GetSelectionIndex(); got != 2 {
+		t.Errorf("expected Down and Page Down to stay on the last item, got %d", got)
+	}
+
+	// With wrapping on, paging past either end wraps like the arrow keys
+	cfg.WrapNavigation = nil
+	nav.PageDown(5)
+	if got := nav.GetSelectionIndex(); got != 1 {
+		t.Errorf("expected Page Down on the last item to wrap to the first, got %d", got)
+	}
+	nav.PageUp(5)
+	if got := nav.GetSelectionIndex(); got != 2 {
+		t.Errorf("expected Page Up on the first item to wrap to the last, got %d", got)
+	}
+}
+
+func TestEmptyMenuGetsBackItem(t *testing.T) {
+	cfg := &config.Config{
+		Title: "Root",
+		Items: []config.MenuItem{{Type: "submenu", Label: "Soon", Target: "soon"}},
+		Menus: map[string]config.Menu{
+			"soon": {Title: "Soon", Items: []config.MenuItem{{Type: "separator"}}, EmptyText: "Nothing here yet"},
+		},
+		TitleFormat: "{title} ({count})",
+	}
+	nav := NewNavigator(cfg)
+	if nav.IsEmpty() {
+		t.Fatal("expected the root menu not to be empty")
+	}
+	if err := nav.Open(); err != nil {
+		t.Fatal(err)
+	}
+	if !nav.IsEmpty() || nav.GetEmptyText() != "Nothing here yet" {
+		t.Errorf("expected an empty menu with its placeholder, got empty=%v text=%q", nav.IsEmpty(), nav.GetEmptyText())
+	}
+	item, err := nav.GetSelectedItem()
+	if err != nil || item.Type != "back" {
+		t.Fatalf("expected the Back item selected, got %+v (%v)", item, err)
+	}
+	if got := nav.GetFormattedTitle(40); got != "Soon (0)" {
+		t.Errorf("expected the Back item left out of the count, got %q", got)
+	}
+	if idx := nav.SelectItemByHotkey("B"); idx != 0 {
+		t.Errorf("expected B to select the Back item, got %d", idx)
+	}
+
+	nav = NewNavigator(&config.Config{Title: "Root"})
+	if item, _ := nav.GetSelectedItem(); item.Type != "quit" {
+		t.Errorf("expected an empty root menu to offer Quit, got %+v", item)
+	}
+}
@@ -0,0 +1,48 @@
+package menu
+
+import "github.com/benworks/menuworks/config"
+
+// Hidden reports whether the navigator's role leaves out the menu called
+// name: submenu items lead to it from the root menu, but none the role can
+// see. Menus no submenu item leads to, such as one only opened as the
+// initial menu, are never hidden, and neither is anything from a navigator
+// that isn't for a role.
+func (n *Navigator) Hidden(menuName string) bool {
+	if !n.forRole {
+		return false
+	}
+	if n.hiddenMenus == nil {
+		all := reachableMenus(func(name string) []config.MenuItem {
+			if name == "root" {
+				return n.cfg.Items
+			}
+			return n.cfg.Menus[name].Items
+		})
+		seen := reachableMenus(n.configuredItems)
+		n.hiddenMenus = make(map[string]bool)
+		for name := range all {
+			if !seen[name] {
+				n.hiddenMenus[name] = true
+			}
+		}
+	}
+	return n.hiddenMenus[menuName]
+}
+
+// reachableMenus returns the menus that submenu items lead to from the root
+// menu, with items giving each menu's items
+func reachableMenus(items func(menuName string) []config.MenuItem) map[string]bool {
+	reached := map[string]bool{"root": true}
+	queue := []string{"root"}
+	for len(queue) > 0 {
+		name := queue[0]
+		queue = queue[1:]
+		for _, item := range items(name) {
+			if item.Type == "submenu" && !reached[item.Target] {
+				reached[item.Target] = true
+				queue = append(queue, item.Target)
+			}
+		}
+	}
+	return reached
+}
@@ -0,0 +1,64 @@
+package menu
+
+import (
+	"github.com/benworks/menuworks/logging"
+)
+
+// RefreshSchedule re-checks available_hours and available_days for the items
+// of every menu prepared so far, so items become selectable or greyed out as
+// their windows open and close. A menu that is open but can no longer be
+// opened from its parent, because every submenu item leading to it is now
+// outside its hours, is closed along with the menus opened from it. Reports
+// whether anything changed.
+func (n *Navigator) RefreshSchedule() bool {
+	now := n.now()
+	changed := false
+	for menuName := range n.prepared {
+		for i, item := range n.configItems(menuName) {
+			if !item.HasSchedule() {
+				continue
+			}
+			key := qualifiedKey(menuName, n.itemKeys[menuName][i])
+			reason, disabled := n.disabledItems[key]
+			if disabled && reason != DisabledBySchedule {
+				continue
+			}
+			if available := item.AvailableAt(now); available == disabled {
+				if available {
+					delete(n.disabledItems, key)
+				} else {
+					n.disabledItems[key] = DisabledBySchedule
+				}
+				logging.Debug("item availability changed", "menu", menuName, "label", item.Label, "available", available)
+				changed = true
+			}
+		}
+	}
+
+	for i := 1; i < len(n.menuPath); i++ {
+		if n.closedBySchedule(n.menuPath[i-1], n.menuPath[i]) {
+			logging.Debug("back out of", "menu", n.menuPath[i], "reason", "outside its available hours")
+			n.menuPath = n.menuPath[:i]
+			return true
+		}
+	}
+	return changed
+}
+
+// closedBySchedule reports whether parent has submenu items leading to
+// target and all of them are outside their available hours. A menu reached
+// some other way, such as initial_menu, is never closed.
+func (n *Navigator) closedBySchedule(parent, target string) bool {
+	n.prepare(parent)
+	found := false
+	for i, item := range n.configItems(parent) {
+		if item.Type != "submenu" || item.Target != target {
+			continue
+		}
+		if n.disabledItems[qualifiedKey(parent, n.itemKeys[parent][i])] != DisabledBySchedule {
+			return false
+		}
+		found = true
+	}
+	return found
+}
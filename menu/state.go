@@ -0,0 +1,121 @@
+package menu
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/benworks/menuworks/config"
+)
+
+// State is the navigation position saved between runs: the stack of open
+// menus and the remembered selection in each menu. Selections are saved as
+// item keys (see config.MenuItem.Key) so they follow items that move;
+// Selection holds the indexes written by older versions and is only read.
+type State struct {
+	Path      []string          `json:"path"`
+	Items     map[string]string `json:"items,omitempty"`
+	Selection map[string]int    `json:"selection,omitempty"`
+}
+
+// StatePath returns the state file kept next to a config file, e.g.
+// config.yaml -> config.state.json, so each profile resumes independently
+func StatePath(configPath string) string {
+	base := strings.TrimSuffix(filepath.Base(configPath), filepath.Ext(configPath))
+	return filepath.Join(filepath.Dir(configPath), base+".state.json")
+}
+
+// LoadState reads a state file written by SaveState
+func LoadState(path string) (State, error) {
+	var s State
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return s, err
+	}
+	if err := json.Unmarshal(data, &s); err != nil {
+		return s, fmt.Errorf("failed to parse state file: %w", err)
+	}
+	return s, nil
+}
+
+// SaveState writes s to path. The file is replaced atomically so a crash
+// mid-write never leaves a truncated state behind.
+func SaveState(path string, s State) error {
+	data, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".menuworks-state-*")
+	if err != nil {
+		return fmt.Errorf("failed to create state file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	_, err = tmp.Write(data)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write state file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to write state file: %w", err)
+	}
+	return nil
+}
+
+// State returns a copy of the current menu path and selections
+func (n *Navigator) State() State {
+	return State{
+		Path:  append([]string(nil), n.menuPath...),
+		Items: n.RememberSelection(),
+	}
+}
+
+// RestoreState applies a saved position. The config may have changed since it
+// was saved, so the path is cut at the first menu that no longer exists and
+// selections of items that are gone, or land on a separator, are dropped.
+func (n *Navigator) RestoreState(s State) {
+	for name, idx := range s.Selection {
+		if _, keyed := s.Items[name]; keyed {
+			continue
+		}
+		items, ok := n.menuItems(name)
+		if !ok || idx < 0 || idx >= len(items) || items[idx].Type == "separator" {
+			continue
+		}
+		n.selectionIndex[name] = idx
+	}
+	for name, key := range s.Items {
+		items, _ := n.menuItems(name)
+		if idx := n.itemIndex(name, key); idx >= 0 && items[idx].Type != "separator" {
+			n.selectionIndex[name] = idx
+		}
+	}
+
+	path := []string{"root"}
+	for i, name := range s.Path {
+		if i == 0 {
+			if name != "root" {
+				break
+			}
+			continue
+		}
+		if _, ok := n.menuItems(name); !ok {
+			break
+		}
+		path = append(path, name)
+		if _, exists := n.selectionIndex[name]; !exists {
+			n.selectionIndex[name] = n.firstSelectableIndex(name)
+		}
+	}
+	n.menuPath = path
+}
+
+// menuItems returns the items shown in a menu by name ("root" for the top level)
+func (n *Navigator) menuItems(name string) ([]config.MenuItem, bool) {
+	n.prepare(name)
+	items, ok := n.shown[name]
+	return items, ok
+}
@@ -0,0 +1,135 @@
+package menu
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/benworks/menuworks/config"
+)
+
+func stateTestConfig() *config.Config {
+	echo := config.ExecConfig{Windows: "echo", Linux: "echo", Mac: "echo"}
+	return &config.Config{
+		Title: "Root",
+		Items: []config.MenuItem{
+			{Type: "command", Label: "Shell", Exec: echo},
+			{Type: "submenu", Label: "Games", Target: "games"},
+		},
+		Menus: map[string]config.Menu{
+			"games": {Title: "Games", Items: []config.MenuItem{
+				{Type: "submenu", Label: "Steam", Target: "steam"},
+			}},
+			"steam": {Title: "Steam", Items: []config.MenuItem{
+				{Type: "command", Label: "Portal", Exec: echo},
+				{Type: "separator"},
+				{Type: "command", Label: "Portal 2", Exec: echo},
+			}},
+		},
+	}
+}
+
+func TestStateRoundTrip(t *testing.T) {
+	cfg := stateTestConfig()
+	nav := NewNavigator(cfg)
+	nav.SetSelectionIndex(1)
+	if err := nav.Open(); err != nil {
+		t.Fatalf("open games: %v", err)
+	}
+	if err := nav.Open(); err != nil {
+		t.Fatalf("open steam: %v", err)
+	}
+	nav.SetSelectionIndex(2)
+
+	path := filepath.Join(t.TempDir(), "config.state.json")
+	if err := SaveState(path, nav.State()); err != nil {
+		t.Fatalf("SaveState: %v", err)
+	}
+	s, err := LoadState(path)
+	if err != nil {
+		t.Fatalf("LoadState: %v", err)
+	}
+
+	restored := NewNavigator(cfg)
+	restored.RestoreState(s)
+	if got := restored.GetCurrentMenuName(); got != "steam" {
+		t.Fatalf("expected to resume in steam, got %s", got)
+	}
+	if got := restored.GetSelectionIndex(); got != 2 {
+		t.Errorf("expected selection 2, got %d", got)
+	}
+	restored.Back()
+	restored.Back()
+	if got := restored.GetSelectionIndex(); got != 1 {
+		t.Errorf("expected root selection 1, got %d", got)
+	}
+}
+
+func TestRestoreStateDropsStaleEntries(t *testing.T) {
+	nav := NewNavigator(stateTestConfig())
+	nav.RestoreState(State{
+		Path:      []string{"root", "games", "removed", "steam"},
+		Selection: map[string]int{"root": 7, "steam": 1, "removed": 0},
+	})
+
+	if got := nav.GetCurrentMenuName(); got != "games" {
+		t.Fatalf("expected path cut at the missing menu, got %s", got)
+	}
+	nav.Back()
+	if got := nav.GetSelectionIndex(); got != 0 {
+		t.Errorf("expected out-of-range root selection ignored, got %d", got)
+	}
+	if _, ok := nav.State().Items["removed"]; ok {
+		t.Errorf("expected selection for a missing menu to be dropped")
+	}
+}
+
+func TestRestoreStateFollowsReorderedItems(t *testing.T) {
+	nav := NewNavigator(stateTestConfig())
+	nav.NavigateToMenu("steam")
+	nav.SetSelectionIndex(2)
+	saved := nav.State()
+	if got := saved.Items["steam"]; got != "portal-2" {
+		t.Fatalf("expected selection saved as portal-2, got %q", got)
+	}
+
+	// Move "Portal 2" to the top and add an item before the old position
+	cfg := stateTestConfig()
+	steam := cfg.Menus["steam"]
+	echo := steam.Items[0].Exec
+	steam.Items = []config.MenuItem{
+		steam.Items[2],
+		{Type: "command", Label: "Half-Life", Exec: echo},
+		steam.Items[0],
+	}
+	cfg.Menus["steam"] = steam
+
+	restored := NewNavigator(cfg)
+	restored.RestoreState(saved)
+	if got := restored.GetSelectionIndex(); got != 0 {
+		t.Errorf("expected selection to follow Portal 2 to index 0, got %d", got)
+	}
+}
+
+func TestRestoreStateReadsLegacyIndexes(t *testing.T) {
+	nav := NewNavigator(stateTestConfig())
+	nav.RestoreState(State{
+		Path:      []string{"root", "games", "steam"},
+		Selection: map[string]int{"root": 1, "steam": 2},
+		Items:     map[string]string{"root": "shell"},
+	})
+	if got := nav.GetSelectionIndex(); got != 2 {
+		t.Errorf("expected legacy steam selection 2, got %d", got)
+	}
+	nav.Back()
+	nav.Back()
+	if got := nav.GetSelectionIndex(); got != 0 {
+		t.Errorf("expected item key to win over legacy index, got %d", got)
+	}
+}
+
+func TestStatePath(t *testing.T) {
+	got := StatePath(filepath.Join("dir", "work.yaml"))
+	if want := filepath.Join("dir", "work.state.json"); got != want {
+		t.Errorf("expected %s, got %s", want, got)
+	}
+}
@@ -0,0 +1,86 @@
+package menu
+
+import (
+	"github.com/benworks/menuworks/config"
+)
+
+// Node is one item in the resolved menu tree, as listed by `menuworks list`
+type Node struct {
+	Label          string   `json:"label,omitempty"`
+	Type           string   `json:"type"`
+	ID             string   `json:"id,omitempty"`
+	Hotkey         string   `json:"hotkey,omitempty"`
+	Path           string   `json:"path,omitempty"`   // labels from the root menu joined by "/", usable with FindItem
+	Target         string   `json:"target,omitempty"` // for submenu type
+	Exec           string   `json:"exec,omitempty"`   // command for the current OS
+	URL            string   `json:"url,omitempty"`    // for url type
+	Browse         string   `json:"browse,omitempty"` // for browse type: directory the file picker opens in
+	WorkDir        string   `json:"workdir,omitempty"`
+	Help           string   `json:"help,omitempty"`
+	Disabled       bool     `json:"disabled,omitempty"`        // missing submenu target, no command for this OS, disabled in the config, or outside its hours
+	DisabledReason string   `json:"disabled_reason,omitempty"` // DisabledMissingTarget, DisabledNoCommand, DisabledByConfig, or DisabledBySchedule
+	Roles          []string `json:"roles,omitempty"`           // roles the item is limited to
+	Children       []Node   `json:"items,omitempty"`
+}
+
+// Tree returns the whole menu structure starting at the root menu, with
+// submenus expanded in place. Hotkeys include auto-assigned ones, and a
+// submenu that leads back to one of its ancestors is listed but not expanded.
+func Tree(cfg *config.Config) []Node {
+	n := NewNavigator(cfg)
+	return n.treeFor("root", cfg.Items, "", map[string]bool{"root": true})
+}
+
+// treeFor builds the nodes for one menu; visiting holds the menus on the current branch
+func (n *Navigator) treeFor(menuName string, items []config.MenuItem, prefix string, visiting map[string]bool) []Node {
+	n.prepare(menuName)
+	hotkeys := make(map[int]string)
+	for hotkey, idx := range n.allHotkeys[menuName] {
+		hotkeys[idx] = hotkey
+	}
+
+	osType := getOSType()
+	nodes := make([]Node, 0, len(items))
+	for i, item := range items {
+		node := Node{
+			Label:          item.Label,
+			Type:           item.Type,
+			ID:             item.ID,
+			Hotkey:         hotkeys[i],
+			Target:         item.Target,
+			Help:           item.Help,
+			URL:            item.URL,
+			Roles:          item.Roles,
+			DisabledReason: n.disabledItems[qualifiedKey(menuName, n.itemKeys[menuName][i])],
+		}
+		node.Disabled = node.DisabledReason != ""
+		if item.Type != "separator" && item.Type != "group" {
+			node.Path = prefix + item.Label
+		}
+		if item.Type == "command" || item.Type == "browse" {
+			node.Exec = item.Exec.CommandForOS(osType)
+			node.WorkDir = item.Exec.WorkDir
+		}
+		if item.Type == "browse" {
+			node.Browse = item.Path
+		}
+		if item.Type == "parallel" {
+			for _, c := range item.CommandsForOS(osType) {
+				node.Children = append(node.Children, Node{
+					Label:   c.Label,
+					Type:    c.Type,
+					Path:    node.Path + "/" + c.Label,
+					Exec:    c.Exec.CommandForOS(osType),
+					WorkDir: c.Exec.WorkDir,
+				})
+			}
+		}
+		if item.Type == "submenu" && !node.Disabled && !visiting[item.Target] {
+			visiting[item.Target] = true
+			node.Children = n.treeFor(item.Target, n.cfg.Menus[item.Target].Items, node.Path+"/", visiting)
+			delete(visiting, item.Target)
+		}
+		nodes = append(nodes, node)
+	}
+	return nodes
+}
@@ -0,0 +1,48 @@
+package menu
+
+import (
+	"testing"
+
+	"github.com/benworks/menuworks/config"
+)
+
+func TestTree(t *testing.T) {
+	echo := config.ExecConfig{Windows: "echo hi", Linux: "echo hi", Mac: "echo hi"}
+	cfg := &config.Config{
+		Title: "Root",
+		Items: []config.MenuItem{
+			{Type: "submenu", Label: "Games", Target: "games"},
+			{Type: "separator"},
+			{Type: "command", Label: "Status", Exec: echo},
+			{Type: "submenu", Label: "Broken", Target: "missing"},
+		},
+		Menus: map[string]config.Menu{
+			"games": {Title: "Games", Items: []config.MenuItem{
+				{Type: "command", Label: "Portal 2", Hotkey: "2", Exec: echo},
+				{Type: "submenu", Label: "Loop", Target: "games"},
+			}},
+		},
+	}
+
+	tree := Tree(cfg)
+	if len(tree) != 4 {
+		t.Fatalf("expected 4 root nodes, got %d", len(tree))
+	}
+	games := tree[0]
+	if games.Hotkey != "G" || len(games.Children) != 2 {
+		t.Fatalf("expected Games with hotkey G and 2 children, got %+v", games)
+	}
+	portal := games.Children[0]
+	if portal.Path != "Games/Portal 2" || portal.Exec != "echo hi" || portal.Hotkey != "2" {
+		t.Errorf("unexpected command node: %+v", portal)
+	}
+	if loop := games.Children[1]; loop.Children != nil {
+		t.Errorf("expected recursive submenu not to be expanded, got %d children", len(loop.Children))
+	}
+	if tree[1].Path != "" || tree[1].Type != "separator" {
+		t.Errorf("expected separator without a path, got %+v", tree[1])
+	}
+	if !tree[3].Disabled {
+		t.Errorf("expected submenu with missing target to be disabled")
+	}
+}
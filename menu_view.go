@@ -0,0 +1,481 @@
+package menuworks
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+
+	"github.com/benworks/menuworks/config"
+	"github.com/benworks/menuworks/exec"
+	"github.com/benworks/menuworks/i18n"
+	"github.com/benworks/menuworks/logging"
+	"github.com/benworks/menuworks/menu"
+	"github.com/benworks/menuworks/ui"
+)
+
+// menuView is the main menu; it stays at the bottom of the dispatcher's view stack
+type menuView struct {
+	a *App
+
+	// Track previous mouse button state for edge detection (act only on new presses)
+	lastMouseButtons tcell.ButtonMask
+
+	// pendingKey is the first key of a hotkey sequence awaiting its second
+	// key; pendingTimer gives up on it after hotkeySequenceTimeout
+	pendingKey   string
+	pendingTimer *time.Timer
+
+	// shownMenu and shownDepth are the menu drawn last, to notice entering
+	// or leaving a submenu. frame counts through the wipe that follows, 0
+	// when none is playing.
+	shownMenu  string
+	shownDepth int
+	frame      int
+	frameBack  bool
+	frameTimer *time.Timer
+
+	// badgeMenu and badgeCfg are the menu and config whose badge_exec
+	// commands were run last; badgeCancel stops those still running
+	badgeMenu   string
+	badgeCfg    *config.Config
+	badgeCancel context.CancelFunc
+}
+
+// A transition between menus is a wipe of transitionFrames frames
+const (
+	transitionFrames   = 4
+	transitionInterval = 25 * time.Millisecond
+)
+
+// hotkeySequenceTimeout is how long the menu waits for the second key of a
+// two-key hotkey
+const hotkeySequenceTimeout = time.Second
+
+// Draw renders the current menu, waiting first if the terminal is too small
+func (v *menuView) Draw() {
+	a := v.a
+
+	// Check terminal size
+	if w, h := a.screen.Size(); w < 80 || h < 25 {
+		// Wait in the "Terminal Too Small" pop-up, then simply redraw;
+		// the config and navigator state are kept as they are
+		a.ensureTerminalSize()
+	}
+
+	// Draw current menu, part way through a wipe if it was just entered
+	v.trackTransition()
+	if v.frame > 0 {
+		a.screen.SetTransition(float64(v.frame)/transitionFrames, v.frameBack)
+	} else {
+		a.screen.SetTransition(1, false)
+	}
+	v.refreshBadges()
+	a.screen.SetFooter(menuKeyHints(a.navigator, a.cfg.ReloadKey(), !a.wrapperMode()), a.cfg.IsFooterEnabled())
+	a.screen.DrawMenu(a.navigator)
+}
+
+// trackTransition starts a wipe when the menu about to be drawn is not the
+// one drawn last, unless animations are off
+func (v *menuView) trackTransition() {
+	navigator := v.a.navigator
+	name, depth := navigator.GetCurrentMenuName(), navigator.Depth()
+	changed := v.shownMenu != "" && (name != v.shownMenu || depth != v.shownDepth)
+	back := depth < v.shownDepth
+	v.shownMenu, v.shownDepth = name, depth
+	if !changed || !v.a.cfg.IsAnimationsEnabled() {
+		return
+	}
+	if v.frameTimer != nil {
+		v.frameTimer.Stop()
+	}
+	v.frame, v.frameBack = 1, back
+	v.nextFrame()
+}
+
+// nextFrame advances the wipe after transitionInterval; each step wakes the
+// dispatcher, which redraws the menu
+func (v *menuView) nextFrame() {
+	var timer *time.Timer
+	timer = v.a.d.AfterFunc(transitionInterval, func() {
+		if v.frameTimer != timer {
+			return // replaced by a newer transition
+		}
+		v.frame++
+		if v.frame < transitionFrames {
+			v.nextFrame()
+			return
+		}
+		v.frame = 0
+		v.frameTimer = nil
+	})
+	v.frameTimer = timer
+}
+
+// exitOrBack leaves the current submenu, or stops the app at the root
+// unless it is in wrapper mode
+func (v *menuView) exitOrBack() {
+	if v.a.navigator.IsAtRoot() {
+		if !v.a.wrapperMode() {
+			v.a.d.Stop() // Exit
+		}
+		return
+	}
+	v.a.navigator.Back()
+}
+
+// ctrlC quits, does nothing, or asks first, as configured by ctrl_c.menu.
+// In wrapper mode it does nothing.
+func (v *menuView) ctrlC() {
+	a := v.a
+	if a.wrapperMode() {
+		return
+	}
+	switch a.cfg.CtrlCMenuAction() {
+	case "ignore":
+		return
+	case "confirm":
+		v.confirmQuit()
+	default:
+		a.d.Stop()
+	}
+}
+
+// confirmQuit asks whether to quit and stops the app if the answer is yes
+func (v *menuView) confirmQuit() {
+	a := v.a
+	a.d.Push(ui.NewDialogView(a.screen, i18n.T("Quit"), i18n.T("Quit MenuWorks?"), []string{i18n.T("No"), i18n.T("Yes")}, func(choice int) {
+		a.d.Pop()
+		if choice == 1 {
+			a.d.Stop()
+		}
+	}).WithSize(40, 8))
+}
+
+// handleSelection opens, runs, or follows the selected item
+func (v *menuView) handleSelection() {
+	a := v.a
+	navigator := a.navigator
+
+	item, _ := navigator.GetSelectedItem()
+	if navigator.DisabledReason(navigator.GetSelectionIndex()) == menu.DisabledByConfig {
+		// Placeholder items explain themselves instead of doing anything
+		reason := item.DisabledReason
+		if reason == "" {
+			reason = i18n.T("This item is not available.")
+		}
+		a.showMessage(item.Label, reason)
+		return
+	}
+	if navigator.DisabledReason(navigator.GetSelectionIndex()) == menu.DisabledBySchedule {
+		reason := item.DisabledReason
+		if reason == "" {
+			reason = i18n.Tf("%s can only be used %s.", item.Label, item.ScheduleText())
+		}
+		a.showMessage(item.Label, reason)
+		return
+	}
+
+	if item.Type == "group" {
+		navigator.ToggleGroup()
+		return
+	}
+
+	if item.Type == "submenu" {
+		if a.menuLocked(item.Target) {
+			a.unlockMenu(item.Target, v.openSubmenu)
+			return
+		}
+		v.openSubmenu()
+		return
+	}
+
+	if item.Type == "command" {
+		a.confirmRun(item, func() { a.runCommand(item) })
+		return
+	}
+
+	if item.Type == "parallel" {
+		a.confirmRun(item, func() { a.runParallel(item) })
+		return
+	}
+
+	if item.Type == "back" {
+		v.exitOrBack()
+		return
+	}
+
+	if item.Type == "edit_config" {
+		a.editAndReload()
+		return
+	}
+
+	if item.Type == "fallback_shell" {
+		a.confirmRun(item, func() { a.openFallbackShell(item) })
+		return
+	}
+
+	if item.Type == "switch_role" {
+		a.selectRole()
+		return
+	}
+
+	if item.Type == "url" {
+		a.openLink(item)
+		return
+	}
+
+	if item.Type == "browse" {
+		a.browseFiles(item)
+		return
+	}
+
+	if item.Type == "quit" {
+		// Quit leaves MenuWorks from any menu, not just the root
+		if item.NeedsConfirm() {
+			v.confirmQuit()
+			return
+		}
+		a.d.Stop()
+	}
+}
+
+// openSubmenu opens the selected submenu, reporting a missing target once per menu
+func (v *menuView) openSubmenu() {
+	a := v.a
+	navigator := a.navigator
+	if err := navigator.Open(); err != nil {
+		if !navigator.IsTargetErrorReported(navigator.GetCurrentMenuName()) {
+			a.showError(i18n.T("Error"), i18n.Tf("Error: %v", err))
+			navigator.MarkTargetErrorReported(navigator.GetCurrentMenuName())
+		}
+	}
+}
+
+// useConfig switches to cfg, applying its display settings and rebuilding the navigator
+func (a *App) useConfig(cfg *config.Config) {
+	a.cfg = cfg
+	applyLocaleFromConfig(cfg)
+	applyThemeFromConfig(a.screen, cfg)
+	applyTitleBarFromConfig(a.screen, cfg)
+	applyLayoutFromConfig(a.screen, cfg)
+	applyAccessibilityFromConfig(a.screen, cfg)
+	applyDisabledNotesFromConfig(a.screen, cfg)
+	a.navigator = a.newNavigator(cfg)
+}
+
+// reload re-reads the config and rebuilds the navigator, keeping selections.
+// A config file that hasn't changed since it was loaded is left alone.
+func (a *App) reload() {
+	old := a.cfg
+	reloaded, err := a.reloadConfig()
+	if err != nil {
+		a.showError(i18n.T("Reload Error"), i18n.Tf("Failed to reload config: %v", err))
+		return
+	}
+	if !reloaded {
+		a.showMessage(i18n.T("Config Unchanged"), i18n.T("The config file has not changed since it was loaded."))
+		return
+	}
+	a.showReloaded(config.CompareConfigs(old, a.cfg))
+	a.warnHotkeyConflicts()
+}
+
+// reloadConfig re-reads the config and switches to it, keeping the selection
+// and group state as much as possible. It reports false without parsing the
+// file if it hasn't changed. The current config stays on error.
+func (a *App) reloadConfig() (bool, error) {
+	if !a.configChanged() {
+		logging.Info("config unchanged, not reloading", "path", a.ConfigPath)
+		return false, nil
+	}
+	logging.Info("reloading config", "path", a.ConfigPath)
+	version, _ := readConfigVersion(a.ConfigPath)
+	newCfg, _, err := config.Load(a.ConfigPath)
+	if err != nil {
+		return false, err
+	}
+	a.loaded = version
+	oldNavState := a.navigator.RememberSelection()
+	oldGroups := a.navigator.GroupState()
+	a.useConfig(newCfg)
+	a.navigator.RestoreGroupState(oldGroups)
+	a.navigator.RecallSelection(oldNavState)
+	return true, nil
+}
+
+// activateHotkey selects and runs the item with hotkey, reporting whether
+// there was one
+func (v *menuView) activateHotkey(hotkey string) bool {
+	idx := v.a.navigator.SelectItemByHotkey(hotkey)
+	if idx < 0 {
+		return false
+	}
+	v.a.navigator.SetSelectionIndex(idx)
+	v.handleSelection()
+	return true
+}
+
+// startSequence waits for the second key of a hotkey sequence starting with
+// key, showing key in the footer meanwhile. If none comes in time, an item
+// whose hotkey is key alone runs instead.
+func (v *menuView) startSequence(key string) {
+	var timer *time.Timer
+	timer = v.a.d.AfterFunc(hotkeySequenceTimeout, func() {
+		if v.pendingTimer != timer {
+			return // finished or replaced meanwhile
+		}
+		v.cancelSequence()
+		v.activateHotkey(key)
+	})
+	v.pendingKey = key
+	v.pendingTimer = timer
+	v.a.screen.SetPendingKey(key)
+}
+
+// cancelSequence forgets a pending hotkey sequence
+func (v *menuView) cancelSequence() {
+	if v.pendingTimer != nil {
+		v.pendingTimer.Stop()
+	}
+	v.pendingKey = ""
+	v.pendingTimer = nil
+	v.a.screen.SetPendingKey("")
+}
+
+// HandleEvent routes keyboard and mouse input for the menu
+func (v *menuView) HandleEvent(ev tcell.Event) {
+	a := v.a
+	navigator := a.navigator
+
+	switch e := ev.(type) {
+	case *tcell.EventKey:
+		if v.pendingKey != "" && e.Key() != tcell.KeyRune {
+			// Any other key abandons the sequence; Escape does nothing else
+			v.cancelSequence()
+			if e.Key() == tcell.KeyEscape {
+				return
+			}
+		}
+		// Reload comes before hotkeys; a sequence's second key is never it
+		if v.pendingKey == "" && isBindingKey(e, a.cfg.ReloadKey()) {
+			a.reload()
+			return
+		}
+
+		switch e.Key() {
+		case tcell.KeyUp:
+			navigator.PrevSelectable()
+
+		case tcell.KeyDown:
+			navigator.NextSelectable()
+
+		case tcell.KeyPgUp:
+			navigator.PageUp(a.screen.MenuPageSize())
+
+		case tcell.KeyPgDn:
+			navigator.PageDown(a.screen.MenuPageSize())
+
+		case tcell.KeyHome:
+			navigator.SelectFirst()
+
+		case tcell.KeyEnd:
+			navigator.SelectLast()
+
+		case tcell.KeyRight, tcell.KeyEnter:
+			v.handleSelection()
+
+		case tcell.KeyLeft, tcell.KeyEscape:
+			v.exitOrBack()
+
+		case tcell.KeyCtrlC:
+			v.ctrlC()
+
+		case tcell.KeyF3:
+			// Switch to another profile in the config directory
+			a.selectProfile()
+
+		case tcell.KeyF5:
+			// Pick a theme with live preview and save the choice to config
+			a.selectTheme()
+
+		case tcell.KeyF4:
+			// Reassign the selected item's hotkey and write it back to the config
+			a.reassignHotkey()
+
+		case tcell.KeyF2:
+			// Show help for current item (if it's a command)
+			item, err := navigator.GetSelectedItem()
+			if err == nil && item.Type == "command" {
+				command := item.Exec.CommandForOS(exec.GetOS())
+				if command == "" {
+					command = i18n.T("(No command defined for this platform)")
+				}
+				a.d.Push(ui.NewItemHelpView(a.screen, command, item.Help, a.d.Pop))
+			}
+
+		case tcell.KeyCtrlP:
+			// Preview what the selected command would run, without running it
+			if item, err := navigator.GetSelectedItem(); err == nil && item.Type == "command" {
+				a.showCommandPreview(item)
+			}
+
+		case tcell.KeyCtrlY:
+			// Copy the selected command to the clipboard
+			if item, err := navigator.GetSelectedItem(); err == nil && item.Type == "command" {
+				a.copyCommand(item)
+			}
+
+		case tcell.KeyRune:
+			key := strings.ToUpper(string(e.Rune()))
+			if v.pendingKey != "" {
+				sequence := v.pendingKey + key
+				v.cancelSequence()
+				if !v.activateHotkey(sequence) {
+					a.bell(config.BellInvalidKey)
+				}
+				return
+			}
+
+			if navigator.IsHotkeyPrefix(key) {
+				v.startSequence(key)
+				return
+			}
+			if !v.activateHotkey(key) {
+				a.bell(config.BellInvalidKey)
+			}
+		}
+
+	case *tcell.EventResize:
+		// Just re-render on resize
+
+	case *tcell.EventMouse:
+		buttons := e.Buttons()
+		// Edge detection: only act on NEW presses (not held buttons)
+		newPresses := buttons &^ v.lastMouseButtons
+		// Release detection: buttons that were pressed but now aren't
+		released := v.lastMouseButtons &^ buttons
+		v.lastMouseButtons = buttons
+
+		// Check wheel first (transient one-shot events)
+		if newPresses&tcell.WheelUp != 0 {
+			navigator.PrevSelectable()
+		} else if newPresses&tcell.WheelDown != 0 {
+			navigator.NextSelectable()
+		} else if newPresses&tcell.ButtonPrimary != 0 {
+			// Left click = the close button or a footer hint's key, else Enter/select (on press)
+			x, y := e.Position()
+			if a.screen.CloseButtonAt(x, y) {
+				v.exitOrBack()
+			} else if press := a.screen.FooterKeyAt(x, y); press != nil {
+				v.HandleEvent(press)
+			} else {
+				v.handleSelection()
+			}
+		} else if released&tcell.ButtonSecondary != 0 {
+			// Right click = Back/exit (on release, to filter phantom events)
+			v.exitOrBack()
+		}
+	}
+}
@@ -0,0 +1,214 @@
+package menuworks
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+
+	"github.com/benworks/menuworks/config"
+)
+
+func TestHotkeySequenceOpensItem(t *testing.T) {
+	cfg := &config.Config{
+		Title: "Root",
+		Items: []config.MenuItem{
+			{Type: "submenu", Label: "All Games", Hotkey: "ga", Target: "all"},
+			{Type: "submenu", Label: "Recent Games", Hotkey: "gr", Target: "recent"},
+		},
+		Menus: map[string]config.Menu{
+			"all":    {Title: "All", Items: []config.MenuItem{{Type: "back", Label: "Back"}}},
+			"recent": {Title: "Recent", Items: []config.MenuItem{{Type: "back", Label: "Back"}}},
+		},
+	}
+	a, sim, _ := newTestApp(t, cfg)
+	v := &menuView{a: a}
+
+	v.HandleEvent(tcell.NewEventKey(tcell.KeyRune, 'g', tcell.ModNone))
+	if v.pendingKey != "G" {
+		t.Fatalf("expected G to wait for a second key, got pending %q", v.pendingKey)
+	}
+	a.screen.DrawMenu(a.navigator)
+	if text := screenText(sim); !strings.Contains(text, "Hotkey G…") {
+		t.Errorf("expected the pending key under the menu, got:\n%s", text)
+	}
+
+	v.HandleEvent(tcell.NewEventKey(tcell.KeyRune, 'r', tcell.ModNone))
+	if got := a.navigator.GetCurrentMenuName(); got != "recent" {
+		t.Errorf("expected GR to open the recent menu, got %q", got)
+	}
+	if v.pendingKey != "" {
+		t.Errorf("expected the sequence to be finished, got pending %q", v.pendingKey)
+	}
+}
+
+func TestClickFooterHintsAndCloseButton(t *testing.T) {
+	cfg := &config.Config{
+		Title: "Root",
+		Items: []config.MenuItem{{Type: "submenu", Label: "Tools", Target: "tools"}},
+		Menus: map[string]config.Menu{
+			"tools": {Title: "Tools", Items: []config.MenuItem{{Type: "back", Label: "Back"}}},
+		},
+	}
+	a, sim, _ := newTestApp(t, cfg)
+	a.screen.EnableMouse()
+	v := &menuView{a: a}
+
+	// click draws the menu, then presses and releases the left button on text
+	click := func(text string) {
+		t.Helper()
+		a.screen.SetFooter(menuKeyHints(a.navigator, a.cfg.ReloadKey(), true), true)
+		a.screen.DrawMenu(a.navigator)
+		for y, line := range strings.Split(screenText(sim), "\n") {
+			if x := strings.Index(line, text); x >= 0 {
+				x = len([]rune(line[:x]))
+				v.HandleEvent(tcell.NewEventMouse(x, y, tcell.ButtonPrimary, tcell.ModNone))
+				v.HandleEvent(tcell.NewEventMouse(x, y, tcell.ButtonNone, tcell.ModNone))
+				return
+			}
+		}
+		t.Fatalf("%q not on screen:\n%s", text, screenText(sim))
+	}
+
+	for _, target := range []string{"ESC: Back", "[X]"} {
+		a.navigator.SetSelectionIndex(0)
+		if err := a.navigator.Open(); err != nil {
+			t.Fatal(err)
+		}
+		click(target)
+		if !a.navigator.IsAtRoot() {
+			t.Errorf("expected clicking %q to go back to the root menu", target)
+		}
+	}
+}
+
+func TestTransitionOnMenuChange(t *testing.T) {
+	cfg := &config.Config{
+		Title: "Root",
+		Items: []config.MenuItem{{Type: "submenu", Label: "Tools", Target: "tools"}},
+		Menus: map[string]config.Menu{
+			"tools": {Title: "Tools", Items: []config.MenuItem{{Type: "back", Label: "Back"}}},
+		},
+	}
+	a, _, _ := newTestApp(t, cfg)
+	v := &menuView{a: a}
+
+	v.trackTransition()
+	if v.frame != 0 {
+		t.Fatalf("expected no transition on the first draw, got frame %d", v.frame)
+	}
+	if err := a.navigator.Open(); err != nil {
+		t.Fatal(err)
+	}
+	v.trackTransition()
+	if v.frame != 1 || v.frameBack {
+		t.Errorf("expected a forward transition into the submenu, got frame %d back %v", v.frame, v.frameBack)
+	}
+	a.navigator.Back()
+	v.trackTransition()
+	if v.frame != 1 || !v.frameBack {
+		t.Errorf("expected a backward transition to the root, got frame %d back %v", v.frame, v.frameBack)
+	}
+	v.frameTimer.Stop()
+
+	off := false
+	a.cfg.Animations = &off
+	v.frame = 0
+	if err := a.navigator.Open(); err != nil {
+		t.Fatal(err)
+	}
+	v.trackTransition()
+	if v.frame != 0 {
+		t.Errorf("expected no transition with animations: false, got frame %d", v.frame)
+	}
+}
+
+func TestIsBindingKey(t *testing.T) {
+	cases := []struct {
+		ev   *tcell.EventKey
+		key  string
+		want bool
+	}{
+		{tcell.NewEventKey(tcell.KeyRune, 'r', tcell.ModNone), "R", true},
+		{tcell.NewEventKey(tcell.KeyRune, 'R', tcell.ModNone), "R", true},
+		{tcell.NewEventKey(tcell.KeyRune, 'f', tcell.ModNone), "F6", false},
+		{tcell.NewEventKey(tcell.KeyF6, 0, tcell.ModNone), "F6", true},
+		{tcell.NewEventKey(tcell.KeyF7, 0, tcell.ModNone), "F6", false},
+		{tcell.NewEventKey(tcell.KeyCtrlR, 0, tcell.ModCtrl), "Ctrl+R", true},
+		{tcell.NewEventKey(tcell.KeyRune, 'r', tcell.ModNone), "Ctrl+R", false},
+	}
+	for _, c := range cases {
+		if got := isBindingKey(c.ev, c.key); got != c.want {
+			t.Errorf("isBindingKey(%s, %s) = %v, expected %v", c.ev.Name(), c.key, got, c.want)
+		}
+	}
+}
+
+func TestReloadSkipsUnchangedConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	write := func(title string) {
+		t.Helper()
+		data := "title: " + title + "\nitems:\n  - type: quit\n    label: Quit\n"
+		if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("One")
+	a, _, _ := newTestApp(t, &config.Config{})
+	a.ConfigPath = path
+	if err := a.loadConfig(); err != nil || a.cfg.Title != "One" {
+		t.Fatalf("load: %v", err)
+	}
+	loaded := a.cfg
+
+	if reloaded, err := a.reloadConfig(); reloaded || err != nil {
+		t.Errorf("expected an unchanged file to be skipped, got %v, %v", reloaded, err)
+	}
+	// A touched file with the same contents is hashed, not parsed
+	later := time.Now().Add(time.Minute)
+	if err := os.Chtimes(path, later, later); err != nil {
+		t.Fatal(err)
+	}
+	if reloaded, err := a.reloadConfig(); reloaded || err != nil || a.cfg != loaded {
+		t.Errorf("expected a touched file to be skipped, got %v, %v", reloaded, err)
+	}
+
+	write("Two")
+	if reloaded, err := a.reloadConfig(); !reloaded || err != nil || a.cfg.Title != "Two" {
+		t.Errorf("expected the edit to be reloaded, got %v, %v, title %q", reloaded, err, a.cfg.Title)
+	}
+}
+
+func TestReloadDetails(t *testing.T) {
+	changes := config.Changes{
+		MenusAdded: []string{"tools"},
+		Items:      []config.ItemCountChange{{Menu: "", Before: 3, After: 2}, {Menu: "games", Before: 1, After: 4}},
+		Warnings:   []string{"item 2: hotkey 'B' is also used by item 1"},
+	}
+	if got, want := reloadSummary(changes), "Menus added: 1\nMenus with more or fewer items: 2\nWarnings: 1"; got != want {
+		t.Errorf("summary = %q, expected %q", got, want)
+	}
+	want := strings.Join([]string{
+		"Reloaded menu.yaml",
+		"",
+		"Menus added:",
+		"  tools",
+		"",
+		"Item counts:",
+		"  (root menu): 3 → 2 (-1)",
+		"  games: 1 → 4 (+3)",
+		"",
+		"Warnings:",
+		"  item 2: hotkey 'B' is also used by item 1",
+	}, "\n")
+	if got := reloadDetails("menu.yaml", changes); got != want {
+		t.Errorf("details:\n%s\nexpected:\n%s", got, want)
+	}
+
+	if got := reloadSummary(config.Changes{}); got != "No menus or items were added or removed." {
+		t.Errorf("unexpected summary for no changes: %q", got)
+	}
+}
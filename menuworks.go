@@ -0,0 +1,564 @@
+// Package menuworks runs a MenuWorks menu in the terminal. It is the engine
+// behind the menuworks binary and can be embedded in other Go programs:
+//
+//	cfg, _, err := config.Load("menu.yaml")
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	app := menuworks.NewApp(cfg)
+//	app.Hooks.AfterCommand = func(item config.MenuItem, command, output string) {
+//		log.Printf("ran %s", item.Label)
+//	}
+//	if err := app.Run(context.Background()); err != nil {
+//		log.Fatal(err)
+//	}
+package menuworks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+
+	"github.com/benworks/menuworks/app"
+	"github.com/benworks/menuworks/config"
+	"github.com/benworks/menuworks/exec"
+	"github.com/benworks/menuworks/i18n"
+	"github.com/benworks/menuworks/instance"
+	"github.com/benworks/menuworks/logging"
+	"github.com/benworks/menuworks/menu"
+	"github.com/benworks/menuworks/secrets"
+	"github.com/benworks/menuworks/ui"
+	"github.com/benworks/menuworks/webhook"
+)
+
+// Hooks let an embedding program observe or replace command execution.
+// Any nil hook is skipped (RunCommand falls back to the built-in runner).
+type Hooks struct {
+	// BeforeCommand runs before a command item executes; returning false skips it
+	BeforeCommand func(item config.MenuItem, command string) bool
+	// RunCommand executes the command and returns its captured output. The
+	// commands of a parallel item call it from several goroutines at once.
+	RunCommand func(item config.MenuItem, command string) string
+	// AfterCommand runs after a command item finishes
+	AfterCommand func(item config.MenuItem, command, output string)
+}
+
+// App is a MenuWorks menu bound to a config. Set the exported fields before Run.
+type App struct {
+	// ConfigPath is the file behind the config. Reload (R), profiles (F3),
+	// hotkey reassignment (F4), and saving the theme (F5) need it, and
+	// secret:// references fall back to the secrets.enc file beside it.
+	ConfigPath string
+	// InitialMenu is the menu to open first, or a path of nested menus such as
+	// "games/steam" (default: the config's initial_menu, then root)
+	InitialMenu string
+	// NoSplash skips the splash screen even when the config enables it
+	NoSplash bool
+	// Version is shown on the splash screen
+	Version string
+	// Hooks customize command execution
+	Hooks Hooks
+	// Backend is the terminal to draw to (default: the real terminal via tcell)
+	Backend ui.ScreenBackend
+	// Remote marks a menu shown on another machine's terminal, such as an SSH
+	// session. Commands that need this machine's terminal or open a window on
+	// it (launch modes other than inline) are refused.
+	Remote bool
+	// Wrapper is wrapper mode, as the config's wrapper setting: for a menu
+	// standing in for a login shell or desktop, which Esc never exits and a
+	// crash restarts
+	Wrapper bool
+	// Role is the role to start in, such as from -role or MENUWORKS_ROLE
+	// (default: the config's default_role)
+	Role string
+	// CheckForUpdate looks up a newer release in the background when the config
+	// enables update_check; ok is true if latest should be advertised
+	CheckForUpdate func(ctx context.Context) (latest string, ok bool)
+
+	// customConfig hides "Use Default" in the config error dialog
+	customConfig bool
+	// firstRun shows the "config created" notice after startup
+	firstRun bool
+
+	cfg       *config.Config
+	navigator *menu.Navigator
+	screen    *ui.Screen
+	d         *app.Dispatcher
+	ctx       context.Context
+
+	// savedPosition is the last menu state written for restore_position
+	savedPosition []byte
+	positionTimer *time.Timer
+
+	// configTimer schedules the next check of the config file for auto_reload
+	configTimer *time.Timer
+	// loaded is the version of the config file a.cfg was loaded from, so an
+	// unchanged file isn't parsed again on reload
+	loaded configVersion
+
+	// unlocked holds the locked menus signed in to this session
+	unlocked map[string]bool
+	// role is the active role, whose items the navigator shows
+	role string
+	// sessionStart is when the session began: when Run started or the last
+	// session timed out. sessionTimer schedules the next timeout check.
+	sessionStart time.Time
+	sessionTimer *time.Timer
+	// scheduleTimer re-checks item available_hours each minute
+	scheduleTimer *time.Timer
+
+	// fallbackShell is the argv a fallback_shell item chose, run once Run
+	// has restored the terminal
+	fallbackShell []string
+
+	// notifier delivers webhook events; started on first use
+	notifier *webhook.Notifier
+	hostname string
+}
+
+// NewApp creates an app for an already loaded config
+func NewApp(cfg *config.Config) *App {
+	return &App{cfg: cfg}
+}
+
+// NewAppFromFile creates an app that loads its config from path when it runs,
+// showing the config error dialog (Retry / Open in Editor / Use Default / Exit)
+// if loading fails.
+// When custom is true the file must already exist and is never replaced with the default.
+func NewAppFromFile(path string, custom bool) *App {
+	return &App{ConfigPath: path, customConfig: custom}
+}
+
+// Run takes over the terminal and shows the menu until the user exits or ctx
+// is cancelled. After a fallback_shell item, the shell takes over the process
+// on Linux and macOS, so Run only returns if it can't be started.
+func (a *App) Run(ctx context.Context) error {
+	err := a.run(ctx)
+	if a.fallbackShell != nil {
+		return exec.ReplaceProcess(a.fallbackShell)
+	}
+	return err
+}
+
+// run shows the menu for Run
+func (a *App) run(ctx context.Context) error {
+	// Flush webhook events after the terminal is restored
+	defer func() {
+		if a.notifier != nil {
+			a.notifier.Close(webhookFlushTimeout)
+			a.notifier = nil
+		}
+	}()
+
+	var screen *ui.Screen
+	var err error
+	if a.Backend != nil {
+		screen, err = ui.NewScreenWithBackend(a.Backend)
+	} else {
+		screen, err = ui.NewScreen()
+	}
+	if err != nil {
+		return fmt.Errorf("failed to initialize screen: %w", err)
+	}
+	defer screen.Close()
+
+	a.ctx = ctx
+	a.screen = screen
+	if a.ConfigPath != "" {
+		secrets.SetFile(secrets.DefaultFile(a.ConfigPath))
+	}
+	// Dialogs shown before the config loads use the environment's language
+	applyLocaleFromConfig(a.cfg)
+	// Start event poller IMMEDIATELY after screen init; all input, timers, and
+	// background jobs are routed through one dispatcher
+	a.d = app.NewDispatcher(screen.StartEventPoller())
+
+	// Check terminal size and show resize pop-up if needed
+	a.ensureTerminalSize()
+	if a.d.Stopped() {
+		return nil
+	}
+
+	if a.cfg == nil {
+		if err := a.loadConfig(); err != nil || a.cfg == nil {
+			return err
+		}
+	}
+	cfg := a.cfg
+
+	// With single_instance, only one MenuWorks runs with this config
+	if mode := cfg.SingleInstanceMode(); mode != "off" && a.ConfigPath != "" {
+		lock, err := instance.Acquire(instance.Path(a.ConfigPath))
+		switch {
+		case errors.Is(err, instance.ErrRunning) && mode == "exit":
+			logging.Info("another instance is running; exiting", "config", a.ConfigPath, "err", err)
+			return nil
+		case errors.Is(err, instance.ErrRunning):
+			return fmt.Errorf("%s: %w", a.ConfigPath, err)
+		case err != nil:
+			logging.Warn("failed to take the instance lock", "err", err)
+		default:
+			defer lock.Release()
+		}
+	}
+
+	// Enable mouse support if configured (default: enabled)
+	if cfg.IsMouseEnabled() {
+		screen.EnableMouse()
+	}
+
+	// Apply display settings from config
+	applyLocaleFromConfig(cfg)
+	applyThemeFromConfig(screen, cfg)
+	applyTitleBarFromConfig(screen, cfg)
+	applyLayoutFromConfig(screen, cfg)
+	applyAccessibilityFromConfig(screen, cfg)
+	applyDisabledNotesFromConfig(screen, cfg)
+
+	// Determine if splash screen should be shown (NoSplash overrides config)
+	if cfg.IsSplashEnabled() && !a.NoSplash {
+		a.showSplash()
+	}
+
+	// Explicitly clear screen before transitioning to menu
+	screen.Clear()
+	screen.Sync()
+
+	// Show first-run notification if config was just created
+	if a.firstRun {
+		a.showMessage(i18n.T("First Run"), i18n.Tf("A configuration file could not be found, so one has been created for you at %s. Edit this file to modify menu items. Press \"%s\" to reload it.", a.ConfigPath, cfg.ReloadKey()))
+	}
+
+	// Create navigator
+	a.role = a.startRole()
+	a.navigator = a.newNavigator(cfg)
+
+	// Navigate to initial menu (silently ignored if not found)
+	initialMenu := cfg.InitialMenu
+	if a.InitialMenu != "" {
+		initialMenu = a.InitialMenu
+	}
+	a.navigateTo(initialMenu)
+
+	// Resume where the last run left off; an explicit InitialMenu still wins
+	restore := cfg.IsRestorePositionEnabled() && a.ConfigPath != ""
+	if restore && a.InitialMenu == "" {
+		a.restorePosition()
+	}
+	// Locked menus are only opened from the menu, where they can ask to sign
+	// in, and menus the role can't see aren't opened at all
+	a.navigator.BackOutOf(a.menuBarred)
+
+	// Warn about explicit hotkeys that lose to an earlier item in the same menu
+	a.warnHotkeyConflicts()
+
+	// Look for a newer release without delaying startup
+	if cfg.IsUpdateCheckEnabled() && a.CheckForUpdate != nil {
+		go func() {
+			latest, ok := a.CheckForUpdate(ctx)
+			logging.Debug("update check finished", "latest", latest, "newer", ok)
+			if ok {
+				a.d.Post(func() { a.screen.SetBadge(i18n.Tf("Update %s available", latest)) })
+			}
+		}()
+	}
+
+	// Main event loop
+	a.d.Push(&menuView{a: a})
+	if restore {
+		a.startPositionSaver()
+		defer a.stopPositionSaver()
+	}
+	if cfg.IsAutoReloadEnabled() && a.ConfigPath != "" {
+		stamp, _ := statConfig(a.ConfigPath)
+		a.startConfigWatcher(a.ConfigPath, stamp)
+		defer a.stopConfigWatcher()
+	}
+	a.sessionStart = time.Now()
+	a.startSessionTimer()
+	defer a.stopSessionTimer()
+	a.startScheduleTimer()
+	defer a.stopScheduleTimer()
+	return a.runMenu(ctx)
+}
+
+// navigateTo opens name from the root: a menu, or a path of menus such as
+// "games/steam" whose menus are opened in turn so Back retraces it. An
+// empty name is the root menu; one that isn't found is logged and ignored.
+func (a *App) navigateTo(name string) {
+	if strings.Contains(name, "/") {
+		if err := a.navigator.NavigateToPath(name); err != nil {
+			logging.Warn("menu not opened", "path", name, "error", err)
+		}
+	} else if name != "" {
+		a.navigator.NavigateToMenu(name)
+	}
+}
+
+// loadConfig loads ConfigPath, retrying through the config error dialog.
+// Leaves a.cfg nil (with a nil error) if the user chose Exit.
+func (a *App) loadConfig() error {
+	// If a custom config path was specified, verify it exists before proceeding
+	if a.customConfig {
+		if _, err := os.Stat(a.ConfigPath); os.IsNotExist(err) {
+			a.showMessage(i18n.T("Error"), i18n.Tf("The specified configuration file was not found:\n%s", a.ConfigPath))
+			return fmt.Errorf("config file not found: %s", a.ConfigPath)
+		}
+	}
+
+	for {
+		version, _ := readConfigVersion(a.ConfigPath)
+		loadedCfg, created, loadErr := config.Load(a.ConfigPath)
+		if loadErr == nil {
+			a.cfg = loadedCfg
+			a.loaded = version
+			a.firstRun = created
+			return nil
+		}
+		if !a.handleConfigError(loadErr) {
+			return nil
+		}
+	}
+}
+
+// showSplash shows the splash screen for a fixed 1000ms
+func (a *App) showSplash() {
+	a.screen.DrawSplashScreen(a.Version)
+
+	// Consume and discard all events during splash (prevents macOS hang)
+	// Per spec: "key events are consumed and discarded by reading and ignoring tcell events"
+	splashStart := time.Now()
+	for time.Since(splashStart) < 1000*time.Millisecond {
+		select {
+		case <-a.d.Events():
+			// Event discarded (consumed but ignored)
+		case <-time.After(10 * time.Millisecond):
+			// No event, continue waiting
+		}
+	}
+}
+
+// runCommand executes a command item through the hooks and shows its result
+func (a *App) runCommand(item config.MenuItem) {
+	// Get the command for the current OS
+	command := item.Exec.CommandForOS(exec.GetOS())
+
+	if a.Hooks.BeforeCommand != nil && !a.Hooks.BeforeCommand(item, command) {
+		logging.Info("command skipped by BeforeCommand hook", "label", item.Label)
+		return
+	}
+
+	// Input typed into the prompt is piped in once the user submits it
+	if item.Stdin == config.StdinPrompt {
+		a.d.Push(ui.NewInputView(a.screen, i18n.Tf("Input for %s", item.Label), func(text string) {
+			a.d.Pop()
+			a.startCommand(item, command, strings.NewReader(text))
+		}, a.d.Pop))
+		return
+	}
+	stdin, err := item.OpenStdin()
+	if err != nil {
+		a.showError(i18n.T("Launch Error"), err.Error())
+		return
+	}
+	if stdin != nil {
+		defer stdin.Close()
+	}
+	a.startCommand(item, command, stdin)
+}
+
+// openURL opens a link with the OS's handler; tests replace it
+var openURL = exec.OpenURL
+
+// openLink opens a url item's link. The browser would open on this machine,
+// so remote sessions are refused.
+func (a *App) openLink(item config.MenuItem) {
+	if a.Remote {
+		a.showError(i18n.T("Launch Error"), i18n.Tf("'%s' opens a link on this machine and cannot be used in a remote session.", item.Label))
+		return
+	}
+	if err := openURL(item.URL); err != nil {
+		a.showError(i18n.T("Launch Error"), err.Error())
+	}
+}
+
+// startCommand runs a command item with stdin as its input (nil for none) and
+// shows its result
+func (a *App) startCommand(item config.MenuItem, command string, stdin io.Reader) {
+	// Only commands given a terminal (interactive or launched) can answer sudo's password prompt
+	mode := a.cfg.LaunchFor(item)
+	if a.Remote && mode != "inline" && a.Hooks.RunCommand == nil {
+		a.showError(i18n.T("Launch Error"), i18n.Tf("'%s' uses launch: %s, which needs this machine's terminal and cannot run in a remote session.", item.Label, mode))
+		return
+	}
+	command, workDir, err := exec.RunAs(command, item.Exec.WorkDir, item.RunAs, mode != "inline")
+	if err != nil {
+		a.showError(i18n.T("Launch Error"), err.Error())
+		return
+	}
+	// Set when the command was given as a program and arguments, to run without a shell
+	argv := item.ArgvForOS(exec.GetOS())
+
+	hook := a.cfg.WebhookFor(item)
+	path := a.navigator.SelectedPath()
+	started := time.Now()
+	a.notify(hook, webhook.Event{Event: webhook.Start, Item: path, Label: item.Label, Command: command, Time: started})
+
+	// Commands launched in another terminal run alongside the menu; there is
+	// no output to show, so only a failure to open the terminal is reported.
+	// Their finish is never seen, so webhooks only get the start event.
+	if mode != "inline" && mode != "interactive" && a.Hooks.RunCommand == nil {
+		if err := exec.Launch(mode, command, workDir); err != nil {
+			a.showError(i18n.T("Launch Error"), err.Error())
+		}
+		return
+	}
+	interactive := mode == "interactive" && a.Hooks.RunCommand == nil
+
+	// Execute command and capture output
+	var output string
+	exitCode := -1
+	interrupted, timedOut := false, false
+	if a.Hooks.RunCommand != nil {
+		output = a.Hooks.RunCommand(item, command)
+	} else if interactive {
+		// The user has seen the command's output in the terminal already
+		if exitCode, err = exec.ExecuteInteractive(a.screen, command, argv, workDir, stdin); err != nil {
+			a.showError(i18n.T("Launch Error"), err.Error())
+		}
+	} else {
+		ctx, cancel := commandContext(a.ctx, item)
+		defer cancel()
+		if a.cfg.IsCtrlCInterruptEnabled() {
+			output, exitCode, interrupted = a.captureInterruptible(ctx, command, argv, workDir, stdin)
+		} else {
+			output, exitCode = exec.ExecuteAndCaptureContext(ctx, command, argv, workDir, stdin)
+		}
+		timedOut = ctx.Err() == context.DeadlineExceeded
+	}
+
+	a.bell(config.BellComplete)
+
+	finished := webhook.Event{Event: webhook.Finish, Item: path, Label: item.Label, Command: command, Time: time.Now()}
+	duration := finished.Time.Sub(started).Milliseconds()
+	finished.DurationMS = &duration
+	if a.Hooks.RunCommand == nil {
+		// A custom runner returns only output, so its exit code is unknown
+		finished.ExitCode = &exitCode
+	}
+	a.notify(hook, finished)
+
+	if a.Hooks.AfterCommand != nil {
+		a.Hooks.AfterCommand(item, command, output)
+	}
+
+	// Determine if we should show output
+	showOutput := true // Default
+	if item.ShowOutput != nil {
+		showOutput = *item.ShowOutput
+	}
+
+	if interrupted {
+		output = strings.TrimSpace(output + "\n\n" + i18n.T("(Interrupted with Ctrl+C)"))
+	} else if timedOut {
+		output = strings.TrimSpace(output + "\n\n" + i18n.Tf("(Stopped after the %s timeout)", item.Timeout))
+	}
+
+	if interactive && exitCode == 0 {
+		return
+	} else if showOutput && output != "" {
+		// Display output in scrollable viewer
+		view := ui.NewOutputView(a.screen, output, a.d.Pop)
+		if a.Hooks.RunCommand == nil {
+			view.WithStatus(exitCode == 0, exec.ExitStatus(exitCode))
+		}
+		a.d.Push(view)
+	} else if a.Hooks.RunCommand != nil || exitCode == 0 {
+		// No output or user chose to hide output
+		view := ui.NewMessageView(a.screen, i18n.T("Command Executed"), i18n.T("Command finished successfully."), a.d.Pop)
+		if a.Hooks.RunCommand == nil {
+			view.WithStatus(true)
+		}
+		a.d.Push(view)
+	} else {
+		a.d.Push(ui.NewMessageView(a.screen, i18n.T("Command Failed"), exec.ExitStatus(exitCode)+".", a.d.Pop).WithStatus(false))
+	}
+}
+
+// commandContext returns the context an item's command runs under: ctx,
+// bounded by the item's timeout if it has one
+func commandContext(ctx context.Context, item config.MenuItem) (context.Context, context.CancelFunc) {
+	if d := item.TimeoutDuration(); d > 0 {
+		return context.WithTimeout(ctx, d)
+	}
+	return context.WithCancel(ctx)
+}
+
+// captureInterruptible runs a command like exec.ExecuteAndCapture while
+// watching input, so Ctrl+C interrupts the command instead of being queued
+// for the menu. Other input received while the command runs is discarded.
+// Returns the output, the exit code, and whether the command was interrupted.
+func (a *App) captureInterruptible(ctx context.Context, command string, argv []string, workDir string, stdin io.Reader) (string, int, bool) {
+	var output string
+	var exitCode int
+	interrupted := a.runInterruptible(ctx, command, func(ctx context.Context) {
+		output, exitCode = exec.ExecuteAndCaptureContext(ctx, command, argv, workDir, stdin)
+	})
+	return output, exitCode, interrupted
+}
+
+// runInterruptible calls run in the background and waits for it, cancelling
+// its context (derived from parent) if Ctrl+C is pressed meanwhile. Other input
+// is discarded. name identifies what is running in the log. Reports whether it
+// was interrupted.
+func (a *App) runInterruptible(parent context.Context, name string, run func(ctx context.Context)) bool {
+	ctx, cancel := context.WithCancel(parent)
+	defer cancel()
+
+	done := make(chan struct{})
+	go func() {
+		run(ctx)
+		close(done)
+	}()
+
+	events := a.d.Events()
+	interrupted := false
+	for {
+		select {
+		case <-done:
+			return interrupted
+		case ev, ok := <-events:
+			if !ok {
+				events = nil // input closed; just wait for the command
+				continue
+			}
+			if keyEv, ok := ev.(*tcell.EventKey); ok && keyEv.Key() == tcell.KeyCtrlC && !interrupted {
+				logging.Info("interrupting command", "command", name)
+				interrupted = true
+				cancel()
+			}
+		}
+	}
+}
+
+// webhookFlushTimeout bounds how long exit waits for queued webhook events
+const webhookFlushTimeout = 3 * time.Second
+
+// notify queues a webhook event for url, if there is one
+func (a *App) notify(url string, event webhook.Event) {
+	if url == "" {
+		return
+	}
+	if a.notifier == nil {
+		a.notifier = webhook.NewNotifier()
+		a.hostname, _ = os.Hostname()
+	}
+	event.Host = a.hostname
+	a.notifier.Send(url, event)
+}
@@ -0,0 +1,165 @@
+package menuworks
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+
+	"github.com/benworks/menuworks/app"
+	"github.com/benworks/menuworks/config"
+	"github.com/benworks/menuworks/exec"
+	"github.com/benworks/menuworks/menu"
+	"github.com/benworks/menuworks/shell"
+	"github.com/benworks/menuworks/ui"
+)
+
+// newTestApp returns an app for cfg drawing to a headless screen, ready to
+// run commands without Run, and the channel its input events come from
+func newTestApp(t *testing.T, cfg *config.Config) (*App, tcell.SimulationScreen, chan<- tcell.Event) {
+	t.Helper()
+	s, sim, err := ui.NewSimulationScreen(80, 24)
+	if err != nil {
+		t.Fatalf("init simulation screen: %v", err)
+	}
+	t.Cleanup(s.Close)
+	a := NewApp(cfg)
+	a.ctx = context.Background()
+	a.screen = s
+	events := make(chan tcell.Event, 8)
+	a.d = app.NewDispatcher(events)
+	a.navigator = menu.NewNavigator(cfg)
+	return a, sim, events
+}
+
+// screenText returns the characters on the simulation screen, one line per row
+func screenText(sim tcell.SimulationScreen) string {
+	cells, w, h := sim.GetContents()
+	var b strings.Builder
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			b.WriteString(string(cells[y*w+x].Runes))
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+func TestEditConfigRefusedRemotely(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	t.Setenv("VISUAL", "touch "+path+".edited")
+	item := config.MenuItem{Type: "edit_config", Label: "Edit Menu"}
+	a, sim, events := newTestApp(t, &config.Config{Title: "Root", Items: []config.MenuItem{item}})
+	a.ConfigPath = path
+	a.Remote = true
+
+	events <- tcell.NewEventKey(tcell.KeyEnter, 0, tcell.ModNone)
+	v := &menuView{a: a}
+	v.handleSelection()
+
+	if _, err := os.Stat(path + ".edited"); err == nil {
+		t.Error("expected the editor not to run in a remote session")
+	}
+	if text := screenText(sim); !strings.Contains(text, "remote session") {
+		t.Errorf("expected an error about the remote session, got:\n%s", text)
+	}
+}
+
+func TestRemoteRefusesTerminalLaunchModes(t *testing.T) {
+	marker := filepath.Join(t.TempDir(), "ran")
+	touch := "touch " + marker
+	item := config.MenuItem{Type: "command", Label: "Top", Launch: "interactive",
+		Exec: config.ExecConfig{Windows: "type nul > " + marker, Linux: touch, Mac: touch}}
+	a, sim, events := newTestApp(t, &config.Config{Title: "Root", Items: []config.MenuItem{item}})
+	a.Remote = true
+
+	// Dismiss the error dialog once it is shown
+	events <- tcell.NewEventKey(tcell.KeyEnter, 0, tcell.ModNone)
+	a.runCommand(item)
+
+	if _, err := os.Stat(marker); err == nil {
+		t.Error("expected the interactive command not to run in a remote session")
+	}
+	if text := screenText(sim); !strings.Contains(text, "remote session") {
+		t.Errorf("expected an error about the remote session, got:\n%s", text)
+	}
+}
+
+func TestBrowseRunsCommandOnPickedFile(t *testing.T) {
+	root := t.TempDir()
+	for _, name := range []string{"Super Mario.nes", "readme.txt"} {
+		if err := os.WriteFile(filepath.Join(root, name), nil, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	item := config.MenuItem{Type: "browse", Label: "NES", Path: root, Extensions: []string{"nes"},
+		Exec: config.ExecConfig{Windows: "fceux {file}", Linux: "fceux {file}", Mac: "fceux {file}"}}
+	a, _, events := newTestApp(t, &config.Config{Title: "Root", Items: []config.MenuItem{item}})
+	var ran []string
+	a.Hooks.RunCommand = func(item config.MenuItem, command string) string {
+		ran = append(ran, command)
+		return "started"
+	}
+
+	// Pick the ROM, then close the output
+	events <- tcell.NewEventKey(tcell.KeyEnter, 0, tcell.ModNone)
+	events <- tcell.NewEventKey(tcell.KeyEnter, 0, tcell.ModNone)
+	(&menuView{a: a}).handleSelection()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := a.d.Run(ctx); err != nil {
+		t.Fatalf("file picker did not finish: %v", err)
+	}
+	want := "fceux " + shell.Quote(exec.GetOS(), []string{filepath.Join(root, "Super Mario.nes")})
+	if len(ran) != 1 || ran[0] != want {
+		t.Errorf("expected %q to run, got %q", want, ran)
+	}
+}
+
+func TestBrowseMissingFolder(t *testing.T) {
+	item := config.MenuItem{Type: "browse", Label: "NES", Path: filepath.Join(t.TempDir(), "gone"),
+		Exec: config.ExecConfig{Windows: "fceux {file}", Linux: "fceux {file}", Mac: "fceux {file}"}}
+	a, sim, events := newTestApp(t, &config.Config{Title: "Root", Items: []config.MenuItem{item}})
+
+	events <- tcell.NewEventKey(tcell.KeyEnter, 0, tcell.ModNone)
+	(&menuView{a: a}).handleSelection()
+	if text := screenText(sim); !strings.Contains(text, "Can't browse") {
+		t.Errorf("expected an error about the missing folder, got:\n%s", text)
+	}
+	if a.d.Focused() != nil {
+		t.Errorf("expected no file picker to open")
+	}
+}
+
+func TestURLItemOpensLink(t *testing.T) {
+	var opened []string
+	saved := openURL
+	openURL = func(url string) error {
+		opened = append(opened, url)
+		return nil
+	}
+	t.Cleanup(func() { openURL = saved })
+	item := config.MenuItem{Type: "url", Label: "Docs", URL: "https://example.com/docs"}
+	a, sim, events := newTestApp(t, &config.Config{Title: "Root", Items: []config.MenuItem{item}})
+
+	(&menuView{a: a}).handleSelection()
+	if len(opened) != 1 || opened[0] != item.URL {
+		t.Fatalf("expected the link to be opened, got %v", opened)
+	}
+
+	// A remote session's browser would open on this machine
+	a.Remote = true
+	events <- tcell.NewEventKey(tcell.KeyEnter, 0, tcell.ModNone)
+	(&menuView{a: a}).handleSelection()
+	if len(opened) != 1 {
+		t.Errorf("expected the link not to be opened in a remote session, got %v", opened)
+	}
+	if text := screenText(sim); !strings.Contains(text, "remote session") {
+		t.Errorf("expected an error about the remote session, got:\n%s", text)
+	}
+}
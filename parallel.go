@@ -0,0 +1,111 @@
+package menuworks
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/benworks/menuworks/config"
+	"github.com/benworks/menuworks/exec"
+	"github.com/benworks/menuworks/i18n"
+	"github.com/benworks/menuworks/logging"
+	"github.com/benworks/menuworks/ui"
+	"github.com/benworks/menuworks/webhook"
+)
+
+// runParallel runs a parallel item's commands for this OS at the same time,
+// waits for all of them, and shows their combined output with each line
+// prefixed by its command's label. The header counts the commands that succeeded.
+func (a *App) runParallel(item config.MenuItem) {
+	osType := exec.GetOS()
+	var commands []config.MenuItem
+	for _, c := range item.CommandsForOS(osType) {
+		if a.Hooks.BeforeCommand != nil && !a.Hooks.BeforeCommand(c, c.Exec.CommandForOS(osType)) {
+			logging.Info("command skipped by BeforeCommand hook", "label", c.Label)
+			continue
+		}
+		commands = append(commands, c)
+	}
+	if len(commands) == 0 {
+		return
+	}
+	jobs, err := exec.ParallelJobs(commands, osType)
+	if err != nil {
+		a.showError(i18n.T("Launch Error"), err.Error())
+		return
+	}
+	defer exec.CloseJobs(jobs)
+	lines := make([]string, len(jobs))
+	for i := range jobs {
+		lines[i] = jobs[i].Command
+		if a.Hooks.RunCommand != nil {
+			// A custom runner returns only output, so its exit code is unknown
+			c, command := jobs[i].Item, jobs[i].Command
+			jobs[i].Run = func(context.Context) (string, int) {
+				return a.Hooks.RunCommand(c, command), 0
+			}
+		}
+	}
+
+	hook := a.cfg.WebhookFor(item)
+	path := a.navigator.SelectedPath()
+	summary := strings.Join(lines, "\n")
+	started := time.Now()
+	a.notify(hook, webhook.Event{Event: webhook.Start, Item: path, Label: item.Label, Command: summary, Time: started})
+	logging.Info("running parallel item", "label", item.Label, "commands", len(commands))
+
+	var results []exec.JobResult
+	run := func(ctx context.Context) {
+		results = exec.RunParallel(ctx, jobs)
+	}
+	ctx, cancel := commandContext(a.ctx, item)
+	defer cancel()
+	interrupted := false
+	if a.cfg.IsCtrlCInterruptEnabled() {
+		interrupted = a.runInterruptible(ctx, item.Label, run)
+	} else {
+		run(ctx)
+	}
+	timedOut := ctx.Err() == context.DeadlineExceeded
+
+	a.bell(config.BellComplete)
+
+	succeeded, exitCode := exec.CountSucceeded(results)
+	finished := webhook.Event{Event: webhook.Finish, Item: path, Label: item.Label, Command: summary, Time: time.Now()}
+	duration := finished.Time.Sub(started).Milliseconds()
+	finished.DurationMS = &duration
+	if a.Hooks.RunCommand == nil {
+		// The first failure's exit code, or 0 when every command succeeded
+		finished.ExitCode = &exitCode
+	}
+	a.notify(hook, finished)
+
+	if a.Hooks.AfterCommand != nil {
+		for i, j := range jobs {
+			a.Hooks.AfterCommand(j.Item, lines[i], results[i].Output)
+		}
+	}
+
+	output := exec.CombineOutput(results)
+	if interrupted {
+		output = strings.TrimSpace(output + "\n\n" + i18n.T("(Interrupted with Ctrl+C)"))
+	} else if timedOut {
+		output = strings.TrimSpace(output + "\n\n" + i18n.Tf("(Stopped after the %s timeout)", item.Timeout))
+	}
+	ok := succeeded == len(results)
+	status := i18n.Tf("%d of %d succeeded", succeeded, len(results))
+
+	if item.ShowOutput != nil && !*item.ShowOutput {
+		if a.Hooks.RunCommand != nil {
+			a.d.Push(ui.NewMessageView(a.screen, i18n.T("Command Executed"), i18n.T("Command finished successfully."), a.d.Pop))
+		} else {
+			a.d.Push(ui.NewMessageView(a.screen, i18n.T("Command Executed"), status+".", a.d.Pop).WithStatus(ok))
+		}
+		return
+	}
+	view := ui.NewOutputView(a.screen, output, a.d.Pop)
+	if a.Hooks.RunCommand == nil {
+		view.WithStatus(ok, status)
+	}
+	a.d.Push(view)
+}
@@ -0,0 +1,38 @@
+package menuworks
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/benworks/menuworks/config"
+	"github.com/benworks/menuworks/ui"
+)
+
+func TestRunParallelCombinesOutput(t *testing.T) {
+	both := func(command string) config.ExecConfig {
+		return config.ExecConfig{Windows: command, Linux: command, Mac: command}
+	}
+	item := config.MenuItem{Type: "parallel", Label: "Stack", Commands: []config.MenuItem{
+		{Type: "command", Label: "api", Exec: both("echo listening")},
+		{Type: "command", Label: "database", Exec: both("exit 4")},
+	}}
+	a, sim, _ := newTestApp(t, &config.Config{Title: "Root", Items: []config.MenuItem{item}})
+
+	a.runParallel(item)
+
+	view, ok := a.d.Focused().(*ui.OutputView)
+	if !ok {
+		t.Fatalf("expected the output viewer, got %T", a.d.Focused())
+	}
+	view.Draw()
+	text := screenText(sim)
+	for _, want := range []string{
+		"1 of 2 succeeded",
+		"[api]      listening",
+		"[database] Exit code 4",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("expected %q on screen, got:\n%s", want, text)
+		}
+	}
+}
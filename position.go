@@ -0,0 +1,58 @@
+package menuworks
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/benworks/menuworks/logging"
+	"github.com/benworks/menuworks/menu"
+)
+
+// positionSaveInterval is how often the menu position is written while
+// restore_position is on, bounding what a crash can lose
+const positionSaveInterval = 3 * time.Second
+
+// restorePosition resumes the menu path and selection saved by a previous run
+func (a *App) restorePosition() {
+	s, err := menu.LoadState(menu.StatePath(a.ConfigPath))
+	if err != nil {
+		logging.Debug("no saved position", "err", err)
+		return
+	}
+	a.navigator.RestoreState(s)
+	a.savedPosition, _ = json.Marshal(a.navigator.State())
+	logging.Info("restored position", "menu", a.navigator.GetCurrentMenuName())
+}
+
+// startPositionSaver saves the position every positionSaveInterval until the app stops
+func (a *App) startPositionSaver() {
+	a.positionTimer = a.d.AfterFunc(positionSaveInterval, func() {
+		if a.d.Stopped() {
+			return
+		}
+		a.savePosition()
+		a.startPositionSaver()
+	})
+}
+
+// stopPositionSaver cancels the periodic save and writes the final position
+func (a *App) stopPositionSaver() {
+	if a.positionTimer != nil {
+		a.positionTimer.Stop()
+	}
+	a.savePosition()
+}
+
+// savePosition writes the current position if it changed since the last save
+func (a *App) savePosition() {
+	s := a.navigator.State()
+	data, err := json.Marshal(s)
+	if err != nil || string(data) == string(a.savedPosition) {
+		return
+	}
+	if err := menu.SaveState(menu.StatePath(a.ConfigPath), s); err != nil {
+		logging.Warn("failed to save position", "err", err)
+		return
+	}
+	a.savedPosition = data
+}
@@ -0,0 +1,100 @@
+package menuworks
+
+import (
+	"crypto/subtle"
+	"time"
+
+	"github.com/benworks/menuworks/config"
+	"github.com/benworks/menuworks/i18n"
+	"github.com/benworks/menuworks/logging"
+	"github.com/benworks/menuworks/menu"
+	"github.com/benworks/menuworks/secrets"
+	"github.com/benworks/menuworks/ui"
+)
+
+// pinRetryDelay is how long a wrong PIN holds up the next try, so PINs
+// can't be guessed quickly; tests shorten it
+var pinRetryDelay = 2 * time.Second
+
+// startRole returns the role a session starts in: Role if set, else the
+// config's default_role
+func (a *App) startRole() string {
+	role := a.Role
+	if role == "" {
+		role = a.cfg.DefaultRole
+	}
+	if _, exists := a.cfg.Roles[role]; role != "" && !exists {
+		logging.Warn("unknown role", "role", role)
+	}
+	return role
+}
+
+// newNavigator returns a navigator for cfg that shows the active role's items
+func (a *App) newNavigator(cfg *config.Config) *menu.Navigator {
+	return menu.NewNavigatorForRole(cfg, a.role)
+}
+
+// menuBarred reports whether the menu called name may only be opened from
+// the menu, or not at all: it is locked, or the active role can't reach it
+func (a *App) menuBarred(name string) bool {
+	return a.menuLocked(name) || a.navigator.Hidden(name)
+}
+
+// selectRole shows the role switcher for a switch_role item and changes to
+// the chosen role, asking for its PIN first if it has one
+func (a *App) selectRole() {
+	names := a.cfg.RoleNames()
+	labels := make([]string, len(names))
+	current := -1
+	for i, name := range names {
+		labels[i] = a.cfg.RoleLabel(name)
+		if name == a.role {
+			current = i
+		}
+	}
+
+	choice := a.screen.DrawSelectList(i18n.T("Switch Role"), labels, max(current, 0), current, a.d.Events())
+	if choice < 0 || choice == current {
+		return
+	}
+	name := names[choice]
+	pin := a.cfg.Roles[name].PIN
+	if pin == "" {
+		a.switchRole(name)
+		return
+	}
+	if secrets.Contains(pin) {
+		resolved, err := secrets.Resolve(pin)
+		if err != nil {
+			logging.Error("role pin unavailable", "role", name, "err", err)
+			a.showError(i18n.T("Switch Role"), i18n.Tf("The PIN for %s could not be read: %v", labels[choice], err))
+			return
+		}
+		pin = resolved
+	}
+
+	var view *ui.LoginView
+	view = ui.NewPINView(a.screen, i18n.T("Switch Role"), i18n.Tf("Enter the PIN for %s.", labels[choice]), func(entered string) {
+		if subtle.ConstantTimeCompare([]byte(entered), []byte(pin)) == 1 {
+			a.d.Pop()
+			a.switchRole(name)
+			return
+		}
+		logging.Warn("role switch refused", "role", name)
+		view.SetBusy()
+		a.d.AfterFunc(pinRetryDelay, func() {
+			if a.d.Focused() == view {
+				view.Fail(i18n.T("Wrong PIN."))
+			}
+		})
+	}, a.d.Pop)
+	a.d.Push(view)
+}
+
+// switchRole makes name the active role and starts over at the root menu
+// with its items
+func (a *App) switchRole(name string) {
+	logging.Info("role switched", "from", a.role, "to", name)
+	a.role = name
+	a.navigator = a.newNavigator(a.cfg)
+}
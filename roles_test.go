@@ -0,0 +1,118 @@
+package menuworks
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+
+	"github.com/benworks/menuworks/config"
+)
+
+// rolesConfig has a kids role that starts by default and a parent role,
+// behind a PIN, that also sees Admin
+func rolesConfig() *config.Config {
+	return &config.Config{
+		Title: "Root",
+		Roles: map[string]config.Role{
+			"kids":   {Label: "Kids"},
+			"parent": {Label: "Parent", PIN: "4821"},
+		},
+		DefaultRole: "kids",
+		Items: []config.MenuItem{
+			{Type: "switch_role", Label: "Switch Role"},
+			{Type: "command", Label: "Homework", Exec: config.ExecConfig{Linux: "true"}},
+			{Type: "command", Label: "Admin", Exec: config.ExecConfig{Linux: "true"}, Roles: []string{"parent"}},
+		},
+	}
+}
+
+// newRolesApp returns a test app in its starting role, like run
+func newRolesApp(t *testing.T, cfg *config.Config) (*App, tcell.SimulationScreen, chan<- tcell.Event) {
+	a, sim, events := newTestApp(t, cfg)
+	a.role = a.startRole()
+	a.navigator = a.newNavigator(cfg)
+	return a, sim, events
+}
+
+// labels returns the labels of the current menu's items
+func labels(a *App) string {
+	var names []string
+	for _, item := range a.navigator.GetCurrentMenu() {
+		names = append(names, item.Label)
+	}
+	return strings.Join(names, ", ")
+}
+
+func TestRoleFiltersItems(t *testing.T) {
+	a, _, _ := newRolesApp(t, rolesConfig())
+	if got := labels(a); got != "Switch Role, Homework" {
+		t.Errorf("expected the default role not to see Admin, got %q", got)
+	}
+
+	a, _, _ = newTestApp(t, rolesConfig())
+	a.Role = "parent"
+	a.role = a.startRole()
+	a.navigator = a.newNavigator(a.cfg)
+	if got := labels(a); got != "Switch Role, Homework, Admin" {
+		t.Errorf("expected Role to win over default_role, got %q", got)
+	}
+}
+
+func TestSwitchRoleNeedsPIN(t *testing.T) {
+	a, _, events := newRolesApp(t, rolesConfig())
+
+	// Pick Parent from the role list, then enter its PIN
+	events <- tcell.NewEventKey(tcell.KeyDown, 0, tcell.ModNone)
+	events <- tcell.NewEventKey(tcell.KeyEnter, 0, tcell.ModNone)
+	typeKeys(events, "4821")
+	events <- tcell.NewEventKey(tcell.KeyEnter, 0, tcell.ModNone)
+	(&menuView{a: a}).handleSelection()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := a.d.Run(ctx); err != nil {
+		t.Fatalf("PIN entry did not finish: %v", err)
+	}
+	if a.role != "parent" {
+		t.Errorf("expected the parent role, got %q", a.role)
+	}
+	if got := labels(a); got != "Switch Role, Homework, Admin" {
+		t.Errorf("expected the parent's items, got %q", got)
+	}
+
+	// Back to a role without a PIN needs nothing more
+	events <- tcell.NewEventKey(tcell.KeyUp, 0, tcell.ModNone)
+	events <- tcell.NewEventKey(tcell.KeyEnter, 0, tcell.ModNone)
+	a.navigator.SetSelectionIndex(0)
+	(&menuView{a: a}).handleSelection()
+	if a.role != "kids" || a.d.Focused() != nil {
+		t.Errorf("expected to switch to kids straight away, got %q", a.role)
+	}
+}
+
+func TestSwitchRoleRefusesWrongPIN(t *testing.T) {
+	saved := pinRetryDelay
+	pinRetryDelay = 10 * time.Millisecond
+	t.Cleanup(func() { pinRetryDelay = saved })
+	a, sim, events := newRolesApp(t, rolesConfig())
+	sim.SetSize(80, 25)
+
+	events <- tcell.NewEventKey(tcell.KeyDown, 0, tcell.ModNone)
+	events <- tcell.NewEventKey(tcell.KeyEnter, 0, tcell.ModNone)
+	typeKeys(events, "1234")
+	events <- tcell.NewEventKey(tcell.KeyEnter, 0, tcell.ModNone)
+	(&menuView{a: a}).handleSelection()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 300*time.Millisecond)
+	defer cancel()
+	a.d.Run(ctx)
+	if a.role != "kids" {
+		t.Errorf("expected a wrong PIN to keep the kids role, got %q", a.role)
+	}
+	if got := screenText(sim); !strings.Contains(got, "Wrong PIN.") {
+		t.Errorf("expected the wrong PIN to be reported, got:\n%s", got)
+	}
+}
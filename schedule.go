@@ -0,0 +1,24 @@
+package menuworks
+
+import "time"
+
+// startScheduleTimer re-checks available_hours and available_days at the
+// start of each minute, the finest step a window can have, so items grey
+// out and come back as their windows close and open
+func (a *App) startScheduleTimer() {
+	now := time.Now()
+	a.scheduleTimer = a.d.AfterFunc(now.Truncate(time.Minute).Add(time.Minute).Sub(now), func() {
+		if a.d.Stopped() {
+			return
+		}
+		a.navigator.RefreshSchedule()
+		a.startScheduleTimer()
+	})
+}
+
+// stopScheduleTimer cancels the schedule check
+func (a *App) stopScheduleTimer() {
+	if a.scheduleTimer != nil {
+		a.scheduleTimer.Stop()
+	}
+}
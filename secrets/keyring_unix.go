@@ -0,0 +1,34 @@
+//go:build !windows
+
+package secrets
+
+import (
+	"errors"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// lookupKeyring reads name from the login Keychain on macOS, where it is
+// stored with `security add-generic-password -s menuworks -a NAME -w`, and
+// elsewhere from libsecret, where it is stored with
+// `secret-tool store --label=NAME service menuworks name NAME`
+func lookupKeyring(name string) (string, error) {
+	var cmd *exec.Cmd
+	if runtime.GOOS == "darwin" {
+		cmd = exec.Command("security", "find-generic-password", "-s", Service, "-a", name, "-w")
+	} else {
+		cmd = exec.Command("secret-tool", "lookup", "service", Service, "name", name)
+	}
+	out, err := cmd.Output()
+	if err != nil {
+		// Both tools exit non-zero for a missing entry, and a machine without
+		// them has no keyring to look in
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) || errors.Is(err, exec.ErrNotFound) {
+			return "", errNoKeyring
+		}
+		return "", err
+	}
+	return strings.TrimSuffix(string(out), "\n"), nil
+}
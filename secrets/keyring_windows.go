@@ -0,0 +1,69 @@
+//go:build windows
+
+package secrets
+
+import (
+	"bytes"
+	"syscall"
+	"unicode/utf16"
+	"unsafe"
+)
+
+var (
+	advapi32     = syscall.NewLazyDLL("advapi32.dll")
+	procCredRead = advapi32.NewProc("CredReadW")
+	procCredFree = advapi32.NewProc("CredFree")
+)
+
+const (
+	credTypeGeneric = 1
+	errorNotFound   = syscall.Errno(1168)
+)
+
+// credential is the start of CREDENTIALW, up to the fields read here
+type credential struct {
+	Flags              uint32
+	Type               uint32
+	TargetName         *uint16
+	Comment            *uint16
+	LastWritten        syscall.Filetime
+	CredentialBlobSize uint32
+	CredentialBlob     *byte
+}
+
+// lookupKeyring reads name from Windows Credential Manager, where it is a
+// generic credential with the target menuworks:NAME, stored with
+// `cmdkey /generic:menuworks:NAME /user:menuworks /pass`
+func lookupKeyring(name string) (string, error) {
+	target, err := syscall.UTF16PtrFromString(Service + ":" + name)
+	if err != nil {
+		return "", err
+	}
+	var cred *credential
+	ok, _, err := procCredRead.Call(uintptr(unsafe.Pointer(target)), credTypeGeneric, 0, uintptr(unsafe.Pointer(&cred)))
+	if ok == 0 {
+		if err == errorNotFound {
+			return "", errNoKeyring
+		}
+		return "", err
+	}
+	defer procCredFree.Call(uintptr(unsafe.Pointer(cred)))
+	if cred.CredentialBlobSize == 0 {
+		return "", nil
+	}
+	return decodeBlob(unsafe.Slice(cred.CredentialBlob, cred.CredentialBlobSize)), nil
+}
+
+// decodeBlob returns a credential's password. cmdkey and the Credential
+// Manager window store it as UTF-16; other tools may store UTF-8, which has
+// no zero bytes.
+func decodeBlob(blob []byte) string {
+	if len(blob)%2 != 0 || bytes.IndexByte(blob, 0) < 0 {
+		return string(blob)
+	}
+	units := make([]uint16, len(blob)/2)
+	for i := range units {
+		units[i] = uint16(blob[2*i]) | uint16(blob[2*i+1])<<8
+	}
+	return string(utf16.Decode(units))
+}
@@ -0,0 +1,286 @@
+// Package secrets resolves secret://name references in menu commands, so API
+// tokens and passwords a command needs can be kept out of config.yaml. A name
+// is looked up first in the OS keyring (Windows Credential Manager, the macOS
+// Keychain, or libsecret through secret-tool) and then in an encrypted file,
+// secrets.enc next to the config, whose passphrase comes from the
+// MENUWORKS_SECRETS_KEY environment variable.
+package secrets
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/scrypt"
+
+	"github.com/benworks/menuworks/logging"
+)
+
+const (
+	// Scheme starts a reference to a secret in a command
+	Scheme = "secret://"
+	// Service is the keyring service (macOS, libsecret) or target prefix
+	// (Windows, "menuworks:name") secrets are stored under
+	Service = "menuworks"
+	// KeyEnv is the environment variable holding the encrypted file's passphrase
+	KeyEnv = "MENUWORKS_SECRETS_KEY"
+	// FileName is the encrypted file kept next to the config
+	FileName = "secrets.enc"
+)
+
+// reference matches secret://name; names are letters, digits, ".", "_" and "-"
+var reference = regexp.MustCompile(`secret://([A-Za-z0-9._-]+)`)
+
+// ErrNotFound is returned by Lookup for a name in neither the keyring nor the file
+var ErrNotFound = errors.New("secret not found")
+
+// errNoKeyring is returned by keyringLookup when the name isn't in the
+// keyring, or there is no keyring to look in
+var errNoKeyring = errors.New("not in keyring")
+
+// keyringLookup reads a secret from the OS keyring; replaced in tests
+var keyringLookup = lookupKeyring
+
+// file is the encrypted file Lookup falls back to, and its last decrypted
+// contents, reused until the file changes
+var file struct {
+	sync.Mutex
+	path    string
+	modTime time.Time
+	key     string
+	values  map[string]string
+}
+
+// SetFile makes Lookup fall back to the encrypted file at path. An empty
+// path means the keyring only.
+func SetFile(path string) {
+	file.Lock()
+	defer file.Unlock()
+	file.path = path
+	file.values = nil
+}
+
+// DefaultFile returns the encrypted file used with the config at configPath
+func DefaultFile(configPath string) string {
+	return filepath.Join(filepath.Dir(configPath), FileName)
+}
+
+// ValidName reports whether name can follow secret://
+func ValidName(name string) bool {
+	return reference.FindString(Scheme+name) == Scheme+name
+}
+
+// Contains reports whether s has any secret:// references
+func Contains(s string) bool {
+	return reference.MatchString(s)
+}
+
+// Resolve returns s with each secret://name replaced by the secret's value.
+// It is an error if any of them can't be found.
+func Resolve(s string) (string, error) {
+	var firstErr error
+	resolved := reference.ReplaceAllStringFunc(s, func(ref string) string {
+		value, err := Lookup(ref[len(Scheme):])
+		if err != nil && firstErr == nil {
+			firstErr = err
+		}
+		return value
+	})
+	if firstErr != nil {
+		return "", firstErr
+	}
+	return resolved, nil
+}
+
+// ResolveAll is Resolve for each of args. It returns args itself when none
+// of them has a reference.
+func ResolveAll(args []string) ([]string, error) {
+	var out []string
+	for i, arg := range args {
+		if !Contains(arg) {
+			if out != nil {
+				out[i] = arg
+			}
+			continue
+		}
+		if out == nil {
+			out = make([]string, len(args))
+			copy(out, args[:i])
+		}
+		value, err := Resolve(arg)
+		if err != nil {
+			return nil, err
+		}
+		out[i] = value
+	}
+	if out == nil {
+		return args, nil
+	}
+	return out, nil
+}
+
+// Lookup returns the secret called name from the keyring, or else from the
+// encrypted file set with SetFile
+func Lookup(name string) (string, error) {
+	value, err := keyringLookup(name)
+	if err == nil {
+		return value, nil
+	}
+	if !errors.Is(err, errNoKeyring) {
+		logging.Debug("keyring lookup failed", "secret", name, "err", err)
+	}
+
+	file.Lock()
+	defer file.Unlock()
+	if file.path == "" {
+		return "", fmt.Errorf("secret %q: %w in the keyring", name, ErrNotFound)
+	}
+	info, err := os.Stat(file.path)
+	if os.IsNotExist(err) {
+		return "", fmt.Errorf("secret %q: %w in the keyring or %s", name, ErrNotFound, file.path)
+	}
+	if err != nil {
+		return "", fmt.Errorf("secret %q: %w", name, err)
+	}
+	key := os.Getenv(KeyEnv)
+	if key == "" {
+		return "", fmt.Errorf("secret %q: %s is encrypted; set %s to its passphrase", name, file.path, KeyEnv)
+	}
+	if file.values == nil || !info.ModTime().Equal(file.modTime) || key != file.key {
+		values, err := ReadFile(file.path, key)
+		if err != nil {
+			return "", fmt.Errorf("secret %q: %w", name, err)
+		}
+		file.values, file.modTime, file.key = values, info.ModTime(), key
+	}
+	value, ok := file.values[name]
+	if !ok {
+		return "", fmt.Errorf("secret %q: %w in the keyring or %s", name, ErrNotFound, file.path)
+	}
+	return value, nil
+}
+
+// sealed is the encrypted file's format: the secrets as a JSON object,
+// encrypted with AES-256-GCM under a key derived from the passphrase by scrypt
+type sealed struct {
+	Version int    `json:"version"`
+	Salt    []byte `json:"salt"`
+	Nonce   []byte `json:"nonce"`
+	Data    []byte `json:"data"`
+}
+
+// scrypt parameters; N is the recommended 2^15 for interactive use
+const (
+	scryptN   = 1 << 15
+	scryptR   = 8
+	scryptP   = 1
+	saltBytes = 16
+)
+
+// ReadFile decrypts the file at path with passphrase and returns its secrets
+func ReadFile(path, passphrase string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var s sealed
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("%s is not a secrets file: %w", path, err)
+	}
+	if s.Version != 1 {
+		return nil, fmt.Errorf("%s: unsupported secrets file version %d", path, s.Version)
+	}
+	gcm, err := newGCM(passphrase, s.Salt)
+	if err != nil {
+		return nil, err
+	}
+	if len(s.Nonce) != gcm.NonceSize() {
+		return nil, fmt.Errorf("%s is damaged", path)
+	}
+	plain, err := gcm.Open(nil, s.Nonce, s.Data, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%s: wrong passphrase or damaged file", path)
+	}
+	values := map[string]string{}
+	if err := json.Unmarshal(plain, &values); err != nil {
+		return nil, fmt.Errorf("%s is damaged: %w", path, err)
+	}
+	return values, nil
+}
+
+// WriteFile encrypts values with passphrase into the file at path, readable
+// only by the current user. A fresh salt and nonce are used on every write.
+func WriteFile(path, passphrase string, values map[string]string) error {
+	if passphrase == "" {
+		return errors.New("the passphrase must not be empty")
+	}
+	plain, err := json.Marshal(values)
+	if err != nil {
+		return err
+	}
+	s := sealed{Version: 1, Salt: make([]byte, saltBytes)}
+	if _, err := rand.Read(s.Salt); err != nil {
+		return err
+	}
+	gcm, err := newGCM(passphrase, s.Salt)
+	if err != nil {
+		return err
+	}
+	s.Nonce = make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(s.Nonce); err != nil {
+		return err
+	}
+	s.Data = gcm.Seal(nil, s.Nonce, plain, nil)
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	// Write beside the file and rename over it, so a failed write can't
+	// leave the secrets half written; CreateTemp makes it mode 0600
+	tmp, err := os.CreateTemp(filepath.Dir(path), FileName+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// Names returns the names in values in order
+func Names(values map[string]string) []string {
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// newGCM returns the AES-256-GCM cipher keyed by passphrase and salt
+func newGCM(passphrase string, salt []byte) (cipher.AEAD, error) {
+	key, err := scrypt.Key([]byte(passphrase), salt, scryptN, scryptR, scryptP, 32)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,91 @@
+package secrets
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// fakeKeyring replaces the OS keyring with values for the test
+func fakeKeyring(t *testing.T, values map[string]string) {
+	t.Helper()
+	saved := keyringLookup
+	keyringLookup = func(name string) (string, error) {
+		if v, ok := values[name]; ok {
+			return v, nil
+		}
+		return "", errNoKeyring
+	}
+	t.Cleanup(func() { keyringLookup = saved; SetFile("") })
+}
+
+func TestFileRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), FileName)
+	values := map[string]string{"github.token": "ghp_123", "db-pass": "p@ss 'word'"}
+	if err := WriteFile(path, "correct horse", values); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "ghp_123") || strings.Contains(string(data), "github.token") {
+		t.Errorf("expected the file to be encrypted, got %s", data)
+	}
+
+	got, err := ReadFile(path, "correct horse")
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	if len(got) != 2 || got["github.token"] != "ghp_123" || got["db-pass"] != "p@ss 'word'" {
+		t.Errorf("expected the secrets back, got %v", got)
+	}
+	if _, err := ReadFile(path, "wrong"); err == nil || !strings.Contains(err.Error(), "wrong passphrase") {
+		t.Errorf("expected a wrong passphrase error, got %v", err)
+	}
+}
+
+func TestResolve(t *testing.T) {
+	fakeKeyring(t, map[string]string{"token": "from-keyring"})
+	path := filepath.Join(t.TempDir(), FileName)
+	if err := WriteFile(path, "pw", map[string]string{"token": "from-file", "db.pass": "hunter2"}); err != nil {
+		t.Fatal(err)
+	}
+	SetFile(path)
+	t.Setenv(KeyEnv, "pw")
+
+	got, err := Resolve("curl -H 'Authorization: Bearer secret://token' -u admin:secret://db.pass")
+	if err != nil {
+		t.Fatalf("Resolve: %v", err)
+	}
+	// The keyring wins over the file
+	if want := "curl -H 'Authorization: Bearer from-keyring' -u admin:hunter2"; got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+
+	if _, err := Resolve("echo secret://missing"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound for a missing secret, got %v", err)
+	}
+
+	args := []string{"deploy", "--token", "secret://token"}
+	resolved, err := ResolveAll(args)
+	if err != nil || strings.Join(resolved, " ") != "deploy --token from-keyring" || args[2] != "secret://token" {
+		t.Errorf("expected a resolved copy of the args, got %v (%v), original %v", resolved, err, args)
+	}
+
+	t.Setenv(KeyEnv, "")
+	if _, err := Resolve("secret://db.pass"); err == nil || !strings.Contains(err.Error(), KeyEnv) {
+		t.Errorf("expected an error naming %s without a passphrase, got %v", KeyEnv, err)
+	}
+}
+
+func TestValidName(t *testing.T) {
+	for name, want := range map[string]bool{"token": true, "github.api-token_2": true, "": false, "a b": false, "a/b": false} {
+		if got := ValidName(name); got != want {
+			t.Errorf("ValidName(%q) = %v, want %v", name, got, want)
+		}
+	}
+}
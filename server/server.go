@@ -0,0 +1,215 @@
+// Package server exposes a MenuWorks config over HTTP so other systems (home
+// automation, dashboards) can read the menu and run the same curated commands
+// as the TUI. Reading is open; running requires the bearer token.
+package server
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/benworks/menuworks/config"
+	"github.com/benworks/menuworks/exec"
+	"github.com/benworks/menuworks/logging"
+	"github.com/benworks/menuworks/menu"
+)
+
+// maxRequestBody bounds the size of a run request
+const maxRequestBody = 64 << 10
+
+// Server serves one loaded config
+type Server struct {
+	cfg   *config.Config
+	token string
+}
+
+// New returns a server for cfg. token must be non-empty; run requests have to
+// send it as "Authorization: Bearer <token>".
+func New(cfg *config.Config, token string) (*Server, error) {
+	if token == "" {
+		return nil, fmt.Errorf("a token is required to serve the run endpoint")
+	}
+	return &Server{cfg: cfg, token: token}, nil
+}
+
+// RunRequest is the body of POST /api/run
+type RunRequest struct {
+	Item string `json:"item"` // menu path or item id, as accepted by `menuworks run`
+}
+
+// RunResult is the response to POST /api/run
+type RunResult struct {
+	Item       string `json:"item"`
+	ExitCode   int    `json:"exit_code"` // -1 if the command could not start or was killed
+	Output     string `json:"output"`
+	DurationMS int64  `json:"duration_ms"`
+}
+
+// Handler returns the API routes:
+//
+//	GET  /api/menu  the menu tree (as `menuworks list -json`)
+//	POST /api/run   run a command or parallel item and return its output and exit code
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/menu", s.handleMenu)
+	mux.HandleFunc("/api/run", s.handleRun)
+	return mux
+}
+
+// handleMenu returns the resolved menu tree
+func (s *Server) handleMenu(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "use GET")
+		return
+	}
+	writeJSON(w, http.StatusOK, menu.Tree(s.cfg))
+}
+
+// handleRun executes a command or parallel item. The command is interrupted if the client
+// disconnects before it finishes.
+func (s *Server) handleRun(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "use POST")
+		return
+	}
+	if !s.authorized(r) {
+		logging.Warn("rejected run request", "remote", r.RemoteAddr)
+		writeError(w, http.StatusUnauthorized, "missing or invalid token")
+		return
+	}
+
+	var req RunRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestBody)).Decode(&req); err != nil || req.Item == "" {
+		writeError(w, http.StatusBadRequest, `expected {"item": "<menu path or id>"}`)
+		return
+	}
+
+	item, err := menu.FindItem(s.cfg, req.Item)
+	if errors.Is(err, menu.ErrLocked) {
+		writeError(w, http.StatusForbidden, err.Error())
+		return
+	} else if err != nil {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	if item.Type != "command" && item.Type != "parallel" {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("'%s' is a %s item, not a command", req.Item, item.Type))
+		return
+	}
+	if item.Disabled {
+		msg := fmt.Sprintf("'%s' is disabled", req.Item)
+		if item.DisabledReason != "" {
+			msg += ": " + item.DisabledReason
+		}
+		writeError(w, http.StatusBadRequest, msg)
+		return
+	}
+	if !item.AvailableAt(time.Now()) {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("'%s' is only available %s", req.Item, item.ScheduleText()))
+		return
+	}
+	if item.Type == "parallel" {
+		s.runParallel(w, r, req.Item, item)
+		return
+	}
+	command := item.Exec.CommandForOS(exec.GetOS())
+	if command == "" {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("'%s' has no command for this platform", req.Item))
+		return
+	}
+
+	// Nobody can answer a password prompt, so sudo must not ask
+	command, workDir, err := exec.RunAs(command, item.Exec.WorkDir, item.RunAs, false)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if item.Stdin == config.StdinPrompt {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("'%s' prompts for its input, which a remote run cannot answer", req.Item))
+		return
+	}
+	stdin, err := item.OpenStdin()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if stdin != nil {
+		defer stdin.Close()
+	}
+
+	logging.Info("remote run", "item", req.Item, "remote", r.RemoteAddr)
+	start := time.Now()
+	ctx, cancel := runContext(r, item)
+	defer cancel()
+	output, exitCode := exec.ExecuteAndCaptureContext(ctx, command, item.ArgvForOS(exec.GetOS()), workDir, stdin)
+	writeJSON(w, http.StatusOK, RunResult{
+		Item:       req.Item,
+		ExitCode:   exitCode,
+		Output:     output,
+		DurationMS: time.Since(start).Milliseconds(),
+	})
+}
+
+// runParallel runs a parallel item's commands at the same time and returns
+// their combined output, each line prefixed with its command's label. The
+// exit code is the first failure's, or 0 when every command succeeded.
+func (s *Server) runParallel(w http.ResponseWriter, r *http.Request, name string, item config.MenuItem) {
+	commands := item.CommandsForOS(exec.GetOS())
+	if len(commands) == 0 {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("'%s' has no command for this platform", name))
+		return
+	}
+	jobs, err := exec.ParallelJobs(commands, exec.GetOS())
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	defer exec.CloseJobs(jobs)
+
+	logging.Info("remote run", "item", name, "remote", r.RemoteAddr, "commands", len(jobs))
+	start := time.Now()
+	ctx, cancel := runContext(r, item)
+	defer cancel()
+	results := exec.RunParallel(ctx, jobs)
+	_, exitCode := exec.CountSucceeded(results)
+	writeJSON(w, http.StatusOK, RunResult{
+		Item:       name,
+		ExitCode:   exitCode,
+		Output:     exec.CombineOutput(results),
+		DurationMS: time.Since(start).Milliseconds(),
+	})
+}
+
+// runContext returns the context a run request's commands run under: the
+// request's, so a client disconnect interrupts them, bounded by the item's timeout
+func runContext(r *http.Request, item config.MenuItem) (context.Context, context.CancelFunc) {
+	if d := item.TimeoutDuration(); d > 0 {
+		return context.WithTimeout(r.Context(), d)
+	}
+	return context.WithCancel(r.Context())
+}
+
+// authorized reports whether r carries the bearer token
+func (s *Server) authorized(r *http.Request) bool {
+	got, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	return ok && subtle.ConstantTimeCompare([]byte(got), []byte(s.token)) == 1
+}
+
+// writeJSON writes v as an indented JSON response
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.Encode(v)
+}
+
+// writeError writes {"error": message}
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]string{"error": message})
+}
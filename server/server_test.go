@@ -0,0 +1,133 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/benworks/menuworks/config"
+	"github.com/benworks/menuworks/menu"
+)
+
+func testServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	cfg := &config.Config{
+		Title: "Root",
+		Items: []config.MenuItem{
+			{Type: "command", Label: "Hello", Exec: config.ExecConfig{Windows: "echo hello", Linux: "echo hello", Mac: "echo hello"}},
+			{Type: "command", Label: "Fail", Exec: config.ExecConfig{Windows: "exit 3", Linux: "exit 3", Mac: "exit 3"}},
+			{Type: "submenu", Label: "Tools", Target: "tools"},
+			{Type: "command", Label: "Query", Exec: config.ExecConfig{Windows: "more", Linux: "cat", Mac: "cat"}, Stdin: config.StdinPrompt},
+			{Type: "parallel", Label: "Stack", Commands: []config.MenuItem{
+				{Type: "command", Label: "api", Exec: config.ExecConfig{Windows: "echo up", Linux: "echo up", Mac: "echo up"}},
+				{Type: "command", Label: "db", Exec: config.ExecConfig{Windows: "exit 2", Linux: "exit 2", Mac: "exit 2"}},
+			}},
+		},
+		Menus: map[string]config.Menu{"tools": {Title: "Tools", Lock: &config.MenuLock{Auth: "os"}, Items: []config.MenuItem{
+			{Type: "command", Label: "Reset", Exec: config.ExecConfig{Windows: "echo reset", Linux: "echo reset", Mac: "echo reset"}},
+		}}},
+	}
+	s, err := New(cfg, "secret")
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	srv := httptest.NewServer(s.Handler())
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func postRun(t *testing.T, srv *httptest.Server, token, body string) *http.Response {
+	t.Helper()
+	req, _ := http.NewRequest(http.MethodPost, srv.URL+"/api/run", strings.NewReader(body))
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("POST: %v", err)
+	}
+	t.Cleanup(func() { resp.Body.Close() })
+	return resp
+}
+
+func TestNewRequiresToken(t *testing.T) {
+	if _, err := New(&config.Config{}, ""); err == nil {
+		t.Errorf("expected an error without a token")
+	}
+}
+
+func TestMenuEndpoint(t *testing.T) {
+	srv := testServer(t)
+	resp, err := http.Get(srv.URL + "/api/menu")
+	if err != nil {
+		t.Fatalf("GET: %v", err)
+	}
+	defer resp.Body.Close()
+	var tree []menu.Node
+	if err := json.NewDecoder(resp.Body).Decode(&tree); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(tree) != 5 || tree[0].Path != "Hello" {
+		t.Errorf("unexpected tree: %+v", tree)
+	}
+}
+
+func TestRunEndpoint(t *testing.T) {
+	srv := testServer(t)
+
+	if resp := postRun(t, srv, "", `{"item":"Hello"}`); resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("expected 401 without a token, got %d", resp.StatusCode)
+	}
+	if resp := postRun(t, srv, "wrong", `{"item":"Hello"}`); resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("expected 401 with a wrong token, got %d", resp.StatusCode)
+	}
+	if resp := postRun(t, srv, "secret", `{"item":"Nope"}`); resp.StatusCode != http.StatusNotFound {
+		t.Errorf("expected 404 for an unknown item, got %d", resp.StatusCode)
+	}
+	if resp := postRun(t, srv, "secret", `{"item":"Tools"}`); resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("expected 400 for a submenu, got %d", resp.StatusCode)
+	}
+	if resp := postRun(t, srv, "secret", `{"item":"Tools/Reset"}`); resp.StatusCode != http.StatusForbidden {
+		t.Errorf("expected 403 for an item in a locked menu, got %d", resp.StatusCode)
+	}
+	if resp := postRun(t, srv, "secret", `{"item":"Query"}`); resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("expected 400 for an item that prompts for input, got %d", resp.StatusCode)
+	}
+
+	resp := postRun(t, srv, "secret", `{"item":"hello"}`)
+	var result RunResult
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK || result.ExitCode != 0 || result.Output != "hello" {
+		t.Errorf("unexpected result %d %+v", resp.StatusCode, result)
+	}
+
+	resp = postRun(t, srv, "secret", `{"item":"Fail"}`)
+	result = RunResult{}
+	json.NewDecoder(resp.Body).Decode(&result)
+	if result.ExitCode != 3 {
+		t.Errorf("expected exit code 3, got %d", result.ExitCode)
+	}
+}
+
+func TestRunEndpointParallel(t *testing.T) {
+	srv := testServer(t)
+
+	resp := postRun(t, srv, "secret", `{"item":"Stack"}`)
+	var result RunResult
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200 for a parallel item, got %d", resp.StatusCode)
+	}
+	if result.ExitCode != 2 {
+		t.Errorf("expected the failing command's exit code 2, got %d", result.ExitCode)
+	}
+	if want := "[api] up\n[db]  Exit code 2"; result.Output != want {
+		t.Errorf("expected output %q, got %q", want, result.Output)
+	}
+}
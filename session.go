@@ -0,0 +1,65 @@
+package menuworks
+
+import (
+	"time"
+
+	"github.com/benworks/menuworks/app"
+	"github.com/benworks/menuworks/logging"
+)
+
+// sessionPollInterval is how often a config without session_timeout is
+// checked again, so a reload that adds one takes effect
+const sessionPollInterval = time.Minute
+
+// sessionIdle returns how long the current session has gone without input
+func (a *App) sessionIdle() time.Duration {
+	return min(a.d.Idle(), time.Since(a.sessionStart))
+}
+
+// startSessionTimer schedules the next check for session_timeout: when the
+// session would time out if no input arrives before then
+func (a *App) startSessionTimer() {
+	wait := sessionPollInterval
+	if timeout := a.cfg.SessionTimeoutDuration(); timeout > 0 {
+		wait = max(timeout-a.sessionIdle(), 0)
+	}
+	a.sessionTimer = a.d.AfterFunc(wait, func() {
+		if a.d.Stopped() {
+			return
+		}
+		if timeout := a.cfg.SessionTimeoutDuration(); timeout > 0 && a.sessionIdle() >= timeout {
+			a.endSession()
+		}
+		a.startSessionTimer()
+	})
+}
+
+// stopSessionTimer cancels the session timeout check
+func (a *App) stopSessionTimer() {
+	if a.sessionTimer != nil {
+		a.sessionTimer.Stop()
+	}
+}
+
+// endSession leaves the menu as the next person should find it: whatever
+// is open over the menu is closed, locked menus are locked again, and the
+// session menu (default: the root) is shown with fresh selections
+func (a *App) endSession() {
+	if !a.d.PopUntil(func(v app.View) bool { _, ok := v.(*menuView); return ok }) {
+		return
+	}
+	logging.Info("session timed out", "idle", a.sessionIdle().Round(time.Second), "menu", a.cfg.SessionMenu)
+	a.resetSession()
+}
+
+// resetSession starts a new session in the session menu, with locked menus
+// locked again and fresh selections
+func (a *App) resetSession() {
+	a.sessionStart = time.Now()
+	a.unlocked = nil
+	a.role = a.startRole()
+	a.screen.HideCursor()
+	a.navigator = a.newNavigator(a.cfg)
+	a.navigateTo(a.cfg.SessionMenu)
+	a.navigator.BackOutOf(a.menuBarred)
+}
@@ -0,0 +1,49 @@
+package menuworks
+
+import (
+	"testing"
+
+	"github.com/benworks/menuworks/config"
+	"github.com/benworks/menuworks/ui"
+)
+
+func TestEndSessionReturnsToSessionMenu(t *testing.T) {
+	cfg := lockedConfig()
+	cfg.Items = append(cfg.Items, config.MenuItem{Type: "submenu", Label: "Public", Target: "public"})
+	cfg.Menus["public"] = config.Menu{Title: "Public", Items: []config.MenuItem{{Type: "back", Label: "Back"}}}
+	cfg.SessionMenu = "public"
+	a, _, _ := newTestApp(t, cfg)
+
+	// Someone signed in to the admin menu and left a dialog open
+	a.unlocked = map[string]bool{"admin": true}
+	a.navigator.NavigateToMenu("admin")
+	a.d.Push(&menuView{a: a})
+	a.d.Push(ui.NewMessageView(a.screen, "Done", "Rebooted", nil))
+
+	a.endSession()
+	if _, ok := a.d.Focused().(*menuView); !ok {
+		t.Errorf("expected the dialog to be closed, got %T", a.d.Focused())
+	}
+	if !a.menuLocked("admin") {
+		t.Error("expected the admin menu to be locked again")
+	}
+	if got := a.navigator.GetCurrentMenuName(); got != "public" {
+		t.Errorf("expected the session menu, got %q", got)
+	}
+	a.navigator.Back()
+	if !a.navigator.IsAtRoot() {
+		t.Errorf("expected Back to reach the root, got %q", a.navigator.GetCurrentMenuName())
+	}
+}
+
+func TestEndSessionWithoutMenuView(t *testing.T) {
+	a, _, _ := newTestApp(t, lockedConfig())
+	a.unlocked = map[string]bool{"admin": true}
+	a.d.Push(ui.NewMessageView(a.screen, "Done", "Rebooted", nil))
+
+	// Nothing to return to yet (the splash or a startup dialog): left alone
+	a.endSession()
+	if a.menuLocked("admin") {
+		t.Error("expected the session to carry on")
+	}
+}
@@ -0,0 +1,193 @@
+// Package shell quotes argument lists as command lines and splits command
+// lines back into arguments, following the rules of the shell that runs
+// commands on each OS: sh on Linux and macOS, cmd.exe and the C runtime's
+// argument parsing on Windows. It lets a program and its arguments be shown,
+// copied, or handed to a terminal as one line without breaking on spaces and
+// quotes (Program Files paths).
+package shell
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Quote joins args into a command line for goos ("windows", "linux",
+// "darwin", or "mac") that the platform shell splits back into the same args
+func Quote(goos string, args []string) string {
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		if goos == "windows" {
+			quoted[i] = quoteWindows(arg)
+		} else {
+			quoted[i] = quotePOSIX(arg)
+		}
+	}
+	return strings.Join(quoted, " ")
+}
+
+// Split breaks a command line for goos into its arguments. Quotes are
+// removed and escapes resolved as the platform would; shell operators such as
+// | or && are not interpreted and end up as ordinary arguments. An unterminated
+// quote is an error.
+func Split(goos, line string) ([]string, error) {
+	if goos == "windows" {
+		return splitWindows(line)
+	}
+	return splitPOSIX(line)
+}
+
+// posixSafe reports whether r needs no quoting in sh
+func posixSafe(r rune) bool {
+	return r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || strings.ContainsRune("_@%+=:,./-", r)
+}
+
+// quotePOSIX single-quotes arg unless it is made only of safe characters
+func quotePOSIX(arg string) string {
+	if arg != "" && strings.IndexFunc(arg, func(r rune) bool { return !posixSafe(r) }) < 0 {
+		return arg
+	}
+	return "'" + strings.ReplaceAll(arg, "'", `'\''`) + "'"
+}
+
+// quoteWindows double-quotes arg when it contains spaces, quotes, or
+// characters cmd.exe treats specially, escaping embedded quotes and the
+// backslashes before them the way CommandLineToArgvW expects. Inside the
+// quotes cmd.exe leaves & | < > ^ ( ) alone; % is still expanded.
+func quoteWindows(arg string) string {
+	if arg != "" && !strings.ContainsAny(arg, " \t\"&|<>^()") {
+		return arg
+	}
+	var b strings.Builder
+	b.WriteByte('"')
+	backslashes := 0
+	for _, r := range arg {
+		switch r {
+		case '\\':
+			backslashes++
+			continue
+		case '"':
+			// Double the backslashes before a quote, then escape the quote
+			b.WriteString(strings.Repeat(`\`, backslashes*2+1))
+		default:
+			b.WriteString(strings.Repeat(`\`, backslashes))
+		}
+		backslashes = 0
+		b.WriteRune(r)
+	}
+	// Backslashes before the closing quote are doubled so they don't escape it
+	b.WriteString(strings.Repeat(`\`, backslashes*2))
+	b.WriteByte('"')
+	return b.String()
+}
+
+// splitPOSIX splits line like sh: single quotes are literal, double quotes
+// allow \ before $ ` " \ and newline, and an unquoted \ escapes any character
+func splitPOSIX(line string) ([]string, error) {
+	var args []string
+	var cur strings.Builder
+	inArg := false
+	runes := []rune(line)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case r == ' ' || r == '\t' || r == '\n':
+			if inArg {
+				args = append(args, cur.String())
+				cur.Reset()
+				inArg = false
+			}
+		case r == '\'':
+			inArg = true
+			end := indexRune(runes, i+1, '\'')
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated single quote")
+			}
+			cur.WriteString(string(runes[i+1 : end]))
+			i = end
+		case r == '"':
+			inArg = true
+			i++
+			for ; i < len(runes) && runes[i] != '"'; i++ {
+				if runes[i] == '\\' && i+1 < len(runes) && strings.ContainsRune("$`\"\\\n", runes[i+1]) {
+					i++
+				}
+				cur.WriteRune(runes[i])
+			}
+			if i >= len(runes) {
+				return nil, fmt.Errorf("unterminated double quote")
+			}
+		case r == '\\':
+			inArg = true
+			if i+1 < len(runes) {
+				i++
+				cur.WriteRune(runes[i])
+			}
+		default:
+			inArg = true
+			cur.WriteRune(r)
+		}
+	}
+	if inArg {
+		args = append(args, cur.String())
+	}
+	return args, nil
+}
+
+// splitWindows splits line the way CommandLineToArgvW does: double quotes
+// group, and backslashes are literal except in front of a quote, where 2n
+// backslashes become n and the quote toggles quoting, and 2n+1 become n
+// followed by a literal quote
+func splitWindows(line string) ([]string, error) {
+	var args []string
+	var cur strings.Builder
+	inArg, quoted := false, false
+	backslashes := 0
+	for _, r := range line {
+		if r == '\\' {
+			backslashes++
+			inArg = true
+			continue
+		}
+		if r == '"' {
+			cur.WriteString(strings.Repeat(`\`, backslashes/2))
+			if backslashes%2 == 1 {
+				cur.WriteRune('"')
+			} else {
+				quoted = !quoted
+			}
+			backslashes = 0
+			inArg = true
+			continue
+		}
+		cur.WriteString(strings.Repeat(`\`, backslashes))
+		backslashes = 0
+		if (r == ' ' || r == '\t') && !quoted {
+			if inArg {
+				args = append(args, cur.String())
+				cur.Reset()
+				inArg = false
+			}
+			continue
+		}
+		inArg = true
+		cur.WriteRune(r)
+	}
+	cur.WriteString(strings.Repeat(`\`, backslashes))
+	if quoted {
+		return nil, fmt.Errorf("unterminated double quote")
+	}
+	if inArg {
+		args = append(args, cur.String())
+	}
+	return args, nil
+}
+
+// indexRune returns the index of the first r in runes at or after from, or -1
+func indexRune(runes []rune, from int, r rune) int {
+	for i := from; i < len(runes); i++ {
+		if runes[i] == r {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,62 @@
+package shell
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestQuote(t *testing.T) {
+	tests := []struct {
+		goos string
+		args []string
+		want string
+	}{
+		{"linux", []string{"ls", "-la", "/tmp"}, "ls -la /tmp"},
+		{"linux", []string{"/opt/My App/run", "it's", ""}, `'/opt/My App/run' 'it'\''s' ''`},
+		{"darwin", []string{"echo", "$HOME"}, `echo '$HOME'`},
+		{"windows", []string{`C:\Program Files\App\app.exe`, "--flag"}, `"C:\Program Files\App\app.exe" --flag`},
+		{"windows", []string{`say "hi"`, `C:\dir\`}, `"say \"hi\"" C:\dir\`},
+		{"windows", []string{`C:\dir with space\`, "a&b", ""}, `"C:\dir with space\\" "a&b" ""`},
+	}
+	for _, tt := range tests {
+		if got := Quote(tt.goos, tt.args); got != tt.want {
+			t.Errorf("Quote(%s, %q) = %s, want %s", tt.goos, tt.args, got, tt.want)
+		}
+	}
+}
+
+func TestSplit(t *testing.T) {
+	tests := []struct {
+		goos string
+		line string
+		want []string
+	}{
+		{"linux", `ls  -la "/tmp/a b"`, []string{"ls", "-la", "/tmp/a b"}},
+		{"linux", `echo 'a "b"' "c \"d\" \$e" f\ g`, []string{"echo", `a "b"`, `c "d" $e`, "f g"}},
+		{"linux", `x '' ""`, []string{"x", "", ""}},
+		{"windows", `"C:\Program Files\App\app.exe" --open C:\x`, []string{`C:\Program Files\App\app.exe`, "--open", `C:\x`}},
+		{"windows", `a\\\"b "c\\" d`, []string{`a\"b`, `c\`, "d"}},
+	}
+	for _, tt := range tests {
+		got, err := Split(tt.goos, tt.line)
+		if err != nil || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Split(%s, %s) = %q, %v; want %q", tt.goos, tt.line, got, err, tt.want)
+		}
+	}
+
+	for _, goos := range []string{"linux", "windows"} {
+		if _, err := Split(goos, `run "unfinished`); err == nil {
+			t.Errorf("%s: expected an error for an unterminated quote", goos)
+		}
+	}
+}
+
+func TestQuoteSplitRoundTrip(t *testing.T) {
+	args := []string{`C:\Program Files (x86)\Tool\tool.exe`, `--name=it's "quoted"`, `trailing\`, "", "a|b"}
+	for _, goos := range []string{"linux", "windows"} {
+		got, err := Split(goos, Quote(goos, args))
+		if err != nil || !reflect.DeepEqual(got, args) {
+			t.Errorf("%s: round trip gave %q, %v", goos, got, err)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package ui
+
+import (
+	_ "embed"
+	"strings"
+	"unicode"
+)
+
+// BigTextHeight is the number of rows BigText draws
+const BigTextHeight = 5
+
+//go:embed fonts/block.txt
+var blockFontData string
+
+// blockFont maps each character of the block font to its rows, '#' for
+// filled cells and '.' for empty ones
+var blockFont = parseFont(blockFontData)
+
+// parseFont reads font data in the format described at the top of
+// fonts/block.txt
+func parseFont(data string) map[rune][]string {
+	lines := strings.Split(strings.ReplaceAll(data, "\r\n", "\n"), "\n")
+	for len(lines) > 0 && strings.HasPrefix(lines[0], "#") {
+		lines = lines[1:] // header comment
+	}
+
+	font := make(map[rune][]string)
+	for len(lines) > BigTextHeight {
+		ch := []rune(lines[0])
+		if len(ch) > 1 {
+			break // not a glyph
+		}
+		r := ' '
+		if len(ch) == 1 {
+			r = ch[0]
+		}
+		font[r] = lines[1 : 1+BigTextHeight]
+		lines = lines[1+BigTextHeight:]
+	}
+	return font
+}
+
+// BigText returns text in the embedded block font as BigTextHeight lines,
+// drawing filled cells with fill. It returns nil if a character of text has
+// no glyph, so the caller can fall back to plain text.
+func BigText(text string, fill rune) []string {
+	rows := make([]strings.Builder, BigTextHeight)
+	for i, ch := range []rune(text) {
+		glyph, ok := blockFont[unicode.ToUpper(ch)]
+		if !ok {
+			return nil
+		}
+		for y, row := range glyph {
+			if i > 0 {
+				rows[y].WriteByte(' ')
+			}
+			for _, cell := range row {
+				if cell == '#' {
+					rows[y].WriteRune(fill)
+				} else {
+					rows[y].WriteByte(' ')
+				}
+			}
+		}
+	}
+	lines := make([]string, BigTextHeight)
+	for y := range rows {
+		lines[y] = rows[y].String()
+	}
+	return lines
+}
@@ -0,0 +1,53 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/benworks/menuworks/config"
+	"github.com/benworks/menuworks/menu"
+)
+
+func TestBigText(t *testing.T) {
+	lines := BigText("Hi 1", '#')
+	want := []string{
+		"#   # ###      # ",
+		"#   #  #      ## ",
+		"#####  #       # ",
+		"#   #  #       # ",
+		"#   # ###     ###",
+	}
+	if strings.Join(lines, "\n") != strings.Join(want, "\n") {
+		t.Errorf("unexpected big text:\n%s\nwant:\n%s", strings.Join(lines, "\n"), strings.Join(want, "\n"))
+	}
+	if BigText("Café", '#') != nil {
+		t.Error("expected nil for a character the font lacks")
+	}
+}
+
+func TestBigTitleAboveMenu(t *testing.T) {
+	cfg := &config.Config{Title: "Arcade", Items: []config.MenuItem{{Type: "back", Label: "Exit"}}}
+	banner := BigText("Arcade", '█')[0]
+
+	for _, tc := range []struct {
+		w, h  int
+		shown bool
+	}{
+		{100, 40, true},
+		{80, 25, false}, // no room above the default 18-row menu
+	} {
+		s, sim := newTestScreen(t, tc.w, tc.h)
+		s.SetBigTitle(cfg.Title, true, false)
+		s.DrawMenu(menu.NewNavigator(cfg))
+
+		shown := false
+		for y := 0; y < tc.h; y++ {
+			if strings.Contains(rowText(sim, y), banner) {
+				shown = true
+			}
+		}
+		if shown != tc.shown {
+			t.Errorf("%dx%d: expected big title shown=%v, got %v", tc.w, tc.h, tc.shown, shown)
+		}
+	}
+}
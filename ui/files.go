@@ -0,0 +1,233 @@
+package ui
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/mattn/go-runewidth"
+
+	"github.com/benworks/menuworks/i18n"
+)
+
+// FileView is a file picker: a bordered list of the folders and files in a
+// directory. Enter opens a folder or picks a file, Left and Backspace go up
+// a folder, a letter jumps to the next entry starting with it, and Escape
+// cancels. Going up stops at the folder the picker was opened in.
+type FileView struct {
+	screen   *Screen
+	title    string
+	root     string
+	dir      string // folder being listed
+	entries  []fileEntry
+	err      error // why dir could not be listed in full
+	selected int
+	offset   int
+	show     func(name string) bool
+	onSelect func(path string)
+	onCancel func()
+}
+
+// fileEntry is one row of a FileView
+type fileEntry struct {
+	name string
+	dir  bool
+}
+
+// parentEntry is the row that goes up a folder
+const parentEntry = ".."
+
+// NewFileView creates a file picker opened at root. show decides which files
+// are listed (nil lists them all); folders always are. onSelect gets the
+// picked file's path.
+func NewFileView(s *Screen, title, root string, show func(name string) bool, onSelect func(path string), onCancel func()) *FileView {
+	v := &FileView{screen: s, title: title, root: filepath.Clean(root), show: show, onSelect: onSelect, onCancel: onCancel}
+	v.open(v.root, "")
+	return v
+}
+
+// open lists dir, selecting the entry called name if there is one
+func (v *FileView) open(dir, name string) {
+	v.dir, v.selected, v.offset = dir, 0, 0
+	v.entries, v.err = listDir(dir, v.show)
+	if dir != v.root {
+		v.entries = append([]fileEntry{{name: parentEntry, dir: true}}, v.entries...)
+	}
+	for i, e := range v.entries {
+		if e.name == name {
+			v.selected = i
+		}
+	}
+}
+
+// up lists the folder above the current one, with the current one selected
+func (v *FileView) up() {
+	if v.dir != v.root {
+		v.open(filepath.Dir(v.dir), filepath.Base(v.dir))
+	}
+}
+
+// listDir returns the folders in dir and the files show accepts, folders
+// first and each sorted by name regardless of case. Hidden entries, whose
+// names start with a dot, are left out. Entries read before an error are
+// returned along with it.
+func listDir(dir string, show func(name string) bool) ([]fileEntry, error) {
+	items, err := os.ReadDir(dir)
+	var dirs, files []fileEntry
+	for _, item := range items {
+		name := item.Name()
+		if strings.HasPrefix(name, ".") {
+			continue
+		}
+		isDir := item.IsDir()
+		if item.Type()&fs.ModeSymlink != 0 {
+			if info, err := os.Stat(filepath.Join(dir, name)); err == nil {
+				isDir = info.IsDir()
+			}
+		}
+		if isDir {
+			dirs = append(dirs, fileEntry{name: name, dir: true})
+		} else if show == nil || show(name) {
+			files = append(files, fileEntry{name: name})
+		}
+	}
+	for _, list := range [][]fileEntry{dirs, files} {
+		sort.SliceStable(list, func(i, j int) bool {
+			return strings.ToLower(list[i].name) < strings.ToLower(list[j].name)
+		})
+	}
+	return append(dirs, files...), err
+}
+
+// box returns the picker's position and size, and how many entries fit
+func (v *FileView) box() (x, y, width, height, rows int) {
+	w, h := v.screen.Size()
+	width, height = min(64, w), min(20, h)
+	return (w - width) / 2, (h - height) / 2, width, height, max(height-4, 1)
+}
+
+// Draw renders the current folder's path and the visible entries
+func (v *FileView) Draw() {
+	s := v.screen
+	w, h := s.Size()
+	startX, startY, width, height, rows := v.box()
+
+	if v.selected < v.offset {
+		v.offset = v.selected
+	}
+	if v.selected >= v.offset+rows {
+		v.offset = v.selected - rows + 1
+	}
+
+	s.ClearRect(0, 0, w, h)
+	s.ClearRectWithStyle(startX, startY, width, height, s.theme.StyleMenuBg())
+	s.DrawBorderWithStyle(startX, startY, width, height, " "+v.title+" ", s.theme.StyleBorderMenuBg())
+	s.DrawShadow(startX, startY, width, height)
+
+	// The end of a long path says most about where the picker is
+	location := v.dir
+	if StringWidth(location) > width-4 {
+		location = "…" + runewidth.TruncateLeft(location, StringWidth(location)-(width-5), "")
+	}
+	s.DrawString(startX+2, startY+1, location, s.theme.StyleBorderMenuBg())
+
+	// Below the ".." row, if any, say why there is nothing else to pick
+	listed := len(v.entries)
+	if v.dir != v.root {
+		listed--
+	}
+	switch {
+	case v.err != nil && listed == 0:
+		s.DrawString(startX+3, startY+2+len(v.entries), TruncateString(i18n.Tf("Can't read this folder: %v", v.err), width-6), s.theme.StyleStatus(false))
+	case listed == 0:
+		s.DrawString(startX+3, startY+2+len(v.entries), i18n.T("(No files)"), s.theme.StyleTextMenuBg())
+	}
+	for i := 0; i < rows && v.offset+i < len(v.entries); i++ {
+		idx := v.offset + i
+		name := v.entries[idx].name
+		if v.entries[idx].dir {
+			name += "/"
+		}
+		style := s.theme.StyleTextMenuBg()
+		if idx == v.selected {
+			style = s.theme.StyleHighlight()
+			s.ClearRectWithStyle(startX+2, startY+2+i, width-4, 1, style)
+		}
+		s.DrawString(startX+3, startY+2+i, TruncateString(name, width-6), style)
+	}
+
+	footer := i18n.T("ENTER: Open | ←: Up | ESC: Cancel")
+	s.DrawString(startX+(width-StringWidth(footer))/2, startY+height-1, footer, s.theme.StyleBorderMenuBg())
+	s.HideCursor()
+	s.Show()
+}
+
+// HandleEvent moves the selection, opens a folder, picks a file, or cancels
+func (v *FileView) HandleEvent(ev tcell.Event) {
+	keyEv, ok := ev.(*tcell.EventKey)
+	if !ok {
+		return
+	}
+
+	_, _, _, _, rows := v.box()
+	last := len(v.entries) - 1
+	switch keyEv.Key() {
+	case tcell.KeyUp:
+		v.selected = max(v.selected-1, 0)
+	case tcell.KeyDown:
+		v.selected = max(min(v.selected+1, last), 0)
+	case tcell.KeyPgUp:
+		v.selected = max(v.selected-rows, 0)
+	case tcell.KeyPgDn:
+		v.selected = max(min(v.selected+rows, last), 0)
+	case tcell.KeyHome:
+		v.selected = 0
+	case tcell.KeyEnd:
+		v.selected = max(last, 0)
+	case tcell.KeyLeft, tcell.KeyBackspace, tcell.KeyBackspace2:
+		v.up()
+	case tcell.KeyRight:
+		if len(v.entries) > 0 && v.entries[v.selected].dir {
+			v.choose()
+		}
+	case tcell.KeyEnter:
+		if len(v.entries) > 0 {
+			v.choose()
+		}
+	case tcell.KeyEscape:
+		v.onCancel()
+	case tcell.KeyRune:
+		v.jumpTo(keyEv.Rune())
+	}
+}
+
+// choose opens the selected folder or picks the selected file
+func (v *FileView) choose() {
+	e := v.entries[v.selected]
+	switch {
+	case e.name == parentEntry:
+		v.up()
+	case e.dir:
+		v.open(filepath.Join(v.dir, e.name), "")
+	default:
+		v.onSelect(filepath.Join(v.dir, e.name))
+	}
+}
+
+// jumpTo selects the next entry after the selected one whose name starts
+// with r, regardless of case, going round to the top
+func (v *FileView) jumpTo(r rune) {
+	r = unicode.ToLower(r)
+	for i := 1; i <= len(v.entries); i++ {
+		idx := (v.selected + i) % len(v.entries)
+		if first, _ := utf8.DecodeRuneInString(v.entries[idx].name); unicode.ToLower(first) == r {
+			v.selected = idx
+			return
+		}
+	}
+}
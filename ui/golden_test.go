@@ -0,0 +1,199 @@
+package ui
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+
+	"github.com/benworks/menuworks/config"
+	"github.com/benworks/menuworks/menu"
+)
+
+// Run `go test ./ui -update` to rewrite the golden files after an intended change
+var updateGolden = flag.Bool("update", false, "rewrite golden frame files in testdata")
+
+// styleClasses maps each theme style to the letter used for it in a snapshot's
+// style grid. Earlier entries win when two styles are identical.
+func styleClasses(t *Theme) []struct {
+	letter byte
+	style  tcell.Style
+} {
+	return []struct {
+		letter byte
+		style  tcell.Style
+	}{
+		{'h', t.StyleHotkeyHighlight()},
+		{'H', t.StyleHighlight()},
+		{'k', t.StyleHotkeyMenuBg()},
+		{'K', t.StyleHotkey()},
+		{'b', t.StyleBorderMenuBg()},
+		{'t', t.StyleTitle(t.StyleBorderMenuBg())},
+		{'B', t.StyleBorder()},
+		{'-', t.StyleSeparator().Background(t.menuBg)},
+		{'d', t.StyleDisabledMenuBg()},
+		{'D', t.StyleDisabled()},
+		{'m', t.StyleTextMenuBg()},
+		{'M', t.StyleMenuBg()},
+		{'s', t.StyleShadow()},
+		{'.', t.StyleNormal()},
+	}
+}
+
+// snapshot renders the simulation screen's cells as a text grid followed by a
+// style grid with one letter per cell (see styleClasses; '?' is unrecognised)
+func snapshot(s *Screen, sim tcell.SimulationScreen) string {
+	cells, w, h := sim.GetContents()
+	classes := styleClasses(s.Theme())
+
+	var text, styles strings.Builder
+	for y := 0; y < h; y++ {
+		var row strings.Builder
+		for x := 0; x < w; x++ {
+			cell := cells[y*w+x]
+			if len(cell.Runes) == 0 {
+				row.WriteByte(' ')
+			} else {
+				row.WriteString(string(cell.Runes))
+			}
+
+			letter := byte('?')
+			for _, c := range classes {
+				if cell.Style == c.style {
+					letter = c.letter
+					break
+				}
+			}
+			styles.WriteByte(letter)
+		}
+		text.WriteString(strings.TrimRight(row.String(), " "))
+		text.WriteByte('\n')
+		styles.WriteByte('\n')
+	}
+	return "-- text --\n" + text.String() + "-- style --\n" + styles.String()
+}
+
+// assertGolden compares got with testdata/<name>.golden, rewriting it under -update
+func assertGolden(t *testing.T, name, got string) {
+	t.Helper()
+	path := filepath.Join("testdata", name+".golden")
+	if *updateGolden {
+		if err := os.MkdirAll("testdata", 0755); err != nil {
+			t.Fatalf("create testdata: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(got), 0644); err != nil {
+			t.Fatalf("write golden file: %v", err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read golden file (run with -update to create it): %v", err)
+	}
+	if got != string(want) {
+		t.Errorf("frame differs from %s (run with -update if the change is intended)\n--- got ---\n%s--- want ---\n%s", path, got, want)
+	}
+}
+
+// renderMenu draws navigator's current menu on a fixed-size headless screen.
+// The title bar shows no date or clock so frames are stable.
+func renderMenu(t *testing.T, navigator *menu.Navigator, setup func(s *Screen)) string {
+	t.Helper()
+	s, sim := newTestScreen(t, 80, 25)
+	s.SetTitleBar(TitleBar{Text: "Golden", HideDate: true, HideClock: true})
+	if setup != nil {
+		setup(s)
+	}
+	s.DrawMenu(navigator)
+	s.Show()
+	return snapshot(s, sim)
+}
+
+func goldenConfig() *config.Config {
+	echo := config.ExecConfig{Windows: "echo", Linux: "echo", Mac: "echo"}
+	return &config.Config{
+		Title: "Main Menu",
+		Items: []config.MenuItem{
+			{Type: "command", Label: "Status", Exec: echo},
+			{Type: "command", Label: "Logs", Hotkey: "L", Exec: echo},
+			{Type: "submenu", Label: "Tools", Target: "tools"},
+			{Type: "submenu", Label: "Missing", Target: "nowhere"},
+			{Type: "separator"},
+			{Type: "back", Label: "Exit"},
+		},
+		Menus: map[string]config.Menu{
+			"tools": {
+				Title: "Tools",
+				Items: []config.MenuItem{
+					{Type: "command", Label: "Disk Usage", Exec: echo},
+					{Type: "back", Label: "Back"},
+				},
+			},
+			"grouped": {
+				Title: "Grouped",
+				Items: []config.MenuItem{
+					{Type: "group", Label: "Network"},
+					{Type: "command", Label: "Ping", Exec: echo},
+					{Type: "command", Label: "Trace Route", Exec: echo},
+					{Type: "group", Label: "Storage", Collapsed: true},
+					{Type: "command", Label: "Disk Usage", Exec: echo},
+					{Type: "separator"},
+					{Type: "back", Label: "Back"},
+				},
+			},
+		},
+	}
+}
+
+func TestGoldenFrames(t *testing.T) {
+	tests := []struct {
+		name  string
+		move  func(n *menu.Navigator)
+		setup func(s *Screen)
+	}{
+		{name: "root", move: func(n *menu.Navigator) {}},
+		{name: "root_second_selected", move: func(n *menu.Navigator) { n.NextSelectable() }},
+		{name: "submenu", move: func(n *menu.Navigator) {
+			n.NavigateToMenu("tools")
+		}},
+		{name: "groups", move: func(n *menu.Navigator) {
+			n.NavigateToMenu("grouped")
+		}},
+		{name: "light_theme", move: func(n *menu.Navigator) {}, setup: func(s *Screen) {
+			s.ApplyTheme(ThemeColors{Background: "white", Text: "black", Border: "blue", HighlightBg: "cyan", HighlightFg: "black", Hotkey: "red"}, config.ParseColorName)
+		}},
+		{name: "accessible", move: func(n *menu.Navigator) { n.NextSelectable() }, setup: func(s *Screen) {
+			s.SetAccessibility(Accessibility{Plain: true, ScreenReader: true})
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			navigator := menu.NewNavigator(goldenConfig())
+			tt.move(navigator)
+			assertGolden(t, tt.name, renderMenu(t, navigator, tt.setup))
+		})
+	}
+}
+
+func BenchmarkDrawMenu(b *testing.B) {
+	echo := config.ExecConfig{Windows: "echo", Linux: "echo", Mac: "echo"}
+	cfg := &config.Config{Title: "Library"}
+	for i := 0; i < 5000; i++ {
+		cfg.Items = append(cfg.Items, config.MenuItem{Type: "command", Label: fmt.Sprintf("Game %d", i), Exec: echo})
+	}
+	navigator := menu.NewNavigator(cfg)
+	navigator.SetSelectionIndex(2500)
+	s, _ := newTestScreen(b, 80, 25)
+	s.SetTitleBar(TitleBar{Text: "Bench", HideDate: true, HideClock: true})
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		s.DrawMenu(navigator)
+	}
+}
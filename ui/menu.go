@@ -3,48 +3,120 @@ package ui
 import (
 	"fmt"
 	"strings"
+	"time"
+	"unicode/utf8"
 
 	"github.com/gdamore/tcell/v2"
+	"github.com/mattn/go-runewidth"
+	"github.com/rivo/uniseg"
 
 	"github.com/benworks/menuworks/config"
+	"github.com/benworks/menuworks/i18n"
 	"github.com/benworks/menuworks/menu"
 )
 
-// DrawMenu renders the current menu on screen
-func (s *Screen) DrawMenu(navigator *menu.Navigator, disabledItems map[string]bool) {
-	w, h := s.Size()
+// Menu box limits
+const (
+	minMenuWidth  = 20
+	minMenuHeight = 6
+)
 
-	// Center the menu in an 80x25 layout
-	menuWidth := 60
-	menuHeight := 18
-	startX := (w - menuWidth) / 2
-	startY := (h - menuHeight) / 2
+// Layout controls the size and placement of the main menu box
+type Layout struct {
+	Width      int    // columns; ignored when FullWidth is set
+	Height     int    // rows; ignored when FullHeight is set
+	FullWidth  bool   // use the whole terminal width
+	FullHeight bool   // use the whole terminal height
+	Align      string // "center" (default) or "top-left"
+}
 
-	if startX < 0 {
-		startX = 0
+// DefaultLayout returns the classic 60x18 centered menu
+func DefaultLayout() Layout {
+	return Layout{Width: 60, Height: 18, Align: "center"}
+}
+
+// MenuRect returns the menu box position and size for a w x h terminal.
+// Room is left for the drop shadow and the footer line.
+func (l Layout) MenuRect(w, h int) (x, y, width, height int) {
+	maxWidth := w - 2
+	maxHeight := h - 2
+
+	width = l.Width
+	if l.FullWidth || width > maxWidth {
+		width = maxWidth
 	}
-	if startY < 0 {
-		startY = 0
+	height = l.Height
+	if l.FullHeight || height > maxHeight {
+		height = maxHeight
+	}
+	if width < minMenuWidth {
+		width = minMenuWidth
+	}
+	if height < minMenuHeight {
+		height = minMenuHeight
 	}
 
+	if l.Align != "top-left" {
+		x = (w - width) / 2
+		y = (h - height) / 2
+	}
+	if x < 0 {
+		x = 0
+	}
+	if y < 0 {
+		y = 0
+	}
+	return x, y, width, height
+}
+
+// SetLayout replaces the screen's menu layout
+func (s *Screen) SetLayout(l Layout) {
+	s.layout = l
+}
+
+// MenuPageSize returns how many menu rows fit in the menu box at the current size
+func (s *Screen) MenuPageSize() int {
+	_, _, _, height := s.layout.MenuRect(s.Size())
+	return height - 4
+}
+
+// DrawMenu renders the current menu on screen
+func (s *Screen) DrawMenu(navigator *menu.Navigator) {
+	w, h := s.Size()
+
+	startX, startY, menuWidth, menuHeight := s.layout.MenuRect(w, h)
+
 	// Clear the area
 	s.ClearRect(0, 0, w, h)
 
 	// Fill menu interior with menu background color
 	for dy := 0; dy < menuHeight; dy++ {
 		for dx := 0; dx < menuWidth; dx++ {
-			s.DrawChar(startX+dx, startY+dy, ' ', StyleMenuBg())
+			s.DrawChar(startX+dx, startY+dy, ' ', s.theme.StyleMenuBg())
 		}
 	}
 
+	s.drawBigTitle(startX, startY, menuWidth)
+
 	// Draw menu frame with menu background for borders
-	title := navigator.GetFormattedTitle()
-	s.DrawBorderWithStyle(startX, startY, menuWidth, menuHeight, " "+title+" ", StyleBorderMenuBg())
+	// The frame draws the title with a space on each side
+	// With the mouse on, the title keeps clear of the close button
+	titleWidth := menuWidth - 6
+	if s.mouse {
+		titleWidth -= 2 * (StringWidth(closeButton) + 1)
+	}
+	title := TruncateString(navigator.GetFormattedTitle(titleWidth), titleWidth)
+	s.DrawBorderWithStyle(startX, startY, menuWidth, menuHeight, " "+title+" ", s.theme.StyleBorderMenuBg())
 	s.DrawShadow(startX, startY, menuWidth, menuHeight)
+	s.closeX = -1
+	if s.mouse {
+		s.closeX, s.closeY = startX+menuWidth-2-StringWidth(closeButton), startY
+		s.DrawString(s.closeX, s.closeY, closeButton, s.theme.StyleBorderMenuBg())
+	}
 
 	// Draw header separator line with menu background
 	headerSepY := startY + 2
-	borderStyle := StyleBorderMenuBg()
+	borderStyle := s.theme.StyleBorderMenuBg()
 	s.DrawBoxChar(startX, headerSepY, boxDoubleTLeft, borderStyle)
 	s.DrawBoxChar(startX+menuWidth-1, headerSepY, boxDoubleTRight, borderStyle)
 	for i := 1; i < menuWidth-1; i++ {
@@ -52,12 +124,15 @@ func (s *Screen) DrawMenu(navigator *menu.Navigator, disabledItems map[string]bo
 	}
 
 	// Draw date/time inside title bar with menu background
-	date := FormatDate()
-	time := FormatTime()
-	leftText := date + "     " + "Menu Works" // 5 spaces
-	timeX := startX + menuWidth - 3 - len(time)
-	s.DrawString(startX+2, startY+1, leftText, StyleTextMenuBg())
-	s.DrawString(timeX, startY+1, time, StyleTextMenuBg())
+	titleBar := s.titleBar
+	if s.badge != "" {
+		titleBar.Badge = s.badge
+	}
+	leftText, rightText := titleBar.Format(time.Now())
+	rightX := startX + menuWidth - 3 - StringWidth(rightText)
+	leftText = TruncateString(leftText, rightX-startX-3)
+	s.DrawString(startX+2, startY+1, leftText, s.theme.StyleTextMenuBg())
+	s.DrawString(rightX, startY+1, rightText, s.theme.StyleTextMenuBg())
 
 	// Draw menu items
 	items := navigator.GetCurrentMenu()
@@ -69,156 +144,132 @@ func (s *Screen) DrawMenu(navigator *menu.Navigator, disabledItems map[string]bo
 	navigator.EnsureVisible(maxItems)
 	scrollOffset := navigator.GetScrollOffset()
 
-	// Filter selectable items and draw them
-	selectableCount := 0
-	for _, item := range items {
-		if item.Type == "separator" {
-			continue
-		}
-		selectableCount++
+	// Only the rows in the scroll window are drawn, so cost doesn't grow
+	// with the menu. An empty menu shows a placeholder over its Back item.
+	var selectedY int
+	if navigator.IsEmpty() {
+		selectedY = s.drawEmptyMenuPlaceholder(startX, contentStartY, menuWidth, maxItems, items, navigator)
+	} else {
+		selectedY = s.drawMenuItems(startX, contentStartY, menuWidth, maxItems, items, selectedIdx, navigator, scrollOffset)
 	}
 
-	// If no selectable items, show placeholder
-	if selectableCount == 0 {
-		s.drawEmptyMenuPlaceholder(startX, contentStartY, menuWidth, maxItems)
-	} else {
-		s.drawMenuItems(startX, contentStartY, menuWidth, maxItems, items, selectedIdx, navigator, scrollOffset)
+	if s.transition > 0 && s.transition < 1 && !s.access.ScreenReader {
+		s.drawWipe(startX+1, contentStartY, menuWidth-2, maxItems)
 	}
 
 	// Draw scroll indicators on the right border
 	hasMore := len(items) > maxItems
 	if hasMore {
 		indicatorX := startX + menuWidth - 2
+		up, down := '▲', '▼'
+		if s.access.Plain {
+			up, down = '^', 'v'
+		}
 		if scrollOffset > 0 {
 			// Items above - draw up arrow at top of content area
-			s.DrawChar(indicatorX, contentStartY, '▲', StyleBorderMenuBg())
+			s.DrawChar(indicatorX, contentStartY, up, s.theme.StyleBorderMenuBg())
 		}
 		if scrollOffset+maxItems < len(items) {
 			// Items below - draw down arrow at bottom of content area
-			s.DrawChar(indicatorX, contentStartY+maxItems-1, '▼', StyleBorderMenuBg())
+			s.DrawChar(indicatorX, contentStartY+maxItems-1, down, s.theme.StyleBorderMenuBg())
 		}
 	}
 
-	// Draw footer with helpful text
+	// Draw footer with the active key bindings and menu hint
 	footerY := startY + menuHeight + 1
-	footerText := "↑↓: Navigate | ENTER: Select | ESC: Back | R: Reload | F2: Help"
-	if footerY < h {
-		s.DrawString(startX, footerY, footerText, StyleNormal())
+	s.footerHits = nil
+	if !s.footerOff && footerY < h {
+		hint := navigator.GetFooterHint()
+		footerText := TruncateString(FormatFooter(hint, s.footerKeys), w-startX)
+		s.DrawString(startX, footerY, footerText, s.theme.StyleNormal())
+		s.recordFooterHits(startX, footerY, w, hint)
 	}
 
-	s.HideCursor()
-	s.Sync()
-}
-
-// DrawCommandOutput displays command output in a scrollable full-screen viewer
-// Returns when user presses any key
-func (s *Screen) DrawCommandOutput(output string, eventChan <-chan tcell.Event) {
-	w, h := s.Size()
-
-	// Split output into lines
-	lines := strings.Split(output, "\n")
-
-	// Track scrolling position
-	scrollOffset := 0
-	visibleLines := h - 3 // Space for header and footer
-
-	for {
-		s.ClearRect(0, 0, w, h)
-
-		// Draw header
-		headerText := "─ Command Output ─"
-		headerX := (w - len(headerText)) / 2
-		s.DrawString(headerX, 0, headerText, StyleBorder())
-
-		// Draw visible lines
-		for i := 0; i < visibleLines && scrollOffset+i < len(lines); i++ {
-			line := lines[scrollOffset+i]
-			// Truncate line to fit screen width
-			if len(line) > w {
-				line = line[:w]
-			}
-			s.DrawString(0, 1+i, line, StyleNormal())
-		}
+	// Show the first key of a hotkey sequence at the footer's right end while
+	// the second is awaited
+	if s.pendingKey != "" && footerY < h {
+		pending := " " + i18n.Tf("Hotkey %s…", s.pendingKey) + " "
+		s.DrawString(startX+menuWidth-StringWidth(pending), footerY, pending, s.theme.StyleHighlight())
+	}
 
-		// Draw footer with navigation info
-		footerY := h - 1
-		var footerText string
-		if len(lines) <= visibleLines {
-			footerText = "Press any key to return"
-		} else {
-			totalLines := len(lines)
-			endLine := scrollOffset + visibleLines
-			if endLine > totalLines {
-				endLine = totalLines
-			}
-			footerText = fmt.Sprintf("Lines %d-%d of %d | ↑↓ or PgUp/PgDn to scroll", scrollOffset+1, endLine, totalLines)
-		}
-		footerX := (w - len(footerText)) / 2
-		s.DrawString(footerX, footerY, footerText, StyleBorder())
+	// Screen readers announce the line under the cursor
+	if s.access.ScreenReader && selectedY >= 0 {
+		s.ShowCursor(startX+3, selectedY)
+	} else {
+		s.HideCursor()
+	}
+	s.Show()
+}
 
-		s.Sync()
+// drawWipe hides the part of the item area at x, y not yet revealed by the
+// transition, behind a line that moves right when entering a submenu and
+// left when leaving one
+func (s *Screen) drawWipe(x, y, width, height int) {
+	revealed := int(s.transition * float64(width))
+	coverX, edgeX := x+revealed, x+revealed
+	if s.transitionBack {
+		coverX, edgeX = x, x+width-1-revealed
+	}
+	s.ClearRectWithStyle(coverX, y, width-revealed, height, s.theme.StyleMenuBg())
+	edge := '│'
+	if s.access.Plain {
+		edge = '|'
+	}
+	for dy := 0; dy < height; dy++ {
+		s.DrawChar(edgeX, y+dy, edge, s.theme.StyleBorderMenuBg())
+	}
+}
 
-		// Wait for input
-		ev := <-eventChan
-		if ev == nil {
-			continue
-		}
+// closeButton is drawn at the right end of the menu's top border when the
+// mouse is on; clicking it does what Escape does
+const closeButton = "[X]"
 
-		keyEv, ok := ev.(*tcell.EventKey)
-		if !ok {
-			continue
+// recordFooterHits remembers where each clickable key hint of the footer at
+// x, y was drawn, as laid out by FormatFooter. Hints cut off at the screen's
+// right edge w are not clickable.
+func (s *Screen) recordFooterHits(x, y, w int, hint string) {
+	if hint != "" {
+		x += StringWidth(hint) + StringWidth(footerSeparator)
+	}
+	for _, k := range s.footerKeys {
+		width := StringWidth(k.Key + ": " + k.Action)
+		if x+width > w {
+			break
 		}
-
-		// Handle navigation
-		switch keyEv.Key() {
-		case tcell.KeyUp:
-			if scrollOffset > 0 {
-				scrollOffset--
-			}
-		case tcell.KeyDown:
-			if scrollOffset < len(lines)-visibleLines {
-				scrollOffset++
-			}
-		case tcell.KeyPgUp:
-			scrollOffset -= visibleLines
-			if scrollOffset < 0 {
-				scrollOffset = 0
-			}
-		case tcell.KeyPgDn:
-			scrollOffset += visibleLines
-			if scrollOffset > len(lines)-visibleLines {
-				scrollOffset = len(lines) - visibleLines
-			}
-			if scrollOffset < 0 {
-				scrollOffset = 0
-			}
-		default:
-			// Any other key returns to menu
-			return
+		if k.Press != nil {
+			s.footerHits = append(s.footerHits, footerHit{x0: x, x1: x + width, y: y, press: k.Press})
 		}
+		x += width + StringWidth(footerSeparator)
 	}
 }
 
-// drawEmptyMenuPlaceholder draws the "(No items)" placeholder
-func (s *Screen) drawEmptyMenuPlaceholder(x, y, width, height int) {
-	placeholder := "(No items)"
-	placeholderX := x + (width-len(placeholder))/2
-
+// drawEmptyMenuPlaceholder draws an empty menu's placeholder text, its
+// empty_text or "(No items)", and below it the Back (or Quit) item the
+// navigator gave the menu, selected. It returns the item's row.
+func (s *Screen) drawEmptyMenuPlaceholder(x, y, width, height int, items []config.MenuItem, navigator *menu.Navigator) int {
+	placeholder := navigator.GetEmptyText()
+	if placeholder == "" {
+		placeholder = i18n.T("(No items)")
+	}
+	placeholder = TruncateString(placeholder, width-4)
+	placeholderX := x + (width-StringWidth(placeholder))/2
 	if placeholderY := y + height/2 - 1; placeholderY >= 0 {
-		s.DrawString(placeholderX, placeholderY, placeholder, StyleTextMenuBg())
+		s.DrawString(placeholderX, placeholderY, placeholder, s.theme.StyleTextMenuBg())
 	}
 
-	// Show Back/Quit option
-	backText := "[B]ack"
-	backX := x + (width-len(backText))/2
-	if backY := y + height/2 + 1; backY >= 0 {
-		s.DrawString(backX, backY, backText, StyleTextMenuBg())
+	backY := y + height/2 + 1
+	if len(items) > 0 && backY >= 0 {
+		s.drawMenuItem(x, backY, width, items[0], true, "", navigator)
 	}
+	return backY
 }
 
-// drawMenuItems draws all menu items with scrolling support
-func (s *Screen) drawMenuItems(x, y, width, maxItems int, items []config.MenuItem, selectedIdx int, navigator *menu.Navigator, scrollOffset int) {
+// drawMenuItems draws all menu items with scrolling support and returns the
+// row of the selected item, or -1 if it is scrolled out of view
+func (s *Screen) drawMenuItems(x, y, width, maxItems int, items []config.MenuItem, selectedIdx int, navigator *menu.Navigator, scrollOffset int) int {
 	contentLineIdx := 0
+	selectedY := -1
+	iconWidth := s.iconColumnWidth(items)
 
 	// Start from scrollOffset and render up to maxItems visible lines
 	for i := scrollOffset; i < len(items); i++ {
@@ -231,9 +282,9 @@ func (s *Screen) drawMenuItems(x, y, width, maxItems int, items []config.MenuIte
 		if item.Type == "separator" {
 			// Draw separator line with border color on menu background
 			separatorY := y + contentLineIdx
-			if separatorY >= 0 {
+			if separatorY >= 0 && !s.access.Plain {
 				for col := 1; col < width-1; col++ {
-					s.DrawChar(x+col, separatorY, '─', StyleBorderMenuBg())
+					s.DrawChar(x+col, separatorY, '─', s.theme.StyleSeparator())
 				}
 			}
 			contentLineIdx++
@@ -241,38 +292,140 @@ func (s *Screen) drawMenuItems(x, y, width, maxItems int, items []config.MenuIte
 			// Draw menu item
 			itemY := y + contentLineIdx
 			isSelected := (i == selectedIdx)
-			isDisabled := navigator.IsItemDisabled(i)
+			reason := navigator.DisabledReason(i)
+
+			if badge, ok := s.itemBadges[item.Key()]; ok {
+				item.Badge = badge
+			}
+
+			// Icons line up in a column before the labels
+			if iconWidth > 0 {
+				item.Label = s.iconPrefix(item, iconWidth) + item.Label
+			}
 
-			s.drawMenuItem(x, itemY, width, item, isSelected, isDisabled, navigator)
+			// Group headers show whether they are open; their items are indented
+			if item.Type == "group" {
+				item.Label = s.groupLabel(item.Label, navigator.IsGroupCollapsed(i))
+			} else if navigator.InGroup(i) {
+				item.Label = "  " + item.Label
+			}
+
+			s.drawMenuItem(x, itemY, width, item, isSelected, reason, navigator)
+			if isSelected {
+				selectedY = itemY
+			}
 			contentLineIdx++
 		}
 	}
+	return selectedY
+}
+
+// itemIcon returns the icon drawn before item's label: its own, or the
+// theme's for its type. Screen readers get none.
+func (s *Screen) itemIcon(item config.MenuItem) string {
+	if s.access.ScreenReader {
+		return ""
+	}
+	if item.Icon != "" {
+		return item.Icon
+	}
+	return s.theme.Icon(item.Type)
+}
+
+// iconColumnWidth returns the width of the widest icon among items, at most
+// 2 columns, or 0 if none has an icon
+func (s *Screen) iconColumnWidth(items []config.MenuItem) int {
+	width := 0
+	for _, item := range items {
+		if item.Type != "separator" {
+			width = max(width, StringWidth(s.itemIcon(item)))
+		}
+	}
+	return min(width, 2)
 }
 
-// drawMenuItem draws a single menu item
-func (s *Screen) drawMenuItem(x, y, width int, item config.MenuItem, isSelected, isDisabled bool, navigator *menu.Navigator) {
+// iconPrefix returns item's icon padded to width columns and followed by a
+// space, or blanks if it has none, so the labels after it line up
+func (s *Screen) iconPrefix(item config.MenuItem, width int) string {
+	icon := s.itemIcon(item)
+	if StringWidth(icon) > width {
+		icon = ""
+	}
+	return icon + strings.Repeat(" ", width-StringWidth(icon)+1)
+}
+
+// groupLabel prefixes a group header's label with ▸ when collapsed or ▾ when
+// expanded (+ and - in plain mode); screen readers get the state in words
+func (s *Screen) groupLabel(label string, collapsed bool) string {
+	glyph, state := "▾ ", i18n.T("(expanded)")
+	if collapsed {
+		glyph, state = "▸ ", i18n.T("(collapsed)")
+	}
+	if s.access.Plain {
+		glyph = "- "
+		if collapsed {
+			glyph = "+ "
+		}
+	}
+	if s.access.ScreenReader {
+		label += " " + state
+	}
+	return glyph + label
+}
+
+// drawMenuItem draws a single menu item; disabledReason is "" for enabled items
+func (s *Screen) drawMenuItem(x, y, width int, item config.MenuItem, isSelected bool, disabledReason string, navigator *menu.Navigator) {
+	isDisabled := disabledReason != ""
+
 	// Determine style for normal text
 	var style tcell.Style
 	var hotkeyStyle tcell.Style
 	
 	if isDisabled {
-		style = StyleDisabledMenuBg()
-		hotkeyStyle = StyleDisabledMenuBg()
+		style = s.theme.StyleDisabledMenuBg()
+		hotkeyStyle = s.theme.StyleDisabledMenuBg()
 	} else if isSelected {
-		style = StyleHighlight()
-		hotkeyStyle = StyleHotkeyHighlight()
+		style = s.theme.StyleHighlight()
+		hotkeyStyle = s.theme.StyleHotkeyHighlight()
 	} else {
-		style = StyleTextMenuBg()
-		hotkeyStyle = StyleHotkeyMenuBg()
+		style = s.theme.StyleTextMenuBg()
+		hotkeyStyle = s.theme.StyleHotkeyMenuBg()
 	}
 
 	// Clear the line with menu background color
-	s.ClearRectWithStyle(x+1, y, width-2, 1, StyleMenuBg())
+	s.ClearRectWithStyle(x+1, y, width-2, 1, s.theme.StyleMenuBg())
 
-	// Build the display text
+	// Build the display text; a screen reader can't see the disabled color
 	label := item.Label
-	if len(label) > width-6 {
-		label = TruncateString(label, width-6)
+	note := s.disabledNotes[disabledReason]
+	if disabledReason == menu.DisabledByConfig && s.disabledNotes != nil && item.DisabledReason != "" {
+		// Items disabled in the config carry their own reason
+		note = "(" + item.DisabledReason + ")"
+	} else if disabledReason == menu.DisabledBySchedule && s.disabledNotes != nil {
+		// Items outside their hours say when they can be used
+		note = "(" + i18n.Tf("available %s", item.ScheduleText()) + ")"
+		if item.DisabledReason != "" {
+			note = "(" + item.DisabledReason + ")"
+		}
+	}
+	if isDisabled && note != "" {
+		label += " " + note
+	} else if isDisabled && s.access.ScreenReader {
+		label += " " + i18n.T("(unavailable)")
+	}
+
+	// The badge keeps at most a third of the row, and the label makes room
+	// for it
+	badge := item.Badge
+	if StringWidth(badge) > width/3 {
+		badge = TruncateString(badge, width/3)
+	}
+	labelWidth := width - 6
+	if badge != "" {
+		labelWidth -= StringWidth(badge) + 3 // the badge with a space either side
+	}
+	if StringWidth(label) > labelWidth {
+		label = TruncateString(label, labelWidth)
 	}
 
 	// Draw the item content
@@ -284,6 +437,14 @@ func (s *Screen) drawMenuItem(x, y, width int, item config.MenuItem, isSelected,
 	// Note: Auto-assigned hotkeys are handled in the menu package
 	// If needed, add a public method to navigator to fetch hotkeys for display
 
+	// A two-key sequence can't be marked in the label; it is shown at the
+	// right end of the row instead
+	sequence := ""
+	if len([]rune(hotkey)) == 2 {
+		sequence = strings.ToUpper(hotkey)
+		hotkey = ""
+	}
+
 	// Render text with potential hotkey highlighting
 	currentX := itemContentX
 	if isSelected && !isDisabled {
@@ -294,15 +455,43 @@ func (s *Screen) drawMenuItem(x, y, width int, item config.MenuItem, isSelected,
 		currentX = s.drawItemWithHotkey(currentX, y, itemContent, hotkey, hotkeyStyle, style)
 	}
 
+	rightX := x + width - 4
+	if sequence != "" {
+		sequenceX := rightX - StringWidth(sequence)
+		if sequenceX > currentX {
+			s.DrawString(sequenceX, y, sequence, hotkeyStyle)
+			rightX = sequenceX - 1
+		}
+	}
+	if badge != "" {
+		badgeX := rightX - StringWidth(badge)
+		if badgeX > currentX {
+			badgeStyle := style
+			if !isSelected && !isDisabled {
+				badgeStyle = s.theme.StyleBorderMenuBg()
+			}
+			s.DrawString(badgeX, y, badge, badgeStyle)
+		}
+	}
+
+	// Mark the selection in text as well as color
+	if isSelected && s.access.ScreenReader {
+		s.DrawChar(x+1, y, '>', s.theme.StyleBorderMenuBg())
+	}
+
 	// Draw menu item type indicator (► for submenu)
 	if item.Type == "submenu" && !isDisabled {
 		typeIndicatorX := (x + width - 3)
 		if typeIndicatorX > currentX {
-			typeStyle := StyleHighlight()
+			typeStyle := s.theme.StyleHighlight()
 			if !isSelected {
-				typeStyle = StyleBorderMenuBg()
+				typeStyle = s.theme.StyleBorderMenuBg()
+			}
+			indicator := '►'
+			if s.access.Plain {
+				indicator = '>'
 			}
-			s.DrawChar(typeIndicatorX, y, '►', typeStyle)
+			s.DrawChar(typeIndicatorX, y, indicator, typeStyle)
 		}
 	}
 }
@@ -318,15 +507,17 @@ func (s *Screen) drawItemWithHotkey(x, y int, text, hotkey string, hotkeyStyle,
 			currentX += s.DrawString(currentX, y, seg.Text, normalStyle)
 		}
 	} else {
-		// Draw with hotkey highlighting
-		hotkeyChar := rune(strings.ToUpper(hotkey)[0])
-		for _, ch := range text {
+		// Draw with hotkey highlighting, a character with its combining
+		// marks (or an emoji sequence) at a time
+		hotkeyChar := string(rune(strings.ToUpper(hotkey)[0]))
+		graphemes := uniseg.NewGraphemes(text)
+		for graphemes.Next() {
+			ch := graphemes.Str()
 			if ch == hotkeyChar {
-				s.DrawChar(currentX, y, ch, hotkeyStyle)
+				currentX += s.DrawString(currentX, y, ch, hotkeyStyle)
 			} else {
-				s.DrawChar(currentX, y, ch, normalStyle)
+				currentX += s.DrawString(currentX, y, ch, normalStyle)
 			}
-			currentX++
 		}
 	}
 
@@ -343,16 +534,17 @@ func indexOf(items []config.MenuItem, target config.MenuItem) int {
 	return -1
 }
 
-// DrawDialog renders a dialog box with buttons
-func (s *Screen) DrawDialog(title, message string, buttons []string, eventChan <-chan tcell.Event) int {
+// DrawSelectList shows a bordered list of options and lets the user pick one.
+// The entry at marked is flagged with "*" (pass -1 for none). Returns the chosen
+// index, or -1 if the user pressed Escape.
+func (s *Screen) DrawSelectList(title string, options []string, selected, marked int, eventChan <-chan tcell.Event) int {
 	w, h := s.Size()
 
-	// Dialog size
-	dialogWidth := 50
-	dialogHeight := 12
-	startX := (w - dialogWidth) / 2
-	startY := (h - dialogHeight) / 2
-
+	listWidth := 40
+	maxVisible := 14
+	listHeight := maxVisible + 4
+	startX := (w - listWidth) / 2
+	startY := (h - listHeight) / 2
 	if startX < 0 {
 		startX = 0
 	}
@@ -360,87 +552,229 @@ func (s *Screen) DrawDialog(title, message string, buttons []string, eventChan <
 		startY = 0
 	}
 
-	// Clear background
-	s.ClearRect(0, 0, w, h)
-
-	// Draw border
-	s.DrawBorder(startX, startY, dialogWidth, dialogHeight, " "+title+" ")
+	if selected < 0 || selected >= len(options) {
+		selected = 0
+	}
+	scrollOffset := 0
 
-	// Draw message text wrapped
-	messageStartY := startY + 2
-	lines := WrapText(message, dialogWidth-4)
-	for i, line := range lines {
-		if i >= 5 {
-			break
+	for {
+		if selected < scrollOffset {
+			scrollOffset = selected
 		}
-		msgX := startX + 2
-		msgY := messageStartY + i
-		if msgY < h {
-			s.DrawString(msgX, msgY, line, StyleNormal())
+		if selected >= scrollOffset+maxVisible {
+			scrollOffset = selected - maxVisible + 1
 		}
-	}
 
-	// Draw buttons
-	buttonY := startY + dialogHeight - 3
-	buttonSpacing := (dialogWidth - 4) / len(buttons)
-	for i, btn := range buttons {
-		btnX := startX + 2 + (i * buttonSpacing)
-		btnText := fmt.Sprintf("[%s]", btn)
-		if btnX+len(btnText) < startX+dialogWidth-1 {
-			if buttonY < h {
-				s.DrawString(btnX, buttonY, btnText, StyleHighlight())
+		s.ClearRect(0, 0, w, h)
+		s.ClearRectWithStyle(startX, startY, listWidth, listHeight, s.theme.StyleMenuBg())
+		s.DrawBorderWithStyle(startX, startY, listWidth, listHeight, " "+title+" ", s.theme.StyleBorderMenuBg())
+		s.DrawShadow(startX, startY, listWidth, listHeight)
+
+		for i := 0; i < maxVisible && scrollOffset+i < len(options); i++ {
+			idx := scrollOffset + i
+			prefix := "  "
+			if idx == marked {
+				prefix = "* "
 			}
+			line := TruncateString(prefix+options[idx], listWidth-6)
+			style := s.theme.StyleTextMenuBg()
+			if idx == selected {
+				style = s.theme.StyleHighlight()
+				s.ClearRectWithStyle(startX+2, startY+2+i, listWidth-4, 1, style)
+			}
+			s.DrawString(startX+3, startY+2+i, line, style)
 		}
+
+		footer := i18n.T("ENTER: Select | ESC: Cancel")
+		s.DrawString(startX+(listWidth-StringWidth(footer))/2, startY+listHeight-1, footer, s.theme.StyleBorderMenuBg())
+		s.HideCursor()
+		s.Show()
+
+		ev := <-eventChan
+		keyEv, ok := ev.(*tcell.EventKey)
+		if !ok {
+			continue
+		}
+		switch keyEv.Key() {
+		case tcell.KeyUp:
+			if selected > 0 {
+				selected--
+			}
+		case tcell.KeyDown:
+			if selected < len(options)-1 {
+				selected++
+			}
+		case tcell.KeyEnter:
+			if len(options) == 0 {
+				return -1
+			}
+			return selected
+		case tcell.KeyEscape, tcell.KeyLeft:
+			return -1
+		}
+	}
+}
+
+// DrawThemeSelector shows the list of theme names beside a live preview panel.
+// preview is called with each newly highlighted name so the caller can apply it;
+// the panel is then drawn with the current theme styles. The entry at current is
+// flagged with "*". Returns the chosen index, or -1 if the user pressed Escape.
+func (s *Screen) DrawThemeSelector(names []string, current int, preview func(name string), eventChan <-chan tcell.Event) int {
+	w, h := s.Size()
+
+	listWidth := 28
+	panelWidth := 34
+	boxHeight := 18
+	maxVisible := boxHeight - 4
+	startX := (w - listWidth - panelWidth - 2) / 2
+	startY := (h - boxHeight) / 2
+	if startX < 0 {
+		startX = 0
 	}
+	if startY < 0 {
+		startY = 0
+	}
+	panelX := startX + listWidth + 2
 
-	s.Sync()
+	selected := current
+	if selected < 0 || selected >= len(names) {
+		selected = 0
+	}
+	scrollOffset := 0
+	previewed := -1
 
-	// Simple event loop for button selection
-	selectedButton := 0
 	for {
+		if selected != previewed && len(names) > 0 {
+			preview(names[selected])
+			previewed = selected
+		}
+		if selected < scrollOffset {
+			scrollOffset = selected
+		}
+		if selected >= scrollOffset+maxVisible {
+			scrollOffset = selected - maxVisible + 1
+		}
+
+		s.ClearRect(0, 0, w, h)
+
+		// Theme list
+		s.ClearRectWithStyle(startX, startY, listWidth, boxHeight, s.theme.StyleMenuBg())
+		s.DrawBorderWithStyle(startX, startY, listWidth, boxHeight, " "+i18n.T("Themes")+" ", s.theme.StyleBorderMenuBg())
+		for i := 0; i < maxVisible && scrollOffset+i < len(names); i++ {
+			idx := scrollOffset + i
+			prefix := "  "
+			if idx == current {
+				prefix = "* "
+			}
+			style := s.theme.StyleTextMenuBg()
+			if idx == selected {
+				style = s.theme.StyleHighlight()
+				s.ClearRectWithStyle(startX+1, startY+2+i, listWidth-2, 1, style)
+			}
+			s.DrawString(startX+2, startY+2+i, TruncateString(prefix+names[idx], listWidth-4), style)
+		}
+
+		// Preview panel: a miniature menu using the highlighted theme
+		s.drawThemePreview(panelX, startY, panelWidth, boxHeight)
+
+		footer := i18n.T("ENTER: Apply | ESC: Cancel")
+		s.DrawString(startX+(listWidth+panelWidth+2-StringWidth(footer))/2, startY+boxHeight+1, footer, s.theme.StyleNormal())
+		s.HideCursor()
+		s.Show()
+
 		ev := <-eventChan
-		switch e := ev.(type) {
-		case *tcell.EventKey:
-			switch e.Key() {
-			case tcell.KeyLeft:
-				selectedButton = (selectedButton - 1 + len(buttons)) % len(buttons)
-			case tcell.KeyRight:
-				selectedButton = (selectedButton + 1) % len(buttons)
-			case tcell.KeyEnter:
-				return selectedButton
-			case tcell.KeyEscape:
-				return 0 // Default to first button on ESC
+		keyEv, ok := ev.(*tcell.EventKey)
+		if !ok {
+			continue
+		}
+		switch keyEv.Key() {
+		case tcell.KeyUp:
+			if selected > 0 {
+				selected--
 			}
+		case tcell.KeyDown:
+			if selected < len(names)-1 {
+				selected++
+			}
+		case tcell.KeyEnter:
+			if len(names) == 0 {
+				return -1
+			}
+			return selected
+		case tcell.KeyEscape, tcell.KeyLeft:
+			return -1
+		}
+	}
+}
 
-			// Redraw with new selection
-			s.ClearRect(0, 0, w, h)
-			s.DrawBorder(startX, startY, dialogWidth, dialogHeight, " "+title+" ")
-			for i, line := range lines {
-				if i >= 5 {
-					break
-				}
-				msgX := startX + 2
-				msgY := messageStartY + i
-				if msgY < h {
-					s.DrawString(msgX, msgY, line, StyleNormal())
-				}
+// drawThemePreview renders a sample menu showing each theme style
+func (s *Screen) drawThemePreview(x, y, width, height int) {
+	s.ClearRectWithStyle(x, y, width, height, s.theme.StyleMenuBg())
+	s.DrawBorderWithStyle(x, y, width, height, " "+i18n.T("Preview")+" ", s.theme.StyleBorderMenuBg())
+
+	innerWidth := width - 2
+	s.DrawString(x+2, y+2, i18n.T("Normal item"), s.theme.StyleTextMenuBg())
+
+	s.ClearRectWithStyle(x+1, y+3, innerWidth, 1, s.theme.StyleHighlight())
+	// The first letter of each sample stands in for its hotkey
+	selected := i18n.T("Selected item")
+	r, size := utf8.DecodeRuneInString(selected)
+	s.DrawChar(x+2, y+3, r, s.theme.StyleHotkeyHighlight())
+	s.DrawString(x+3, y+3, selected[size:], s.theme.StyleHighlight())
+
+	hotkey := i18n.T("Hotkey item")
+	r, size = utf8.DecodeRuneInString(hotkey)
+	s.DrawChar(x+2, y+4, r, s.theme.StyleHotkeyMenuBg())
+	s.DrawString(x+3, y+4, hotkey[size:], s.theme.StyleTextMenuBg())
+
+	s.DrawString(x+2, y+5, i18n.T("Disabled item"), s.theme.StyleDisabledMenuBg())
+
+	for col := 1; col < width-1; col++ {
+		s.DrawChar(x+col, y+6, '─', s.theme.StyleSeparator())
+	}
+	s.DrawString(x+2, y+7, i18n.T("Submenu"), s.theme.StyleTextMenuBg())
+	s.DrawChar(x+width-3, y+7, '►', s.theme.StyleBorderMenuBg())
+
+	s.DrawShadow(x, y, width, height)
+}
+
+// PromptKey shows a message dialog and waits for a single character key.
+// Returns the rune pressed, or false if the user pressed Escape.
+func (s *Screen) PromptKey(title, message string, eventChan <-chan tcell.Event) (rune, bool) {
+	w, h := s.Size()
+
+	dialogWidth := 50
+	dialogHeight := 9
+	startX := (w - dialogWidth) / 2
+	startY := (h - dialogHeight) / 2
+	if startX < 0 {
+		startX = 0
+	}
+	if startY < 0 {
+		startY = 0
+	}
+
+	for {
+		s.ClearRect(0, 0, w, h)
+		s.DrawBorder(startX, startY, dialogWidth, dialogHeight, " "+title+" ")
+		for i, line := range WrapText(message, dialogWidth-4) {
+			if i >= dialogHeight-4 {
+				break
 			}
+			s.DrawString(startX+2, startY+2+i, line, s.theme.StyleNormal())
+		}
+		hint := i18n.T("ESC: Cancel")
+		s.DrawString(startX+(dialogWidth-StringWidth(hint))/2, startY+dialogHeight-2, hint, s.theme.StyleBorder())
+		s.Show()
 
-			// Redraw buttons with selection
-			for i, btn := range buttons {
-				btnX := startX + 2 + (i * buttonSpacing)
-				btnText := fmt.Sprintf("[%s]", btn)
-				style := StyleHighlight()
-				if i != selectedButton {
-					style = StyleNormal()
-				}
-				if btnX+len(btnText) < startX+dialogWidth-1 {
-					if buttonY < h {
-						s.DrawString(btnX, buttonY, btnText, style)
-					}
-				}
+		ev := <-eventChan
+		if keyEv, ok := ev.(*tcell.EventKey); ok {
+			switch keyEv.Key() {
+			case tcell.KeyEscape:
+				return 0, false
+			case tcell.KeyRune:
+				return keyEv.Rune(), true
 			}
-			s.Sync()
 		}
 	}
 }
@@ -461,19 +795,25 @@ func WrapText(text string, maxWidth int) []string {
 		}
 		var currentLine string
 		for _, word := range words {
-			// If the word itself is longer than maxWidth, hard-break it
-			for len(word) > maxWidth {
+			// If the word itself is wider than maxWidth, hard-break it
+			for StringWidth(word) > maxWidth {
 				if currentLine != "" {
 					lines = append(lines, currentLine)
 					currentLine = ""
 				}
-				lines = append(lines, word[:maxWidth])
-				word = word[maxWidth:]
+				head := runewidth.Truncate(word, maxWidth, "")
+				if head == "" {
+					// A single character wider than maxWidth; take it anyway
+					_, size := utf8.DecodeRuneInString(word)
+					head = word[:size]
+				}
+				lines = append(lines, head)
+				word = word[len(head):]
 			}
 			if len(word) == 0 {
 				continue
 			}
-			if len(currentLine)+1+len(word) <= maxWidth {
+			if StringWidth(currentLine)+1+StringWidth(word) <= maxWidth {
 				if currentLine == "" {
 					currentLine = word
 				} else {
@@ -500,9 +840,18 @@ func (s *Screen) DrawSplashScreen(version string) {
 	// Clear screen
 	s.Clear()
 
-	// Draw splash box
+	// The title is drawn big when configured and there is room for it
+	titleLines := []string{"MenuWorks 3.X"}
 	splashWidth := 50
-	splashHeight := 12
+	if big := s.bigTitleLines(s.bigTitleSplash); big != nil {
+		if bigWidth := StringWidth(big[0]) + 6; bigWidth <= w && 11+len(big) <= h {
+			titleLines = big
+			splashWidth = max(splashWidth, bigWidth)
+		}
+	}
+
+	// Draw splash box
+	splashHeight := 11 + len(titleLines)
 	startX := (w - splashWidth) / 2
 	startY := (h - splashHeight) / 2
 
@@ -517,104 +866,59 @@ func (s *Screen) DrawSplashScreen(version string) {
 
 	// Draw content
 	titleY := startY + 3
-	titleText := "MenuWorks 3.X"
-	titleX := startX + (splashWidth-len(titleText))/2
-	if titleY < h {
-		s.DrawString(titleX, titleY, titleText, StyleHighlight())
+	titleStyle := s.theme.StyleHighlight()
+	if len(titleLines) > 1 {
+		titleStyle = s.theme.StyleBorder()
+	}
+	for i, titleText := range titleLines {
+		titleX := startX + (splashWidth-StringWidth(titleText))/2
+		if titleY+i < h {
+			s.DrawString(titleX, titleY+i, titleText, titleStyle)
+		}
 	}
 
-	versionY := startY + 5
-	versionText := fmt.Sprintf("Version: %s", version)
-	versionX := startX + (splashWidth-len(versionText))/2
+	versionY := titleY + len(titleLines) + 1
+	versionText := i18n.Tf("Version: %s", version)
+	versionX := startX + (splashWidth-StringWidth(versionText))/2
 	if versionY < h {
-		s.DrawString(versionX, versionY, versionText, StyleNormal())
+		s.DrawString(versionX, versionY, versionText, s.theme.StyleNormal())
 	}
 
-	creditsY := startY + 7
-	creditsText := "A Retro DOS-Style TUI"
-	creditsX := startX + (splashWidth-len(creditsText))/2
+	creditsY := versionY + 2
+	creditsText := i18n.T("A Retro DOS-Style TUI")
+	creditsX := startX + (splashWidth-StringWidth(creditsText))/2
 	if creditsY < h {
-		s.DrawString(creditsX, creditsY, creditsText, StyleNormal())
+		s.DrawString(creditsX, creditsY, creditsText, s.theme.StyleNormal())
 	}
 
-	s.Sync()
+	s.Show()
 }
 
-// ShowItemHelp displays a dialog with command info and help text for a menu item
-func (s *Screen) ShowItemHelp(command, help string, eventChan <-chan tcell.Event) {
-	w, h := s.Size()
-
-	// Dialog dimensions
-	dialogWidth := 60
-	dialogHeight := 14
-	startX := (w - dialogWidth) / 2
-	startY := (h - dialogHeight) / 2
-
-	if startX < 0 {
-		startX = 0
+// bigTitleLines returns the big title in the block font if on is set, or nil
+// if it is off, has a character the font lacks, or a screen reader would
+// read it out
+func (s *Screen) bigTitleLines(on bool) []string {
+	if !on || s.bigTitle == "" || s.access.ScreenReader {
+		return nil
 	}
-	if startY < 0 {
-		startY = 0
+	fill := '█'
+	if s.access.Plain {
+		fill = '#'
 	}
+	return BigText(s.bigTitle, fill)
+}
 
-	// Build the help dialog message
-	var messageLines []string
-	messageLines = append(messageLines, "Command:")
-	messageLines = append(messageLines, command)
-
-	// Add help text if available
-	if help != "" {
-		messageLines = append(messageLines, "")
-		messageLines = append(messageLines, help)
+// drawBigTitle draws the big title centered above the menu box at x, y, if
+// it fits in the rows above the box with one to spare
+func (s *Screen) drawBigTitle(x, y, width int) {
+	lines := s.bigTitleLines(s.bigTitleMenu)
+	w, _ := s.Size()
+	if lines == nil || y < BigTextHeight+1 || StringWidth(lines[0]) > w {
+		return
 	}
-
-	// Render loop
-	for {
-		s.ClearRect(0, 0, w, h)
-		s.DrawBorder(startX, startY, dialogWidth, dialogHeight, " Item Info ")
-
-		// Draw message lines
-		msgX := startX + 2
-		msgY := startY + 2
-		for _, line := range messageLines {
-			if msgY >= startY+dialogHeight-3 {
-				break
-			}
-			// Handle empty lines (blank space)
-			if line == "" {
-				msgY++
-				continue
-			}
-			// Wrap text to fit dialog width
-			wrappedLines := WrapText(line, dialogWidth-4)
-			for _, wrappedLine := range wrappedLines {
-				if msgY >= startY+dialogHeight-3 {
-					break
-				}
-				if msgY < h {
-					s.DrawString(msgX, msgY, wrappedLine, StyleNormal())
-				}
-				msgY++
-			}
-		}
-
-		// Draw OK button
-		buttonY := startY + dialogHeight - 2
-		btnX := startX + (dialogWidth-len("[OK]"))/2 - 1
-		if buttonY < h {
-			s.DrawString(btnX, buttonY, "[OK]", StyleHighlight())
-		}
-
-		s.Sync()
-
-		// Handle input
-		ev := <-eventChan
-		if keyEv, ok := ev.(*tcell.EventKey); ok {
-			switch keyEv.Key() {
-			case tcell.KeyEnter, tcell.KeyEscape:
-				return
-			}
-		}
+	bigX := x + (width-StringWidth(lines[0]))/2
+	bigX = max(0, min(bigX, w-StringWidth(lines[0])))
+	for i, line := range lines {
+		s.DrawString(bigX, y-BigTextHeight-1+i, line, s.theme.StyleBorder())
 	}
 }
-
@@ -1,15 +1,66 @@
 package ui
 
 import (
+	"encoding/base64"
 	"fmt"
+	"io"
+	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/gdamore/tcell/v2"
+	"github.com/mattn/go-runewidth"
 )
 
+// ScreenBackend is the terminal a Screen draws to. tcell.Screen satisfies it;
+// tests use tcell's simulation screen, and other front-ends can supply their own.
+type ScreenBackend interface {
+	Init() error
+	Fini()
+	Size() (width, height int)
+	Clear()
+	SetContent(x, y int, primary rune, combining []rune, style tcell.Style)
+	SetStyle(style tcell.Style)
+	ShowCursor(x, y int)
+	HideCursor()
+	Show()
+	Sync()
+	PollEvent() tcell.Event
+	EnableMouse(flags ...tcell.MouseFlags)
+	Beep() error
+}
+
 // Screen wraps tcell screen with rendering utilities
 type Screen struct {
-	tcellScreen tcell.Screen
+	tcellScreen ScreenBackend
+	theme       Theme
+	titleBar    TitleBar
+	badge       string
+	flash       bool // visual bell: borders are drawn highlighted
+	footerKeys  []KeyHint
+	footerOff   bool
+	pendingKey  string // first key of a hotkey sequence, shown under the menu
+	itemBadges  map[string]string // badge_exec output by item Key, replacing the items' badge text
+	mouse       bool   // mouse support is on; footer hints and the close button are clickable
+	footerHits  []footerHit
+	closeX      int // position of the header's close button, or -1 when not drawn
+	closeY      int
+	// transition is how much of a newly entered menu is revealed, from 0 to
+	// 1; transitionBack reveals it from the right, for leaving a submenu
+	transition     float64
+	transitionBack bool
+	// bigTitle is drawn in the block font above the menu and on the splash
+	// screen, where enabled
+	bigTitle       string
+	bigTitleMenu   bool
+	bigTitleSplash bool
+	layout      Layout
+	access      Accessibility
+	symbols     bool // prefix statuses with ✓/✗
+	disabledNotes map[string]string // text after disabled items, by menu.Navigator.DisabledReason
+
+	// resized is set by the event poller so the next Show repaints everything
+	resized atomic.Bool
 }
 
 // NewScreen initializes and returns a new Screen
@@ -18,20 +69,89 @@ func NewScreen() (*Screen, error) {
 	if err != nil {
 		return nil, err
 	}
+	return NewScreenWithBackend(s)
+}
 
-	if err := s.Init(); err != nil {
+// NewScreenWithBackend initializes backend and returns a Screen drawing to it
+func NewScreenWithBackend(backend ScreenBackend) (*Screen, error) {
+	if err := backend.Init(); err != nil {
 		return nil, err
 	}
 
+	screen := &Screen{tcellScreen: backend, theme: DefaultTheme(), titleBar: DefaultTitleBar(), layout: DefaultLayout(), closeX: -1}
+
 	// Set color palette
-	s.SetStyle(defaultStyle())
+	screen.RefreshTheme()
+
+	return screen, nil
+}
+
+// NewSimulationScreen returns a headless Screen of the given size along with
+// the simulation backend, whose cells can be inspected and which accepts
+// injected key and mouse events.
+func NewSimulationScreen(width, height int) (*Screen, tcell.SimulationScreen, error) {
+	sim := tcell.NewSimulationScreen("")
+	screen, err := NewScreenWithBackend(sim)
+	if err != nil {
+		return nil, nil, err
+	}
+	sim.SetSize(width, height)
+	return screen, sim, nil
+}
+
+// suspender is implemented by backends that can hand the terminal back
+// temporarily, as tcell screens do
+type suspender interface {
+	Suspend() error
+	Resume() error
+}
+
+// Suspend releases the terminal so a command can use it directly: the screen
+// leaves raw mode and the alternate buffer and stops reading input. Backends
+// without a terminal to release ignore it.
+func (s *Screen) Suspend() error {
+	if b, ok := s.tcellScreen.(suspender); ok {
+		return b.Suspend()
+	}
+	return nil
+}
+
+// Resume takes the terminal back after Suspend; the next Show repaints
+// everything, since the command may have drawn over the screen
+func (s *Screen) Resume() error {
+	s.resized.Store(true)
+	if b, ok := s.tcellScreen.(suspender); ok {
+		return b.Resume()
+	}
+	return nil
+}
+
+// ttyProvider is implemented by backends that draw to a terminal, as tcell screens do
+type ttyProvider interface {
+	Tty() (tcell.Tty, bool)
+}
 
-	return &Screen{tcellScreen: s}, nil
+// SetClipboard asks the terminal to put text on its clipboard with an OSC 52
+// escape sequence, which also reaches the user's terminal over SSH. Terminals
+// may ignore it, so the result only reports whether the sequence was sent.
+func (s *Screen) SetClipboard(text string) bool {
+	b, ok := s.tcellScreen.(ttyProvider)
+	if !ok {
+		return false
+	}
+	tty, ok := b.Tty()
+	if !ok {
+		return false
+	}
+	_, err := io.WriteString(tty, "\x1b]52;c;"+base64.StdEncoding.EncodeToString([]byte(text))+"\a")
+	return err == nil
 }
 
-// EnableMouse enables mouse button event handling
+// EnableMouse enables mouse button event handling, and draws a close button
+// in the menu header
 func (s *Screen) EnableMouse() {
 	s.tcellScreen.EnableMouse(tcell.MouseButtonEvents)
+	s.mouse = true
 }
 
 // Close closes the screen
@@ -49,6 +169,41 @@ func (s *Screen) Clear() {
 	s.tcellScreen.Clear()
 }
 
+// Accessibility adjusts drawing for screen readers and low vision
+type Accessibility struct {
+	Plain        bool // draw no shadows, box art, or arrow glyphs
+	ScreenReader bool // mark the selection with text and keep the cursor on it
+}
+
+// SetAccessibility sets how menus and dialogs are drawn for accessibility
+func (s *Screen) SetAccessibility(a Accessibility) {
+	s.access = a
+}
+
+// SetStatusSymbols sets whether command results are marked with ✓ and ✗ as
+// well as the success and failure colors
+func (s *Screen) SetStatusSymbols(on bool) {
+	s.symbols = on
+}
+
+// SetDisabledNotes sets the text shown after disabled menu items, keyed by
+// the reason from menu.Navigator.DisabledReason. Nil shows no notes.
+func (s *Screen) SetDisabledNotes(notes map[string]string) {
+	s.disabledNotes = notes
+}
+
+// StatusText returns text as a command result, marked ✓ or ✗ when status
+// symbols are on
+func (s *Screen) StatusText(ok bool, text string) string {
+	if !s.symbols {
+		return text
+	}
+	if ok {
+		return "✓ " + text
+	}
+	return "✗ " + text
+}
+
 // ShowCursor shows the cursor
 func (s *Screen) ShowCursor(x, y int) {
 	s.tcellScreen.ShowCursor(x, y)
@@ -59,11 +214,34 @@ func (s *Screen) HideCursor() {
 	s.tcellScreen.HideCursor()
 }
 
-// Sync syncs the screen
+// Sync repaints the whole terminal. Prefer Show for normal redraws.
 func (s *Screen) Sync() {
+	s.resized.Store(false)
 	s.tcellScreen.Sync()
 }
 
+// Show flushes the frame to the terminal. tcell keeps the previous frame and
+// only writes cells that changed, so unchanged rows cost nothing; a full
+// repaint happens only on the first Show after a resize.
+func (s *Screen) Show() {
+	if s.resized.Swap(false) {
+		s.tcellScreen.Sync()
+		return
+	}
+	s.tcellScreen.Show()
+}
+
+// Beep sounds the terminal bell
+func (s *Screen) Beep() {
+	s.tcellScreen.Beep()
+}
+
+// SetFlash turns the visual bell on or off; while on, box borders are drawn
+// in the highlight style. The caller redraws and turns it off again.
+func (s *Screen) SetFlash(on bool) {
+	s.flash = on
+}
+
 // PollEvent polls for an event
 func (s *Screen) PollEvent() tcell.Event {
 	return s.tcellScreen.PollEvent()
@@ -79,6 +257,9 @@ func (s *Screen) StartEventPoller() <-chan tcell.Event {
 			if ev == nil {
 				return
 			}
+			if _, ok := ev.(*tcell.EventResize); ok {
+				s.resized.Store(true)
+			}
 			eventChan <- ev
 		}
 	}()
@@ -87,36 +268,46 @@ func (s *Screen) StartEventPoller() <-chan tcell.Event {
 
 // SetCellUnsafe sets a cell at (x, y) with the given character and style
 func (s *Screen) SetCellUnsafe(x, y int, r rune, st tcell.Style) {
-	s.tcellScreen.SetCell(x, y, st, r)
+	s.tcellScreen.SetContent(x, y, r, nil, st)
 }
 
 // RefreshTheme updates the screen's default style to reflect current theme colors
 func (s *Screen) RefreshTheme() {
-	s.tcellScreen.SetStyle(defaultStyle())
+	s.tcellScreen.SetStyle(s.theme.defaultStyle())
 }
 
 
 
-// Color constants for VGA palette (mutable for theme support)
-var (
-	darkBlue     = tcell.ColorBlue
-	brightCyan   = tcell.ColorAqua
-	white        = tcell.ColorWhite
-	lightGray    = tcell.Color250  // Light gray
-	darkGray     = tcell.Color240  // Dark gray for shadow
-	brightYellow = tcell.ColorYellow
-	
-	// Theme-specific colors (can be overridden by ApplyTheme)
-	colorBackground  = tcell.ColorBlue
-	colorText        = tcell.Color250
-	colorBorder      = tcell.ColorAqua
-	colorHighlightBg = tcell.ColorBlue
-	colorHighlightFg = tcell.ColorWhite
-	colorHotkey      = tcell.ColorYellow
-	colorShadow      = tcell.Color240
-	colorDisabled    = tcell.Color240
-	colorMenuBg      = tcell.ColorNavy
-)
+// Theme holds the resolved colors used to build every UI style.
+// Each Screen owns its own Theme, so screens never share mutable color state.
+type Theme struct {
+	background  tcell.Color
+	text        tcell.Color
+	border      tcell.Color
+	highlightBg tcell.Color
+	highlightFg tcell.Color
+	hotkey      tcell.Color
+	shadow      tcell.Color
+	disabled    tcell.Color
+	menuBg      tcell.Color
+	success     tcell.Color
+	failure     tcell.Color
+
+	titleBold       bool
+	selectedReverse bool
+	hotkeyUnderline bool
+	separatorDim    bool
+
+	noShadow    bool
+	transparent bool
+
+	icons map[string]string // default icon by item type
+}
+
+// Icon returns the theme's default icon for items of itemType, or ""
+func (t Theme) Icon(itemType string) string {
+	return t.icons[itemType]
+}
 
 // ThemeColors represents a color scheme for the UI
 type ThemeColors struct {
@@ -129,11 +320,46 @@ type ThemeColors struct {
 	Shadow      string
 	Disabled    string
 	MenuBg      string
+	Success     string
+	Failure     string
+
+	// Style attributes
+	TitleBold       bool
+	SelectedReverse bool
+	HotkeyUnderline bool
+	SeparatorDim    bool
+
+	// Display toggles
+	NoShadow    bool // skip drop shadows
+	Transparent bool // use the terminal's default background
+
+	// Icons are drawn before the labels of items of each type that set no
+	// icon of their own
+	Icons map[string]string
+}
+
+// DefaultTheme returns the built-in VGA-style color scheme
+func DefaultTheme() Theme {
+	return Theme{
+		background:  tcell.ColorBlue,
+		text:        tcell.Color250, // Light gray
+		border:      tcell.ColorAqua,
+		highlightBg: tcell.ColorBlue,
+		highlightFg: tcell.ColorWhite,
+		hotkey:      tcell.ColorYellow,
+		shadow:      tcell.Color240, // Dark gray for shadow
+		disabled:    tcell.Color240,
+		menuBg:      tcell.ColorNavy,
+		success:     tcell.ColorLime,
+		failure:     tcell.ColorRed,
+	}
 }
 
-// ApplyTheme updates the global color variables with the provided theme
-// colorParser is a function that converts a color name to tcell.Color
-func ApplyTheme(theme ThemeColors, colorParser func(string) (tcell.Color, bool)) {
+// NewTheme builds a Theme from color names, falling back to the default color for
+// any name colorParser rejects. colorParser converts a color name to tcell.Color.
+func NewTheme(colors ThemeColors, colorParser func(string) (tcell.Color, bool)) Theme {
+	defaults := DefaultTheme()
+
 	// Helper to apply color or keep default
 	applyColor := func(colorName string, defaultColor tcell.Color) tcell.Color {
 		if color, valid := colorParser(colorName); valid {
@@ -141,134 +367,376 @@ func ApplyTheme(theme ThemeColors, colorParser func(string) (tcell.Color, bool))
 		}
 		return defaultColor
 	}
-	
-	// Update theme-specific colors
-	colorBackground = applyColor(theme.Background, tcell.ColorBlue)
-	colorText = applyColor(theme.Text, tcell.Color250)
-	colorBorder = applyColor(theme.Border, tcell.ColorAqua)
-	colorHighlightBg = applyColor(theme.HighlightBg, tcell.ColorBlue)
-	colorHighlightFg = applyColor(theme.HighlightFg, tcell.ColorWhite)
-	colorHotkey = applyColor(theme.Hotkey, tcell.ColorYellow)
-	colorShadow = applyColor(theme.Shadow, tcell.Color240)
-	colorDisabled = applyColor(theme.Disabled, tcell.Color240)
-	if theme.MenuBg != "" {
-		colorMenuBg = applyColor(theme.MenuBg, tcell.ColorNavy)
+
+	t := Theme{
+		background:  applyColor(colors.Background, defaults.background),
+		text:        applyColor(colors.Text, defaults.text),
+		border:      applyColor(colors.Border, defaults.border),
+		highlightBg: applyColor(colors.HighlightBg, defaults.highlightBg),
+		highlightFg: applyColor(colors.HighlightFg, defaults.highlightFg),
+		hotkey:      applyColor(colors.Hotkey, defaults.hotkey),
+		shadow:      applyColor(colors.Shadow, defaults.shadow),
+		disabled:    applyColor(colors.Disabled, defaults.disabled),
+		success:     applyColor(colors.Success, defaults.success),
+		failure:     applyColor(colors.Failure, defaults.failure),
+
+		titleBold:       colors.TitleBold,
+		selectedReverse: colors.SelectedReverse,
+		hotkeyUnderline: colors.HotkeyUnderline,
+		separatorDim:    colors.SeparatorDim,
+
+		noShadow:    colors.NoShadow,
+		transparent: colors.Transparent,
+
+		icons: colors.Icons,
+	}
+	if colors.Transparent {
+		t.background = tcell.ColorDefault
+	}
+	if colors.MenuBg != "" {
+		t.menuBg = applyColor(colors.MenuBg, defaults.menuBg)
 	} else {
-		colorMenuBg = colorBackground
+		t.menuBg = t.background
 	}
-	
-	// Update legacy color variables for backwards compatibility
-	darkBlue = colorBackground
-	brightCyan = colorBorder
-	white = colorHighlightFg
-	lightGray = colorText
-	darkGray = colorShadow
-	brightYellow = colorHotkey
+	return t
+}
+
+// Theme returns the screen's current theme
+func (s *Screen) Theme() *Theme {
+	return &s.theme
+}
+
+// SetTheme replaces the screen's theme and updates its default style
+func (s *Screen) SetTheme(t Theme) {
+	s.theme = t
+	s.RefreshTheme()
+}
+
+// ApplyTheme builds a Theme from color names and makes it the screen's theme
+func (s *Screen) ApplyTheme(colors ThemeColors, colorParser func(string) (tcell.Color, bool)) {
+	s.SetTheme(NewTheme(colors, colorParser))
 }
 
 // defaultStyle returns the default style (uses theme colors)
-func defaultStyle() tcell.Style {
+func (t Theme) defaultStyle() tcell.Style {
 	return tcell.StyleDefault.
-		Foreground(colorText).
-		Background(colorBackground)
+		Foreground(t.text).
+		Background(t.background)
 }
 
 // StyleNormal returns the normal style (uses theme colors)
-func StyleNormal() tcell.Style {
+func (t Theme) StyleNormal() tcell.Style {
 	return tcell.StyleDefault.
-		Foreground(colorText).
-		Background(colorBackground)
+		Foreground(t.text).
+		Background(t.background)
 }
 
 // StyleBorder returns the border style (uses theme colors)
-func StyleBorder() tcell.Style {
+func (t Theme) StyleBorder() tcell.Style {
 	return tcell.StyleDefault.
-		Foreground(colorBorder).
-		Background(colorBackground)
+		Foreground(t.border).
+		Background(t.background)
 }
 
 // StyleHighlight returns the highlight style (uses theme colors)
-func StyleHighlight() tcell.Style {
+func (t Theme) StyleHighlight() tcell.Style {
 	return tcell.StyleDefault.
-		Foreground(colorHighlightFg).
-		Background(colorHighlightBg)
+		Foreground(t.highlightFg).
+		Background(t.highlightBg).
+		Reverse(t.selectedReverse)
 }
 
 // StyleShadow returns the shadow style (uses theme colors)
-func StyleShadow() tcell.Style {
+func (t Theme) StyleShadow() tcell.Style {
 	return tcell.StyleDefault.
-		Foreground(colorShadow).
-		Background(colorShadow)
+		Foreground(t.shadow).
+		Background(t.shadow)
 }
 
 // StyleHotkey returns the hotkey style (uses theme colors)
-func StyleHotkey() tcell.Style {
+func (t Theme) StyleHotkey() tcell.Style {
 	return tcell.StyleDefault.
-		Foreground(colorHotkey).
-		Background(colorBackground).
-		Bold(true)
+		Foreground(t.hotkey).
+		Background(t.background).
+		Bold(true).
+		Underline(t.hotkeyUnderline)
 }
 
 // StyleHotkeyHighlight returns the hotkey highlight style (uses theme colors)
-func StyleHotkeyHighlight() tcell.Style {
+func (t Theme) StyleHotkeyHighlight() tcell.Style {
 	return tcell.StyleDefault.
-		Foreground(colorHotkey).
-		Background(colorHighlightBg).
-		Bold(true)
+		Foreground(t.hotkey).
+		Background(t.highlightBg).
+		Bold(true).
+		Underline(t.hotkeyUnderline).
+		Reverse(t.selectedReverse)
 }
 
 // StyleDisabled returns the disabled style (uses theme colors)
-func StyleDisabled() tcell.Style {
+func (t Theme) StyleDisabled() tcell.Style {
 	return tcell.StyleDefault.
-		Foreground(colorDisabled).
-		Background(colorBackground)
+		Foreground(t.disabled).
+		Background(t.background)
 }
 
 // StyleMenuBg returns the menu background style (uses theme colors)
-func StyleMenuBg() tcell.Style {
+func (t Theme) StyleMenuBg() tcell.Style {
 	return tcell.StyleDefault.
-		Foreground(colorMenuBg).
-		Background(colorMenuBg)
+		Foreground(t.menuBg).
+		Background(t.menuBg)
 }
 
 // StyleBorderMenuBg returns border style with menu background
-func StyleBorderMenuBg() tcell.Style {
+func (t Theme) StyleBorderMenuBg() tcell.Style {
 	return tcell.StyleDefault.
-		Foreground(colorBorder).
-		Background(colorMenuBg)
+		Foreground(t.border).
+		Background(t.menuBg)
 }
 
 // StyleTextMenuBg returns text style with menu background
-func StyleTextMenuBg() tcell.Style {
+func (t Theme) StyleTextMenuBg() tcell.Style {
 	return tcell.StyleDefault.
-		Foreground(colorText).
-		Background(colorMenuBg)
+		Foreground(t.text).
+		Background(t.menuBg)
 }
 
 // StyleDisabledMenuBg returns disabled style with menu background
-func StyleDisabledMenuBg() tcell.Style {
+func (t Theme) StyleDisabledMenuBg() tcell.Style {
 	return tcell.StyleDefault.
-		Foreground(colorDisabled).
-		Background(colorMenuBg)
+		Foreground(t.disabled).
+		Background(t.menuBg)
 }
 
 // StyleHotkeyMenuBg returns hotkey style with menu background
-func StyleHotkeyMenuBg() tcell.Style {
+func (t Theme) StyleHotkeyMenuBg() tcell.Style {
 	return tcell.StyleDefault.
-		Foreground(colorHotkey).
-		Background(colorMenuBg).
+		Foreground(t.hotkey).
+		Background(t.menuBg).
+		Bold(true).
+		Underline(t.hotkeyUnderline)
+}
+
+// StyleStatus returns the style for a command result on the normal background
+func (t Theme) StyleStatus(ok bool) tcell.Style {
+	color := t.failure
+	if ok {
+		color = t.success
+	}
+	return tcell.StyleDefault.
+		Foreground(color).
+		Background(t.background).
 		Bold(true)
 }
 
+// StyleSeparator returns the separator line style with menu background
+func (t Theme) StyleSeparator() tcell.Style {
+	return t.StyleBorderMenuBg().Dim(t.separatorDim)
+}
+
+// StyleTitle returns the style for a border title drawn in borderStyle
+func (t Theme) StyleTitle(borderStyle tcell.Style) tcell.Style {
+	if t.titleBold {
+		return borderStyle.Bold(true)
+	}
+	return borderStyle
+}
+
+// Package-level style wrappers kept for compatibility. They always use the
+// default colors; use Screen.Theme() to get styles for the active theme.
+
+// StyleNormal returns the default theme's normal style
+func StyleNormal() tcell.Style { return defaultTheme.StyleNormal() }
+
+// StyleBorder returns the default theme's border style
+func StyleBorder() tcell.Style { return defaultTheme.StyleBorder() }
+
+// StyleHighlight returns the default theme's highlight style
+func StyleHighlight() tcell.Style { return defaultTheme.StyleHighlight() }
+
+// StyleShadow returns the default theme's shadow style
+func StyleShadow() tcell.Style { return defaultTheme.StyleShadow() }
+
+// StyleHotkey returns the default theme's hotkey style
+func StyleHotkey() tcell.Style { return defaultTheme.StyleHotkey() }
+
+// StyleHotkeyHighlight returns the default theme's hotkey highlight style
+func StyleHotkeyHighlight() tcell.Style { return defaultTheme.StyleHotkeyHighlight() }
+
+// StyleDisabled returns the default theme's disabled style
+func StyleDisabled() tcell.Style { return defaultTheme.StyleDisabled() }
+
+// StyleMenuBg returns the default theme's menu background style
+func StyleMenuBg() tcell.Style { return defaultTheme.StyleMenuBg() }
+
+// StyleBorderMenuBg returns the default theme's border style with menu background
+func StyleBorderMenuBg() tcell.Style { return defaultTheme.StyleBorderMenuBg() }
+
+// StyleTextMenuBg returns the default theme's text style with menu background
+func StyleTextMenuBg() tcell.Style { return defaultTheme.StyleTextMenuBg() }
+
+// StyleDisabledMenuBg returns the default theme's disabled style with menu background
+func StyleDisabledMenuBg() tcell.Style { return defaultTheme.StyleDisabledMenuBg() }
+
+// StyleHotkeyMenuBg returns the default theme's hotkey style with menu background
+func StyleHotkeyMenuBg() tcell.Style { return defaultTheme.StyleHotkeyMenuBg() }
+
+// defaultTheme backs the package-level style wrappers; it is never modified
+var defaultTheme = DefaultTheme()
+
 // FormatDate returns current date in DD/MM/YY format
 func FormatDate() string {
 	now := time.Now()
 	return now.Format("02/01/06")
 }
 
+// TitleBar describes the header line drawn inside the menu title bar
+type TitleBar struct {
+	Text       string // label after the date
+	HideDate   bool
+	HideClock  bool
+	DateLayout string // Go time layout; empty uses FormatDate's layout
+	TimeLayout string // Go time layout; empty uses FormatTime's 12-hour style
+	Identity   string // user and/or host shown before the clock
+	Badge      string // short notice shown first on the right, e.g. "Update available"
+}
+
+// DefaultTitleBar returns the classic "date  Menu Works  time" header
+func DefaultTitleBar() TitleBar {
+	return TitleBar{Text: "Menu Works"}
+}
+
+// Format returns the left and right header text for the given time
+func (tb TitleBar) Format(now time.Time) (left, right string) {
+	var parts []string
+	if !tb.HideDate {
+		if tb.DateLayout != "" {
+			parts = append(parts, now.Format(tb.DateLayout))
+		} else {
+			parts = append(parts, now.Format("02/01/06"))
+		}
+	}
+	if tb.Text != "" {
+		parts = append(parts, tb.Text)
+	}
+	left = strings.Join(parts, "     ") // 5 spaces
+
+	parts = nil
+	if tb.Badge != "" {
+		parts = append(parts, tb.Badge)
+	}
+	if tb.Identity != "" {
+		parts = append(parts, tb.Identity)
+	}
+	if !tb.HideClock {
+		if tb.TimeLayout != "" {
+			parts = append(parts, now.Format(tb.TimeLayout))
+		} else {
+			parts = append(parts, formatClock(now))
+		}
+	}
+	right = strings.Join(parts, "  ")
+	return left, right
+}
+
+// KeyHint is one key binding advertised in the menu footer
+type KeyHint struct {
+	Key    string
+	Action string
+	Press  *tcell.EventKey // sent when the hint is clicked; nil if it can't be
+}
+
+// footerHit is where a clickable KeyHint was drawn
+type footerHit struct {
+	x0, x1, y int // columns x0 up to, not including, x1
+	press     *tcell.EventKey
+}
+
+// FooterKeyAt returns the key event of the footer hint drawn at x, y, or nil
+// if there is no clickable hint there
+func (s *Screen) FooterKeyAt(x, y int) *tcell.EventKey {
+	for _, hit := range s.footerHits {
+		if y == hit.y && x >= hit.x0 && x < hit.x1 {
+			return hit.press
+		}
+	}
+	return nil
+}
+
+// CloseButtonAt reports whether x, y is on the header's close button
+func (s *Screen) CloseButtonAt(x, y int) bool {
+	return s.closeX >= 0 && y == s.closeY && x >= s.closeX && x < s.closeX+StringWidth(closeButton)
+}
+
+// FormatFooter joins an optional hint and key bindings as "hint | KEY: Action | ..."
+func FormatFooter(hint string, keys []KeyHint) string {
+	var parts []string
+	if hint != "" {
+		parts = append(parts, hint)
+	}
+	for _, k := range keys {
+		parts = append(parts, k.Key+": "+k.Action)
+	}
+	return strings.Join(parts, footerSeparator)
+}
+
+// footerSeparator separates the parts of the footer
+const footerSeparator = " | "
+
+// SetFooter sets the key bindings shown under the menu; visible=false hides the footer
+func (s *Screen) SetFooter(keys []KeyHint, visible bool) {
+	s.footerKeys = keys
+	s.footerOff = !visible
+}
+
+// SetTransition draws the menu's items partly hidden, progress (0 to 1) of
+// the way through a wipe; back wipes from the right. 1 shows the whole menu.
+func (s *Screen) SetTransition(progress float64, back bool) {
+	s.transition = progress
+	s.transitionBack = back
+}
+
+// SetBigTitle sets text to draw in the block font above the menu (onMenu)
+// and on the splash screen (onSplash), where it fits
+func (s *Screen) SetBigTitle(text string, onMenu, onSplash bool) {
+	s.bigTitle = text
+	s.bigTitleMenu = onMenu
+	s.bigTitleSplash = onSplash
+}
+
+// SetPendingKey shows key under the menu as the start of a hotkey sequence;
+// "" hides it
+func (s *Screen) SetPendingKey(key string) {
+	s.pendingKey = key
+}
+
+// SetItemBadges sets the badges of the current menu's items by their Key,
+// replacing the badge text in their config; nil leaves every item its own
+func (s *Screen) SetItemBadges(badges map[string]string) {
+	s.itemBadges = badges
+}
+
+// TitleBar returns the screen's title bar settings
+func (s *Screen) TitleBar() TitleBar {
+	return s.titleBar
+}
+
+// SetTitleBar replaces the screen's title bar settings
+func (s *Screen) SetTitleBar(tb TitleBar) {
+	s.titleBar = tb
+}
+
+// SetBadge sets a notice shown in the title bar regardless of its settings; empty clears it
+func (s *Screen) SetBadge(text string) {
+	s.badge = text
+}
+
 // FormatTime returns current time in H:MM AM/PM format (uppercase, no leading zero on hour)
 func FormatTime() string {
-	now := time.Now()
+	return formatClock(time.Now())
+}
+
+// formatClock formats t in H:MM AM/PM format
+func formatClock(now time.Time) string {
 	hour := now.Hour()
 	minute := now.Minute()
 	ampm := "AM"
@@ -295,6 +763,9 @@ func (s *Screen) DrawBoxChar(x, y int, ch rune, style tcell.Style) {
 	if x >= w || y >= h {
 		return
 	}
+	if s.access.Plain {
+		ch = ' '
+	}
 	s.SetCellUnsafe(x, y, ch, style)
 }
 
@@ -328,7 +799,9 @@ func (s *Screen) DrawChar(x, y int, ch rune, style tcell.Style) {
 	s.SetCellUnsafe(x, y, ch, style)
 }
 
-// DrawString draws a string starting at (x, y) with style, truncating if needed
+// DrawString draws a string starting at (x, y) with style, truncating if needed.
+// Wide characters take two columns and combining characters attach to the
+// preceding character. Returns the number of columns written.
 func (s *Screen) DrawString(x, y int, text string, style tcell.Style) int {
 	w, h := s.Size()
 	if y < 0 || y >= h || x >= w {
@@ -336,28 +809,60 @@ func (s *Screen) DrawString(x, y int, text string, style tcell.Style) int {
 	}
 
 	colsWritten := 0
+	var mainc rune
+	var combc []rune
+	mainWidth := 0
+	flush := func() bool {
+		if mainWidth == 0 {
+			return true
+		}
+		if x+colsWritten+mainWidth > w {
+			return false
+		}
+		if x+colsWritten >= 0 {
+			s.tcellScreen.SetContent(x+colsWritten, y, mainc, combc, style)
+		}
+		colsWritten += mainWidth
+		return true
+	}
+
 	for _, ch := range text {
-		if x+colsWritten >= w {
-			break
+		rw := runewidth.RuneWidth(ch)
+		if rw == 0 && mainWidth > 0 {
+			// Combining mark: attach to the pending character
+			combc = append(combc, ch)
+			continue
 		}
-		s.DrawChar(x+colsWritten, y, ch, style)
-		colsWritten++
+		if rw == 0 {
+			// Zero-width character with nothing to attach to
+			continue
+		}
+		if !flush() {
+			return colsWritten
+		}
+		mainc, combc, mainWidth = ch, nil, rw
 	}
+	flush()
 	return colsWritten
 }
 
-// TruncateString truncates a string to fit within maxWidth, adding ellipsis if needed
+// StringWidth returns the number of terminal columns text occupies
+func StringWidth(text string) int {
+	return runewidth.StringWidth(text)
+}
+
+// TruncateString truncates a string to fit within maxWidth columns, adding ellipsis if needed
 func TruncateString(text string, maxWidth int) string {
-	if len(text) <= maxWidth {
+	if StringWidth(text) <= maxWidth {
 		return text
 	}
 	if maxWidth <= 0 {
 		return ""
 	}
 	if maxWidth < 3 {
-		return text[:maxWidth]
+		return runewidth.Truncate(text, maxWidth, "")
 	}
-	return text[:maxWidth-1] + "…"
+	return runewidth.Truncate(text, maxWidth, "…")
 }
 
 // HighlightHotkey returns the label with hotkey highlighted using ANSI-like markers
@@ -401,12 +906,15 @@ func ParseHotkeyLabel(label, hotkey string) []HotkeylabelSegment {
 
 // DrawBorder draws a double-line border box with optional title using default border style
 func (s *Screen) DrawBorder(x, y, width, height int, title string) {
-	s.DrawBorderWithStyle(x, y, width, height, title, StyleBorder())
+	s.DrawBorderWithStyle(x, y, width, height, title, s.theme.StyleBorder())
 }
 
 // DrawBorderWithStyle draws a double-line border box with optional title and custom style
 func (s *Screen) DrawBorderWithStyle(x, y, width, height int, title string, borderStyle tcell.Style) {
 	w, h := s.Size()
+	if s.flash {
+		borderStyle = s.theme.StyleHighlight()
+	}
 
 	// Ensure bounds
 	if x < 0 || y < 0 || width <= 0 || height <= 0 {
@@ -460,16 +968,12 @@ func (s *Screen) DrawBorderWithStyle(x, y, width, height int, title string, bord
 	// Draw title if provided
 	if title != "" {
 		titleX := x + 2
-		titleLen := len(title)
-		if titleLen > width-4 {
-			titleLen = width - 4
-			title = TruncateString(title, titleLen)
+		if StringWidth(title) > width-4 {
+			title = TruncateString(title, width-4)
 		}
 
-		for i, ch := range title {
-			if titleX+i < w && y < h {
-				s.DrawBoxChar(titleX+i, y, ch, borderStyle)
-			}
+		if y < h {
+			s.DrawString(titleX, y, title, s.theme.StyleTitle(borderStyle))
 		}
 	}
 }
@@ -477,13 +981,16 @@ func (s *Screen) DrawBorderWithStyle(x, y, width, height int, title string, bord
 // DrawShadow draws a drop shadow effect (space char with dark gray background)
 // Shadows are +1 row, +2 columns offset and clipped at terminal boundaries
 func (s *Screen) DrawShadow(x, y, width, height int) {
+	if s.theme.noShadow || s.access.Plain {
+		return
+	}
 	w, h := s.Size()
 
 	// Right edge shadow
 	shadowX := x + width + 1
 	for j := y + 1; j < y+height+1; j++ {
 		if shadowX < w && j < h {
-			s.DrawChar(shadowX, j, shadowChar, StyleShadow())
+			s.DrawChar(shadowX, j, shadowChar, s.theme.StyleShadow())
 		}
 	}
 
@@ -491,19 +998,19 @@ func (s *Screen) DrawShadow(x, y, width, height int) {
 	shadowY := y + height
 	for i := x + 2; i < x+width+2; i++ {
 		if i < w && shadowY < h {
-			s.DrawChar(i, shadowY, shadowChar, StyleShadow())
+			s.DrawChar(i, shadowY, shadowChar, s.theme.StyleShadow())
 		}
 	}
 
 	// Corner shadow
 	if shadowX < w && shadowY < h {
-		s.DrawChar(shadowX, shadowY, shadowChar, StyleShadow())
+		s.DrawChar(shadowX, shadowY, shadowChar, s.theme.StyleShadow())
 	}
 }
 
 // ClearRect clears a rectangular area
 func (s *Screen) ClearRect(x, y, width, height int) {
-	s.ClearRectWithStyle(x, y, width, height, StyleNormal())
+	s.ClearRectWithStyle(x, y, width, height, s.theme.StyleNormal())
 }
 
 // ClearRectWithStyle clears a rectangular area with a specific style
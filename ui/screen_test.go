@@ -0,0 +1,746 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+
+	"github.com/benworks/menuworks/config"
+	"github.com/benworks/menuworks/menu"
+)
+
+func testColorParser(name string) (tcell.Color, bool) {
+	switch name {
+	case "red":
+		return tcell.ColorRed, true
+	case "green":
+		return tcell.ColorGreen, true
+	}
+	return tcell.ColorDefault, false
+}
+
+func TestNewThemeFallsBackToDefaults(t *testing.T) {
+	theme := NewTheme(ThemeColors{Text: "red", Border: "bogus"}, testColorParser)
+	defaults := DefaultTheme()
+
+	if fg, _, _ := theme.StyleNormal().Decompose(); fg != tcell.ColorRed {
+		t.Errorf("expected text color red, got %v", fg)
+	}
+	if fg, _, _ := theme.StyleBorder().Decompose(); fg != defaults.border {
+		t.Errorf("expected invalid border to fall back to default, got %v", fg)
+	}
+	// menu_bg falls back to the background color when omitted
+	if _, bg, _ := theme.StyleMenuBg().Decompose(); bg != theme.background {
+		t.Errorf("expected menu background to match background, got %v", bg)
+	}
+}
+
+func TestScreensHaveIndependentThemes(t *testing.T) {
+	a := &Screen{tcellScreen: tcell.NewSimulationScreen(""), theme: DefaultTheme()}
+	b := &Screen{tcellScreen: tcell.NewSimulationScreen(""), theme: DefaultTheme()}
+
+	a.ApplyTheme(ThemeColors{Text: "red"}, testColorParser)
+	b.ApplyTheme(ThemeColors{Text: "green"}, testColorParser)
+
+	if fg, _, _ := a.Theme().StyleNormal().Decompose(); fg != tcell.ColorRed {
+		t.Errorf("expected screen a text red, got %v", fg)
+	}
+	if fg, _, _ := b.Theme().StyleNormal().Decompose(); fg != tcell.ColorGreen {
+		t.Errorf("expected screen b text green, got %v", fg)
+	}
+	if StyleNormal() != DefaultTheme().StyleNormal() {
+		t.Errorf("expected package-level wrapper to keep default colors")
+	}
+}
+
+func TestThemeStyleAttributes(t *testing.T) {
+	plain := DefaultTheme()
+	if _, _, attrs := plain.StyleHotkey().Decompose(); attrs&tcell.AttrUnderline != 0 {
+		t.Errorf("expected no hotkey underline by default")
+	}
+
+	theme := NewTheme(ThemeColors{TitleBold: true, SelectedReverse: true, HotkeyUnderline: true, SeparatorDim: true}, testColorParser)
+	if _, _, attrs := theme.StyleTitle(theme.StyleBorder()).Decompose(); attrs&tcell.AttrBold == 0 {
+		t.Errorf("expected bold title")
+	}
+	if _, _, attrs := theme.StyleHighlight().Decompose(); attrs&tcell.AttrReverse == 0 {
+		t.Errorf("expected reversed selection")
+	}
+	if _, _, attrs := theme.StyleHotkeyMenuBg().Decompose(); attrs&tcell.AttrUnderline == 0 {
+		t.Errorf("expected underlined hotkey")
+	}
+	if _, _, attrs := theme.StyleSeparator().Decompose(); attrs&tcell.AttrDim == 0 {
+		t.Errorf("expected dim separator")
+	}
+}
+
+func TestTransparentThemeAndNoShadow(t *testing.T) {
+	theme := NewTheme(ThemeColors{Background: "red", Transparent: true, NoShadow: true}, testColorParser)
+	if _, bg, _ := theme.StyleNormal().Decompose(); bg != tcell.ColorDefault {
+		t.Errorf("expected transparent background, got %v", bg)
+	}
+	if _, bg, _ := theme.StyleMenuBg().Decompose(); bg != tcell.ColorDefault {
+		t.Errorf("expected menu background to follow transparent background, got %v", bg)
+	}
+
+	sim := tcell.NewSimulationScreen("")
+	if err := sim.Init(); err != nil {
+		t.Fatalf("init simulation screen: %v", err)
+	}
+	defer sim.Fini()
+	sim.SetSize(20, 10)
+	s := &Screen{tcellScreen: sim, theme: theme}
+	s.DrawShadow(0, 0, 5, 3)
+	if _, _, style, _ := sim.GetContent(6, 1); style == theme.StyleShadow() {
+		t.Errorf("expected no shadow to be drawn")
+	}
+}
+
+func TestTitleBarFormat(t *testing.T) {
+	now := time.Date(2026, 3, 7, 14, 5, 0, 0, time.UTC)
+
+	left, right := DefaultTitleBar().Format(now)
+	if left != "07/03/26     Menu Works" || right != "2:05 PM" {
+		t.Errorf("unexpected default header: %q / %q", left, right)
+	}
+
+	tb := TitleBar{Text: "Ops", DateLayout: "2006-01-02", TimeLayout: "15:04", Identity: "ben@box"}
+	left, right = tb.Format(now)
+	if left != "2026-03-07     Ops" || right != "ben@box  14:05" {
+		t.Errorf("unexpected custom header: %q / %q", left, right)
+	}
+
+	left, right = TitleBar{Text: "Ops", HideDate: true, HideClock: true}.Format(now)
+	if left != "Ops" || right != "" {
+		t.Errorf("expected date and clock hidden, got %q / %q", left, right)
+	}
+
+	tb.Badge = "Update available"
+	if _, right = tb.Format(now); right != "Update available  ben@box  14:05" {
+		t.Errorf("expected badge before identity, got %q", right)
+	}
+}
+
+func TestFormatFooter(t *testing.T) {
+	keys := []KeyHint{{Key: "ENTER", Action: "Select"}, {Key: "ESC", Action: "Back"}}
+	if got := FormatFooter("", keys); got != "ENTER: Select | ESC: Back" {
+		t.Errorf("unexpected footer: %q", got)
+	}
+	if got := FormatFooter("Pick one", keys); got != "Pick one | ENTER: Select | ESC: Back" {
+		t.Errorf("unexpected footer with hint: %q", got)
+	}
+	if got := FormatFooter("", nil); got != "" {
+		t.Errorf("expected empty footer, got %q", got)
+	}
+}
+
+func TestLayoutMenuRect(t *testing.T) {
+	tests := []struct {
+		name                       string
+		layout                     Layout
+		wantX, wantY, wantW, wantH int
+	}{
+		{"default centered", DefaultLayout(), 10, 3, 60, 18},
+		{"full screen", Layout{FullWidth: true, FullHeight: true, Align: "center"}, 1, 1, 78, 23},
+		{"top-left custom", Layout{Width: 40, Height: 10, Align: "top-left"}, 0, 0, 40, 10},
+		{"clamped to terminal", Layout{Width: 200, Height: 100}, 1, 1, 78, 23},
+		{"minimum size", Layout{Width: 5, Height: 2}, 30, 9, 20, 6},
+	}
+	for _, tt := range tests {
+		x, y, w, h := tt.layout.MenuRect(80, 25)
+		if x != tt.wantX || y != tt.wantY || w != tt.wantW || h != tt.wantH {
+			t.Errorf("%s: got (%d,%d %dx%d), want (%d,%d %dx%d)", tt.name, x, y, w, h, tt.wantX, tt.wantY, tt.wantW, tt.wantH)
+		}
+	}
+}
+
+func newTestScreen(t testing.TB, w, h int) (*Screen, tcell.SimulationScreen) {
+	t.Helper()
+	s, sim, err := NewSimulationScreen(w, h)
+	if err != nil {
+		t.Fatalf("init simulation screen: %v", err)
+	}
+	t.Cleanup(s.Close)
+	return s, sim
+}
+
+// rowText returns the characters on row y of the simulation screen
+func rowText(sim tcell.SimulationScreen, y int) string {
+	cells, w, _ := sim.GetContents()
+	var b strings.Builder
+	for x := 0; x < w; x++ {
+		b.WriteString(string(cells[y*w+x].Runes))
+	}
+	return b.String()
+}
+
+func TestDrawMenuHeadless(t *testing.T) {
+	s, sim := newTestScreen(t, 80, 25)
+	s.SetTitleBar(TitleBar{Text: "Test Menu", HideDate: true, HideClock: true})
+	cfg := &config.Config{
+		Title: "Main",
+		Items: []config.MenuItem{
+			{Type: "command", Label: "Status", Exec: config.ExecConfig{Windows: "echo", Linux: "echo", Mac: "echo"}},
+			{Type: "separator"},
+			{Type: "back", Label: "Exit"},
+		},
+	}
+
+	s.DrawMenu(menu.NewNavigator(cfg))
+	s.Show()
+
+	var screenText []string
+	_, h := sim.Size()
+	for y := 0; y < h; y++ {
+		screenText = append(screenText, rowText(sim, y))
+	}
+	all := strings.Join(screenText, "\n")
+	for _, want := range []string{"Main", "Test Menu", "Status", "Exit"} {
+		if !strings.Contains(all, want) {
+			t.Errorf("expected %q on screen, got:\n%s", want, all)
+		}
+	}
+}
+
+func TestTransitionWipe(t *testing.T) {
+	s, sim := newTestScreen(t, 80, 25)
+	cfg := &config.Config{
+		Title: "Main",
+		Items: []config.MenuItem{{Type: "back", Label: "Status"}},
+	}
+	nav := menu.NewNavigator(cfg)
+	shown := func() bool {
+		s.DrawMenu(nav)
+		_, h := sim.Size()
+		for y := 0; y < h; y++ {
+			if strings.Contains(rowText(sim, y), "Status") {
+				return true
+			}
+		}
+		return false
+	}
+
+	// Entering reveals from the left, leaving from the right
+	s.SetTransition(0.5, false)
+	if !shown() {
+		t.Error("expected the left half of the items to be revealed first when entering")
+	}
+	s.SetTransition(0.5, true)
+	if shown() {
+		t.Error("expected the left half of the items to be hidden until last when leaving")
+	}
+	s.SetTransition(1, false)
+	if !shown() {
+		t.Error("expected a finished transition to show every item")
+	}
+}
+
+func TestDrawMenuScrollsToSelection(t *testing.T) {
+	s, sim := newTestScreen(t, 80, 25)
+	s.SetTitleBar(TitleBar{Text: "Long", HideDate: true, HideClock: true})
+	cfg := &config.Config{Title: "Main"}
+	for i := 1; i <= 40; i++ {
+		cfg.Items = append(cfg.Items, config.MenuItem{Type: "command", Label: fmt.Sprintf("Item %02d", i), Exec: config.ExecConfig{Windows: "echo", Linux: "echo", Mac: "echo"}})
+	}
+	navigator := menu.NewNavigator(cfg)
+
+	screenText := func() string {
+		s.DrawMenu(navigator)
+		var rows []string
+		_, h := sim.Size()
+		for y := 0; y < h; y++ {
+			rows = append(rows, rowText(sim, y))
+		}
+		return strings.Join(rows, "\n")
+	}
+
+	navigator.SelectLast()
+	all := screenText()
+	if !strings.Contains(all, "Item 40") || strings.Contains(all, "Item 01") {
+		t.Errorf("expected the window scrolled to the last item, got:\n%s", all)
+	}
+	if !strings.Contains(all, "▲") || strings.Contains(all, "▼") {
+		t.Errorf("expected only the up indicator at the bottom of the menu, got:\n%s", all)
+	}
+
+	navigator.PageUp(s.MenuPageSize())
+	all = screenText()
+	if !strings.Contains(all, "▲") || !strings.Contains(all, "▼") {
+		t.Errorf("expected both indicators mid-menu, got:\n%s", all)
+	}
+
+	navigator.SelectFirst()
+	if all = screenText(); !strings.Contains(all, "Item 01") {
+		t.Errorf("expected the window back at the top, got:\n%s", all)
+	}
+}
+
+func TestDisabledNotes(t *testing.T) {
+	s, sim := newTestScreen(t, 80, 25)
+	// A window that opens in two hours, so Games is never available now
+	now := time.Now()
+	later := now.Add(2*time.Hour).Format("15:04") + "-" + now.Add(3*time.Hour).Format("15:04")
+	cfg := &config.Config{
+		Title: "Main",
+		Items: []config.MenuItem{
+			{Type: "command", Label: "Status", Exec: config.ExecConfig{Windows: "echo", Linux: "echo", Mac: "echo"}},
+			{Type: "submenu", Label: "Tools", Target: "missing"},
+			{Type: "command", Label: "Games", Exec: config.ExecConfig{Windows: "echo", Linux: "echo", Mac: "echo"}, AvailableHours: later},
+		},
+	}
+	navigator := menu.NewNavigator(cfg)
+	_, y, _, _ := s.layout.MenuRect(s.Size())
+	toolsRow := y + 4
+
+	s.DrawMenu(navigator)
+	if got := rowText(sim, toolsRow); strings.Contains(got, "(menu not found)") {
+		t.Errorf("expected no note by default, got %q", got)
+	}
+
+	s.SetDisabledNotes(map[string]string{menu.DisabledMissingTarget: "(menu not found)"})
+	s.DrawMenu(navigator)
+	if got := rowText(sim, toolsRow); !strings.Contains(got, "Tools (menu not found)") {
+		t.Errorf("expected the reason after the disabled item, got %q", got)
+	}
+	if got, want := rowText(sim, toolsRow+1), "Games (available "+later+")"; !strings.Contains(got, want) {
+		t.Errorf("expected %q after the item outside its hours, got %q", want, got)
+	}
+}
+
+func TestStringWidthAndTruncate(t *testing.T) {
+	tests := []struct {
+		text  string
+		width int
+	}{
+		{"Menu", 4},
+		{"日本語", 6},
+		{"e\u0301te\u0301", 3}, // combining accents take no column
+		{"🎮 Games", 8},
+	}
+	for _, tt := range tests {
+		if got := StringWidth(tt.text); got != tt.width {
+			t.Errorf("StringWidth(%q) = %d, want %d", tt.text, got, tt.width)
+		}
+	}
+
+	if got := TruncateString("日本語テキスト", 7); got != "日本語…" {
+		t.Errorf("expected wide truncation to fit 7 columns, got %q", got)
+	}
+	if got := TruncateString("日本語", 6); got != "日本語" {
+		t.Errorf("expected exact fit to be unchanged, got %q", got)
+	}
+	if got := StringWidth(TruncateString("e\u0301e\u0301e\u0301e\u0301", 3)); got > 3 {
+		t.Errorf("expected combining truncation within 3 columns, got width %d", got)
+	}
+}
+
+func TestDrawStringWideAndCombining(t *testing.T) {
+	s, sim := newTestScreen(t, 10, 2)
+
+	if cols := s.DrawString(0, 0, "日本x", StyleNormal()); cols != 5 {
+		t.Errorf("expected 5 columns written, got %d", cols)
+	}
+	if mainc, _, _, width := sim.GetContent(2, 0); mainc != '本' || width != 2 {
+		t.Errorf("expected '本' at column 2 with width 2, got %q width %d", mainc, width)
+	}
+	if mainc, _, _, _ := sim.GetContent(4, 0); mainc != 'x' {
+		t.Errorf("expected 'x' at column 4, got %q", mainc)
+	}
+
+	if cols := s.DrawString(0, 1, "e\u0301a", StyleNormal()); cols != 2 {
+		t.Errorf("expected combining mark to take no column, got %d", cols)
+	}
+	if mainc, combc, _, _ := sim.GetContent(0, 1); mainc != 'e' || len(combc) != 1 || combc[0] != '\u0301' {
+		t.Errorf("expected e with combining acute, got %q %q", mainc, combc)
+	}
+
+	// A wide character that doesn't fit in the last column is not drawn
+	if cols := s.DrawString(9, 0, "日", StyleNormal()); cols != 0 {
+		t.Errorf("expected clipped wide character, got %d columns", cols)
+	}
+}
+
+func TestWrapTextWide(t *testing.T) {
+	lines := WrapText("日本語 テキスト", 6)
+	for _, line := range lines {
+		if StringWidth(line) > 6 {
+			t.Errorf("line %q exceeds 6 columns", line)
+		}
+	}
+	if len(lines) != 3 || lines[0] != "日本語" || lines[1] != "テキス" || lines[2] != "ト" {
+		t.Errorf("unexpected wrap: %q", lines)
+	}
+}
+
+func TestShowRepaintsOnceAfterResize(t *testing.T) {
+	s, _ := newTestScreen(t, 20, 5)
+
+	s.resized.Store(true)
+	s.Show()
+	if s.resized.Load() {
+		t.Errorf("expected Show to consume the pending resize repaint")
+	}
+
+	s.resized.Store(true)
+	s.Sync()
+	if s.resized.Load() {
+		t.Errorf("expected Sync to clear the pending resize repaint")
+	}
+}
+
+func TestSuspendResumeRepaints(t *testing.T) {
+	s, _ := newTestScreen(t, 20, 5)
+	s.Show()
+
+	if err := s.Suspend(); err != nil {
+		t.Fatalf("Suspend: %v", err)
+	}
+	if err := s.Resume(); err != nil {
+		t.Fatalf("Resume: %v", err)
+	}
+	if !s.resized.Load() {
+		t.Errorf("expected Resume to request a full repaint")
+	}
+}
+
+func TestOutputViewScrollsAndCloses(t *testing.T) {
+	s, _ := newTestScreen(t, 80, 13) // 10 visible lines
+	closed := false
+	v := NewOutputView(s, strings.Repeat("line\n", 30), func() { closed = true })
+
+	v.HandleEvent(tcell.NewEventKey(tcell.KeyPgDn, 0, tcell.ModNone))
+	if v.scrollOffset != 10 {
+		t.Errorf("expected PgDn to scroll one page, got offset %d", v.scrollOffset)
+	}
+	v.HandleEvent(tcell.NewEventKey(tcell.KeyUp, 0, tcell.ModNone))
+	if v.scrollOffset != 9 || closed {
+		t.Errorf("expected Up to scroll without closing, got offset %d closed %v", v.scrollOffset, closed)
+	}
+	v.Draw()
+	v.HandleEvent(tcell.NewEventKey(tcell.KeyRune, 'q', tcell.ModNone))
+	if !closed {
+		t.Errorf("expected other keys to close the viewer")
+	}
+}
+
+func TestDialogViewButtons(t *testing.T) {
+	s, _ := newTestScreen(t, 80, 25)
+	choice := -1
+	d := NewDialogView(s, "Config Error", "Failed", []string{"Retry", "Use Default", "Exit"}, func(c int) { choice = c })
+
+	d.HandleEvent(tcell.NewEventKey(tcell.KeyLeft, 0, tcell.ModNone))
+	d.Draw()
+	d.HandleEvent(tcell.NewEventKey(tcell.KeyEnter, 0, tcell.ModNone))
+	if choice != 2 {
+		t.Errorf("expected Left to wrap to the last button, got %d", choice)
+	}
+
+	closed := false
+	m := NewMessageView(s, "Done", "Finished", func() { closed = true })
+	m.HandleEvent(tcell.NewEventKey(tcell.KeyRune, 'x', tcell.ModNone))
+	if !closed {
+		t.Errorf("expected message view to close on any key")
+	}
+}
+
+func TestDialogViewCode(t *testing.T) {
+	s, sim := newTestScreen(t, 80, 25)
+	code := []string{"  1 | items:", "> 2 |     - type: x", "    |       ^", "  3 | " + strings.Repeat("y", 100)}
+	d := NewDialogView(s, "Config Error", "Failed to load configuration.", []string{"OK"}, func(int) {}).WithCode(code).WithSize(60, 14)
+	d.Draw()
+
+	// The dialog is centered: 60x14 on 80x25 starts at column 10, row 5, and
+	// its text at column 12. The message takes row 7, then a blank row.
+	inside := func(y int) string { return string([]rune(rowText(sim, y))[12:68]) }
+	for i, want := range code[:3] {
+		if got := strings.TrimRight(inside(9+i), " "); got != want {
+			t.Errorf("row %d = %q, expected %q", i, got, want)
+		}
+	}
+	if got := inside(12); !strings.HasSuffix(got, "y…") {
+		t.Errorf("expected a long line cut to the dialog, got %q", got)
+	}
+}
+
+func TestSetClipboardWithoutTerminal(t *testing.T) {
+	s, _ := newTestScreen(t, 80, 25)
+	if s.SetClipboard("ls") {
+		t.Errorf("expected no clipboard sequence without a terminal")
+	}
+}
+
+func TestInputViewEditsAndSubmits(t *testing.T) {
+	s, sim := newTestScreen(t, 80, 25)
+	submitted := "unset"
+	v := NewInputView(s, "Input", func(text string) { submitted = text }, func() {})
+
+	for _, r := range "ab" {
+		v.HandleEvent(tcell.NewEventKey(tcell.KeyRune, r, tcell.ModNone))
+	}
+	v.HandleEvent(tcell.NewEventKey(tcell.KeyBackspace2, 0, tcell.ModNone))
+	v.HandleEvent(tcell.NewEventKey(tcell.KeyEnter, 0, tcell.ModNone))
+	v.HandleEvent(tcell.NewEventKey(tcell.KeyRune, 'c', tcell.ModNone))
+	v.Draw()
+	if !strings.Contains(rowText(sim, 6), "a") || !strings.Contains(rowText(sim, 7), "c") {
+		t.Errorf("expected both lines in the box, got %q and %q", rowText(sim, 6), rowText(sim, 7))
+	}
+
+	v.HandleEvent(tcell.NewEventKey(tcell.KeyCtrlD, 0, tcell.ModNone))
+	if submitted != "a\nc" {
+		t.Errorf("expected Ctrl+D to submit %q, got %q", "a\nc", submitted)
+	}
+
+	cancelled := false
+	c := NewInputView(s, "Input", func(string) { t.Errorf("expected ESC not to submit") }, func() { cancelled = true })
+	c.HandleEvent(tcell.NewEventKey(tcell.KeyEscape, 0, tcell.ModNone))
+	if !cancelled {
+		t.Errorf("expected ESC to cancel")
+	}
+}
+
+func TestFileViewBrowsesAndPicks(t *testing.T) {
+	root := t.TempDir()
+	for _, name := range []string{"Zelda.nes", "notes.txt", ".hidden.nes", filepath.Join("snes", "Star Fox.sfc")} {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, nil, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	roms := func(name string) bool { return !strings.HasSuffix(name, ".txt") }
+	key := func(v *FileView, k tcell.Key, r rune) { v.HandleEvent(tcell.NewEventKey(k, r, tcell.ModNone)) }
+
+	s, sim := newTestScreen(t, 80, 25)
+	picked := ""
+	v := NewFileView(s, "ROMs", root, roms, func(path string) { picked = path }, func() {})
+	v.Draw()
+	if row := rowText(sim, 4); !strings.Contains(row, "snes/") {
+		t.Errorf("expected folders first, got %q", row)
+	}
+	if row := rowText(sim, 5); !strings.Contains(row, "Zelda.nes") {
+		t.Errorf("expected the ROM after the folder, got %q", row)
+	}
+	for y := 6; y < 21; y++ {
+		if row := rowText(sim, y); strings.Contains(row, "notes") || strings.Contains(row, "hidden") {
+			t.Errorf("expected filtered and hidden files to be left out, got %q", row)
+		}
+	}
+
+	// Left can't leave the root; Enter opens the folder, Left comes back to it
+	key(v, tcell.KeyLeft, 0)
+	key(v, tcell.KeyEnter, 0)
+	key(v, tcell.KeyLeft, 0)
+	key(v, tcell.KeyEnter, 0)
+	v.Draw()
+	if row := rowText(sim, 3); !strings.Contains(row, "snes") {
+		t.Errorf("expected the subfolder's path, got %q", row)
+	}
+	if row := rowText(sim, 4); !strings.Contains(row, "../") {
+		t.Errorf("expected a row to go up, got %q", row)
+	}
+	key(v, tcell.KeyRune, 's')
+	key(v, tcell.KeyEnter, 0)
+	if want := filepath.Join(root, "snes", "Star Fox.sfc"); picked != want {
+		t.Errorf("expected %q to be picked, got %q", want, picked)
+	}
+
+	cancelled := false
+	c := NewFileView(s, "ROMs", root, nil, func(string) { t.Errorf("expected ESC not to pick a file") }, func() { cancelled = true })
+	key(c, tcell.KeyEscape, 0)
+	if !cancelled {
+		t.Errorf("expected ESC to cancel")
+	}
+}
+
+func TestLoginViewMasksPassword(t *testing.T) {
+	s, sim := newTestScreen(t, 80, 25)
+	var user, password string
+	v := NewLoginView(s, "Locked Menu", "Admin is locked.", "alice", func(u, p string) { user, password = u, p }, func() {})
+
+	// Focus starts on the password since the user is filled in
+	for _, r := range "hunter2" {
+		v.HandleEvent(tcell.NewEventKey(tcell.KeyRune, r, tcell.ModNone))
+	}
+	v.Draw()
+	if row := rowText(sim, 12); strings.Contains(row, "hunter2") || !strings.Contains(row, "*******") {
+		t.Errorf("expected the password as asterisks, got %q", row)
+	}
+	if row := rowText(sim, 11); !strings.Contains(row, "alice") {
+		t.Errorf("expected the user name, got %q", row)
+	}
+
+	v.HandleEvent(tcell.NewEventKey(tcell.KeyEnter, 0, tcell.ModNone))
+	if user != "alice" || password != "hunter2" {
+		t.Errorf("expected Enter to submit alice/hunter2, got %q/%q", user, password)
+	}
+
+	v.Fail("Wrong user name or password.")
+	v.Draw()
+	if row := rowText(sim, 12); strings.Contains(row, "*") {
+		t.Errorf("expected a failed attempt to clear the password, got %q", row)
+	}
+	if row := rowText(sim, 14); !strings.Contains(row, "Wrong user name or password.") {
+		t.Errorf("expected the failure reason, got %q", row)
+	}
+}
+
+func TestVisualBellHighlightsBorders(t *testing.T) {
+	s, sim := newTestScreen(t, 80, 25)
+	s.DrawBorder(0, 0, 10, 5, "")
+	s.Show()
+	cells, _, _ := sim.GetContents()
+	if cells[0].Style != s.theme.StyleBorder() {
+		t.Fatalf("expected normal border style before the bell")
+	}
+
+	s.SetFlash(true)
+	s.DrawBorder(0, 0, 10, 5, "")
+	s.Show()
+	cells, _, _ = sim.GetContents()
+	if cells[0].Style != s.theme.StyleHighlight() {
+		t.Errorf("expected highlighted border while flashing")
+	}
+}
+
+func TestScreenReaderCursorFollowsSelection(t *testing.T) {
+	s, sim := newTestScreen(t, 80, 25)
+	navigator := menu.NewNavigator(goldenConfig())
+	s.DrawMenu(navigator)
+	if _, _, visible := sim.GetCursor(); visible {
+		t.Errorf("expected the cursor hidden by default")
+	}
+
+	s.SetAccessibility(Accessibility{ScreenReader: true})
+	navigator.NextSelectable()
+	s.DrawMenu(navigator)
+	_, y, visible := sim.GetCursor()
+	if !visible || !strings.Contains(rowText(sim, y), "> Logs") {
+		t.Errorf("expected the cursor on the marked selection, got row %q", rowText(sim, y))
+	}
+}
+
+func TestStatusSymbols(t *testing.T) {
+	s, sim := newTestScreen(t, 80, 25)
+	if got := s.StatusText(false, "Exit code 1"); got != "Exit code 1" {
+		t.Errorf("expected no symbol by default, got %q", got)
+	}
+
+	s.SetStatusSymbols(true)
+	if got := s.StatusText(true, "Exit code 0"); got != "✓ Exit code 0" {
+		t.Errorf("expected a check mark, got %q", got)
+	}
+	NewMessageView(s, "Command Failed", "Exit code 2.", func() {}).WithStatus(false).Draw()
+	found := false
+	for y := 0; y < 25; y++ {
+		if strings.Contains(rowText(sim, y), "✗ Exit code 2.") {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("expected the failure marked with ✗")
+	}
+}
+
+func TestItemIcons(t *testing.T) {
+	cfg := &config.Config{
+		Title: "Main",
+		Items: []config.MenuItem{
+			{Type: "command", Label: "Deploy", Icon: "🚀", Exec: config.ExecConfig{Linux: "deploy"}},
+			{Type: "submenu", Label: "Tools", Target: "tools"},
+			{Type: "back", Label: "Exit"},
+		},
+		Menus: map[string]config.Menu{"tools": {Title: "Tools"}},
+	}
+	s, sim := newTestScreen(t, 80, 25)
+	s.SetTheme(NewTheme(ThemeColors{Icons: map[string]string{"submenu": "▸"}}, testColorParser))
+	s.DrawMenu(menu.NewNavigator(cfg))
+
+	icons := map[string]string{"Deploy": "🚀", "Tools": "▸", "Exit": ""}
+	columns := map[string]int{}
+	for y := 0; y < 25; y++ {
+		row := rowText(sim, y)
+		for label, icon := range icons {
+			i := strings.Index(row, " "+label+" ")
+			if i < 0 {
+				continue
+			}
+			if !strings.Contains(row[:i], icon) {
+				t.Errorf("expected %q before %q, got %q", icon, label, row[:i])
+			}
+			columns[label] = StringWidth(row[:i])
+		}
+	}
+	if len(columns) != 3 || columns["Deploy"] != columns["Tools"] || columns["Tools"] != columns["Exit"] {
+		t.Errorf("expected the labels to start in the same column, got %v", columns)
+	}
+}
+
+func TestItemBadges(t *testing.T) {
+	long := strings.Repeat("Very long label ", 5)
+	cfg := &config.Config{
+		Title: "Main",
+		Items: []config.MenuItem{
+			{Type: "command", Label: "Open repo", Badge: "?", Exec: config.ExecConfig{Linux: "code ."}},
+			{Type: "command", Label: long, Badge: "12 GB free", Exec: config.ExecConfig{Linux: "cleanup"}},
+			{Type: "submenu", Label: "Tools", Badge: "3 new", Target: "tools"},
+		},
+		Menus: map[string]config.Menu{"tools": {Title: "Tools"}},
+	}
+	s, sim := newTestScreen(t, 80, 25)
+	s.SetItemBadges(map[string]string{"open-repo": "main"})
+	s.DrawMenu(menu.NewNavigator(cfg))
+
+	ends := map[string]int{}
+	for y := 0; y < 25; y++ {
+		row := rowText(sim, y)
+		for _, badge := range []string{"main", "12 GB free", "3 new"} {
+			if i := strings.Index(row, " "+badge+" "); i >= 0 {
+				ends[badge] = StringWidth(row[:i]) + 1 + StringWidth(badge)
+			}
+		}
+		if strings.Contains(row, " ? ") {
+			t.Errorf("expected the command's output to replace the static badge, got %q", row)
+		}
+		if strings.Contains(row, "12 GB free") && !strings.Contains(row, "…") {
+			t.Errorf("expected the long label truncated to make room, got %q", row)
+		}
+	}
+	if len(ends) != 3 || ends["main"] != ends["12 GB free"] || ends["main"] != ends["3 new"] {
+		t.Errorf("expected the badges right-aligned, got end columns %v", ends)
+	}
+}
+
+func TestEmptyMenuPlaceholder(t *testing.T) {
+	cfg := &config.Config{
+		Title: "Main",
+		Items: []config.MenuItem{{Type: "submenu", Label: "Soon", Target: "soon"}},
+		Menus: map[string]config.Menu{"soon": {Title: "Soon", EmptyText: "Nothing here yet"}},
+	}
+	nav := menu.NewNavigator(cfg)
+	if err := nav.Open(); err != nil {
+		t.Fatal(err)
+	}
+	s, sim := newTestScreen(t, 80, 25)
+	s.DrawMenu(nav)
+
+	textY, backY := -1, -1
+	for y := 0; y < 25; y++ {
+		row := rowText(sim, y)
+		if strings.Contains(row, "Nothing here yet") {
+			textY = y
+		}
+		if strings.Contains(row, " Back ") {
+			backY = y
+		}
+	}
+	if textY < 0 || backY <= textY {
+		t.Errorf("expected the placeholder above a Back item, got rows %d and %d", textY, backY)
+	}
+}
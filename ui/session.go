@@ -0,0 +1,155 @@
+package ui
+
+import (
+	"io"
+	"sync"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// SessionTty is a tcell.Tty over a remote terminal session such as an SSH
+// channel with a PTY request. The client's terminal is already in raw mode,
+// so Start and Stop have nothing to change; window size changes arrive out of
+// band and are reported with Resize.
+type SessionTty struct {
+	rw io.ReadWriter
+
+	mu       sync.Mutex
+	width    int
+	height   int
+	onResize func()
+	drain    chan struct{} // closed by Drain to wake a blocked Read
+	pending  []byte        // input received but not yet returned by Read
+
+	startReader sync.Once
+	input       chan []byte
+	inputErr    error // set before input is closed
+}
+
+// NewSessionTty returns a tty reading keys from and drawing to rw, with the
+// size the client requested
+func NewSessionTty(rw io.ReadWriter, width, height int) *SessionTty {
+	return &SessionTty{rw: rw, width: width, height: height, input: make(chan []byte, 16)}
+}
+
+// NewSessionBackend returns a screen backend drawing to a session tty for the
+// client's terminal type ($TERM on the client, e.g. "xterm-256color")
+func NewSessionBackend(tty *SessionTty, term string) (ScreenBackend, error) {
+	ti, err := tcell.LookupTerminfo(term)
+	if err != nil {
+		return nil, err
+	}
+	return tcell.NewTerminfoScreenFromTtyTerminfo(tty, ti)
+}
+
+// Start begins reading input from the session
+func (t *SessionTty) Start() error {
+	t.mu.Lock()
+	t.drain = make(chan struct{})
+	t.mu.Unlock()
+	t.startReader.Do(func() { go t.readLoop() })
+	return nil
+}
+
+// readLoop copies session input to the input channel until the session ends
+func (t *SessionTty) readLoop() {
+	for {
+		buf := make([]byte, 128)
+		n, err := t.rw.Read(buf)
+		if n > 0 {
+			t.input <- buf[:n]
+		}
+		if err != nil {
+			t.inputErr = err
+			close(t.input)
+			return
+		}
+	}
+}
+
+// Stop does nothing; the client owns its terminal modes
+func (t *SessionTty) Stop() error {
+	return nil
+}
+
+// Drain wakes a Read that is waiting for input so the screen can shut down
+// without the remote user pressing a key
+func (t *SessionTty) Drain() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.drain != nil {
+		select {
+		case <-t.drain:
+		default:
+			close(t.drain)
+		}
+	}
+	return nil
+}
+
+// Read returns session input, or 0 bytes once Drain has been called
+func (t *SessionTty) Read(p []byte) (int, error) {
+	t.mu.Lock()
+	if len(t.pending) > 0 {
+		n := copy(p, t.pending)
+		t.pending = t.pending[n:]
+		t.mu.Unlock()
+		return n, nil
+	}
+	drain := t.drain
+	t.mu.Unlock()
+
+	select {
+	case buf, ok := <-t.input:
+		if !ok {
+			return 0, t.inputErr
+		}
+		n := copy(p, buf)
+		if n < len(buf) {
+			t.mu.Lock()
+			t.pending = append(t.pending, buf[n:]...)
+			t.mu.Unlock()
+		}
+		return n, nil
+	case <-drain:
+		return 0, nil
+	}
+}
+
+// Write sends output to the session
+func (t *SessionTty) Write(p []byte) (int, error) {
+	return t.rw.Write(p)
+}
+
+// Close closes the session if it can be closed
+func (t *SessionTty) Close() error {
+	if c, ok := t.rw.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
+
+// NotifyResize registers the callback run by Resize
+func (t *SessionTty) NotifyResize(cb func()) {
+	t.mu.Lock()
+	t.onResize = cb
+	t.mu.Unlock()
+}
+
+// WindowSize returns the size last reported by the client
+func (t *SessionTty) WindowSize() (tcell.WindowSize, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return tcell.WindowSize{Width: t.width, Height: t.height}, nil
+}
+
+// Resize records a new client window size, e.g. from an SSH window-change request
+func (t *SessionTty) Resize(width, height int) {
+	t.mu.Lock()
+	t.width, t.height = width, height
+	cb := t.onResize
+	t.mu.Unlock()
+	if cb != nil {
+		cb()
+	}
+}
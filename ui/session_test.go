@@ -0,0 +1,102 @@
+package ui
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// sessionPipe is the server side of an in-memory terminal session
+type sessionPipe struct {
+	io.Reader
+	io.Writer
+}
+
+// syncBuffer collects what the "client" terminal receives
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestSessionBackend(t *testing.T) {
+	keysR, keysW := io.Pipe()
+	out := &syncBuffer{}
+	tty := NewSessionTty(sessionPipe{keysR, out}, 80, 25)
+
+	backend, err := NewSessionBackend(tty, "xterm")
+	if err != nil {
+		t.Fatalf("NewSessionBackend: %v", err)
+	}
+	s, err := NewScreenWithBackend(backend)
+	if err != nil {
+		t.Fatalf("NewScreenWithBackend: %v", err)
+	}
+	if w, h := s.Size(); w != 80 || h != 25 {
+		t.Errorf("expected the client's 80x25, got %dx%d", w, h)
+	}
+
+	s.DrawString(0, 0, "Remote Menu", s.theme.StyleNormal())
+	s.Show()
+	if !strings.Contains(out.String(), "Remote Menu") {
+		t.Errorf("expected drawing to reach the session")
+	}
+
+	go keysW.Write([]byte("q"))
+	var key *tcell.EventKey
+	for key == nil {
+		// Skip the resize event tcell posts on startup
+		key, _ = s.PollEvent().(*tcell.EventKey)
+	}
+	if key.Rune() != 'q' {
+		t.Errorf("expected key q from the session, got %q", key.Rune())
+	}
+
+	if !s.SetClipboard("ls -la") {
+		t.Errorf("expected the clipboard sequence to be sent")
+	}
+	if want := "\x1b]52;c;bHMgLWxh\a"; !strings.Contains(out.String(), want) {
+		t.Errorf("expected OSC 52 sequence %q in the session output", want)
+	}
+
+	tty.Resize(100, 30)
+	deadline := time.After(2 * time.Second)
+	for {
+		if w, h := s.Size(); w == 100 && h == 30 {
+			break
+		}
+		select {
+		case <-deadline:
+			t.Fatalf("expected resize to 100x30")
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+
+	// Closing must not wait for the remote user to type anything
+	closed := make(chan struct{})
+	go func() {
+		s.Close()
+		close(closed)
+	}()
+	select {
+	case <-closed:
+	case <-time.After(2 * time.Second):
+		t.Fatalf("Close blocked on session input")
+	}
+}
@@ -0,0 +1,525 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/mattn/go-runewidth"
+
+	"github.com/benworks/menuworks/i18n"
+)
+
+// OutputView displays command output in a scrollable full-screen viewer.
+// Any key other than the scroll keys closes it.
+type OutputView struct {
+	screen       *Screen
+	lines        []string
+	scrollOffset int
+	onClose      func()
+	title        string // header; "Command Output" when empty
+
+	status   string // command result shown in the header, if known
+	statusOK bool
+}
+
+// NewOutputView creates an output viewer; onClose runs when the user leaves it
+func NewOutputView(s *Screen, output string, onClose func()) *OutputView {
+	return &OutputView{screen: s, lines: strings.Split(output, "\n"), onClose: onClose}
+}
+
+// WithStatus shows the command's result in the header
+func (v *OutputView) WithStatus(ok bool, status string) *OutputView {
+	v.status = status
+	v.statusOK = ok
+	return v
+}
+
+// WithTitle replaces "Command Output" in the header, for text that isn't a
+// command's output
+func (v *OutputView) WithTitle(title string) *OutputView {
+	v.title = title
+	return v
+}
+
+// visibleLines returns how many output lines fit between header and footer
+func (v *OutputView) visibleLines() int {
+	_, h := v.screen.Size()
+	return h - 3
+}
+
+// Draw renders the visible part of the output
+func (v *OutputView) Draw() {
+	s := v.screen
+	w, h := s.Size()
+	visibleLines := v.visibleLines()
+
+	s.ClearRect(0, 0, w, h)
+
+	// Draw header
+	title := v.title
+	if title == "" {
+		title = i18n.T("Command Output")
+	}
+	headerText := "─ " + title + " ─"
+	if s.access.Plain {
+		headerText = title
+	}
+	headerX := (w - StringWidth(headerText)) / 2
+	s.DrawString(headerX, 0, headerText, s.theme.StyleBorder())
+	if v.status != "" {
+		s.DrawString(1, 0, s.StatusText(v.statusOK, v.status), s.theme.StyleStatus(v.statusOK))
+	}
+
+	// Draw visible lines
+	for i := 0; i < visibleLines && v.scrollOffset+i < len(v.lines); i++ {
+		line := v.lines[v.scrollOffset+i]
+		// Truncate line to fit screen width
+		if StringWidth(line) > w {
+			line = runewidth.Truncate(line, w, "")
+		}
+		s.DrawString(0, 1+i, line, s.theme.StyleNormal())
+	}
+
+	// Draw footer with navigation info
+	footerY := h - 1
+	var footerText string
+	if len(v.lines) <= visibleLines {
+		footerText = i18n.T("Press any key to return")
+	} else {
+		totalLines := len(v.lines)
+		endLine := v.scrollOffset + visibleLines
+		if endLine > totalLines {
+			endLine = totalLines
+		}
+		footerText = i18n.Tf("Lines %d-%d of %d | ↑↓ or PgUp/PgDn to scroll", v.scrollOffset+1, endLine, totalLines)
+	}
+	footerX := (w - StringWidth(footerText)) / 2
+	s.DrawString(footerX, footerY, footerText, s.theme.StyleBorder())
+
+	// Let a screen reader start at the first line of output
+	if s.access.ScreenReader {
+		s.ShowCursor(0, 1)
+	}
+	s.Show()
+}
+
+// HandleEvent scrolls the output or closes the viewer
+func (v *OutputView) HandleEvent(ev tcell.Event) {
+	keyEv, ok := ev.(*tcell.EventKey)
+	if !ok {
+		return
+	}
+
+	visibleLines := v.visibleLines()
+	switch keyEv.Key() {
+	case tcell.KeyUp:
+		if v.scrollOffset > 0 {
+			v.scrollOffset--
+		}
+	case tcell.KeyDown:
+		if v.scrollOffset < len(v.lines)-visibleLines {
+			v.scrollOffset++
+		}
+	case tcell.KeyPgUp:
+		v.scrollOffset -= visibleLines
+		if v.scrollOffset < 0 {
+			v.scrollOffset = 0
+		}
+	case tcell.KeyPgDn:
+		v.scrollOffset += visibleLines
+		if v.scrollOffset > len(v.lines)-visibleLines {
+			v.scrollOffset = len(v.lines) - visibleLines
+		}
+		if v.scrollOffset < 0 {
+			v.scrollOffset = 0
+		}
+	default:
+		// Any other key returns to menu
+		v.onClose()
+	}
+}
+
+// DialogView is a centered message box with one or more buttons.
+// Explicit line breaks in the message are kept; long lines are wrapped.
+type DialogView struct {
+	screen   *Screen
+	title    string
+	message  string
+	buttons  []string
+	width    int
+	height   int
+	anyKey   bool // any key closes the dialog (message boxes)
+	selected int
+	onClose  func(choice int)
+	code     []string // shown as is under the message, such as lines of a file
+
+	hasStatus bool // the message is a command result
+	statusOK  bool
+}
+
+// NewDialogView creates a dialog with buttons. Left/Right move between buttons,
+// Enter chooses, and Escape chooses the first button.
+func NewDialogView(s *Screen, title, message string, buttons []string, onClose func(choice int)) *DialogView {
+	return &DialogView{screen: s, title: title, message: message, buttons: buttons, width: 50, height: 12, onClose: onClose}
+}
+
+// WithSize sets the dialog's width and height
+func (d *DialogView) WithSize(width, height int) *DialogView {
+	d.width = width
+	d.height = height
+	return d
+}
+
+// WithStatus shows the message as a command result in the success or failure style
+func (d *DialogView) WithStatus(ok bool) *DialogView {
+	d.hasStatus = true
+	d.statusOK = ok
+	return d
+}
+
+// WithCode shows lines under the message without wrapping them, so their
+// indentation and columns line up. Lines too wide for the dialog are cut.
+func (d *DialogView) WithCode(lines []string) *DialogView {
+	d.code = lines
+	return d
+}
+
+// NewMessageView creates an [OK] message box that closes on any key
+func NewMessageView(s *Screen, title, message string, onClose func()) *DialogView {
+	d := NewDialogView(s, title, message, []string{"OK"}, func(int) { onClose() })
+	d.anyKey = true
+	return d
+}
+
+// NewItemHelpView creates a dialog showing a menu item's command and help text
+func NewItemHelpView(s *Screen, command, help string, onClose func()) *DialogView {
+	message := i18n.T("Command:") + "\n" + command
+	if help != "" {
+		message += "\n\n" + help
+	}
+	return NewDialogView(s, i18n.T("Item Info"), message, []string{i18n.T("OK")}, func(int) { onClose() }).WithSize(60, 14)
+}
+
+// Draw renders the dialog
+func (d *DialogView) Draw() {
+	s := d.screen
+	w, h := s.Size()
+
+	startX := (w - d.width) / 2
+	startY := (h - d.height) / 2
+	if startX < 0 {
+		startX = 0
+	}
+	if startY < 0 {
+		startY = 0
+	}
+
+	s.ClearRect(0, 0, w, h)
+	s.DrawBorder(startX, startY, d.width, d.height, " "+d.title+" ")
+
+	// Draw message with wrapping (preserve explicit line breaks)
+	message, messageStyle := d.message, s.theme.StyleNormal()
+	if d.hasStatus {
+		message, messageStyle = s.StatusText(d.statusOK, message), s.theme.StyleStatus(d.statusOK)
+	}
+	var lines []string
+	for _, rawLine := range strings.Split(message, "\n") {
+		wrapped := WrapText(rawLine, d.width-4)
+		if len(wrapped) == 0 {
+			wrapped = []string{""}
+		}
+		lines = append(lines, wrapped...)
+	}
+	maxLines := d.height - 5
+	if len(d.code) > 0 {
+		maxLines = max(maxLines-len(d.code)-1, 1) // a blank line, then the code
+	}
+	for i, line := range lines {
+		if i >= maxLines {
+			break
+		}
+		s.DrawString(startX+2, startY+2+i, line, messageStyle)
+	}
+	codeY := startY + 2 + min(len(lines), maxLines) + 1
+	for i, line := range d.code {
+		if codeY+i > startY+d.height-4 {
+			break
+		}
+		s.DrawString(startX+2, codeY+i, TruncateString(line, d.width-4), s.theme.StyleNormal())
+	}
+
+	// Draw buttons evenly across the bottom row
+	buttonY := startY + d.height - 2
+	slotWidth := (d.width - 4) / len(d.buttons)
+	for i, btn := range d.buttons {
+		btnText := fmt.Sprintf("[%s]", btn)
+		btnX := startX + 2 + i*slotWidth + (slotWidth-StringWidth(btnText))/2
+		style := s.theme.StyleNormal()
+		if i == d.selected {
+			style = s.theme.StyleHighlight()
+			if s.access.ScreenReader {
+				s.ShowCursor(btnX+1, buttonY)
+			}
+		}
+		s.DrawString(btnX, buttonY, btnText, style)
+	}
+
+	s.Show()
+}
+
+// HandleEvent moves between buttons or closes the dialog
+func (d *DialogView) HandleEvent(ev tcell.Event) {
+	keyEv, ok := ev.(*tcell.EventKey)
+	if !ok {
+		return
+	}
+	if d.anyKey {
+		d.onClose(0)
+		return
+	}
+
+	switch keyEv.Key() {
+	case tcell.KeyLeft:
+		d.selected = (d.selected - 1 + len(d.buttons)) % len(d.buttons)
+	case tcell.KeyRight:
+		d.selected = (d.selected + 1) % len(d.buttons)
+	case tcell.KeyEnter:
+		d.onClose(d.selected)
+	case tcell.KeyEscape:
+		d.onClose(0) // Default to first button on ESC
+	}
+}
+
+// InputView is a centered box for typing multi-line text, such as input to
+// pipe into a command. Enter starts a new line, Ctrl+D submits, and Escape
+// cancels. Text is only added or removed at the end.
+type InputView struct {
+	screen   *Screen
+	title    string
+	text     []rune
+	width    int
+	height   int
+	onSubmit func(text string)
+	onCancel func()
+}
+
+// NewInputView creates a multi-line input box; onSubmit gets the typed text
+func NewInputView(s *Screen, title string, onSubmit func(text string), onCancel func()) *InputView {
+	return &InputView{screen: s, title: title, width: 60, height: 14, onSubmit: onSubmit, onCancel: onCancel}
+}
+
+// Draw renders the input box, keeping the last lines of the text in view
+func (v *InputView) Draw() {
+	s := v.screen
+	w, h := s.Size()
+
+	width, height := v.width, v.height
+	if width > w {
+		width = w
+	}
+	if height > h {
+		height = h
+	}
+	startX := (w - width) / 2
+	startY := (h - height) / 2
+
+	s.ClearRect(0, 0, w, h)
+	s.DrawBorder(startX, startY, width, height, " "+v.title+" ")
+
+	// Long lines are cut rather than wrapped so the cursor stays at the end of the last line
+	lines := strings.Split(string(v.text), "\n")
+	maxLines := height - 4
+	if maxLines < 1 {
+		maxLines = 1
+	}
+	if len(lines) > maxLines {
+		lines = lines[len(lines)-maxLines:]
+	}
+	textWidth := width - 4
+	for i, line := range lines {
+		if StringWidth(line) > textWidth {
+			line = runewidth.TruncateLeft(line, StringWidth(line)-textWidth, "")
+		}
+		s.DrawString(startX+2, startY+1+i, line, s.theme.StyleNormal())
+	}
+	last := lines[len(lines)-1]
+	cursorX := startX + 2 + StringWidth(last)
+	if cursorX > startX+2+textWidth {
+		cursorX = startX + 2 + textWidth
+	}
+	s.ShowCursor(cursorX, startY+len(lines))
+
+	hint := i18n.T("Ctrl+D to run, ESC to cancel")
+	s.DrawString(startX+(width-StringWidth(hint))/2, startY+height-2, hint, s.theme.StyleBorder())
+
+	s.Show()
+}
+
+// HandleEvent edits the text, submits it, or cancels
+func (v *InputView) HandleEvent(ev tcell.Event) {
+	keyEv, ok := ev.(*tcell.EventKey)
+	if !ok {
+		return
+	}
+
+	switch keyEv.Key() {
+	case tcell.KeyCtrlD:
+		v.screen.HideCursor()
+		v.onSubmit(string(v.text))
+	case tcell.KeyEscape:
+		v.screen.HideCursor()
+		v.onCancel()
+	case tcell.KeyEnter:
+		v.text = append(v.text, '\n')
+	case tcell.KeyTab:
+		v.text = append(v.text, '\t')
+	case tcell.KeyBackspace, tcell.KeyBackspace2:
+		if len(v.text) > 0 {
+			v.text = v.text[:len(v.text)-1]
+		}
+	case tcell.KeyRune:
+		v.text = append(v.text, keyEv.Rune())
+	}
+}
+
+// LoginView asks for a user name and password. Tab, Up, and Down move
+// between the fields, Enter moves on from the user name and submits from the
+// password, and Escape cancels. The password is shown as asterisks. A PIN
+// view has the password field alone.
+type LoginView struct {
+	screen     *Screen
+	title      string
+	message    string
+	user       []rune
+	password   []rune
+	onPassword bool   // the password field has focus
+	pinOnly    bool   // there is no user name field, and the password is a PIN
+	status     string // why the last attempt failed
+	busy       bool   // a submitted attempt is being checked
+	onSubmit   func(user, password string)
+	onCancel   func()
+}
+
+// NewLoginView creates a sign-in box with user filled in; focus starts on
+// the password when user is set
+func NewLoginView(s *Screen, title, message, user string, onSubmit func(user, password string), onCancel func()) *LoginView {
+	return &LoginView{screen: s, title: title, message: message, user: []rune(user), onPassword: user != "", onSubmit: onSubmit, onCancel: onCancel}
+}
+
+// NewPINView creates a box that asks for a PIN alone
+func NewPINView(s *Screen, title, message string, onSubmit func(pin string), onCancel func()) *LoginView {
+	return &LoginView{screen: s, title: title, message: message, onPassword: true, pinOnly: true,
+		onSubmit: func(_, pin string) { onSubmit(pin) }, onCancel: onCancel}
+}
+
+// SetBusy shows that the submitted credentials are being checked and ignores
+// keys until Fail is called or the view is closed
+func (v *LoginView) SetBusy() {
+	v.busy = true
+}
+
+// Fail shows reason, clears the password, and lets the user try again
+func (v *LoginView) Fail(reason string) {
+	v.busy = false
+	v.status = reason
+	v.password = nil
+	v.onPassword = true
+}
+
+// Draw renders the sign-in box
+func (v *LoginView) Draw() {
+	s := v.screen
+	w, h := s.Size()
+
+	width, height := min(56, w), min(12, h)
+	startX, startY := (w-width)/2, (h-height)/2
+
+	s.ClearRect(0, 0, w, h)
+	s.DrawBorder(startX, startY, width, height, " "+v.title+" ")
+
+	lines := WrapText(v.message, width-4)
+	if len(lines) > 2 {
+		lines = lines[:2]
+	}
+	for i, line := range lines {
+		s.DrawString(startX+2, startY+2+i, line, s.theme.StyleNormal())
+	}
+
+	userLabel, passwordLabel := i18n.T("User:"), i18n.T("Password:")
+	if v.pinOnly {
+		userLabel, passwordLabel = "", i18n.T("PIN:")
+	}
+	labelWidth := max(StringWidth(userLabel), StringWidth(passwordLabel)) + 1
+	fieldX := startX + 2 + labelWidth
+	fieldWidth := width - 4 - labelWidth
+	userY, passwordY := startY+5, startY+6
+	user := string(v.user)
+	if StringWidth(user) > fieldWidth-1 {
+		user = runewidth.TruncateLeft(user, StringWidth(user)-(fieldWidth-1), "")
+	}
+	if v.pinOnly {
+		passwordY = userY
+	} else {
+		s.DrawString(startX+2, userY, userLabel, s.theme.StyleNormal())
+		s.DrawString(fieldX, userY, user, s.theme.StyleNormal())
+	}
+	masked := strings.Repeat("*", min(len(v.password), fieldWidth-1))
+	s.DrawString(startX+2, passwordY, passwordLabel, s.theme.StyleNormal())
+	s.DrawString(fieldX, passwordY, masked, s.theme.StyleNormal())
+
+	switch {
+	case v.busy:
+		s.DrawString(startX+2, startY+8, TruncateString(i18n.T("Checking…"), width-4), s.theme.StyleBorder())
+	case v.status != "":
+		s.DrawString(startX+2, startY+8, TruncateString(v.status, width-4), s.theme.StyleStatus(false))
+	}
+
+	if v.busy {
+		s.HideCursor()
+	} else if v.onPassword {
+		s.ShowCursor(fieldX+StringWidth(masked), passwordY)
+	} else {
+		s.ShowCursor(fieldX+StringWidth(user), userY)
+	}
+
+	hint := i18n.T("Enter to sign in, ESC to cancel")
+	s.DrawString(startX+(width-StringWidth(hint))/2, startY+height-2, hint, s.theme.StyleBorder())
+
+	s.Show()
+}
+
+// HandleEvent edits the focused field, moves between fields, submits, or cancels
+func (v *LoginView) HandleEvent(ev tcell.Event) {
+	keyEv, ok := ev.(*tcell.EventKey)
+	if !ok || v.busy {
+		return
+	}
+
+	field := &v.user
+	if v.onPassword {
+		field = &v.password
+	}
+	switch keyEv.Key() {
+	case tcell.KeyEscape:
+		v.screen.HideCursor()
+		v.onCancel()
+	case tcell.KeyTab, tcell.KeyBacktab, tcell.KeyUp, tcell.KeyDown:
+		v.onPassword = !v.onPassword || v.pinOnly
+	case tcell.KeyEnter:
+		if !v.onPassword {
+			v.onPassword = true
+			return
+		}
+		v.screen.HideCursor()
+		v.onSubmit(string(v.user), string(v.password))
+	case tcell.KeyBackspace, tcell.KeyBackspace2:
+		if len(*field) > 0 {
+			*field = (*field)[:len(*field)-1]
+		}
+	case tcell.KeyCtrlU:
+		*field = nil
+	case tcell.KeyRune:
+		*field = append(*field, keyEv.Rune())
+	}
+}
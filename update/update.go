@@ -0,0 +1,238 @@
+// Package update checks GitHub releases for a newer MenuWorks and replaces the
+// running binary with a downloaded one after verifying its SHA256 checksum.
+package update
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Repository is the GitHub owner/name whose releases are checked. Forks that
+// publish their own releases set it at build time with
+// -ldflags "-X github.com/benworks/menuworks/update.Repository=owner/name".
+var Repository = "benleov/menuworks-3"
+
+// LatestReleaseURL returns the GitHub API endpoint for the newest published
+// release of Repository
+func LatestReleaseURL() string {
+	return "https://api.github.com/repos/" + Repository + "/releases/latest"
+}
+
+// checksumsAsset is the release asset listing `sha256sum` output for every binary
+const checksumsAsset = "checksums.txt"
+
+// Release is a published version and its downloadable assets
+type Release struct {
+	Version string            // without the leading "v"
+	Assets  map[string]string // asset name -> download URL
+}
+
+// Checker finds and installs releases. The zero value is not usable; see NewChecker.
+type Checker struct {
+	URL    string // latest-release API endpoint
+	Client *http.Client
+}
+
+// NewChecker returns a checker for the official releases with a short timeout
+func NewChecker() *Checker {
+	return &Checker{URL: LatestReleaseURL(), Client: &http.Client{Timeout: 15 * time.Second}}
+}
+
+// Latest fetches the newest published release
+func (c *Checker) Latest(ctx context.Context) (*Release, error) {
+	body, err := c.get(ctx, c.URL)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	var payload struct {
+		TagName string `json:"tag_name"`
+		Assets  []struct {
+			Name string `json:"name"`
+			URL  string `json:"browser_download_url"`
+		} `json:"assets"`
+	}
+	if err := json.NewDecoder(body).Decode(&payload); err != nil {
+		return nil, fmt.Errorf("failed to parse release: %w", err)
+	}
+	if payload.TagName == "" {
+		return nil, fmt.Errorf("release has no tag")
+	}
+
+	release := &Release{Version: strings.TrimPrefix(payload.TagName, "v"), Assets: make(map[string]string)}
+	for _, asset := range payload.Assets {
+		release.Assets[asset.Name] = asset.URL
+	}
+	return release, nil
+}
+
+// get performs a GET request and returns the body of a 200 response
+func (c *Checker) get(ctx context.Context, url string) (io.ReadCloser, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", "menuworks-updater")
+	resp, err := c.Client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("GET %s: %s", url, resp.Status)
+	}
+	return resp.Body, nil
+}
+
+// IsNewer reports whether latest is a higher dotted version than current.
+// Development builds ("dev" or empty) never report updates.
+func IsNewer(current, latest string) bool {
+	cur, ok := parseVersion(current)
+	if !ok {
+		return false
+	}
+	lat, ok := parseVersion(latest)
+	if !ok {
+		return false
+	}
+	for i := 0; i < len(cur) || i < len(lat); i++ {
+		var a, b int
+		if i < len(cur) {
+			a = cur[i]
+		}
+		if i < len(lat) {
+			b = lat[i]
+		}
+		if a != b {
+			return b > a
+		}
+	}
+	return false
+}
+
+// parseVersion splits "v1.2.3" (ignoring any "-suffix") into its numbers
+func parseVersion(v string) ([]int, bool) {
+	v = strings.TrimPrefix(strings.TrimSpace(v), "v")
+	if i := strings.IndexAny(v, "-+ "); i >= 0 {
+		v = v[:i]
+	}
+	if v == "" {
+		return nil, false
+	}
+	var nums []int
+	for _, part := range strings.Split(v, ".") {
+		n, err := strconv.Atoi(part)
+		if err != nil {
+			return nil, false
+		}
+		nums = append(nums, n)
+	}
+	return nums, true
+}
+
+// AssetName returns the release binary for a platform, or "" if none is published
+func AssetName(goos, goarch string) string {
+	switch {
+	case goos == "windows" && goarch == "amd64":
+		return "menuworks-windows.exe"
+	case goos == "linux" && goarch == "amd64":
+		return "menuworks-linux"
+	case goos == "darwin" && goarch == "amd64":
+		return "menuworks-macos"
+	case goos == "darwin" && goarch == "arm64":
+		return "menuworks-macos-arm64"
+	}
+	return ""
+}
+
+// Install downloads asset from release, verifies it against the release's
+// checksums.txt, and replaces the binary at exePath. The previous binary is
+// kept as exePath + ".old" so Windows can replace a running executable.
+func (c *Checker) Install(ctx context.Context, release *Release, asset, exePath string) error {
+	assetURL, ok := release.Assets[asset]
+	if !ok {
+		return fmt.Errorf("release %s has no %s", release.Version, asset)
+	}
+	sumsURL, ok := release.Assets[checksumsAsset]
+	if !ok {
+		return fmt.Errorf("release %s has no %s; refusing to install unverified binary", release.Version, checksumsAsset)
+	}
+
+	want, err := c.expectedChecksum(ctx, sumsURL, asset)
+	if err != nil {
+		return err
+	}
+
+	// Download next to the binary so the final rename stays on one filesystem
+	tmp, err := os.CreateTemp(filepath.Dir(exePath), ".menuworks-update-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	body, err := c.get(ctx, assetURL)
+	if err != nil {
+		tmp.Close()
+		return err
+	}
+	hash := sha256.New()
+	_, err = io.Copy(io.MultiWriter(tmp, hash), body)
+	body.Close()
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to download %s: %w", asset, err)
+	}
+	if got := hex.EncodeToString(hash.Sum(nil)); got != want {
+		return fmt.Errorf("checksum mismatch for %s: got %s, want %s", asset, got, want)
+	}
+
+	if err := os.Chmod(tmp.Name(), 0755); err != nil {
+		return err
+	}
+	oldPath := exePath + ".old"
+	os.Remove(oldPath)
+	if err := os.Rename(exePath, oldPath); err != nil {
+		return fmt.Errorf("failed to move current binary aside: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), exePath); err != nil {
+		// Put the original back so the install is never left without a binary
+		os.Rename(oldPath, exePath)
+		return fmt.Errorf("failed to install new binary: %w", err)
+	}
+	return nil
+}
+
+// expectedChecksum reads the SHA256 listed for asset in a checksums file
+func (c *Checker) expectedChecksum(ctx context.Context, url, asset string) (string, error) {
+	body, err := c.get(ctx, url)
+	if err != nil {
+		return "", err
+	}
+	defer body.Close()
+
+	scanner := bufio.NewScanner(body)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 2 && strings.TrimPrefix(fields[1], "*") == asset {
+			return strings.ToLower(fields[0]), nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return "", fmt.Errorf("failed to read checksums: %w", err)
+	}
+	return "", fmt.Errorf("no checksum listed for %s", asset)
+}
@@ -0,0 +1,108 @@
+package update
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestIsNewer(t *testing.T) {
+	tests := []struct {
+		current, latest string
+		want            bool
+	}{
+		{"1.2.0", "1.3.0", true},
+		{"1.2.0", "v1.2.1", true},
+		{"1.10.0", "1.9.9", false},
+		{"1.2.0", "1.2.0", false},
+		{"1.2", "1.2.0", false},
+		{"dev", "9.9.9", false},
+		{"", "1.0.0", false},
+		{"1.2.0-rc1", "1.2.0", false},
+	}
+	for _, tt := range tests {
+		if got := IsNewer(tt.current, tt.latest); got != tt.want {
+			t.Errorf("IsNewer(%q, %q) = %v, want %v", tt.current, tt.latest, got, tt.want)
+		}
+	}
+}
+
+// newReleaseServer serves a latest release with one binary and a checksums file
+func newReleaseServer(t *testing.T, binary []byte, sum string) *httptest.Server {
+	t.Helper()
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/latest":
+			fmt.Fprintf(w, `{"tag_name":"v2.0.0","assets":[{"name":"menuworks-linux","browser_download_url":"%[1]s/bin"},{"name":"checksums.txt","browser_download_url":"%[1]s/sums"}]}`, srv.URL)
+		case "/bin":
+			w.Write(binary)
+		case "/sums":
+			fmt.Fprintf(w, "%s  menuworks-linux\n", sum)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestInstallVerifiesChecksum(t *testing.T) {
+	binary := []byte("new binary")
+	hash := sha256.Sum256(binary)
+	srv := newReleaseServer(t, binary, hex.EncodeToString(hash[:]))
+	c := &Checker{URL: srv.URL + "/latest", Client: srv.Client()}
+
+	release, err := c.Latest(context.Background())
+	if err != nil || release.Version != "2.0.0" {
+		t.Fatalf("expected release 2.0.0, got %+v, %v", release, err)
+	}
+
+	exe := filepath.Join(t.TempDir(), "menuworks")
+	os.WriteFile(exe, []byte("old binary"), 0755)
+	if err := c.Install(context.Background(), release, "menuworks-linux", exe); err != nil {
+		t.Fatalf("unexpected install error: %v", err)
+	}
+	if data, _ := os.ReadFile(exe); string(data) != "new binary" {
+		t.Errorf("expected binary to be replaced, got %q", data)
+	}
+	if data, _ := os.ReadFile(exe + ".old"); string(data) != "old binary" {
+		t.Errorf("expected previous binary kept as .old, got %q", data)
+	}
+}
+
+func TestInstallRejectsBadChecksum(t *testing.T) {
+	srv := newReleaseServer(t, []byte("tampered"), strings.Repeat("0", 64))
+	c := &Checker{URL: srv.URL + "/latest", Client: srv.Client()}
+	release, err := c.Latest(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	exe := filepath.Join(t.TempDir(), "menuworks")
+	os.WriteFile(exe, []byte("old binary"), 0755)
+	if err := c.Install(context.Background(), release, "menuworks-linux", exe); err == nil || !strings.Contains(err.Error(), "checksum mismatch") {
+		t.Fatalf("expected checksum mismatch, got %v", err)
+	}
+	if data, _ := os.ReadFile(exe); string(data) != "old binary" {
+		t.Errorf("expected binary untouched, got %q", data)
+	}
+}
+
+func TestLatestReleaseURLFollowsRepository(t *testing.T) {
+	if got := NewChecker().URL; got != "https://api.github.com/repos/benleov/menuworks-3/releases/latest" {
+		t.Errorf("unexpected default release URL %q", got)
+	}
+	defer func(old string) { Repository = old }(Repository)
+	Repository = "someone/fork"
+	if got := LatestReleaseURL(); got != "https://api.github.com/repos/someone/fork/releases/latest" {
+		t.Errorf("expected the release URL of the overridden repository, got %q", got)
+	}
+}
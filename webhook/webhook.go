@@ -0,0 +1,113 @@
+// Package webhook reports command executions to an HTTP endpoint, so a
+// central dashboard can follow what a fleet of menus is doing. Delivery is
+// best effort: events are queued, posted in order by one goroutine, and
+// dropped (and logged) if the endpoint is slow or unreachable.
+package webhook
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/benworks/menuworks/logging"
+)
+
+// Event kinds
+const (
+	Start  = "start"
+	Finish = "finish"
+)
+
+// Event is the JSON body posted for each execution
+type Event struct {
+	Event      string    `json:"event"` // "start" or "finish"
+	Item       string    `json:"item"`  // menu path, e.g. "Games/Steam/Portal 2"
+	Label      string    `json:"label"`
+	Command    string    `json:"command"`
+	Host       string    `json:"host,omitempty"`
+	Time       time.Time `json:"time"`
+	DurationMS *int64    `json:"duration_ms,omitempty"` // finish only
+	ExitCode   *int      `json:"exit_code,omitempty"`   // finish only, when known
+}
+
+// queueSize bounds how many events can wait for delivery
+const queueSize = 64
+
+// delivery is one queued event and where to send it
+type delivery struct {
+	url   string
+	event Event
+}
+
+// Notifier posts events in the background. Create it with NewNotifier and
+// Close it on exit to flush what is queued.
+type Notifier struct {
+	Client *http.Client
+	queue  chan delivery
+	done   chan struct{}
+}
+
+// NewNotifier starts a notifier with a short per-request timeout
+func NewNotifier() *Notifier {
+	n := &Notifier{
+		Client: &http.Client{Timeout: 5 * time.Second},
+		queue:  make(chan delivery, queueSize),
+		done:   make(chan struct{}),
+	}
+	go n.run()
+	return n
+}
+
+// Send queues event for url without blocking. If the queue is full the event is dropped.
+func (n *Notifier) Send(url string, event Event) {
+	select {
+	case n.queue <- delivery{url, event}:
+	default:
+		logging.Warn("webhook queue full, dropping event", "url", url, "event", event.Event, "item", event.Item)
+	}
+}
+
+// Close stops accepting events and waits up to timeout for queued ones to be delivered
+func (n *Notifier) Close(timeout time.Duration) {
+	close(n.queue)
+	select {
+	case <-n.done:
+	case <-time.After(timeout):
+		logging.Warn("webhook events still pending at exit")
+	}
+}
+
+// run delivers queued events in order
+func (n *Notifier) run() {
+	defer close(n.done)
+	for d := range n.queue {
+		if err := n.post(d.url, d.event); err != nil {
+			logging.Warn("webhook delivery failed", "url", d.url, "event", d.event.Event, "item", d.event.Item, "err", err)
+		}
+	}
+}
+
+// post sends one event as a JSON POST
+func (n *Notifier) post(url string, event Event) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "menuworks-webhook")
+	resp, err := n.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("POST %s: %s", url, resp.Status)
+	}
+	return nil
+}
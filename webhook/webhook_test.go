@@ -0,0 +1,61 @@
+package webhook
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestNotifierPostsEventsInOrder(t *testing.T) {
+	var mu sync.Mutex
+	var got []Event
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("unexpected request: %s %s", r.Method, r.Header.Get("Content-Type"))
+		}
+		var ev Event
+		if err := json.NewDecoder(r.Body).Decode(&ev); err != nil {
+			t.Errorf("decode: %v", err)
+		}
+		mu.Lock()
+		got = append(got, ev)
+		mu.Unlock()
+	}))
+	defer srv.Close()
+
+	n := NewNotifier()
+	duration, exitCode := int64(1500), 3
+	n.Send(srv.URL, Event{Event: Start, Item: "Games/Steam/Portal 2", Label: "Portal 2"})
+	n.Send(srv.URL, Event{Event: Finish, Item: "Games/Steam/Portal 2", Label: "Portal 2", DurationMS: &duration, ExitCode: &exitCode})
+	n.Close(5 * time.Second)
+
+	if len(got) != 2 {
+		t.Fatalf("expected 2 events, got %d", len(got))
+	}
+	if got[0].Event != Start || got[1].Event != Finish {
+		t.Errorf("expected start then finish, got %s then %s", got[0].Event, got[1].Event)
+	}
+	if got[1].ExitCode == nil || *got[1].ExitCode != 3 || got[1].DurationMS == nil || *got[1].DurationMS != 1500 {
+		t.Errorf("unexpected finish event: %+v", got[1])
+	}
+	if got[0].Item != "Games/Steam/Portal 2" {
+		t.Errorf("unexpected item path %q", got[0].Item)
+	}
+}
+
+func TestNotifierSurvivesFailingEndpoint(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "nope", http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	n := NewNotifier()
+	if err := n.post(srv.URL, Event{Event: Start}); err == nil {
+		t.Errorf("expected an error for a 500 response")
+	}
+	n.Send(srv.URL, Event{Event: Start})
+	n.Close(5 * time.Second)
+}
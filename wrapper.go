@@ -0,0 +1,85 @@
+package menuworks
+
+import (
+	"context"
+	"runtime/debug"
+	"time"
+
+	"github.com/benworks/menuworks/config"
+	"github.com/benworks/menuworks/exec"
+	"github.com/benworks/menuworks/i18n"
+	"github.com/benworks/menuworks/logging"
+	"github.com/benworks/menuworks/shell"
+	"github.com/benworks/menuworks/ui"
+)
+
+// restartDelay keeps a menu that crashes as soon as it is drawn from
+// restarting in a tight loop; tests shorten it
+var restartDelay = time.Second
+
+// wrapperMode reports whether MenuWorks stands in for the user's login shell
+// or desktop, where exiting would end the session
+func (a *App) wrapperMode() bool {
+	return a.Wrapper || (a.cfg != nil && a.cfg.IsWrapperEnabled())
+}
+
+// runMenu runs the dispatcher until the menu is quit. In wrapper mode a panic
+// is logged and the menu starts over, rather than ending the process and with
+// it the user's session.
+func (a *App) runMenu(ctx context.Context) error {
+	for {
+		crashed, err := a.runGuarded(ctx)
+		if !crashed {
+			return err
+		}
+		time.Sleep(restartDelay)
+		a.restartMenu()
+	}
+}
+
+// runGuarded runs the dispatcher, recovering from a panic in wrapper mode
+func (a *App) runGuarded(ctx context.Context) (crashed bool, err error) {
+	if !a.wrapperMode() {
+		return false, a.d.Run(ctx)
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			logging.Error("menu crashed; restarting", "panic", r, "stack", string(debug.Stack()))
+			crashed = true
+		}
+	}()
+	return false, a.d.Run(ctx)
+}
+
+// restartMenu starts the menu over after a crash, as a timed-out session
+// does, and says what happened
+func (a *App) restartMenu() {
+	for a.d.Focused() != nil {
+		a.d.Pop()
+	}
+	a.screen.Sync()
+	a.resetSession()
+	a.d.Push(&menuView{a: a})
+	a.d.Push(ui.NewMessageView(a.screen, i18n.T("Menu Restarted"),
+		i18n.T("Something went wrong, so the menu started over. The details are in the log."), a.d.Pop))
+}
+
+// openFallbackShell leaves MenuWorks for the item's shell. Run restores the
+// terminal first, then hands the process to the shell.
+func (a *App) openFallbackShell(item config.MenuItem) {
+	if a.Remote {
+		a.showError(i18n.T("Launch Error"), i18n.T("A fallback shell can't be opened in a remote session."))
+		return
+	}
+	argv := []string{exec.FallbackShell()}
+	if command := item.Exec.CommandForOS(exec.GetOS()); command != "" {
+		var err error
+		if argv, err = shell.Split(exec.GetOS(), command); err != nil || len(argv) == 0 {
+			a.showError(i18n.T("Launch Error"), i18n.Tf("Invalid fallback shell: %s", command))
+			return
+		}
+	}
+	logging.Warn("leaving for the fallback shell", "argv", argv, "menu", a.navigator.GetCurrentMenuName())
+	a.fallbackShell = argv
+	a.d.Stop()
+}
@@ -0,0 +1,68 @@
+package menuworks
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+
+	"github.com/benworks/menuworks/config"
+)
+
+// panicView panics on the first event it gets
+type panicView struct{}
+
+func (panicView) Draw()                   {}
+func (panicView) HandleEvent(tcell.Event) { panic("boom") }
+
+func wrapperConfig() *config.Config {
+	cfg := lockedConfig()
+	cfg.Wrapper = new(bool)
+	*cfg.Wrapper = true
+	cfg.Items = append(cfg.Items, config.MenuItem{Type: "quit", Label: "Log Out"})
+	return cfg
+}
+
+func TestWrapperModeIgnoresEscapeAtRoot(t *testing.T) {
+	a, _, _ := newTestApp(t, wrapperConfig())
+	v := &menuView{a: a}
+	v.exitOrBack()
+	v.ctrlC()
+	if a.d.Stopped() {
+		t.Error("expected Esc and Ctrl+C to leave the menu running")
+	}
+}
+
+func TestWrapperModeRestartsAfterPanic(t *testing.T) {
+	saved := restartDelay
+	restartDelay = 0
+	t.Cleanup(func() { restartDelay = saved })
+
+	a, sim, events := newTestApp(t, wrapperConfig())
+	sim.SetSize(80, 25)
+	a.d.Push(panicView{})
+	events <- tcell.NewEventKey(tcell.KeyRune, 'x', tcell.ModNone)
+	events <- tcell.NewEventKey(tcell.KeyEnter, 0, tcell.ModNone) // closes the notice
+	events <- tcell.NewEventKey(tcell.KeyEnd, 0, tcell.ModNone)
+	events <- tcell.NewEventKey(tcell.KeyEnter, 0, tcell.ModNone) // Log Out
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	a.ctx = ctx
+	if err := a.runMenu(ctx); err != nil {
+		t.Fatalf("expected the restarted menu to be quit, got %v\n%s", err, screenText(sim))
+	}
+	if _, ok := a.d.Focused().(*menuView); !ok {
+		t.Errorf("expected the menu to be restarted, got %T", a.d.Focused())
+	}
+}
+
+func TestFallbackShell(t *testing.T) {
+	a, _, _ := newTestApp(t, wrapperConfig())
+	item := config.MenuItem{Type: "fallback_shell", Label: "Shell", Exec: config.ExecConfig{Linux: "/bin/bash -l", Mac: "/bin/zsh -l", Windows: "cmd.exe /k"}}
+	a.openFallbackShell(item)
+	if len(a.fallbackShell) != 2 || !a.d.Stopped() {
+		t.Errorf("expected the menu to stop for the shell, got %q", a.fallbackShell)
+	}
+}